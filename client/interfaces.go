@@ -140,6 +140,7 @@ type InstanceServer interface {
 	GetInstanceSnapshotNames(instanceName string) (names []string, err error)
 	GetInstanceSnapshots(instanceName string) (snapshots []api.InstanceSnapshot, err error)
 	GetInstanceSnapshot(instanceName string, name string) (snapshot *api.InstanceSnapshot, ETag string, err error)
+	GetInstanceSnapshotDiff(instanceName string, name string, against string) (entries []api.SnapshotDiffEntry, err error)
 	CreateInstanceSnapshot(instanceName string, snapshot api.InstanceSnapshotsPost) (op Operation, err error)
 	CopyInstanceSnapshot(source InstanceServer, instanceName string, snapshot api.InstanceSnapshot, args *InstanceSnapshotCopyArgs) (op RemoteOperation, err error)
 	RenameInstanceSnapshot(instanceName string, name string, instance api.InstanceSnapshotPost) (op Operation, err error)
@@ -238,11 +239,23 @@ type InstanceServer interface {
 	GetNetworkACLsAllProjects() (acls []api.NetworkACL, err error)
 	GetNetworkACL(name string) (acl *api.NetworkACL, ETag string, err error)
 	GetNetworkACLLogfile(name string) (log io.ReadCloser, err error)
+	GetNetworkACLState(name string) (state *api.NetworkACLState, err error)
 	CreateNetworkACL(acl api.NetworkACLsPost) (op Operation, err error)
 	UpdateNetworkACL(name string, acl api.NetworkACLPut, ETag string) (op Operation, err error)
 	RenameNetworkACL(name string, acl api.NetworkACLPost) (op Operation, err error)
 	DeleteNetworkACL(name string) (op Operation, err error)
 
+	// Network address set functions ("network_address_set" API extension)
+	GetNetworkAddressSetNames() (addressSetNames []string, err error)
+	GetNetworkAddressSetNamesAllProjects() (projectToAddressSets map[string][]string, err error)
+	GetNetworkAddressSets() (addressSets []api.NetworkAddressSet, err error)
+	GetNetworkAddressSetsAllProjects() (addressSets []api.NetworkAddressSet, err error)
+	GetNetworkAddressSet(addressSetName string) (addressSet *api.NetworkAddressSet, ETag string, err error)
+	CreateNetworkAddressSet(addressSetsPost api.NetworkAddressSetsPost) error
+	UpdateNetworkAddressSet(addressSetName string, addressSetPut api.NetworkAddressSetPut, ETag string) error
+	DeleteNetworkAddressSet(addressSetName string) error
+	RenameNetworkAddressSet(addressSetName string, addressSetPost api.NetworkAddressSetPost) error
+
 	// Network allocations functions ("network_allocations" API extension)
 	GetNetworkAllocations(allProjects bool) (allocations []api.NetworkAllocations, err error)
 
@@ -251,6 +264,7 @@ type InstanceServer interface {
 	GetNetworkZoneNames() (names []string, err error)
 	GetNetworkZones() (zones []api.NetworkZone, err error)
 	GetNetworkZone(name string) (zone *api.NetworkZone, ETag string, err error)
+	GetNetworkZoneState(name string) (zoneState *api.NetworkZoneState, err error)
 	CreateNetworkZone(zone api.NetworkZonesPost) (op Operation, err error)
 	UpdateNetworkZone(name string, zone api.NetworkZonePut, ETag string) (op Operation, err error)
 	DeleteNetworkZone(name string) (op Operation, err error)
@@ -299,6 +313,7 @@ type InstanceServer interface {
 	GetStoragePools() (pools []api.StoragePool, err error)
 	GetStoragePool(name string) (pool *api.StoragePool, ETag string, err error)
 	GetStoragePoolResources(name string) (resources *api.ResourcesStoragePool, err error)
+	GetStoragePoolHealth(name string) (health *api.StoragePoolHealth, err error)
 	CreateStoragePool(pool api.StoragePoolsPost) (op Operation, err error)
 	UpdateStoragePool(name string, pool api.StoragePoolPut, ETag string) (op Operation, err error)
 	DeleteStoragePool(name string) (op Operation, err error)
@@ -331,6 +346,7 @@ type InstanceServer interface {
 	GetStoragePoolVolumesWithFilterAllProjects(pool string, filters []string) (volumes []api.StorageVolume, err error)
 	GetStoragePoolVolume(pool string, volType string, name string) (volume *api.StorageVolume, ETag string, err error)
 	GetStoragePoolVolumeState(pool string, volType string, name string) (state *api.StorageVolumeState, err error)
+	UpdateStoragePoolVolumeState(pool string, volType string, name string, state api.StorageVolumeStatePut) (op Operation, err error)
 	CreateStoragePoolVolume(pool string, volume api.StorageVolumesPost) (op Operation, err error)
 	UpdateStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumePut, ETag string) (op Operation, err error)
 	RenameStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumePost) (op Operation, err error)
@@ -345,6 +361,7 @@ type InstanceServer interface {
 	GetStoragePoolVolumeSnapshotNames(pool string, volumeType string, volumeName string) (names []string, err error)
 	GetStoragePoolVolumeSnapshots(pool string, volumeType string, volumeName string) (snapshots []api.StorageVolumeSnapshot, err error)
 	GetStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string) (snapshot *api.StorageVolumeSnapshot, ETag string, err error)
+	GetStoragePoolVolumeSnapshotDiff(pool string, volumeType string, volumeName string, snapshotName string, against string) (entries []api.SnapshotDiffEntry, err error)
 	RenameStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string, snapshot api.StorageVolumeSnapshotPost) (op Operation, err error)
 	UpdateStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string, volume api.StorageVolumeSnapshotPut, ETag string) (op Operation, err error)
 
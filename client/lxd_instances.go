@@ -1790,6 +1790,35 @@ func (r *ProtocolLXD) GetInstanceSnapshot(instanceName string, name string) (*ap
 	return &snapshot, etag, nil
 }
 
+// GetInstanceSnapshotDiff returns the paths which differ between the instance snapshot and either a later
+// snapshot or, if against is empty, the instance itself.
+func (r *ProtocolLXD) GetInstanceSnapshotDiff(instanceName string, name string, against string) ([]api.SnapshotDiffEntry, error) {
+	err := r.CheckExtension("snapshot_diff")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	u := api.NewURL().Path(strings.TrimPrefix(path, "/"), instanceName, "snapshots", name, "diff")
+	if against != "" {
+		u = u.WithQuery("against", against)
+	}
+
+	entries := []api.SnapshotDiffEntry{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, u.String(), nil, "", &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
 // CreateInstanceSnapshot requests that LXD creates a new snapshot for the instance.
 func (r *ProtocolLXD) CreateInstanceSnapshot(instanceName string, snapshot api.InstanceSnapshotsPost) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -118,6 +118,24 @@ func (r *ProtocolLXD) GetNetworkACLLogfile(name string) (io.ReadCloser, error) {
 	return resp.Body, err
 }
 
+// GetNetworkACLState returns the rule counters of the ACL for each network using it.
+func (r *ProtocolLXD) GetNetworkACLState(name string) (*api.NetworkACLState, error) {
+	err := r.CheckExtension("network_acl_counters")
+	if err != nil {
+		return nil, err
+	}
+
+	aclState := api.NetworkACLState{}
+
+	// Fetch the raw value.
+	_, err = r.queryStruct(http.MethodGet, "/network-acls/"+url.PathEscape(name)+"/state", nil, "", &aclState)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aclState, nil
+}
+
 // CreateNetworkACL defines a new network ACL using the provided struct.
 func (r *ProtocolLXD) CreateNetworkACL(acl api.NetworkACLsPost) (Operation, error) {
 	err := r.CheckExtension("network_acl")
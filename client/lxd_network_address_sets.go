@@ -0,0 +1,149 @@
+package lxd
+
+import (
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// GetNetworkAddressSetNames returns a list of network address set names in the current project.
+func (r *ProtocolLXD) GetNetworkAddressSetNames() ([]string, error) {
+	err := r.CheckExtension("network_address_set")
+	if err != nil {
+		return nil, err
+	}
+
+	urls := []string{}
+	baseURL := api.NewURL().Path("network-address-sets").String()
+	_, err = r.queryStruct(http.MethodGet, baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetNetworkAddressSetNamesAllProjects returns a map of project name to slice of network address set names.
+func (r *ProtocolLXD) GetNetworkAddressSetNamesAllProjects() (map[string][]string, error) {
+	err := r.CheckExtension("network_address_set")
+	if err != nil {
+		return nil, err
+	}
+
+	urls := []string{}
+	baseURL := api.NewURL().Path("network-address-sets").WithQuery("all-projects", "true").String()
+	_, err = r.queryStruct(http.MethodGet, baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return urlsToResourceNamesAllProjects(baseURL, urls...)
+}
+
+// GetNetworkAddressSets returns network address sets in the current project.
+func (r *ProtocolLXD) GetNetworkAddressSets() ([]api.NetworkAddressSet, error) {
+	err := r.CheckExtension("network_address_set")
+	if err != nil {
+		return nil, err
+	}
+
+	var addressSets []api.NetworkAddressSet
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("network-address-sets").WithQuery("recursion", "1").String(), nil, "", &addressSets)
+	if err != nil {
+		return nil, err
+	}
+
+	return addressSets, nil
+}
+
+// GetNetworkAddressSetsAllProjects returns the network address sets from all projects.
+func (r *ProtocolLXD) GetNetworkAddressSetsAllProjects() ([]api.NetworkAddressSet, error) {
+	err := r.CheckExtension("network_address_set")
+	if err != nil {
+		return nil, err
+	}
+
+	var addressSets []api.NetworkAddressSet
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("network-address-sets").WithQuery("recursion", "1").WithQuery("all-projects", "true").String(), nil, "", &addressSets)
+	if err != nil {
+		return nil, err
+	}
+
+	return addressSets, nil
+}
+
+// GetNetworkAddressSet gets a single network address set.
+func (r *ProtocolLXD) GetNetworkAddressSet(addressSetName string) (*api.NetworkAddressSet, string, error) {
+	err := r.CheckExtension("network_address_set")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var addressSet api.NetworkAddressSet
+	eTag, err := r.queryStruct(http.MethodGet, api.NewURL().Path("network-address-sets", addressSetName).String(), nil, "", &addressSet)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &addressSet, eTag, nil
+}
+
+// CreateNetworkAddressSet creates a new network address set.
+func (r *ProtocolLXD) CreateNetworkAddressSet(addressSetsPost api.NetworkAddressSetsPost) error {
+	err := r.CheckExtension("network_address_set")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queryStruct(http.MethodPost, api.NewURL().Path("network-address-sets").String(), addressSetsPost, "", nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateNetworkAddressSet fully overwrites the updatable fields of the network address set.
+func (r *ProtocolLXD) UpdateNetworkAddressSet(addressSetName string, addressSetPut api.NetworkAddressSetPut, ETag string) error {
+	err := r.CheckExtension("network_address_set")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queryStruct(http.MethodPut, api.NewURL().Path("network-address-sets", addressSetName).String(), addressSetPut, ETag, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteNetworkAddressSet deletes the network address set.
+func (r *ProtocolLXD) DeleteNetworkAddressSet(addressSetName string) error {
+	err := r.CheckExtension("network_address_set")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queryStruct(http.MethodDelete, api.NewURL().Path("network-address-sets", addressSetName).String(), nil, "", nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameNetworkAddressSet renames the network address set.
+func (r *ProtocolLXD) RenameNetworkAddressSet(addressSetName string, addressSetPost api.NetworkAddressSetPost) error {
+	err := r.CheckExtension("network_address_set")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queryStruct(http.MethodPost, api.NewURL().Path("network-address-sets", addressSetName).String(), addressSetPost, "", nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -81,6 +81,24 @@ func (r *ProtocolLXD) GetNetworkZone(name string) (*api.NetworkZone, string, err
 	return &zone, etag, nil
 }
 
+// GetNetworkZoneState returns the state of the Network zone for the provided name.
+func (r *ProtocolLXD) GetNetworkZoneState(name string) (*api.NetworkZoneState, error) {
+	err := r.CheckExtension("network_zones_dnssec")
+	if err != nil {
+		return nil, err
+	}
+
+	zoneState := api.NetworkZoneState{}
+
+	// Fetch the raw value.
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("network-zones", name, "state").String(), nil, "", &zoneState)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zoneState, nil
+}
+
 // CreateNetworkZone defines a new Network zone using the provided struct.
 func (r *ProtocolLXD) CreateNetworkZone(zone api.NetworkZonesPost) (Operation, error) {
 	err := r.CheckExtension("network_dns")
@@ -174,3 +174,21 @@ func (r *ProtocolLXD) GetStoragePoolResources(name string) (*api.ResourcesStorag
 
 	return &res, nil
 }
+
+// GetStoragePoolHealth gets the health of a given storage pool and the devices backing it.
+func (r *ProtocolLXD) GetStoragePoolHealth(name string) (*api.StoragePoolHealth, error) {
+	err := r.CheckExtension("storage_pool_health")
+	if err != nil {
+		return nil, err
+	}
+
+	health := api.StoragePoolHealth{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, fmt.Sprintf("/storage-pools/%s/health", url.PathEscape(name)), nil, "", &health)
+	if err != nil {
+		return nil, err
+	}
+
+	return &health, nil
+}
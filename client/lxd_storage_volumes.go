@@ -248,6 +248,23 @@ func (r *ProtocolLXD) GetStoragePoolVolumeState(pool string, volType string, nam
 	return &state, nil
 }
 
+// UpdateStoragePoolVolumeState triggers a state action (such as a mirror refresh or promotion) on a storage volume.
+func (r *ProtocolLXD) UpdateStoragePoolVolumeState(pool string, volType string, name string, state api.StorageVolumeStatePut) (Operation, error) {
+	err := r.CheckExtension("storage_volume_mirror")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	path := api.NewURL().Path("storage-pools", pool, "volumes", volType, name, "state")
+	op, _, err := r.queryOperation(http.MethodPut, path.String(), state, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // CreateStoragePoolVolume defines a new storage volume.
 func (r *ProtocolLXD) CreateStoragePoolVolume(pool string, volume api.StorageVolumesPost) (Operation, error) {
 	err := r.CheckExtension("storage")
@@ -349,6 +366,29 @@ func (r *ProtocolLXD) GetStoragePoolVolumeSnapshot(pool string, volumeType strin
 	return &snapshot, etag, nil
 }
 
+// GetStoragePoolVolumeSnapshotDiff returns the paths which differ between the storage volume snapshot and either
+// a later snapshot or, if against is empty, the storage volume itself.
+func (r *ProtocolLXD) GetStoragePoolVolumeSnapshotDiff(pool string, volumeType string, volumeName string, snapshotName string, against string) ([]api.SnapshotDiffEntry, error) {
+	err := r.CheckExtension("snapshot_diff")
+	if err != nil {
+		return nil, err
+	}
+
+	u := api.NewURL().Path("storage-pools", pool, "volumes", volumeType, volumeName, "snapshots", snapshotName, "diff")
+	if against != "" {
+		u = u.WithQuery("against", against)
+	}
+
+	entries := []api.SnapshotDiffEntry{}
+
+	_, err = r.queryStruct(http.MethodGet, u.String(), nil, "", &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
 // RenameStoragePoolVolumeSnapshot renames a storage volume snapshot.
 func (r *ProtocolLXD) RenameStoragePoolVolumeSnapshot(pool string, volumeType string, volumeName string, snapshotName string, snapshot api.StorageVolumeSnapshotPost) (Operation, error) {
 	err := r.CheckExtension("storage_api_volume_snapshots")
@@ -103,6 +103,10 @@ func (c *cmdNetwork) command() *cobra.Command {
 	networkACLCmd := cmdNetworkACL{global: c.global}
 	cmd.AddCommand(networkACLCmd.command())
 
+	// Address set
+	networkAddressSetCmd := cmdNetworkAddressSet{global: c.global}
+	cmd.AddCommand(networkAddressSetCmd.command())
+
 	// Forward
 	networkForwardCmd := cmdNetworkForward{global: c.global}
 	cmd.AddCommand(networkForwardCmd.command())
@@ -1005,6 +1009,20 @@ func (c *cmdNetworkInfo) run(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Chassis: %s\n", state.OVN.Chassis)
 	}
 
+	// BGP information.
+	if state.BGP != nil {
+		fmt.Println("")
+		fmt.Println("BGP routes:")
+		for _, route := range state.BGP.Routes {
+			status := "learned"
+			if route.Imported {
+				status = "imported"
+			}
+
+			fmt.Printf("  %s via %s (peer %s, %s)\n", route.Prefix, route.Nexthop, route.Peer, status)
+		}
+	}
+
 	return nil
 }
 
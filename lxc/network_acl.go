@@ -43,6 +43,10 @@ func (c *cmdNetworkACL) command() *cobra.Command {
 	networkACLShowLogCmd := cmdNetworkACLShowLog{global: c.global, networkACL: c}
 	cmd.AddCommand(networkACLShowLogCmd.command())
 
+	// Show state.
+	networkACLShowStateCmd := cmdNetworkACLShowState{global: c.global, networkACL: c}
+	cmd.AddCommand(networkACLShowStateCmd.command())
+
 	// Get.
 	networkACLGetCmd := cmdNetworkACLGet{global: c.global, networkACL: c}
 	cmd.AddCommand(networkACLGetCmd.command())
@@ -318,6 +322,102 @@ func (c *cmdNetworkACLShowLog) run(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+// Show state.
+type cmdNetworkACLShowState struct {
+	global     *cmdGlobal
+	networkACL *cmdNetworkACL
+
+	flagFormat string
+}
+
+func (c *cmdNetworkACLShowState) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show-state", "[<remote>:]<ACL>")
+	cmd.Short = "Show network ACL rule counters"
+	cmd.Long = cli.FormatSection("Description", `Show network ACL rule counters
+
+The packet and byte counters of each rule are shown for each network using the ACL,
+and for each instance NIC on an OVN network that sets the ACL in its own security.acls option.
+Rules are numbered by their position in the ACL's ingress or egress rules.`)
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("network_acl", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkACLShowState) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return errors.New("Missing network ACL name")
+	}
+
+	// Get the ACL state.
+	aclState, err := resource.server.GetNetworkACLState(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	addRows := func(networkName string, instanceName string, nicName string, location string, ingress []api.NetworkACLRuleCounters, egress []api.NetworkACLRuleCounters) {
+		for _, direction := range []string{"ingress", "egress"} {
+			rules := ingress
+			if direction == "egress" {
+				rules = egress
+			}
+
+			for ruleIndex, rule := range rules {
+				data = append(data, []string{networkName, instanceName, nicName, location, direction, strconv.Itoa(ruleIndex), strconv.FormatUint(rule.Packets, 10), strconv.FormatUint(rule.Bytes, 10)})
+			}
+		}
+	}
+
+	for _, network := range aclState.Networks {
+		addRows(network.Name, "", "", network.Location, network.Ingress, network.Egress)
+	}
+
+	for _, nic := range aclState.NICs {
+		instanceName := nic.Instance
+		if nic.Project != api.ProjectDefaultName {
+			instanceName = nic.Project + "/" + nic.Instance
+		}
+
+		addRows(nic.Network, instanceName, nic.Name, nic.Location, nic.Ingress, nic.Egress)
+	}
+
+	header := []string{
+		"NETWORK",
+		"INSTANCE",
+		"NIC",
+		"LOCATION",
+		"DIRECTION",
+		"RULE",
+		"PACKETS",
+		"BYTES",
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, aclState)
+}
+
 // Get.
 type cmdNetworkACLGet struct {
 	global     *cmdGlobal
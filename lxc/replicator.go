@@ -7,6 +7,7 @@ import (
 	"maps"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -888,14 +889,35 @@ func (c *cmdReplicatorInfo) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	instanceStates := make(map[string]api.ReplicatorInstanceState, len(state.Instances))
+	for _, instState := range state.Instances {
+		instanceStates[instState.Name] = instState
+	}
+
 	// Render instances as a table.
 	fmt.Println("Instances:")
 	instanceData := make([][]string, 0, len(instanceNames))
 	for _, name := range instanceNames {
-		instanceData = append(instanceData, []string{name})
+		instState, ok := instanceStates[name]
+		if !ok {
+			instanceData = append(instanceData, []string{name, "", "", "", ""})
+			continue
+		}
+
+		var lastRun string
+		if shared.TimeIsSet(instState.LastRunAt) {
+			lastRun = instState.LastRunAt.Local().Format(layout)
+		}
+
+		var recoveryPoint string
+		if instState.RecoveryPointAge >= 0 {
+			recoveryPoint = fmt.Sprintf("%s (%s ago)", instState.RecoveryPointAt.Local().Format(layout), (time.Duration(instState.RecoveryPointAge) * time.Second).String())
+		}
+
+		instanceData = append(instanceData, []string{name, instState.LastRunStatus, lastRun, recoveryPoint, strconv.Itoa(instState.ReplicationPoints)})
 	}
 
-	err = cli.RenderTable(cli.TableFormatTable, []string{"NAME"}, instanceData, instanceNames)
+	err = cli.RenderTable(cli.TableFormatTable, []string{"NAME", "LAST STATUS", "LAST RUN", "RECOVERY POINT", "SNAPSHOTS"}, instanceData, instanceNames)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	cli "github.com/canonical/lxd/shared/cmd"
 	"github.com/canonical/lxd/shared/termios"
+	"github.com/canonical/lxd/shared/units"
 )
 
 type cmdSnapshot struct {
@@ -52,6 +54,10 @@ running state, including process memory state, TCP connections, ...`)
 		return c.global.cmpTopLevelResource("instance", toComplete)
 	}
 
+	// Diff
+	snapshotDiffCmd := cmdSnapshotDiff{global: c.global}
+	cmd.AddCommand(snapshotDiffCmd.command())
+
 	return cmd
 }
 
@@ -139,3 +145,129 @@ func (c *cmdSnapshot) run(cmd *cobra.Command, args []string) error {
 
 	return op.Wait()
 }
+
+// Diff.
+type cmdSnapshotDiff struct {
+	global *cmdGlobal
+
+	flagFormat  string
+	flagStorage string
+	flagTarget  string
+}
+
+func (c *cmdSnapshotDiff) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("diff", "[<remote>:]<instance>/<snapshot> [<snapshot>]")
+	cmd.Short = "Compare snapshot content"
+	cmd.Long = cli.FormatSection("Description", `Compare snapshot content
+
+Lists the paths which were added, removed or modified since the snapshot was taken,
+either in the instance itself or in a later snapshot of it.
+
+When --storage is used, the snapshot is the one of a custom storage volume in that pool.`)
+	cmd.Example = cli.FormatSection("", `lxc snapshot diff u1/snap0
+    Show the changes made to "u1" since "snap0" was taken.
+
+lxc snapshot diff u1/snap0 snap1
+    Show the changes made to "u1" between "snap0" and "snap1".
+
+lxc snapshot diff --storage default v1/snap0
+    Show the changes made to the custom storage volume "v1" in pool "default" since "snap0" was taken.`)
+
+	cmd.RunE = c.run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+	cmd.Flags().StringVarP(&c.flagStorage, "storage", "s", "", cli.FormatStringFlagLabel("Storage pool of the custom storage volume to compare"))
+	cmd.Flags().StringVar(&c.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) == 0 && c.flagStorage == "" {
+			return c.global.cmpInstancesAndSnapshots(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdSnapshotDiff) run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	remote, name, err := conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	parentName, snapshotName, isSnapshot := api.GetParentAndSnapshotName(name)
+	if !isSnapshot {
+		return fmt.Errorf("Invalid snapshot name %q: Must be of the form <name>/<snapshot>", name)
+	}
+
+	var against string
+	if len(args) > 1 {
+		against = args[1]
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	var entries []api.SnapshotDiffEntry
+	if c.flagStorage != "" {
+		if c.flagTarget != "" {
+			d = d.UseTarget(c.flagTarget)
+		}
+
+		entries, err = d.GetStoragePoolVolumeSnapshotDiff(c.flagStorage, "custom", parentName, snapshotName, against)
+	} else {
+		if c.flagTarget != "" {
+			return errors.New("--target can only be used with --storage")
+		}
+
+		entries, err = d.GetInstanceSnapshotDiff(parentName, snapshotName, against)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Render the table.
+	data := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		size := units.GetByteSizeStringIEC(entry.NewSize, 2)
+		mode := entry.NewMode
+
+		switch entry.Change {
+		case api.SnapshotDiffChangeRemoved:
+			size = units.GetByteSizeStringIEC(entry.OldSize, 2)
+			mode = entry.OldMode
+		case api.SnapshotDiffChangeModified:
+			if entry.OldSize != entry.NewSize {
+				size = units.GetByteSizeStringIEC(entry.OldSize, 2) + " -> " + size
+			}
+
+			if entry.OldMode != entry.NewMode {
+				mode = entry.OldMode + " -> " + mode
+			}
+		}
+
+		data = append(data, []string{entry.Change, entry.Path, entry.Type, size, mode})
+	}
+
+	header := []string{
+		"CHANGE",
+		"PATH",
+		"TYPE",
+		"SIZE",
+		"MODE",
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, entries)
+}
@@ -886,15 +886,17 @@ type cmdStorageShow struct {
 	storage *cmdStorage
 
 	flagResources bool
+	flagHealth    bool
 }
 
 func (c *cmdStorageShow) command() *cobra.Command {
 	cmd := &cobra.Command{}
 	cmd.Use = usage("show", "[<remote>:]<pool>")
-	cmd.Short = "Show storage pool configurations and resources"
+	cmd.Short = "Show storage pool configurations, resources and health"
 	cmd.Long = cli.FormatSection("Description", cmd.Short)
 
 	cmd.Flags().BoolVar(&c.flagResources, "resources", false, "Show the resources available to the storage pool")
+	cmd.Flags().BoolVar(&c.flagHealth, "health", false, "Show the health of the storage pool and the devices backing it")
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
 	cmd.RunE = c.run
 
@@ -939,6 +941,26 @@ func (c *cmdStorageShow) run(cmd *cobra.Command, args []string) error {
 		client = client.UseTarget(c.storage.flagTarget)
 	}
 
+	if c.flagResources && c.flagHealth {
+		return errors.New("--resources and --health cannot be used together")
+	}
+
+	if c.flagHealth {
+		health, err := client.GetStoragePoolHealth(resource.name)
+		if err != nil {
+			return err
+		}
+
+		data, err := yaml.Marshal(&health)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s", data)
+
+		return nil
+	}
+
 	if c.flagResources {
 		res, err := client.GetStoragePoolResources(resource.name)
 		if err != nil {
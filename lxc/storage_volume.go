@@ -123,6 +123,10 @@ Unless specified through a prefix, all volume operations affect "custom" (user c
 	storageVolumeRenameCmd := cmdStorageVolumeRename{global: c.global, storage: c.storage, storageVolume: c}
 	cmd.AddCommand(storageVolumeRenameCmd.command())
 
+	// Mirror
+	storageVolumeMirrorCmd := cmdStorageVolumeMirror{global: c.global, storage: c.storage, storageVolume: c}
+	cmd.AddCommand(storageVolumeMirrorCmd.command())
+
 	// Move
 	storageVolumeMoveCmd := cmdStorageVolumeMove{global: c.global, storage: c.storage, storageVolume: c, storageVolumeCopy: &storageVolumeCopyCmd, storageVolumeRename: &storageVolumeRenameCmd}
 	cmd.AddCommand(storageVolumeMoveCmd.command())
@@ -1502,6 +1506,43 @@ func (c *cmdStorageVolumeInfo) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if volState != nil && volState.Limits != nil {
+		fmt.Println("Limits:")
+
+		if volState.Limits.ReadBytes > 0 {
+			fmt.Printf("  Read bandwidth: %s/s\n", units.GetByteSizeStringIEC(volState.Limits.ReadBytes, 2))
+		}
+
+		if volState.Limits.WriteBytes > 0 {
+			fmt.Printf("  Write bandwidth: %s/s\n", units.GetByteSizeStringIEC(volState.Limits.WriteBytes, 2))
+		}
+
+		if volState.Limits.ReadIOps > 0 {
+			fmt.Printf("  Read IOPS: %d\n", volState.Limits.ReadIOps)
+		}
+
+		if volState.Limits.WriteIOps > 0 {
+			fmt.Printf("  Write IOPS: %d\n", volState.Limits.WriteIOps)
+		}
+
+		if len(volState.Limits.Instances) > 0 {
+			fmt.Println("  Shared by:")
+			for _, instURL := range volState.Limits.Instances {
+				fmt.Printf("    - %s\n", instURL)
+			}
+		}
+	}
+
+	if volState != nil && volState.Mirror != nil {
+		fmt.Println("Mirror:")
+		fmt.Printf("  Role: %s\n", volState.Mirror.Role)
+
+		if shared.TimeIsSet(volState.Mirror.LastRefreshAt) {
+			fmt.Printf("  Last refresh: %s\n", volState.Mirror.LastRefreshAt.Local().Format(layout))
+			fmt.Printf("  Lag: %s\n", time.Duration(volState.Mirror.Lag)*time.Second)
+		}
+	}
+
 	if shared.TimeIsSet(vol.CreatedAt) {
 		fmt.Printf("Created: %s\n", vol.CreatedAt.Local().Format(layout))
 	}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdStorageVolumeMirror struct {
+	global        *cmdGlobal
+	storage       *cmdStorage
+	storageVolume *cmdStorageVolume
+}
+
+func (c *cmdStorageVolumeMirror) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("mirror")
+	cmd.Short = "Manage custom storage volume mirrors"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+Mirrors are configured through the "mirror.*" volume configuration keys.`)
+
+	// Promote
+	storageVolumeMirrorPromoteCmd := cmdStorageVolumeMirrorAction{global: c.global, storage: c.storage, action: "promote"}
+	cmd.AddCommand(storageVolumeMirrorPromoteCmd.command())
+
+	// Refresh
+	storageVolumeMirrorRefreshCmd := cmdStorageVolumeMirrorAction{global: c.global, storage: c.storage, action: "refresh"}
+	cmd.AddCommand(storageVolumeMirrorRefreshCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Refresh and promote.
+type cmdStorageVolumeMirrorAction struct {
+	global  *cmdGlobal
+	storage *cmdStorage
+	action  string
+}
+
+func (c *cmdStorageVolumeMirrorAction) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage(c.action, "[<remote>:]<pool> <volume>")
+
+	switch c.action {
+	case "promote":
+		cmd.Short = "Promote a custom storage volume mirror"
+		cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The mirror is detached from its source and becomes a regular custom volume.
+This works even when the source volume is no longer reachable.`)
+	case "refresh":
+		cmd.Short = "Refresh a custom storage volume mirror"
+		cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The volume must be the source of the mirror (have "mirror.pool" set).`)
+	}
+
+	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("storage_pool", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpStoragePoolVolumes(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdStorageVolumeMirrorAction) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return errors.New("Missing pool name")
+	}
+
+	client := resource.server
+
+	// Use the provided target.
+	if c.storage.flagTarget != "" {
+		client = client.UseTarget(c.storage.flagTarget)
+	}
+
+	op, err := client.UpdateStoragePoolVolumeState(resource.name, "custom", args[1], api.StorageVolumeStatePut{Action: c.action})
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
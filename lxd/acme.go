@@ -2,13 +2,23 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-acme/lego/v4/challenge"
 
 	"github.com/canonical/lxd/lxd/acme"
 	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/network/zone"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
@@ -74,8 +84,13 @@ func autoRenewCertificate(ctx context.Context, d *Daemon, force bool) error {
 		return nil
 	}
 
+	provider, err := acmeChallengeProvider(d)
+	if err != nil {
+		return err
+	}
+
 	opRun := func(ctx context.Context, op *operations.Operation) error {
-		newCert, err := acme.UpdateCertificate(s, d.http01Provider, s.ServerClustered, domain, email, caURL, force)
+		newCert, err := acme.UpdateCertificate(s, provider, s.ServerClustered, domain, email, caURL, force)
 		if err != nil {
 			return err
 		}
@@ -138,6 +153,113 @@ func autoRenewCertificate(ctx context.Context, d *Daemon, force bool) error {
 	return nil
 }
 
+// acmeChallengeProvider returns the provider for the configured ACME challenge.
+func acmeChallengeProvider(d *Daemon) (challenge.Provider, error) {
+	s := d.State()
+
+	challengeType, providerName, providerConfig := s.GlobalConfig.ACMEChallenge()
+	if challengeType != "DNS-01" {
+		return d.http01Provider, nil
+	}
+
+	switch providerName {
+	case "rfc2136":
+		var tsigSecret string
+		err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+			tsigSecret, err = dbCluster.GetACMETSIGSecret(ctx, tx.Tx())
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return acme.NewRFC2136Provider(providerConfig["nameserver"], providerConfig["zone"], providerConfig["tsig_key"], tsigSecret, providerConfig["tsig_algorithm"])
+	case "network-zone":
+		if providerConfig["zone"] == "" {
+			return nil, errors.New(`The "network-zone" ACME provider requires "acme.provider.zone" to be set`)
+		}
+
+		return acme.NewZoneProvider(providerConfig["zone"], &acmeNetworkZoneRecords{s: s, zoneName: providerConfig["zone"]}), nil
+	case "":
+		return nil, errors.New(`The DNS-01 ACME challenge requires "acme.provider" to be set`)
+	}
+
+	return nil, fmt.Errorf("Unknown ACME provider %q", providerName)
+}
+
+// acmeNetworkZoneRecords publishes ACME challenge records in a network zone.
+type acmeNetworkZoneRecords struct {
+	s        *state.State
+	zoneName string
+}
+
+// AddTXTRecord adds the TXT record value to the record with the given name, creating the record if needed.
+func (z *acmeNetworkZoneRecords) AddTXTRecord(ctx context.Context, name string, value string) error {
+	netzone, err := zone.LoadByName(ctx, z.s, z.zoneName)
+	if err != nil {
+		return fmt.Errorf("Failed loading network zone %q: %w", z.zoneName, err)
+	}
+
+	entry := api.NetworkZoneRecordEntry{
+		Type:  "TXT",
+		Value: strconv.Quote(value),
+		TTL:   60,
+	}
+
+	record, err := netzone.GetRecord(ctx, name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return fmt.Errorf("Failed loading network zone record %q: %w", name, err)
+		}
+
+		return netzone.AddRecord(ctx, api.NetworkZoneRecordsPost{
+			Name: name,
+			NetworkZoneRecordPut: api.NetworkZoneRecordPut{
+				Description: "ACME DNS-01 challenge",
+				Entries:     []api.NetworkZoneRecordEntry{entry},
+			},
+		})
+	}
+
+	if slices.Contains(record.Entries, entry) {
+		return nil
+	}
+
+	req := record.Writable()
+	req.Entries = append(req.Entries, entry)
+
+	return netzone.UpdateRecord(ctx, name, req)
+}
+
+// DeleteTXTRecord removes the TXT record value from the record with the given name, deleting the record once empty.
+func (z *acmeNetworkZoneRecords) DeleteTXTRecord(ctx context.Context, name string, value string) error {
+	netzone, err := zone.LoadByName(ctx, z.s, z.zoneName)
+	if err != nil {
+		return fmt.Errorf("Failed loading network zone %q: %w", z.zoneName, err)
+	}
+
+	record, err := netzone.GetRecord(ctx, name)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed loading network zone record %q: %w", name, err)
+	}
+
+	req := record.Writable()
+	req.Entries = slices.DeleteFunc(req.Entries, func(entry api.NetworkZoneRecordEntry) bool {
+		return entry.Type == "TXT" && entry.Value == strconv.Quote(value)
+	})
+
+	if len(req.Entries) == 0 {
+		return netzone.DeleteRecord(ctx, name)
+	}
+
+	return netzone.UpdateRecord(ctx, name, req)
+}
+
 func autoRenewCertificateTask(d *Daemon) (task.Func, task.Schedule) {
 	f := func(ctx context.Context) {
 		_ = autoRenewCertificate(ctx, d, false)
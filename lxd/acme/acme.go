@@ -6,14 +6,17 @@ import (
 	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/registration"
 
@@ -40,7 +43,8 @@ func certificateNeedsUpdate(domain string, cert *x509.Certificate) bool {
 }
 
 // UpdateCertificate updates the certificate.
-func UpdateCertificate(s *state.State, provider HTTP01Provider, clustered bool, domain string, email string, caURL string, force bool) (*certificate.Resource, error) {
+// The provider is either a HTTP01Provider or a DNS01Provider and determines the challenge type used.
+func UpdateCertificate(s *state.State, provider challenge.Provider, clustered bool, domain string, email string, caURL string, force bool) (*certificate.Resource, error) {
 	clusterCertFilename := shared.VarPath(ClusterCertFilename)
 
 	l := logger.AddContext(logger.Ctx{"domain": domain, "caURL": caURL})
@@ -129,9 +133,25 @@ func UpdateCertificate(s *state.State, provider HTTP01Provider, clustered bool,
 		return nil, fmt.Errorf("Failed creating new client: %w", err)
 	}
 
-	err = client.Challenge.SetHTTP01Provider(provider)
-	if err != nil {
-		return nil, fmt.Errorf("Failed setting HTTP-01 provider: %w", err)
+	switch p := provider.(type) {
+	case HTTP01Provider:
+		if strings.HasPrefix(domain, "*.") {
+			return nil, errors.New("Wildcard certificates require the DNS-01 challenge")
+		}
+
+		err = client.Challenge.SetHTTP01Provider(p)
+		if err != nil {
+			return nil, fmt.Errorf("Failed setting HTTP-01 provider: %w", err)
+		}
+
+	case DNS01Provider:
+		err = client.Challenge.SetDNS01Provider(p)
+		if err != nil {
+			return nil, fmt.Errorf("Failed setting DNS-01 provider: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("Unsupported ACME challenge provider %T", provider)
 	}
 
 	var reg *registration.Resource
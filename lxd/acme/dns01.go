@@ -0,0 +1,204 @@
+package acme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/miekg/dns"
+
+	"github.com/canonical/lxd/lxd/util"
+)
+
+// DNS01Provider is a challenge provider which publishes the DNS-01 challenge record.
+type DNS01Provider interface {
+	challenge.ProviderTimeout
+}
+
+// TXTRecordStore adds and removes TXT record values within a DNS zone.
+// Record names are relative to the zone.
+type TXTRecordStore interface {
+	AddTXTRecord(ctx context.Context, name string, value string) error
+	DeleteTXTRecord(ctx context.Context, name string, value string) error
+}
+
+// challengeTTL is the TTL used for the published challenge records.
+const challengeTTL = 60
+
+// tsigAlgorithms maps the supported TSIG algorithm names to their DNS representation.
+var tsigAlgorithms = map[string]string{
+	"hmac-sha1":   dns.HmacSHA1,
+	"hmac-sha224": dns.HmacSHA224,
+	"hmac-sha256": dns.HmacSHA256,
+	"hmac-sha384": dns.HmacSHA384,
+	"hmac-sha512": dns.HmacSHA512,
+}
+
+// zoneRecordName returns the name of the given FQDN relative to the given zone.
+func zoneRecordName(fqdn string, zone string) (string, error) {
+	fqdn = strings.ToLower(dns.Fqdn(fqdn))
+	zone = strings.ToLower(dns.Fqdn(zone))
+
+	name, found := strings.CutSuffix(fqdn, "."+zone)
+	if !found || name == "" {
+		return "", fmt.Errorf("Challenge record %q is not part of zone %q", fqdn, zone)
+	}
+
+	return name, nil
+}
+
+type rfc2136Provider struct {
+	nameserver    string
+	zone          string
+	tsigKey       string
+	tsigSecret    string
+	tsigAlgorithm string
+}
+
+// NewRFC2136Provider returns a DNS01Provider which publishes the challenge record through a DNS
+// UPDATE (RFC 2136) sent to the given nameserver. If zone is empty, the zone is looked up
+// from the challenge record name. If tsigKey is set, the update is signed using TSIG (RFC 8945).
+func NewRFC2136Provider(nameserver string, zone string, tsigKey string, tsigSecret string, tsigAlgorithm string) (DNS01Provider, error) {
+	if nameserver == "" {
+		return nil, errors.New("A nameserver is required for RFC2136 updates")
+	}
+
+	p := &rfc2136Provider{
+		nameserver: util.CanonicalNetworkAddress(nameserver, 53),
+		zone:       zone,
+	}
+
+	if tsigKey != "" {
+		if tsigSecret == "" {
+			return nil, errors.New("A TSIG secret is required when a TSIG key is set")
+		}
+
+		if tsigAlgorithm == "" {
+			tsigAlgorithm = "hmac-sha256"
+		}
+
+		algorithm, ok := tsigAlgorithms[tsigAlgorithm]
+		if !ok {
+			return nil, fmt.Errorf("Unsupported TSIG algorithm %q", tsigAlgorithm)
+		}
+
+		p.tsigKey = dns.Fqdn(tsigKey)
+		p.tsigSecret = tsigSecret
+		p.tsigAlgorithm = algorithm
+	}
+
+	return p, nil
+}
+
+// Present adds the challenge TXT record.
+func (p *rfc2136Provider) Present(domain string, token string, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	return p.update(info.EffectiveFQDN, info.Value, true)
+}
+
+// CleanUp removes the challenge TXT record.
+func (p *rfc2136Provider) CleanUp(domain string, token string, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	return p.update(info.EffectiveFQDN, info.Value, false)
+}
+
+// Timeout returns the timeout and interval used when checking whether the record has propagated.
+func (p *rfc2136Provider) Timeout() (timeout time.Duration, interval time.Duration) {
+	return 2 * time.Minute, 2 * time.Second
+}
+
+// update inserts or removes the TXT record value for the given FQDN.
+func (p *rfc2136Provider) update(fqdn string, value string, insert bool) error {
+	zone := p.zone
+	if zone == "" {
+		var err error
+
+		zone, err = dns01.FindZoneByFqdn(fqdn)
+		if err != nil {
+			return fmt.Errorf("Failed finding zone for %q: %w", fqdn, err)
+		}
+	}
+
+	rr := &dns.TXT{
+		Hdr: dns.RR_Header{Name: dns.Fqdn(fqdn), Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: challengeTTL},
+		Txt: []string{value},
+	}
+
+	msg := &dns.Msg{}
+	msg.SetUpdate(dns.Fqdn(zone))
+
+	if insert {
+		msg.Insert([]dns.RR{rr})
+	} else {
+		msg.Remove([]dns.RR{rr})
+	}
+
+	client := &dns.Client{Net: "tcp", Timeout: 10 * time.Second}
+
+	if p.tsigKey != "" {
+		client.TsigSecret = map[string]string{p.tsigKey: p.tsigSecret}
+		msg.SetTsig(p.tsigKey, p.tsigAlgorithm, 300, time.Now().Unix())
+	}
+
+	reply, _, err := client.Exchange(msg, p.nameserver)
+	if err != nil {
+		return fmt.Errorf("Failed sending DNS update to %q: %w", p.nameserver, err)
+	}
+
+	if reply.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("DNS update of %q rejected by %q: %s", fqdn, p.nameserver, dns.RcodeToString[reply.Rcode])
+	}
+
+	return nil
+}
+
+type zoneProvider struct {
+	zone    string
+	records TXTRecordStore
+}
+
+// NewZoneProvider returns a DNS01Provider which publishes the challenge record in the given zone
+// through the given TXTRecordStore.
+func NewZoneProvider(zone string, records TXTRecordStore) DNS01Provider {
+	return &zoneProvider{
+		zone:    zone,
+		records: records,
+	}
+}
+
+// Present adds the challenge TXT record to the zone.
+func (p *zoneProvider) Present(domain string, token string, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	name, err := zoneRecordName(info.EffectiveFQDN, p.zone)
+	if err != nil {
+		return err
+	}
+
+	return p.records.AddTXTRecord(context.Background(), name, info.Value)
+}
+
+// CleanUp removes the challenge TXT record from the zone.
+func (p *zoneProvider) CleanUp(domain string, token string, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	name, err := zoneRecordName(info.EffectiveFQDN, p.zone)
+	if err != nil {
+		return err
+	}
+
+	return p.records.DeleteTXTRecord(context.Background(), name, info.Value)
+}
+
+// Timeout returns the timeout and interval used when checking whether the record has propagated.
+// Secondary servers transferring the zone only pick up changes when refreshing it, so this is
+// longer than for providers updating the authoritative servers directly.
+func (p *zoneProvider) Timeout() (timeout time.Duration, interval time.Duration) {
+	return 10 * time.Minute, 10 * time.Second
+}
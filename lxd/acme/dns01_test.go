@@ -0,0 +1,214 @@
+package acme
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_zoneRecordName(t *testing.T) {
+	tests := []struct {
+		name    string
+		fqdn    string
+		zone    string
+		want    string
+		wantErr bool
+	}{
+		{"Record in zone", "_acme-challenge.lxd.example.net.", "example.net", "_acme-challenge.lxd", false},
+		{"Zone with trailing dot", "_acme-challenge.lxd.example.net.", "example.net.", "_acme-challenge.lxd", false},
+		{"Mixed case", "_acme-challenge.LXD.Example.net.", "example.NET", "_acme-challenge.lxd", false},
+		{"Record outside zone", "_acme-challenge.lxd.example.org.", "example.net", "", true},
+		{"Zone suffix without label boundary", "_acme-challenge.lxdexample.net.", "example.net", "", true},
+		{"Record is zone apex", "example.net.", "example.net", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := zoneRecordName(tt.fqdn, tt.zone)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type fakeTXTRecordStore struct {
+	records map[string][]string
+}
+
+func (f *fakeTXTRecordStore) AddTXTRecord(_ context.Context, name string, value string) error {
+	f.records[name] = append(f.records[name], value)
+	return nil
+}
+
+func (f *fakeTXTRecordStore) DeleteTXTRecord(_ context.Context, name string, value string) error {
+	values := f.records[name][:0]
+	for _, v := range f.records[name] {
+		if v != value {
+			values = append(values, v)
+		}
+	}
+
+	if len(values) == 0 {
+		delete(f.records, name)
+	} else {
+		f.records[name] = values
+	}
+
+	return nil
+}
+
+func TestZoneProvider(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	store := &fakeTXTRecordStore{records: map[string][]string{}}
+	p := NewZoneProvider("example.net", store)
+
+	info := dns01.GetChallengeInfo("lxd.example.net", "key-auth")
+
+	t.Run("Present adds the challenge record", func(t *testing.T) {
+		require.NoError(t, p.Present("lxd.example.net", "token", "key-auth"))
+		require.Equal(t, []string{info.Value}, store.records["_acme-challenge.lxd"])
+	})
+
+	t.Run("Present for a second value keeps the first one", func(t *testing.T) {
+		require.NoError(t, p.Present("lxd.example.net", "token", "other-key-auth"))
+		require.Len(t, store.records["_acme-challenge.lxd"], 2)
+	})
+
+	t.Run("CleanUp removes the challenge values", func(t *testing.T) {
+		require.NoError(t, p.CleanUp("lxd.example.net", "token", "other-key-auth"))
+		require.Equal(t, []string{info.Value}, store.records["_acme-challenge.lxd"])
+
+		require.NoError(t, p.CleanUp("lxd.example.net", "token", "key-auth"))
+		require.Empty(t, store.records)
+	})
+
+	t.Run("Domain outside the zone is rejected", func(t *testing.T) {
+		require.Error(t, p.Present("lxd.example.org", "token", "key-auth"))
+		require.Empty(t, store.records)
+	})
+}
+
+// startUpdateServer starts a DNS server recording the TXT records added and removed through DNS UPDATE.
+func startUpdateServer(t *testing.T, tsigKey string, tsigSecret string) (string, map[string][]string) {
+	t.Helper()
+
+	records := map[string][]string{}
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := &dns.Msg{}
+		m.SetReply(r)
+
+		if tsigKey != "" && (r.IsTsig() == nil || w.TsigStatus() != nil) {
+			m.SetRcode(r, dns.RcodeRefused)
+			_ = w.WriteMsg(m)
+			return
+		}
+
+		for _, rr := range r.Ns {
+			txt, ok := rr.(*dns.TXT)
+			if !ok {
+				continue
+			}
+
+			if rr.Header().Class == dns.ClassNONE {
+				delete(records, txt.Hdr.Name)
+			} else {
+				records[txt.Hdr.Name] = txt.Txt
+			}
+		}
+
+		if r.IsTsig() != nil {
+			m.SetTsig(tsigKey, r.IsTsig().Algorithm, 300, time.Now().Unix())
+		}
+
+		_ = w.WriteMsg(m)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &dns.Server{Listener: listener, Net: "tcp", Handler: handler}
+
+	// The default accept function rejects DNS UPDATE messages.
+	server.MsgAcceptFunc = func(dns.Header) dns.MsgAcceptAction { return dns.MsgAccept }
+
+	if tsigKey != "" {
+		server.TsigSecret = map[string]string{tsigKey: tsigSecret}
+	}
+
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+
+	go func() { _ = server.ActivateAndServe() }()
+
+	t.Cleanup(func() { _ = server.Shutdown() })
+	<-started
+
+	return listener.Addr().String(), records
+}
+
+func TestRFC2136Provider(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	info := dns01.GetChallengeInfo("lxd.example.net", "key-auth")
+
+	t.Run("Nameserver is required", func(t *testing.T) {
+		_, err := NewRFC2136Provider("", "example.net", "", "", "")
+		require.Error(t, err)
+	})
+
+	t.Run("TSIG secret is required with a TSIG key", func(t *testing.T) {
+		_, err := NewRFC2136Provider("127.0.0.1", "example.net", "acme", "", "")
+		require.Error(t, err)
+	})
+
+	t.Run("Unknown TSIG algorithm is rejected", func(t *testing.T) {
+		_, err := NewRFC2136Provider("127.0.0.1", "example.net", "acme", "c2VjcmV0", "hmac-md4")
+		require.Error(t, err)
+	})
+
+	t.Run("Unsigned update", func(t *testing.T) {
+		addr, records := startUpdateServer(t, "", "")
+
+		p, err := NewRFC2136Provider(addr, "example.net", "", "", "")
+		require.NoError(t, err)
+
+		require.NoError(t, p.Present("lxd.example.net", "token", "key-auth"))
+		require.Equal(t, []string{info.Value}, records["_acme-challenge.lxd.example.net."])
+
+		require.NoError(t, p.CleanUp("lxd.example.net", "token", "key-auth"))
+		require.Empty(t, records)
+	})
+
+	t.Run("Signed update", func(t *testing.T) {
+		addr, records := startUpdateServer(t, "acme.", "c2VjcmV0")
+
+		p, err := NewRFC2136Provider(addr, "example.net", "acme", "c2VjcmV0", "")
+		require.NoError(t, err)
+
+		require.NoError(t, p.Present("lxd.example.net", "token", "key-auth"))
+		require.Equal(t, []string{info.Value}, records["_acme-challenge.lxd.example.net."])
+	})
+
+	t.Run("Update with wrong TSIG secret is refused", func(t *testing.T) {
+		addr, records := startUpdateServer(t, "acme.", "c2VjcmV0")
+
+		p, err := NewRFC2136Provider(addr, "example.net", "acme", "d3Jvbmc=", "")
+		require.NoError(t, err)
+
+		require.Error(t, p.Present("lxd.example.net", "token", "key-auth"))
+		require.Empty(t, records)
+	})
+}
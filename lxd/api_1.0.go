@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -85,6 +86,7 @@ var api10 = []APIEndpoint{
 	instanceRebuildCmd,
 	instanceSFTPCmd,
 	instanceSnapshotCmd,
+	instanceSnapshotDiffCmd,
 	instanceSnapshotsCmd,
 	instanceStateCmd,
 	instanceUEFIVarsCmd,
@@ -100,6 +102,9 @@ var api10 = []APIEndpoint{
 	networkACLCmd,
 	networkACLsCmd,
 	networkACLLogCmd,
+	networkACLStateCmd,
+	networkAddressSetCmd,
+	networkAddressSetsCmd,
 	networkAllocationsCmd,
 	networkForwardCmd,
 	networkForwardsCmd,
@@ -111,6 +116,7 @@ var api10 = []APIEndpoint{
 	networkPeerCmd,
 	networkPeersCmd,
 	networkZoneCmd,
+	networkZoneStateCmd,
 	networkZonesCmd,
 	networkZoneRecordCmd,
 	networkZoneRecordsCmd,
@@ -125,6 +131,7 @@ var api10 = []APIEndpoint{
 	projectStateCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
+	storagePoolHealthCmd,
 	storagePoolsCmd,
 	storagePoolBucketsCmd,
 	storagePoolBucketCmd,
@@ -133,6 +140,7 @@ var api10 = []APIEndpoint{
 	storagePoolVolumesCmd,
 	storagePoolVolumeSnapshotsTypeCmd,
 	storagePoolVolumeSnapshotTypeCmd,
+	storagePoolVolumeSnapshotTypeDiffCmd,
 	storagePoolVolumesTypeCmd,
 	storagePoolVolumeTypeCmd,
 	storagePoolVolumeTypeCustomBackupsCmd,
@@ -843,6 +851,21 @@ func doAPI10Update(d *Daemon, r *http.Request, req api.ServerPut, patch bool) re
 		return response.BadRequest(errors.New("The cluster UUID cannot be changed"))
 	}
 
+	// The secret key of the S3 endpoint for backups is stored as a server secret rather than in the configuration.
+	// It is only updated when included in the request so that it isn't removed by a PUT of the returned configuration.
+	backupsS3SecretKey, setBackupsS3SecretKey := stringReqConfig["backups.s3.secret_key"]
+	delete(stringReqConfig, "backups.s3.secret_key")
+
+	// Same for the TSIG secret of the rfc2136 ACME provider.
+	acmeTSIGSecret, setACMETSIGSecret := stringReqConfig["acme.provider.tsig_secret"]
+	delete(stringReqConfig, "acme.provider.tsig_secret")
+	if acmeTSIGSecret != "" {
+		_, err := base64.StdEncoding.DecodeString(acmeTSIGSecret)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid value for %q: %w", "acme.provider.tsig_secret", err))
+		}
+	}
+
 	d.globalConfigMu.Lock()
 	currentGlobalConfig := d.globalConfig
 	d.globalConfigMu.Unlock()
@@ -974,6 +997,20 @@ func doAPI10Update(d *Daemon, r *http.Request, req api.ServerPut, patch bool) re
 		// Keep old config around in case something goes wrong. In that case the config will be reverted.
 		oldClusterConfig = newClusterConfig.Dump()
 
+		if setBackupsS3SecretKey {
+			err = dbCluster.UpdateBackupsS3SecretKey(ctx, tx.Tx(), backupsS3SecretKey)
+			if err != nil {
+				return err
+			}
+		}
+
+		if setACMETSIGSecret {
+			err = dbCluster.UpdateACMETSIGSecret(ctx, tx.Tx(), acmeTSIGSecret)
+			if err != nil {
+				return err
+			}
+		}
+
 		if patch {
 			clusterChanged, err = newClusterConfig.Patch(tx, stringReqConfig)
 		} else {
@@ -1203,6 +1240,14 @@ func doAPI10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	value, ok = nodeChanged["core.storage_buckets_address"]
+	if ok {
+		err := s.Endpoints.StorageBucketsUpdateAddress(value, s.Endpoints.NetworkCert())
+		if err != nil {
+			return err
+		}
+	}
+
 	value, ok = nodeChanged["storage.backups_volume"]
 	if ok {
 		oldValue := oldNodeConfig["storage.backups_volume"]
@@ -1921,7 +1921,6 @@ func evacuateInstances(ctx context.Context, opts evacuateOpts) error {
 }
 
 func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst instance.Instance, pgCache *placement.Cache) (*db.NodeInfo, error) {
-	var targetMemberInfo *db.NodeInfo
 	var candidateMembers []db.NodeInfo
 
 	// Get candidate cluster members to move instances to.
@@ -1979,19 +1978,51 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 			candidateMembers = newMembers
 		}
 
-		// Find the least loaded cluster member which supports the instance's architecture.
-		targetMemberInfo, err = tx.GetNodeWithLeastInstances(ctx, candidateMembers)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var req *placement.Requirements
+	if s.GlobalConfig.ClusterScheduler() == placement.SchedulerResources {
+		req, err = placement.InstanceRequirements(inst.Type(), inst.ExpandedConfig(), inst.ExpandedDevices().CloneNative())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Find the cluster member which supports the instance's architecture using the configured scheduler.
+	return clusterSelectMember(ctx, s, candidateMembers, req)
+}
+
+// restoreClusterMemberPlacementAllowed returns whether the placement group of the instance (if any) allows moving it back to the restored member.
+func restoreClusterMemberPlacementAllowed(ctx context.Context, s *state.State, inst instance.Instance, originName string, pgCache *placement.Cache) (bool, error) {
+	placementGroupName := inst.ExpandedConfig()["placement.group"]
+	if placementGroupName == "" {
+		return true, nil
+	}
+
+	allowed := true
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		originMember, err := tx.GetNodeByName(ctx, originName)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster member %q: %w", originName, err)
+		}
+
+		apiPlacementGroup, err := pgCache.Get(ctx, tx, placementGroupName, inst.Project().Name)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		allowed, err = placement.Allows(ctx, tx, originMember, *apiPlacementGroup, int64(inst.ID()))
+		return err
 	})
 	if err != nil {
-		return nil, err
+		return false, fmt.Errorf("Failed checking placement group %q of instance %q: %w", placementGroupName, inst.Name(), err)
 	}
 
-	return targetMemberInfo, nil
+	return allowed, nil
 }
 
 func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Response {
@@ -2094,10 +2125,24 @@ func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Resp
 				}
 			}
 
+			// Prepare a placement group cache to avoid reloading the same group repeatedly.
+			pgCache := placement.NewCache()
+
 			// Migrate back the remote instances.
 			for _, inst := range instances {
 				l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 
+				// Leave the instance where it is if moving it back would violate its placement group.
+				allowed, err := restoreClusterMemberPlacementAllowed(ctx, s, inst, originName, pgCache)
+				if err != nil {
+					return err
+				}
+
+				if !allowed {
+					l.Warn("Not restoring instance as it would violate its placement group")
+					continue
+				}
+
 				// Check if live-migratable.
 				_, live := inst.CanMigrate()
 
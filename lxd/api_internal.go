@@ -988,69 +988,88 @@ func internalImportFromBackup(ctx context.Context, s *state.State, bInfo *backup
 			return fmt.Errorf(`Storage volume for snapshot %q already exists in the database`, snapInstName)
 		}
 
-		baseImage := snap.Config["volatile.base_image"]
-
-		arch, err := osarch.ArchitectureId(snap.Architecture)
+		cleanup, err := internalImportSnapshotRecord(ctx, s, projectName, backupConf.Instance.Name, instancePoolName, instanceType, instanceVolType, snap)
 		if err != nil {
 			return err
 		}
 
-		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-			profiles, err = tx.GetProfiles(ctx, projectName, snap.Profiles)
+		revert.Add(cleanup)
+	}
 
-			return err
-		})
-		if err != nil {
-			return fmt.Errorf("Failed loading profiles for instance snapshot %q: %w", snapInstName, err)
-		}
+	revert.Success()
+	return nil
+}
 
-		// Add root device if needed.
-		if snap.Devices == nil {
-			snap.Devices = make(map[string]map[string]string, 0)
-		}
+// internalImportSnapshotRecord creates the database record of an imported instance snapshot and recreates its
+// mountpoint and symlinks. The returned revert hook removes the database record.
+func internalImportSnapshotRecord(ctx context.Context, s *state.State, projectName string, instName string, instancePoolName string, instanceType instancetype.Type, instanceVolType storageDrivers.VolumeType, snap *api.InstanceSnapshot) (revert.Hook, error) {
+	snapInstName := instName + shared.SnapshotDelimiter + snap.Name
+	baseImage := snap.Config["volatile.base_image"]
 
-		if snap.ExpandedDevices == nil {
-			snap.ExpandedDevices = make(map[string]map[string]string, 0)
-		}
+	arch, err := osarch.ArchitectureId(snap.Architecture)
+	if err != nil {
+		return nil, err
+	}
 
-		internalImportRootDevicePopulate(instancePoolName, snap.Devices, snap.ExpandedDevices, profiles)
-
-		_, snapInstOp, cleanup, err := instance.CreateInternal(ctx, s, db.InstanceArgs{
-			Project:      projectName,
-			Architecture: arch,
-			BaseImage:    baseImage,
-			Config:       snap.Config,
-			CreationDate: snap.CreatedAt,
-			Type:         instanceType,
-			Snapshot:     true,
-			Devices:      deviceConfig.NewDevices(snap.Devices),
-			Ephemeral:    snap.Ephemeral,
-			LastUsedDate: snap.LastUsedAt,
-			Name:         snapInstName,
-			Profiles:     profiles,
-			Stateful:     snap.Stateful,
-		}, true)
-		if err != nil {
-			return fmt.Errorf("Failed creating instance snapshot record %q: %w", snap.Name, err)
-		}
+	var profiles []api.Profile
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		profiles, err = tx.GetProfiles(ctx, projectName, snap.Profiles)
 
-		revert.Add(cleanup)
-		defer snapInstOp.Done(err)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading profiles for instance snapshot %q: %w", snapInstName, err)
+	}
 
-		// Recreate missing mountpoints and symlinks.
-		volStorageName := project.Instance(projectName, snapInstName)
-		snapshotMountPoint := storageDrivers.GetVolumeMountPath(instancePoolName, instanceVolType, volStorageName)
-		snapshotPath := storagePools.InstancePath(instanceType, projectName, backupConf.Instance.Name, true)
-		snapshotTargetPath := storageDrivers.GetVolumeSnapshotDir(instancePoolName, instanceVolType, volStorageName)
+	// Add root device if needed.
+	if snap.Devices == nil {
+		snap.Devices = make(map[string]map[string]string, 0)
+	}
 
-		err = storagePools.CreateSnapshotMountpoint(snapshotMountPoint, snapshotTargetPath, snapshotPath)
-		if err != nil {
-			return err
-		}
+	if snap.ExpandedDevices == nil {
+		snap.ExpandedDevices = make(map[string]map[string]string, 0)
+	}
+
+	internalImportRootDevicePopulate(instancePoolName, snap.Devices, snap.ExpandedDevices, profiles)
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	_, snapInstOp, cleanup, err := instance.CreateInternal(ctx, s, db.InstanceArgs{
+		Project:      projectName,
+		Architecture: arch,
+		BaseImage:    baseImage,
+		Config:       snap.Config,
+		CreationDate: snap.CreatedAt,
+		Type:         instanceType,
+		Snapshot:     true,
+		Devices:      deviceConfig.NewDevices(snap.Devices),
+		Ephemeral:    snap.Ephemeral,
+		LastUsedDate: snap.LastUsedAt,
+		Name:         snapInstName,
+		Profiles:     profiles,
+		Stateful:     snap.Stateful,
+	}, true)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating instance snapshot record %q: %w", snap.Name, err)
+	}
+
+	revert.Add(cleanup)
+	defer snapInstOp.Done(err)
+
+	// Recreate missing mountpoints and symlinks.
+	volStorageName := project.Instance(projectName, snapInstName)
+	snapshotMountPoint := storageDrivers.GetVolumeMountPath(instancePoolName, instanceVolType, volStorageName)
+	snapshotPath := storagePools.InstancePath(instanceType, projectName, instName, true)
+	snapshotTargetPath := storageDrivers.GetVolumeSnapshotDir(instancePoolName, instanceVolType, volStorageName)
+
+	err = storagePools.CreateSnapshotMountpoint(snapshotMountPoint, snapshotTargetPath, snapshotPath)
+	if err != nil {
+		return nil, err
 	}
 
 	revert.Success()
-	return nil
+	return cleanup, nil
 }
 
 // internalImportRootDevicePopulate considers the local and expanded devices from backup.yaml as well as the
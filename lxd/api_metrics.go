@@ -288,6 +288,21 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	wg.Wait()
 	close(instMetricsCh)
 
+	// Add the network ACL rule counters gathered on this member.
+	for _, project := range projectsToFetch {
+		aclMetrics, err := networkACLMetrics(r.Context(), s, *project.Project)
+		if err != nil {
+			logger.Warn("Failed getting network ACL metrics", logger.Ctx{"project": *project.Project, "err": err})
+			continue
+		}
+
+		if newMetrics[*project.Project] == nil {
+			newMetrics[*project.Project] = metrics.NewMetricSet(nil)
+		}
+
+		newMetrics[*project.Project].Merge(aclMetrics)
+	}
+
 	// Put the new data in the global cache and in response.
 	metricsCacheLock.Lock()
 
@@ -451,6 +466,20 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 		}
 	}
 
+	// Replicator metrics are cluster wide, so only report them from the leader to avoid duplicates.
+	leaderInfo, err := s.LeaderInfo()
+	if err != nil {
+		logger.Warn("Failed getting leader information", logger.Ctx{"err": err})
+	} else if leaderInfo.Leader {
+		err = replicatorMetrics(ctx, tx, out)
+		if err != nil {
+			logger.Warn("Failed getting replicator metrics", logger.Ctx{"err": err})
+		}
+	}
+
+	// Storage pool health as of the last health check.
+	storagePoolHealthMetrics(out)
+
 	// Daemon uptime
 	out.AddSamples(metrics.UptimeSeconds, metrics.Sample{Value: time.Since(s.StartTime).Seconds()})
 
@@ -237,6 +237,7 @@ func projectUsedByMap(ctx context.Context, tx *sql.Tx, projectName string) (map[
 		entity.TypeStorageVolume,
 		entity.TypeNetwork,
 		entity.TypeNetworkACL,
+		entity.TypeNetworkAddressSet,
 		entity.TypeStorageBucket,
 		entity.TypePlacementGroup,
 		entity.TypeReplicator,
@@ -251,7 +252,7 @@ func projectUsedByMap(ctx context.Context, tx *sql.Tx, projectName string) (map[
 }
 
 // projectUsedBy returns a list of URLs for all instances, images, profiles,
-// storage volumes, storage buckets, networks, acls, placement groups, and replicators that use this project.
+// storage volumes, storage buckets, networks, acls, address sets, placement groups, and replicators that use this project.
 func projectUsedBy(ctx context.Context, tx *db.ClusterTx, project *dbCluster.Project) ([]string, error) {
 	m, err := projectUsedByMap(ctx, tx.Tx(), project.Name)
 	if err != nil {
@@ -1162,6 +1163,8 @@ func doProjectForceDelete(ctx context.Context, clientType request.ClientType, op
 				return 1 // Delete instances first.
 			case entity.TypeProfile:
 				return 2 // Delete profiles after instances to avoid "profile is currently in use" errors.
+			case entity.TypeNetworkAddressSet:
+				return 4 // Delete address sets after the network ACLs referencing them.
 			default:
 				return 3 // Everything else can be deleted in any order.
 			}
@@ -1920,7 +1923,7 @@ func projectValidateConfig(ctx context.Context, s *state.State, config map[strin
 		//  shortdesc: Maximum number of CPUs to use in the project
 		"limits.cpu": validate.Optional(validate.IsUint32),
 		// lxdmeta:generate(entities=project; group=limits; key=limits.disk)
-		// This value is the maximum value of the aggregate disk space used by all instance volumes, custom volumes, and images of the project.
+		// This value is the maximum value of the aggregate disk space used by all instance volumes, custom volumes, storage buckets on local pools, and images of the project.
 		// ---
 		//  type: string
 		//  shortdesc: Maximum disk space used by the project
@@ -2215,8 +2218,8 @@ func projectValidateConfig(ctx context.Context, s *state.State, config map[strin
 		for _, poolName := range pools {
 			// lxdmeta:generate(entities=project; group=limits; key=limits.disk.pool.POOL_NAME)
 			// This value is the maximum value of the aggregate disk
-			// space used by all instance volumes, custom volumes, and images of the
-			// project on this specific storage pool.
+			// space used by all instance volumes, custom volumes, storage buckets,
+			// and images of the project on this specific storage pool.
 			//
 			// When set to 0, the pool is excluded from storage pool list for
 			// the project.
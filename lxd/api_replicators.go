@@ -8,7 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -19,12 +22,17 @@ import (
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/device/filters"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/metrics"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
 	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
@@ -369,6 +377,29 @@ func replicatorValidateConfig(ctx context.Context, s *state.State, config map[st
 		//  shortdesc: Cron expression for the replication schedule.
 		//  scope: global
 		"schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=snapshots.disk_volumes_mode)
+		// Set to `all-exclusive` to include the custom volumes attached to each instance in the replication
+		// snapshot and replicate them alongside the instance. The snapshot of the root disk and the attached
+		// volumes is taken in a single crash-consistent step, so running instances can be replicated without
+		// being stopped. This requires `features.storage.volumes` to be enabled on the project.
+		// ---
+		//  type: string
+		//  defaultdesc: `root`
+		//  shortdesc: Which instance disk volumes to snapshot and replicate
+		//  scope: global
+		"snapshots.disk_volumes_mode": validate.Optional(validate.IsOneOf(api.DiskVolumesModeRoot, api.DiskVolumesModeAllExclusive)),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=snapshots.retention)
+		// Number of snapshots created by the replicator to keep for each instance, including the snapshot
+		// of the current run. Older replication snapshots are deleted from the source before each run, and
+		// the refresh then removes them from the target cluster. Set to `0` to keep all snapshots.
+		// ---
+		//  type: integer
+		//  defaultdesc: `0`
+		//  shortdesc: Number of replication snapshots to keep per instance
+		//  scope: global
+		"snapshots.retention": validate.Optional(validate.IsUint32),
 	}
 
 	for k, v := range config {
@@ -669,7 +700,7 @@ func replicatorStatePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Replicator %q has no cluster link configured", name))
 	}
 
-	opArgs, err := prepareReplicatorRunOperation(r.Context(), s, projectName, name, clusterLinkName, restore, dbReplicator.Row.ID)
+	opArgs, err := prepareReplicatorRunOperation(r.Context(), s, projectName, name, apiReplicator.Config, restore, dbReplicator.Row.ID)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -958,16 +989,39 @@ func replicatorStateGet(d *Daemon, r *http.Request) response.Response {
 	}
 
 	name := r.PathValue("name")
-	var status string
+	replicatorState := api.ReplicatorState{}
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		dbReplicator, err := dbCluster.GetReplicator(ctx, tx.Tx(), name, projectName)
 		if err != nil {
 			return err
 		}
 
-		status = api.ReplicatorStatusPending
+		replicatorState.Status = api.ReplicatorStatusPending
 		if dbReplicator.Row.LastRunStatus != "" {
-			status = dbReplicator.Row.LastRunStatus
+			replicatorState.Status = dbReplicator.Row.LastRunStatus
+		}
+
+		instNames, err := tx.GetInstanceNames(ctx, projectName)
+		if err != nil {
+			return fmt.Errorf("Failed loading instance names: %w", err)
+		}
+
+		runs, runInstances, err := dbCluster.GetReplicatorRuns(ctx, tx.Tx(), dbReplicator.Row.ID)
+		if err != nil {
+			return err
+		}
+
+		snapshots, err := dbCluster.GetInstanceSnapshots(ctx, tx.Tx(), dbCluster.InstanceSnapshotFilter{Project: &projectName})
+		if err != nil {
+			return fmt.Errorf("Failed loading instance snapshots: %w", err)
+		}
+
+		replicationSnapshots := replicatorReplicationSnapshots(runs, runInstances, snapshots)
+		replicatorState.Instances = replicatorInstancesState(instNames, runs, runInstances, replicationSnapshots, time.Now())
+
+		replicatorState.Runs = make([]api.ReplicatorRun, 0, len(runs))
+		for _, run := range runs {
+			replicatorState.Runs = append(replicatorState.Runs, run.ToAPI(runInstances[run.ID]))
 		}
 
 		return nil
@@ -976,7 +1030,99 @@ func replicatorStateGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Failed loading replicator state for %q: %w", name, err))
 	}
 
-	return response.SyncResponse(true, api.ReplicatorState{Status: status})
+	return response.SyncResponse(true, replicatorState)
+}
+
+// replicatorInstancesState returns the replication state of the given instances computed from the forward
+// runs of the replicator (newest first) and the replication snapshots still present on each instance.
+// The recovery point of an instance is the start of its most recent successful replication.
+func replicatorInstancesState(instNames []string, runs []dbCluster.ReplicatorRun, runInstances map[int64][]dbCluster.ReplicatorRunInstance, replicationSnapshots map[string][]string, now time.Time) []api.ReplicatorInstanceState {
+	instStates := make([]api.ReplicatorInstanceState, 0, len(instNames))
+	for _, instName := range instNames {
+		instState := api.ReplicatorInstanceState{
+			Name:              instName,
+			RecoveryPointAge:  -1,
+			ReplicationPoints: len(replicationSnapshots[instName]),
+		}
+
+		for _, run := range runs {
+			if run.Restore {
+				continue
+			}
+
+			for _, inst := range runInstances[run.ID] {
+				if inst.InstanceName != instName {
+					continue
+				}
+
+				if instState.LastRunStatus == "" {
+					instState.LastRunStatus = inst.Status
+					instState.LastRunAt = inst.StartedAt
+				}
+
+				if inst.Status == api.ReplicatorStatusCompleted && instState.RecoveryPointAge < 0 {
+					instState.RecoveryPointAt = inst.StartedAt
+					instState.RecoveryPointAge = max(int64(now.Sub(inst.StartedAt).Seconds()), 0)
+				}
+			}
+
+			if instState.RecoveryPointAge >= 0 {
+				break
+			}
+		}
+
+		instStates = append(instStates, instState)
+	}
+
+	slices.SortFunc(instStates, func(a api.ReplicatorInstanceState, b api.ReplicatorInstanceState) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return instStates
+}
+
+// replicatorMetrics adds the transferred bytes of the last run of each replicator and the recovery point age
+// of each replicated instance to the given metric set.
+func replicatorMetrics(ctx context.Context, tx *db.ClusterTx, out *metrics.MetricSet) error {
+	replicators, _, err := dbCluster.GetReplicatorsAndURLs(ctx, tx.Tx(), nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed loading replicators: %w", err)
+	}
+
+	now := time.Now()
+	for _, replicator := range replicators {
+		runs, runInstances, err := dbCluster.GetReplicatorRuns(ctx, tx.Tx(), replicator.Row.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(runs) > 0 {
+			lastRun := runs[0].ToAPI(runInstances[runs[0].ID])
+			out.AddSamples(metrics.ReplicatorLastRunTransferredBytes, metrics.Sample{
+				Labels: map[string]string{"project": replicator.ProjectName, "name": replicator.Row.Name},
+				Value:  float64(lastRun.BytesTransferred),
+			})
+		}
+
+		instNames, err := tx.GetInstanceNames(ctx, replicator.ProjectName)
+		if err != nil {
+			return fmt.Errorf("Failed loading instance names: %w", err)
+		}
+
+		for _, instState := range replicatorInstancesState(instNames, runs, runInstances, nil, now) {
+			// Instances that were never replicated have no recovery point.
+			if instState.RecoveryPointAge < 0 {
+				continue
+			}
+
+			out.AddSamples(metrics.ReplicatorRecoveryPointAgeSeconds, metrics.Sample{
+				Labels: map[string]string{"project": replicator.ProjectName, "name": replicator.Row.Name, "instance": instState.Name},
+				Value:  float64(instState.RecoveryPointAge),
+			})
+		}
+	}
+
+	return nil
 }
 
 // runScheduledReplicatorsTask returns a background task that checks replicator schedules every minute
@@ -1005,13 +1151,20 @@ func runScheduledReplicatorsTask(stateFunc func() *state.State) (task.Func, task
 }
 
 // prepareReplicatorRunOperation builds the operation used to run a replicator.
-func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectName string, name string, clusterLinkName string, restore bool, replicatorID int64) (operations.OperationArgs, error) {
+func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectName string, name string, config map[string]string, restore bool, replicatorID int64) (operations.OperationArgs, error) {
+	clusterLinkName := config["cluster"]
+	diskVolumesMode := config["snapshots.disk_volumes_mode"]
+	if diskVolumesMode == "" {
+		diskVolumesMode = api.DiskVolumesModeRoot
+	}
+
 	// Load all DB state in a single transaction before any network I/O.
 	var clusterLink *api.ClusterLink
 	var targetCert *x509.Certificate
 	var sourceProject *api.Project
 	var allInsts []instance.Instance
 	var nodeAddressByName map[string]string
+	var replicationSnapshots map[string][]string
 	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 		_, clusterLink, targetCert, err = lxdCluster.LoadClusterLinkAndCert(ctx, tx.Tx(), clusterLinkName)
@@ -1056,12 +1209,26 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			nodeAddressByName[node.Name] = node.Address
 		}
 
+		// Load the replication snapshots still present on each instance for retention.
+		replicationSnapshots, err = replicatorLoadReplicationSnapshots(ctx, tx, projectName, replicatorID)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
 		return operations.OperationArgs{}, fmt.Errorf("Failed loading replicator state: %w", err)
 	}
 
+	retention := 0
+	if config["snapshots.retention"] != "" {
+		retention, err = strconv.Atoi(config["snapshots.retention"])
+		if err != nil {
+			return operations.OperationArgs{}, fmt.Errorf("Invalid replicator configuration key %q: %w", "snapshots.retention", err)
+		}
+	}
+
 	clusterCert := s.Endpoints.NetworkCert()
 
 	connArgs := lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert)
@@ -1082,6 +1249,12 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 		return operations.OperationArgs{}, api.StatusErrorf(http.StatusBadRequest, "%s", err)
 	}
 
+	// Attached custom volumes can only be replicated alongside their instances when they belong to the
+	// replicated project, otherwise they would be written into another project on the target cluster.
+	if !restore && diskVolumesMode == api.DiskVolumesModeAllExclusive && shared.IsFalse(sourceProject.Config["features.storage.volumes"]) {
+		return operations.OperationArgs{}, api.StatusErrorf(http.StatusBadRequest, "Replicator configuration key %q cannot be %q when %q is disabled on project %q", "snapshots.disk_volumes_mode", api.DiskVolumesModeAllExclusive, "features.storage.volumes", projectName)
+	}
+
 	targetCertPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: targetCert.Raw}))
 
 	// In restore mode, all project instances across all cluster members must be stopped
@@ -1115,6 +1288,72 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 	replicatorURL := entity.ReplicatorURL(projectName, name)
 	projectURL := entity.ProjectURL(projectName)
 
+	// Collect the result of each instance so that the run can be recorded in the replicator history.
+	startedAt := time.Now()
+	var resultsMu sync.Mutex
+	var results []dbCluster.ReplicatorRunInstance
+
+	recordResult := func(instName string, f func(ctx context.Context, op *operations.Operation) (string, int64, error)) func(ctx context.Context, op *operations.Operation) error {
+		return func(ctx context.Context, op *operations.Operation) error {
+			instStartedAt := time.Now()
+			snapName, bytesSent, err := f(ctx, op)
+
+			result := dbCluster.ReplicatorRunInstance{
+				InstanceName:     instName,
+				Status:           api.ReplicatorStatusCompleted,
+				SnapshotName:     snapName,
+				StartedAt:        instStartedAt,
+				FinishedAt:       time.Now(),
+				BytesTransferred: bytesSent,
+			}
+
+			if err != nil {
+				result.Status = api.ReplicatorStatusFailed
+				result.Error = err.Error()
+			}
+
+			resultsMu.Lock()
+			results = append(results, result)
+			resultsMu.Unlock()
+
+			return err
+		}
+	}
+
+	runHook := func(_ context.Context, op *operations.Operation) error {
+		runStatus := api.ReplicatorStatusCompleted
+		for _, child := range op.Children() {
+			if child.Status() != api.Success {
+				runStatus = api.ReplicatorStatusFailed
+				break
+			}
+		}
+
+		resultsMu.Lock()
+		runInstances := slices.Clone(results)
+		resultsMu.Unlock()
+
+		run := dbCluster.ReplicatorRun{
+			ReplicatorID: replicatorID,
+			Restore:      restore,
+			StartedAt:    startedAt,
+			FinishedAt:   time.Now(),
+			Status:       runStatus,
+		}
+
+		// Use a fresh context so the status write always completes, even if the operation context was cancelled.
+		// Only the status is updated here; last_run_date was already set when the operation started.
+		return s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			err := dbCluster.UpdateReplicatorLastRunStatus(ctx, tx.Tx(), replicatorID, runStatus)
+			if err != nil {
+				return err
+			}
+
+			_, err = dbCluster.CreateReplicatorRun(ctx, tx.Tx(), run, runInstances)
+			return err
+		})
+	}
+
 	// Forward replication: iterate over all loaded instances directly.
 	if !restore {
 		childArgs := make([]*operations.OperationArgs, 0, len(allInsts))
@@ -1122,16 +1361,16 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 		for _, inst := range allInsts {
 			memberAddress := nodeAddressByName[inst.Location()]
 
-			copyFunc := func(ctx context.Context, op *operations.Operation) error {
+			copyFunc := recordResult(inst.Name(), func(ctx context.Context, op *operations.Operation) (string, int64, error) {
 				dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
 				if err != nil {
-					return fmt.Errorf("Failed connecting to target cluster: %w", err)
+					return "", 0, fmt.Errorf("Failed connecting to target cluster: %w", err)
 				}
 
 				dstClient = dstClient.UseProject(projectName)
 
-				return replicateInstance(ctx, s, op, inst, memberAddress, dstClient, targetCertPEM)
-			}
+				return replicateInstance(ctx, s, op, inst, memberAddress, dstClient, targetCertPEM, diskVolumesMode, replicationSnapshots[inst.Name()], retention)
+			})
 
 			childArgs = append(childArgs, &operations.OperationArgs{
 				ProjectName: projectName,
@@ -1152,21 +1391,7 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			Class:             operationtype.OperationClassTask,
 			ConflictReference: replicatorURL.String(), // Prevents concurrent runs; paired with ConflictActionFail on the operation type to enforce cluster-wide exclusivity.
 			Children:          childArgs,
-			RunHook: func(_ context.Context, op *operations.Operation) error {
-				runStatus := api.ReplicatorStatusCompleted
-				for _, child := range op.Children() {
-					if child.Status() != api.Success {
-						runStatus = api.ReplicatorStatusFailed
-						break
-					}
-				}
-
-				// Use a fresh context so the status write always completes, even if the operation context was cancelled.
-				// Only the status is updated here; last_run_date was already set when the operation started.
-				return s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
-					return dbCluster.UpdateReplicatorLastRunStatus(ctx, tx.Tx(), replicatorID, runStatus)
-				})
-			},
+			RunHook:           runHook,
 		}, nil
 	}
 
@@ -1178,7 +1403,7 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 	localCertPEM := string(clusterCert.PublicKey())
 
 	for _, instName := range iterNames {
-		copyFunc := func(ctx context.Context, op *operations.Operation) error {
+		restoreFunc := func(ctx context.Context, op *operations.Operation) error {
 			dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
 			if err != nil {
 				return fmt.Errorf("Failed connecting to target cluster: %w", err)
@@ -1389,6 +1614,10 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			return nil
 		}
 
+		copyFunc := recordResult(instName, func(ctx context.Context, op *operations.Operation) (string, int64, error) {
+			return "", 0, restoreFunc(ctx, op)
+		})
+
 		childArgs = append(childArgs, &operations.OperationArgs{
 			ProjectName: projectName,
 			EntityURL:   projectURL,
@@ -1408,30 +1637,23 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 		Class:             operationtype.OperationClassTask,
 		ConflictReference: replicatorURL.String(), // Prevents concurrent runs; paired with ConflictActionFail on the operation type to enforce cluster-wide exclusivity.
 		Children:          childArgs,
-		RunHook: func(_ context.Context, op *operations.Operation) error {
-			runStatus := api.ReplicatorStatusCompleted
-			for _, child := range op.Children() {
-				if child.Status() != api.Success {
-					runStatus = api.ReplicatorStatusFailed
-					break
-				}
-			}
-
-			// Use a fresh context so the status write always completes, even if the operation context was cancelled.
-			// Only the status is updated here; last_run_date was already set when the operation started.
-			return s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
-				return dbCluster.UpdateReplicatorLastRunStatus(ctx, tx.Tx(), replicatorID, runStatus)
-			})
-		},
+		RunHook:           runHook,
 	}, nil
 }
 
+// replicatorInstanceIsRunning returns true if the instance was last recorded as running.
+// It relies on the volatile.last_state.power config key from the database, so it is also
+// accurate for instances hosted on other cluster members.
+func replicatorInstanceIsRunning(inst instance.Instance) bool {
+	return inst.LocalConfig()["volatile.last_state.power"] == instance.PowerStateRunning
+}
+
 // replicatorCheckInstancesStopped verifies that all project instances across all
 // cluster members are stopped before a restore operation. It checks the
 // volatile.last_state.power config key from the database for all instances.
 func replicatorCheckInstancesStopped(allInsts []instance.Instance) error {
 	for _, inst := range allInsts {
-		if inst.LocalConfig()["volatile.last_state.power"] == instance.PowerStateRunning {
+		if replicatorInstanceIsRunning(inst) {
 			return fmt.Errorf("Instance %q is running, stop all project instances before restoring", inst.Name())
 		}
 	}
@@ -1439,50 +1661,195 @@ func replicatorCheckInstancesStopped(allInsts []instance.Instance) error {
 	return nil
 }
 
+// replicatorAttachedVolumes returns the pool and name of each custom volume referenced by the
+// given devices, sorted for a stable replication order. Volumes attached more than once are
+// only returned once.
+func replicatorAttachedVolumes(devices deviceConfig.Devices) [][2]string {
+	var volumes [][2]string
+	for _, dev := range devices.Filter(filters.IsCustomVolumeDisk) {
+		volName, _, _ := api.GetParentAndSnapshotName(dev["source"])
+		volume := [2]string{dev["pool"], volName}
+		if !slices.Contains(volumes, volume) {
+			volumes = append(volumes, volume)
+		}
+	}
+
+	slices.SortFunc(volumes, func(a [2]string, b [2]string) int {
+		return strings.Compare(a[0]+"/"+a[1], b[0]+"/"+b[1])
+	})
+
+	return volumes
+}
+
+// replicatorLoadReplicationSnapshots returns, for each instance of the project, the names of the snapshots
+// created by successful runs of the replicator that still exist, newest first.
+func replicatorLoadReplicationSnapshots(ctx context.Context, tx *db.ClusterTx, projectName string, replicatorID int64) (map[string][]string, error) {
+	runs, runInstances, err := dbCluster.GetReplicatorRuns(ctx, tx.Tx(), replicatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := dbCluster.GetInstanceSnapshots(ctx, tx.Tx(), dbCluster.InstanceSnapshotFilter{Project: &projectName})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instance snapshots: %w", err)
+	}
+
+	return replicatorReplicationSnapshots(runs, runInstances, snapshots), nil
+}
+
+// replicatorReplicationSnapshots returns, for each instance, the names of the snapshots recorded by
+// successful replicator runs that are still present in snapshots, newest first. The runs must be
+// ordered newest first.
+func replicatorReplicationSnapshots(runs []dbCluster.ReplicatorRun, runInstances map[int64][]dbCluster.ReplicatorRunInstance, snapshots []dbCluster.InstanceSnapshot) map[string][]string {
+	existing := make(map[string]bool, len(snapshots))
+	for _, snap := range snapshots {
+		existing[snap.Instance+shared.SnapshotDelimiter+snap.Name] = true
+	}
+
+	replicationSnapshots := make(map[string][]string)
+	for _, run := range runs {
+		for _, inst := range runInstances[run.ID] {
+			if inst.Status != api.ReplicatorStatusCompleted || inst.SnapshotName == "" {
+				continue
+			}
+
+			if !existing[inst.InstanceName+shared.SnapshotDelimiter+inst.SnapshotName] || slices.Contains(replicationSnapshots[inst.InstanceName], inst.SnapshotName) {
+				continue
+			}
+
+			replicationSnapshots[inst.InstanceName] = append(replicationSnapshots[inst.InstanceName], inst.SnapshotName)
+		}
+	}
+
+	return replicationSnapshots
+}
+
+// replicatorExpiredSnapshots returns the replication snapshots (newest first) that exceed the given
+// retention. When a new replication snapshot is about to be created it counts towards the retention.
+// A retention of 0 keeps all snapshots.
+func replicatorExpiredSnapshots(replicationSnapshots []string, retention int, createSnapshot bool) []string {
+	if retention <= 0 {
+		return nil
+	}
+
+	keep := retention
+	if createSnapshot {
+		keep--
+	}
+
+	if len(replicationSnapshots) <= keep {
+		return nil
+	}
+
+	return replicationSnapshots[keep:]
+}
+
 // replicateInstance handles forward replication of a single instance to the
 // destination cluster. It handles both instances on the local cluster member
 // and instances on other cluster members.
-func replicateInstance(ctx context.Context, s *state.State, op *operations.Operation, inst instance.Instance, memberAddress string, dstClient lxd.InstanceServer, targetCertPEM string) error {
+//
+// Running instances are replicated without being stopped: the replication snapshot
+// is the consistent point in time shipped to the destination, and the refresh only
+// transfers the snapshots the destination does not already have. When diskVolumesMode
+// is [api.DiskVolumesModeAllExclusive], the custom volumes attached to the instance are
+// included in the same snapshot and replicated before the instance itself.
+//
+// Before replicating, the replication snapshots (newest first) beyond the retention limit are deleted
+// from the source so that the refresh removes them from the destination too. It returns the name of
+// the snapshot created for this run (if any) and the number of bytes sent to the destination.
+func replicateInstance(ctx context.Context, s *state.State, op *operations.Operation, inst instance.Instance, memberAddress string, dstClient lxd.InstanceServer, targetCertPEM string, diskVolumesMode string, replicationSnapshots []string, retention int) (string, int64, error) {
 	instName := inst.Name()
 	projectName := inst.Project().Name
 	// Snapshotting is unconditional; the only exception is when the instance already has a
 	// snapshot schedule defined, since scheduled snapshots provide point-in-time history so
-	// an extra one here would be redundant.
-	createSnapshot := inst.ExpandedConfig()["snapshots.schedule"] == ""
+	// an extra one here would be redundant. Scheduled snapshots only cover the root disk, so
+	// a snapshot is always taken when attached volumes are replicated too.
+	createSnapshot := inst.ExpandedConfig()["snapshots.schedule"] == "" || diskVolumesMode == api.DiskVolumesModeAllExclusive
+
+	// A running instance is not frozen for the duration of the transfer. Its consistent state
+	// is captured by the replication snapshot, so the live volume is sent as a best effort copy.
+	allowInconsistent := replicatorInstanceIsRunning(inst)
+
+	var attachedVolumes [][2]string
+	if diskVolumesMode == api.DiskVolumesModeAllExclusive {
+		attachedVolumes = replicatorAttachedVolumes(inst.ExpandedDevices())
+	}
+
+	// Generate the snapshot name up front as it is recorded in the run history.
+	var snapName string
+	if createSnapshot {
+		var err error
+		snapName, err = instance.NextSnapshotName(s, inst, "snap%d")
+		if err != nil {
+			return "", 0, fmt.Errorf("Failed generating snapshot name for instance %q: %w", instName, err)
+		}
+	}
+
+	expiredSnapshots := replicatorExpiredSnapshots(replicationSnapshots, retention, createSnapshot)
+
+	var bytesSent int64
 
 	// Instance on another cluster member: connect to the hosting cluster member and
 	// drive the snapshot (if needed) and push migration through its API so the
 	// migration source has direct access to the instance's storage.
 	if inst.Location() != s.ServerName {
 		if memberAddress == "" {
-			return fmt.Errorf("Failed resolving cluster member address for instance %q", instName)
+			return "", 0, fmt.Errorf("Failed resolving cluster member address for instance %q", instName)
 		}
 
 		// Connect to the hosting cluster member.
 		memberClient, err := lxdCluster.Connect(ctx, memberAddress, s.Endpoints.NetworkCert(), s.ServerCert(), false)
 		if err != nil {
-			return fmt.Errorf("Failed connecting to hosting cluster member for instance %q: %w", instName, err)
+			return "", 0, fmt.Errorf("Failed connecting to hosting cluster member for instance %q: %w", instName, err)
 		}
 
 		memberClient = memberClient.UseProject(projectName)
 
+		// Delete the replication snapshots that are beyond the retention limit.
+		for _, expiredSnapName := range expiredSnapshots {
+			deleteOp, err := memberClient.DeleteInstanceSnapshot(instName, expiredSnapName, diskVolumesMode)
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					continue
+				}
+
+				return "", 0, fmt.Errorf("Failed deleting expired snapshot %q of instance %q on hosting cluster member: %w", expiredSnapName, instName, err)
+			}
+
+			err = deleteOp.Wait()
+			if err != nil {
+				return "", 0, fmt.Errorf("Failed waiting for deletion of expired snapshot %q of instance %q on hosting cluster member: %w", expiredSnapName, instName, err)
+			}
+		}
+
 		// Create a snapshot on the hosting cluster member if needed.
 		if createSnapshot {
-			snapOp, err := memberClient.CreateInstanceSnapshot(instName, api.InstanceSnapshotsPost{})
+			snapOp, err := memberClient.CreateInstanceSnapshot(instName, api.InstanceSnapshotsPost{Name: snapName, DiskVolumesMode: diskVolumesMode})
 			if err != nil {
-				return fmt.Errorf("Failed creating snapshot of instance %q on hosting cluster member: %w", instName, err)
+				return "", 0, fmt.Errorf("Failed creating snapshot of instance %q on hosting cluster member: %w", instName, err)
 			}
 
 			err = snapOp.Wait()
 			if err != nil {
-				return fmt.Errorf("Failed waiting for snapshot of instance %q on hosting cluster member: %w", instName, err)
+				return "", 0, fmt.Errorf("Failed waiting for snapshot of instance %q on hosting cluster member: %w", instName, err)
 			}
 		}
 
+		// Replicate the attached volumes first so that the instance devices referencing
+		// them are valid on the destination.
+		for _, volume := range attachedVolumes {
+			volBytesSent, err := replicateCustomVolume(ctx, s, op, projectName, volume[0], volume[1], memberClient.UseTarget(inst.Location()), dstClient, targetCertPEM)
+			if err != nil {
+				return "", 0, fmt.Errorf("Failed replicating volume %q attached to instance %q: %w", volume[1], instName, err)
+			}
+
+			bytesSent += volBytesSent
+		}
+
 		// Get instance metadata from the hosting cluster member.
 		srcInstInfo, _, err := memberClient.GetInstance(instName)
 		if err != nil {
-			return fmt.Errorf("Failed getting instance %q from hosting cluster member: %w", instName, err)
+			return "", 0, fmt.Errorf("Failed getting instance %q from hosting cluster member: %w", instName, err)
 		}
 
 		// Set up a push-mode migration sink on the destination.
@@ -1497,7 +1864,7 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 			},
 		})
 		if err != nil {
-			return fmt.Errorf("Failed requesting instance create on destination for %q: %w", instName, err)
+			return "", 0, fmt.Errorf("Failed requesting instance create on destination for %q: %w", instName, err)
 		}
 
 		destOpCancelled := false
@@ -1510,12 +1877,13 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 		destOpAPI := destOp.Get()
 		destSecrets, err := destOpAPI.WebsocketSecrets()
 		if err != nil {
-			return fmt.Errorf("Failed getting websocket secrets from destination for instance %q: %w", instName, err)
+			return "", 0, fmt.Errorf("Failed getting websocket secrets from destination for instance %q: %w", instName, err)
 		}
 
 		// Tell the hosting cluster member to push-migrate the instance to the destination.
 		srcMigrateOp, err := memberClient.MigrateInstance(instName, api.InstancePost{
-			Migration: true,
+			Migration:         true,
+			AllowInconsistent: allowInconsistent,
 			Target: &api.InstancePostTarget{
 				Operation:   destOp.URL().String(),
 				Websockets:  destSecrets,
@@ -1523,40 +1891,69 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 			},
 		})
 		if err != nil {
-			return fmt.Errorf("Failed starting push migration for instance %q: %w", instName, err)
+			return "", 0, fmt.Errorf("Failed starting push migration for instance %q: %w", instName, err)
 		}
 
 		err = srcMigrateOp.Wait()
 		if err != nil {
-			return fmt.Errorf("Replication of instance %q failed on hosting cluster member: %w", instName, err)
+			return "", 0, fmt.Errorf("Replication of instance %q failed on hosting cluster member: %w", instName, err)
 		}
 
 		destOpCancelled = true
+		bytesSent += replicatorOperationBytesSent(srcMigrateOp.Get())
 
-		return destOp.Wait()
+		err = destOp.Wait()
+		if err != nil {
+			return "", 0, err
+		}
+
+		return snapName, bytesSent, nil
 	}
 
 	// Local instance: handle replication directly.
+	// Delete the replication snapshots that are beyond the retention limit.
+	for _, expiredSnapName := range expiredSnapshots {
+		snapInst, err := instance.LoadByProjectAndName(s, projectName, instName+shared.SnapshotDelimiter+expiredSnapName)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				continue
+			}
+
+			return "", 0, fmt.Errorf("Failed loading expired snapshot %q of instance %q: %w", expiredSnapName, instName, err)
+		}
+
+		err = snapInst.Delete(ctx, false, diskVolumesMode, op)
+		if err != nil {
+			return "", 0, fmt.Errorf("Failed deleting expired snapshot %q of instance %q: %w", expiredSnapName, instName, err)
+		}
+	}
+
 	if createSnapshot {
-		snapName, err := instance.NextSnapshotName(s, inst, "snap%d")
+		err := inst.Snapshot(ctx, snapName, nil, false, diskVolumesMode, nil)
 		if err != nil {
-			return fmt.Errorf("Failed generating snapshot name for instance %q: %w", instName, err)
+			return "", 0, fmt.Errorf("Failed creating snapshot of instance %q: %w", instName, err)
 		}
+	}
 
-		err = inst.Snapshot(ctx, snapName, nil, false, api.DiskVolumesModeRoot, nil)
+	// Replicate the attached volumes first so that the instance devices referencing
+	// them are valid on the destination.
+	for _, volume := range attachedVolumes {
+		volBytesSent, err := replicateCustomVolume(ctx, s, op, projectName, volume[0], volume[1], nil, dstClient, targetCertPEM)
 		if err != nil {
-			return fmt.Errorf("Failed creating snapshot of instance %q: %w", instName, err)
+			return "", 0, fmt.Errorf("Failed replicating volume %q attached to instance %q: %w", volume[1], instName, err)
 		}
+
+		bytesSent += volBytesSent
 	}
 
 	srcRenderRes, _, err := inst.Render()
 	if err != nil {
-		return fmt.Errorf("Failed rendering source instance %q: %w", instName, err)
+		return "", 0, fmt.Errorf("Failed rendering source instance %q: %w", instName, err)
 	}
 
 	srcInstInfo, ok := srcRenderRes.(*api.Instance)
 	if !ok {
-		return fmt.Errorf("Unexpected result from source instance render for %q", instName)
+		return "", 0, fmt.Errorf("Unexpected result from source instance render for %q", instName)
 	}
 
 	// Set up a push-mode migration sink on the destination. In push mode the
@@ -1574,7 +1971,7 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 		},
 	})
 	if err != nil {
-		return fmt.Errorf("Failed requesting instance create on destination: %w", err)
+		return "", 0, fmt.Errorf("Failed requesting instance create on destination: %w", err)
 	}
 
 	// Guard against leaving the destination sink operation running if we fail
@@ -1589,7 +1986,7 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 	destOpAPI := destOp.Get()
 	destSecrets, err := destOpAPI.WebsocketSecrets()
 	if err != nil {
-		return fmt.Errorf("Failed getting websocket secrets from destination for instance %q: %w", instName, err)
+		return "", 0, fmt.Errorf("Failed getting websocket secrets from destination for instance %q: %w", instName, err)
 	}
 
 	pushTarget := &api.InstancePostTarget{
@@ -1598,9 +1995,9 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 		Certificate: targetCertPEM,
 	}
 
-	srcMigration, err := newMigrationSource(inst, false, false, false, "", pushTarget)
+	srcMigration, err := newMigrationSource(inst, false, false, allowInconsistent, "", pushTarget)
 	if err != nil {
-		return fmt.Errorf("Failed setting up migration source for instance %q: %w", instName, err)
+		return "", 0, fmt.Errorf("Failed setting up migration source for instance %q: %w", instName, err)
 	}
 
 	migrArgs := operations.OperationArgs{
@@ -1631,17 +2028,175 @@ func replicateInstance(ctx context.Context, s *state.State, op *operations.Opera
 	}
 
 	if err != nil {
-		return err
+		return "", 0, err
 	}
 
 	destOpCancelled = true // source is now connected via websockets; cancel would interrupt an in-flight transfer
 
 	err = srcOp.Wait(context.Background())
 	if err != nil {
-		return fmt.Errorf("Replication of instance %q failed on source: %w", instName, err)
+		return "", 0, fmt.Errorf("Replication of instance %q failed on source: %w", instName, err)
+	}
+
+	bytesSent += srcMigration.bytesSent()
+
+	err = destOp.Wait()
+	if err != nil {
+		return "", 0, err
+	}
+
+	return snapName, bytesSent, nil
+}
+
+// replicateCustomVolume refreshes a single custom volume on the destination cluster from the source
+// volume. Only the snapshots missing on the destination are transferred, along with the current state
+// of the volume. If memberClient is non-nil, the volume is hosted on another cluster member and the
+// push migration is driven through that member's API, otherwise the volume is migrated locally.
+// It returns the number of bytes sent to the destination.
+func replicateCustomVolume(ctx context.Context, s *state.State, op *operations.Operation, projectName string, poolName string, volName string, memberClient lxd.InstanceServer, dstClient lxd.InstanceServer, targetCertPEM string) (int64, error) {
+	var srcVol *api.StorageVolume
+	if memberClient != nil {
+		var err error
+		srcVol, _, err = memberClient.GetStoragePoolVolume(poolName, "custom", volName)
+		if err != nil {
+			return 0, fmt.Errorf("Failed getting volume from hosting cluster member: %w", err)
+		}
+	} else {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			return 0, err
+		}
+
+		dbVol, err := storagePools.VolumeDBGet(pool, projectName, volName, storageDrivers.VolumeTypeCustom)
+		if err != nil {
+			return 0, err
+		}
+
+		srcVol = &dbVol.StorageVolume
+	}
+
+	// Set up a push-mode migration sink on the destination.
+	destOp, err := dstClient.CreateStoragePoolVolume(poolName, api.StorageVolumesPost{
+		Name:             volName,
+		Type:             "custom",
+		ContentType:      srcVol.ContentType,
+		StorageVolumePut: srcVol.Writable(),
+		Source: api.StorageVolumeSource{
+			Type:    api.SourceTypeMigration,
+			Mode:    "push",
+			Refresh: true,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Failed requesting volume create on destination: %w", err)
+	}
+
+	destOpCancelled := false
+	defer func() {
+		if !destOpCancelled {
+			_ = destOp.Cancel()
+		}
+	}()
+
+	destOpAPI := destOp.Get()
+	destSecrets, err := destOpAPI.WebsocketSecrets()
+	if err != nil {
+		return 0, fmt.Errorf("Failed getting websocket secrets from destination: %w", err)
+	}
+
+	pushTarget := &api.StorageVolumePostTarget{
+		Operation:   destOp.URL().String(),
+		Websockets:  destSecrets,
+		Certificate: targetCertPEM,
+	}
+
+	if memberClient != nil {
+		// Tell the hosting cluster member to push-migrate the volume to the destination.
+		srcMigrateOp, err := memberClient.MigrateStoragePoolVolume(poolName, api.StorageVolumePost{
+			Name:      volName,
+			Migration: true,
+			Target:    pushTarget,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("Failed starting push migration: %w", err)
+		}
+
+		destOpCancelled = true
+
+		err = srcMigrateOp.Wait()
+		if err != nil {
+			return 0, fmt.Errorf("Replication failed on hosting cluster member: %w", err)
+		}
+
+		err = destOp.Wait()
+		if err != nil {
+			return 0, err
+		}
+
+		return replicatorOperationBytesSent(srcMigrateOp.Get()), nil
+	}
+
+	srcMigration, err := newStorageMigrationSource(false, pushTarget)
+	if err != nil {
+		return 0, fmt.Errorf("Failed setting up migration source: %w", err)
+	}
+
+	migrArgs := operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", "custom", volName).Project(projectName),
+		Type:        operationtype.VolumeMigrate,
+		Class:       operationtype.OperationClassTask,
+		RunHook: func(ctx context.Context, innerOp *operations.Operation) error {
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				select {
+				case <-done:
+				case <-ctx.Done():
+					srcMigration.disconnect()
+				}
+			}()
+
+			return srcMigration.DoStorage(s, projectName, poolName, volName, innerOp)
+		},
+	}
+
+	var srcOp *operations.Operation
+	if op.Requestor() != nil {
+		srcOp, err = operations.ScheduleUserOperationFromOperation(s, op, migrArgs)
+	} else {
+		srcOp, err = operations.ScheduleServerOperation(s, migrArgs)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	destOpCancelled = true // source is now connected via websockets; cancel would interrupt an in-flight transfer
+
+	err = srcOp.Wait(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("Replication failed on source: %w", err)
+	}
+
+	err = destOp.Wait()
+	if err != nil {
+		return 0, err
+	}
+
+	return srcMigration.bytesSent(), nil
+}
+
+// replicatorOperationBytesSent returns the number of bytes reported as sent in the metadata of a
+// migration operation run on another server, or 0 if it was not reported.
+func replicatorOperationBytesSent(op api.Operation) int64 {
+	// Numbers are decoded as float64 from the JSON operation metadata.
+	bytesSent, ok := op.Metadata[api.MetadataBytesSent].(float64)
+	if !ok {
+		return 0
 	}
 
-	return destOp.Wait()
+	return int64(bytesSent)
 }
 
 // runScheduledReplicators loads all replicators, checks their schedule config key against the current
@@ -1759,7 +2314,7 @@ func triggerScheduledReplicator(ctx context.Context, s *state.State, replicator
 		return fmt.Errorf("Replicator %q has no cluster link configured", replicator.Name)
 	}
 
-	opArgs, err := prepareReplicatorRunOperation(ctx, s, replicator.Project, replicator.Name, clusterLinkName, false, row.Row.ID)
+	opArgs, err := prepareReplicatorRunOperation(ctx, s, replicator.Project, replicator.Name, replicator.Config, false, row.Row.ID)
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/shared/api"
 )
 
 func TestReplicatorIsScheduledNow(t *testing.T) {
@@ -66,3 +70,133 @@ func TestReplicatorIsScheduledNow(t *testing.T) {
 		})
 	}
 }
+
+func TestReplicatorAttachedVolumes(t *testing.T) {
+	tests := []struct {
+		name    string
+		devices deviceConfig.Devices
+		want    [][2]string
+	}{
+		{
+			name: "root disk only",
+			devices: deviceConfig.Devices{
+				"root": {"type": "disk", "path": "/", "pool": "default"},
+			},
+			want: nil,
+		},
+		{
+			name: "filesystem and block volumes",
+			devices: deviceConfig.Devices{
+				"root":  {"type": "disk", "path": "/", "pool": "default"},
+				"data":  {"type": "disk", "path": "/data", "pool": "fast", "source": "data"},
+				"block": {"type": "disk", "pool": "default", "source": "blockvol"},
+			},
+			want: [][2]string{{"default", "blockvol"}, {"fast", "data"}},
+		},
+		{
+			name: "volume attached twice",
+			devices: deviceConfig.Devices{
+				"data1": {"type": "disk", "path": "/data1", "pool": "default", "source": "data"},
+				"data2": {"type": "disk", "path": "/data2", "pool": "default", "source": "data"},
+			},
+			want: [][2]string{{"default", "data"}},
+		},
+		{
+			name: "host path and non-disk devices ignored",
+			devices: deviceConfig.Devices{
+				"host": {"type": "disk", "path": "/mnt", "source": "/srv"},
+				"eth0": {"type": "nic", "network": "lxdbr0"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replicatorAttachedVolumes(tt.devices))
+		})
+	}
+}
+
+func TestReplicatorExpiredSnapshots(t *testing.T) {
+	snapshots := []string{"snap3", "snap2", "snap1"}
+
+	tests := []struct {
+		name           string
+		retention      int
+		createSnapshot bool
+		want           []string
+	}{
+		{name: "retention disabled", retention: 0, createSnapshot: true, want: nil},
+		{name: "retention not reached", retention: 4, createSnapshot: true, want: nil},
+		{name: "new snapshot counts towards retention", retention: 3, createSnapshot: true, want: []string{"snap1"}},
+		{name: "no new snapshot", retention: 3, createSnapshot: false, want: nil},
+		{name: "keep only the new snapshot", retention: 1, createSnapshot: true, want: []string{"snap3", "snap2", "snap1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replicatorExpiredSnapshots(snapshots, tt.retention, tt.createSnapshot))
+		})
+	}
+}
+
+func TestReplicatorInstancesState(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	// Runs are ordered newest first.
+	runs := []dbCluster.ReplicatorRun{
+		{ID: 4, Restore: true, StartedAt: now.Add(-30 * time.Minute)},
+		{ID: 3, StartedAt: now.Add(-time.Hour)},
+		{ID: 2, StartedAt: now.Add(-2 * time.Hour)},
+		{ID: 1, StartedAt: now.Add(-3 * time.Hour)},
+	}
+
+	runInstances := map[int64][]dbCluster.ReplicatorRunInstance{
+		4: {
+			{InstanceName: "c1", Status: api.ReplicatorStatusCompleted, StartedAt: now.Add(-30 * time.Minute)},
+		},
+		3: {
+			{InstanceName: "c1", Status: api.ReplicatorStatusFailed, StartedAt: now.Add(-time.Hour)},
+			{InstanceName: "c2", Status: api.ReplicatorStatusFailed, StartedAt: now.Add(-time.Hour)},
+		},
+		2: {
+			{InstanceName: "c1", Status: api.ReplicatorStatusCompleted, SnapshotName: "snap2", StartedAt: now.Add(-2 * time.Hour)},
+		},
+		1: {
+			{InstanceName: "c1", Status: api.ReplicatorStatusCompleted, SnapshotName: "snap1", StartedAt: now.Add(-3 * time.Hour)},
+			{InstanceName: "c2", Status: api.ReplicatorStatusFailed, StartedAt: now.Add(-3 * time.Hour)},
+		},
+	}
+
+	// The snapshot of the oldest run was deleted.
+	snapshots := []dbCluster.InstanceSnapshot{
+		{Instance: "c1", Name: "snap2"},
+		{Instance: "c1", Name: "manual"},
+	}
+
+	replicationSnapshots := replicatorReplicationSnapshots(runs, runInstances, snapshots)
+	assert.Equal(t, map[string][]string{"c1": {"snap2"}}, replicationSnapshots)
+
+	states := replicatorInstancesState([]string{"c3", "c2", "c1"}, runs, runInstances, replicationSnapshots, now)
+	assert.Equal(t, []api.ReplicatorInstanceState{
+		{
+			Name:              "c1",
+			LastRunStatus:     api.ReplicatorStatusFailed,
+			LastRunAt:         now.Add(-time.Hour),
+			RecoveryPointAt:   now.Add(-2 * time.Hour),
+			RecoveryPointAge:  7200,
+			ReplicationPoints: 1,
+		},
+		{
+			Name:             "c2",
+			LastRunStatus:    api.ReplicatorStatusFailed,
+			LastRunAt:        now.Add(-time.Hour),
+			RecoveryPointAge: -1,
+		},
+		{
+			Name:             "c3",
+			RecoveryPointAge: -1,
+		},
+	}, states)
+}
@@ -3,6 +3,7 @@ package apparmor
 import (
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/canonical/lxd/lxd/sys"
 )
@@ -52,7 +53,7 @@ func NetworkLoad(sysOS *sys.OS, n network) error {
 	}
 
 	// forkdns
-	if n.Config()["bridge.mode"] == "fan" {
+	if slices.Contains([]string{"fan", "wireguard"}, n.Config()["bridge.mode"]) {
 		profile := filepath.Join(aaPath, "profiles", forkdnsProfileFilename(n))
 		content, err := os.ReadFile(profile)
 		if err != nil && !os.IsNotExist(err) {
@@ -90,7 +91,7 @@ func NetworkUnload(sysOS *sys.OS, n network) error {
 	}
 
 	// forkdns
-	if n.Config()["bridge.mode"] == "fan" {
+	if slices.Contains([]string{"fan", "wireguard"}, n.Config()["bridge.mode"]) {
 		err := unloadProfile(sysOS, ForkdnsProfileName(n), forkdnsProfileFilename(n))
 		if err != nil {
 			return err
@@ -107,7 +108,7 @@ func NetworkDelete(sysOS *sys.OS, n network) error {
 		return err
 	}
 
-	if n.Config()["bridge.mode"] == "fan" {
+	if slices.Contains([]string{"fan", "wireguard"}, n.Config()["bridge.mode"]) {
 		err := deleteProfile(sysOS, ForkdnsProfileName(n), forkdnsProfileFilename(n))
 		if err != nil {
 			return err
@@ -10,13 +10,13 @@ import (
 type Entitlement string
 
 const (
-	// EntitlementCanView is the "can_view" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeStorageBucket, entity.TypeStorageVolume.
+	// EntitlementCanView is the "can_view" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkAddressSet, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeStorageBucket, entity.TypeStorageVolume.
 	EntitlementCanView Entitlement = "can_view"
 
-	// EntitlementCanEdit is the "can_edit" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeServer, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
+	// EntitlementCanEdit is the "can_edit" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkAddressSet, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeServer, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
 	EntitlementCanEdit Entitlement = "can_edit"
 
-	// EntitlementCanDelete is the "can_delete" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
+	// EntitlementCanDelete is the "can_delete" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkAddressSet, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
 	EntitlementCanDelete Entitlement = "can_delete"
 
 	// EntitlementAdmin is the "admin" entitlement. It applies to the following entities: entity.TypeServer.
@@ -208,6 +208,21 @@ const (
 	// EntitlementCanDeleteNetworkACLs is the "can_delete_network_acls" entitlement. It applies to the following entities: entity.TypeProject.
 	EntitlementCanDeleteNetworkACLs Entitlement = "can_delete_network_acls"
 
+	// EntitlementNetworkAddressSetManager is the "network_address_set_manager" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementNetworkAddressSetManager Entitlement = "network_address_set_manager"
+
+	// EntitlementCanCreateNetworkAddressSets is the "can_create_network_address_sets" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementCanCreateNetworkAddressSets Entitlement = "can_create_network_address_sets"
+
+	// EntitlementCanViewNetworkAddressSets is the "can_view_network_address_sets" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementCanViewNetworkAddressSets Entitlement = "can_view_network_address_sets"
+
+	// EntitlementCanEditNetworkAddressSets is the "can_edit_network_address_sets" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementCanEditNetworkAddressSets Entitlement = "can_edit_network_address_sets"
+
+	// EntitlementCanDeleteNetworkAddressSets is the "can_delete_network_address_sets" entitlement. It applies to the following entities: entity.TypeProject.
+	EntitlementCanDeleteNetworkAddressSets Entitlement = "can_delete_network_address_sets"
+
 	// EntitlementNetworkZoneManager is the "network_zone_manager" entitlement. It applies to the following entities: entity.TypeProject.
 	EntitlementNetworkZoneManager Entitlement = "network_zone_manager"
 
@@ -422,6 +437,14 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		// Grants permission to view the network ACL.
 		EntitlementCanView,
 	},
+	entity.TypeNetworkAddressSet: {
+		// Grants permission to edit the network address set.
+		EntitlementCanEdit,
+		// Grants permission to delete the network address set.
+		EntitlementCanDelete,
+		// Grants permission to view the network address set.
+		EntitlementCanView,
+	},
 	entity.TypeNetworkZone: {
 		// Grants permission to edit the network zone.
 		EntitlementCanEdit,
@@ -509,6 +532,16 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		EntitlementCanEditNetworkACLs,
 		// Grants permission to delete network ACLs.
 		EntitlementCanDeleteNetworkACLs,
+		// Grants permission to create, view, edit, and delete all network address sets belonging to the project.
+		EntitlementNetworkAddressSetManager,
+		// Grants permission to create network address sets.
+		EntitlementCanCreateNetworkAddressSets,
+		// Grants permission to view network address sets.
+		EntitlementCanViewNetworkAddressSets,
+		// Grants permission to edit network address sets.
+		EntitlementCanEditNetworkAddressSets,
+		// Grants permission to delete network address sets.
+		EntitlementCanDeleteNetworkAddressSets,
 		// Grants permission to create, view, edit, and delete all network zones belonging to the project.
 		EntitlementNetworkZoneManager,
 		// Grants permission to create network zones.
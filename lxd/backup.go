@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -9,6 +10,9 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.yaml.in/yaml/v2"
@@ -18,6 +22,7 @@ import (
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/db/warningtype"
 	"github.com/canonical/lxd/lxd/idmap"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
@@ -25,20 +30,25 @@ import (
 	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/lxd/warnings"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 )
 
 // Create a new backup.
-func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, sourceInst instance.Instance, version uint32, op *operations.Operation) error {
+// If baseSnapshot is set an incremental backup containing only the changes since that snapshot is created.
+func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, sourceInst instance.Instance, baseSnapshot string, version uint32, op *operations.Operation) error {
 	projectName := sourceInst.Project().Name
-	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": sourceInst.Name(), "name": args.Name})
+	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": sourceInst.Name(), "name": args.Name, "baseSnapshot": baseSnapshot})
 	l.Debug("Instance backup started")
 	defer l.Debug("Instance backup finished")
 
@@ -77,31 +87,10 @@ func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, s
 	}
 
 	// Detect compression method.
-	var compress string
 	b.SetCompressionAlgorithm(args.CompressionAlgorithm)
-	if b.CompressionAlgorithm() != "" {
-		compress = b.CompressionAlgorithm()
-	} else {
-		var p *api.Project
-		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			project, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
-			if err != nil {
-				return err
-			}
-
-			p, err = project.ToAPI(ctx, tx.Tx())
-
-			return err
-		})
-		if err != nil {
-			return err
-		}
-
-		if p.Config["backups.compression_algorithm"] != "" {
-			compress = p.Config["backups.compression_algorithm"]
-		} else {
-			compress = s.GlobalConfig.BackupsCompressionAlgorithm()
-		}
+	compress, err := backupCompressionAlgorithm(s, projectName, b.CompressionAlgorithm())
+	if err != nil {
+		return err
 	}
 
 	// Create the target path if needed.
@@ -132,6 +121,58 @@ func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, s
 	defer func() { _ = tarFileWriter.Close() }()
 	revert.Add(func() { _ = os.Remove(target) })
 
+	err = backupWriteTarball(s, sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), baseSnapshot, version, compress, tarFileWriter, op)
+	if err != nil {
+		return err
+	}
+
+	err = tarFileWriter.Close()
+	if err != nil {
+		return fmt.Errorf("Error closing tar file: %w", err)
+	}
+
+	revert.Success()
+	s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupCreated.Event(ctx, args.Name, b.Instance(), nil))
+
+	return nil
+}
+
+// backupCompressionAlgorithm returns the compression algorithm to use for a backup of the project.
+// If no algorithm was requested, the one configured on the project or server is used.
+func backupCompressionAlgorithm(s *state.State, projectName string, compressionAlgorithm string) (string, error) {
+	if compressionAlgorithm != "" {
+		return compressionAlgorithm, nil
+	}
+
+	var p *api.Project
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		project, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		p, err = project.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if p.Config["backups.compression_algorithm"] != "" {
+		return p.Config["backups.compression_algorithm"], nil
+	}
+
+	return s.GlobalConfig.BackupsCompressionAlgorithm(), nil
+}
+
+// backupWriteTarball writes the backup tarball of the instance to the given writer using the given compression.
+// This allows the tarball to be written to any backup target, whether a local file or an object store.
+func backupWriteTarball(s *state.State, sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, baseSnapshot string, version uint32, compress string, target io.WriteCloser, op *operations.Operation) error {
+	l := logger.AddContext(logger.Ctx{"project": sourceInst.Project().Name, "instance": sourceInst.Name()})
+
+	var err error
+
 	// Get IDMap to unshift container as the tarball is created.
 	var idmap *idmap.IdmapSet
 	if sourceInst.Type() == instancetype.Container {
@@ -160,14 +201,14 @@ func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, s
 		l.Debug("Started backup tarball writer")
 		defer l.Debug("Finished backup tarball writer")
 		if compress != "none" {
-			compressErr = compressFile(s.OS, compress, tarPipeReader, writerWrapper(tarFileWriter))
+			compressErr = compressFile(s.OS, compress, tarPipeReader, writerWrapper(target))
 
 			// If a compression error occurred, close the tarPipeWriter to end the export.
 			if compressErr != nil {
 				_ = tarPipeWriter.Close()
 			}
 		} else {
-			_, err = io.Copy(writerWrapper(tarFileWriter), tarPipeReader)
+			_, err = io.Copy(writerWrapper(target), tarPipeReader)
 		}
 
 		resCh <- err
@@ -175,7 +216,7 @@ func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, s
 
 	// Write index file.
 	l.Debug("Adding backup index file")
-	err = backupWriteIndex(sourceInst, pool, b.OptimizedStorage(), !b.InstanceOnly(), version, tarWriter)
+	err = backupWriteIndex(sourceInst, pool, optimized, snapshots, baseSnapshot, version, tarWriter)
 
 	// Check compression errors.
 	if compressErr != nil {
@@ -187,7 +228,7 @@ func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, s
 		return fmt.Errorf("Error writing backup index file: %w", err)
 	}
 
-	err = pool.BackupInstance(sourceInst, tarWriter, b.OptimizedStorage(), !b.InstanceOnly(), version, nil)
+	err = pool.BackupInstance(sourceInst, tarWriter, optimized, snapshots, baseSnapshot, version, nil)
 	if err != nil {
 		return fmt.Errorf("Backup create: %w", err)
 	}
@@ -209,19 +250,11 @@ func backupCreate(ctx context.Context, s *state.State, args db.InstanceBackup, s
 		return fmt.Errorf("Error writing tarball: %w", err)
 	}
 
-	err = tarFileWriter.Close()
-	if err != nil {
-		return fmt.Errorf("Error closing tar file: %w", err)
-	}
-
-	revert.Success()
-	s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupCreated.Event(ctx, args.Name, b.Instance(), nil))
-
 	return nil
 }
 
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
+func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, baseSnapshot string, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
 	driverInfo := pool.Driver().Info()
 
 	// Indicate whether the driver will include a driver-specific optimized header.
@@ -271,6 +304,7 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		OptimizedStorage: &optimized,
 		OptimizedHeader:  &poolDriverOptimizedHeader,
 		Config:           config,
+		BaseSnapshot:     baseSnapshot,
 	}
 
 	if snapshots {
@@ -284,6 +318,14 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 		}
 	}
 
+	// Incremental backups only contain the snapshots more recent than the base snapshot.
+	if baseSnapshot != "" && snapshots {
+		indexInfo.Snapshots, err = backup.IncrementalSnapshots(indexInfo.Snapshots, baseSnapshot)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Convert to YAML.
 	indexData, err := yaml.Marshal(&indexInfo)
 	if err != nil {
@@ -308,6 +350,23 @@ func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, opti
 	return nil
 }
 
+// backupIncrementalBase returns the snapshot an incremental backup following the backup tarball at path is sent from.
+func backupIncrementalBase(s *state.State, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("Failed opening backup tarball %q: %w", path, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	info, err := backup.GetInfo(s, f, path)
+	if err != nil {
+		return "", fmt.Errorf("Failed reading backup tarball %q: %w", path, err)
+	}
+
+	return info.IncrementalBase()
+}
+
 func pruneExpiredBackupsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
 	f := func(ctx context.Context) {
 		s := stateFunc()
@@ -394,8 +453,10 @@ func pruneExpiredInstanceBackups(ctx context.Context, s *state.State) error {
 	return nil
 }
 
-func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string, version uint32) error {
-	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName, "name": args.Name})
+// volumeBackupCreate creates a new custom volume backup.
+// If baseSnapshot is set an incremental backup containing only the changes since that snapshot is created.
+func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string, baseSnapshot string, version uint32) error {
+	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName, "name": args.Name, "baseSnapshot": baseSnapshot})
 	l.Debug("Volume backup started")
 	defer l.Debug("Volume backup finished")
 
@@ -504,7 +565,7 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 
 	// Write index file.
 	l.Debug("Adding backup index file")
-	err = volumeBackupWriteIndex(projectName, volumeName, pool, backupRow.OptimizedStorage, !backupRow.VolumeOnly, version, tarWriter)
+	err = volumeBackupWriteIndex(projectName, volumeName, pool, backupRow.OptimizedStorage, !backupRow.VolumeOnly, baseSnapshot, version, tarWriter)
 
 	// Check compression errors.
 	if compressErr != nil {
@@ -516,7 +577,7 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		return fmt.Errorf("Error writing backup index file: %w", err)
 	}
 
-	err = pool.BackupCustomVolume(projectName, volumeName, tarWriter, backupRow.OptimizedStorage, !backupRow.VolumeOnly, nil)
+	err = pool.BackupCustomVolume(projectName, volumeName, tarWriter, backupRow.OptimizedStorage, !backupRow.VolumeOnly, baseSnapshot, nil)
 	if err != nil {
 		return fmt.Errorf("Backup create: %w", err)
 	}
@@ -548,7 +609,7 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 }
 
 // volumeBackupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
-func volumeBackupWriteIndex(projectName string, volumeName string, pool storagePools.Pool, optimized bool, snapshots bool, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
+func volumeBackupWriteIndex(projectName string, volumeName string, pool storagePools.Pool, optimized bool, snapshots bool, baseSnapshot string, version uint32, tarWriter *instancewriter.InstanceTarWriter) error {
 	driverInfo := pool.Driver().Info()
 	poolName := pool.Name()
 
@@ -582,6 +643,7 @@ func volumeBackupWriteIndex(projectName string, volumeName string, pool storageP
 		OptimizedHeader:  &poolDriverOptimizedHeader,
 		Type:             backupConfig.TypeCustom,
 		Config:           config,
+		BaseSnapshot:     baseSnapshot,
 	}
 
 	if snapshots {
@@ -595,6 +657,14 @@ func volumeBackupWriteIndex(projectName string, volumeName string, pool storageP
 		}
 	}
 
+	// Incremental backups only contain the snapshots more recent than the base snapshot.
+	if baseSnapshot != "" && snapshots {
+		indexInfo.Snapshots, err = backup.IncrementalSnapshots(indexInfo.Snapshots, baseSnapshot)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Convert to YAML.
 	indexData, err := yaml.Marshal(indexInfo)
 	if err != nil {
@@ -665,3 +735,431 @@ func pruneExpiredStorageVolumeBackups(ctx context.Context, s *state.State) error
 
 	return nil
 }
+
+// scheduledBackupPrefix is the name prefix of the backups created by the backup scheduler.
+const scheduledBackupPrefix = "scheduled"
+
+// backupNextName returns the next free backup name made of the prefix followed by an increasing number.
+// The backupNames are the full names of the existing backups of the parent instance or custom volume.
+func backupNextName(parentName string, backupNames []string, prefix string) string {
+	base := parentName + shared.SnapshotDelimiter + prefix
+	backupNo := 0
+
+	// Iterate over previous backups to autoincrement the backup number.
+	for _, backupName := range backupNames {
+		// Ignore backups not containing base.
+		if !strings.HasPrefix(backupName, base) {
+			continue
+		}
+
+		var num int
+		count, err := fmt.Sscanf(backupName[len(base):], "%d", &num)
+		if err != nil || count != 1 {
+			continue
+		}
+
+		if num >= backupNo {
+			backupNo = num + 1
+		}
+	}
+
+	return prefix + strconv.Itoa(backupNo)
+}
+
+// scheduledBackupsToPrune returns the full names of the scheduled backups exceeding the retain count,
+// given the creation date of every backup keyed by full backup name.
+// Backups that were not created by the scheduler are never returned.
+func scheduledBackupsToPrune(backups map[string]time.Time, retain string) []string {
+	keep, err := strconv.Atoi(retain)
+	if err != nil || keep < 1 {
+		return nil
+	}
+
+	scheduled := make([]string, 0, len(backups))
+	for fullName := range backups {
+		_, backupName, _ := api.GetParentAndSnapshotName(fullName)
+
+		num, ok := strings.CutPrefix(backupName, scheduledBackupPrefix)
+		if !ok {
+			continue
+		}
+
+		_, err := strconv.ParseUint(num, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		scheduled = append(scheduled, fullName)
+	}
+
+	if len(scheduled) <= keep {
+		return nil
+	}
+
+	// Sort the most recent backups first.
+	slices.SortFunc(scheduled, func(a string, b string) int {
+		return cmp.Or(backups[b].Compare(backups[a]), strings.Compare(b, a))
+	})
+
+	return scheduled[keep:]
+}
+
+func autoCreateScheduledBackupsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := autoCreateScheduledBackups(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed running scheduled backup task", logger.Ctx{"err": err})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoCreateScheduledBackups creates the backups of the instances and custom volumes whose backup schedule is due.
+func autoCreateScheduledBackups(ctx context.Context, s *state.State) error {
+	var instances []instance.Instance
+	var volumes, remoteVolumes []db.StorageVolumeArgs
+	var memberCount int
+	var onlineMemberIDs []int64
+
+	// Get list of instances on the local member that are due to be backed up.
+	filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q (project %q) for backup task: %w", dbInst.Name, dbInst.Project, err)
+			}
+
+			schedule := inst.ExpandedConfig()["backups.schedule"]
+			if schedule == "" || !snapshotIsScheduledNow(schedule, int64(inst.ID())) {
+				return nil
+			}
+
+			logger.Debug("Scheduling instance backup", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name})
+			instances = append(instances, inst)
+
+			return nil
+		}, filter)
+		if err != nil {
+			return err
+		}
+
+		allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, dbCluster.StoragePoolVolumeTypeCustom, true)
+		if err != nil {
+			return fmt.Errorf("Failed getting volumes for custom volume backup task: %w", err)
+		}
+
+		for _, v := range allVolumes {
+			schedule := v.Config["backups.schedule"]
+			if schedule == "" || !snapshotIsScheduledNow(schedule, v.ID) {
+				continue
+			}
+
+			if v.NodeID < 0 {
+				// Keep a separate list of remote volumes in order to select a member to
+				// perform the backup later.
+				remoteVolumes = append(remoteVolumes, v)
+			} else {
+				logger.Debug("Scheduling local custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+				volumes = append(volumes, v) // Always include local volumes.
+			}
+		}
+
+		if len(remoteVolumes) > 0 {
+			members, err := tx.GetNodes(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed getting cluster members: %w", err)
+			}
+
+			memberCount = len(members)
+
+			for _, member := range members {
+				if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+					continue
+				}
+
+				onlineMemberIDs = append(onlineMemberIDs, member.ID)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting backup schedule info: %w", err)
+	}
+
+	if len(remoteVolumes) > 0 {
+		// Skip backing up remote custom volumes if there are no online members, as we can't be
+		// sure that the cluster isn't partitioned and we may end up creating the backup on
+		// multiple members.
+		if memberCount > 1 && len(onlineMemberIDs) <= 0 {
+			logger.Error("Skipping remote volumes for custom volume backup task due to no online members")
+		} else {
+			localMemberID := s.DB.Cluster.GetNodeID()
+
+			for _, v := range remoteVolumes {
+				// If there are multiple cluster members, a stable random member is chosen
+				// to perform the backup from. This spreads the load across the online cluster members.
+				if memberCount > 1 {
+					selectedMemberID, err := util.GetStableRandomInt64FromList(v.ID, onlineMemberIDs)
+					if err != nil {
+						logger.Error("Failed scheduling remote custom volume backup task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+						continue
+					}
+
+					if localMemberID != selectedMemberID {
+						continue
+					}
+				}
+
+				logger.Debug("Scheduling remote custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+				volumes = append(volumes, v)
+			}
+		}
+	}
+
+	if len(instances) == 0 && len(volumes) == 0 {
+		return nil
+	}
+
+	opRun := func(ctx context.Context, op *operations.Operation) error {
+		return autoCreateBackups(ctx, s, op, instances, volumes)
+	}
+
+	args := operations.OperationArgs{
+		Type:    operationtype.BackupsCreateScheduled,
+		Class:   operationtype.OperationClassTask,
+		RunHook: opRun,
+	}
+
+	logger.Info("Creating scheduled backups")
+	op, err := operations.ScheduleServerOperation(s, args)
+	if err != nil {
+		return fmt.Errorf("Failed creating scheduled backup operation: %w", err)
+	}
+
+	err = op.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed creating scheduled backups: %w", err)
+	}
+
+	logger.Info("Done creating scheduled backups")
+
+	return nil
+}
+
+// autoCreateBackups sequentially creates the scheduled backups of the given instances and custom volumes.
+// A failure to back up one of them doesn't prevent backing up the others, it is instead reported with a warning
+// that is resolved by the next successful scheduled backup.
+func autoCreateBackups(ctx context.Context, s *state.State, op *operations.Operation, instances []instance.Instance, volumes []db.StorageVolumeArgs) error {
+	reportResult := func(projectName string, entityType entity.Type, entityID int, backupErr error) {
+		if backupErr == nil {
+			err := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, projectName, warningtype.ScheduledBackupFailure, entityType, entityID)
+			if err != nil {
+				logger.Warn("Failed resolving scheduled backup failure warning", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, projectName, entityType, entityID, warningtype.ScheduledBackupFailure, backupErr.Error())
+		})
+		if err != nil {
+			logger.Warn("Failed creating scheduled backup failure warning", logger.Ctx{"err": err})
+		}
+	}
+
+	failures := 0
+
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		err = autoCreateInstanceBackup(ctx, s, op, inst)
+		if err != nil {
+			logger.Error("Failed creating scheduled instance backup", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": err})
+			failures++
+		}
+
+		reportResult(inst.Project().Name, entity.TypeInstance, inst.ID(), err)
+	}
+
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		err = autoCreateCustomVolumeBackup(ctx, s, v)
+		if err != nil {
+			logger.Error("Failed creating scheduled custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+			failures++
+		}
+
+		reportResult(v.ProjectName, entity.TypeStorageVolume, int(v.ID), err)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("Failed creating %d out of %d scheduled backups", failures, len(instances)+len(volumes))
+	}
+
+	return nil
+}
+
+// autoCreateInstanceBackup creates a scheduled backup of the instance and then deletes the scheduled backups
+// exceeding the instance's retain count.
+func autoCreateInstanceBackup(ctx context.Context, s *state.State, op *operations.Operation, inst instance.Instance) error {
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return limits.AllowBackupCreation(tx, inst.Project().Name)
+	})
+	if err != nil {
+		return err
+	}
+
+	backups, err := inst.Backups()
+	if err != nil {
+		return fmt.Errorf("Failed loading instance backups: %w", err)
+	}
+
+	backupNames := make([]string, 0, len(backups))
+	for _, b := range backups {
+		backupNames = append(backupNames, b.Name())
+	}
+
+	now := time.Now()
+	expiry, err := shared.GetExpiry(now, inst.ExpandedConfig()["backups.expiry"])
+	if err != nil {
+		return err
+	}
+
+	args := db.InstanceBackup{
+		Name:         inst.Name() + shared.SnapshotDelimiter + backupNextName(inst.Name(), backupNames, scheduledBackupPrefix),
+		InstanceID:   inst.ID(),
+		CreationDate: now,
+		ExpiryDate:   expiry,
+	}
+
+	err = backupCreate(ctx, s, args, inst, "", backupConfig.DefaultMetadataVersion, op)
+	if err != nil {
+		return fmt.Errorf("Failed creating instance backup %q: %w", args.Name, err)
+	}
+
+	backups, err = inst.Backups()
+	if err != nil {
+		return fmt.Errorf("Failed loading instance backups: %w", err)
+	}
+
+	creationDates := make(map[string]time.Time, len(backups))
+	for _, b := range backups {
+		creationDates[b.Name()] = b.Render().CreatedAt
+	}
+
+	prune := scheduledBackupsToPrune(creationDates, inst.ExpandedConfig()["backups.retain"])
+	for i := range backups {
+		if !slices.Contains(prune, backups[i].Name()) {
+			continue
+		}
+
+		err = backups[i].Delete(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed deleting instance backup %q: %w", backups[i].Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// autoCreateCustomVolumeBackup creates a scheduled backup of the custom volume and then deletes the scheduled backups
+// exceeding the volume's retain count.
+func autoCreateCustomVolumeBackup(ctx context.Context, s *state.State, v db.StorageVolumeArgs) error {
+	pool, err := storagePools.LoadByName(s, v.PoolName)
+	if err != nil {
+		return fmt.Errorf("Failed loading storage pool: %w", err)
+	}
+
+	var backups []db.StoragePoolVolumeBackup
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		err := limits.AllowBackupCreation(tx, v.ProjectName)
+		if err != nil {
+			return err
+		}
+
+		backups, err = tx.GetStoragePoolVolumeBackups(ctx, v.ProjectName, v.Name, pool.ID())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	backupNames := make([]string, 0, len(backups))
+	for _, b := range backups {
+		backupNames = append(backupNames, b.Name)
+	}
+
+	now := time.Now()
+	expiry, err := shared.GetExpiry(now, v.Config["backups.expiry"])
+	if err != nil {
+		return err
+	}
+
+	args := db.StoragePoolVolumeBackup{
+		Name:         v.Name + shared.SnapshotDelimiter + backupNextName(v.Name, backupNames, scheduledBackupPrefix),
+		VolumeID:     v.ID,
+		CreationDate: now,
+		ExpiryDate:   expiry,
+	}
+
+	err = volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name, "", backupConfig.DefaultMetadataVersion)
+	if err != nil {
+		return fmt.Errorf("Failed creating custom volume backup %q: %w", args.Name, err)
+	}
+
+	s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupCreated.Event(v.PoolName, dbCluster.StoragePoolVolumeTypeNameCustom, args.Name, v.ProjectName, nil, logger.Ctx{"type": dbCluster.StoragePoolVolumeTypeNameCustom}))
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		backups, err = tx.GetStoragePoolVolumeBackups(ctx, v.ProjectName, v.Name, pool.ID())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading custom volume backups: %w", err)
+	}
+
+	creationDates := make(map[string]time.Time, len(backups))
+	for _, b := range backups {
+		creationDates[b.Name] = b.CreationDate
+	}
+
+	prune := scheduledBackupsToPrune(creationDates, v.Config["backups.retain"])
+	for _, b := range backups {
+		if !slices.Contains(prune, b.Name) {
+			continue
+		}
+
+		volBackup := backup.NewVolumeBackup(s, v.ProjectName, v.PoolName, v.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+		err = volBackup.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed deleting custom volume backup %q: %w", b.Name, err)
+		}
+
+		s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupDeleted.Event(v.PoolName, dbCluster.StoragePoolVolumeTypeNameCustom, b.Name, v.ProjectName, nil, nil))
+	}
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package backup
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 
 	"go.yaml.in/yaml/v2"
 
@@ -37,6 +39,62 @@ type Info struct {
 	OptimizedHeader  *bool          `json:"optimized_header,omitempty" yaml:"optimized_header,omitempty"` // Optional field to handle older optimized backups that don't have this field.
 	Type             config.Type    `json:"type,omitempty" yaml:"type,omitempty"`                         // Type of backup.
 	Config           *config.Config `json:"config,omitempty" yaml:"config,omitempty"`                     // Equivalent of backup.yaml but embedded in index for quick retrieval.
+	BaseSnapshot     string         `json:"base_snapshot,omitempty" yaml:"base_snapshot,omitempty"`       // Snapshot an incremental backup was sent from, empty for full backups.
+}
+
+// IncrementalBase returns the snapshot a subsequent incremental backup of the same volume gets sent from.
+// This is the most recent snapshot included in the backup or, if it contains no snapshots, the snapshot the
+// backup itself was sent from.
+func (b *Info) IncrementalBase() (string, error) {
+	if b.OptimizedStorage == nil || !*b.OptimizedStorage {
+		return "", errors.New("Incremental backups can only follow optimized backups")
+	}
+
+	if len(b.Snapshots) > 0 {
+		return b.Snapshots[len(b.Snapshots)-1], nil
+	}
+
+	if b.BaseSnapshot != "" {
+		return b.BaseSnapshot, nil
+	}
+
+	return "", errors.New("Incremental backups can only follow backups that include snapshots")
+}
+
+// IncrementalSnapshots returns the snapshots included in an incremental backup sent from baseSnapshot.
+// The snapshots must be in age order, oldest first, and only those more recent than the base are returned.
+func IncrementalSnapshots(snapshots []string, baseSnapshot string) ([]string, error) {
+	i := slices.Index(snapshots, baseSnapshot)
+	if i < 0 {
+		return nil, fmt.Errorf("Base snapshot %q of the incremental backup not found", baseSnapshot)
+	}
+
+	return snapshots[i+1:], nil
+}
+
+// CheckIncrementalBase checks that the incremental backup can be applied on top of a volume with the given
+// snapshots (oldest first). The base snapshot must be the most recent snapshot of the volume so that applying
+// the backup doesn't discard any data and the snapshots included in the backup must not exist yet.
+func (b *Info) CheckIncrementalBase(snapshots []string) error {
+	if b.BaseSnapshot == "" {
+		return errors.New("Backup is not an incremental backup")
+	}
+
+	if !slices.Contains(snapshots, b.BaseSnapshot) {
+		return fmt.Errorf("Base snapshot %q of the incremental backup not found, import the backups of the chain in order", b.BaseSnapshot)
+	}
+
+	if snapshots[len(snapshots)-1] != b.BaseSnapshot {
+		return fmt.Errorf("Base snapshot %q of the incremental backup is not the most recent snapshot, the backup chain is broken", b.BaseSnapshot)
+	}
+
+	for _, snapName := range b.Snapshots {
+		if slices.Contains(snapshots, snapName) {
+			return fmt.Errorf("Snapshot %q of the incremental backup already exists", snapName)
+		}
+	}
+
+	return nil
 }
 
 // GetInfo extracts backup information from a given ReadSeeker.
@@ -0,0 +1,139 @@
+package backup
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInfoIncrementalBase(t *testing.T) {
+	optimized := true
+	notOptimized := false
+
+	tests := []struct {
+		name         string
+		info         Info
+		expectedBase string
+		expectErr    bool
+	}{
+		{
+			name:         "Most recent snapshot of a full backup",
+			info:         Info{OptimizedStorage: &optimized, Snapshots: []string{"snap0", "snap1"}},
+			expectedBase: "snap1",
+		},
+		{
+			name:         "Most recent snapshot of an incremental backup",
+			info:         Info{OptimizedStorage: &optimized, Snapshots: []string{"snap2"}, BaseSnapshot: "snap1"},
+			expectedBase: "snap2",
+		},
+		{
+			name:         "Base of an incremental backup without snapshots",
+			info:         Info{OptimizedStorage: &optimized, BaseSnapshot: "snap1"},
+			expectedBase: "snap1",
+		},
+		{
+			name:      "Full backup without snapshots",
+			info:      Info{OptimizedStorage: &optimized},
+			expectErr: true,
+		},
+		{
+			name:      "Non-optimized backup",
+			info:      Info{OptimizedStorage: &notOptimized, Snapshots: []string{"snap0"}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		base, err := test.info.IncrementalBase()
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: Expected an error", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: Unexpected error: %v", test.name, err)
+		}
+
+		if base != test.expectedBase {
+			t.Errorf("%s: Base snapshot does not match: %q != %q", test.name, base, test.expectedBase)
+		}
+	}
+}
+
+func TestInfoCheckIncrementalBase(t *testing.T) {
+	info := Info{BaseSnapshot: "snap1", Snapshots: []string{"snap2", "snap3"}}
+
+	tests := []struct {
+		name      string
+		snapshots []string
+		expectErr bool
+	}{
+		{
+			name:      "Base is the most recent snapshot",
+			snapshots: []string{"snap0", "snap1"},
+		},
+		{
+			name:      "No snapshots",
+			snapshots: nil,
+			expectErr: true,
+		},
+		{
+			name:      "Base missing",
+			snapshots: []string{"snap0"},
+			expectErr: true,
+		},
+		{
+			name:      "Base is not the most recent snapshot",
+			snapshots: []string{"snap1", "snap4"},
+			expectErr: true,
+		},
+		{
+			name:      "Included snapshot already exists",
+			snapshots: []string{"snap2", "snap1"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := info.CheckIncrementalBase(test.snapshots)
+		if test.expectErr && err == nil {
+			t.Errorf("%s: Expected an error", test.name)
+		} else if !test.expectErr && err != nil {
+			t.Errorf("%s: Unexpected error: %v", test.name, err)
+		}
+	}
+
+	full := Info{Snapshots: []string{"snap0"}}
+	if full.CheckIncrementalBase([]string{"snap0"}) == nil {
+		t.Error("Expected an error for a full backup")
+	}
+}
+
+func TestIncrementalSnapshots(t *testing.T) {
+	snapshots := []string{"snap0", "snap1", "snap2"}
+
+	included, err := IncrementalSnapshots(snapshots, "snap0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !slices.Equal(included, []string{"snap1", "snap2"}) {
+		t.Errorf("Included snapshots do not match: %v", included)
+	}
+
+	included, err = IncrementalSnapshots(snapshots, "snap2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(included) != 0 {
+		t.Errorf("Expected no included snapshots: %v", included)
+	}
+
+	_, err = IncrementalSnapshots(snapshots, "snap3")
+	if err == nil {
+		t.Error("Expected an error for a missing base snapshot")
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/storage/s3"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// backupsS3Client returns a client for the S3 endpoint configured for backups.
+func backupsS3Client(s *state.State) (*s3.Client, error) {
+	endpoint, region, accessKey, caCert := s.GlobalConfig.BackupsS3()
+	if endpoint == "" {
+		return nil, errors.New("No S3 endpoint is configured for backups")
+	}
+
+	var secretKey string
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		secretKey, err = dbCluster.GetBackupsS3SecretKey(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.New("No credentials are configured for the S3 endpoint for backups")
+	}
+
+	httpClient, err := util.HTTPClient(caCert, s.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating HTTP client for the S3 endpoint for backups: %w", err)
+	}
+
+	return s3.NewClient(endpoint, region, accessKey, secretKey, httpClient)
+}
+
+// backupsS3ParseURL parses the URL of an object of the S3 endpoint for backups and returns its bucket and key.
+// The credentials of the endpoint are shared by all projects, so the keys of the objects a project can push
+// to or import from must start with the name of the project followed by a slash.
+func backupsS3ParseURL(projectName string, objectURL string) (string, string, error) {
+	bucket, key, err := s3.ParseURL(objectURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix := projectName + "/"
+	if !strings.HasPrefix(key, prefix) || len(key) == len(prefix) {
+		return "", "", fmt.Errorf("Invalid object URL %q: Key must start with %q", objectURL, prefix)
+	}
+
+	// Reject keys that could be normalized to a path outside of the project prefix by the endpoint.
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", "", fmt.Errorf("Invalid object URL %q: Key must not contain empty, %q or %q path segments", objectURL, ".", "..")
+		}
+	}
+
+	return bucket, key, nil
+}
+
+// s3BackupUpload is a backup target streaming the tarball written to it to an object of the S3 endpoint for backups.
+type s3BackupUpload struct {
+	pipeWriter *io.PipeWriter
+	result     chan error
+	info       *s3.ObjectInfo
+
+	closeOnce sync.Once
+	err       error
+}
+
+// newS3BackupUpload starts the upload of a backup tarball to the object with the given key.
+// The upload completes when the returned target is closed.
+func newS3BackupUpload(ctx context.Context, client *s3.Client, bucket string, key string) *s3BackupUpload {
+	pipeReader, pipeWriter := io.Pipe()
+	u := &s3BackupUpload{
+		pipeWriter: pipeWriter,
+		result:     make(chan error, 1),
+	}
+
+	go func() {
+		var err error
+		u.info, err = client.Upload(ctx, bucket, key, pipeReader)
+
+		// Unblock the writer if the upload failed before the whole tarball was read.
+		_ = pipeReader.CloseWithError(err)
+		u.result <- err
+	}()
+
+	return u
+}
+
+// Write implements io.Writer.
+func (u *s3BackupUpload) Write(p []byte) (int, error) {
+	return u.pipeWriter.Write(p)
+}
+
+// Close marks the end of the tarball and waits for the upload to complete.
+func (u *s3BackupUpload) Close() error {
+	u.closeOnce.Do(func() {
+		_ = u.pipeWriter.Close()
+		u.err = <-u.result
+	})
+
+	return u.err
+}
+
+// Abort interrupts the upload, causing the partially uploaded object to be discarded.
+func (u *s3BackupUpload) Abort(err error) {
+	_ = u.pipeWriter.CloseWithError(err)
+	_ = u.Close()
+}
+
+// backupPush creates a backup of the instance and streams it directly to the object at the given URL
+// of the S3 endpoint for backups. Unlike backups stored locally, no backup record is created.
+func backupPush(ctx context.Context, s *state.State, args db.InstanceBackup, sourceInst instance.Instance, objectURL string, baseSnapshot string, version uint32, op *operations.Operation) (*s3.ObjectInfo, error) {
+	projectName := sourceInst.Project().Name
+	l := logger.AddContext(logger.Ctx{"project": projectName, "instance": sourceInst.Name(), "url": objectURL, "baseSnapshot": baseSnapshot})
+	l.Debug("Instance backup push started")
+	defer l.Debug("Instance backup push finished")
+
+	// Get storage pool.
+	pool, err := storagePools.LoadByInstance(s, sourceInst)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	// Ignore requests for optimized backups when pool driver doesn't support it.
+	if args.OptimizedStorage && !pool.Driver().Info().OptimizedBackups {
+		args.OptimizedStorage = false
+	}
+
+	compress, err := backupCompressionAlgorithm(s, projectName, args.CompressionAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, key, err := backupsS3ParseURL(projectName, objectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := backupsS3Client(s)
+	if err != nil {
+		return nil, err
+	}
+
+	upload := newS3BackupUpload(ctx, client, bucket, key)
+
+	err = backupWriteTarball(s, sourceInst, pool, args.OptimizedStorage, !args.InstanceOnly, baseSnapshot, version, compress, upload, op)
+	if err != nil {
+		upload.Abort(err)
+		return nil, err
+	}
+
+	err = upload.Close()
+	if err != nil {
+		return nil, fmt.Errorf("Failed pushing backup to %q: %w", objectURL, err)
+	}
+
+	return upload.info, nil
+}
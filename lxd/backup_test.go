@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupNextName(t *testing.T) {
+	assert.Equal(t, "backup0", backupNextName("c1", nil, "backup"))
+	assert.Equal(t, "backup3", backupNextName("c1", []string{"c1/backup0", "c1/backup2", "c1/foo", "c1/scheduled7"}, "backup"))
+	assert.Equal(t, "scheduled8", backupNextName("c1", []string{"c1/backup0", "c1/scheduled7"}, "scheduled"))
+}
+
+func TestScheduledBackupsToPrune(t *testing.T) {
+	now := time.Now()
+	backups := map[string]time.Time{
+		"c1/scheduled0": now.Add(-4 * time.Hour),
+		"c1/scheduled1": now.Add(-3 * time.Hour),
+		"c1/backup0":    now.Add(-5 * time.Hour),
+		"c1/scheduled2": now.Add(-2 * time.Hour),
+		"c1/scheduledX": now.Add(-6 * time.Hour),
+		"c1/scheduled3": now.Add(-1 * time.Hour),
+	}
+
+	// Only the scheduled backups exceeding the retain count are pruned.
+	assert.Equal(t, []string{"c1/scheduled1", "c1/scheduled0"}, scheduledBackupsToPrune(backups, "2"))
+	assert.Equal(t, []string{"c1/scheduled2", "c1/scheduled1", "c1/scheduled0"}, scheduledBackupsToPrune(backups, "1"))
+	assert.Empty(t, scheduledBackupsToPrune(backups, "4"))
+
+	// Without a valid retain count nothing is pruned.
+	assert.Empty(t, scheduledBackupsToPrune(backups, ""))
+	assert.Empty(t, scheduledBackupsToPrune(backups, "0"))
+}
+
+func TestBackupsS3ParseURL(t *testing.T) {
+	bucket, key, err := backupsS3ParseURL("p1", "s3://backups/p1/c1/backup0")
+	assert.NoError(t, err)
+	assert.Equal(t, "backups", bucket)
+	assert.Equal(t, "p1/c1/backup0", key)
+
+	// Keys must be within the prefix of the project.
+	for _, objectURL := range []string{
+		"s3://backups/c1",
+		"s3://backups/p1",
+		"s3://backups/p1/",
+		"s3://backups/p10/c1",
+		"s3://backups/default/c1",
+		"s3://backups/p1/../default/c1",
+		"s3://backups/p1/./c1",
+		"s3://backups/p1//c1",
+		"https://backups/p1/c1",
+	} {
+		_, _, err := backupsS3ParseURL("p1", objectURL)
+		assert.Error(t, err, objectURL)
+	}
+}
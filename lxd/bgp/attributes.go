@@ -0,0 +1,178 @@
+package bgp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// wellKnownCommunities maps the names accepted for the well-known communities to their value.
+var wellKnownCommunities = map[string]uint32{
+	"no-export":    0xFFFFFF01,
+	"no-advertise": 0xFFFFFF02,
+	"blackhole":    0xFFFF029A,
+}
+
+// MaxASPathPrepend is the maximum number of times the local ASN can be prepended to the AS path.
+const MaxASPathPrepend = 16
+
+// PathAttributes represents the optional attributes sent along with an advertised prefix.
+type PathAttributes struct {
+	// Standard communities (ASN:VALUE or a well-known community name).
+	Communities []string
+
+	// Large communities (GLOBAL:LOCAL1:LOCAL2).
+	LargeCommunities []string
+
+	// Multi-exit discriminator, not sent when nil.
+	MED *uint32
+
+	// Number of times the local ASN is prepended to the AS path.
+	ASPathPrepend uint32
+}
+
+// ParseCommunity parses a standard community in the ASN:VALUE format or a well-known community name.
+func ParseCommunity(value string) (uint32, error) {
+	community, ok := wellKnownCommunities[value]
+	if ok {
+		return community, nil
+	}
+
+	fields := strings.Split(value, ":")
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("Invalid community %q (must be ASN:VALUE)", value)
+	}
+
+	asn, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid community %q ASN: %w", value, err)
+	}
+
+	local, err := strconv.ParseUint(fields[1], 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid community %q value: %w", value, err)
+	}
+
+	return uint32(asn<<16 | local), nil
+}
+
+// ParseLargeCommunity parses a large community in the GLOBAL:LOCAL1:LOCAL2 format.
+func ParseLargeCommunity(value string) (*bgpAPI.LargeCommunity, error) {
+	fields := strings.Split(value, ":")
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("Invalid large community %q (must be GLOBAL:LOCAL1:LOCAL2)", value)
+	}
+
+	parts := make([]uint32, 0, len(fields))
+	for _, field := range fields {
+		part, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid large community %q: %w", value, err)
+		}
+
+		parts = append(parts, uint32(part))
+	}
+
+	return &bgpAPI.LargeCommunity{GlobalAdmin: parts[0], LocalData1: parts[1], LocalData2: parts[2]}, nil
+}
+
+// Validate checks the path attributes.
+func (a PathAttributes) Validate() error {
+	for _, community := range a.Communities {
+		_, err := ParseCommunity(community)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, community := range a.LargeCommunities {
+		_, err := ParseLargeCommunity(community)
+		if err != nil {
+			return err
+		}
+	}
+
+	if a.ASPathPrepend > MaxASPathPrepend {
+		return fmt.Errorf("AS path prepend count cannot exceed %d", MaxASPathPrepend)
+	}
+
+	return nil
+}
+
+// pathAttributes returns the BGP path attributes for the prefix, using asn to prepend the AS path.
+func (a PathAttributes) pathAttributes(asn uint32) ([]*anypb.Any, error) {
+	var attrs []*anypb.Any
+
+	if a.ASPathPrepend > 0 {
+		if asn == 0 {
+			return nil, errors.New("AS path prepending requires a local ASN")
+		}
+
+		numbers := make([]uint32, 0, a.ASPathPrepend)
+		for range a.ASPathPrepend {
+			numbers = append(numbers, asn)
+		}
+
+		attr, err := anypb.New(&bgpAPI.AsPathAttribute{
+			Segments: []*bgpAPI.AsSegment{{Type: bgpAPI.AsSegment_AS_SEQUENCE, Numbers: numbers}},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		attrs = append(attrs, attr)
+	}
+
+	if a.MED != nil {
+		attr, err := anypb.New(&bgpAPI.MultiExitDiscAttribute{Med: *a.MED})
+		if err != nil {
+			return nil, err
+		}
+
+		attrs = append(attrs, attr)
+	}
+
+	if len(a.Communities) > 0 {
+		communities := make([]uint32, 0, len(a.Communities))
+		for _, value := range a.Communities {
+			community, err := ParseCommunity(value)
+			if err != nil {
+				return nil, err
+			}
+
+			communities = append(communities, community)
+		}
+
+		attr, err := anypb.New(&bgpAPI.CommunitiesAttribute{Communities: communities})
+		if err != nil {
+			return nil, err
+		}
+
+		attrs = append(attrs, attr)
+	}
+
+	if len(a.LargeCommunities) > 0 {
+		communities := make([]*bgpAPI.LargeCommunity, 0, len(a.LargeCommunities))
+		for _, value := range a.LargeCommunities {
+			community, err := ParseLargeCommunity(value)
+			if err != nil {
+				return nil, err
+			}
+
+			communities = append(communities, community)
+		}
+
+		attr, err := anypb.New(&bgpAPI.LargeCommunitiesAttribute{Communities: communities})
+		if err != nil {
+			return nil, err
+		}
+
+		attrs = append(attrs, attr)
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,120 @@
+package bgp
+
+import (
+	"testing"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommunity(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    uint32
+		wantErr bool
+	}{
+		{value: "65000:100", want: 65000<<16 | 100},
+		{value: "0:0", want: 0},
+		{value: "no-export", want: 0xFFFFFF01},
+		{value: "65536:100", wantErr: true},
+		{value: "65000:65536", wantErr: true},
+		{value: "65000", wantErr: true},
+		{value: "65000:1:2", wantErr: true},
+		{value: "foo:bar", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := ParseCommunity(tc.value)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseLargeCommunity(t *testing.T) {
+	community, err := ParseLargeCommunity("4200000000:1:2")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4200000000), community.GlobalAdmin)
+	assert.Equal(t, uint32(1), community.LocalData1)
+	assert.Equal(t, uint32(2), community.LocalData2)
+
+	for _, value := range []string{"65000:1", "4294967296:1:2", "a:b:c", ""} {
+		_, err := ParseLargeCommunity(value)
+		assert.Error(t, err, value)
+	}
+}
+
+// TestPathAttributes verifies the BGP attributes generated for advertised prefixes.
+func TestPathAttributes(t *testing.T) {
+	med := uint32(50)
+	attrs := PathAttributes{
+		Communities:      []string{"65000:100", "no-export"},
+		LargeCommunities: []string{"65000:1:2"},
+		MED:              &med,
+		ASPathPrepend:    2,
+	}
+
+	require.NoError(t, attrs.Validate())
+
+	pattrs, err := attrs.pathAttributes(65000)
+	require.NoError(t, err)
+	require.Len(t, pattrs, 4)
+
+	found := 0
+	for _, pattr := range pattrs {
+		msg, err := pattr.UnmarshalNew()
+		require.NoError(t, err)
+
+		switch a := msg.(type) {
+		case *bgpAPI.AsPathAttribute:
+			require.Len(t, a.Segments, 1)
+			assert.Equal(t, bgpAPI.AsSegment_AS_SEQUENCE, a.Segments[0].Type)
+			assert.Equal(t, []uint32{65000, 65000}, a.Segments[0].Numbers)
+			found++
+		case *bgpAPI.MultiExitDiscAttribute:
+			assert.Equal(t, med, a.Med)
+			found++
+		case *bgpAPI.CommunitiesAttribute:
+			assert.Equal(t, []uint32{65000<<16 | 100, 0xFFFFFF01}, a.Communities)
+			found++
+		case *bgpAPI.LargeCommunitiesAttribute:
+			require.Len(t, a.Communities, 1)
+			assert.Equal(t, uint32(65000), a.Communities[0].GlobalAdmin)
+			found++
+		}
+	}
+
+	assert.Equal(t, 4, found)
+
+	// No attributes are added by default.
+	pattrs, err = PathAttributes{}.pathAttributes(65000)
+	require.NoError(t, err)
+	assert.Empty(t, pattrs)
+
+	// Prepending requires the local ASN.
+	_, err = attrs.pathAttributes(0)
+	assert.Error(t, err)
+}
+
+// TestAddPrefixInvalidAttributes verifies that prefixes with invalid attributes are rejected.
+func TestAddPrefixInvalidAttributes(t *testing.T) {
+	s := NewServer()
+
+	err := s.AddPrefix(mustParseCIDR("10.0.0.0/24"), mustParseIP("192.168.1.1"), "owner", PathAttributes{Communities: []string{"foo"}})
+	assert.Error(t, err)
+
+	err = s.AddPrefix(mustParseCIDR("10.0.0.0/24"), mustParseIP("192.168.1.1"), "owner", PathAttributes{ASPathPrepend: MaxASPathPrepend + 1})
+	assert.Error(t, err)
+	assert.Empty(t, s.paths)
+
+	err = s.AddPrefix(mustParseCIDR("10.0.0.0/24"), mustParseIP("192.168.1.1"), "owner", PathAttributes{Communities: []string{"65000:1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"65000:1"}, s.Debug().Prefixes[0].Communities)
+}
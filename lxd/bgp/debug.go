@@ -5,6 +5,7 @@ type DebugInfo struct {
 	Server   DebugInfoServer   `json:"server" yaml:"server"`
 	Prefixes []DebugInfoPrefix `json:"prefixes" yaml:"prefixes"`
 	Peers    []DebugInfoPeer   `json:"peers" yaml:"peers"`
+	Routes   []DebugInfoRoute  `json:"routes" yaml:"routes"`
 }
 
 // DebugInfoServer exposes the shared listener configuration.
@@ -20,6 +21,19 @@ type DebugInfoPrefix struct {
 	Owner   string `json:"owner" yaml:"owner"`
 	Prefix  string `json:"prefix" yaml:"prefix"`
 	Nexthop string `json:"nexthop" yaml:"nexthop"`
+
+	Communities      []string `json:"communities" yaml:"communities"`
+	LargeCommunities []string `json:"large_communities" yaml:"large_communities"`
+	MED              *uint32  `json:"med" yaml:"med"`
+	ASPathPrepend    uint32   `json:"as_path_prepend" yaml:"as_path_prepend"`
+}
+
+// DebugInfoRoute exposes details on a single route learned from a BGP peer.
+type DebugInfoRoute struct {
+	Peer    string   `json:"peer" yaml:"peer"`
+	Prefix  string   `json:"prefix" yaml:"prefix"`
+	Nexthop string   `json:"nexthop" yaml:"nexthop"`
+	Owners  []string `json:"owners" yaml:"owners"`
 }
 
 // DebugInfoPeer exposes details on a single BGP peer.
@@ -29,6 +43,8 @@ type DebugInfoPeer struct {
 	Password string `json:"password" yaml:"password"`
 	Count    int    `json:"count" yaml:"count"`
 	HoldTime uint64 `json:"holdtime" yaml:"holdtime"`
+
+	MaxPrefixes uint32 `json:"max_prefixes" yaml:"max_prefixes"`
 }
 
 // Debug returns a dump of the current configuration.
@@ -58,6 +74,7 @@ func (s *Server) debug() DebugInfo {
 		entry.Password = peer.password
 		entry.Count = peer.count
 		entry.HoldTime = peer.holdtime
+		entry.MaxPrefixes = peer.maxPrefixes
 
 		debug.Peers = append(debug.Peers, entry)
 	}
@@ -69,9 +86,34 @@ func (s *Server) debug() DebugInfo {
 		entry.Prefix = path.prefix.String()
 		entry.Owner = path.owner
 		entry.Nexthop = path.nexthop.String()
+		entry.Communities = path.attrs.Communities
+		entry.LargeCommunities = path.attrs.LargeCommunities
+		entry.MED = path.attrs.MED
+		entry.ASPathPrepend = path.attrs.ASPathPrepend
 
 		debug.Prefixes = append(debug.Prefixes, entry)
 	}
 
+	// Fill in the learned routes and the imports using them.
+	debug.Routes = []DebugInfoRoute{}
+	for _, routes := range s.learned {
+		for key, route := range routes {
+			entry := DebugInfoRoute{}
+			entry.Peer = route.Peer.String()
+			entry.Prefix = key
+			entry.Nexthop = route.Nexthop.String()
+			entry.Owners = []string{}
+
+			for owner, imp := range s.imports {
+				selected, ok := imp.selected[key]
+				if ok && selected.Peer.Equal(route.Peer) {
+					entry.Owners = append(entry.Owners, owner)
+				}
+			}
+
+			debug.Routes = append(debug.Routes, entry)
+		}
+	}
+
 	return debug
 }
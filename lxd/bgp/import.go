@@ -0,0 +1,366 @@
+package bgp
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	bgpServer "github.com/osrg/gobgp/v3/pkg/server"
+
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Route represents a route learned from a BGP peer.
+type Route struct {
+	Prefix  net.IPNet
+	Nexthop net.IP
+	Peer    net.IP
+}
+
+// LearnedRoute represents a route learned from one of the peers of an import.
+type LearnedRoute struct {
+	Route
+
+	// Imported is true when the route was selected and passed to the import handler.
+	Imported bool
+}
+
+// ImportRejectFunc returns whether a learned prefix must never be imported, whatever the import prefix filter.
+type ImportRejectFunc func(prefix net.IPNet) bool
+
+// ImportHandler is called when a route is selected for import (withdraw is false), replacing any route
+// previously selected for the same prefix, and when no route is selected anymore for the prefix (withdraw is true).
+type ImportHandler func(route Route, withdraw bool) error
+
+type routeImport struct {
+	peers    []net.IP
+	prefixes []net.IPNet
+	reject   ImportRejectFunc
+	handler  ImportHandler
+	selected map[string]Route
+}
+
+// allowed returns whether the prefix is allowed by the import prefix filter.
+// An empty filter allows no prefixes, and the default route and the prefixes rejected by the import are
+// never allowed.
+func (i *routeImport) allowed(prefix net.IPNet) bool {
+	prefixLen, prefixBits := prefix.Mask.Size()
+	if prefixLen == 0 {
+		return false
+	}
+
+	for _, filter := range i.prefixes {
+		filterLen, filterBits := filter.Mask.Size()
+		if filterBits == prefixBits && filterLen <= prefixLen && filter.Contains(prefix.IP) {
+			return i.reject == nil || !i.reject(prefix)
+		}
+	}
+
+	return false
+}
+
+// hasPeer returns whether the import uses routes learned from the peer.
+func (i *routeImport) hasPeer(peer net.IP) bool {
+	return slices.ContainsFunc(i.peers, peer.Equal)
+}
+
+// AddImport installs the routes learned from the given peers and matching the prefix filter through the handler.
+// Prefixes for which the optional reject function returns true are never installed.
+// When several peers advertise the same prefix, the route from the first peer (in address order) is used.
+// Any existing import for the owner is replaced.
+func (s *Server) AddImport(owner string, peers []net.IP, prefixes []net.IPNet, reject ImportRejectFunc, handler ImportHandler) error {
+	if handler == nil {
+		return errors.New("Import handler is required")
+	}
+
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeImport(owner)
+
+	imp := &routeImport{
+		peers:    slices.Clone(peers),
+		prefixes: slices.Clone(prefixes),
+		reject:   reject,
+		handler:  handler,
+		selected: map[string]Route{},
+	}
+
+	slices.SortFunc(imp.peers, func(a net.IP, b net.IP) int { return bytes.Compare(a.To16(), b.To16()) })
+
+	s.imports[owner] = imp
+
+	// Import the routes already learned.
+	for _, prefix := range s.learnedPrefixes() {
+		s.selectRoute(imp, prefix)
+	}
+
+	return nil
+}
+
+// RemoveImportByOwner withdraws the routes imported for the owner and removes the import.
+func (s *Server) RemoveImportByOwner(owner string) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeImport(owner)
+}
+
+func (s *Server) removeImport(owner string) {
+	imp, ok := s.imports[owner]
+	if !ok {
+		return
+	}
+
+	for key, route := range imp.selected {
+		err := imp.handler(route, true)
+		if err != nil {
+			logger.Warn("Failed withdrawing imported BGP route", logger.Ctx{"owner": owner, "prefix": route.Prefix.String(), "err": err})
+		}
+
+		delete(imp.selected, key)
+	}
+
+	delete(s.imports, owner)
+}
+
+// LearnedRoutes returns the routes learned from the peers of the owner's import.
+func (s *Server) LearnedRoutes(owner string) []LearnedRoute {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imp, ok := s.imports[owner]
+	if !ok {
+		return nil
+	}
+
+	routes := []LearnedRoute{}
+	for _, peer := range imp.peers {
+		for key, route := range s.learned[peer.String()] {
+			selected, ok := imp.selected[key]
+			imported := ok && selected.Peer.Equal(route.Peer) && selected.Nexthop.Equal(route.Nexthop)
+
+			routes = append(routes, LearnedRoute{Route: route, Imported: imported})
+		}
+	}
+
+	slices.SortFunc(routes, func(a LearnedRoute, b LearnedRoute) int {
+		return strings.Compare(a.Prefix.String()+" "+a.Peer.String(), b.Prefix.String()+" "+b.Peer.String())
+	})
+
+	return routes
+}
+
+// learnedPrefixes returns the prefixes learned from all peers.
+func (s *Server) learnedPrefixes() []string {
+	prefixes := []string{}
+	for _, routes := range s.learned {
+		for key := range routes {
+			if !slices.Contains(prefixes, key) {
+				prefixes = append(prefixes, key)
+			}
+		}
+	}
+
+	return prefixes
+}
+
+// selectRoute updates the route selected by the import for the prefix.
+func (s *Server) selectRoute(imp *routeImport, key string) {
+	var candidate *Route
+	for _, peer := range imp.peers {
+		route, ok := s.learned[peer.String()][key]
+		if ok && imp.allowed(route.Prefix) {
+			candidate = &route
+			break
+		}
+	}
+
+	current, hasCurrent := imp.selected[key]
+	if candidate == nil {
+		if hasCurrent {
+			err := imp.handler(current, true)
+			if err != nil {
+				logger.Warn("Failed withdrawing imported BGP route", logger.Ctx{"prefix": key, "err": err})
+			}
+
+			delete(imp.selected, key)
+		}
+
+		return
+	}
+
+	if hasCurrent && current.Peer.Equal(candidate.Peer) && current.Nexthop.Equal(candidate.Nexthop) {
+		return
+	}
+
+	// Importing a route replaces the one previously selected for the prefix.
+	err := imp.handler(*candidate, false)
+	if err != nil {
+		logger.Warn("Failed importing BGP route", logger.Ctx{"prefix": key, "nexthop": candidate.Nexthop.String(), "err": err})
+		return
+	}
+
+	imp.selected[key] = *candidate
+}
+
+// refreshImports updates the routes selected by the imports using the peer.
+func (s *Server) refreshImports(peer net.IP, key string) {
+	for _, imp := range s.imports {
+		if imp.hasPeer(peer) {
+			s.selectRoute(imp, key)
+		}
+	}
+}
+
+// learnRoute records a route advertised by a peer.
+func (s *Server) learnRoute(route Route) {
+	peer := route.Peer.String()
+	if s.learned[peer] == nil {
+		s.learned[peer] = map[string]Route{}
+	}
+
+	key := route.Prefix.String()
+	s.learned[peer][key] = route
+	s.refreshImports(route.Peer, key)
+}
+
+// withdrawRoute removes a route withdrawn by a peer.
+func (s *Server) withdrawRoute(peer net.IP, prefix net.IPNet) {
+	key := prefix.String()
+
+	_, ok := s.learned[peer.String()][key]
+	if !ok {
+		return
+	}
+
+	delete(s.learned[peer.String()], key)
+	s.refreshImports(peer, key)
+}
+
+// forgetPeer removes all the routes learned from a peer.
+func (s *Server) forgetPeer(peer net.IP) {
+	routes := s.learned[peer.String()]
+	delete(s.learned, peer.String())
+
+	for key := range routes {
+		s.refreshImports(peer, key)
+	}
+}
+
+// watchRoutes returns the handler of the events of the BGP listener, recording the routes advertised by peers.
+func (s *Server) watchRoutes(listener *bgpServer.BgpServer) func(*bgpAPI.WatchEventResponse) {
+	return func(resp *bgpAPI.WatchEventResponse) {
+		// Locking.
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// Ignore events from a listener which was since stopped.
+		if s.bgp != listener {
+			return
+		}
+
+		switch event := resp.Event.(type) {
+		case *bgpAPI.WatchEventResponse_Peer:
+			peerState := event.Peer.GetPeer().GetState()
+			if peerState == nil || peerState.SessionState == bgpAPI.PeerState_ESTABLISHED {
+				return
+			}
+
+			// Routes from peers which are down are no longer valid.
+			peer := net.ParseIP(peerState.NeighborAddress)
+			if peer != nil {
+				s.forgetPeer(peer)
+			}
+
+		case *bgpAPI.WatchEventResponse_Table:
+			for _, path := range event.Table.Paths {
+				route, err := pathToRoute(path)
+				if err != nil {
+					logger.Debug("Ignoring BGP path", logger.Ctx{"err": err})
+					continue
+				}
+
+				if path.IsWithdraw {
+					s.withdrawRoute(route.Peer, route.Prefix)
+				} else {
+					s.learnRoute(*route)
+				}
+			}
+		}
+	}
+}
+
+// pathToRoute converts a path received from a peer into a route.
+func pathToRoute(path *bgpAPI.Path) (*Route, error) {
+	peer := net.ParseIP(path.NeighborIp)
+	if peer == nil {
+		return nil, errors.New("Path has no peer address")
+	}
+
+	if path.Nlri == nil {
+		return nil, errors.New("Path has no NLRI")
+	}
+
+	nlri := &bgpAPI.IPAddressPrefix{}
+	err := path.Nlri.UnmarshalTo(nlri)
+	if err != nil {
+		return nil, errors.New("Path is not for an IP prefix")
+	}
+
+	ip := net.ParseIP(nlri.Prefix)
+	if ip == nil {
+		return nil, errors.New("Invalid path prefix")
+	}
+
+	bits := 128
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 32
+	}
+
+	if int(nlri.PrefixLen) > bits {
+		return nil, errors.New("Invalid path prefix length")
+	}
+
+	mask := net.CIDRMask(int(nlri.PrefixLen), bits)
+	route := &Route{
+		Prefix: net.IPNet{IP: ip.Mask(mask), Mask: mask},
+		Peer:   peer,
+	}
+
+	for _, attr := range path.Pattrs {
+		msg, err := attr.UnmarshalNew()
+		if err != nil {
+			continue
+		}
+
+		var nexthops []string
+		switch a := msg.(type) {
+		case *bgpAPI.NextHopAttribute:
+			nexthops = []string{a.NextHop}
+		case *bgpAPI.MpReachNLRIAttribute:
+			nexthops = a.NextHops
+		default:
+			continue
+		}
+
+		// Use the first (global) next-hop.
+		if len(nexthops) > 0 {
+			route.Nexthop = net.ParseIP(nexthops[0])
+		}
+	}
+
+	// Withdrawals don't carry a next-hop.
+	if route.Nexthop == nil && !path.IsWithdraw {
+		return nil, errors.New("Path has no next-hop")
+	}
+
+	return route, nil
+}
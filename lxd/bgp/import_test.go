@@ -0,0 +1,219 @@
+package bgp
+
+import (
+	"net"
+	"testing"
+
+	bgpAPI "github.com/osrg/gobgp/v3/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// importRecorder records the routes installed through an import handler.
+type importRecorder struct {
+	routes map[string]Route
+}
+
+func (r *importRecorder) handler(route Route, withdraw bool) error {
+	if withdraw {
+		delete(r.routes, route.Prefix.String())
+	} else {
+		r.routes[route.Prefix.String()] = route
+	}
+
+	return nil
+}
+
+func newImportRecorder() *importRecorder {
+	return &importRecorder{routes: map[string]Route{}}
+}
+
+// TestImportSelection verifies the routes selected for an import as peers advertise and withdraw them.
+func TestImportSelection(t *testing.T) {
+	s := NewServer()
+	peer1 := mustParseIP("192.0.2.1")
+	peer2 := mustParseIP("192.0.2.2")
+	other := mustParseIP("198.51.100.1")
+
+	rec := newImportRecorder()
+	require.NoError(t, s.AddImport("network_1", []net.IP{peer2, peer1}, []net.IPNet{mustParseCIDR("10.0.0.0/8")}, nil, rec.handler))
+
+	// Routes from peers outside of the import are ignored.
+	s.learnRoute(Route{Prefix: mustParseCIDR("10.1.0.0/16"), Nexthop: other, Peer: other})
+	assert.Empty(t, rec.routes)
+
+	// Routes from the import peers are installed.
+	s.learnRoute(Route{Prefix: mustParseCIDR("10.0.0.0/16"), Nexthop: mustParseIP("192.0.2.20"), Peer: peer2})
+	require.Contains(t, rec.routes, "10.0.0.0/16")
+	assert.True(t, rec.routes["10.0.0.0/16"].Peer.Equal(peer2))
+
+	// The route from the first peer is preferred.
+	s.learnRoute(Route{Prefix: mustParseCIDR("10.0.0.0/16"), Nexthop: mustParseIP("192.0.2.10"), Peer: peer1})
+	assert.True(t, rec.routes["10.0.0.0/16"].Peer.Equal(peer1))
+
+	// Withdrawing the preferred route falls back to the other peer.
+	s.withdrawRoute(peer1, mustParseCIDR("10.0.0.0/16"))
+	assert.True(t, rec.routes["10.0.0.0/16"].Peer.Equal(peer2))
+
+	// Routes are removed when the peer goes away.
+	s.forgetPeer(peer2)
+	assert.Empty(t, rec.routes)
+}
+
+// TestImportPrefixFilter verifies that only the routes within the import prefixes are installed.
+func TestImportPrefixFilter(t *testing.T) {
+	s := NewServer()
+	peer := mustParseIP("2001:db8::1")
+
+	rec := newImportRecorder()
+	require.NoError(t, s.AddImport("network_1", []net.IP{peer}, []net.IPNet{mustParseCIDR("10.0.0.0/8"), mustParseCIDR("2001:db8:1::/48")}, nil, rec.handler))
+
+	for _, prefix := range []string{"10.0.0.0/8", "10.1.2.0/24", "2001:db8:1:2::/64", "11.0.0.0/24", "0.0.0.0/0", "2001:db8::/32", "::/0"} {
+		s.learnRoute(Route{Prefix: mustParseCIDR(prefix), Nexthop: peer, Peer: peer})
+	}
+
+	assert.Len(t, rec.routes, 3)
+	assert.Contains(t, rec.routes, "10.0.0.0/8")
+	assert.Contains(t, rec.routes, "10.1.2.0/24")
+	assert.Contains(t, rec.routes, "2001:db8:1:2::/64")
+
+	learned := s.LearnedRoutes("network_1")
+	require.Len(t, learned, 7)
+
+	imported := 0
+	for _, route := range learned {
+		if route.Imported {
+			imported++
+		}
+	}
+
+	assert.Equal(t, 3, imported)
+}
+
+// TestImportRejected verifies that nothing is installed without a prefix filter, and that the default route
+// and the rejected prefixes are never installed.
+func TestImportRejected(t *testing.T) {
+	s := NewServer()
+	peer := mustParseIP("192.0.2.1")
+
+	for _, prefix := range []string{"0.0.0.0/0", "10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "10.2.0.0/16"} {
+		s.learnRoute(Route{Prefix: mustParseCIDR(prefix), Nexthop: peer, Peer: peer})
+	}
+
+	rec := newImportRecorder()
+	require.NoError(t, s.AddImport("network_1", []net.IP{peer}, nil, nil, rec.handler))
+	assert.Empty(t, rec.routes)
+
+	_, local, err := net.ParseCIDR("10.1.0.0/16")
+	require.NoError(t, err)
+
+	reject := func(prefix net.IPNet) bool {
+		ones, _ := prefix.Mask.Size()
+		localOnes, _ := local.Mask.Size()
+
+		return ones >= localOnes && local.Contains(prefix.IP)
+	}
+
+	require.NoError(t, s.AddImport("network_1", []net.IP{peer}, []net.IPNet{mustParseCIDR("0.0.0.0/0")}, reject, rec.handler))
+	assert.Len(t, rec.routes, 2)
+	assert.Contains(t, rec.routes, "10.0.0.0/8")
+	assert.Contains(t, rec.routes, "10.2.0.0/16")
+}
+
+// TestImportExistingRoutes verifies that adding an import installs the routes already learned,
+// and that removing it withdraws them.
+func TestImportExistingRoutes(t *testing.T) {
+	s := NewServer()
+	peer := mustParseIP("192.0.2.1")
+
+	s.learnRoute(Route{Prefix: mustParseCIDR("10.0.0.0/16"), Nexthop: peer, Peer: peer})
+
+	rec := newImportRecorder()
+	require.NoError(t, s.AddImport("network_1", []net.IP{peer}, []net.IPNet{mustParseCIDR("10.0.0.0/8")}, nil, rec.handler))
+	assert.Len(t, rec.routes, 1)
+
+	// Replacing the import with a filter withdraws the routes which aren't allowed anymore.
+	require.NoError(t, s.AddImport("network_1", []net.IP{peer}, []net.IPNet{mustParseCIDR("172.16.0.0/12")}, nil, rec.handler))
+	assert.Empty(t, rec.routes)
+
+	require.NoError(t, s.AddImport("network_1", []net.IP{peer}, []net.IPNet{mustParseCIDR("10.0.0.0/8")}, nil, rec.handler))
+	assert.Len(t, rec.routes, 1)
+
+	s.RemoveImportByOwner("network_1")
+	assert.Empty(t, rec.routes)
+	assert.Nil(t, s.LearnedRoutes("network_1"))
+}
+
+// TestPathToRoute verifies the conversion of paths received from peers.
+func TestPathToRoute(t *testing.T) {
+	mustAny := func(msg *bgpAPI.IPAddressPrefix) *anypb.Any {
+		a, err := anypb.New(msg)
+		require.NoError(t, err)
+		return a
+	}
+
+	nextHop, err := anypb.New(&bgpAPI.NextHopAttribute{NextHop: "192.0.2.254"})
+	require.NoError(t, err)
+
+	mpReach, err := anypb.New(&bgpAPI.MpReachNLRIAttribute{NextHops: []string{"2001:db8::254", "fe80::1"}})
+	require.NoError(t, err)
+
+	t.Run("IPv4", func(t *testing.T) {
+		route, err := pathToRoute(&bgpAPI.Path{
+			Nlri:       mustAny(&bgpAPI.IPAddressPrefix{Prefix: "10.1.2.3", PrefixLen: 24}),
+			Pattrs:     []*anypb.Any{nextHop},
+			NeighborIp: "192.0.2.1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "10.1.2.0/24", route.Prefix.String())
+		assert.Equal(t, "192.0.2.254", route.Nexthop.String())
+		assert.Equal(t, "192.0.2.1", route.Peer.String())
+	})
+
+	t.Run("IPv6", func(t *testing.T) {
+		route, err := pathToRoute(&bgpAPI.Path{
+			Nlri:       mustAny(&bgpAPI.IPAddressPrefix{Prefix: "2001:db8:1::", PrefixLen: 48}),
+			Pattrs:     []*anypb.Any{mpReach},
+			NeighborIp: "2001:db8::1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "2001:db8:1::/48", route.Prefix.String())
+		assert.Equal(t, "2001:db8::254", route.Nexthop.String())
+	})
+
+	t.Run("Withdrawal without next-hop", func(t *testing.T) {
+		route, err := pathToRoute(&bgpAPI.Path{
+			Nlri:       mustAny(&bgpAPI.IPAddressPrefix{Prefix: "10.1.2.0", PrefixLen: 24}),
+			NeighborIp: "192.0.2.1",
+			IsWithdraw: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "10.1.2.0/24", route.Prefix.String())
+	})
+
+	t.Run("Missing next-hop", func(t *testing.T) {
+		_, err := pathToRoute(&bgpAPI.Path{
+			Nlri:       mustAny(&bgpAPI.IPAddressPrefix{Prefix: "10.1.2.0", PrefixLen: 24}),
+			NeighborIp: "192.0.2.1",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing peer", func(t *testing.T) {
+		_, err := pathToRoute(&bgpAPI.Path{
+			Nlri:   mustAny(&bgpAPI.IPAddressPrefix{Prefix: "10.1.2.0", PrefixLen: 24}),
+			Pattrs: []*anypb.Any{nextHop},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid prefix length", func(t *testing.T) {
+		_, err := pathToRoute(&bgpAPI.Path{
+			Nlri:       mustAny(&bgpAPI.IPAddressPrefix{Prefix: "10.1.2.0", PrefixLen: 33}),
+			Pattrs:     []*anypb.Any{nextHop},
+			NeighborIp: "192.0.2.1",
+		})
+		assert.Error(t, err)
+	})
+}
@@ -32,6 +32,11 @@ type Server struct {
 	paths    map[string]path
 	peers    map[string]peer
 
+	// Routes learned from peers (by peer address and prefix) and their imports (by owner).
+	learned     map[string]map[string]Route
+	imports     map[string]*routeImport
+	watchCancel context.CancelFunc
+
 	mu sync.Mutex
 }
 
@@ -39,6 +44,7 @@ type path struct {
 	owner   string
 	prefix  net.IPNet
 	nexthop net.IP
+	attrs   PathAttributes
 }
 
 type peer struct {
@@ -47,14 +53,18 @@ type peer struct {
 	password string
 	holdtime uint64
 	count    int
+
+	maxPrefixes uint32
 }
 
 // NewServer returns a new server instance.
 func NewServer() *Server {
 	// Setup new struct.
 	s := &Server{
-		paths: map[string]path{},
-		peers: map[string]peer{},
+		paths:   map[string]path{},
+		peers:   map[string]peer{},
+		learned: map[string]map[string]Route{},
+		imports: map[string]*routeImport{},
 	}
 
 	return s
@@ -111,6 +121,24 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 		return err
 	}
 
+	// Watch the routes advertised by the peers.
+	watchCtx, watchCancel := context.WithCancel(context.Background())
+	err = s.bgp.WatchEvent(watchCtx, &bgpAPI.WatchEventRequest{
+		Peer: &bgpAPI.WatchEventRequest_Peer{},
+		Table: &bgpAPI.WatchEventRequest_Table{
+			Filters: []*bgpAPI.WatchEventRequest_Table_Filter{{Type: bgpAPI.WatchEventRequest_Table_Filter_ADJIN, Init: true}},
+		},
+	}, s.watchRoutes(s.bgp))
+	if err != nil {
+		watchCancel()
+		return err
+	}
+
+	s.watchCancel = watchCancel
+
+	// Record the ASN (used when adding paths).
+	s.asn = asn
+
 	// Copy the path list
 	oldPaths := map[string]path{}
 	maps.Copy(oldPaths, s.paths)
@@ -118,7 +146,7 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 	// Add existing paths.
 	s.paths = map[string]path{}
 	for _, path := range oldPaths {
-		err := s.addPrefix(path.prefix, path.nexthop, path.owner)
+		err := s.addPrefix(path.prefix, path.nexthop, path.owner, path.attrs)
 		if err != nil {
 			logger.Warn("Cannot add prefix to BGP server", logger.Ctx{"prefix": path.prefix.String(), "err": err})
 		}
@@ -131,7 +159,7 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 	// Add existing peers.
 	s.peers = map[string]peer{}
 	for _, peer := range oldPeers {
-		err := s.addPeer(peer.address, peer.asn, peer.password, peer.holdtime)
+		err := s.addPeer(peer.address, peer.asn, peer.password, peer.holdtime, peer.maxPrefixes)
 		if err != nil {
 			return err
 		}
@@ -173,6 +201,16 @@ func (s *Server) stop() error {
 		return err
 	}
 
+	// Stop watching routes and drop the ones learned from the peers.
+	if s.watchCancel != nil {
+		s.watchCancel()
+		s.watchCancel = nil
+	}
+
+	for peer := range s.learned {
+		s.forgetPeer(net.ParseIP(peer))
+	}
+
 	// Mark the daemon as down.
 	s.address = ""
 	s.asn = 0
@@ -224,16 +262,21 @@ func (s *Server) configure(address string, asn uint32, routerID net.IP) error {
 	return nil
 }
 
-// AddPrefix adds a new prefix to the BGP server.
-func (s *Server) AddPrefix(subnet net.IPNet, nexthop net.IP, owner string) error {
+// AddPrefix adds a new prefix to the BGP server, advertised with the provided path attributes.
+func (s *Server) AddPrefix(subnet net.IPNet, nexthop net.IP, owner string, attrs PathAttributes) error {
+	err := attrs.Validate()
+	if err != nil {
+		return err
+	}
+
 	// Locking.
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return s.addPrefix(subnet, nexthop, owner)
+	return s.addPrefix(subnet, nexthop, owner, attrs)
 }
 
-func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error {
+func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string, attrs PathAttributes) error {
 	// Prepare the prefix.
 	prefixLen, _ := subnet.Mask.Size()
 	prefix := subnet.IP.String()
@@ -250,6 +293,11 @@ func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error
 	// Add the prefix to the server.
 	var pathUUID string
 	if s.bgp != nil {
+		extraAttrs, err := attrs.pathAttributes(s.asn)
+		if err != nil {
+			return err
+		}
+
 		if subnet.IP.To4() != nil {
 			// IPv4 prefix.
 			aNextHop, _ := anypb.New(&bgpAPI.NextHopAttribute{
@@ -260,7 +308,7 @@ func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error
 				Path: &bgpAPI.Path{
 					Family: &bgpAPI.Family{Afi: bgpAPI.Family_AFI_IP, Safi: bgpAPI.Family_SAFI_UNICAST},
 					Nlri:   nlri,
-					Pattrs: []*anypb.Any{aOrigin, aNextHop},
+					Pattrs: append([]*anypb.Any{aOrigin, aNextHop}, extraAttrs...),
 				},
 			})
 			if err != nil {
@@ -285,7 +333,7 @@ func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error
 				Path: &bgpAPI.Path{
 					Family: family,
 					Nlri:   nlri,
-					Pattrs: []*anypb.Any{aOrigin, v6Attrs},
+					Pattrs: append([]*anypb.Any{aOrigin, v6Attrs}, extraAttrs...),
 				},
 			})
 			if err != nil {
@@ -304,6 +352,7 @@ func (s *Server) addPrefix(subnet net.IPNet, nexthop net.IP, owner string) error
 		prefix:  subnet,
 		nexthop: nexthop,
 		owner:   owner,
+		attrs:   attrs,
 	}
 
 	return nil
@@ -380,15 +429,16 @@ func (s *Server) removePrefixByUUID(pathUUID string) error {
 }
 
 // AddPeer adds a new BGP peer.
-func (s *Server) AddPeer(address net.IP, asn uint32, password string, holdTime uint64) error {
+// When maxPrefixes is set, the session is torn down if the peer advertises more prefixes per address family.
+func (s *Server) AddPeer(address net.IP, asn uint32, password string, holdTime uint64, maxPrefixes uint32) error {
 	// Locking.
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return s.addPeer(address, asn, password, holdTime)
+	return s.addPeer(address, asn, password, holdTime, maxPrefixes)
 }
 
-func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime uint64) error {
+func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime uint64, maxPrefixes uint32) error {
 	addrStr := address.String()
 
 	// Look for an existing peer.
@@ -402,6 +452,10 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 			return fmt.Errorf("Peer %q already used but with a different password", addrStr)
 		}
 
+		if bgpPeer.maxPrefixes != maxPrefixes {
+			return fmt.Errorf("Peer %q already used but with differing prefix limit (%d vs %d)", addrStr, maxPrefixes, bgpPeer.maxPrefixes)
+		}
+
 		// Re-use the existing entry.
 		bgpPeer.count++
 		s.peers[addrStr] = bgpPeer
@@ -453,14 +507,24 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 			Safi: bgpAPI.Family_Safi(safi),
 		}
 
-		n.AfiSafis = append(n.AfiSafis, &bgpAPI.AfiSafi{
+		afiSafi := &bgpAPI.AfiSafi{
 			MpGracefulRestart: &bgpAPI.MpGracefulRestart{
 				Config: &bgpAPI.MpGracefulRestartConfig{
 					Enabled: true,
 				},
 			},
 			Config: &bgpAPI.AfiSafiConfig{Family: family},
-		})
+		}
+
+		// Limit the number of prefixes accepted from the peer.
+		if maxPrefixes > 0 {
+			afiSafi.PrefixLimits = &bgpAPI.PrefixLimit{
+				Family:      family,
+				MaxPrefixes: maxPrefixes,
+			}
+		}
+
+		n.AfiSafis = append(n.AfiSafis, afiSafi)
 	}
 
 	// Add the peer.
@@ -478,6 +542,8 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 		password: password,
 		holdtime: holdTime,
 		count:    1,
+
+		maxPrefixes: maxPrefixes,
 	}
 
 	return nil
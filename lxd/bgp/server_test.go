@@ -51,7 +51,7 @@ func TestAddRemovePrefix(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			s := NewServer()
 
-			err := s.AddPrefix(tc.subnet, tc.nexthop, "owner")
+			err := s.AddPrefix(tc.subnet, tc.nexthop, "owner", PathAttributes{})
 			require.NoError(t, err)
 			require.Len(t, s.paths, 1)
 
@@ -77,7 +77,7 @@ func TestRemovePrefixWrongNexthop(t *testing.T) {
 	s := NewServer()
 	subnet := mustParseCIDR("10.0.0.0/24")
 
-	err := s.AddPrefix(subnet, mustParseIP("192.168.1.1"), "owner")
+	err := s.AddPrefix(subnet, mustParseIP("192.168.1.1"), "owner", PathAttributes{})
 	require.NoError(t, err)
 
 	// Different nexthop — should not match.
@@ -93,13 +93,13 @@ func TestRemovePrefixWrongNexthop(t *testing.T) {
 func TestRemovePrefixByOwner(t *testing.T) {
 	s := NewServer()
 
-	err := s.AddPrefix(mustParseCIDR("10.0.0.0/24"), mustParseIP("192.168.1.1"), "owner-a")
+	err := s.AddPrefix(mustParseCIDR("10.0.0.0/24"), mustParseIP("192.168.1.1"), "owner-a", PathAttributes{})
 	require.NoError(t, err)
 
-	err = s.AddPrefix(mustParseCIDR("10.0.1.0/24"), mustParseIP("192.168.1.1"), "owner-b")
+	err = s.AddPrefix(mustParseCIDR("10.0.1.0/24"), mustParseIP("192.168.1.1"), "owner-b", PathAttributes{})
 	require.NoError(t, err)
 
-	err = s.AddPrefix(mustParseCIDR("10.0.2.0/24"), mustParseIP("192.168.1.1"), "owner-a")
+	err = s.AddPrefix(mustParseCIDR("10.0.2.0/24"), mustParseIP("192.168.1.1"), "owner-a", PathAttributes{})
 	require.NoError(t, err)
 
 	require.Len(t, s.paths, 3)
@@ -119,7 +119,7 @@ func TestRemovePrefixByOwner(t *testing.T) {
 func TestRemovePrefixByOwnerNoMatch(t *testing.T) {
 	s := NewServer()
 
-	err := s.AddPrefix(mustParseCIDR("10.0.0.0/24"), mustParseIP("192.168.1.1"), "owner-a")
+	err := s.AddPrefix(mustParseCIDR("10.0.0.0/24"), mustParseIP("192.168.1.1"), "owner-a", PathAttributes{})
 	require.NoError(t, err)
 
 	err = s.RemovePrefixByOwner("owner-b")
@@ -132,7 +132,7 @@ func TestAddRemovePeer(t *testing.T) {
 	s := NewServer()
 	addr := mustParseIP("192.168.1.1")
 
-	err := s.AddPeer(addr, 65000, "", 0)
+	err := s.AddPeer(addr, 65000, "", 0, 0)
 	require.NoError(t, err)
 	require.Len(t, s.peers, 1)
 
@@ -157,11 +157,11 @@ func TestAddPeerRefcount(t *testing.T) {
 	s := NewServer()
 	addr := mustParseIP("192.168.1.1")
 
-	err := s.AddPeer(addr, 65000, "", 0)
+	err := s.AddPeer(addr, 65000, "", 0, 0)
 	require.NoError(t, err)
 	require.Equal(t, 1, s.peers[addr.String()].count)
 
-	err = s.AddPeer(addr, 65000, "", 0)
+	err = s.AddPeer(addr, 65000, "", 0, 0)
 	require.NoError(t, err)
 	require.Equal(t, 2, s.peers[addr.String()].count)
 
@@ -183,10 +183,10 @@ func TestAddPeerConflictASN(t *testing.T) {
 	s := NewServer()
 	addr := mustParseIP("192.168.1.1")
 
-	err := s.AddPeer(addr, 65000, "", 0)
+	err := s.AddPeer(addr, 65000, "", 0, 0)
 	require.NoError(t, err)
 
-	err = s.AddPeer(addr, 65001, "", 0)
+	err = s.AddPeer(addr, 65001, "", 0, 0)
 	require.Error(t, err)
 }
 
@@ -196,9 +196,9 @@ func TestAddPeerConflictPassword(t *testing.T) {
 	s := NewServer()
 	addr := mustParseIP("192.168.1.1")
 
-	err := s.AddPeer(addr, 65000, "secret", 0)
+	err := s.AddPeer(addr, 65000, "secret", 0, 0)
 	require.NoError(t, err)
 
-	err = s.AddPeer(addr, 65000, "different", 0)
+	err = s.AddPeer(addr, 65000, "different", 0, 0)
 	require.Error(t, err)
 }
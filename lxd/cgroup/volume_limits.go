@@ -0,0 +1,202 @@
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// VolumeLimitsCGroup is the name of the cgroup shared by the instances using storage volumes with I/O limits.
+// Its io.max setting holds the limits of those volumes so that they form a single budget for all the instances.
+// It lives next to the default location of the container cgroups.
+const VolumeLimitsCGroup = "lxd.volume-limits"
+
+// VolumeLimit represents the I/O limits of a block device. Zero means no limit.
+type VolumeLimit struct {
+	ReadBytes  int64
+	ReadIOps   int64
+	WriteBytes int64
+	WriteIOps  int64
+}
+
+// VolumeLimitsSupported returns whether I/O limits can be shared between instances.
+// This requires a pure cgroup2 layout with the io controller.
+func VolumeLimitsSupported() bool {
+	return cgLayout == CgroupsUnified && cgControllers["io"] == V2
+}
+
+// unifiedPath returns the cgroup2 path of the given process.
+func unifiedPath(pid int) (string, error) {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+
+	for line := range strings.SplitSeq(string(content), "\n") {
+		path, ok := strings.CutPrefix(strings.TrimSpace(line), "0::")
+		if ok {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("Failed finding cgroup2 path of process %d", pid)
+}
+
+// volumeLimitsPath returns the path of the shared cgroup.
+// Like liblxc does for the container cgroups, it is placed relative to the cgroup of the init process.
+func volumeLimitsPath() (string, error) {
+	initPath, err := unifiedPath(1)
+	if err != nil {
+		return "", err
+	}
+
+	initPath, _ = strings.CutSuffix(initPath, "/init.scope")
+
+	return filepath.Join(cgPath, initPath, VolumeLimitsCGroup), nil
+}
+
+// VolumeLimitsEnsure creates the shared cgroup and enables the io controller for its children.
+func VolumeLimitsEnsure() error {
+	if !VolumeLimitsSupported() {
+		return errors.New("Shared I/O limits require a pure cgroup2 layout with the io controller")
+	}
+
+	path, err := volumeLimitsPath()
+	if err != nil {
+		return err
+	}
+
+	err = os.Mkdir(path, 0755)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("Failed creating cgroup %q: %w", path, err)
+	}
+
+	for _, dir := range []string{filepath.Dir(path), path} {
+		err = os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte("+io"), 0600)
+		if err != nil {
+			return fmt.Errorf("Failed enabling io controller in cgroup %q: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// VolumeLimitsContains returns whether the given process is in the shared cgroup.
+func VolumeLimitsContains(pid int) (bool, error) {
+	path, err := unifiedPath(pid)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(path+"/", "/"+VolumeLimitsCGroup+"/"), nil
+}
+
+// VolumeLimitsAddProcess moves the given process to the named child of the shared cgroup, creating it if needed.
+func VolumeLimitsAddProcess(name string, pid int) error {
+	err := VolumeLimitsEnsure()
+	if err != nil {
+		return err
+	}
+
+	path, err := volumeLimitsPath()
+	if err != nil {
+		return err
+	}
+
+	path = filepath.Join(path, name)
+
+	err = os.Mkdir(path, 0755)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("Failed creating cgroup %q: %w", path, err)
+	}
+
+	err = os.WriteFile(filepath.Join(path, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed moving process %d to cgroup %q: %w", pid, path, err)
+	}
+
+	return nil
+}
+
+// VolumeLimitsRemove removes the named child of the shared cgroup.
+// Nothing is done if the child does not exist or still holds processes.
+func VolumeLimitsRemove(name string) error {
+	if !VolumeLimitsSupported() {
+		return nil
+	}
+
+	path, err := volumeLimitsPath()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(path, name))
+	if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.Is(err, unix.EBUSY) {
+		return fmt.Errorf("Failed removing cgroup %q: %w", filepath.Join(path, name), err)
+	}
+
+	return nil
+}
+
+// VolumeLimitsSet sets the io.max setting of the shared cgroup to the given limits, keyed by block device
+// (major:minor). The limits of the block devices not in the map are cleared.
+func VolumeLimitsSet(limits map[string]VolumeLimit) error {
+	err := VolumeLimitsEnsure()
+	if err != nil {
+		return err
+	}
+
+	path, err := volumeLimitsPath()
+	if err != nil {
+		return err
+	}
+
+	ioMaxPath := filepath.Join(path, "io.max")
+
+	content, err := os.ReadFile(ioMaxPath)
+	if err != nil {
+		return err
+	}
+
+	lines := []string{}
+
+	// Clear the limits of the block devices which are no longer limited.
+	for line := range strings.SplitSeq(string(content), "\n") {
+		block, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if block == "" {
+			continue
+		}
+
+		_, ok := limits[block]
+		if !ok {
+			lines = append(lines, block+" rbps=max wbps=max riops=max wiops=max")
+		}
+	}
+
+	value := func(limit int64) string {
+		if limit <= 0 {
+			return "max"
+		}
+
+		return strconv.FormatInt(limit, 10)
+	}
+
+	for block, limit := range limits {
+		lines = append(lines, fmt.Sprintf("%s rbps=%s wbps=%s riops=%s wiops=%s", block, value(limit.ReadBytes), value(limit.WriteBytes), value(limit.ReadIOps), value(limit.WriteIOps)))
+	}
+
+	// The kernel only accepts a single block device per write.
+	for _, line := range lines {
+		err = os.WriteFile(ioMaxPath, []byte(line), 0600)
+		if err != nil {
+			return fmt.Errorf("Failed setting %q in %q: %w", line, ioMaxPath, err)
+		}
+	}
+
+	return nil
+}
@@ -57,6 +57,12 @@ func (c *Config) BackupsCompressionAlgorithm() string {
 	return c.m.GetString("backups.compression_algorithm")
 }
 
+// BackupsS3 returns the settings of the S3 endpoint backups are pushed to.
+// The secret key is stored as a server secret and isn't part of the configuration.
+func (c *Config) BackupsS3() (endpoint string, region string, accessKey string, caCert string) {
+	return c.m.GetString("backups.s3.endpoint"), c.m.GetString("backups.s3.region"), c.m.GetString("backups.s3.access_key"), c.m.GetString("backups.s3.ca_cert")
+}
+
 // MetricsAuthentication checks whether metrics API requires authentication.
 func (c *Config) MetricsAuthentication() bool {
 	return c.m.GetBool("core.metrics_authentication")
@@ -213,6 +219,18 @@ func (c *Config) ACME() (domain string, email string, caURL string, agreeTOS boo
 	return c.m.GetString("acme.domain"), c.m.GetString("acme.email"), c.m.GetString("acme.ca_url"), c.m.GetBool("acme.agree_tos")
 }
 
+// ACMEChallenge returns the ACME challenge type along with the DNS-01 provider and its settings.
+// The keys of the provider settings have the "acme.provider." prefix removed. The TSIG secret isn't included
+// as it is stored as a server secret.
+func (c *Config) ACMEChallenge() (challenge string, provider string, providerConfig map[string]string) {
+	providerConfig = map[string]string{}
+	for _, key := range []string{"nameserver", "zone", "tsig_key", "tsig_algorithm"} {
+		providerConfig[key] = c.m.GetString("acme.provider." + key)
+	}
+
+	return c.m.GetString("acme.challenge"), c.m.GetString("acme.provider"), providerConfig
+}
+
 // ClusterJoinTokenExpiry returns the cluster join token expiry.
 func (c *Config) ClusterJoinTokenExpiry() string {
 	return c.m.GetString("cluster.join_token_expiry")
@@ -273,6 +291,11 @@ func (c *Config) ClusterHealingThreshold() time.Duration {
 	return healingThreshold
 }
 
+// ClusterScheduler returns the strategy used to select the cluster member for an instance.
+func (c *Config) ClusterScheduler() string {
+	return c.m.GetString("cluster.scheduler")
+}
+
 // Dump current configuration keys and their values. Keys with values matching
 // their defaults are omitted.
 func (c *Config) Dump() map[string]string {
@@ -373,6 +396,67 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Agree to ACME terms of service
 		"acme.agree_tos": {Type: config.Bool, Default: "false"},
 
+		// lxdmeta:generate(entities=server; group=acme; key=acme.challenge)
+		// Possible values are `HTTP-01` and `DNS-01`.
+		// The `DNS-01` challenge requires {config:option}`server-acme:acme.provider` to be set and is needed for wildcard domains.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `HTTP-01`
+		//  shortdesc: ACME challenge type
+		"acme.challenge": {Default: "HTTP-01", Validator: validate.Optional(validate.IsOneOf("HTTP-01", "DNS-01"))},
+
+		// lxdmeta:generate(entities=server; group=acme; key=acme.provider)
+		// Possible values are `rfc2136` (dynamic DNS update sent to {config:option}`server-acme:acme.provider.nameserver`)
+		// and `network-zone` (record added to the LXD network zone set in {config:option}`server-acme:acme.provider.zone`).
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: DNS-01 challenge provider
+		"acme.provider": {Validator: validate.Optional(validate.IsOneOf("rfc2136", "network-zone"))},
+
+		// lxdmeta:generate(entities=server; group=acme; key=acme.provider.nameserver)
+		// Specify the address, with an optional port, of the authoritative DNS server accepting dynamic updates.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: DNS server for the `rfc2136` provider
+		"acme.provider.nameserver": {},
+
+		// lxdmeta:generate(entities=server; group=acme; key=acme.provider.zone)
+		// For the `rfc2136` provider, this is the DNS zone to update. If not set, it is looked up from the domain.
+		// For the `network-zone` provider, this is the name of the network zone holding the `_acme-challenge` record.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: DNS zone in which the challenge record is published
+		"acme.provider.zone": {},
+
+		// lxdmeta:generate(entities=server; group=acme; key=acme.provider.tsig_key)
+		//
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Name of the TSIG key for the `rfc2136` provider
+		"acme.provider.tsig_key": {},
+
+		// lxdmeta:generate(entities=server; group=acme; key=acme.provider.tsig_secret)
+		// The secret is stored as a server secret and is never returned by the API.
+		// Set it to an empty value to remove it.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Base64-encoded TSIG secret for the `rfc2136` provider
+
+		// lxdmeta:generate(entities=server; group=acme; key=acme.provider.tsig_algorithm)
+		// Possible values are `hmac-sha1`, `hmac-sha224`, `hmac-sha256`, `hmac-sha384`, and `hmac-sha512`.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `hmac-sha256`
+		//  shortdesc: TSIG algorithm for the `rfc2136` provider
+		"acme.provider.tsig_algorithm": {Default: "hmac-sha256", Validator: validate.Optional(validate.IsOneOf("hmac-sha1", "hmac-sha224", "hmac-sha256", "hmac-sha384", "hmac-sha512"))},
+
 		// lxdmeta:generate(entities=server; group=miscellaneous; key=backups.compression_algorithm)
 		// Possible values are `bzip2`, `gzip`, `lzma`, `xz`, or `none`.
 		// ---
@@ -382,6 +466,48 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Compression algorithm to use for backups
 		"backups.compression_algorithm": {Default: "gzip", Validator: validate.IsCompressionAlgorithm},
 
+		// lxdmeta:generate(entities=server; group=backups; key=backups.s3.endpoint)
+		// Specify the protocol, name or IP and port of the S3 compatible endpoint, for example `https://s3.example.com`.
+		// Backups are pushed to and imported from objects of this endpoint using path-style requests.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: URL of the S3 endpoint for backups
+		"backups.s3.endpoint": {Validator: validate.Optional(validate.IsRequestURL)},
+
+		// lxdmeta:generate(entities=server; group=backups; key=backups.s3.region)
+		//
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `us-east-1`
+		//  shortdesc: Region of the S3 endpoint for backups
+		"backups.s3.region": {Default: "us-east-1"},
+
+		// lxdmeta:generate(entities=server; group=backups; key=backups.s3.access_key)
+		//
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Access key used to authenticate with the S3 endpoint for backups
+		"backups.s3.access_key": {},
+
+		// lxdmeta:generate(entities=server; group=backups; key=backups.s3.secret_key)
+		// The secret key is stored as a server secret and is never returned by the API.
+		// Set it to an empty value to remove it.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Secret key used to authenticate with the S3 endpoint for backups
+
+		// lxdmeta:generate(entities=server; group=backups; key=backups.s3.ca_cert)
+		// Specify the certificate of the S3 endpoint, or of the CA that issued it, if it isn't trusted by the system.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Certificate of the S3 endpoint for backups
+		"backups.s3.ca_cert": {},
+
 		// lxdmeta:generate(entities=server; group=cluster; key=cluster.offline_threshold)
 		// Specify the number of seconds after which an unresponsive member is considered offline.
 		// ---
@@ -410,6 +536,19 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Threshold when to evacuate an offline cluster member
 		"cluster.healing_threshold": {Type: config.Int64, Default: "0"},
 
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.scheduler)
+		// Specify how a cluster member is selected for instances that are not targeted at a specific member.
+		// This applies to instance creation, migration, evacuation and cluster healing.
+		// Possible values are `instances` (select the member with the least instances) and `resources`
+		// (select the member with the most free memory, CPU and root disk pool space, excluding members
+		// that cannot fit the instance's limits, root disk size, GPU and PCI devices).
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `instances`
+		//  shortdesc: Strategy used to select the cluster member for an instance
+		"cluster.scheduler": {Default: "instances", Validator: validate.IsOneOf("instances", "resources")},
+
 		// lxdmeta:generate(entities=server; group=cluster; key=cluster.join_token_expiry)
 		//
 		// ---
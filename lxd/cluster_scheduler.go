@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/placement"
+	"github.com/canonical/lxd/lxd/resources"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// clusterSelectMember selects the cluster member to place an instance on among the candidates,
+// using the strategy configured in "cluster.scheduler".
+// The requirements are only used by the "resources" scheduler.
+func clusterSelectMember(ctx context.Context, s *state.State, candidates []db.NodeInfo, req *placement.Requirements) (*db.NodeInfo, error) {
+	if req != nil && s.GlobalConfig.ClusterScheduler() == placement.SchedulerResources {
+		memberResources := clusterMemberResources(ctx, s, candidates, req.RootDiskPool)
+
+		return placement.SelectByResources(candidates, *req, memberResources)
+	}
+
+	var member *db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		member, err = tx.GetNodeWithLeastInstances(ctx, candidates)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// clusterMemberResources fetches the resources of the given cluster members and the space of the storage pool (if not empty).
+// Members whose resources cannot be retrieved are left out of the result.
+func clusterMemberResources(ctx context.Context, s *state.State, members []db.NodeInfo, poolName string) map[string]placement.MemberResources {
+	memberResources := make(map[string]placement.MemberResources, len(members))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, member := range members {
+		wg.Go(func() {
+			res, err := clusterGetMemberResources(ctx, s, member, poolName)
+			if err != nil {
+				logger.Warn("Failed getting cluster member resources", logger.Ctx{"member": member.Name, "err": err})
+				return
+			}
+
+			mu.Lock()
+			memberResources[member.Name] = *res
+			mu.Unlock()
+		})
+	}
+
+	wg.Wait()
+
+	err := clusterMemberAllocations(ctx, s, memberResources)
+	if err != nil {
+		logger.Warn("Failed getting resources allocated to instances", logger.Ctx{"err": err})
+	}
+
+	return memberResources
+}
+
+// clusterMemberAllocations records the resources allocated to the instances located on the given cluster members.
+func clusterMemberAllocations(ctx context.Context, s *state.State, memberResources map[string]placement.MemberResources) error {
+	globalConfig := s.GlobalConfig.Dump()
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			res, ok := memberResources[inst.Node]
+			if !ok {
+				return nil
+			}
+
+			config := instancetype.ExpandInstanceConfig(globalConfig, inst.Config, inst.Profiles)
+			devices := instancetype.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
+
+			err := res.AddInstance(inst.Type, config, devices.CloneNative())
+			if err != nil {
+				// Instances with invalid limits don't hold any resources.
+				logger.Debug("Failed getting resources allocated to instance", logger.Ctx{"project": inst.Project, "instance": inst.Name, "err": err})
+				return nil
+			}
+
+			memberResources[inst.Node] = res
+
+			return nil
+		})
+	})
+}
+
+// clusterGetMemberResources returns the resources of a single cluster member.
+func clusterGetMemberResources(ctx context.Context, s *state.State, member db.NodeInfo, poolName string) (*placement.MemberResources, error) {
+	res := &placement.MemberResources{Pools: map[string]*api.ResourcesStoragePool{}}
+
+	// Get the local resources directly.
+	if member.Name == s.ServerName {
+		var err error
+		res.Resources, err = resources.GetResources()
+		if err != nil {
+			return nil, err
+		}
+
+		if poolName != "" {
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				return nil, err
+			}
+
+			res.Pools[poolName], err = pool.GetResources()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return res, nil
+	}
+
+	client, err := cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Resources, err = client.GetServerResources()
+	if err != nil {
+		return nil, err
+	}
+
+	if poolName != "" {
+		res.Pools[poolName], err = client.GetStoragePoolResources(poolName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
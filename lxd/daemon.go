@@ -1536,6 +1536,7 @@ func (d *Daemon) init() error {
 		ClusterAddress:       localClusterAddress,
 		DebugAddress:         debugAddress,
 		MetricsServer:        metricsServer(d),
+		StorageBucketsServer: storageBucketsServer(d),
 		VsockServer:          vSockServer(d),
 		VsockSupport:         false,
 	}
@@ -1897,6 +1898,14 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	storageBucketsAddress := d.localConfig.StorageBucketsAddress()
+	if storageBucketsAddress != "" {
+		err = d.endpoints.UpStorageBuckets(storageBucketsAddress)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Apply all patches that need to be run after networks are initialised.
 	err = patchesApply(d, patchPostNetworks)
 	if err != nil {
@@ -2014,12 +2023,27 @@ func (d *Daemon) init() error {
 		// Remove expired backups (hourly)
 		d.tasks.Add(pruneExpiredBackupsTask(d.State))
 
+		// Create scheduled backups of instances and custom volumes (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateScheduledBackupsTask(d.State))
+
 		// Prune expired instance snapshots and take snapshot of instances (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateInstanceSnapshotsTask(d.State))
 
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d.State))
 
+		// Check the health of storage pools (every 5 minutes)
+		d.tasks.Add(storagePoolsHealthCheckTask(d.State))
+
+		// Scrub storage pools (minutely check of configurable cron expression)
+		d.tasks.Add(autoScrubStoragePoolsTask(d.State))
+
+		// Refresh custom volume mirrors (minutely check of configurable cron expression)
+		d.tasks.Add(volumeMirrorsRefreshTask(d.State))
+
+		// Refresh the addresses of DNS names used in network ACL rules (every 30s)
+		d.tasks.Add(networkACLFQDNRefreshTask(d.State))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d.State))
 
@@ -2152,6 +2176,9 @@ func (d *Daemon) startClusterTasks() {
 	// Remove expired OIDC sessions
 	d.clusterTasks.Add(pruneExpiredOIDCSessionsTask(d.State))
 
+	// Roll over the DNSSEC keys of network zones (daily).
+	d.clusterTasks.Add(rolloverNetworkZoneKeysTask(d.State))
+
 	// Refresh cluster link volatile addresses (daily).
 	d.clusterTasks.Add(autoRefreshClusterLinkVolatileAddressesTask(d.State))
 
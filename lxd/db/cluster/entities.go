@@ -112,6 +112,7 @@ var entityTypes = map[entity.Type]entityTypeDBInfo{
 	entity.TypePlacementGroup:        entityTypePlacementGroup{},
 	entity.TypeClusterLink:           entityTypeClusterLink{},
 	entity.TypeReplicator:            entityTypeReplicator{},
+	entity.TypeNetworkAddressSet:     entityTypeNetworkAddressSet{},
 }
 
 const (
@@ -144,6 +145,7 @@ const (
 	entityTypeCodePlacementGroup        int64 = 25
 	entityTypeCodeClusterLink           int64 = 26
 	entityTypeCodeReplicator            int64 = 27
+	entityTypeCodeNetworkAddressSet     int64 = 28
 )
 
 var entityTypeByCode = map[int64]EntityType{
@@ -0,0 +1,39 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/canonical/lxd/lxd/db/query"
+)
+
+// entityTypeNetworkAddressSet implements entityTypeDBInfo for a NetworkAddressSet.
+type entityTypeNetworkAddressSet struct {
+	entityTypeCommon
+}
+
+func (e entityTypeNetworkAddressSet) code() int64 {
+	return entityTypeCodeNetworkAddressSet
+}
+
+func (e entityTypeNetworkAddressSet) allURLsQuery() string {
+	return fmt.Sprintf(`
+SELECT %d, networks_address_sets.id, projects.name, '', json_array(networks_address_sets.name)
+FROM networks_address_sets
+JOIN projects ON networks_address_sets.project_id = projects.id`, e.code())
+}
+
+func (e entityTypeNetworkAddressSet) urlsByProjectQuery() string {
+	return e.allURLsQuery() + " WHERE projects.name = ?"
+}
+
+func (e entityTypeNetworkAddressSet) urlsByIDsQuery(ids ...int64) string {
+	return e.allURLsQuery() + " WHERE networks_address_sets.id IN " + query.IntParams(ids...)
+}
+
+func (e entityTypeNetworkAddressSet) idFromURLQuery() string {
+	return projectEntityIDFromURLQuery("networks_address_sets")
+}
+
+func (e entityTypeNetworkAddressSet) onDeleteTriggerSQL() (name string, sql string) {
+	return standardOnDeleteTriggerSQL("on_network_address_set_delete", "networks_address_sets", e.code())
+}
@@ -386,6 +386,100 @@ func (i InstancesProfilesRow) UpdateStmt() string {
 	return "UPDATE instances_profiles SET instance_id = ?, profile_id = ?, apply_order = ? "
 }
 
+// TableName returns the table name for [NetworkAddressSet] entities.
+func (n NetworkAddressSet) TableName() string {
+	return "networks_address_sets"
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (n NetworkAddressSet) APIName() string {
+	return n.Row.APIName()
+}
+
+// SelectColumns returns a slice of column names for [NetworkAddressSet] entities.
+func (n NetworkAddressSet) SelectColumns() []string {
+	return []string{
+		"networks_address_sets.id",
+		"networks_address_sets.project_id",
+		"networks_address_sets.name",
+		"networks_address_sets.description",
+		"networks_address_sets.addresses",
+		"projects.name",
+	}
+}
+
+// Joins returns a slice of join expressions for [NetworkAddressSet].
+func (n NetworkAddressSet) Joins() []string {
+	return []string{
+		"JOIN projects ON networks_address_sets.project_id = projects.id",
+	}
+}
+
+// ScanArgs implements [query.ScanArger] for [NetworkAddressSet].
+// This returns references to struct fields in definition order.
+func (n *NetworkAddressSet) ScanArgs() []any {
+	return []any{&n.Row.ID, &n.Row.ProjectID, &n.Row.Name, &n.Row.Description, &n.Row.Addresses, &n.ProjectName}
+}
+
+// TableName returns the table name for [NetworkAddressSetsRow] entities.
+func (n NetworkAddressSetsRow) TableName() string {
+	return "networks_address_sets"
+}
+
+// SelectColumns returns a slice of column names for [NetworkAddressSetsRow] entities.
+func (n NetworkAddressSetsRow) SelectColumns() []string {
+	return []string{
+		"networks_address_sets.id",
+		"networks_address_sets.project_id",
+		"networks_address_sets.name",
+		"networks_address_sets.description",
+		"networks_address_sets.addresses",
+	}
+}
+
+// Joins returns a slice of join expressions for [NetworkAddressSetsRow].
+func (n NetworkAddressSetsRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [NetworkAddressSetsRow].
+// This returns references to struct fields in definition order.
+func (n *NetworkAddressSetsRow) ScanArgs() []any {
+	return []any{&n.ID, &n.ProjectID, &n.Name, &n.Description, &n.Addresses}
+}
+
+// CreateValues returns a list of values from [NetworkAddressSetsRow] entities matching the bind arguments in [CreateStmt].
+func (n NetworkAddressSetsRow) CreateValues() []any {
+	return []any{n.ProjectID, n.Name, n.Description, n.Addresses}
+}
+
+// UpdateValues returns a list of values from [NetworkAddressSetsRow] entities matching the columns in [UpdateStmt].
+func (n NetworkAddressSetsRow) UpdateValues() []any {
+	return []any{n.ProjectID, n.Name, n.Description, n.Addresses}
+}
+
+// PKColumns returns the column names for the primary key of a [NetworkAddressSetsRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (n NetworkAddressSetsRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [NetworkAddressSetsRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (n NetworkAddressSetsRow) PKValues() []any {
+	return []any{n.ID}
+}
+
+// CreateStmt returns a query that creates a [NetworkAddressSetsRow] entity.
+func (n NetworkAddressSetsRow) CreateStmt() string {
+	return "INSERT INTO networks_address_sets (project_id, name, description, addresses) VALUES (?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [NetworkAddressSetsRow] by primary key.
+func (n NetworkAddressSetsRow) UpdateStmt() string {
+	return "UPDATE networks_address_sets SET project_id = ?, name = ?, description = ?, addresses = ? "
+}
+
 // TableName returns the table name for [NetworksLoadBalancerPool] entities.
 func (n NetworksLoadBalancerPool) TableName() string {
 	return "networks_load_balancer_pools"
@@ -1032,3 +1126,126 @@ func (r ReplicatorRow) CreateStmt() string {
 func (r ReplicatorRow) UpdateStmt() string {
 	return "UPDATE replicators SET name = ?, project_id = ?, description = ?, last_run_date = ?, last_run_status = ? "
 }
+
+// TableName returns the table name for [ReplicatorRun] entities.
+func (r ReplicatorRun) TableName() string {
+	return "replicators_runs"
+}
+
+// SelectColumns returns a slice of column names for [ReplicatorRun] entities.
+func (r ReplicatorRun) SelectColumns() []string {
+	return []string{
+		"replicators_runs.id",
+		"replicators_runs.replicator_id",
+		"replicators_runs.restore",
+		"replicators_runs.started_at",
+		"replicators_runs.finished_at",
+		"replicators_runs.status",
+	}
+}
+
+// Joins returns a slice of join expressions for [ReplicatorRun].
+func (r ReplicatorRun) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [ReplicatorRun].
+// This returns references to struct fields in definition order.
+func (r *ReplicatorRun) ScanArgs() []any {
+	return []any{&r.ID, &r.ReplicatorID, &r.Restore, &r.StartedAt, &r.FinishedAt, &r.Status}
+}
+
+// CreateValues returns a list of values from [ReplicatorRun] entities matching the bind arguments in [CreateStmt].
+func (r ReplicatorRun) CreateValues() []any {
+	return []any{r.ReplicatorID, r.Restore, r.StartedAt, r.FinishedAt, r.Status}
+}
+
+// UpdateValues returns a list of values from [ReplicatorRun] entities matching the columns in [UpdateStmt].
+func (r ReplicatorRun) UpdateValues() []any {
+	return []any{r.ReplicatorID, r.Restore, r.StartedAt, r.FinishedAt, r.Status}
+}
+
+// PKColumns returns the column names for the primary key of a [ReplicatorRun] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (r ReplicatorRun) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [ReplicatorRun] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (r ReplicatorRun) PKValues() []any {
+	return []any{r.ID}
+}
+
+// CreateStmt returns a query that creates a [ReplicatorRun] entity.
+func (r ReplicatorRun) CreateStmt() string {
+	return "INSERT INTO replicators_runs (replicator_id, restore, started_at, finished_at, status) VALUES (?, ?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [ReplicatorRun] by primary key.
+func (r ReplicatorRun) UpdateStmt() string {
+	return "UPDATE replicators_runs SET replicator_id = ?, restore = ?, started_at = ?, finished_at = ?, status = ? "
+}
+
+// TableName returns the table name for [ReplicatorRunInstance] entities.
+func (r ReplicatorRunInstance) TableName() string {
+	return "replicators_runs_instances"
+}
+
+// SelectColumns returns a slice of column names for [ReplicatorRunInstance] entities.
+func (r ReplicatorRunInstance) SelectColumns() []string {
+	return []string{
+		"replicators_runs_instances.id",
+		"replicators_runs_instances.replicator_run_id",
+		"replicators_runs_instances.instance_name",
+		"replicators_runs_instances.status",
+		"replicators_runs_instances.error",
+		"replicators_runs_instances.snapshot_name",
+		"replicators_runs_instances.started_at",
+		"replicators_runs_instances.finished_at",
+		"replicators_runs_instances.bytes_transferred",
+	}
+}
+
+// Joins returns a slice of join expressions for [ReplicatorRunInstance].
+func (r ReplicatorRunInstance) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [ReplicatorRunInstance].
+// This returns references to struct fields in definition order.
+func (r *ReplicatorRunInstance) ScanArgs() []any {
+	return []any{&r.ID, &r.ReplicatorRunID, &r.InstanceName, &r.Status, &r.Error, &r.SnapshotName, &r.StartedAt, &r.FinishedAt, &r.BytesTransferred}
+}
+
+// CreateValues returns a list of values from [ReplicatorRunInstance] entities matching the bind arguments in [CreateStmt].
+func (r ReplicatorRunInstance) CreateValues() []any {
+	return []any{r.ReplicatorRunID, r.InstanceName, r.Status, r.Error, r.SnapshotName, r.StartedAt, r.FinishedAt, r.BytesTransferred}
+}
+
+// UpdateValues returns a list of values from [ReplicatorRunInstance] entities matching the columns in [UpdateStmt].
+func (r ReplicatorRunInstance) UpdateValues() []any {
+	return []any{r.ReplicatorRunID, r.InstanceName, r.Status, r.Error, r.SnapshotName, r.StartedAt, r.FinishedAt, r.BytesTransferred}
+}
+
+// PKColumns returns the column names for the primary key of a [ReplicatorRunInstance] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (r ReplicatorRunInstance) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [ReplicatorRunInstance] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (r ReplicatorRunInstance) PKValues() []any {
+	return []any{r.ID}
+}
+
+// CreateStmt returns a query that creates a [ReplicatorRunInstance] entity.
+func (r ReplicatorRunInstance) CreateStmt() string {
+	return "INSERT INTO replicators_runs_instances (replicator_run_id, instance_name, status, error, snapshot_name, started_at, finished_at, bytes_transferred) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [ReplicatorRunInstance] by primary key.
+func (r ReplicatorRunInstance) UpdateStmt() string {
+	return "UPDATE replicators_runs_instances SET replicator_run_id = ?, instance_name = ?, status = ?, error = ?, snapshot_name = ?, started_at = ?, finished_at = ?, bytes_transferred = ? "
+}
@@ -0,0 +1,264 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// NetworkAddressSetAddresses represents the list of addresses of a network address set.
+// It is stored as a JSON encoded list in the database.
+type NetworkAddressSetAddresses []string
+
+// Value implements [driver.Valuer] for NetworkAddressSetAddresses.
+func (a NetworkAddressSetAddresses) Value() (driver.Value, error) {
+	if a == nil {
+		a = NetworkAddressSetAddresses{}
+	}
+
+	out, err := json.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf("Failed marshalling address set addresses: %w", err)
+	}
+
+	return string(out), nil
+}
+
+// ScanText implements [query.TextScanner] for NetworkAddressSetAddresses to simplify the [sql.Scanner] implementation.
+func (a *NetworkAddressSetAddresses) ScanText(str string) error {
+	var addresses NetworkAddressSetAddresses
+	err := json.Unmarshal([]byte(str), &addresses)
+	if err != nil {
+		return fmt.Errorf("Failed unmarshalling address set addresses: %w", err)
+	}
+
+	*a = addresses
+	return nil
+}
+
+// Scan implements [sql.Scanner] for NetworkAddressSetAddresses.
+func (a *NetworkAddressSetAddresses) Scan(value any) error {
+	return query.ScanValue(value, a, false)
+}
+
+// NetworkAddressSetsRow represents a single row of the networks_address_sets table.
+// db:model networks_address_sets
+type NetworkAddressSetsRow struct {
+	ID          int64                      `db:"id"`
+	ProjectID   int64                      `db:"project_id"`
+	Name        string                     `db:"name"`
+	Description string                     `db:"description"`
+	Addresses   NetworkAddressSetAddresses `db:"addresses"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (NetworkAddressSetsRow) APIName() string {
+	return "Network address set"
+}
+
+// NetworkAddressSet contains [NetworkAddressSetsRow] with additional joins.
+// db:model networks_address_sets
+type NetworkAddressSet struct {
+	Row NetworkAddressSetsRow
+
+	// db:join JOIN projects ON networks_address_sets.project_id = projects.id
+	ProjectName string `db:"projects.name"`
+}
+
+// NetworkAddressSetFilter contains fields that can be used to filter results when getting network address sets.
+type NetworkAddressSetFilter struct {
+	Project *string
+	Name    *string
+}
+
+// NetworkAddressSetsConfigStore returns a [query.EntityConfigStore] for network address sets.
+func NetworkAddressSetsConfigStore() *query.EntityConfigStore {
+	return &query.EntityConfigStore{
+		EntityTable:               "networks_address_sets",
+		ConfigTable:               "networks_address_sets_config",
+		ConfigTableEntityIDColumn: "network_address_set_id",
+	}
+}
+
+// GetNetworkAddressSet gets a [NetworkAddressSet] by name and project.
+func GetNetworkAddressSet(ctx context.Context, tx *sql.Tx, name string, projectName string) (*NetworkAddressSet, error) {
+	addressSet, err := query.SelectOne[NetworkAddressSet](ctx, tx, "WHERE networks_address_sets.name = ? AND projects.name = ?", name, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return addressSet, nil
+}
+
+// GetNetworkAddressSets gets all network address sets in the given project.
+func GetNetworkAddressSets(ctx context.Context, tx *sql.Tx, projectName string) ([]NetworkAddressSet, error) {
+	return query.Select[NetworkAddressSet](ctx, tx, "WHERE projects.name = ? ORDER BY networks_address_sets.name", projectName)
+}
+
+// GetNetworkAddressSetsAndURLs queries for all network address sets and then applies the given filter to the result.
+// This is useful when filtering by address sets the caller is able to view.
+// The filter must return true to include an entry, and false to reject an entry.
+// A slice of (filtered) address set URLs is also returned for convenience.
+// If the project name argument is non-nil, only address sets in that project are returned.
+// If the project name is nil, address sets from all projects are returned.
+func GetNetworkAddressSetsAndURLs(ctx context.Context, tx *sql.Tx, projectName *string, filter func(addressSet NetworkAddressSet) bool) ([]NetworkAddressSet, []string, error) {
+	var args []any
+	var b strings.Builder
+	if projectName == nil {
+		b.WriteString("ORDER BY projects.name, ")
+	} else {
+		b.WriteString("WHERE projects.name = ? ORDER BY ")
+		args = append(args, *projectName)
+	}
+
+	b.WriteString("networks_address_sets.name")
+	clause := b.String()
+
+	var addressSets []NetworkAddressSet
+	var addressSetURLs []string
+	err := query.SelectFunc[NetworkAddressSet](ctx, tx, clause, func(addressSet NetworkAddressSet) error {
+		if filter != nil && !filter(addressSet) {
+			return nil
+		}
+
+		addressSets = append(addressSets, addressSet)
+		addressSetURLs = append(addressSetURLs, entity.NetworkAddressSetURL(addressSet.ProjectName, addressSet.Row.Name).String())
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return addressSets, addressSetURLs, nil
+}
+
+// ToAPI converts the [NetworkAddressSet] to an [api.NetworkAddressSet].
+func (n *NetworkAddressSet) ToAPI(configs map[int64]map[string]string) *api.NetworkAddressSet {
+	config := configs[n.Row.ID]
+	if config == nil {
+		config = map[string]string{}
+	}
+
+	addresses := []string(n.Row.Addresses)
+	if addresses == nil {
+		addresses = []string{}
+	}
+
+	return &api.NetworkAddressSet{
+		Name:        n.Row.Name,
+		Description: n.Row.Description,
+		Addresses:   addresses,
+		Project:     n.ProjectName,
+		Config:      config,
+	}
+}
+
+// NetworkAddressSetSubject returns the name of the address set referenced by the given network ACL rule
+// subject ("$<name>"), and whether the subject is an address set reference.
+func NetworkAddressSetSubject(subject string) (string, bool) {
+	name, found := strings.CutPrefix(subject, "$")
+	if !found || name == "" {
+		return "", false
+	}
+
+	return name, true
+}
+
+// GetNetworkAddressSetUsedBy returns a list of URLs of entities that reference the network address set with the given name and project.
+func GetNetworkAddressSetUsedBy(ctx context.Context, tx *sql.Tx, projectName string, addressSetName string, firstOnly bool) ([]string, error) {
+	usedByMap, err := GetNetworkAddressSetsUsedBy(ctx, tx, NetworkAddressSetFilter{
+		Project: &projectName,
+		Name:    &addressSetName,
+	}, firstOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return usedByMap[projectName][addressSetName], nil
+}
+
+// GetNetworkAddressSetsUsedBy returns a map of project name to map of network address set name (matching the given
+// filter) to list of URLs of network ACLs whose rules reference the address set.
+func GetNetworkAddressSetsUsedBy(ctx context.Context, tx *sql.Tx, filter NetworkAddressSetFilter, firstOnly bool) (map[string]map[string][]string, error) {
+	var args []any
+	urls := make(map[string]map[string][]string)
+
+	q := `SELECT networks_acls.name, projects.name, networks_acls.ingress, networks_acls.egress FROM networks_acls
+JOIN projects ON networks_acls.project_id = projects.id`
+
+	if filter.Project != nil {
+		q += " WHERE projects.name = ?"
+		args = append(args, *filter.Project)
+
+		// Ensure returned map is populated for filter keys even if empty
+		// so that the caller can lookup results without worrying if the map is nil.
+		urls[*filter.Project] = make(map[string][]string)
+	}
+
+	q += " ORDER BY projects.name, networks_acls.name"
+
+	found := false
+	err := query.Scan(ctx, tx, q, func(scan func(dest ...any) error) error {
+		if firstOnly && found {
+			return nil
+		}
+
+		var aclName, projectName, ingressJSON, egressJSON string
+		err := scan(&aclName, &projectName, &ingressJSON, &egressJSON)
+		if err != nil {
+			return err
+		}
+
+		var rules []api.NetworkACLRule
+		for _, rulesJSON := range []string{ingressJSON, egressJSON} {
+			var directionRules []api.NetworkACLRule
+			err = json.Unmarshal([]byte(rulesJSON), &directionRules)
+			if err != nil {
+				return fmt.Errorf("Failed unmarshalling network ACL %q rules: %w", aclName, err)
+			}
+
+			rules = append(rules, directionRules...)
+		}
+
+		// Find the address sets referenced by the rules of the ACL, counting each ACL only once per address set.
+		referenced := make(map[string]struct{})
+		for _, rule := range rules {
+			for _, subject := range append(shared.SplitNTrimSpace(rule.Source, ",", -1, true), shared.SplitNTrimSpace(rule.Destination, ",", -1, true)...) {
+				addressSetName, isAddressSet := NetworkAddressSetSubject(subject)
+				if !isAddressSet || (filter.Name != nil && addressSetName != *filter.Name) {
+					continue
+				}
+
+				_, seen := referenced[addressSetName]
+				if seen {
+					continue
+				}
+
+				referenced[addressSetName] = struct{}{}
+
+				_, ok := urls[projectName]
+				if !ok {
+					urls[projectName] = make(map[string][]string)
+				}
+
+				urls[projectName][addressSetName] = append(urls[projectName][addressSetName], api.NewURL().Project(projectName).Path("1.0", "network-acls", aclName).String())
+				found = true
+			}
+		}
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed finding references to network address set: %w", err)
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,123 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// ReplicatorRunHistoryLimit is the number of runs kept in the history of each replicator.
+const ReplicatorRunHistoryLimit = 50
+
+// ReplicatorRun represents a single row of the replicators_runs table.
+// db:model replicators_runs
+type ReplicatorRun struct {
+	ID           int64     `db:"id"`
+	ReplicatorID int64     `db:"replicator_id"`
+	Restore      bool      `db:"restore"`
+	StartedAt    time.Time `db:"started_at"`
+	FinishedAt   time.Time `db:"finished_at"`
+	Status       string    `db:"status"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (ReplicatorRun) APIName() string {
+	return "Replicator run"
+}
+
+// ReplicatorRunInstance represents a single row of the replicators_runs_instances table.
+// It records the result of replicating a single instance during a replicator run.
+// db:model replicators_runs_instances
+type ReplicatorRunInstance struct {
+	ID               int64     `db:"id"`
+	ReplicatorRunID  int64     `db:"replicator_run_id"`
+	InstanceName     string    `db:"instance_name"`
+	Status           string    `db:"status"`
+	Error            string    `db:"error"`
+	SnapshotName     string    `db:"snapshot_name"`
+	StartedAt        time.Time `db:"started_at"`
+	FinishedAt       time.Time `db:"finished_at"`
+	BytesTransferred int64     `db:"bytes_transferred"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (ReplicatorRunInstance) APIName() string {
+	return "Replicator run instance"
+}
+
+// ToAPI converts the [ReplicatorRun] and the given instance results to an [api.ReplicatorRun].
+func (r *ReplicatorRun) ToAPI(instances []ReplicatorRunInstance) api.ReplicatorRun {
+	run := api.ReplicatorRun{
+		Restore:    r.Restore,
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+		Status:     r.Status,
+		Instances:  make([]api.ReplicatorRunInstance, 0, len(instances)),
+	}
+
+	for _, inst := range instances {
+		run.BytesTransferred += inst.BytesTransferred
+		run.Instances = append(run.Instances, api.ReplicatorRunInstance{
+			Name:             inst.InstanceName,
+			Status:           inst.Status,
+			Error:            inst.Error,
+			Snapshot:         inst.SnapshotName,
+			StartedAt:        inst.StartedAt,
+			FinishedAt:       inst.FinishedAt,
+			BytesTransferred: inst.BytesTransferred,
+		})
+	}
+
+	return run
+}
+
+// CreateReplicatorRun records a finished replicator run along with its per-instance results.
+// Only the most recent [ReplicatorRunHistoryLimit] runs of the replicator are kept.
+func CreateReplicatorRun(ctx context.Context, tx *sql.Tx, run ReplicatorRun, instances []ReplicatorRunInstance) (int64, error) {
+	runID, err := query.Create(ctx, tx, run)
+	if err != nil {
+		return -1, err
+	}
+
+	for _, inst := range instances {
+		inst.ReplicatorRunID = runID
+		_, err = query.Create(ctx, tx, inst)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+DELETE FROM replicators_runs WHERE replicator_id = ? AND id NOT IN (
+	SELECT id FROM replicators_runs WHERE replicator_id = ? ORDER BY started_at DESC, id DESC LIMIT ?
+)`, run.ReplicatorID, run.ReplicatorID, ReplicatorRunHistoryLimit)
+	if err != nil {
+		return -1, fmt.Errorf("Failed pruning replicator run history: %w", err)
+	}
+
+	return runID, nil
+}
+
+// GetReplicatorRuns returns the recorded runs of the replicator with the given ID, newest first,
+// along with the per-instance results of each run keyed by run ID.
+func GetReplicatorRuns(ctx context.Context, tx *sql.Tx, replicatorID int64) ([]ReplicatorRun, map[int64][]ReplicatorRunInstance, error) {
+	runs, err := query.Select[ReplicatorRun](ctx, tx, "WHERE replicators_runs.replicator_id = ? ORDER BY replicators_runs.started_at DESC, replicators_runs.id DESC", replicatorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed loading replicator runs: %w", err)
+	}
+
+	instances := make(map[int64][]ReplicatorRunInstance, len(runs))
+	err = query.SelectFunc[ReplicatorRunInstance](ctx, tx, "WHERE replicators_runs_instances.replicator_run_id IN (SELECT id FROM replicators_runs WHERE replicator_id = ?) ORDER BY replicators_runs_instances.instance_name", func(inst ReplicatorRunInstance) error {
+		instances[inst.ReplicatorRunID] = append(instances[inst.ReplicatorRunID], inst)
+		return nil
+	}, replicatorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed loading replicator run instances: %w", err)
+	}
+
+	return runs, instances, nil
+}
@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestReplicatorRuns(t *testing.T) {
+	db := newDB(t)
+	_, err := db.Exec("PRAGMA foreign_keys=ON")
+	require.NoError(t, err)
+
+	doTx := func(f func(ctx context.Context, tx *sql.Tx)) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+
+		f(ctx, tx)
+		require.NoError(t, tx.Commit())
+	}
+
+	var replicatorID int64
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		_, err := tx.ExecContext(ctx, "INSERT INTO projects (name, description, replica_mode) VALUES ('p1', '', 'leader')")
+		require.NoError(t, err)
+
+		replicatorID, err = CreateReplicator(ctx, tx, ReplicatorRow{Name: "r1", ProjectID: 1})
+		require.NoError(t, err)
+	})
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i := range ReplicatorRunHistoryLimit + 2 {
+		startedAt := start.Add(time.Duration(i) * time.Hour)
+		doTx(func(ctx context.Context, tx *sql.Tx) {
+			_, err := CreateReplicatorRun(ctx, tx, ReplicatorRun{
+				ReplicatorID: replicatorID,
+				StartedAt:    startedAt,
+				FinishedAt:   startedAt.Add(time.Minute),
+				Status:       api.ReplicatorStatusCompleted,
+			}, []ReplicatorRunInstance{
+				{InstanceName: "c2", Status: api.ReplicatorStatusFailed, Error: "boom", StartedAt: startedAt, FinishedAt: startedAt.Add(time.Minute)},
+				{InstanceName: "c1", Status: api.ReplicatorStatusCompleted, SnapshotName: "snap0", StartedAt: startedAt, FinishedAt: startedAt.Add(time.Minute), BytesTransferred: 1024},
+			})
+			require.NoError(t, err)
+		})
+	}
+
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		runs, instances, err := GetReplicatorRuns(ctx, tx, replicatorID)
+		require.NoError(t, err)
+
+		// Only the most recent runs are kept, newest first.
+		require.Len(t, runs, ReplicatorRunHistoryLimit)
+		require.True(t, runs[0].StartedAt.Equal(start.Add(time.Duration(ReplicatorRunHistoryLimit+1)*time.Hour)))
+		require.True(t, runs[len(runs)-1].StartedAt.Equal(start.Add(2*time.Hour)))
+
+		// Per-instance results of pruned runs are removed too.
+		require.Len(t, instances, ReplicatorRunHistoryLimit)
+
+		apiRun := runs[0].ToAPI(instances[runs[0].ID])
+		require.Equal(t, int64(1024), apiRun.BytesTransferred)
+		require.Len(t, apiRun.Instances, 2)
+		require.Equal(t, "c1", apiRun.Instances[0].Name)
+		require.Equal(t, "snap0", apiRun.Instances[0].Snapshot)
+		require.Equal(t, "c2", apiRun.Instances[1].Name)
+		require.Equal(t, "boom", apiRun.Instances[1].Error)
+	})
+
+	// Runs are deleted along with their replicator.
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		require.NoError(t, DeleteReplicator(ctx, tx, "r1", "p1"))
+
+		count, err := query.Count(ctx, tx, "replicators_runs_instances", "")
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+	})
+}
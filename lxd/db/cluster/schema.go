@@ -316,6 +316,23 @@ CREATE TABLE "networks_acls_config" (
     UNIQUE (network_acl_id, key),
     FOREIGN KEY (network_acl_id) REFERENCES "networks_acls" (id) ON DELETE CASCADE
 );
+CREATE TABLE networks_address_sets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	project_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	addresses TEXT NOT NULL,
+	UNIQUE (project_id, name),
+	FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE TABLE networks_address_sets_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	network_address_set_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	UNIQUE (network_address_set_id, key),
+	FOREIGN KEY (network_address_set_id) REFERENCES networks_address_sets (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_config" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     network_id INTEGER NOT NULL,
@@ -439,6 +456,17 @@ CREATE TABLE "networks_zones_config" (
 	UNIQUE (network_zone_id, key),
 	FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
 );
+CREATE TABLE networks_zones_dnssec_keys (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	network_zone_id INTEGER NOT NULL,
+	flags INTEGER NOT NULL,
+	algorithm INTEGER NOT NULL,
+	public_key TEXT NOT NULL,
+	private_key TEXT NOT NULL,
+	created_at DATETIME NOT NULL,
+	activate_at DATETIME NOT NULL,
+	FOREIGN KEY (network_zone_id) REFERENCES networks_zones (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_zones_records" (
 	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
 	network_zone_id INTEGER NOT NULL,
@@ -630,6 +658,30 @@ CREATE TABLE replicators_config (
 	PRIMARY KEY (replicator_id,
     key)
 ) WITHOUT ROWID;
+CREATE TABLE replicators_runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_id INTEGER NOT NULL,
+	restore INTEGER NOT NULL DEFAULT 0,
+	started_at DATETIME NOT NULL,
+	finished_at DATETIME NOT NULL,
+	status TEXT NOT NULL,
+	FOREIGN KEY (replicator_id) REFERENCES replicators (id) ON DELETE CASCADE
+);
+CREATE TABLE replicators_runs_instances (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_run_id INTEGER NOT NULL,
+	instance_name TEXT NOT NULL,
+	status TEXT NOT NULL,
+	error TEXT NOT NULL,
+	snapshot_name TEXT NOT NULL,
+	started_at DATETIME NOT NULL,
+	finished_at DATETIME NOT NULL,
+	bytes_transferred INTEGER NOT NULL DEFAULT 0,
+	UNIQUE (replicator_run_id, instance_name),
+	FOREIGN KEY (replicator_run_id) REFERENCES replicators_runs (id) ON DELETE CASCADE
+);
+CREATE INDEX replicators_runs_replicator_id_started_at ON replicators_runs (replicator_id,
+    started_at);
 CREATE TABLE secrets (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     entity_type INTEGER NOT NULL,
@@ -645,6 +697,10 @@ CREATE UNIQUE INDEX secrets_bearer_identity_signing_key_unique ON secrets (entit
 CREATE INDEX secrets_entity_type_entity_id_type ON secrets (entity_type,
     entity_id,
     type);
+CREATE UNIQUE INDEX secrets_server_secret_unique ON secrets (entity_type, entity_id, type)
+	WHERE entity_type = 21
+	AND type IN (3, 4, 5)
+;
 CREATE TABLE "storage_buckets" (
 	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
 	name TEXT NOT NULL,
@@ -676,6 +732,7 @@ CREATE TABLE "storage_buckets_keys" (
 	UNIQUE (storage_bucket_id, name),
 	FOREIGN KEY (storage_bucket_id) REFERENCES "storage_buckets" (id) ON DELETE CASCADE
 );
+CREATE UNIQUE INDEX storage_buckets_keys_unique_access_key ON "storage_buckets_keys" (access_key);
 CREATE UNIQUE INDEX storage_buckets_unique_storage_pool_id_node_id_name ON "storage_buckets" (storage_pool_id, IFNULL(node_id, -1), name);
 CREATE TABLE "storage_pools" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
@@ -826,5 +883,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (88, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (95, strftime("%s"))
 `
@@ -90,12 +90,25 @@ const (
 
 	// SecretTypeBearerSigningKey is the SecretType for bearer identity signing keys.
 	SecretTypeBearerSigningKey SecretType = "bearer_signing_key"
+
+	// SecretTypeBackupsS3SecretKey is the SecretType for the secret key of the S3 endpoint backups are pushed to.
+	SecretTypeBackupsS3SecretKey SecretType = "backups_s3_secret_key"
+
+	// SecretTypeACMETSIGSecret is the SecretType for the TSIG secret used by the rfc2136 ACME DNS-01 provider.
+	SecretTypeACMETSIGSecret SecretType = "acme_tsig_secret"
+
+	// SecretTypeStorageEncryptionKey is the SecretType for the key wrapping the encryption keys of volumes on
+	// remote storage pools.
+	SecretTypeStorageEncryptionKey SecretType = "storage_encryption_key"
 )
 
 const (
 	// secretTypeCodeCoreAuth is the database code for SecretTypeCoreAuth.
-	secretTypeCodeCoreAuth         int64 = 1
-	secretTypeCodeBearerSigningKey int64 = 2
+	secretTypeCodeCoreAuth             int64 = 1
+	secretTypeCodeBearerSigningKey     int64 = 2
+	secretTypeCodeBackupsS3SecretKey   int64 = 3
+	secretTypeCodeACMETSIGSecret       int64 = 4
+	secretTypeCodeStorageEncryptionKey int64 = 5
 )
 
 // Value implements [driver.Valuer] for SecretType.
@@ -105,6 +118,12 @@ func (s SecretType) Value() (driver.Value, error) {
 		return secretTypeCodeCoreAuth, nil
 	case SecretTypeBearerSigningKey:
 		return secretTypeCodeBearerSigningKey, nil
+	case SecretTypeBackupsS3SecretKey:
+		return secretTypeCodeBackupsS3SecretKey, nil
+	case SecretTypeACMETSIGSecret:
+		return secretTypeCodeACMETSIGSecret, nil
+	case SecretTypeStorageEncryptionKey:
+		return secretTypeCodeStorageEncryptionKey, nil
 	}
 
 	return nil, fmt.Errorf("Invalid secret type %q", s)
@@ -117,6 +136,12 @@ func (s *SecretType) ScanInteger(code int64) error {
 		*s = SecretTypeCoreAuth
 	case secretTypeCodeBearerSigningKey:
 		*s = SecretTypeBearerSigningKey
+	case secretTypeCodeBackupsS3SecretKey:
+		*s = SecretTypeBackupsS3SecretKey
+	case secretTypeCodeACMETSIGSecret:
+		*s = SecretTypeACMETSIGSecret
+	case secretTypeCodeStorageEncryptionKey:
+		*s = SecretTypeStorageEncryptionKey
 	default:
 		return fmt.Errorf("Invalid secret type code %d", code)
 	}
@@ -378,3 +403,86 @@ func RotateBearerIdentitySigningKey(ctx context.Context, tx *sql.Tx, identityID
 
 	return signingKey, nil
 }
+
+// GetBackupsS3SecretKey returns the secret key of the S3 endpoint backups are pushed to.
+// An empty string is returned if no secret key is set.
+func GetBackupsS3SecretKey(ctx context.Context, tx *sql.Tx) (string, error) {
+	return getServerSecret(ctx, tx, SecretTypeBackupsS3SecretKey)
+}
+
+// UpdateBackupsS3SecretKey replaces the secret key of the S3 endpoint backups are pushed to.
+// The secret key is removed if the given value is empty.
+func UpdateBackupsS3SecretKey(ctx context.Context, tx *sql.Tx, secretKey string) error {
+	return updateServerSecret(ctx, tx, SecretTypeBackupsS3SecretKey, secretKey)
+}
+
+// GetACMETSIGSecret returns the TSIG secret used by the rfc2136 ACME DNS-01 provider.
+// An empty string is returned if no secret is set.
+func GetACMETSIGSecret(ctx context.Context, tx *sql.Tx) (string, error) {
+	return getServerSecret(ctx, tx, SecretTypeACMETSIGSecret)
+}
+
+// UpdateACMETSIGSecret replaces the TSIG secret used by the rfc2136 ACME DNS-01 provider.
+// The secret is removed if the given value is empty.
+func UpdateACMETSIGSecret(ctx context.Context, tx *sql.Tx, secret string) error {
+	return updateServerSecret(ctx, tx, SecretTypeACMETSIGSecret, secret)
+}
+
+// GetStorageEncryptionKey returns the key wrapping the encryption keys of volumes on remote storage pools.
+// The key is shared by all cluster members, so that each of them can unlock the volumes, and is created on first use.
+func GetStorageEncryptionKey(ctx context.Context, tx *sql.Tx) (AuthSecretValue, error) {
+	q := `SELECT value FROM secrets WHERE entity_type = ? AND entity_id = ? AND type = ?`
+
+	var key AuthSecretValue
+	err := tx.QueryRowContext(ctx, q, EntityType(entity.TypeServer), 0, SecretTypeStorageEncryptionKey).Scan(&key)
+	if err == nil {
+		return key, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("Failed getting storage encryption key: %w", err)
+	}
+
+	key = newAuthSecretValue()
+	_, err = createSecret(ctx, tx, entity.TypeServer, 0, SecretTypeStorageEncryptionKey, key, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating storage encryption key: %w", err)
+	}
+
+	return key, nil
+}
+
+// getServerSecret returns the value of the server secret of the given type.
+// An empty string is returned if the secret isn't set.
+func getServerSecret(ctx context.Context, tx *sql.Tx, secretType SecretType) (string, error) {
+	q := `SELECT value FROM secrets WHERE entity_type = ? AND entity_id = ? AND type = ? ORDER BY creation_date DESC LIMIT 1`
+
+	var value string
+	err := tx.QueryRowContext(ctx, q, EntityType(entity.TypeServer), 0, secretType).Scan(&value)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("Failed getting %q secret: %w", secretType, err)
+	}
+
+	return value, nil
+}
+
+// updateServerSecret replaces the value of the server secret of the given type.
+// The secret is removed if the given value is empty.
+func updateServerSecret(ctx context.Context, tx *sql.Tx, secretType SecretType, value string) error {
+	q := "DELETE FROM secrets WHERE entity_type = ? AND entity_id = ? AND type = ?"
+	_, err := tx.ExecContext(ctx, q, EntityType(entity.TypeServer), 0, secretType)
+	if err != nil {
+		return fmt.Errorf("Failed deleting %q secret: %w", secretType, err)
+	}
+
+	if value == "" {
+		return nil
+	}
+
+	_, err = createSecret(ctx, tx, entity.TypeServer, 0, secretType, value, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("Failed creating %q secret: %w", secretType, err)
+	}
+
+	return nil
+}
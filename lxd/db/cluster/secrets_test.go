@@ -77,3 +77,82 @@ func TestAuthSecrets(t *testing.T) {
 		require.Equal(t, rotatedSecrets[i].CreationDate.String(), dbSecrets[i].CreationDate.String())
 	}
 }
+
+func TestGetStorageEncryptionKey(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	// The key is created on first use.
+	key, err := GetStorageEncryptionKey(ctx, tx)
+	require.NoError(t, err)
+	require.NoError(t, key.Validate())
+
+	// And returned unchanged afterwards.
+	sameKey, err := GetStorageEncryptionKey(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, key, sameKey)
+
+	require.NoError(t, tx.Commit())
+}
+
+func TestBackupsS3SecretKey(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	// No secret key is set by default.
+	secretKey, err := GetBackupsS3SecretKey(ctx, tx)
+	require.NoError(t, err)
+	require.Empty(t, secretKey)
+
+	// Setting the secret key replaces the previous one.
+	require.NoError(t, UpdateBackupsS3SecretKey(ctx, tx, "foo"))
+	require.NoError(t, UpdateBackupsS3SecretKey(ctx, tx, "bar"))
+
+	secretKey, err = GetBackupsS3SecretKey(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, "bar", secretKey)
+
+	// And an empty value removes it.
+	require.NoError(t, UpdateBackupsS3SecretKey(ctx, tx, ""))
+
+	secretKey, err = GetBackupsS3SecretKey(ctx, tx)
+	require.NoError(t, err)
+	require.Empty(t, secretKey)
+
+	require.NoError(t, tx.Commit())
+}
+
+func TestACMETSIGSecret(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	require.NoError(t, UpdateACMETSIGSecret(ctx, tx, "Zm9v"))
+
+	secret, err := GetACMETSIGSecret(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, "Zm9v", secret)
+
+	// The secret is independent from other server secrets.
+	require.NoError(t, UpdateBackupsS3SecretKey(ctx, tx, ""))
+
+	secret, err = GetACMETSIGSecret(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, "Zm9v", secret)
+
+	require.NoError(t, UpdateACMETSIGSecret(ctx, tx, ""))
+
+	secret, err = GetACMETSIGSecret(ctx, tx)
+	require.NoError(t, err)
+	require.Empty(t, secret)
+
+	require.NoError(t, tx.Commit())
+}
@@ -132,6 +132,180 @@ var updates = map[int]schema.Update{
 	86: updateFromV85,
 	87: updateFromV86,
 	88: updateFromV87,
+	89: updateFromV88,
+	90: updateFromV89,
+	91: updateFromV90,
+	92: updateFromV91,
+	93: updateFromV92,
+	94: updateFromV93,
+	95: updateFromV94,
+}
+
+// updateFromV94 extends the unique index on single valued server secrets to the key wrapping the encryption
+// keys of volumes on remote storage pools, so that the cluster members never end up with different keys.
+func updateFromV94(ctx context.Context, tx *sql.Tx) error {
+	entityTypeCode := strconv.FormatInt(entityTypeCodeServer, 10)
+	backupsS3SecretKeyCode := strconv.FormatInt(secretTypeCodeBackupsS3SecretKey, 10)
+	acmeTSIGSecretCode := strconv.FormatInt(secretTypeCodeACMETSIGSecret, 10)
+	storageEncryptionKeyCode := strconv.FormatInt(secretTypeCodeStorageEncryptionKey, 10)
+	_, err := tx.ExecContext(ctx, `
+DROP INDEX secrets_server_secret_unique;
+CREATE UNIQUE INDEX secrets_server_secret_unique ON secrets (entity_type, entity_id, type)
+	WHERE entity_type = `+entityTypeCode+`
+	AND type IN (`+backupsS3SecretKeyCode+`, `+acmeTSIGSecretCode+`, `+storageEncryptionKeyCode+`)
+`)
+	return err
+}
+
+// updateFromV93 ensures that an access key can only be used by a single storage bucket key, as the storage
+// buckets listener finds the bucket of a request from its access key.
+func updateFromV93(ctx context.Context, tx *sql.Tx) error {
+	var count int
+	err := tx.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM (
+	SELECT access_key FROM storage_buckets_keys GROUP BY access_key HAVING COUNT(*) > 1
+)
+`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("Failed checking for duplicate storage bucket access keys: %w", err)
+	}
+
+	if count > 0 {
+		return errors.New("Some storage bucket keys share the same access key. " +
+			"To proceed with the upgrade, roll back to the previous LXD version, change the access keys so that each is unique, then retry.")
+	}
+
+	_, err = tx.ExecContext(ctx, `CREATE UNIQUE INDEX storage_buckets_keys_unique_access_key ON "storage_buckets_keys" (access_key);`)
+	return err
+}
+
+// updateFromV92 ensures that server secrets which have a single value, such as the secret key of the S3 endpoint
+// backups are pushed to, cannot be stored more than once.
+func updateFromV92(ctx context.Context, tx *sql.Tx) error {
+	entityTypeCode := strconv.FormatInt(entityTypeCodeServer, 10)
+	backupsS3SecretKeyCode := strconv.FormatInt(secretTypeCodeBackupsS3SecretKey, 10)
+	acmeTSIGSecretCode := strconv.FormatInt(secretTypeCodeACMETSIGSecret, 10)
+	_, err := tx.ExecContext(ctx, `
+CREATE UNIQUE INDEX secrets_server_secret_unique ON secrets (entity_type, entity_id, type)
+	WHERE entity_type = `+entityTypeCode+`
+	AND type IN (`+backupsS3SecretKeyCode+`, `+acmeTSIGSecretCode+`)
+`)
+	return err
+}
+
+// updateFromV91 blocks the upgrade if any storage buckets of the former MinIO based implementation remain on
+// local storage pools. From this version on, buckets on local storage pools are served by the embedded S3
+// gateway instead.
+func updateFromV91(ctx context.Context, tx *sql.Tx) error {
+	return checkNoLocalStorageBuckets(ctx, tx)
+}
+
+// checkNoLocalStorageBuckets returns an error if the database contains any legacy storage buckets backed by
+// a local (non-object) storage pool driver. Such buckets were served by MinIO, whose on-disk format isn't
+// supported by the embedded S3 gateway, and must be migrated before the schema can be upgraded.
+func checkNoLocalStorageBuckets(ctx context.Context, tx *sql.Tx) error {
+	// Check whether the storage_buckets table already exists (it may not in very old schemas).
+	var tableName string
+	err := tx.QueryRowContext(ctx, "SELECT name FROM sqlite_master WHERE type='table' AND name='storage_buckets'").Scan(&tableName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // Table doesn't exist yet, nothing to check.
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed checking if storage_buckets table exists: %w", err)
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, `
+SELECT COUNT(sb.id)
+FROM storage_buckets AS sb
+JOIN storage_pools AS sp ON sb.storage_pool_id = sp.id
+WHERE sp.driver != 'cephobject'
+`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("Failed checking for local storage buckets: %w", err)
+	}
+
+	if count > 0 {
+		return errors.New("This LXD version does not support the legacy MinIO based storage buckets on local storage pools. " +
+			"This server has one or more such buckets and cannot be upgraded. " +
+			"To migrate them, roll back to the previous LXD version, copy the objects of those buckets out with an S3 client and delete the buckets, then retry the upgrade. " +
+			"Once upgraded, recreate the buckets, which are then served by the built-in S3 gateway, and copy the objects back in.")
+	}
+
+	return nil
+}
+
+func updateFromV90(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE networks_address_sets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	project_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	addresses TEXT NOT NULL,
+	UNIQUE (project_id, name),
+	FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+
+CREATE TABLE networks_address_sets_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	network_address_set_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	UNIQUE (network_address_set_id, key),
+	FOREIGN KEY (network_address_set_id) REFERENCES networks_address_sets (id) ON DELETE CASCADE
+);
+`)
+	return err
+}
+
+func updateFromV89(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE networks_zones_dnssec_keys (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	network_zone_id INTEGER NOT NULL,
+	flags INTEGER NOT NULL,
+	algorithm INTEGER NOT NULL,
+	public_key TEXT NOT NULL,
+	private_key TEXT NOT NULL,
+	created_at DATETIME NOT NULL,
+	activate_at DATETIME NOT NULL,
+	FOREIGN KEY (network_zone_id) REFERENCES networks_zones (id) ON DELETE CASCADE
+);
+`)
+	return err
+}
+
+func updateFromV88(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE replicators_runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_id INTEGER NOT NULL,
+	restore INTEGER NOT NULL DEFAULT 0,
+	started_at DATETIME NOT NULL,
+	finished_at DATETIME NOT NULL,
+	status TEXT NOT NULL,
+	FOREIGN KEY (replicator_id) REFERENCES replicators (id) ON DELETE CASCADE
+);
+
+CREATE INDEX replicators_runs_replicator_id_started_at ON replicators_runs (replicator_id, started_at);
+
+CREATE TABLE replicators_runs_instances (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_run_id INTEGER NOT NULL,
+	instance_name TEXT NOT NULL,
+	status TEXT NOT NULL,
+	error TEXT NOT NULL,
+	snapshot_name TEXT NOT NULL,
+	started_at DATETIME NOT NULL,
+	finished_at DATETIME NOT NULL,
+	bytes_transferred INTEGER NOT NULL DEFAULT 0,
+	UNIQUE (replicator_run_id, instance_name),
+	FOREIGN KEY (replicator_run_id) REFERENCES replicators_runs (id) ON DELETE CASCADE
+);
+`)
+	return err
 }
 
 func updateFromV87(ctx context.Context, tx *sql.Tx) error {
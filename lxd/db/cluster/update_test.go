@@ -951,3 +951,91 @@ FROM identities
 	require.NoError(t, err)
 	require.Equal(t, pendingTLSMetadata, gotPendingTLSMetadata)
 }
+
+func TestUpdateFromV91(t *testing.T) {
+	schema := Schema()
+	_, err := schema.ExerciseUpdate(92, func(db *sql.DB) {
+		_, err := db.Exec(`
+INSERT INTO storage_pools (id, name, driver, description, state) VALUES (1, 'remote', 'cephobject', '', 1);
+INSERT INTO storage_buckets (storage_pool_id, node_id, name, description, project_id) VALUES (1, NULL, 'bucket1', '', 1);
+`)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	_, err = schema.ExerciseUpdate(92, func(db *sql.DB) {
+		_, err := db.Exec(`
+INSERT INTO storage_pools (id, name, driver, description, state) VALUES (1, 'local', 'dir', '', 1);
+INSERT INTO storage_buckets (storage_pool_id, node_id, name, description, project_id) VALUES (1, NULL, 'bucket1', '', 1);
+`)
+		require.NoError(t, err)
+	})
+	require.ErrorContains(t, err, "does not support the legacy MinIO based storage buckets")
+}
+
+func TestUpdateFromV92(t *testing.T) {
+	schema := Schema()
+	db, err := schema.ExerciseUpdate(93, nil)
+	require.NoError(t, err)
+
+	// Single valued server secrets cannot be stored twice.
+	_, err = db.Exec("INSERT INTO secrets (entity_type, entity_id, type, value) VALUES (21, 0, 3, 'a')")
+	require.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO secrets (entity_type, entity_id, type, value) VALUES (21, 0, 3, 'b')")
+	require.ErrorContains(t, err, "UNIQUE constraint failed")
+
+	// Core auth secrets are rotated so can be stored more than once.
+	_, err = db.Exec("INSERT INTO secrets (entity_type, entity_id, type, value) VALUES (21, 0, 1, 'a'), (21, 0, 1, 'b')")
+	require.NoError(t, err)
+}
+
+func TestUpdateFromV93(t *testing.T) {
+	schema := Schema()
+	db, err := schema.ExerciseUpdate(94, func(db *sql.DB) {
+		_, err := db.Exec(`
+INSERT INTO storage_pools (id, name, driver, description, state) VALUES (1, 'local', 'dir', '', 1);
+INSERT INTO storage_buckets (id, storage_pool_id, node_id, name, description, project_id) VALUES (1, 1, NULL, 'bucket1', '', 1);
+INSERT INTO storage_buckets (id, storage_pool_id, node_id, name, description, project_id) VALUES (2, 1, NULL, 'bucket2', '', 1);
+INSERT INTO storage_buckets_keys (storage_bucket_id, name, description, access_key, secret_key, role) VALUES (1, 'key1', '', 'ACCESS1', 'secret', 'admin');
+`)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	// An access key cannot be used by two keys, even of different buckets.
+	_, err = db.Exec("INSERT INTO storage_buckets_keys (storage_bucket_id, name, description, access_key, secret_key, role) VALUES (2, 'key1', '', 'ACCESS1', 'secret', 'admin')")
+	require.ErrorContains(t, err, "UNIQUE constraint failed")
+
+	_, err = schema.ExerciseUpdate(94, func(db *sql.DB) {
+		_, err := db.Exec(`
+INSERT INTO storage_pools (id, name, driver, description, state) VALUES (1, 'remote', 'cephobject', '', 1);
+INSERT INTO storage_buckets (id, storage_pool_id, node_id, name, description, project_id) VALUES (1, 1, NULL, 'bucket1', '', 1);
+INSERT INTO storage_buckets (id, storage_pool_id, node_id, name, description, project_id) VALUES (2, 1, NULL, 'bucket2', '', 1);
+INSERT INTO storage_buckets_keys (storage_bucket_id, name, description, access_key, secret_key, role) VALUES (1, 'key1', '', 'ACCESS1', 'secret', 'admin');
+INSERT INTO storage_buckets_keys (storage_bucket_id, name, description, access_key, secret_key, role) VALUES (2, 'key1', '', 'ACCESS1', 'secret', 'admin');
+`)
+		require.NoError(t, err)
+	})
+	require.ErrorContains(t, err, "share the same access key")
+}
+
+func TestUpdateFromV94(t *testing.T) {
+	schema := Schema()
+	db, err := schema.ExerciseUpdate(95, nil)
+	require.NoError(t, err)
+
+	// The storage encryption key cannot be stored twice.
+	_, err = db.Exec("INSERT INTO secrets (entity_type, entity_id, type, value) VALUES (21, 0, 5, 'a')")
+	require.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO secrets (entity_type, entity_id, type, value) VALUES (21, 0, 5, 'b')")
+	require.ErrorContains(t, err, "UNIQUE constraint failed")
+
+	// Other single valued server secrets remain unique.
+	_, err = db.Exec("INSERT INTO secrets (entity_type, entity_id, type, value) VALUES (21, 0, 3, 'a')")
+	require.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO secrets (entity_type, entity_id, type, value) VALUES (21, 0, 3, 'b')")
+	require.ErrorContains(t, err, "UNIQUE constraint failed")
+}
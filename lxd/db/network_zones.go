@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/canonical/lxd/lxd/db/query"
 	"github.com/canonical/lxd/shared/api"
@@ -484,3 +485,69 @@ func (c *ClusterTx) DeleteNetworkZoneRecord(ctx context.Context, id int64) error
 
 	return err
 }
+
+// NetworkZoneDNSSECKey represents a DNSSEC signing key of a network zone.
+type NetworkZoneDNSSECKey struct {
+	ID         int64
+	Flags      uint16
+	Algorithm  uint8
+	PublicKey  string
+	PrivateKey string
+	CreatedAt  time.Time
+	ActivateAt time.Time
+}
+
+// GetNetworkZoneDNSSECKeys returns the DNSSEC keys of the network zone, oldest first.
+func (c *ClusterTx) GetNetworkZoneDNSSECKeys(ctx context.Context, zone int64) ([]NetworkZoneDNSSECKey, error) {
+	q := `SELECT id, flags, algorithm, public_key, private_key, created_at, activate_at FROM networks_zones_dnssec_keys
+		WHERE network_zone_id=?
+		ORDER BY created_at, id
+	`
+
+	var keys []NetworkZoneDNSSECKey
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var key NetworkZoneDNSSECKey
+
+		err := scan(&key.ID, &key.Flags, &key.Algorithm, &key.PublicKey, &key.PrivateKey, &key.CreatedAt, &key.ActivateAt)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+
+		return nil
+	}, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// CreateNetworkZoneDNSSECKey adds a DNSSEC key to the network zone.
+func (c *ClusterTx) CreateNetworkZoneDNSSECKey(ctx context.Context, zone int64, key NetworkZoneDNSSECKey) (int64, error) {
+	result, err := c.tx.ExecContext(ctx, `
+			INSERT INTO networks_zones_dnssec_keys (network_zone_id, flags, algorithm, public_key, private_key, created_at, activate_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, zone, key.Flags, key.Algorithm, key.PublicKey, key.PrivateKey, key.CreatedAt, key.ActivateAt)
+	if err != nil {
+		return -1, err
+	}
+
+	return result.LastInsertId()
+}
+
+// DeleteNetworkZoneDNSSECKey deletes a DNSSEC key.
+func (c *ClusterTx) DeleteNetworkZoneDNSSECKey(ctx context.Context, id int64) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM networks_zones_dnssec_keys WHERE id=?", id)
+
+	return err
+}
+
+// DeleteNetworkZoneDNSSECKeys deletes all the DNSSEC keys of the network zone.
+func (c *ClusterTx) DeleteNetworkZoneDNSSECKeys(ctx context.Context, zone int64) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM networks_zones_dnssec_keys WHERE network_zone_id=?", zone)
+
+	return err
+}
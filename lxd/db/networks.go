@@ -124,6 +124,34 @@ func (c *ClusterTx) GetNetworksNodeParent(ctx context.Context) (map[int64]map[st
 	return nodesNetworks, nil
 }
 
+// GetNetworkMembersConfigValue returns a map associating each member ID to the value of the given
+// node-specific config key of a network. Members without a value for the key are omitted.
+func (c *ClusterTx) GetNetworkMembersConfigValue(ctx context.Context, networkID int64, key string) (map[int64]string, error) {
+	q := "SELECT node_id, value FROM networks_config WHERE network_id=? AND key=? AND node_id IS NOT NULL"
+
+	values := make(map[int64]string)
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var nodeID int64
+		var value string
+
+		err := scan(&nodeID, &value)
+		if err != nil {
+			return err
+		}
+
+		if value != "" {
+			values[nodeID] = value
+		}
+
+		return nil
+	}, networkID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
 // GetNonPendingNetworkIDs returns a map associating each network name to its ID.
 //
 // Pending networks are skipped.
@@ -996,4 +1024,6 @@ var NodeSpecificNetworkConfig = []string{
 	"bridge.external_interfaces",
 	"parent",
 	"acceleration.parent",
+	"volatile.wireguard.public_key",
+	"volatile.wireguard.index",
 }
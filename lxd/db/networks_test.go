@@ -44,6 +44,30 @@ func TestGetNetworksLocalConfigs(t *testing.T) {
 	}, config)
 }
 
+// The GetNetworkMembersConfigValue method returns the node-specific values of a key for each member.
+func TestGetNetworkMembersConfigValue(t *testing.T) {
+	cluster, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+
+	var values map[int64]string
+
+	err := cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID, err := tx.CreateNetwork(ctx, api.ProjectDefaultName, "lxdbr0", "", db.NetworkTypeBridge, map[string]string{
+			"dns.mode":                      "none",
+			"volatile.wireguard.public_key": "foo",
+		})
+		if err != nil {
+			return err
+		}
+
+		values, err = tx.GetNetworkMembersConfigValue(ctx, networkID, "volatile.wireguard.public_key")
+		return err
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int64]string{1: "foo"}, values)
+}
+
 func TestCreatePendingNetwork(t *testing.T) {
 	tx, cleanup := db.NewTestClusterTx(t)
 	defer cleanup()
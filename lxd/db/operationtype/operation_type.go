@@ -145,6 +145,10 @@ const (
 	ReplicatorRun
 	ReplicatorRunInstance
 	ProjectReplicaModeUpdate
+	NetworkZoneKeysRollover
+	BackupsCreateScheduled
+	VolumeMirrorRefresh
+	VolumeMirrorPromote
 
 	// upperBound is used only to enforce consistency in the package on init.
 	// Make sure it's always the last item in this list.
@@ -396,6 +400,14 @@ func (t Type) Description() string {
 		return "Replicating instance"
 	case ProjectReplicaModeUpdate:
 		return "Updating project replica mode"
+	case NetworkZoneKeysRollover:
+		return "Rolling over network zone DNSSEC keys"
+	case BackupsCreateScheduled:
+		return "Creating scheduled backups"
+	case VolumeMirrorRefresh:
+		return "Refreshing volume mirror"
+	case VolumeMirrorPromote:
+		return "Promoting volume mirror"
 
 	// It should never be possible to reach the default clause.
 	// See the init function.
@@ -414,7 +426,7 @@ func (t Type) EntityType() entity.Type {
 		BackupsExpire, SnapshotsExpire, ClusterJoinToken, CertificateAddToken, RenewServerCertificate,
 		ClusterHeal, ImagesUpdate, VolumeSnapshotsCreateScheduled, SnapshotsCreateScheduled,
 		PruneExpiredOperations, RefreshClusterLinkVolatileAddresses,
-		StoragePoolCreate, Wait:
+		StoragePoolCreate, NetworkZoneKeysRollover, BackupsCreateScheduled, Wait:
 		return entity.TypeServer
 
 	// Project level operations.
@@ -430,7 +442,8 @@ func (t Type) EntityType() entity.Type {
 		return entity.TypeStorageBucket
 
 	// Volume operations.
-	case VolumeMigrate, VolumeMove, VolumeSnapshotCreate, CustomVolumeBackupCreate, VolumeCopy, VolumeUpdate, VolumeDelete:
+	case VolumeMigrate, VolumeMove, VolumeSnapshotCreate, CustomVolumeBackupCreate, VolumeCopy, VolumeUpdate, VolumeDelete,
+		VolumeMirrorRefresh, VolumeMirrorPromote:
 		return entity.TypeStorageVolume
 
 	// Volume snapshot operations
@@ -530,6 +543,8 @@ func (t Type) ConflictAction() ConflictAction {
 		return ConflictActionFail // Enforces cluster-wide evacuation exclusivity when used with a shared ConflictReference; this prevents evacuation race conditions.
 	case ReplicatorRun:
 		return ConflictActionFail // Prevents concurrent runs of the same replicator; the replicator URL is used as the per-replicator conflict reference.
+	case VolumeMirrorRefresh:
+		return ConflictActionFail // Prevents concurrent refreshes of the same mirror; the source volume URL is used as the conflict reference.
 	}
 
 	return ConflictActionNone
@@ -197,11 +197,12 @@ func (c *ClusterTx) GetStoragePoolBucket(ctx context.Context, poolID int64, proj
 	return buckets[0], nil
 }
 
-// GetStoragePoolLocalBucket returns the local Storage Bucket for the given bucket name.
+// GetStoragePoolLocalBucket returns the local Storage Bucket for the given pool ID and bucket name.
 // The search is restricted to buckets that belong to this member.
-func (c *ClusterTx) GetStoragePoolLocalBucket(ctx context.Context, bucketName string) (*StorageBucket, error) {
+func (c *ClusterTx) GetStoragePoolLocalBucket(ctx context.Context, poolID int64, bucketName string) (*StorageBucket, error) {
 	filters := []StorageBucketFilter{{
-		Name: &bucketName,
+		PoolID: &poolID,
+		Name:   &bucketName,
 	}}
 
 	buckets, err := c.GetStoragePoolBuckets(ctx, true, filters...)
@@ -280,16 +281,23 @@ func (c *ClusterTx) GetStoragePoolLocalBucketByAccessKey(ctx context.Context, ac
 }
 
 // CreateStoragePoolBucket creates a new Storage Bucket.
-func (c *ClusterTx) CreateStoragePoolBucket(ctx context.Context, poolID int64, projectName string, info api.StorageBucketsPost) (int64, error) {
+// If memberSpecific is true, then the bucket is associated to the current member, rather than being associated
+// to all members.
+func (c *ClusterTx) CreateStoragePoolBucket(ctx context.Context, poolID int64, projectName string, memberSpecific bool, info api.StorageBucketsPost) (int64, error) {
 	var err error
 	var bucketID int64
+	var nodeID any
+
+	if memberSpecific {
+		nodeID = c.nodeID
+	}
 
 	// Insert a new Storage Bucket record.
 	result, err := c.tx.ExecContext(ctx, `
 		INSERT INTO storage_buckets
 		(storage_pool_id, node_id, name, description, project_id)
 		VALUES (?, ?, ?, ?, (SELECT id FROM projects WHERE name = ?))
-		`, poolID, nil, info.Name, info.Description, projectName)
+		`, poolID, nodeID, info.Name, info.Description, projectName)
 	if err != nil {
 		if query.IsConflictErr(err) {
 			return -1, api.StatusErrorf(http.StatusConflict, "A bucket for that name already exists")
@@ -499,15 +507,8 @@ func (c *ClusterTx) CreateStoragePoolBucketKey(ctx context.Context, bucketID int
 	var err error
 	var bucketKeyID int64
 
-	// Check there isn't another bucket with the same access key on the local server.
-	bucket, err := c.GetStoragePoolLocalBucketByAccessKey(ctx, info.AccessKey)
-	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
-		return -1, err
-	} else if bucket != nil {
-		return -1, api.StatusErrorf(http.StatusConflict, "A bucket key using that access key already exists on this server")
-	}
-
 	// Insert a new Storage Bucket Key record.
+	// The access key is unique across all keys, as the storage buckets listener finds buckets by access key.
 	result, err := c.tx.ExecContext(ctx, `
 		INSERT INTO storage_buckets_keys
 		(storage_bucket_id, name, description, role, access_key, secret_key)
@@ -515,7 +516,7 @@ func (c *ClusterTx) CreateStoragePoolBucketKey(ctx context.Context, bucketID int
 		`, bucketID, info.Name, info.Description, info.Role, info.AccessKey, info.SecretKey)
 	if err != nil {
 		if query.IsConflictErr(err) {
-			return -1, api.StatusErrorf(http.StatusConflict, "A bucket key for that name already exists")
+			return -1, api.StatusErrorf(http.StatusConflict, "A bucket key with that name or access key already exists")
 		}
 
 		return -1, err
@@ -531,14 +532,6 @@ func (c *ClusterTx) CreateStoragePoolBucketKey(ctx context.Context, bucketID int
 
 // UpdateStoragePoolBucketKey updates an existing Storage Bucket Key.
 func (c *ClusterTx) UpdateStoragePoolBucketKey(ctx context.Context, bucketID int64, bucketKeyID int64, info api.StorageBucketKeyPut) error {
-	// Check there isn't another bucket with the same access key on the local server.
-	bucket, err := c.GetStoragePoolLocalBucketByAccessKey(ctx, info.AccessKey)
-	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
-		return err
-	} else if bucket != nil && bucket.ID != bucketID {
-		return api.StatusErrorf(http.StatusConflict, "A bucket key using that access key already exists on this server")
-	}
-
 	// Update existing Storage Bucket Key record.
 	res, err := c.tx.ExecContext(ctx, `
 		UPDATE storage_buckets_keys
@@ -546,6 +539,10 @@ func (c *ClusterTx) UpdateStoragePoolBucketKey(ctx context.Context, bucketID int
 		WHERE storage_bucket_id = ? and id = ?
 		`, info.Description, info.Role, info.AccessKey, info.SecretKey, bucketID, bucketKeyID)
 	if err != nil {
+		if query.IsConflictErr(err) {
+			return api.StatusErrorf(http.StatusConflict, "A bucket key with that access key already exists")
+		}
+
 		return err
 	}
 
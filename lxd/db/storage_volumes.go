@@ -483,6 +483,17 @@ func (c *ClusterTx) UpdateStoragePoolVolume(ctx context.Context, projectName str
 	return nil
 }
 
+// UpdateStorageVolumeConfig replaces the config of the storage volume with the given ID.
+// Unlike UpdateStoragePoolVolume, the volume doesn't need to be located on this member.
+func (c *ClusterTx) UpdateStorageVolumeConfig(ctx context.Context, volumeID int64, volumeConfig map[string]string) error {
+	err := storageVolumeConfigClear(c.tx, volumeID, false)
+	if err != nil {
+		return err
+	}
+
+	return storageVolumeConfigAdd(c.tx, volumeID, volumeConfig, false)
+}
+
 // RemoveStoragePoolVolume deletes the storage volume attached to a given storage
 // pool.
 func (c *ClusterTx) RemoveStoragePoolVolume(ctx context.Context, projectName string, volumeName string, volumeType cluster.StoragePoolVolumeType, poolID int64) error {
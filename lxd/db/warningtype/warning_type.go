@@ -61,6 +61,14 @@ const (
 	// OIDCAuthenticationUnavailable warnings are created when OIDC is configured on LXD but LXD is unable to use those
 	// settings to initialize the OIDC verifier.
 	OIDCAuthenticationUnavailable
+	// ScheduledBackupFailure represents the failure of a scheduled instance or custom volume backup.
+	ScheduledBackupFailure
+	// StoragePoolDegraded represents a storage pool reported as degraded or unavailable by its health check.
+	StoragePoolDegraded
+	// VolumeMirrorRefreshFailure represents the failure to refresh the mirror of a custom volume.
+	VolumeMirrorRefreshFailure
+	// NetworkACLFQDNResolutionFailure represents the failure to resolve a DNS name used in network ACL rules.
+	NetworkACLFQDNResolutionFailure
 )
 
 // TypeNames associates a warning code to its name.
@@ -92,6 +100,10 @@ var TypeNames = map[Type]string{
 	StoragePoolUnvailable:                  "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:       "Cannot update cluster certificate",
 	OIDCAuthenticationUnavailable:          "Failed applying OIDC settings",
+	ScheduledBackupFailure:                 "Failed creating scheduled backup",
+	StoragePoolDegraded:                    "Storage pool degraded",
+	VolumeMirrorRefreshFailure:             "Failed refreshing volume mirror",
+	NetworkACLFQDNResolutionFailure:        "Failed resolving network ACL FQDN",
 }
 
 // Severity returns the severity of the warning type.
@@ -151,6 +163,14 @@ func (t Type) Severity() Severity {
 		return SeverityLow
 	case OIDCAuthenticationUnavailable:
 		return SeverityModerate
+	case ScheduledBackupFailure:
+		return SeverityModerate
+	case StoragePoolDegraded:
+		return SeverityHigh
+	case VolumeMirrorRefreshFailure:
+		return SeverityModerate
+	case NetworkACLFQDNResolutionFailure:
+		return SeverityModerate
 	}
 
 	return SeverityLow
@@ -82,6 +82,7 @@ type DiskLimits struct {
 	ReadIOps   int64
 	WriteBytes int64
 	WriteIOps  int64
+	Group      string // Name of the throttle group shared by the attachments of a storage volume.
 }
 
 // RunConfig represents run-time config used for device setup/cleanup.
@@ -98,6 +99,7 @@ type RunConfig struct {
 	TPMDevice        []RunConfigItem  // TPM device configuration settings.
 	PCIDevice        []RunConfigItem  // PCI device configuration settings.
 	Revert           revert.Hook      // Revert setup of device on post-setup error.
+	VolumeLimits     bool             // Whether to start the instance in the cgroup sharing the I/O limits of storage volumes.
 }
 
 // NICConfigDir shared constant used to indicate where NIC config is stored.
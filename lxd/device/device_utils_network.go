@@ -622,6 +622,12 @@ func bgpAddPrefix(d *deviceCommon, n network.Network, config map[string]string)
 		}
 	}
 
+	// The NIC's path attributes take precedence over the network's.
+	attrs, err := network.BGPPathAttributes(config, n.Config())
+	if err != nil {
+		return err
+	}
+
 	// Add the prefixes.
 	bgpOwner := fmt.Sprint("instance_", d.inst.ID(), "_", d.name)
 	if config["ipv4.routes.external"] != "" {
@@ -631,7 +637,7 @@ func bgpAddPrefix(d *deviceCommon, n network.Network, config map[string]string)
 				return err
 			}
 
-			err = d.state.BGP.AddPrefix(*prefixNet, nexthopV4, bgpOwner)
+			err = d.state.BGP.AddPrefix(*prefixNet, nexthopV4, bgpOwner, attrs)
 			if err != nil {
 				return err
 			}
@@ -645,7 +651,7 @@ func bgpAddPrefix(d *deviceCommon, n network.Network, config map[string]string)
 				return err
 			}
 
-			err = d.state.BGP.AddPrefix(*prefixNet, nexthopV6, bgpOwner)
+			err = d.state.BGP.AddPrefix(*prefixNet, nexthopV6, bgpOwner, attrs)
 			if err != nil {
 				return err
 			}
@@ -12,6 +12,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"golang.org/x/sys/unix"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/lxd/storage/block"
 	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
@@ -986,6 +988,19 @@ func (d *disk) startContainer() (*deviceConfig.RunConfig, error) {
 
 		// Unmount host-side mount once instance is started.
 		runConf.PostHooks = append(runConf.PostHooks, d.postStart)
+
+		// Share the limits of the custom volume with the other instances using it.
+		if cgroup.VolumeLimitsSupported() {
+			volLimits, err := d.volumeLimits(d.config)
+			if err != nil {
+				return nil, err
+			}
+
+			if volLimits != nil {
+				runConf.VolumeLimits = true
+				runConf.PostHooks = append(runConf.PostHooks, d.syncVolumeLimits)
+			}
+		}
 	}
 
 	revert.Success()
@@ -1047,20 +1062,14 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 	}
 
 	// Add I/O limits if set.
-	var diskLimits *deviceConfig.DiskLimits
-	if d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "" {
-		// Parse the limits into usable values.
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
-		if err != nil {
-			return nil, err
-		}
+	diskLimits, err := d.getLimits(d.config)
+	if err != nil {
+		return nil, err
+	}
 
-		diskLimits = &deviceConfig.DiskLimits{
-			ReadBytes:  readBps,
-			ReadIOps:   readIops,
-			WriteBytes: writeBps,
-			WriteIOps:  writeIops,
-		}
+	// Share the limits of the custom volume with the other instances using it once QEMU is running.
+	if diskLimits != nil && diskLimits.Group != "" && cgroup.VolumeLimitsSupported() {
+		runConf.PostHooks = append(runConf.PostHooks, d.vmJoinVolumeLimits)
 	}
 
 	if filters.IsRootDisk(d.config) {
@@ -1410,45 +1419,52 @@ func (d *disk) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 
 	// Only apply IO limits if instance is running.
 	if isRunning {
-		runConf := deviceConfig.RunConfig{}
-
-		switch d.inst.Type() {
-		case instancetype.Container:
-			err := d.generateLimits(&runConf)
-			if err != nil {
-				return err
-			}
+		return d.applyLimits()
+	}
 
-		case instancetype.VM:
-			// Parse the limits into usable values.
-			readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
-			if err != nil {
-				return err
-			}
+	return nil
+}
 
-			// Apply the limits to a minimal mount entry.
-			diskLimits := &deviceConfig.DiskLimits{
-				ReadBytes:  readBps,
-				ReadIOps:   readIops,
-				WriteBytes: writeBps,
-				WriteIOps:  writeIops,
-			}
+// applyLimits applies the I/O limits of the device to the running instance.
+func (d *disk) applyLimits() error {
+	runConf := deviceConfig.RunConfig{}
 
-			runConf.Mounts = []deviceConfig.MountEntryItem{
-				{
-					DevName: d.name,
-					Limits:  diskLimits,
-				},
-			}
+	switch d.inst.Type() {
+	case instancetype.Container:
+		err := d.generateLimits(&runConf)
+		if err != nil {
+			return err
 		}
 
-		err := d.inst.DeviceEventHandler(&runConf)
+	case instancetype.VM:
+		diskLimits, err := d.getLimits(d.config)
 		if err != nil {
 			return err
 		}
+
+		// Clear any previously applied limits.
+		if diskLimits == nil {
+			diskLimits = &deviceConfig.DiskLimits{}
+		}
+
+		// Share the limits of the custom volume with the other instances using it.
+		if diskLimits.Group != "" && cgroup.VolumeLimitsSupported() {
+			err = cgroup.VolumeLimitsAddProcess(diskVolumeLimitsVMCGroup(d.inst), d.inst.InitPID())
+			if err != nil {
+				return err
+			}
+		}
+
+		// Apply the limits to a minimal mount entry.
+		runConf.Mounts = []deviceConfig.MountEntryItem{
+			{
+				DevName: d.name,
+				Limits:  diskLimits,
+			},
+		}
 	}
 
-	return nil
+	return d.inst.DeviceEventHandler(&runConf)
 }
 
 // applyDeferredQuota attempts to apply the deferred quota specified in the volatile "apply_quota" key if set.
@@ -1516,11 +1532,35 @@ func (d *disk) applyQuota(remount bool) error {
 }
 
 // generateLimits adds a set of cgroup rules to apply specified limits to the supplied RunConfig.
+// The limits of the custom storage volumes are left out when the container is in the cgroup shared by the instances
+// using them, as they are applied to that cgroup instead.
 func (d *disk) generateLimits(runConf *deviceConfig.RunConfig) error {
+	sharedLimits := false
+	pid := d.inst.InitPID()
+	if cgroup.VolumeLimitsSupported() && pid > 0 {
+		var err error
+		sharedLimits, err = cgroup.VolumeLimitsContains(pid)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Disk throttle limits.
 	hasDiskLimits := false
 	for _, dev := range d.inst.ExpandedDevices().Filter(filters.IsDisk) {
-		if dev["limits.read"] != "" || dev["limits.write"] != "" || dev["limits.max"] != "" {
+		var diskLimits *deviceConfig.DiskLimits
+		var err error
+		if sharedLimits {
+			diskLimits, err = d.deviceLimits(dev)
+		} else {
+			diskLimits, err = d.getLimits(dev)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if diskLimits != nil {
 			hasDiskLimits = true
 			break
 		}
@@ -1534,7 +1574,7 @@ func (d *disk) generateLimits(runConf *deviceConfig.RunConfig) error {
 		return errors.New("Cannot apply disk limits as blkio cgroup controller is missing")
 	}
 
-	diskLimits, err := d.getDiskLimits()
+	diskLimits, err := d.getDiskLimits(sharedLimits)
 	if err != nil {
 		return err
 	}
@@ -2195,14 +2235,31 @@ func (d *disk) postStop() error {
 		}
 	}
 
+	// Stop sharing the limits of the custom volume with the other instances using it.
+	if cgroup.VolumeLimitsSupported() {
+		volLimits, err := d.volumeLimits(d.config)
+		if err == nil && volLimits != nil {
+			err = diskSyncVolumeLimits(d.state, d)
+		}
+
+		if err != nil {
+			d.logger.Warn("Failed updating shared I/O limits", logger.Ctx{"err": err})
+		}
+
+		// Remove the cgroup of the QEMU process once the VM has stopped.
+		if d.inst.Type() == instancetype.VM {
+			err = cgroup.VolumeLimitsRemove(diskVolumeLimitsVMCGroup(d.inst))
+			if err != nil {
+				d.logger.Warn("Failed removing shared I/O limits cgroup", logger.Ctx{"err": err})
+			}
+		}
+	}
+
 	return nil
 }
 
-// getDiskLimits calculates Block I/O limits.
-func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
-	result := map[string]diskBlockLimit{}
-
-	// Build a list of all valid block devices
+// diskValidBlocks returns the block devices (major:minor) which I/O limits can be applied to.
+func diskValidBlocks() ([]string, error) {
 	validBlocks := []string{}
 
 	dents, err := os.ReadDir("/sys/class/block/")
@@ -2228,15 +2285,62 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 		validBlocks = append(validBlocks, strings.TrimSuffix(string(block), "\n"))
 	}
 
+	return validBlocks, nil
+}
+
+// diskLimitBlock returns the block device from validBlocks which the I/O limits of the given block device
+// (major:minor) apply to, being either the block device itself or the parent of a partition.
+func diskLimitBlock(validBlocks []string, block string) (string, error) {
+	// Straightforward entry (full block device)
+	if slices.Contains(validBlocks, block) {
+		return block, nil
+	}
+
+	// Attempt to deal with a partition (guess its parent)
+	major, _, _ := strings.Cut(block, ":")
+	parent := major + ":0"
+	if slices.Contains(validBlocks, parent) {
+		return parent, nil
+	}
+
+	return "", fmt.Errorf("Block device does not support quotas %q", block)
+}
+
+// getDiskLimits calculates Block I/O limits.
+// When sharedLimits is true, the limits of the custom storage volumes are left out as they are applied
+// through the cgroup shared by the instances using them.
+func (d *disk) getDiskLimits(sharedLimits bool) (map[string]diskBlockLimit, error) {
+	result := map[string]diskBlockLimit{}
+
+	// Build a list of all valid block devices
+	validBlocks, err := diskValidBlocks()
+	if err != nil {
+		return nil, err
+	}
+
 	// Process all the limits
 	blockLimits := map[string][]diskBlockLimit{}
 	for devName, dev := range d.inst.ExpandedDevices().Filter(filters.IsDisk) {
 		// Parse the user input
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(dev)
+		var diskLimits *deviceConfig.DiskLimits
+		if sharedLimits {
+			diskLimits, err = d.deviceLimits(dev)
+		} else {
+			diskLimits, err = d.getLimits(dev)
+		}
+
 		if err != nil {
 			return nil, err
 		}
 
+		var readBps, readIops, writeBps, writeIops int64
+		if diskLimits != nil {
+			readBps = diskLimits.ReadBytes
+			readIops = diskLimits.ReadIOps
+			writeBps = diskLimits.WriteBytes
+			writeIops = diskLimits.WriteIOps
+		}
+
 		// Set the source path
 		source := d.getDevicePath(devName, dev)
 		if dev["source"] == "" {
@@ -2271,22 +2375,9 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 
 		device := diskBlockLimit{readBps: readBps, readIops: readIops, writeBps: writeBps, writeIops: writeIops}
 		for _, block := range blocks {
-			blockStr := ""
-
-			if slices.Contains(validBlocks, block) {
-				// Straightforward entry (full block device)
-				blockStr = block
-			} else {
-				// Attempt to deal with a partition (guess its parent)
-				major, _, _ := strings.Cut(block, ":")
-				parent := major + ":0"
-				if slices.Contains(validBlocks, parent) {
-					blockStr = parent
-				}
-			}
-
-			if blockStr == "" {
-				return nil, fmt.Errorf("Block device does not support quotas %q", block)
+			blockStr, err := diskLimitBlock(validBlocks, block)
+			if err != nil {
+				return nil, err
 			}
 
 			if blockLimits[blockStr] == nil {
@@ -2395,6 +2486,402 @@ func (d *disk) parseLimit(dev deviceConfig.Device) (readBps int64, readIops int6
 	return readBps, readIops, writeBps, writeIops, nil
 }
 
+// customVolume returns the record of the custom storage volume used by the disk configuration.
+// Nil is returned if the disk does not use a custom volume.
+func (d *disk) customVolume(dev deviceConfig.Device) (*db.StorageVolume, error) {
+	if dev["pool"] == "" || filters.IsRootDisk(dev) {
+		return nil, nil
+	}
+
+	if dev["source.type"] != "" && dev["source.type"] != cluster.StoragePoolVolumeTypeNameCustom {
+		return nil, nil
+	}
+
+	volumeName := dev["source"]
+	if dev["source.snapshot"] != "" {
+		volumeName = volumeName + shared.SnapshotDelimiter + dev["source.snapshot"]
+	}
+
+	instProj := d.inst.Project()
+	storageProjectName := project.StorageVolumeProjectFromRecord(&instProj, cluster.StoragePoolVolumeTypeCustom)
+
+	var dbVolume *db.StorageVolume
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID, err := tx.GetStoragePoolID(ctx, dev["pool"])
+		if err != nil {
+			return err
+		}
+
+		dbVolume, err = tx.GetStoragePoolVolume(ctx, poolID, storageProjectName, cluster.StoragePoolVolumeTypeCustom, volumeName, true)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading custom volume %q: %w", volumeName, err)
+	}
+
+	return dbVolume, nil
+}
+
+// volumeLimits returns the I/O limits of the custom storage volume used by the disk configuration.
+// Nil is returned if the disk does not use a custom volume or the volume has no limits.
+func (d *disk) volumeLimits(dev deviceConfig.Device) (*deviceConfig.DiskLimits, error) {
+	dbVolume, err := d.customVolume(dev)
+	if err != nil || dbVolume == nil {
+		return nil, err
+	}
+
+	readBps, readIops, writeBps, writeIops, err := storagePools.VolumeIOLimits(dbVolume.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if readBps == 0 && readIops == 0 && writeBps == 0 && writeIops == 0 {
+		return nil, nil
+	}
+
+	return &deviceConfig.DiskLimits{
+		ReadBytes:  readBps,
+		ReadIOps:   readIops,
+		WriteBytes: writeBps,
+		WriteIOps:  writeIops,
+		Group:      fmt.Sprintf("lxd_volume_%d", dbVolume.ID),
+	}, nil
+}
+
+// deviceLimits returns the I/O limits set on the disk configuration itself.
+// Nil is returned if the disk has no limits.
+func (d *disk) deviceLimits(dev deviceConfig.Device) (*deviceConfig.DiskLimits, error) {
+	if dev["limits.read"] == "" && dev["limits.write"] == "" && dev["limits.max"] == "" {
+		return nil, nil
+	}
+
+	readBps, readIops, writeBps, writeIops, err := d.parseLimit(dev)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deviceConfig.DiskLimits{
+		ReadBytes:  readBps,
+		ReadIOps:   readIops,
+		WriteBytes: writeBps,
+		WriteIOps:  writeIops,
+	}, nil
+}
+
+// getLimits returns the I/O limits of the disk configuration, combining the limits set on the device with the
+// ones of the custom storage volume it uses. The lowest limit applies when both are set.
+// Nil is returned if the disk has no limits.
+func (d *disk) getLimits(dev deviceConfig.Device) (*deviceConfig.DiskLimits, error) {
+	volLimits, err := d.volumeLimits(dev)
+	if err != nil {
+		return nil, err
+	}
+
+	devLimits, err := d.deviceLimits(dev)
+	if err != nil {
+		return nil, err
+	}
+
+	return combineDiskLimits(devLimits, volLimits), nil
+}
+
+// combineDiskLimits returns the limits of a disk device combined with the ones of the custom storage volume it
+// uses, either of which may be nil. The lowest limit applies when both are set, zero meaning no limit.
+// The throttle group of the volume is kept so that its attachments within a VM share the limits.
+func combineDiskLimits(devLimits *deviceConfig.DiskLimits, volLimits *deviceConfig.DiskLimits) *deviceConfig.DiskLimits {
+	if devLimits == nil {
+		return volLimits
+	}
+
+	if volLimits == nil {
+		return devLimits
+	}
+
+	lowest := func(a int64, b int64) int64 {
+		if a == 0 || (b != 0 && b < a) {
+			return b
+		}
+
+		return a
+	}
+
+	return &deviceConfig.DiskLimits{
+		ReadBytes:  lowest(devLimits.ReadBytes, volLimits.ReadBytes),
+		ReadIOps:   lowest(devLimits.ReadIOps, volLimits.ReadIOps),
+		WriteBytes: lowest(devLimits.WriteBytes, volLimits.WriteBytes),
+		WriteIOps:  lowest(devLimits.WriteIOps, volLimits.WriteIOps),
+		Group:      volLimits.Group,
+	}
+}
+
+// DiskApplyVolumeLimits applies the I/O limits of the given disk devices of a running instance again, for example
+// after the limits of the custom storage volume they use were changed.
+func DiskApplyVolumeLimits(s *state.State, inst instance.Instance, devNames []string) error {
+	for _, devName := range devNames {
+		devConfig, ok := inst.ExpandedDevices()[devName]
+		if !ok || !filters.IsDisk(devConfig) {
+			continue
+		}
+
+		dev, err := load(inst, s, inst.Project().Name, devName, devConfig, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		diskDev, ok := dev.(*disk)
+		if !ok {
+			continue
+		}
+
+		err = diskDev.applyLimits()
+		if err != nil {
+			return fmt.Errorf("Failed applying limits of disk device %q: %w", devName, err)
+		}
+	}
+
+	return nil
+}
+
+// diskVolumeLimitsMu serializes the updates of the cgroup shared by the instances using custom storage volumes with
+// I/O limits.
+var diskVolumeLimitsMu sync.Mutex
+
+// diskVolumeLimitsVMCGroup returns the name of the child of the shared cgroup holding the QEMU process of a VM.
+func diskVolumeLimitsVMCGroup(inst instance.Instance) string {
+	return "qemu." + project.Instance(inst.Project().Name, inst.Name())
+}
+
+// volumeLimitBlocks returns the I/O limits of the custom storage volume used by the disk along with the block
+// devices (major:minor) backing it, which the limits are applied to in the cgroup shared by the instances.
+// Nil is returned if the volume has no limits or if they cannot be applied to block devices of the host.
+func (d *disk) volumeLimitBlocks(validBlocks []string) (*deviceConfig.DiskLimits, []string, error) {
+	dbVolume, err := d.customVolume(d.config)
+	if err != nil || dbVolume == nil {
+		return nil, nil, err
+	}
+
+	readBps, readIops, writeBps, writeIops, err := storagePools.VolumeIOLimits(dbVolume.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if readBps == 0 && readIops == 0 && writeBps == 0 && writeIops == 0 {
+		return nil, nil, nil
+	}
+
+	isBlock := dbVolume.ContentType == cluster.StoragePoolVolumeContentTypeNameBlock || dbVolume.ContentType == cluster.StoragePoolVolumeContentTypeNameISO
+
+	// The filesystem volumes of VMs are accessed by virtiofsd rather than QEMU, and Ceph RBD volumes are
+	// accessed by QEMU through the network. Those are only limited by the QEMU throttle group of the volume.
+	if d.inst.Type() == instancetype.VM && (!isBlock || d.pool.Driver().Info().Name == "ceph") {
+		return nil, nil, nil
+	}
+
+	volumeName := d.config["source"]
+	if d.config["source.snapshot"] != "" {
+		volumeName = volumeName + shared.SnapshotDelimiter + d.config["source.snapshot"]
+	}
+
+	instProj := d.inst.Project()
+	storageProjectName := project.StorageVolumeProjectFromRecord(&instProj, cluster.StoragePoolVolumeTypeCustom)
+	volStorageName, err := volumeStorageName(storageProjectName, volumeName, dbVolume)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vol := d.pool.GetVolume(storageDrivers.VolumeTypeCustom, storageDrivers.ContentType(dbVolume.ContentType), volStorageName, dbVolume.Config)
+
+	var blocks []string
+	if isBlock {
+		diskPath, err := d.pool.Driver().GetVolumeDiskPath(vol)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed getting disk path: %w", err)
+		}
+
+		_, major, minor, err := unixDeviceAttributes(diskPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		blocks = []string{fmt.Sprint(major, ":", minor)}
+	} else {
+		blocks, err = d.getParentBlocks(vol.MountPath())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i, block := range blocks {
+		blocks[i], err = diskLimitBlock(validBlocks, block)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	limits := &deviceConfig.DiskLimits{
+		ReadBytes:  readBps,
+		ReadIOps:   readIops,
+		WriteBytes: writeBps,
+		WriteIOps:  writeIops,
+	}
+
+	return limits, blocks, nil
+}
+
+// syncVolumeLimits updates the limits of the cgroup shared by the instances using custom storage volumes with
+// I/O limits once the disk is started.
+func (d *disk) syncVolumeLimits() error {
+	return diskSyncVolumeLimits(d.state, nil)
+}
+
+// vmJoinVolumeLimits moves the QEMU process of the VM to the cgroup shared by the instances using custom storage
+// volumes with I/O limits, and updates the limits of that cgroup.
+func (d *disk) vmJoinVolumeLimits() error {
+	err := cgroup.VolumeLimitsAddProcess(diskVolumeLimitsVMCGroup(d.inst), d.inst.InitPID())
+	if err != nil {
+		return err
+	}
+
+	return diskSyncVolumeLimits(d.state, nil)
+}
+
+// DiskSyncVolumeLimits updates the limits of the cgroup shared by the local instances using custom storage
+// volumes with I/O limits, for example after the limits of a volume were changed.
+func DiskSyncVolumeLimits(s *state.State) error {
+	return diskSyncVolumeLimits(s, nil)
+}
+
+// DiskVolumeLimitsShared returns whether the running instance shares the I/O limits of the custom storage volume
+// used by the given disk devices with the other instances using that volume.
+func DiskVolumeLimitsShared(s *state.State, inst instance.Instance, devNames []string) (bool, error) {
+	if !cgroup.VolumeLimitsSupported() {
+		return false, nil
+	}
+
+	pid := inst.InitPID()
+	if pid <= 0 {
+		return false, nil
+	}
+
+	inShared, err := cgroup.VolumeLimitsContains(pid)
+	if err != nil || !inShared {
+		return false, err
+	}
+
+	validBlocks, err := diskValidBlocks()
+	if err != nil {
+		return false, err
+	}
+
+	for _, devName := range devNames {
+		devConfig, ok := inst.ExpandedDevices()[devName]
+		if !ok || !filters.IsDisk(devConfig) {
+			continue
+		}
+
+		dev, err := load(inst, s, inst.Project().Name, devName, devConfig, nil, nil)
+		if err != nil {
+			return false, err
+		}
+
+		diskDev, ok := dev.(*disk)
+		if !ok {
+			continue
+		}
+
+		// Same as when updating the shared cgroup, volumes whose block devices cannot be found are left out.
+		limits, _, err := diskDev.volumeLimitBlocks(validBlocks)
+		if err != nil {
+			logger.Warn("Failed getting shared I/O limits of disk device", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "device": devName, "err": err})
+			continue
+		}
+
+		if limits != nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// diskSyncVolumeLimits sets the io.max setting of the cgroup shared by the local instances using custom storage
+// volumes with I/O limits, from the volumes attached to the running instances in that cgroup. This makes the limits
+// of a volume a single budget for all those instances. When several volumes share a block device, the lowest limits
+// apply. The stopping disk is left out if not nil.
+func diskSyncVolumeLimits(s *state.State, stopping *disk) error {
+	if !cgroup.VolumeLimitsSupported() {
+		return nil
+	}
+
+	diskVolumeLimitsMu.Lock()
+	defer diskVolumeLimitsMu.Unlock()
+
+	insts, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return err
+	}
+
+	validBlocks, err := diskValidBlocks()
+	if err != nil {
+		return err
+	}
+
+	blockLimits := map[string]*deviceConfig.DiskLimits{}
+	for _, inst := range insts {
+		pid := inst.InitPID()
+		if pid <= 0 {
+			continue
+		}
+
+		inShared, err := cgroup.VolumeLimitsContains(pid)
+		if err != nil || !inShared {
+			continue
+		}
+
+		for devName, devConfig := range inst.ExpandedDevices().Filter(filters.IsDisk) {
+			if stopping != nil && inst.ID() == stopping.inst.ID() && devName == stopping.name {
+				continue
+			}
+
+			dev, err := load(inst, s, inst.Project().Name, devName, devConfig, nil, nil)
+			if err != nil {
+				continue
+			}
+
+			diskDev, ok := dev.(*disk)
+			if !ok {
+				continue
+			}
+
+			limits, blocks, err := diskDev.volumeLimitBlocks(validBlocks)
+			if err != nil {
+				logger.Warn("Failed getting shared I/O limits of disk device", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "device": devName, "err": err})
+				continue
+			}
+
+			if limits == nil {
+				continue
+			}
+
+			for _, block := range blocks {
+				blockLimits[block] = combineDiskLimits(blockLimits[block], limits)
+			}
+		}
+	}
+
+	cgLimits := make(map[string]cgroup.VolumeLimit, len(blockLimits))
+	for block, limits := range blockLimits {
+		cgLimits[block] = cgroup.VolumeLimit{
+			ReadBytes:  limits.ReadBytes,
+			ReadIOps:   limits.ReadIOps,
+			WriteBytes: limits.WriteBytes,
+			WriteIOps:  limits.WriteIOps,
+		}
+	}
+
+	return cgroup.VolumeLimitsSet(cgLimits)
+}
+
 func (d *disk) getParentBlocks(path string) ([]string, error) {
 	var devices []string
 	var dev []string
@@ -0,0 +1,90 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+)
+
+func TestCombineDiskLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		devLimits  *deviceConfig.DiskLimits
+		volLimits  *deviceConfig.DiskLimits
+		wantLimits *deviceConfig.DiskLimits
+	}{
+		{
+			name: "No limits",
+		},
+		{
+			name:       "Device limits only",
+			devLimits:  &deviceConfig.DiskLimits{ReadBytes: 100, WriteIOps: 10},
+			wantLimits: &deviceConfig.DiskLimits{ReadBytes: 100, WriteIOps: 10},
+		},
+		{
+			name:       "Volume limits only",
+			volLimits:  &deviceConfig.DiskLimits{ReadIOps: 20, Group: "lxd_volume_1"},
+			wantLimits: &deviceConfig.DiskLimits{ReadIOps: 20, Group: "lxd_volume_1"},
+		},
+		{
+			name:       "Lowest limit applies",
+			devLimits:  &deviceConfig.DiskLimits{ReadBytes: 100, ReadIOps: 50, WriteBytes: 300, WriteIOps: 10},
+			volLimits:  &deviceConfig.DiskLimits{ReadBytes: 200, ReadIOps: 20, WriteBytes: 300, WriteIOps: 30, Group: "lxd_volume_1"},
+			wantLimits: &deviceConfig.DiskLimits{ReadBytes: 100, ReadIOps: 20, WriteBytes: 300, WriteIOps: 10, Group: "lxd_volume_1"},
+		},
+		{
+			name:       "Unset limits do not apply",
+			devLimits:  &deviceConfig.DiskLimits{ReadBytes: 100},
+			volLimits:  &deviceConfig.DiskLimits{WriteIOps: 30, Group: "lxd_volume_1"},
+			wantLimits: &deviceConfig.DiskLimits{ReadBytes: 100, WriteIOps: 30, Group: "lxd_volume_1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLimits, combineDiskLimits(tt.devLimits, tt.volLimits))
+		})
+	}
+}
+
+func TestDiskLimitBlock(t *testing.T) {
+	validBlocks := []string{"8:0", "253:0"}
+
+	tests := []struct {
+		name      string
+		block     string
+		wantBlock string
+		wantErr   bool
+	}{
+		{
+			name:      "Full block device",
+			block:     "253:0",
+			wantBlock: "253:0",
+		},
+		{
+			name:      "Partition uses its parent",
+			block:     "8:2",
+			wantBlock: "8:0",
+		},
+		{
+			name:    "Unknown block device",
+			block:   "230:16",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := diskLimitBlock(validBlocks, tt.block)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBlock, block)
+		})
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"strings"
 
 	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/network"
 	"github.com/canonical/lxd/lxd/network/acl"
 	"github.com/canonical/lxd/shared/validate"
 )
 
 // nicValidationRules returns config validation rules for nic devices.
 func nicValidationRules(requiredFields []string, optionalFields []string, instConf instance.ConfigReader) map[string]func(value string) error {
+	bgpRules := network.BGPPathAttributesValidationRules()
+
 	// Define a set of default validators for each field name.
 	defaultValidators := map[string]func(value string) error{
 		// lxdmeta:generate(entities=device-nic-ovn; group=device-conf; key=acceleration)
@@ -544,6 +547,66 @@ func nicValidationRules(requiredFields []string, optionalFields []string, instCo
 		//  managed: no
 		//  shortdesc: IPv6 static routes to route to NIC
 		"ipv6.routes.external": validate.Optional(validate.IsListOf(validate.IsNetworkV6)),
+		// lxdmeta:generate(entities=device-nic-bridged; group=device-conf; key=bgp.communities)
+		// Specify a comma-separated list of standard communities in the `ASN:VALUE` format, or the well-known `no-export`, `no-advertise` and `blackhole` communities.
+		// Overrides the network's `bgp.communities` for the external routes of the NIC.
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: BGP communities attached to the external routes
+
+		// lxdmeta:generate(entities=device-nic-ovn; group=device-conf; key=bgp.communities)
+		// Specify a comma-separated list of standard communities in the `ASN:VALUE` format, or the well-known `no-export`, `no-advertise` and `blackhole` communities.
+		// Overrides the network's `bgp.communities` for the external routes of the NIC.
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: BGP communities attached to the external routes
+		"bgp.communities": bgpRules["bgp.communities"],
+		// lxdmeta:generate(entities=device-nic-bridged; group=device-conf; key=bgp.large_communities)
+		// Specify a comma-separated list of large communities in the `GLOBAL:LOCAL1:LOCAL2` format.
+		// Overrides the network's `bgp.large_communities` for the external routes of the NIC.
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: BGP large communities attached to the external routes
+
+		// lxdmeta:generate(entities=device-nic-ovn; group=device-conf; key=bgp.large_communities)
+		// Specify a comma-separated list of large communities in the `GLOBAL:LOCAL1:LOCAL2` format.
+		// Overrides the network's `bgp.large_communities` for the external routes of the NIC.
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: BGP large communities attached to the external routes
+		"bgp.large_communities": bgpRules["bgp.large_communities"],
+		// lxdmeta:generate(entities=device-nic-bridged; group=device-conf; key=bgp.med)
+		// Overrides the network's `bgp.med` for the external routes of the NIC.
+		// ---
+		//  type: integer
+		//  managed: no
+		//  shortdesc: BGP multi-exit discriminator of the external routes
+
+		// lxdmeta:generate(entities=device-nic-ovn; group=device-conf; key=bgp.med)
+		// Overrides the network's `bgp.med` for the external routes of the NIC.
+		// ---
+		//  type: integer
+		//  managed: no
+		//  shortdesc: BGP multi-exit discriminator of the external routes
+		"bgp.med": bgpRules["bgp.med"],
+		// lxdmeta:generate(entities=device-nic-bridged; group=device-conf; key=bgp.as_path_prepend)
+		// Overrides the network's `bgp.as_path_prepend` for the external routes of the NIC.
+		// ---
+		//  type: integer
+		//  managed: no
+		//  shortdesc: AS path prepending of the external routes
+
+		// lxdmeta:generate(entities=device-nic-ovn; group=device-conf; key=bgp.as_path_prepend)
+		// Overrides the network's `bgp.as_path_prepend` for the external routes of the NIC.
+		// ---
+		//  type: integer
+		//  managed: no
+		//  shortdesc: AS path prepending of the external routes
+		"bgp.as_path_prepend": bgpRules["bgp.as_path_prepend"],
 		// lxdmeta:generate(entities=device-nic-ovn; group=device-conf; key=nested)
 		// See also {config:option}`device-nic-ovn-device-conf:vlan`.
 		// ---
@@ -84,6 +84,10 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 		"ipv6.routes",
 		"ipv4.routes.external",
 		"ipv6.routes.external",
+		"bgp.communities",
+		"bgp.large_communities",
+		"bgp.med",
+		"bgp.as_path_prepend",
 		"security.mac_filtering",
 		"security.ipv4_filtering",
 		"security.ipv6_filtering",
@@ -458,7 +462,7 @@ func (d *nicBridged) UpdatableFields(oldDevice Type) []string {
 		return []string{}
 	}
 
-	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering"}
+	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "bgp.communities", "bgp.large_communities", "bgp.med", "bgp.as_path_prepend", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering"}
 }
 
 // Add is run when a device is added to a non-snapshot instance whether or not the instance is running.
@@ -630,22 +634,31 @@ func (d *nicBridged) Start() (*deviceConfig.RunConfig, error) {
 		}
 
 		if brNetfilterEnabled {
-			var listenAddresses map[int64]string
+			var forwardListenAddresses map[int64]string
+			var loadBalancerListenAddresses map[int64]string
 
 			err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-				listenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, d.network.ID(), true)
+				forwardListenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, d.network.ID(), true)
+				if err != nil {
+					return fmt.Errorf("Failed loading network forwards: %w", err)
+				}
 
-				return err
+				loadBalancerListenAddresses, err = tx.GetNetworkLoadBalancerListenAddresses(ctx, d.network.ID(), true)
+				if err != nil {
+					return fmt.Errorf("Failed loading network load balancers: %w", err)
+				}
+
+				return nil
 			})
 			if err != nil {
-				return nil, fmt.Errorf("Failed loading network forwards: %w", err)
+				return nil, err
 			}
 
-			// If br_netfilter is enabled and bridge has forwards, we enable hairpin mode on NIC's
-			// bridge port in case any of the forwards target this NIC and the instance attempts to
-			// connect to the forward's listener. Without hairpin mode on the target of the forward
-			// will not be able to connect to the listener.
-			if len(listenAddresses) > 0 {
+			// If br_netfilter is enabled and bridge has forwards or load balancers, we enable hairpin mode
+			// on NIC's bridge port in case any of them target this NIC and the instance attempts to
+			// connect to their listener. Without hairpin mode on the target will not be able to connect
+			// to the listener.
+			if len(forwardListenAddresses) > 0 || len(loadBalancerListenAddresses) > 0 {
 				link := &ip.Link{Name: saveData["host_name"]}
 				err = link.BridgeLinkSetHairpin(true)
 				if err != nil {
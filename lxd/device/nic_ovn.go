@@ -93,6 +93,10 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader) error {
 		"ipv6.routes",
 		"ipv4.routes.external",
 		"ipv6.routes.external",
+		"bgp.communities",
+		"bgp.large_communities",
+		"bgp.med",
+		"bgp.as_path_prepend",
 		"boot.priority",
 		"security.acls",
 		"security.acls.default.ingress.action",
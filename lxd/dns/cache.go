@@ -0,0 +1,242 @@
+package dns
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// zoneRefreshInterval is how long the cached content of a zone is used before being rendered again.
+// Zones are rendered again in the background when queried, so queries are always answered from memory.
+const zoneRefreshInterval = 30 * time.Second
+
+// cachedZone holds the configuration of a zone and, if the zone answers queries, its parsed records.
+// Apart from the refreshing flag, it is never modified once cached: a refresh replaces it.
+type cachedZone struct {
+	info     api.NetworkZone
+	records  *zoneRecords
+	rendered time.Time
+
+	// Set (with the cache lock held for writing) while the zone is rendered again in the background.
+	refreshing bool
+}
+
+// zoneCache caches the zones used to answer queries.
+type zoneCache struct {
+	mu sync.RWMutex
+
+	// Incremented whenever the cache is invalidated, so that content loaded beforehand isn't stored.
+	generation uint64
+
+	// Names of all zones, nil until loaded.
+	names map[string]bool
+
+	// Zones looked up by queries, keyed by name.
+	zones map[string]*cachedZone
+}
+
+// zoneLister returns a function listing the names of all network zones from the database.
+func zoneLister(cluster *db.Cluster) func() ([]string, error) {
+	return func() ([]string, error) {
+		var names []string
+
+		err := cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			zones, err := tx.GetNetworkZones(ctx)
+			if err != nil {
+				return err
+			}
+
+			for name := range zones {
+				names = append(names, name)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return names, nil
+	}
+}
+
+// InvalidateZones clears the cached zones so that changes to the zones or their records are taken into
+// account by the next queries.
+func (s *Server) InvalidateZones() {
+	s.zones.mu.Lock()
+	defer s.zones.mu.Unlock()
+
+	s.zones.generation++
+	s.zones.names = nil
+	s.zones.zones = nil
+}
+
+// zoneNames returns the names of all zones, or nil if the zones can't be listed.
+func (s *Server) zoneNames() (map[string]bool, error) {
+	if s.zoneLister == nil {
+		return nil, nil
+	}
+
+	s.zones.mu.RLock()
+	names := s.zones.names
+	generation := s.zones.generation
+	s.zones.mu.RUnlock()
+
+	if names != nil {
+		return names, nil
+	}
+
+	list, err := s.zoneLister()
+	if err != nil {
+		return nil, err
+	}
+
+	names = make(map[string]bool, len(list))
+	for _, name := range list {
+		names[name] = true
+	}
+
+	s.zones.mu.Lock()
+	if s.zones.generation == generation {
+		s.zones.names = names
+	}
+
+	s.zones.mu.Unlock()
+
+	return names, nil
+}
+
+// findZone returns the zone holding the given name, which is the zone with the longest matching name.
+// It returns nil if the name doesn't belong to any zone.
+func (s *Server) findZone(name string) (*cachedZone, error) {
+	names, err := s.zoneNames()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := dns.SplitDomainName(name)
+	for i := range labels {
+		zoneName := strings.Join(labels[i:], ".")
+
+		// Without a list of the zones, each suffix of the name is looked up.
+		if names != nil && !names[zoneName] {
+			continue
+		}
+
+		zone, err := s.loadZone(zoneName)
+		if err != nil {
+			if names == nil {
+				continue
+			}
+
+			return nil, err
+		}
+
+		return zone, nil
+	}
+
+	return nil, nil
+}
+
+// loadZone returns the cached zone with the given name, rendering it if it isn't cached yet.
+// If the cached zone is outdated, it is returned as is and rendered again in the background.
+func (s *Server) loadZone(name string) (*cachedZone, error) {
+	s.zones.mu.RLock()
+	zone := s.zones.zones[name]
+	generation := s.zones.generation
+	s.zones.mu.RUnlock()
+
+	if zone != nil {
+		if time.Since(zone.rendered) > zoneRefreshInterval {
+			s.zones.mu.Lock()
+			refresh := !zone.refreshing && s.zones.zones[name] == zone
+			if refresh {
+				zone.refreshing = true
+			}
+
+			s.zones.mu.Unlock()
+
+			if refresh {
+				go s.refreshZone(name, zone, generation)
+			}
+		}
+
+		return zone, nil
+	}
+
+	zone, err := s.renderZone(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.storeZone(name, zone, generation)
+
+	return zone, nil
+}
+
+// refreshZone renders the given cached zone again and replaces it in the cache.
+// On failure, the outdated zone keeps being used.
+func (s *Server) refreshZone(name string, old *cachedZone, generation uint64) {
+	zone, err := s.renderZone(name)
+	if err != nil {
+		logger.Warn("Failed refreshing DNS zone", logger.Ctx{"zone": name, "err": err})
+
+		s.zones.mu.Lock()
+		old.refreshing = false
+		s.zones.mu.Unlock()
+
+		return
+	}
+
+	s.storeZone(name, zone, generation)
+}
+
+// storeZone caches the given zone unless the cache was invalidated since it was loaded.
+func (s *Server) storeZone(name string, zone *cachedZone, generation uint64) {
+	s.zones.mu.Lock()
+	defer s.zones.mu.Unlock()
+
+	if s.zones.generation != generation {
+		return
+	}
+
+	if s.zones.zones == nil {
+		s.zones.zones = map[string]*cachedZone{}
+	}
+
+	s.zones.zones[name] = zone
+}
+
+// renderZone loads the zone with the given name, along with its records if it answers queries.
+func (s *Server) renderZone(name string) (*cachedZone, error) {
+	zone, err := s.zoneRetriever(name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := &cachedZone{info: zone.Info, rendered: time.Now()}
+	if !queryEnabled(zone.Info) {
+		return cached, nil
+	}
+
+	zone, err = s.zoneRetriever(name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	cached.info = zone.Info
+
+	cached.records, err = parseZoneRecords(zone.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return cached, nil
+}
@@ -0,0 +1,124 @@
+package dns
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// CanonicalNameLess returns whether name a sorts before name b in the canonical DNS name order
+// defined in RFC 4034 section 6.1, comparing labels from the most significant one.
+func CanonicalNameLess(a string, b string) bool {
+	labelsA := wireLabels(a)
+	labelsB := wireLabels(b)
+
+	for i := 1; i <= len(labelsA) && i <= len(labelsB); i++ {
+		labelA := labelsA[len(labelsA)-i]
+		labelB := labelsB[len(labelsB)-i]
+
+		if labelA != labelB {
+			return labelA < labelB
+		}
+	}
+
+	return len(labelsA) < len(labelsB)
+}
+
+// wireLabels returns the lowercased labels of the name in wire format, with any escaped characters decoded.
+func wireLabels(name string) []string {
+	buf := make([]byte, 256)
+
+	n, err := dns.PackDomainName(dns.Fqdn(strings.ToLower(name)), buf, 0, nil, false)
+	if err != nil {
+		return dns.SplitDomainName(strings.ToLower(name))
+	}
+
+	var labels []string
+	for i := 0; i < n && buf[i] != 0; i += int(buf[i]) + 1 {
+		labels = append(labels, string(buf[i+1:i+1+int(buf[i])]))
+	}
+
+	return labels
+}
+
+// signatures returns the RRSIG records covering the RRset of the given owner and type.
+func (z *zoneRecords) signatures(owner string, rrtype uint16) []dns.RR {
+	var sigs []dns.RR
+
+	for _, rr := range z.records {
+		sig, ok := rr.(*dns.RRSIG)
+		if !ok || sig.TypeCovered != rrtype || !strings.EqualFold(sig.Hdr.Name, owner) {
+			continue
+		}
+
+		sigs = append(sigs, dns.Copy(sig))
+	}
+
+	return sigs
+}
+
+// coveringNSEC returns the NSEC record matching the name or, if there is none, the one
+// proving that the name doesn't exist.
+func (z *zoneRecords) coveringNSEC(name string) *dns.NSEC {
+	for _, rr := range z.records {
+		nsec, ok := rr.(*dns.NSEC)
+		if !ok {
+			continue
+		}
+
+		if strings.EqualFold(nsec.Hdr.Name, name) {
+			return nsec
+		}
+
+		// The last record of the chain points back to the zone apex.
+		lastRecord := !CanonicalNameLess(nsec.Hdr.Name, nsec.NextDomain)
+		if CanonicalNameLess(nsec.Hdr.Name, name) && (lastRecord || CanonicalNameLess(name, nsec.NextDomain)) {
+			return nsec
+		}
+	}
+
+	return nil
+}
+
+// addDNSSEC adds the signatures of the RRsets in the response along with the NSEC records
+// proving negative answers.
+func (z *zoneRecords) addDNSSEC(m *dns.Msg, qname string) {
+	type rrset struct {
+		owner  string
+		rrtype uint16
+	}
+
+	addSignatures := func(section []dns.RR) []dns.RR {
+		seen := []rrset{}
+		for _, rr := range section {
+			set := rrset{owner: strings.ToLower(rr.Header().Name), rrtype: rr.Header().Rrtype}
+			if set.rrtype == dns.TypeRRSIG || slices.Contains(seen, set) {
+				continue
+			}
+
+			seen = append(seen, set)
+			section = append(section, z.signatures(set.owner, set.rrtype)...)
+		}
+
+		return section
+	}
+
+	if len(m.Answer) == 0 && z.soa != nil {
+		proofs := []string{qname}
+		if m.Rcode == dns.RcodeNameError {
+			// Also prove that no wildcard record could have matched.
+			proofs = append(proofs, "*."+z.soa.Header().Name)
+		}
+
+		for _, name := range proofs {
+			nsec := z.coveringNSEC(name)
+			if nsec != nil && !slices.ContainsFunc(m.Ns, func(rr dns.RR) bool { return rr.String() == nsec.String() }) {
+				m.Ns = append(m.Ns, dns.Copy(nsec))
+			}
+		}
+	}
+
+	m.Answer = addSignatures(m.Answer)
+	m.Ns = addSignatures(m.Ns)
+}
@@ -0,0 +1,141 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSignedZoneContent = `
+lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1 120 60 86400 30
+lxd.example.net. 3600 IN RRSIG SOA 13 3 3600 20261101000000 20261001000000 1111 lxd.example.net. c2lnbmF0dXJl
+lxd.example.net. 300 IN NS ns1.lxd.example.net.
+lxd.example.net. 300 IN RRSIG NS 13 3 300 20261101000000 20261001000000 1111 lxd.example.net. c2lnbmF0dXJl
+lxd.example.net. 30 IN NSEC c1.lxd.example.net. NS SOA RRSIG NSEC
+lxd.example.net. 30 IN RRSIG NSEC 13 3 30 20261101000000 20261001000000 1111 lxd.example.net. c2lnbmF0dXJl
+c1.lxd.example.net. 300 IN A 192.0.2.42
+c1.lxd.example.net. 300 IN RRSIG A 13 4 300 20261101000000 20261001000000 1111 lxd.example.net. c2lnbmF0dXJl
+c1.lxd.example.net. 30 IN NSEC www.lxd.example.net. A RRSIG NSEC
+c1.lxd.example.net. 30 IN RRSIG NSEC 13 4 30 20261101000000 20261001000000 1111 lxd.example.net. c2lnbmF0dXJl
+www.lxd.example.net. 300 IN CNAME c1.lxd.example.net.
+www.lxd.example.net. 300 IN RRSIG CNAME 13 4 300 20261101000000 20261001000000 1111 lxd.example.net. c2lnbmF0dXJl
+www.lxd.example.net. 30 IN NSEC lxd.example.net. CNAME RRSIG NSEC
+www.lxd.example.net. 30 IN RRSIG NSEC 13 4 30 20261101000000 20261001000000 1111 lxd.example.net. c2lnbmF0dXJl
+lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1 120 60 86400 30
+`
+
+func TestCanonicalNameLess(t *testing.T) {
+	t.Parallel()
+
+	// Names in canonical order as listed in RFC 4034 section 6.1.
+	names := []string{
+		"example.",
+		"a.example.",
+		"yljkjljk.a.example.",
+		"Z.a.example.",
+		"zABC.a.EXAMPLE.",
+		"z.example.",
+		"\\001.z.example.",
+		"*.z.example.",
+	}
+
+	for i := range names {
+		for j := range names {
+			assert.Equal(t, i < j, CanonicalNameLess(names[i], names[j]), "%s < %s", names[i], names[j])
+		}
+	}
+}
+
+func TestServeDNS_QueryDNSSEC(t *testing.T) {
+	t.Parallel()
+
+	h := newQueryHandler(map[string]string{"dns.query.enabled": "true"}, testSignedZoneContent)
+
+	query := func(qname string, qtype uint16, do bool) *dns.Msg {
+		w := newMockWriter("192.0.2.10:12345", nil)
+		r := new(dns.Msg)
+		r.SetQuestion(qname, qtype)
+		r.SetEdns0(4096, do)
+
+		h.ServeDNS(w, r)
+
+		require.NotNil(t, w.written)
+		return w.written
+	}
+
+	countTypes := func(section []dns.RR) map[uint16]int {
+		types := map[uint16]int{}
+		for _, rr := range section {
+			types[rr.Header().Rrtype]++
+		}
+
+		return types
+	}
+
+	t.Run("Signatures are only added when requested", func(t *testing.T) {
+		m := query("c1.lxd.example.net.", dns.TypeA, false)
+		assert.Equal(t, map[uint16]int{dns.TypeA: 1}, countTypes(m.Answer))
+		assert.False(t, m.IsEdns0().Do())
+	})
+
+	t.Run("Positive answer", func(t *testing.T) {
+		m := query("c1.lxd.example.net.", dns.TypeA, true)
+		assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+		assert.Equal(t, map[uint16]int{dns.TypeA: 1, dns.TypeRRSIG: 1}, countTypes(m.Answer))
+		assert.True(t, m.IsEdns0().Do())
+	})
+
+	t.Run("CNAME chain", func(t *testing.T) {
+		m := query("www.lxd.example.net.", dns.TypeA, true)
+		assert.Equal(t, map[uint16]int{dns.TypeCNAME: 1, dns.TypeA: 1, dns.TypeRRSIG: 2}, countTypes(m.Answer))
+	})
+
+	t.Run("SOA", func(t *testing.T) {
+		m := query("lxd.example.net.", dns.TypeSOA, true)
+		assert.Equal(t, map[uint16]int{dns.TypeSOA: 1, dns.TypeRRSIG: 1}, countTypes(m.Answer))
+	})
+
+	t.Run("No data", func(t *testing.T) {
+		m := query("c1.lxd.example.net.", dns.TypeTXT, true)
+		assert.Equal(t, dns.RcodeSuccess, m.Rcode)
+		assert.Empty(t, m.Answer)
+		assert.Equal(t, map[uint16]int{dns.TypeSOA: 1, dns.TypeNSEC: 1, dns.TypeRRSIG: 2}, countTypes(m.Ns))
+	})
+
+	t.Run("Name error", func(t *testing.T) {
+		m := query("d1.lxd.example.net.", dns.TypeA, true)
+		assert.Equal(t, dns.RcodeNameError, m.Rcode)
+		assert.Empty(t, m.Answer)
+
+		// The NSEC records prove both that the name and the wildcard don't exist.
+		types := countTypes(m.Ns)
+		assert.Equal(t, 1, types[dns.TypeSOA])
+		assert.Equal(t, 2, types[dns.TypeNSEC])
+		assert.Equal(t, 3, types[dns.TypeRRSIG])
+
+		var owners []string
+		for _, rr := range m.Ns {
+			if rr.Header().Rrtype == dns.TypeNSEC {
+				owners = append(owners, rr.Header().Name)
+			}
+		}
+
+		assert.ElementsMatch(t, []string{"c1.lxd.example.net.", "lxd.example.net."}, owners)
+	})
+
+	t.Run("Name after the last NSEC record", func(t *testing.T) {
+		m := query("zzz.lxd.example.net.", dns.TypeA, true)
+		assert.Equal(t, dns.RcodeNameError, m.Rcode)
+
+		var owners []string
+		for _, rr := range m.Ns {
+			if rr.Header().Rrtype == dns.TypeNSEC {
+				owners = append(owners, rr.Header().Name)
+			}
+		}
+
+		assert.Contains(t, owners, "www.lxd.example.net.")
+	})
+}
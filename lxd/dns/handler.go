@@ -30,10 +30,6 @@ func writeRcode(w dns.ResponseWriter, r *dns.Msg, rcode int) {
 
 // ServeDNS handles each DNS request.
 func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
-	// Don't allow concurrent queries.
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
 	// Check if we're ready to serve queries.
 	if d.server.zoneRetriever == nil {
 		writeRcode(w, r, dns.RcodeServerFailure)
@@ -46,20 +42,40 @@ func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
-	// Check that it's a supported request type.
-	if r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && r.Question[0].Qtype != dns.TypeSOA {
-		writeRcode(w, r, dns.RcodeNotImplemented)
-		return
-	}
-
 	// Extract the request information.
-	name := strings.TrimSuffix(r.Question[0].Name, ".")
 	ip, _, err := net.SplitHostPort(w.RemoteAddr().String())
 	if err != nil {
 		writeRcode(w, r, dns.RcodeServerFailure)
 		return
 	}
 
+	// Zone transfers and SOA requests from peers.
+	qtype := r.Question[0].Qtype
+	if qtype == dns.TypeAXFR || qtype == dns.TypeIXFR || qtype == dns.TypeSOA {
+		if d.serveTransfer(w, r, ip) {
+			return
+		}
+
+		// Zone transfers are only available to peers, other SOA requests are handled as queries.
+		if qtype != dns.TypeSOA {
+			// On failure, return NXDOMAIN to avoid information leaks.
+			writeRcode(w, r, dns.RcodeNameError)
+			return
+		}
+	}
+
+	d.serveQuery(w, r, ip)
+}
+
+// serveTransfer answers zone transfer and SOA requests from the peers of the requested zone.
+// It returns false without writing a response if the zone doesn't exist or the client isn't a peer.
+func (d *dnsHandler) serveTransfer(w dns.ResponseWriter, r *dns.Msg, ip string) bool {
+	// Don't allow concurrent zone transfers. Queries are answered from the zone cache instead.
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	name := strings.TrimSuffix(r.Question[0].Name, ".")
+
 	// Prepare the response.
 	m := new(dns.Msg)
 	m.SetReply(r)
@@ -68,9 +84,7 @@ func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	// Load the zone.
 	zone, err := d.server.zoneRetriever(name, r.Question[0].Qtype != dns.TypeSOA)
 	if err != nil {
-		// On failure, return NXDOMAIN.
-		writeRcode(w, r, dns.RcodeNameError)
-		return
+		return false
 	}
 
 	tsig := r.IsTsig()
@@ -78,9 +92,7 @@ func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 
 	// Check access.
 	if !d.isAllowed(zone.Info, ip, tsig, tsigOK) {
-		// On auth failure, return NXDOMAIN to avoid information leaks.
-		writeRcode(w, r, dns.RcodeNameError)
-		return
+		return false
 	}
 
 	zoneRR := dns.NewZoneParser(strings.NewReader(zone.Content), "", "")
@@ -91,7 +103,7 @@ func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 			if err != nil {
 				logger.Errorf("Bad DNS record in zone %q: %v", name, err)
 				writeRcode(w, r, dns.RcodeFormatError)
-				return
+				return true
 			}
 
 			break
@@ -108,6 +120,8 @@ func (d *dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	if err != nil {
 		logger.Error("Cannot write message", logger.Ctx{"err": err})
 	}
+
+	return true
 }
 
 func (d *dnsHandler) isAllowed(zone api.NetworkZone, ip string, tsig *dns.TSIG, tsigStatus bool) bool {
@@ -0,0 +1,252 @@
+package dns
+
+import (
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// maxCNAMEChain is the maximum number of CNAME records followed within a zone.
+const maxCNAMEChain = 8
+
+// maxUDPSize is the largest UDP payload size advertised in EDNS responses.
+const maxUDPSize = 1232
+
+// zoneRecords holds the parsed records of a zone.
+type zoneRecords struct {
+	soa     dns.RR
+	records []dns.RR
+}
+
+// queryEnabled returns whether the zone answers queries.
+func queryEnabled(zone api.NetworkZone) bool {
+	return shared.IsTrue(zone.Config["dns.query.enabled"])
+}
+
+// queryAllowed returns whether the client IP is allowed to query the zone.
+func queryAllowed(zone api.NetworkZone, ip string) bool {
+	sources := shared.SplitNTrimSpace(zone.Config["dns.query.sources"], ",", -1, true)
+	if len(sources) == 0 {
+		return true
+	}
+
+	clientIP := net.ParseIP(ip)
+	if clientIP == nil {
+		return false
+	}
+
+	for _, source := range sources {
+		_, subnet, err := net.ParseCIDR(source)
+		if err != nil {
+			continue
+		}
+
+		if subnet.Contains(clientIP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseZoneRecords parses the records of the given zone content.
+func parseZoneRecords(content string) (*zoneRecords, error) {
+	records := &zoneRecords{}
+
+	zoneRR := dns.NewZoneParser(strings.NewReader(content), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			err := zoneRR.Err()
+			if err != nil {
+				return nil, err
+			}
+
+			break
+		}
+
+		// The zone content starts and ends with the SOA record.
+		if rr.Header().Rrtype == dns.TypeSOA {
+			if records.soa == nil {
+				records.soa = rr
+			}
+
+			continue
+		}
+
+		records.records = append(records.records, rr)
+	}
+
+	return records, nil
+}
+
+// answer fills in the response to the question from the zone records.
+func (z *zoneRecords) answer(m *dns.Msg, q dns.Question) {
+	qname := strings.ToLower(dns.Fqdn(q.Name))
+
+	zoneName := ""
+	if z.soa != nil {
+		zoneName = strings.ToLower(z.soa.Header().Name)
+
+		if qname == zoneName && (q.Qtype == dns.TypeSOA || q.Qtype == dns.TypeANY) {
+			m.Answer = append(m.Answer, dns.Copy(z.soa))
+
+			if q.Qtype == dns.TypeSOA {
+				return
+			}
+		}
+	}
+
+	for range maxCNAMEChain {
+		var cname dns.RR
+		answered := false
+		found := false
+
+		for _, rr := range z.records {
+			if strings.ToLower(rr.Header().Name) != qname {
+				continue
+			}
+
+			found = true
+
+			if q.Qtype == dns.TypeANY || rr.Header().Rrtype == q.Qtype {
+				m.Answer = append(m.Answer, dns.Copy(rr))
+				answered = true
+			} else if rr.Header().Rrtype == dns.TypeCNAME {
+				cname = rr
+			}
+		}
+
+		if !found && len(m.Answer) == 0 && qname != zoneName && !z.hasDescendant(qname) {
+			m.Rcode = dns.RcodeNameError
+		}
+
+		if answered || cname == nil {
+			break
+		}
+
+		// Follow CNAME records pointing within the zone.
+		m.Answer = append(m.Answer, dns.Copy(cname))
+
+		qname = strings.ToLower(cname.(*dns.CNAME).Target)
+		if zoneName == "" || !dns.IsSubDomain(zoneName, qname) {
+			break
+		}
+	}
+
+	// Negative answers carry the SOA record in the authority section.
+	if len(m.Answer) == 0 && z.soa != nil {
+		m.Ns = append(m.Ns, dns.Copy(z.soa))
+	}
+}
+
+// hasDescendant returns whether the zone has records below the given name (empty non-terminal).
+func (z *zoneRecords) hasDescendant(name string) bool {
+	for _, rr := range z.records {
+		if dns.IsSubDomain(name, strings.ToLower(rr.Header().Name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serveQuery answers a query for records of a zone which has queries enabled.
+func (d *dnsHandler) serveQuery(w dns.ResponseWriter, r *dns.Msg, ip string) {
+	q := r.Question[0]
+	name := strings.ToLower(strings.TrimSuffix(q.Name, "."))
+
+	zone, err := d.server.findZone(name)
+	if err != nil {
+		logger.Error("Failed loading DNS zones", logger.Ctx{"name": name, "err": err})
+		writeRcode(w, r, dns.RcodeServerFailure)
+		return
+	}
+
+	if zone == nil {
+		// The server isn't authoritative for the name. SOA requests keep the response of servers only
+		// providing zone transfers.
+		if q.Qtype == dns.TypeSOA {
+			writeRcode(w, r, dns.RcodeNameError)
+		} else {
+			writeRcode(w, r, dns.RcodeRefused)
+		}
+
+		return
+	}
+
+	if !queryEnabled(zone.info) {
+		// Keep the responses of servers only providing zone transfers.
+		if q.Qtype == dns.TypeSOA {
+			writeRcode(w, r, dns.RcodeNameError)
+		} else {
+			writeRcode(w, r, dns.RcodeNotImplemented)
+		}
+
+		return
+	}
+
+	if !queryAllowed(zone.info, ip) {
+		writeRcode(w, r, dns.RcodeRefused)
+		return
+	}
+
+	// Validate EDNS.
+	opt := r.IsEdns0()
+	if opt != nil && opt.Version() != 0 {
+		m := new(dns.Msg)
+		m.SetRcode(r, dns.RcodeBadVers)
+		m.SetEdns0(maxUDPSize, false)
+
+		err := w.WriteMsg(m)
+		if err != nil {
+			logger.Error("Cannot write message", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	records := zone.records
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+
+	if q.Qclass != dns.ClassINET && q.Qclass != dns.ClassANY {
+		m.Rcode = dns.RcodeRefused
+	} else {
+		records.answer(m, q)
+
+		// Add the DNSSEC records if requested by the client.
+		if opt != nil && opt.Do() {
+			records.addDNSSEC(m, dns.Fqdn(name))
+		}
+	}
+
+	// Limit the size of UDP responses, setting the truncation bit so that the client retries over TCP.
+	_, isUDP := w.RemoteAddr().(*net.UDPAddr)
+
+	size := dns.MaxMsgSize
+	if isUDP {
+		size = dns.MinMsgSize
+		if opt != nil {
+			size = max(dns.MinMsgSize, min(int(opt.UDPSize()), maxUDPSize))
+		}
+	}
+
+	if opt != nil {
+		m.SetEdns0(maxUDPSize, opt.Do())
+	}
+
+	m.Truncate(size)
+
+	err = w.WriteMsg(m)
+	if err != nil {
+		logger.Error("Cannot write message", logger.Ctx{"err": err})
+	}
+}
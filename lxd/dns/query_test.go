@@ -0,0 +1,309 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+const testZoneContent = `
+lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1 120 60 86400 30
+lxd.example.net. 300 IN NS ns1.lxd.example.net.
+c1.lxd.example.net. 300 IN A 192.0.2.42
+c1.lxd.example.net. 300 IN AAAA fd42::42
+www.lxd.example.net. 300 IN CNAME c1.lxd.example.net.
+ext.lxd.example.net. 300 IN CNAME www.example.org.
+demo.lxd.example.net. 300 IN TXT "hello"
+_http._tcp.lxd.example.net. 300 IN SRV 10 5 80 c1.lxd.example.net.
+lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1 120 60 86400 30
+`
+
+// newQueryHandler returns a handler serving the test zone with the given configuration.
+func newQueryHandler(config map[string]string, content string) *dnsHandler {
+	zone := &Zone{
+		Info: api.NetworkZone{
+			Name:   "lxd.example.net",
+			Config: config,
+		},
+		Content: content,
+	}
+
+	s := &Server{
+		zoneRetriever: func(name string, full bool) (*Zone, error) {
+			if name != zone.Info.Name {
+				return nil, assert.AnError
+			}
+
+			return zone, nil
+		},
+		zoneLister: func() ([]string, error) {
+			return []string{zone.Info.Name}, nil
+		},
+	}
+
+	return &dnsHandler{server: s}
+}
+
+// newMockUDPWriter returns a mockResponseWriter for a client connected over UDP.
+func newMockUDPWriter(addr string) *mockResponseWriter {
+	udpAddr, _ := net.ResolveUDPAddr("udp", addr)
+	return &mockResponseWriter{remoteAddr: udpAddr}
+}
+
+func TestServeDNS_Query(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		qname      string
+		qtype      uint16
+		wantRcode  int
+		wantAnswer []uint16
+		wantNs     bool
+	}{
+		{"A record", "c1.lxd.example.net.", dns.TypeA, dns.RcodeSuccess, []uint16{dns.TypeA}, false},
+		{"Mixed case name", "C1.LXD.example.net.", dns.TypeAAAA, dns.RcodeSuccess, []uint16{dns.TypeAAAA}, false},
+		{"TXT record", "demo.lxd.example.net.", dns.TypeTXT, dns.RcodeSuccess, []uint16{dns.TypeTXT}, false},
+		{"SRV record", "_http._tcp.lxd.example.net.", dns.TypeSRV, dns.RcodeSuccess, []uint16{dns.TypeSRV}, false},
+		{"CNAME within zone is followed", "www.lxd.example.net.", dns.TypeA, dns.RcodeSuccess, []uint16{dns.TypeCNAME, dns.TypeA}, false},
+		{"CNAME outside zone", "ext.lxd.example.net.", dns.TypeA, dns.RcodeSuccess, []uint16{dns.TypeCNAME}, false},
+		{"Zone SOA", "lxd.example.net.", dns.TypeSOA, dns.RcodeSuccess, []uint16{dns.TypeSOA}, false},
+		{"Zone NS", "lxd.example.net.", dns.TypeNS, dns.RcodeSuccess, []uint16{dns.TypeNS}, false},
+		{"No data for type", "c1.lxd.example.net.", dns.TypeTXT, dns.RcodeSuccess, nil, true},
+		{"Empty non-terminal", "_tcp.lxd.example.net.", dns.TypeA, dns.RcodeSuccess, nil, true},
+		{"Missing name", "missing.lxd.example.net.", dns.TypeA, dns.RcodeNameError, nil, true},
+	}
+
+	h := newQueryHandler(map[string]string{"dns.query.enabled": "true"}, testZoneContent)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newMockWriter("192.0.2.10:12345", nil)
+			r := new(dns.Msg)
+			r.SetQuestion(tt.qname, tt.qtype)
+
+			h.ServeDNS(w, r)
+
+			require.NotNil(t, w.written)
+			assert.Equal(t, tt.wantRcode, w.written.Rcode)
+			assert.True(t, w.written.Authoritative)
+
+			answerTypes := []uint16{}
+			for _, rr := range w.written.Answer {
+				answerTypes = append(answerTypes, rr.Header().Rrtype)
+			}
+
+			assert.ElementsMatch(t, tt.wantAnswer, answerTypes)
+
+			if tt.wantNs {
+				require.Len(t, w.written.Ns, 1)
+				assert.Equal(t, dns.TypeSOA, w.written.Ns[0].Header().Rrtype)
+			} else {
+				assert.Empty(t, w.written.Ns)
+			}
+		})
+	}
+}
+
+func TestServeDNS_QueryDisabled(t *testing.T) {
+	t.Parallel()
+
+	h := newQueryHandler(map[string]string{}, testZoneContent)
+
+	w := newMockWriter("192.0.2.10:12345", nil)
+	r := new(dns.Msg)
+	r.SetQuestion("c1.lxd.example.net.", dns.TypeA)
+
+	h.ServeDNS(w, r)
+
+	require.NotNil(t, w.written)
+	assert.Equal(t, dns.RcodeNotImplemented, w.written.Rcode)
+}
+
+func TestServeDNS_QueryNotInZone(t *testing.T) {
+	t.Parallel()
+
+	h := newQueryHandler(map[string]string{"dns.query.enabled": "true"}, testZoneContent)
+
+	w := newMockWriter("192.0.2.10:12345", nil)
+	r := new(dns.Msg)
+	r.SetQuestion("c1.example.org.", dns.TypeA)
+
+	h.ServeDNS(w, r)
+
+	// The server isn't authoritative for the name.
+	require.NotNil(t, w.written)
+	assert.Equal(t, dns.RcodeRefused, w.written.Rcode)
+}
+
+func TestServeDNS_QueryZoneCache(t *testing.T) {
+	t.Parallel()
+
+	zone := &Zone{
+		Info: api.NetworkZone{
+			Name:   "lxd.example.net",
+			Config: map[string]string{"dns.query.enabled": "true"},
+		},
+		Content: testZoneContent,
+	}
+
+	var renders, lists int
+	s := &Server{
+		zoneRetriever: func(name string, full bool) (*Zone, error) {
+			if name != zone.Info.Name {
+				return nil, assert.AnError
+			}
+
+			if full {
+				renders++
+			}
+
+			return zone, nil
+		},
+		zoneLister: func() ([]string, error) {
+			lists++
+			return []string{zone.Info.Name}, nil
+		},
+	}
+
+	h := &dnsHandler{server: s}
+
+	query := func(qname string) int {
+		w := newMockWriter("192.0.2.10:12345", nil)
+		r := new(dns.Msg)
+		r.SetQuestion(qname, dns.TypeA)
+
+		h.ServeDNS(w, r)
+		require.NotNil(t, w.written)
+
+		return w.written.Rcode
+	}
+
+	// Queries are answered from the cache.
+	for range 3 {
+		assert.Equal(t, dns.RcodeSuccess, query("c1.lxd.example.net."))
+		assert.Equal(t, dns.RcodeRefused, query("c1.example.org."))
+	}
+
+	assert.Equal(t, 1, renders)
+	assert.Equal(t, 1, lists)
+
+	// Changes are taken into account once the cache is invalidated.
+	zone = &Zone{Info: api.NetworkZone{Name: "lxd.example.net", Config: map[string]string{}}, Content: testZoneContent}
+	assert.Equal(t, dns.RcodeSuccess, query("c1.lxd.example.net."))
+
+	s.InvalidateZones()
+	assert.Equal(t, dns.RcodeNotImplemented, query("c1.lxd.example.net."))
+	assert.Equal(t, 2, lists)
+}
+
+func TestServeDNS_QuerySources(t *testing.T) {
+	t.Parallel()
+
+	h := newQueryHandler(map[string]string{
+		"dns.query.enabled": "true",
+		"dns.query.sources": "192.0.2.0/24, fd42::/64",
+	}, testZoneContent)
+
+	tests := []struct {
+		addr      string
+		wantRcode int
+	}{
+		{"192.0.2.10:12345", dns.RcodeSuccess},
+		{"[fd42::10]:12345", dns.RcodeSuccess},
+		{"198.51.100.10:12345", dns.RcodeRefused},
+		{"[fd43::10]:12345", dns.RcodeRefused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			w := newMockWriter(tt.addr, nil)
+			r := new(dns.Msg)
+			r.SetQuestion("c1.lxd.example.net.", dns.TypeA)
+
+			h.ServeDNS(w, r)
+
+			require.NotNil(t, w.written)
+			assert.Equal(t, tt.wantRcode, w.written.Rcode)
+		})
+	}
+}
+
+func TestServeDNS_QueryEDNS(t *testing.T) {
+	t.Parallel()
+
+	h := newQueryHandler(map[string]string{"dns.query.enabled": "true"}, testZoneContent)
+
+	t.Run("Response carries EDNS when requested", func(t *testing.T) {
+		w := newMockUDPWriter("192.0.2.10:12345")
+		r := new(dns.Msg)
+		r.SetQuestion("c1.lxd.example.net.", dns.TypeA)
+		r.SetEdns0(4096, false)
+
+		h.ServeDNS(w, r)
+
+		require.NotNil(t, w.written)
+		assert.Equal(t, dns.RcodeSuccess, w.written.Rcode)
+
+		opt := w.written.IsEdns0()
+		require.NotNil(t, opt)
+		assert.Equal(t, uint16(maxUDPSize), opt.UDPSize())
+	})
+
+	t.Run("Unsupported EDNS version", func(t *testing.T) {
+		w := newMockUDPWriter("192.0.2.10:12345")
+		r := new(dns.Msg)
+		r.SetQuestion("c1.lxd.example.net.", dns.TypeA)
+		r.SetEdns0(4096, false)
+		r.IsEdns0().SetVersion(1)
+
+		h.ServeDNS(w, r)
+
+		require.NotNil(t, w.written)
+		assert.Equal(t, dns.RcodeBadVers, w.written.Rcode)
+	})
+}
+
+func TestServeDNS_QueryTruncation(t *testing.T) {
+	t.Parallel()
+
+	// Build a zone with a record set larger than a UDP response.
+	var content strings.Builder
+	content.WriteString("lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1 120 60 86400 30\n")
+	for i := range 100 {
+		fmt.Fprintf(&content, "big.lxd.example.net. 300 IN TXT \"record-%03d-%s\"\n", i, strings.Repeat("x", 32))
+	}
+
+	h := newQueryHandler(map[string]string{"dns.query.enabled": "true"}, content.String())
+
+	t.Run("UDP response is truncated", func(t *testing.T) {
+		w := newMockUDPWriter("192.0.2.10:12345")
+		r := new(dns.Msg)
+		r.SetQuestion("big.lxd.example.net.", dns.TypeTXT)
+
+		h.ServeDNS(w, r)
+
+		require.NotNil(t, w.written)
+		assert.True(t, w.written.Truncated)
+		assert.LessOrEqual(t, w.written.Len(), dns.MinMsgSize)
+	})
+
+	t.Run("TCP response is complete", func(t *testing.T) {
+		w := newMockWriter("192.0.2.10:12345", nil)
+		r := new(dns.Msg)
+		r.SetQuestion("big.lxd.example.net.", dns.TypeTXT)
+
+		h.ServeDNS(w, r)
+
+		require.NotNil(t, w.written)
+		assert.False(t, w.written.Truncated)
+		assert.Len(t, w.written.Answer, 100)
+	})
+}
@@ -23,6 +23,10 @@ type Server struct {
 	// External dependencies.
 	db            *db.Cluster
 	zoneRetriever ZoneRetriever
+	zoneLister    func() ([]string, error)
+
+	// Cache of the zones used to answer queries.
+	zones zoneCache
 
 	// Internal state (to handle reconfiguration).
 	address string
@@ -34,6 +38,10 @@ type Server struct {
 func NewServer(db *db.Cluster, retriever ZoneRetriever) *Server {
 	// Setup new struct.
 	s := &Server{db: db, zoneRetriever: retriever}
+	if db != nil {
+		s.zoneLister = zoneLister(db)
+	}
+
 	return s
 }
 
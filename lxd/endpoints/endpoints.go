@@ -64,6 +64,9 @@ type Config struct {
 	// HTTP server handling requests for the LXD metrics API.
 	MetricsServer *http.Server
 
+	// HTTP server handling requests for the S3 API of storage buckets.
+	StorageBucketsServer *http.Server
+
 	// HTTP server handling requests from VMs via the vsock.
 	VsockServer *http.Server
 
@@ -172,13 +175,14 @@ func (e *Endpoints) up(config *Config) error {
 	defer e.mu.Unlock()
 
 	e.servers = map[kind]*http.Server{
-		cluster: config.RestServer,
-		devlxd:  config.DevLxdServer,
-		local:   config.RestServer,
-		metrics: config.MetricsServer,
-		network: config.RestServer,
-		pprof:   pprofCreateServer(),
-		vmvsock: config.VsockServer,
+		cluster:        config.RestServer,
+		devlxd:         config.DevLxdServer,
+		local:          config.RestServer,
+		metrics:        config.MetricsServer,
+		network:        config.RestServer,
+		pprof:          pprofCreateServer(),
+		vmvsock:        config.VsockServer,
+		storageBuckets: config.StorageBucketsServer,
 	}
 
 	e.cert = config.Cert
@@ -326,6 +330,22 @@ func (e *Endpoints) UpMetrics(listenAddress string) error {
 	return nil
 }
 
+// UpStorageBuckets brings up storage buckets listener on specified address.
+func (e *Endpoints) UpStorageBuckets(listenAddress string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var err error
+	e.listeners[storageBuckets], err = storageBucketsCreateListener(listenAddress, e.cert)
+	if err != nil {
+		return fmt.Errorf("Failed starting storage buckets listener: %w", err)
+	}
+
+	e.serve(storageBuckets)
+
+	return nil
+}
+
 // Down brings down all endpoints and stops serving HTTP requests.
 func (e *Endpoints) Down() error {
 	e.mu.Lock()
@@ -371,6 +391,13 @@ func (e *Endpoints) Down() error {
 		}
 	}
 
+	if e.listeners[storageBuckets] != nil {
+		err := e.closeListener(storageBuckets)
+		if err != nil {
+			return err
+		}
+	}
+
 	if e.listeners[vmvsock] != nil {
 		err := e.closeListener(vmvsock)
 		if err != nil {
@@ -468,15 +495,17 @@ const (
 	cluster
 	metrics
 	vmvsock
+	storageBuckets
 )
 
 // Human-readable descriptions of the various kinds of endpoints.
 var descriptions = map[kind]string{
-	local:   "REST API Unix socket",
-	devlxd:  "devlxd socket",
-	network: "REST API TCP socket",
-	pprof:   "pprof socket",
-	cluster: "cluster socket",
-	metrics: "metrics socket",
-	vmvsock: "VM socket",
+	local:          "REST API Unix socket",
+	devlxd:         "devlxd socket",
+	network:        "REST API TCP socket",
+	pprof:          "pprof socket",
+	cluster:        "cluster socket",
+	metrics:        "metrics socket",
+	vmvsock:        "VM socket",
+	storageBuckets: "storage buckets socket",
 }
@@ -132,6 +132,20 @@ func (d networkACLDeleter) Delete(ctx context.Context, clientType request.Client
 	return nil
 }
 
+type networkAddressSetDeleter struct{}
+
+// Delete deletes a network address set.
+func (d networkAddressSetDeleter) Delete(ctx context.Context, clientType request.ClientType, op *operations.Operation, s *state.State, ref entity.Reference) error {
+	name := ref.Name()
+
+	err := doNetworkAddressSetDelete(ctx, s, name, ref.ProjectName)
+	if err != nil {
+		return fmt.Errorf("Failed deleting network address set %q: %w", name, err)
+	}
+
+	return nil
+}
+
 type networkZoneDeleter struct{}
 
 // Delete deletes a network zone.
@@ -263,6 +277,8 @@ func getEntityDeleter(t entity.Type) (entityDeleter, error) {
 		return networkDeleter{}, nil
 	case entity.TypeNetworkACL:
 		return networkACLDeleter{}, nil
+	case entity.TypeNetworkAddressSet:
+		return networkAddressSetDeleter{}, nil
 	case entity.TypeNetworkZone:
 		return networkZoneDeleter{}, nil
 	case entity.TypeStorageVolume:
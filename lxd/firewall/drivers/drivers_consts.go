@@ -32,13 +32,26 @@ type ACLRule struct {
 	Action          string
 	Log             bool   // Whether or not to log matched packets.
 	LogName         string // Log label name (requires Log be true).
-	Source          string
-	Destination     string
+	Source          string // May reference address sets as "$<name>".
+	Destination     string // May reference address sets as "$<name>".
 	Protocol        string
 	SourcePort      string
 	DestinationPort string
 	ICMPType        string
 	ICMPCode        string
+	Identifier      string // Identifies the rule's counters (no counters are kept if empty).
+}
+
+// ACLRuleCounters represents the packet and byte counters of an ACL rule.
+type ACLRuleCounters struct {
+	Packets uint64
+	Bytes   uint64
+}
+
+// AddressSet represents a named set of addresses that ACL rules can reference.
+type AddressSet struct {
+	Name      string
+	Addresses []string // IP addresses and CIDR subnets.
 }
 
 // AddressForward represents a NAT address forward.
@@ -49,3 +62,18 @@ type AddressForward struct {
 	ListenPorts   []uint64
 	TargetPorts   []uint64
 }
+
+// LoadBalancerTarget represents a single target of a NAT load balancer.
+type LoadBalancerTarget struct {
+	Address net.IP
+	Port    uint64
+	Weight  uint64 // Relative weight of the target. Zero is handled as a weight of one.
+}
+
+// LoadBalancer represents a NAT load balancer for a single listen address, protocol and port.
+type LoadBalancer struct {
+	ListenAddress net.IP
+	Protocol      string
+	ListenPort    uint64
+	Targets       []LoadBalancerTarget
+}
@@ -92,11 +92,11 @@ func (d Nftables) Compat() (bool, error) {
 
 // nftGenericItem represents some common fields amongst the different nftables types.
 type nftGenericItem struct {
-	itemType string // Type of item (table, chain or rule). Populated by LXD.
+	itemType string // Type of item (table, chain, set or rule). Populated by LXD.
 	Family   string `json:"family"` // Family of item (ip, ip6, bridge etc).
 	Table    string `json:"table"`  // Table the item belongs to (for chains and rules).
 	Chain    string `json:"chain"`  // Chain the item belongs to (for rules).
-	Name     string `json:"name"`   // Name of item (for tables and chains).
+	Name     string `json:"name"`   // Name of item (for tables, chains and sets).
 }
 
 // nftParseRuleset parses the ruleset and returns the generic parts as a slice of items.
@@ -129,10 +129,14 @@ func (d Nftables) nftParseRuleset() ([]nftGenericItem, error) {
 	for _, item := range v.Nftables {
 		rule, foundRule := item["rule"]
 		chain, foundChain := item["chain"]
+		set, foundSet := item["set"]
 		table, foundTable := item["table"]
 		if foundRule {
 			rule.itemType = "rule"
 			items = append(items, rule)
+		} else if foundSet {
+			set.itemType = "set"
+			items = append(items, set)
 		} else if foundChain {
 			chain.itemType = "chain"
 			items = append(items, chain)
@@ -318,6 +322,7 @@ func (d Nftables) NetworkClear(networkName string, _ bool, _ []uint) error {
 		"fwd", "pstrt", "in", "out", // Chains used for network operation rules.
 		"aclin", "aclout", "aclfwd", "acl", // Chains used by ACL rules.
 		"fwdprert", "fwdout", "fwdpstrt", // Chains used by Address Forward rules.
+		"lbprert", "lbout", "lbpstrt", // Chains used by Load Balancer rules.
 		"egress", // Chains added for limits.priority option
 	}
 
@@ -328,6 +333,12 @@ func (d Nftables) NetworkClear(networkName string, _ bool, _ []uint) error {
 		return fmt.Errorf("Failed clearing nftables rules for network %q: %w", networkName, err)
 	}
 
+	// Remove sets used by ACL rules (now that no chain references them).
+	err = d.removeACLAddressSets(networkName, nil)
+	if err != nil {
+		return fmt.Errorf("Failed clearing nftables sets for network %q: %w", networkName, err)
+	}
+
 	return nil
 }
 
@@ -654,32 +665,44 @@ func (d Nftables) InstanceClearNetPrio(projectName string, instanceName string,
 // NetworkApplyACLRules applies ACL rules to the existing firewall chains.
 func (d Nftables) NetworkApplyACLRules(networkName string, rules []ACLRule) error {
 	nftRules := make([]string, 0)
-	for _, rule := range rules {
-		// First try generating rules with IPv4 or IP agnostic criteria.
-		nftRule, partial, err := d.aclRuleCriteriaToRules(networkName, 4, &rule)
-		if err != nil {
-			return err
-		}
-
-		if nftRule != "" {
-			nftRules = append(nftRules, nftRule)
-		}
+	var usedSets []string
+	for _, aclRule := range rules {
+		for _, rule := range aclRuleExpandAddressSets(aclRule) {
+			setNames := aclRuleAddressSetNames(&rule)
+			for _, setName := range setNames {
+				usedSets = append(usedSets, nftablesACLAddressSetName(networkName, setName, 4), nftablesACLAddressSetName(networkName, setName, 6))
+			}
 
-		if partial {
-			// If we couldn't fully generate the ruleset with only IPv4 or IP agnostic criteria, then
-			// fill in the remaining parts using IPv6 criteria.
-			nftRule, _, err = d.aclRuleCriteriaToRules(networkName, 6, &rule)
+			// First try generating rules with IPv4 or IP agnostic criteria.
+			nftRule, partial, err := d.aclRuleCriteriaToRules(networkName, 4, &rule)
 			if err != nil {
 				return err
 			}
 
-			if nftRule == "" {
-				return errors.New("Invalid empty rule generated")
+			if nftRule != "" {
+				nftRules = append(nftRules, nftRule)
 			}
 
-			nftRules = append(nftRules, nftRule)
-		} else if nftRule == "" {
-			return errors.New("Invalid empty rule generated")
+			if partial {
+				// If we couldn't fully generate the ruleset with only IPv4 or IP agnostic criteria, then
+				// fill in the remaining parts using IPv6 criteria.
+				nftRule, _, err = d.aclRuleCriteriaToRules(networkName, 6, &rule)
+				if err != nil {
+					return err
+				}
+
+				// Address sets can contain addresses of either IP family, so a rule combining an address
+				// set with subjects of a single IP family only applies to that family.
+				if nftRule == "" && len(setNames) == 0 {
+					return errors.New("Invalid empty rule generated")
+				}
+
+				if nftRule != "" {
+					nftRules = append(nftRules, nftRule)
+				}
+			} else if nftRule == "" && len(setNames) == 0 {
+				return errors.New("Invalid empty rule generated")
+			}
 		}
 	}
 
@@ -702,9 +725,236 @@ func (d Nftables) NetworkApplyACLRules(networkName string, rules []ACLRule) erro
 		return err
 	}
 
+	// Remove the sets of address sets no longer referenced by the rules.
+	err = d.removeACLAddressSets(networkName, usedSets)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NetworkACLRuleCounters returns the packet and byte counters of the ACL rules applied to a network, keyed by
+// rule identifier. Rules without an identifier are not included.
+func (d Nftables) NetworkACLRuleCounters(networkName string) (map[string]ACLRuleCounters, error) {
+	// Use -nn flags to avoid doing DNS lookups of IPs mentioned in any rules.
+	output, err := shared.RunCommand(context.TODO(), "nft", "--json", "-nn", "list", "chain", "inet", nftablesNamespace, "acl"+nftablesChainSeparator+networkName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing ACL rules of network %q: %w", networkName, err)
+	}
+
+	counters, err := nftParseRuleCounters([]byte(output))
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing ACL rule counters of network %q: %w", networkName, err)
+	}
+
+	return counters, nil
+}
+
+// nftParseRuleCounters parses the JSON output of an nft list command and sums the counters of the rules for each
+// rule comment. Rules without a comment are ignored.
+func nftParseRuleCounters(output []byte) (map[string]ACLRuleCounters, error) {
+	// This only extracts the rule comments and counters, see man libnftables-json for more info.
+	v := &struct {
+		Nftables []struct {
+			Rule *struct {
+				Comment string                       `json:"comment"`
+				Expr    []map[string]json.RawMessage `json:"expr"`
+			} `json:"rule"`
+		} `json:"nftables"`
+	}{}
+
+	err := json.Unmarshal(output, v)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]ACLRuleCounters)
+	for _, item := range v.Nftables {
+		if item.Rule == nil || item.Rule.Comment == "" {
+			continue
+		}
+
+		ruleCounters := counters[item.Rule.Comment]
+		for _, expr := range item.Rule.Expr {
+			counter, found := expr["counter"]
+			if !found {
+				continue
+			}
+
+			c := ACLRuleCounters{}
+			err = json.Unmarshal(counter, &struct {
+				Packets *uint64 `json:"packets"`
+				Bytes   *uint64 `json:"bytes"`
+			}{Packets: &c.Packets, Bytes: &c.Bytes})
+			if err != nil {
+				return nil, err
+			}
+
+			ruleCounters.Packets += c.Packets
+			ruleCounters.Bytes += c.Bytes
+		}
+
+		counters[item.Rule.Comment] = ruleCounters
+	}
+
+	return counters, nil
+}
+
+// NetworkApplyAddressSets creates or updates the nftables sets holding the addresses of the address sets used by
+// the ACL rules of a network. The sets must be applied before any ACL rules referencing them.
+func (d Nftables) NetworkApplyAddressSets(networkName string, sets []AddressSet) error {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	nftSets := make([]map[string]string, 0, len(sets)*2)
+	for _, set := range sets {
+		elements := map[uint][]string{}
+		for _, address := range set.Addresses {
+			ip := net.ParseIP(address)
+			if ip == nil {
+				ip, _, _ = net.ParseCIDR(address)
+			}
+
+			if ip == nil {
+				return fmt.Errorf("Invalid address %q in address set %q", address, set.Name)
+			}
+
+			if ip.To4() != nil {
+				elements[4] = append(elements[4], address)
+			} else {
+				elements[6] = append(elements[6], address)
+			}
+		}
+
+		nftSets = append(nftSets, map[string]string{
+			"name":     nftablesACLAddressSetName(networkName, set.Name, 4),
+			"type":     "ipv4_addr",
+			"elements": strings.Join(elements[4], ", "),
+		}, map[string]string{
+			"name":     nftablesACLAddressSetName(networkName, set.Name, 6),
+			"type":     "ipv6_addr",
+			"elements": strings.Join(elements[6], ", "),
+		})
+	}
+
+	tplFields := map[string]any{
+		"namespace": nftablesNamespace,
+		"family":    "inet",
+		"sets":      nftSets,
+	}
+
+	config := &strings.Builder{}
+	err := nftablesNetACLAddressSets.Execute(config, tplFields)
+	if err != nil {
+		return fmt.Errorf("Failed running %q template: %w", nftablesNetACLAddressSets.Name(), err)
+	}
+
+	err = shared.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("Failed applying address sets for network %q: %w", networkName, err)
+	}
+
 	return nil
 }
 
+// removeACLAddressSets removes the nftables sets of the address sets used by the ACL rules of a network, apart
+// from those in the keep list.
+func (d Nftables) removeACLAddressSets(networkName string, keep []string) error {
+	ruleset, err := d.nftParseRuleset()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range ruleset {
+		if item.itemType != "set" || item.Family != "inet" || item.Table != nftablesNamespace || slices.Contains(keep, item.Name) {
+			continue
+		}
+
+		// Address set names cannot contain the separator, so the remainder after the network's prefix
+		// must not contain it either (otherwise the set belongs to a network with a longer name).
+		isNetworkSet := false
+		for _, ipVersion := range []uint{4, 6} {
+			setName, found := strings.CutPrefix(item.Name, nftablesACLAddressSetName(networkName, "", ipVersion))
+			if found && setName != "" && !strings.Contains(setName, nftablesChainSeparator) {
+				isNetworkSet = true
+				break
+			}
+		}
+
+		if !isNetworkSet {
+			continue
+		}
+
+		_, err = shared.RunCommand(context.TODO(), "nft", "delete", "set", item.Family, nftablesNamespace, item.Name)
+		if err != nil {
+			return fmt.Errorf("Failed deleting nftables set %q (%s): %w", item.Name, item.Family, err)
+		}
+	}
+
+	return nil
+}
+
+// nftablesACLAddressSetName returns the name of the nftables set holding the addresses of the specified IP family
+// of an address set used by the ACL rules of a network.
+func nftablesACLAddressSetName(networkName string, setName string, ipVersion uint) string {
+	return fmt.Sprintf("aclset%d%s%s%s%s", ipVersion, nftablesChainSeparator, networkName, nftablesChainSeparator, setName)
+}
+
+// aclRuleAddressSetNames returns the names of the address sets referenced by the rule's source and destination.
+func aclRuleAddressSetNames(rule *ACLRule) []string {
+	var names []string
+	for _, subject := range append(shared.SplitNTrimSpace(rule.Source, ",", -1, true), shared.SplitNTrimSpace(rule.Destination, ",", -1, true)...) {
+		name, found := strings.CutPrefix(subject, "$")
+		if found && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// aclRuleExpandAddressSets splits a rule whose source or destination combines an address set with other subjects
+// into multiple rules, each with either only literal subjects or a single address set per field. This is needed
+// because nftables cannot match a named set and literal addresses in the same expression. The resulting rules
+// are equivalent to the original one as ACL rule actions are terminal.
+func aclRuleExpandAddressSets(rule ACLRule) []ACLRule {
+	splitSubjects := func(subjects string) []string {
+		if subjects == "" {
+			return []string{""}
+		}
+
+		var literals []string
+		var groups []string
+		for _, subject := range shared.SplitNTrimSpace(subjects, ",", -1, true) {
+			if strings.HasPrefix(subject, "$") {
+				groups = append(groups, subject)
+			} else {
+				literals = append(literals, subject)
+			}
+		}
+
+		if len(literals) > 0 {
+			groups = append([]string{strings.Join(literals, ",")}, groups...)
+		}
+
+		return groups
+	}
+
+	var rules []ACLRule
+	for _, source := range splitSubjects(rule.Source) {
+		for _, destination := range splitSubjects(rule.Destination) {
+			expandedRule := rule
+			expandedRule.Source = source
+			expandedRule.Destination = destination
+			rules = append(rules, expandedRule)
+		}
+	}
+
+	return rules
+}
+
 // aclRuleCriteriaToRules converts an ACL rule into 1 or more nftables rules.
 func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rule *ACLRule) (string, bool, error) {
 	var args []string
@@ -719,7 +969,7 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 	isPartialRule := false
 
 	if rule.Source != "" {
-		matchArgs, partial, err := d.aclRuleSubjectToACLMatch("saddr", ipVersion, shared.SplitNTrimSpace(rule.Source, ",", -1, false)...)
+		matchArgs, partial, err := d.aclRuleSubjectToACLMatchWithSets(networkName, "saddr", ipVersion, rule.Protocol, rule.Source)
 		if err != nil {
 			return "", false, err
 		}
@@ -736,7 +986,7 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 	}
 
 	if rule.Destination != "" {
-		matchArgs, partial, err := d.aclRuleSubjectToACLMatch("daddr", ipVersion, shared.SplitNTrimSpace(rule.Destination, ",", -1, false)...)
+		matchArgs, partial, err := d.aclRuleSubjectToACLMatchWithSets(networkName, "daddr", ipVersion, rule.Protocol, rule.Destination)
 		if err != nil {
 			return "", false, err
 		}
@@ -801,6 +1051,11 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 		}
 	}
 
+	// Count matched packets if the rule's counters are requested.
+	if rule.Identifier != "" {
+		args = append(args, "counter")
+	}
+
 	// Handle logging.
 	if rule.Log {
 		args = append(args, "log")
@@ -819,9 +1074,37 @@ func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rul
 
 	args = append(args, action)
 
+	if rule.Identifier != "" {
+		args = append(args, "comment", `"`+rule.Identifier+`"`)
+	}
+
 	return strings.Join(args, " "), isPartialRule, nil
 }
 
+// aclRuleSubjectToACLMatchWithSets converts direction (source/destination) and subjects into nftables args.
+// The subjects must either be a single address set reference or only contain literal subjects.
+// Address sets can contain addresses of either IP family, so their match is partial unless the protocol is
+// specific to one IP family.
+func (d Nftables) aclRuleSubjectToACLMatchWithSets(networkName string, direction string, ipVersion uint, protocol string, subjects string) ([]string, bool, error) {
+	setName, isSet := strings.CutPrefix(subjects, "$")
+	if !isSet {
+		return d.aclRuleSubjectToACLMatch(direction, ipVersion, shared.SplitNTrimSpace(subjects, ",", -1, false)...)
+	}
+
+	if (protocol == "icmp4" && ipVersion != 4) || (protocol == "icmp6" && ipVersion != 6) {
+		return nil, ipVersion == 4, nil // Rule is not appropriate for ipVersion.
+	}
+
+	ipFamily := "ip"
+	if ipVersion == 6 {
+		ipFamily = "ip6"
+	}
+
+	partial := ipVersion == 4 && protocol != "icmp4"
+
+	return []string{ipFamily, direction, "@" + nftablesACLAddressSetName(networkName, setName, ipVersion)}, partial, nil
+}
+
 // aclRuleSubjectToACLMatch converts direction (source/destination) and subject criteria list into xtables args.
 // Returns nil if none of the subjects are appropriate for the ipVersion.
 func (d Nftables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, subjectCriteria ...string) ([]string, bool, error) {
@@ -1038,3 +1321,116 @@ func (d Nftables) NetworkApplyForwards(networkName string, rules []AddressForwar
 
 	return nil
 }
+
+// NetworkApplyLoadBalancers apply network load balancer rules to firewall.
+func (d Nftables) NetworkApplyLoadBalancers(networkName string, rules []LoadBalancer) error {
+	var dnatRules []map[string]any
+	var snatRules []map[string]any
+
+	snatRulesSeen := make(map[string]struct{})
+
+	for ruleIndex, rule := range rules {
+		// Validate the rule.
+		if rule.ListenAddress == nil {
+			return fmt.Errorf("Invalid rule %d, listen address is required", ruleIndex)
+		}
+
+		if rule.Protocol == "" || rule.ListenPort == 0 {
+			return fmt.Errorf("Invalid rule %d, protocol and listen port are required", ruleIndex)
+		}
+
+		if len(rule.Targets) == 0 {
+			return fmt.Errorf("Invalid rule %d, at least one target is required", ruleIndex)
+		}
+
+		ipFamily := "ip"
+		if rule.ListenAddress.To4() == nil {
+			ipFamily = "ip6"
+		}
+
+		targetDests := make([]string, 0, len(rule.Targets))
+		for _, target := range rule.Targets {
+			if target.Address == nil || target.Port == 0 {
+				return fmt.Errorf("Invalid rule %d, target address and port are required", ruleIndex)
+			}
+
+			targetAddressStr := target.Address.String()
+			targetPortStr := strconv.FormatUint(target.Port, 10)
+
+			// Apply a masquerade rule for each target so that instances can reach themselves through the load balancer.
+			snatKey := rule.Protocol + "/" + net.JoinHostPort(targetAddressStr, targetPortStr)
+			_, found := snatRulesSeen[snatKey]
+			if !found {
+				snatRulesSeen[snatKey] = struct{}{}
+				snatRules = append(snatRules, map[string]any{
+					"ipFamily":   ipFamily,
+					"protocol":   rule.Protocol,
+					"targetHost": targetAddressStr,
+					"targetPort": targetPortStr,
+				})
+			}
+
+			targetDests = append(targetDests, targetAddressStr+" . "+targetPortStr)
+		}
+
+		dnatRule := map[string]any{
+			"ipFamily":      ipFamily,
+			"protocol":      rule.Protocol,
+			"listenAddress": rule.ListenAddress.String(),
+			"listenPort":    strconv.FormatUint(rule.ListenPort, 10),
+		}
+
+		if len(rule.Targets) == 1 {
+			targetDest := net.JoinHostPort(rule.Targets[0].Address.String(), strconv.FormatUint(rule.Targets[0].Port, 10))
+			dnatRule["targetDest"] = targetDest
+		} else {
+			// Pick a target at random for each new connection, each target being selected by a range of
+			// numbers whose size is the weight of the target.
+			weightRanges, modulus := loadBalancerWeightRanges(rule.Targets)
+			mapEntries := make([]string, 0, len(rule.Targets))
+			for i, weightRange := range weightRanges {
+				key := strconv.FormatUint(weightRange[0], 10)
+				if weightRange[1] != weightRange[0] {
+					key = fmt.Sprint(weightRange[0], "-", weightRange[1])
+				}
+
+				mapEntries = append(mapEntries, key+" : "+targetDests[i])
+			}
+
+			dnatRule["weighted"] = true
+			dnatRule["targetDest"] = fmt.Sprintf("numgen random mod %d map { %s }", modulus, strings.Join(mapEntries, ", "))
+		}
+
+		dnatRules = append(dnatRules, dnatRule)
+	}
+
+	tplFields := map[string]any{
+		"namespace":      nftablesNamespace,
+		"chainSeparator": nftablesChainSeparator,
+		"family":         "inet",
+		"networkName":    networkName,
+		"dnatRules":      dnatRules,
+		"snatRules":      snatRules,
+	}
+
+	// Apply rules or remove chains if no rules generated.
+	if len(dnatRules) > 0 {
+		config := &strings.Builder{}
+		err := nftablesNetLoadBalancers.Execute(config, tplFields)
+		if err != nil {
+			return fmt.Errorf("Failed running %q template: %w", nftablesNetLoadBalancers.Name(), err)
+		}
+
+		err = shared.RunCommandWithFds(context.TODO(), strings.NewReader(config.String()), nil, "nft", "-f", "-")
+		if err != nil {
+			return err
+		}
+	} else {
+		err := d.removeChains([]string{"inet"}, networkName, "lbprert", "lbout", "lbpstrt")
+		if err != nil {
+			return fmt.Errorf("Failed clearing nftables load balancer rules for network %q: %w", networkName, err)
+		}
+	}
+
+	return nil
+}
@@ -121,6 +121,39 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+var nftablesNetLoadBalancers = template.Must(template.New("nftablesNetLoadBalancers").Parse(`
+add table {{.family}} {{.namespace}}
+add chain {{.family}} {{.namespace}} lbprert{{.chainSeparator}}{{.networkName}} {type nat hook prerouting priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} lbout{{.chainSeparator}}{{.networkName}} {type nat hook output priority -100; policy accept;}
+add chain {{.family}} {{.namespace}} lbpstrt{{.chainSeparator}}{{.networkName}} {type nat hook postrouting priority 100; policy accept;}
+flush chain {{.family}} {{.namespace}} lbprert{{.chainSeparator}}{{.networkName}}
+flush chain {{.family}} {{.namespace}} lbout{{.chainSeparator}}{{.networkName}}
+flush chain {{.family}} {{.namespace}} lbpstrt{{.chainSeparator}}{{.networkName}}
+
+table {{.family}} {{.namespace}} {
+	chain lbprert{{.chainSeparator}}{{.networkName}} {
+		type nat hook prerouting priority -100; policy accept;
+		{{- range .dnatRules}}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} {{if .weighted}}addr . port {{end}}to {{.targetDest}}
+		{{- end}}
+	}
+
+	chain lbout{{.chainSeparator}}{{.networkName}} {
+		type nat hook output priority -100; policy accept;
+		{{- range .dnatRules}}
+		{{.ipFamily}} daddr {{.listenAddress}} {{.protocol}} dport {{.listenPort}} dnat {{.ipFamily}} {{if .weighted}}addr . port {{end}}to {{.targetDest}}
+		{{- end}}
+	}
+
+	chain lbpstrt{{.chainSeparator}}{{.networkName}} {
+		type nat hook postrouting priority 100; policy accept;
+		{{- range .snatRules}}
+		{{.ipFamily}} saddr {{.targetHost}} {{.ipFamily}} daddr {{.targetHost}} {{.protocol}} dport {{.targetPort}} masquerade
+		{{- end}}
+	}
+}
+`))
+
 var nftablesNetACLSetup = template.Must(template.New("nftablesNetACLSetup").Parse(`
 add table {{.family}} {{.namespace}}
 add chain {{.family}} {{.namespace}} acl{{.chainSeparator}}{{.networkName}}
@@ -186,6 +219,26 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+// nftablesNetACLAddressSets defines the sets holding the addresses of the address sets used by network ACL rules.
+// Existing sets are flushed before adding the new elements so that the update is applied atomically.
+var nftablesNetACLAddressSets = template.Must(template.New("nftablesNetACLAddressSets").Parse(`
+table {{.family}} {{.namespace}} {
+	{{- range .sets}}
+	set {{.name}} {
+		type {{.type}}
+		flags interval
+		auto-merge
+	}
+	{{- end}}
+}
+{{range .sets}}
+flush set {{$.family}} {{$.namespace}} {{.name}}
+{{- if .elements}}
+add element {{$.family}} {{$.namespace}} {{.name}} { {{.elements}} }
+{{- end}}
+{{- end}}
+`))
+
 // nftablesInstanceBridgeFilter defines the rules needed for MAC, IPv4 and IPv6 bridge security filtering.
 // To prevent instances from using IPs that are different from their assigned IPs we use ARP and NDP filtering
 // to prevent neighbour advertisements that are not allowed. However in order for DHCPv4 & DHCPv6 to work back to
@@ -0,0 +1,125 @@
+package drivers
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func Test_aclRuleExpandAddressSets(t *testing.T) {
+	rule := ACLRule{
+		Direction:   "egress",
+		Action:      "allow",
+		Source:      "192.0.2.1,$web,198.51.100.0/24,$db",
+		Destination: "$dns",
+	}
+
+	rules := aclRuleExpandAddressSets(rule)
+
+	var subjects []string
+	for _, r := range rules {
+		subjects = append(subjects, r.Source+" -> "+r.Destination)
+	}
+
+	expected := []string{
+		"192.0.2.1,198.51.100.0/24 -> $dns",
+		"$web -> $dns",
+		"$db -> $dns",
+	}
+
+	if !slices.Equal(subjects, expected) {
+		t.Errorf("Expected %v, got %v", expected, subjects)
+	}
+
+	// Rules without address sets are left unchanged.
+	rule = ACLRule{Direction: "ingress", Action: "drop", Source: "192.0.2.1"}
+	rules = aclRuleExpandAddressSets(rule)
+	if len(rules) != 1 || rules[0] != rule {
+		t.Errorf("Expected rule to be unchanged, got %v", rules)
+	}
+}
+
+func Test_aclRuleCriteriaToRules_AddressSets(t *testing.T) {
+	d := Nftables{}
+
+	tests := []struct {
+		name     string
+		rule     ACLRule
+		expected []string
+	}{
+		{
+			name:     "Address set source",
+			rule:     ACLRule{Direction: "ingress", Action: "allow", Source: "$web"},
+			expected: []string{"oifname lxdbr0 ip saddr @aclset4.lxdbr0.web accept", "oifname lxdbr0 ip6 saddr @aclset6.lxdbr0.web accept"},
+		},
+		{
+			name:     "Address set source with IPv4 destination",
+			rule:     ACLRule{Direction: "egress", Action: "drop", Source: "$web", Destination: "192.0.2.0/24"},
+			expected: []string{"iifname lxdbr0 ip saddr @aclset4.lxdbr0.web ip daddr {192.0.2.0/24} drop"},
+		},
+		{
+			name:     "Address set destination with ICMPv6",
+			rule:     ACLRule{Direction: "egress", Action: "reject", Destination: "$web", Protocol: "icmp6"},
+			expected: []string{"iifname lxdbr0 ip6 daddr @aclset6.lxdbr0.web ip6 nexthdr icmpv6 reject"},
+		},
+		{
+			name:     "Address set destination with ICMPv4",
+			rule:     ACLRule{Direction: "egress", Action: "reject", Destination: "$web", Protocol: "icmp4"},
+			expected: []string{"iifname lxdbr0 ip daddr @aclset4.lxdbr0.web ip protocol icmp reject"},
+		},
+		{
+			name:     "Address set source with counters",
+			rule:     ACLRule{Direction: "ingress", Action: "allow", Source: "$web", Log: true, LogName: "web", Identifier: "acl1-ingress-0"},
+			expected: []string{`oifname lxdbr0 ip saddr @aclset4.lxdbr0.web counter log prefix "web " accept comment "acl1-ingress-0"`, `oifname lxdbr0 ip6 saddr @aclset6.lxdbr0.web counter log prefix "web " accept comment "acl1-ingress-0"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, ipVersion := range []uint{4, 6} {
+				nftRule, partial, err := d.aclRuleCriteriaToRules("lxdbr0", ipVersion, &tt.rule)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if nftRule != "" {
+					result = append(result, nftRule)
+				}
+
+				if !partial {
+					break
+				}
+			}
+
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_nftParseRuleCounters(t *testing.T) {
+	output := `{"nftables": [
+		{"metainfo": {"version": "1.0.9", "release_name": "Old Doc Yak #3", "json_schema_version": 1}},
+		{"chain": {"family": "inet", "table": "lxd", "name": "acl.lxdbr0", "handle": 10}},
+		{"rule": {"family": "inet", "table": "lxd", "chain": "acl.lxdbr0", "handle": 11, "expr": [{"match": {"op": "in", "left": {"ct": {"key": "state"}}, "right": ["established", "related"]}}, {"accept": null}]}},
+		{"rule": {"family": "inet", "table": "lxd", "chain": "acl.lxdbr0", "handle": 12, "comment": "acl1-ingress-0", "expr": [{"match": {"op": "==", "left": {"meta": {"key": "oifname"}}, "right": "lxdbr0"}}, {"counter": {"packets": 3, "bytes": 180}}, {"accept": null}]}},
+		{"rule": {"family": "inet", "table": "lxd", "chain": "acl.lxdbr0", "handle": 13, "comment": "acl1-ingress-0", "expr": [{"match": {"op": "==", "left": {"meta": {"key": "oifname"}}, "right": "lxdbr0"}}, {"counter": {"packets": 2, "bytes": 200}}, {"accept": null}]}},
+		{"rule": {"family": "inet", "table": "lxd", "chain": "acl.lxdbr0", "handle": 14, "comment": "acl1-egress-1", "expr": [{"match": {"op": "==", "left": {"meta": {"key": "iifname"}}, "right": "lxdbr0"}}, {"counter": {"packets": 0, "bytes": 0}}, {"drop": null}]}}
+	]}`
+
+	counters, err := nftParseRuleCounters([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]ACLRuleCounters{
+		"acl1-ingress-0": {Packets: 5, Bytes: 380},
+		"acl1-egress-1":  {Packets: 0, Bytes: 0},
+	}
+
+	if !maps.Equal(counters, expected) {
+		t.Errorf("Expected %v, got %v", expected, counters)
+	}
+}
@@ -113,6 +113,49 @@ func getOptimisedDNATRanges(forward *AddressForward) map[[2]uint64][2]uint64 {
 	return snatRules
 }
 
+// loadBalancerTargetWeight returns the effective weight of a load balancer target.
+func loadBalancerTargetWeight(target LoadBalancerTarget) uint64 {
+	if target.Weight == 0 {
+		return 1
+	}
+
+	return target.Weight
+}
+
+// loadBalancerWeightRanges returns the range of random numbers ([first, last]) selecting each of the targets,
+// along with the modulus to use when generating the random number.
+func loadBalancerWeightRanges(targets []LoadBalancerTarget) ([][2]uint64, uint64) {
+	ranges := make([][2]uint64, 0, len(targets))
+	modulus := uint64(0)
+
+	for _, target := range targets {
+		weight := loadBalancerTargetWeight(target)
+		ranges = append(ranges, [2]uint64{modulus, modulus + weight - 1})
+		modulus += weight
+	}
+
+	return ranges, modulus
+}
+
+// loadBalancerProbabilities returns the probability with which each of the targets must be selected when the
+// targets are evaluated in order and each target is only evaluated if none of the previous ones was selected.
+// The last target is always selected when reached and so has a probability of one.
+func loadBalancerProbabilities(targets []LoadBalancerTarget) []float64 {
+	remainingWeight := uint64(0)
+	for _, target := range targets {
+		remainingWeight += loadBalancerTargetWeight(target)
+	}
+
+	probabilities := make([]float64, 0, len(targets))
+	for _, target := range targets {
+		weight := loadBalancerTargetWeight(target)
+		probabilities = append(probabilities, float64(weight)/float64(remainingWeight))
+		remainingWeight -= weight
+	}
+
+	return probabilities
+}
+
 // subnetMask returns the subnet mask of the given network as a string. Both IPv4 and IPv6 are handled.
 func subnetMask(ipNet *net.IPNet) string {
 	if ipNet.IP.To4() != nil {
@@ -145,3 +145,65 @@ func Test_getOptimisedSNATRanges(t *testing.T) {
 		assert.Equal(t, tt.expected, actual)
 	}
 }
+
+func Test_loadBalancerWeightRanges(t *testing.T) {
+	tests := []struct {
+		name            string
+		targets         []LoadBalancerTarget
+		expectedRanges  [][2]uint64
+		expectedModulus uint64
+	}{
+		{
+			name:            "Single target",
+			targets:         []LoadBalancerTarget{{}},
+			expectedRanges:  [][2]uint64{{0, 0}},
+			expectedModulus: 1,
+		},
+		{
+			name:            "Default weights",
+			targets:         []LoadBalancerTarget{{}, {}, {}},
+			expectedRanges:  [][2]uint64{{0, 0}, {1, 1}, {2, 2}},
+			expectedModulus: 3,
+		},
+		{
+			name:            "Mixed weights",
+			targets:         []LoadBalancerTarget{{Weight: 3}, {}, {Weight: 2}},
+			expectedRanges:  [][2]uint64{{0, 2}, {3, 3}, {4, 5}},
+			expectedModulus: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		ranges, modulus := loadBalancerWeightRanges(tt.targets)
+		assert.Equal(t, tt.expectedRanges, ranges, tt.name)
+		assert.Equal(t, tt.expectedModulus, modulus, tt.name)
+	}
+}
+
+func Test_loadBalancerProbabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		targets  []LoadBalancerTarget
+		expected []float64
+	}{
+		{
+			name:     "Single target",
+			targets:  []LoadBalancerTarget{{}},
+			expected: []float64{1},
+		},
+		{
+			name:     "Default weights",
+			targets:  []LoadBalancerTarget{{}, {}, {}, {}},
+			expected: []float64{0.25, 1.0 / 3, 0.5, 1},
+		},
+		{
+			name:     "Mixed weights",
+			targets:  []LoadBalancerTarget{{Weight: 2}, {Weight: 1}, {Weight: 1}},
+			expected: []float64{0.5, 0.5, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.InDeltaSlice(t, tt.expected, loadBalancerProbabilities(tt.targets), 1e-9, tt.name)
+	}
+}
@@ -160,6 +160,11 @@ func (d Xtables) networkForwardIPTablesComment(networkName string) string {
 	return "LXD network-forward " + networkName
 }
 
+// networkLoadBalancerIPTablesComment returns the iptables comment that is added to each network load balancer related rule.
+func (d Xtables) networkLoadBalancerIPTablesComment(networkName string) string {
+	return "LXD network-load-balancer " + networkName
+}
+
 // networkSetupNICFilteringChain creates the NIC filtering chain if it doesn't exist, and adds the jump rules to
 // the INPUT and FORWARD filter chains. Must be called after networkSetupForwardingPolicy so that the rules are
 // prepended before the default fowarding policy rules.
@@ -589,6 +594,17 @@ func (d Xtables) NetworkApplyACLRules(networkName string, rules []ACLRule) error
 	return nil
 }
 
+// NetworkACLRuleCounters is not supported by xtables.
+func (d Xtables) NetworkACLRuleCounters(networkName string) (map[string]ACLRuleCounters, error) {
+	return nil, errors.New("ACL rule counters are not supported by the xtables firewall driver")
+}
+
+// NetworkApplyAddressSets is not supported by xtables.
+// Rules using address sets must have the addresses of the sets substituted in before being applied.
+func (d Xtables) NetworkApplyAddressSets(networkName string, sets []AddressSet) error {
+	return errors.New("Address sets are not supported by the xtables firewall driver")
+}
+
 // aclRuleCriteriaToArgs converts an ACL rule into an set of arguments for an xtables rule.
 // Returns the arguments to use for the action command and separately the arguments for logging if enabled.
 // Returns nil arguments if the rule is not appropriate for the ipVersion.
@@ -765,10 +781,11 @@ func (d Xtables) NetworkClear(networkName string, remove bool, ipVersions []uint
 	comments := []string{
 		d.networkIPTablesComment(networkName),
 		d.networkForwardIPTablesComment(networkName),
+		d.networkLoadBalancerIPTablesComment(networkName),
 	}
 
 	for _, ipVersion := range ipVersions {
-		// Clear any rules associated to the network and network address forwards.
+		// Clear any rules associated to the network, network address forwards and network load balancers.
 		err := d.iptablesClear(ipVersion, comments, "filter", "mangle", "nat")
 		if err != nil {
 			return err
@@ -1667,3 +1684,115 @@ func (d Xtables) NetworkApplyForwards(networkName string, rules []AddressForward
 	reverter.Success()
 	return nil
 }
+
+// NetworkApplyLoadBalancers apply network load balancer rules to firewall.
+func (d Xtables) NetworkApplyLoadBalancers(networkName string, rules []LoadBalancer) error {
+	// Validate all rules first.
+	for i, rule := range rules {
+		if rule.ListenAddress == nil {
+			return fmt.Errorf("Invalid rule %d, listen address is required", i)
+		}
+
+		if rule.Protocol == "" || rule.ListenPort == 0 {
+			return fmt.Errorf("Invalid rule %d, protocol and listen port are required", i)
+		}
+
+		if len(rule.Targets) == 0 {
+			return fmt.Errorf("Invalid rule %d, at least one target is required", i)
+		}
+
+		for _, target := range rule.Targets {
+			if target.Address == nil || target.Port == 0 {
+				return fmt.Errorf("Invalid rule %d, target address and port are required", i)
+			}
+		}
+	}
+
+	comment := d.networkLoadBalancerIPTablesComment(networkName)
+
+	clearNetworkLoadBalancers := func() error {
+		for _, ipVersion := range []uint{4, 6} {
+			err := d.iptablesClear(ipVersion, []string{comment}, "nat")
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Clear any load balancer rules associated to the network.
+	err := clearNetworkLoadBalancers()
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Clear all network load balancers if we fail, otherwise the load balancers are only partially applied.
+	reverter.Add(func() {
+		err := clearNetworkLoadBalancers()
+		if err != nil {
+			logger.Error("Failed clearing firewall rules after failing to apply network load balancers", logger.Ctx{"network_name": networkName, "err": err})
+		}
+	})
+
+	masqueradeRulesSeen := make(map[string]struct{})
+
+	for _, rule := range rules {
+		ipVersion := uint(4)
+		if rule.ListenAddress.To4() == nil {
+			ipVersion = 6
+		}
+
+		listenAddressStr := rule.ListenAddress.String()
+		listenPortStr := strconv.FormatUint(rule.ListenPort, 10)
+		probabilities := loadBalancerProbabilities(rule.Targets)
+
+		// Rules are prepended so add them in reverse order. This way each target is only considered if none of the
+		// previous targets was selected, with the last target catching all the remaining connections.
+		for i := len(rule.Targets) - 1; i >= 0; i-- {
+			target := rule.Targets[i]
+			targetAddressStr := target.Address.String()
+			targetPortStr := strconv.FormatUint(target.Port, 10)
+			targetDest := net.JoinHostPort(targetAddressStr, targetPortStr)
+
+			// Apply MASQUERADE rule for each target.
+			// instance <-> instance.
+			// Requires instance's bridge port has hairpin mode enabled when br_netfilter is loaded.
+			masqueradeKey := rule.Protocol + "/" + targetDest
+			_, found := masqueradeRulesSeen[masqueradeKey]
+			if !found {
+				masqueradeRulesSeen[masqueradeKey] = struct{}{}
+
+				err := d.iptablesPrepend(ipVersion, comment, "nat", "POSTROUTING", "-p", rule.Protocol, "--source", targetAddressStr, "--destination", targetAddressStr, "--dport", targetPortStr, "-j", "MASQUERADE")
+				if err != nil {
+					return err
+				}
+			}
+
+			args := []string{"-p", rule.Protocol, "--destination", listenAddressStr, "--dport", listenPortStr}
+			if i < len(rule.Targets)-1 {
+				args = append(args, "-m", "statistic", "--mode", "random", "--probability", strconv.FormatFloat(probabilities[i], 'f', 10, 64))
+			}
+
+			args = append(args, "-j", "DNAT", "--to-destination", targetDest)
+
+			// outbound <-> instance.
+			err := d.iptablesPrepend(ipVersion, comment, "nat", "PREROUTING", args...)
+			if err != nil {
+				return err
+			}
+
+			// host <-> instance.
+			err = d.iptablesPrepend(ipVersion, comment, "nat", "OUTPUT", args...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
@@ -1969,6 +1969,7 @@ func (d *lxc) startCommon(ctx context.Context, progressReporter ioprogress.Progr
 	}
 
 	// Start devices in order.
+	volumeLimits := false
 	for i := range startDevices {
 		dev := startDevices[i] // Local var for revert.
 
@@ -2086,6 +2087,29 @@ func (d *lxc) startCommon(ctx context.Context, progressReporter ioprogress.Progr
 		if len(runConf.PostHooks) > 0 {
 			postStartHooks = append(postStartHooks, runConf.PostHooks...)
 		}
+
+		if runConf.VolumeLimits {
+			volumeLimits = true
+		}
+	}
+
+	// Start the container in the cgroup sharing the I/O limits of the storage volumes it uses.
+	if volumeLimits {
+		err = cgroup.VolumeLimitsEnsure()
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		cname := project.Instance(d.Project().Name, d.Name())
+		err = lxcSetConfigItem(cc, "lxc.cgroup.dir.container", cgroup.VolumeLimitsCGroup+"/lxc.payload."+cname)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		err = lxcSetConfigItem(cc, "lxc.cgroup.dir.monitor", "lxc.monitor."+cname)
+		if err != nil {
+			return nil, "", nil, err
+		}
 	}
 
 	// Load the LXC raw config.
@@ -4728,10 +4752,23 @@ func (d *lxc) MigrateSend(ctx context.Context, args instance.MigrateSendArgs, pr
 		return err
 	}
 
+	volType, err := storagePools.InstanceTypeToVolumeType(d.Type())
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
 	// The refresh argument passed to MigrationTypes() is always set to false here.
 	// The migration source/sender doesn't need to care whether or not it's doing a refresh as the migration
 	// sink/receiver will know this, and adjust the migration types accordingly.
-	poolMigrationTypes := pool.MigrationTypes(storagePools.InstanceContentType(d), false, args.Snapshots)
+	// Encrypted instance volumes are only offered non-optimized migration types.
+	poolMigrationTypes, err := storagePools.SourceMigrationTypes(pool, d.project.Name, d.name, volType, storagePools.InstanceContentType(d), args.Snapshots)
+	if err != nil {
+		err := fmt.Errorf("Failed getting source migration types: %w", err)
+		op.Done(err)
+		return err
+	}
+
 	if len(poolMigrationTypes) == 0 {
 		err := errors.New("No source migration types available")
 		op.Done(err)
@@ -4374,6 +4374,7 @@ func (d *qemu) addDriveConfig(busAllocate busAllocator, bootIndexes map[string]i
 	}
 
 	var isBlockDev bool
+	var diskChain []storageDrivers.DiskImage
 
 	// Detect device caches and I/O modes.
 	if isRBDImage {
@@ -4400,6 +4401,14 @@ func (d *qemu) addDriveConfig(busAllocate busAllocator, bootIndexes map[string]i
 
 		isBlockDev = shared.IsBlockdev(srcDevPathInfo.Mode())
 
+		// Root disks stored as qcow2 files need a qcow2 format node and may have a backing chain.
+		if !isBlockDev && driveConf.TargetPath == "/" && storageDrivers.DiskImageFormat(srcDevPath) == storageDrivers.DiskImageFormatQcow2 {
+			diskChain, err = storageDrivers.DiskImageBackingChain(srcDevPath)
+			if err != nil {
+				return nil, fmt.Errorf("Failed getting backing chain of disk device %q: %w", driveConf.DevName, err)
+			}
+		}
+
 		// Handle I/O mode configuration.
 		if !isBlockDev {
 			// Disk dev path is a file, check what the backing filesystem is.
@@ -4675,6 +4684,13 @@ func (d *qemu) addDriveConfig(busAllocate busAllocator, bootIndexes map[string]i
 			})
 
 			blockDev["filename"] = fmt.Sprintf("/dev/fdset/%d", info.ID)
+
+			if len(diskChain) > 0 {
+				blockDev, err = d.diskImageBlockDev(m, reverter, nodeName, blockDev, diskChain, directCache)
+				if err != nil {
+					return fmt.Errorf("Failed setting up backing chain for disk device %q: %w", driveConf.DevName, err)
+				}
+			}
 		}
 
 		err := m.AddBlockDevice(blockDev, qemuDev)
@@ -4688,7 +4704,7 @@ func (d *qemu) addDriveConfig(busAllocate busAllocator, bootIndexes map[string]i
 				return errors.New("Failed getting QEMU device id")
 			}
 
-			err = m.SetBlockThrottle(qemuDevID, int(driveConf.Limits.ReadBytes), int(driveConf.Limits.WriteBytes), int(driveConf.Limits.ReadIOps), int(driveConf.Limits.WriteIOps))
+			err = m.SetBlockThrottle(qemuDevID, driveConf.Limits.Group, int(driveConf.Limits.ReadBytes), int(driveConf.Limits.WriteBytes), int(driveConf.Limits.ReadIOps), int(driveConf.Limits.WriteIOps))
 			if err != nil {
 				return fmt.Errorf("Failed applying limits for disk device %q: %w", driveConf.DevName, err)
 			}
@@ -4701,6 +4717,67 @@ func (d *qemu) addDriveConfig(busAllocate busAllocator, bootIndexes map[string]i
 	return monHook, nil
 }
 
+// diskImageBlockDev wraps the file node of a qcow2 disk image into a qcow2 format node that takes over the node
+// name of the file node. The backing chain of the disk image is added explicitly so that QEMU never opens files
+// by itself. The backing files are opened read-only and passed to QEMU as file descriptors.
+func (d *qemu) diskImageBlockDev(m *qmp.Monitor, reverter *revert.Reverter, nodeName string, fileNode map[string]any, chain []storageDrivers.DiskImage, directCache bool) (map[string]any, error) {
+	permissions := unix.O_RDONLY
+	if directCache {
+		permissions |= unix.O_DIRECT
+	}
+
+	// Build the backing chain from the bottom up. A nil backing disables opening the backing file recorded in
+	// the qcow2 header.
+	var backing map[string]any
+	for i := len(chain) - 1; i > 0; i-- {
+		f, err := os.OpenFile(chain[i].Path, permissions, 0)
+		if err != nil {
+			return nil, fmt.Errorf("Failed opening backing file %q: %w", chain[i].Path, err)
+		}
+
+		defer func() { _ = f.Close() }()
+
+		fdSetName := nodeName + "_" + strconv.Itoa(i)
+		info, err := m.SendFileWithFDSet(fdSetName, f, true)
+		if err != nil {
+			return nil, fmt.Errorf("Failed sending file descriptor of %q: %w", f.Name(), err)
+		}
+
+		reverter.Add(func() { _ = m.RemoveFDFromFDSet(fdSetName) })
+
+		node := map[string]any{
+			"driver":    chain[i].Format,
+			"read-only": true,
+			"file": map[string]any{
+				"driver":    "file",
+				"filename":  fmt.Sprintf("/dev/fdset/%d", info.ID),
+				"aio":       fileNode["aio"],
+				"cache":     fileNode["cache"],
+				"locking":   "off",
+				"read-only": true,
+			},
+		}
+
+		if chain[i].Format == storageDrivers.DiskImageFormatQcow2 {
+			node["backing"] = backing
+		}
+
+		backing = node
+	}
+
+	delete(fileNode, "node-name")
+
+	return map[string]any{
+		"driver":    storageDrivers.DiskImageFormatQcow2,
+		"node-name": nodeName,
+		"read-only": fileNode["read-only"],
+		"cache":     fileNode["cache"],
+		"discard":   fileNode["discard"],
+		"file":      fileNode,
+		"backing":   backing,
+	}, nil
+}
+
 // addNetDevConfig adds the qemu config required for adding a network device.
 // The qemuDev map is expected to be preconfigured with the settings for an existing port to use for the device.
 func (d *qemu) addNetDevConfig(busName string, busAllocate busAllocator, bootIndexes map[string]int, nicConfig []deviceConfig.RunConfigItem) (monitorHook, error) {
@@ -6892,6 +6969,17 @@ func (d *qemu) Export(w io.Writer, properties map[string]string, expiration time
 		return meta, errors.New("No disk path available from mount")
 	}
 
+	// Root disks that aren't raw (including their backing chain) are first flattened into a raw file so that
+	// the confined qemu-img only ever needs access to a single source file.
+	rawPath, cleanup, err := storageDrivers.RawDiskImage(devSource.Path, tmpPath)
+	if err != nil {
+		return meta, fmt.Errorf("Failed converting instance disk to raw: %w", err)
+	}
+
+	defer cleanup()
+
+	devSource.Path = rawPath
+
 	fPath := tmpPath + "/rootfs.img"
 
 	// Convert to qcow2 image.
@@ -6997,11 +7085,24 @@ func (d *qemu) MigrateSend(ctx context.Context, args instance.MigrateSendArgs, p
 		return err
 	}
 
+	volType, err := storagePools.InstanceTypeToVolumeType(d.Type())
+	if err != nil {
+		op.Done(err)
+		return err
+	}
+
 	// The refresh argument passed to MigrationTypes() is always set
 	// to false here. The migration source/sender doesn't need to care whether
 	// or not it's doing a refresh as the migration sink/receiver will know
 	// this, and adjust the migration types accordingly.
-	poolMigrationTypes := pool.MigrationTypes(storagePools.InstanceContentType(d), false, args.Snapshots)
+	// Encrypted instance volumes are only offered non-optimized migration types.
+	poolMigrationTypes, err := storagePools.SourceMigrationTypes(pool, d.project.Name, d.name, volType, storagePools.InstanceContentType(d), args.Snapshots)
+	if err != nil {
+		err := fmt.Errorf("Failed getting source migration types: %w", err)
+		op.Done(err)
+		return err
+	}
+
 	if len(poolMigrationTypes) == 0 {
 		err := errors.New("No source migration types available")
 		op.Done(err)
@@ -8726,7 +8827,7 @@ func (d *qemu) DeviceEventHandler(runConf *deviceConfig.RunConfig) error {
 		devID := qemuDeviceIDPrefix + filesystem.PathNameEncode(mount.DevName)
 
 		// Apply the limits.
-		err = m.SetBlockThrottle(devID, int(mount.Limits.ReadBytes), int(mount.Limits.WriteBytes), int(mount.Limits.ReadIOps), int(mount.Limits.WriteIOps))
+		err = m.SetBlockThrottle(devID, mount.Limits.Group, int(mount.Limits.ReadBytes), int(mount.Limits.WriteBytes), int(mount.Limits.ReadIOps), int(mount.Limits.WriteIOps))
 		if err != nil {
 			return fmt.Errorf("Failed applying limits for disk device %q: %w", mount.DevName, err)
 		}
@@ -1013,9 +1013,11 @@ func (m *Monitor) Eject(id string) error {
 }
 
 // SetBlockThrottle applies an I/O limit on a disk.
-func (m *Monitor) SetBlockThrottle(id string, bytesRead int, bytesWrite int, iopsRead int, iopsWrite int) error {
+// If a group is given, the limit is shared by all the disks of that group.
+func (m *Monitor) SetBlockThrottle(id string, group string, bytesRead int, bytesWrite int, iopsRead int, iopsWrite int) error {
 	var args struct {
-		ID string `json:"id"`
+		ID    string `json:"id"`
+		Group string `json:"group,omitempty"`
 
 		Bytes      int `json:"bps"`
 		BytesRead  int `json:"bps_rd"`
@@ -1026,6 +1028,7 @@ func (m *Monitor) SetBlockThrottle(id string, bytesRead int, bytesWrite int, iop
 	}
 
 	args.ID = id
+	args.Group = group
 	args.BytesRead = bytesRead
 	args.BytesWrite = bytesWrite
 	args.IOPsRead = iopsRead
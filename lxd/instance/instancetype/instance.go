@@ -408,6 +408,39 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 		return err
 	},
 
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups.
+	//
+	// See {ref}`instances-backup-schedule` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: no
+	//  shortdesc: Schedule for automatic instance backups
+	"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.expiry)
+	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Time until scheduled backups are deleted
+	"backups.expiry": func(value string) error {
+		// Validate expression
+		_, err := shared.GetExpiry(time.Time{}, value)
+		return err
+	},
+
+	// lxdmeta:generate(entities=instance; group=backups; key=backups.retain)
+	// Only the given number of most recent scheduled backups is kept, older scheduled backups are deleted after each scheduled backup.
+	// Backups created manually are never deleted.
+	// ---
+	//  type: integer
+	//  defaultdesc: unlimited
+	//  liveupdate: no
+	//  shortdesc: Number of scheduled backups to keep
+	"backups.retain": validate.Optional(validate.IsInRange(1, 4294967295)),
+
 	// lxdmeta:generate(entities=instance; group=miscellaneous; key=ubuntu_pro.guest_attach)
 	// Indicate whether the guest should auto-attach Ubuntu Pro at start up.
 	//
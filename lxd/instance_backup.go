@@ -22,6 +22,7 @@ import (
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
@@ -299,30 +300,12 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			return response.BadRequest(err)
 		}
 
-		base := name + shared.SnapshotDelimiter + "backup"
-		length := len(base)
-		backupNo := 0
-
-		// Iterate over previous backups to autoincrement the backup number.
+		backupNames := make([]string, 0, len(backups))
 		for _, backup := range backups {
-			// Ignore backups not containing base.
-			if !strings.HasPrefix(backup.Name(), base) {
-				continue
-			}
-
-			substr := backup.Name()[length:]
-			var num int
-			count, err := fmt.Sscanf(substr, "%d", &num)
-			if err != nil || count != 1 {
-				continue
-			}
-
-			if num >= backupNo {
-				backupNo = num + 1
-			}
+			backupNames = append(backupNames, backup.Name())
 		}
 
-		req.Name = fmt.Sprintf("backup%d", backupNo)
+		req.Name = backupNextName(name, backupNames, "backup")
 	}
 
 	// In case no version was selected for the backup format use the globally set format by default.
@@ -344,6 +327,45 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 	// We keep the req.ContainerOnly for backward compatibility.
 	instanceOnly := req.InstanceOnly || req.ContainerOnly //nolint:staticcheck,unused
 
+	// Find the snapshot an incremental backup is sent from.
+	var baseSnapshot string
+	if req.IncrementalFrom != "" {
+		if !req.OptimizedStorage {
+			return response.BadRequest(errors.New("Incremental backups require the optimized storage format"))
+		}
+
+		pool, err := storagePools.LoadByInstance(s, inst)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if !pool.Driver().Info().IncrementalBackups {
+			return response.BadRequest(fmt.Errorf("Storage pool %q does not support incremental backups", pool.Name()))
+		}
+
+		prevBackup, err := instance.BackupLoadByName(s, projectName, name+shared.SnapshotDelimiter+req.IncrementalFrom)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed loading backup %q: %w", req.IncrementalFrom, err))
+		}
+
+		baseSnapshot, err = backupIncrementalBase(s, filepath.Join(s.BackupsStoragePath(projectName), "instances", project.Instance(projectName, prevBackup.Name())))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	// Backups pushed to an object store aren't tracked by LXD.
+	if req.TargetURL != "" {
+		if !req.ExpiresAt.IsZero() {
+			return response.BadRequest(errors.New("Backups pushed to an object store cannot have an expiry date"))
+		}
+
+		_, _, err = backupsS3ParseURL(projectName, req.TargetURL)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
 	backup := func(ctx context.Context, op *operations.Operation) error {
 		args := db.InstanceBackup{
 			Name:                 fullName,
@@ -355,7 +377,21 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			CompressionAlgorithm: req.CompressionAlgorithm,
 		}
 
-		err := backupCreate(ctx, s, args, inst, req.Version, op)
+		if req.TargetURL != "" {
+			info, err := backupPush(ctx, s, args, inst, req.TargetURL, baseSnapshot, req.Version, op)
+			if err != nil {
+				return fmt.Errorf("Push backup: %w", err)
+			}
+
+			return op.ExtendMetadata(map[string]any{
+				"url":    req.TargetURL,
+				"size":   info.Size,
+				"etag":   info.ETag,
+				"sha256": info.SHA256,
+			})
+		}
+
+		err := backupCreate(ctx, s, args, inst, baseSnapshot, req.Version, op)
 		if err != nil {
 			return fmt.Errorf("Create backup: %w", err)
 		}
@@ -363,8 +399,9 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		return nil
 	}
 
-	metadata := map[string]any{
-		api.MetadataEntityURL: api.NewURL().Path(version.APIVersion, "instances", name, "backups", backupName).Project(inst.Project().Name).String(),
+	metadata := map[string]any{}
+	if req.TargetURL == "" {
+		metadata[api.MetadataEntityURL] = api.NewURL().Path(version.APIVersion, "instances", name, "backups", backupName).Project(inst.Project().Name).String()
 	}
 
 	args := operations.OperationArgs{
@@ -20,6 +20,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/placement"
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
@@ -271,7 +272,7 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
-		// Pick the member with the least number of instances.
+		// Pick the member using the configured scheduler.
 		if targetMemberInfo == nil {
 			var filteredCandidateMembers []db.NodeInfo
 
@@ -283,10 +284,15 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 				}
 			}
 
-			err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-				targetMemberInfo, err = tx.GetNodeWithLeastInstances(ctx, filteredCandidateMembers)
-				return err
-			})
+			var placementRequirements *placement.Requirements
+			if s.GlobalConfig.ClusterScheduler() == placement.SchedulerResources {
+				placementRequirements, err = placement.InstanceRequirements(inst.Type(), inst.ExpandedConfig(), inst.ExpandedDevices().CloneNative())
+				if err != nil {
+					return response.SmartError(err)
+				}
+			}
+
+			targetMemberInfo, err = clusterSelectMember(r.Context(), s, filteredCandidateMembers, placementRequirements)
 			if err != nil {
 				return response.SmartError(err)
 			}
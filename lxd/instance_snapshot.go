@@ -838,3 +838,101 @@ func snapshotDelete(s *state.State, r *http.Request, snapInst instance.Instance)
 
 	return response.OperationResponse(op)
 }
+
+// swagger:operation GET /1.0/instances/{name}/snapshots/{snapshot}/diff instances instance_snapshot_diff_get
+//
+//	Compare the snapshot
+//
+//	Returns the paths which were added, removed or modified between the instance snapshot
+//	and either a later snapshot or the instance itself.
+//	The request fails if more than 10000 paths differ.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: against
+//	    description: Name of a later snapshot to compare with (defaults to the instance itself)
+//	    type: string
+//	    example: snap1
+//	responses:
+//	  "200":
+//	    description: Changed paths
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of changed paths
+//	          items:
+//	            $ref: "#/definitions/SnapshotDiffEntry"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSnapshotDiffGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	instName := r.PathValue("name")
+	snapshotName := r.PathValue("snapshotName")
+
+	// Forward the request if the instance is remote.
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, instName, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, instName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	entries, err := pool.DiffInstanceSnapshot(inst, snapshotName, request.QueryParam(r, "against"), nil)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if entries == nil {
+		entries = []api.SnapshotDiffEntry{}
+	}
+
+	return response.SyncResponse(true, entries)
+}
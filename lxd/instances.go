@@ -114,6 +114,14 @@ var instanceSnapshotCmd = APIEndpoint{
 	Put:    APIEndpointAction{Handler: instanceSnapshotHandler, AccessHandler: allowPermission(entity.TypeInstanceSnapshot, auth.EntitlementCanEdit, "name", "snapshotName")},
 }
 
+var instanceSnapshotDiffCmd = APIEndpoint{
+	Path:            "instances/{name}/snapshots/{snapshotName}/diff",
+	MetricsType:     entity.TypeInstance,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: instanceSnapshotDiffGet, AccessHandler: allowPermission(entity.TypeInstanceSnapshot, auth.EntitlementCanView, "name", "snapshotName")},
+}
+
 var instanceConsoleCmd = APIEndpoint{
 	Path:            "instances/{name}/console",
 	MetricsType:     entity.TypeInstance,
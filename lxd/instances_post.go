@@ -28,6 +28,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/instance/operationlock"
+	"github.com/canonical/lxd/lxd/lifecycle"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/placement"
 	"github.com/canonical/lxd/lxd/project"
@@ -904,6 +905,19 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return response.BadRequest(errors.New("Instance definition in backup config is missing"))
 	}
 
+	// Incremental backups are applied on top of an existing instance.
+	if bInfo.BaseSnapshot != "" {
+		if instanceName != "" {
+			bInfo.Name = instanceName
+		}
+
+		bInfo.Project = projectName
+
+		// The backup file is closed once the incremental backup has been applied.
+		revert.Success()
+		return createFromIncrementalBackup(s, r, bInfo, backupFile, pool, devices)
+	}
+
 	// Initialise the devices maps.
 	if bInfo.Config.Instance.Devices == nil {
 		bInfo.Config.Instance.Devices = make(map[string]map[string]string, 0)
@@ -1153,6 +1167,173 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 	return response.OperationResponse(op)
 }
 
+// createFromObjectBackup imports the backup stored in an object of the S3 endpoint for backups.
+// The storage pool to import the backup into is taken from the root disk device of the request, if any.
+func createFromObjectBackup(s *state.State, r *http.Request, projectName string, req api.InstancesPost) response.Response {
+	bucket, key, err := backupsS3ParseURL(projectName, req.Source.URL)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	client, err := backupsS3Client(s)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	body, err := client.Download(r.Context(), bucket, key)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	defer func() { _ = body.Close() }()
+
+	var pool string
+	_, rootDev, err := api.GetRootDiskDevice(req.Devices)
+	if err == nil {
+		pool = rootDev["pool"]
+	}
+
+	return createFromBackup(s, r, projectName, body, pool, req.Name, req.Devices)
+}
+
+// createFromIncrementalBackup applies an incremental backup on top of the existing instance it follows.
+// The instance's root volume data is updated and the snapshots included in the backup are created, the
+// instance's config is left unchanged.
+func createFromIncrementalBackup(s *state.State, r *http.Request, bInfo *backup.Info, backupFile *os.File, pool string, devices map[string]map[string]string) response.Response {
+	revert := revert.New()
+	defer revert.Fail()
+
+	revert.Add(func() { _ = backupFile.Close() })
+
+	if len(devices) > 0 {
+		return response.BadRequest(errors.New("Device overrides cannot be used when importing incremental backups"))
+	}
+
+	// Incremental backups modify an existing instance and its snapshots so require permission to do both.
+	instanceURL := entity.InstanceURL(bInfo.Project, bInfo.Name)
+	err := s.Authorizer.CheckPermission(r.Context(), instanceURL, auth.EntitlementCanEdit)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(bInfo.Snapshots) > 0 {
+		err = s.Authorizer.CheckPermission(r.Context(), instanceURL, auth.EntitlementCanManageSnapshots)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, bInfo.Project, bInfo.Name)
+	if err != nil {
+		if response.IsNotFoundError(err) {
+			return response.BadRequest(fmt.Errorf("Instance %q not found, import the backups of the chain in order", bInfo.Name))
+		}
+
+		return response.SmartError(err)
+	}
+
+	instPool, err := inst.StoragePool()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if pool != "" && pool != instPool {
+		return response.BadRequest(fmt.Errorf("Incremental backups must be imported into the storage pool %q of instance %q", instPool, inst.Name()))
+	}
+
+	if inst.IsRunning() {
+		return response.BadRequest(errors.New("Cannot apply incremental backup to a running instance"))
+	}
+
+	logger.Debug("Incremental backup file info loaded", logger.Ctx{
+		"type":         bInfo.Type,
+		"name":         bInfo.Name,
+		"project":      bInfo.Project,
+		"backend":      bInfo.Backend,
+		"snapshots":    bInfo.Snapshots,
+		"baseSnapshot": bInfo.BaseSnapshot,
+	})
+
+	// Copy reverter so far so we can use it inside run after this function has finished.
+	runRevert := revert.Clone()
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		defer func() { _ = backupFile.Close() }()
+		defer runRevert.Fail()
+
+		pool, err := storagePools.LoadByInstance(s, inst)
+		if err != nil {
+			return err
+		}
+
+		// Check the source pool driver matches the target pool driver.
+		if pool.Driver().Info().Name != bInfo.Backend {
+			return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
+		}
+
+		revertHook, err := pool.RefreshInstanceFromBackup(inst, *bInfo, backupFile, nil)
+		if err != nil {
+			return fmt.Errorf("Apply incremental backup to instance: %w", err)
+		}
+
+		runRevert.Add(revertHook)
+
+		volType, err := storagePools.InstanceTypeToVolumeType(inst.Type())
+		if err != nil {
+			return err
+		}
+
+		// Create the records of the snapshots included in the backup.
+		for _, snapName := range bInfo.Snapshots {
+			var snap *api.InstanceSnapshot
+			for _, s := range bInfo.Config.Snapshots {
+				if s != nil && s.Name == snapName {
+					snap = s
+					break
+				}
+			}
+
+			if snap == nil {
+				return fmt.Errorf("Instance snapshot %q not found in backup config", snapName)
+			}
+
+			cleanup, err := internalImportSnapshotRecord(ctx, s, inst.Project().Name, inst.Name(), instPool, inst.Type(), volType, snap)
+			if err != nil {
+				return err
+			}
+
+			runRevert.Add(cleanup)
+		}
+
+		err = inst.UpdateBackupFile()
+		if err != nil {
+			return fmt.Errorf("Failed updating backup file: %w", err)
+		}
+
+		runRevert.Success()
+
+		s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceUpdated.Event(ctx, inst, nil))
+
+		return nil
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: bInfo.Project,
+		EntityURL:   api.NewURL().Path(version.APIVersion, "instances", bInfo.Name).Project(bInfo.Project),
+		Type:        operationtype.BackupRestore,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	revert.Success()
+	return response.OperationResponse(op)
+}
+
 // instanceProfilesFromNames loads the named profiles from the database and returns them as API
 // structs in the same order as the input names. It is intended to be called inside a cluster
 // transaction.
@@ -1370,6 +1551,11 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// Backups stored in an object store are imported in the same way as uploaded ones.
+	if req.Source.Type == api.SourceTypeBackup {
+		return createFromObjectBackup(s, r, targetProjectName, req)
+	}
+
 	// Set type from URL if missing
 	urlType, err := urlInstanceTypeDetect(r)
 	if err != nil {
@@ -1439,6 +1625,7 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 	var targetMemberInfo *db.NodeInfo
 	var targetGroupName string
 	var placementGroupName string
+	var placementRequirements *placement.Requirements
 
 	// Set to true once we find that the request is currently handled on a member which isn't hosting the source instance.
 	sourceInstOnDifferentMember := false
@@ -1703,10 +1890,24 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 
 			expandedConfig := instancetype.ExpandInstanceConfig(s.GlobalConfig.Dump(), req.Config, profiles)
 			placementGroupName = expandedConfig["placement.group"]
-			targetMemberInfo, err = instancesPostSelectClusterMember(ctx, tx, placementGroupName, candidateMembers, targetProject.Name)
+			candidateMembers, err = instancesPostFilterClusterMembers(ctx, tx, placementGroupName, candidateMembers, targetProject.Name)
 			if err != nil {
 				return err
 			}
+
+			// The resources scheduler needs to know what the instance requires from the cluster member.
+			if s.GlobalConfig.ClusterScheduler() == placement.SchedulerResources {
+				instanceType, err := instancetype.New(string(req.Type))
+				if err != nil {
+					return err
+				}
+
+				expandedDevices := instancetype.ExpandInstanceDevices(deviceConfig.NewDevices(req.Devices), profiles)
+				placementRequirements, err = placement.InstanceRequirements(instanceType, expandedConfig, expandedDevices.CloneNative())
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		if !clusterNotification {
@@ -1732,6 +1933,15 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Select the cluster member among the candidates outside of the transaction,
+	// as the resources scheduler queries the candidate members.
+	if s.ServerClustered && !clusterNotification && targetMemberInfo == nil {
+		targetMemberInfo, err = instancesPostSelectClusterMember(r.Context(), s, candidateMembers, placementRequirements)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	poolSupportsInternalCopy := false
 
 	if s.ServerClustered && req.Source.Type == api.SourceTypeCopy && sourceInstPoolName != "" {
@@ -1820,14 +2030,13 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 	}
 }
 
-// instancesPostSelectClusterMember determines which cluster member to use for placing an instance during creation or migration.
-// It first checks whether the instance belongs to a placement group and, if so, applies the placement group’s policy and rigor to filter the available members.
-// Among the remaining candidates, the member with the fewest existing instances is selected.
-// If the instance does not belong to a placement group, the member with the fewest instances is chosen from all candidates.
-func instancesPostSelectClusterMember(ctx context.Context, tx *db.ClusterTx, placementGroupName string, candidateMembers []db.NodeInfo, projectName string) (*db.NodeInfo, error) {
+// instancesPostFilterClusterMembers filters the candidate cluster members for placing an instance during creation or migration.
+// If the instance belongs to a placement group, the placement group’s policy and rigor are applied to filter the available members.
+// Otherwise all candidates are returned.
+func instancesPostFilterClusterMembers(ctx context.Context, tx *db.ClusterTx, placementGroupName string, candidateMembers []db.NodeInfo, projectName string) ([]db.NodeInfo, error) {
 	// Check if instance is using a placement group.
 	if placementGroupName == "" {
-		return tx.GetNodeWithLeastInstances(ctx, candidateMembers)
+		return candidateMembers, nil
 	}
 
 	placementGroup, err := dbCluster.GetPlacementGroup(ctx, tx.Tx(), placementGroupName, projectName)
@@ -1842,18 +2051,19 @@ func instancesPostSelectClusterMember(ctx context.Context, tx *db.ClusterTx, pla
 
 	apiPlacementGroup := placementGroup.ToAPI(configs)
 
-	filteredCandidates, err := placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, false)
-	if err != nil {
-		return nil, err
-	}
+	return placement.Filter(ctx, tx, candidateMembers, *apiPlacementGroup, false)
+}
 
-	// Early return if only a single candidate.
-	if len(filteredCandidates) == 1 {
-		return &filteredCandidates[0], nil
+// instancesPostSelectClusterMember determines which cluster member to use for placing an instance among the filtered candidates.
+// With the default scheduler, the member with the fewest existing instances is selected.
+// With the resources scheduler, the member with the most resource headroom that can fit the instance is selected.
+func instancesPostSelectClusterMember(ctx context.Context, s *state.State, candidateMembers []db.NodeInfo, req *placement.Requirements) (*db.NodeInfo, error) {
+	// Early return if only a single candidate and there are no resource requirements to check.
+	if len(candidateMembers) == 1 && req == nil {
+		return &candidateMembers[0], nil
 	}
 
-	// Use filtered candidates to pick the node with least instances.
-	return tx.GetNodeWithLeastInstances(ctx, filteredCandidates)
+	return clusterSelectMember(ctx, s, candidateMembers, req)
 }
 
 func instanceFindStoragePool(s *state.State, projectName string, req *api.InstancesPost) (storagePool string, storagePoolProfile string, localRootDiskDeviceKey string, localRootDiskDevice map[string]string, err error) {
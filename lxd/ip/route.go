@@ -16,6 +16,7 @@ type Route struct {
 	Proto   string
 	Family  string
 	Via     string
+	VRF     string
 }
 
 // Add adds new route.
@@ -25,11 +26,19 @@ func (r *Route) Add() error {
 		cmd = append(cmd, "table", r.Table)
 	}
 
+	if r.VRF != "" {
+		cmd = append(cmd, "vrf", r.VRF)
+	}
+
 	if r.Via != "" {
 		cmd = append(cmd, "via", r.Via)
 	}
 
-	cmd = append(cmd, r.Route, "dev", r.DevName)
+	cmd = append(cmd, r.Route)
+	if r.DevName != "" {
+		cmd = append(cmd, "dev", r.DevName)
+	}
+
 	if r.Src != "" {
 		cmd = append(cmd, "src", r.Src)
 	}
@@ -48,7 +57,25 @@ func (r *Route) Add() error {
 
 // Delete deletes routing table.
 func (r *Route) Delete() error {
-	_, err := shared.RunCommand(context.TODO(), "ip", r.Family, "route", "delete", "table", r.Table, r.Route, "dev", r.DevName)
+	cmd := []string{r.Family, "route", "delete"}
+	if r.Table != "" {
+		cmd = append(cmd, "table", r.Table)
+	}
+
+	if r.VRF != "" {
+		cmd = append(cmd, "vrf", r.VRF)
+	}
+
+	cmd = append(cmd, r.Route)
+	if r.DevName != "" {
+		cmd = append(cmd, "dev", r.DevName)
+	}
+
+	if r.Proto != "" {
+		cmd = append(cmd, "proto", r.Proto)
+	}
+
+	_, err := shared.RunCommand(context.TODO(), "ip", cmd...)
 	if err != nil {
 		return err
 	}
@@ -87,8 +114,24 @@ func (r *Route) Flush() error {
 
 // Replace changes or adds new route.
 func (r *Route) Replace(routes []string) error {
-	cmd := make([]string, 0, 7+len(routes))
-	cmd = append(cmd, r.Family, "route", "replace", "dev", r.DevName, "proto", r.Proto)
+	cmd := make([]string, 0, 11+len(routes))
+	cmd = append(cmd, r.Family, "route", "replace")
+	if r.DevName != "" {
+		cmd = append(cmd, "dev", r.DevName)
+	}
+
+	if r.Proto != "" {
+		cmd = append(cmd, "proto", r.Proto)
+	}
+
+	if r.Table != "" {
+		cmd = append(cmd, "table", r.Table)
+	}
+
+	if r.VRF != "" {
+		cmd = append(cmd, "vrf", r.VRF)
+	}
+
 	cmd = append(cmd, routes...)
 	_, err := shared.RunCommand(context.TODO(), "ip", cmd...)
 	if err != nil {
@@ -94,6 +94,10 @@ func (m *MetricSet) String() string {
 		GoHeapObjects,
 		Instances,
 		APIOngoingRequests,
+		ReplicatorRecoveryPointAgeSeconds,
+		StoragePoolHealthState,
+		StoragePoolScrubErrors,
+		StoragePoolScrubTimestampSeconds,
 	}
 
 	for _, metricType := range metricTypes {
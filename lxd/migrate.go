@@ -101,6 +101,16 @@ func (c *migrationFields) disconnect() {
 	}
 }
 
+// bytesSent returns the number of bytes sent over the data connections of the migration.
+func (c *migrationFields) bytesSent() int64 {
+	var total int64
+	for _, conn := range c.conns {
+		total += conn.BytesSent()
+	}
+
+	return total
+}
+
 func (c *migrationFields) sendControl(err error) {
 	c.controlLock.Lock()
 	conn, _ := c.conns[api.SecretNameControl].WebSocket(context.TODO())
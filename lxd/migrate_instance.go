@@ -99,6 +99,9 @@ func (s *migrationSourceWs) Do(ctx context.Context, state *state.State, migrateO
 	defer l.Info("Migration channels disconnected on source")
 	defer s.disconnect()
 
+	// Report the amount of data sent so that callers driving the migration through the API can account for it.
+	defer func() { _ = migrateOp.ExtendMetadata(map[string]any{api.MetadataBytesSent: s.bytesSent()}) }()
+
 	stateConnFunc := func(ctx context.Context) (io.ReadWriteCloser, error) {
 		conn := s.conns[api.SecretNameState]
 		if conn == nil {
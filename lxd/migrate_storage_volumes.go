@@ -91,6 +91,9 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 	defer l.Info("Migration channels disconnected on source")
 	defer s.disconnect()
 
+	// Report the amount of data sent so that callers driving the migration through the API can account for it.
+	defer func() { _ = migrateOp.ExtendMetadata(map[string]any{api.MetadataBytesSent: s.bytesSent()}) }()
+
 	var poolMigrationTypes []migration.Type
 
 	pool, err := storagePools.LoadByName(state, poolName)
@@ -112,7 +115,12 @@ func (s *migrationSourceWs) DoStorage(state *state.State, projectName string, po
 	// to false here. The migration source/sender doesn't need to care whether
 	// or not it's doing a refresh as the migration sink/receiver will know
 	// this, and adjust the migration types accordingly.
-	poolMigrationTypes = pool.MigrationTypes(storageDrivers.ContentType(customVol.ContentType), false, !s.volumeOnly)
+	// Encrypted volumes are only offered non-optimized migration types.
+	poolMigrationTypes, err = storagePools.SourceMigrationTypes(pool, projectName, volName, storageDrivers.VolumeTypeCustom, storageDrivers.ContentType(customVol.ContentType), !s.volumeOnly)
+	if err != nil {
+		return fmt.Errorf("Failed getting source migration types: %w", err)
+	}
+
 	if len(poolMigrationTypes) == 0 {
 		return errors.New("No source migration types available")
 	}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -63,6 +64,22 @@ type migrationConn struct {
 	conn           *websocket.Conn
 	connected      chan struct{}
 	disconnected   bool
+	bytesSent      atomic.Int64
+}
+
+// migrationConnIO wraps the websocket of a migration connection and counts the bytes written to it.
+type migrationConnIO struct {
+	io.ReadWriteCloser
+
+	conn *migrationConn
+}
+
+// Write writes to the underlying websocket and records the number of bytes written.
+func (w *migrationConnIO) Write(p []byte) (int, error) {
+	n, err := w.ReadWriteCloser.Write(p)
+	w.conn.bytesSent.Add(int64(n))
+
+	return n, err
 }
 
 // Secret returns the secret for this connection.
@@ -167,7 +184,12 @@ func (c *migrationConn) WebsocketIO(ctx context.Context) (io.ReadWriteCloser, er
 		return nil, err
 	}
 
-	return ws.NewWrapper(wsConn), nil
+	return &migrationConnIO{ReadWriteCloser: ws.NewWrapper(wsConn), conn: c}, nil
+}
+
+// BytesSent returns the number of bytes written to the connection through WebsocketIO.
+func (c *migrationConn) BytesSent() int64 {
+	return c.bytesSent.Load()
 }
 
 // Close closes the connection (if established) and marks it as disconnected so that it cannot be used again.
@@ -0,0 +1,250 @@
+package acl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// ruleAddressSets returns the names of the network address sets referenced by the rules of an ACL.
+func ruleAddressSets(info *api.NetworkACL) []string {
+	var names []string
+
+	for _, rule := range append(slices.Clone(info.Ingress), info.Egress...) {
+		for _, subject := range append(shared.SplitNTrimSpace(rule.Source, ",", -1, true), shared.SplitNTrimSpace(rule.Destination, ",", -1, true)...) {
+			name, isAddressSet := dbCluster.NetworkAddressSetSubject(subject)
+			if isAddressSet && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+// addressSetIPNets converts the addresses of a network address set into IP networks.
+func addressSetIPNets(addresses []string) ([]net.IPNet, error) {
+	ipNets := make([]net.IPNet, 0, len(addresses))
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip != nil {
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+
+			ipNets = append(ipNets, net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(address)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid address %q: %w", address, err)
+		}
+
+		ipNets = append(ipNets, *ipNet)
+	}
+
+	return ipNets, nil
+}
+
+// firewallRuleInlineAddressSets returns the rule subjects with any address set references replaced by the
+// addresses of the sets. This is used with firewall drivers that do not support address sets.
+// Only addresses of the IP family that can be used with the rule's protocol are included.
+// Returns false if subjects were specified but none have usable addresses, in which case the rule cannot match
+// any traffic and should be skipped.
+func firewallRuleInlineAddressSets(subjects string, protocol string, addressSets map[string][]string) (string, bool) {
+	if subjects == "" {
+		return "", true
+	}
+
+	result := []string{}
+	for _, subject := range shared.SplitNTrimSpace(subjects, ",", -1, true) {
+		name, isAddressSet := dbCluster.NetworkAddressSetSubject(subject)
+		if !isAddressSet {
+			result = append(result, subject)
+			continue
+		}
+
+		for _, address := range addressSets[name] {
+			ip := net.ParseIP(address)
+			if ip == nil {
+				ip, _, _ = net.ParseCIDR(address)
+			}
+
+			if ip == nil {
+				continue
+			}
+
+			isIPv4 := ip.To4() != nil
+			if (protocol == "icmp4" && !isIPv4) || (protocol == "icmp6" && isIPv4) {
+				continue
+			}
+
+			result = append(result, address)
+		}
+	}
+
+	if len(result) == 0 {
+		return "", false
+	}
+
+	return strings.Join(result, ","), true
+}
+
+// ovnNetworkAddressSetPrefix returns the prefix of the OVN address sets holding the addresses of a network
+// address set. The address sets are shared by all ACLs of the project referencing the network address set.
+func ovnNetworkAddressSetPrefix(addressSetID int64) openvswitch.OVNAddressSet {
+	return openvswitch.OVNAddressSet(fmt.Sprintf("lxd_address_set%d", addressSetID))
+}
+
+// ovnNetworkAddressSetApply sets the addresses of the OVN address sets used for a network address set.
+func ovnNetworkAddressSetApply(client *openvswitch.OVN, addressSet dbCluster.NetworkAddressSet) error {
+	ipNets, err := addressSetIPNets(addressSet.Row.Addresses)
+	if err != nil {
+		return fmt.Errorf("Failed parsing addresses of network address set %q: %w", addressSet.Row.Name, err)
+	}
+
+	return client.AddressSetReplace(ovnNetworkAddressSetPrefix(addressSet.Row.ID), ipNets...)
+}
+
+// AddressSetApply applies the current addresses of a network address set to the networks using the ACLs that
+// reference it, without reapplying the ACL rules. Bridge networks are updated on the local member only. The OVN
+// address sets are shared by all cluster members and so are only updated when the client type is normal.
+func AddressSetApply(ctx context.Context, s *state.State, projectName string, addressSetName string, clientType request.ClientType) error {
+	var addressSet *dbCluster.NetworkAddressSet
+	var aclNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		addressSet, err = dbCluster.GetNetworkAddressSet(ctx, tx.Tx(), addressSetName, projectName)
+		if err != nil {
+			return err
+		}
+
+		projectACLNames, err := tx.GetNetworkACLs(ctx, projectName)
+		if err != nil {
+			return err
+		}
+
+		for _, aclName := range projectACLNames {
+			_, aclInfo, err := tx.GetNetworkACL(ctx, projectName, aclName)
+			if err != nil {
+				return err
+			}
+
+			if slices.Contains(ruleAddressSets(aclInfo), addressSetName) {
+				aclNames = append(aclNames, aclName)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network address set %q: %w", addressSetName, err)
+	}
+
+	if len(aclNames) == 0 {
+		return nil
+	}
+
+	// Get a list of networks that are using the ACLs (either directly or indirectly via a NIC).
+	aclNets := map[string]NetworkACLUsage{}
+	err = NetworkUsage(ctx, s, projectName, aclNames, aclNets)
+	if err != nil {
+		return fmt.Errorf("Failed getting ACL network usage: %w", err)
+	}
+
+	usedByOVN := false
+	for _, aclNet := range aclNets {
+		if aclNet.Type == "ovn" {
+			usedByOVN = true
+			continue
+		}
+
+		// Only update bridge networks running on this member.
+		if aclNet.Type != "bridge" || !shared.PathExists("/sys/class/net/"+aclNet.Name) {
+			continue
+		}
+
+		// The xtables driver does not support address sets, so the addresses are part of the ACL rules.
+		if s.Firewall.String() == "xtables" {
+			err = FirewallApplyACLRules(ctx, s, projectName, aclNet)
+		} else {
+			err = s.Firewall.NetworkApplyAddressSets(aclNet.Name, []firewallDrivers.AddressSet{{Name: addressSet.Row.Name, Addresses: addressSet.Row.Addresses}})
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed applying network address set %q to network %q: %w", addressSetName, aclNet.Name, err)
+		}
+	}
+
+	if usedByOVN && clientType == request.ClientTypeNormal {
+		client, err := openvswitch.NewOVN(s.GlobalConfig.NetworkOVNNorthboundConnection(), s.GlobalConfig.NetworkOVNSSL)
+		if err != nil {
+			return fmt.Errorf("Failed getting OVN client: %w", err)
+		}
+
+		err = ovnNetworkAddressSetApply(client, *addressSet)
+		if err != nil {
+			return fmt.Errorf("Failed applying network address set %q to OVN: %w", addressSetName, err)
+		}
+	}
+
+	return nil
+}
+
+// AddressSetDelete removes the OVN address sets of a network address set that is being deleted.
+// The network address set must not be referenced by any ACL. Bridge networks do not need cleaning up as their
+// address sets are removed when they are no longer referenced by the ACL rules.
+func AddressSetDelete(ctx context.Context, s *state.State, projectName string, addressSetID int64) error {
+	var hasOVNNetworks bool
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		networks, err := tx.GetCreatedNetworksByProject(ctx, projectName)
+		if err != nil {
+			return err
+		}
+
+		for _, network := range networks {
+			if network.Type == "ovn" {
+				hasOVNNetworks = true
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading networks: %w", err)
+	}
+
+	// OVN address sets are only created for projects with OVN networks.
+	if !hasOVNNetworks {
+		return nil
+	}
+
+	client, err := openvswitch.NewOVN(s.GlobalConfig.NetworkOVNNorthboundConnection(), s.GlobalConfig.NetworkOVNSSL)
+	if err != nil {
+		return fmt.Errorf("Failed getting OVN client: %w", err)
+	}
+
+	err = client.AddressSetDelete(ovnNetworkAddressSetPrefix(addressSetID))
+	if err != nil {
+		return fmt.Errorf("Failed deleting OVN address set: %w", err)
+	}
+
+	return nil
+}
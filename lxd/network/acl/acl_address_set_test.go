@@ -0,0 +1,88 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/lxd/db/cluster"
+)
+
+func Test_firewallRuleInlineAddressSets(t *testing.T) {
+	addressSets := map[string][]string{
+		"web":   {"192.0.2.1", "198.51.100.0/24", "2001:db8::/64"},
+		"empty": {},
+	}
+
+	tests := []struct {
+		name     string
+		subjects string
+		protocol string
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "No subjects",
+			subjects: "",
+			expected: "",
+			ok:       true,
+		},
+		{
+			name:     "No address sets",
+			subjects: "192.0.2.2,2001:db8:1::/64",
+			expected: "192.0.2.2,2001:db8:1::/64",
+			ok:       true,
+		},
+		{
+			name:     "Address set and static subject",
+			subjects: "192.0.2.2,$web",
+			expected: "192.0.2.2,192.0.2.1,198.51.100.0/24,2001:db8::/64",
+			ok:       true,
+		},
+		{
+			name:     "Address set with ICMPv6",
+			subjects: "$web",
+			protocol: "icmp6",
+			expected: "2001:db8::/64",
+			ok:       true,
+		},
+		{
+			name:     "Empty address set",
+			subjects: "$empty",
+			ok:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := firewallRuleInlineAddressSets(tt.subjects, tt.protocol, addressSets)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %v, got %v", tt.ok, ok)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func Test_ovnRuleSubjectToOVNACLMatch_AddressSet(t *testing.T) {
+	portGroupName := OVNACLPortGroupName(1)
+	addressSets := map[string]cluster.NetworkAddressSet{
+		"web": {Row: cluster.NetworkAddressSetsRow{ID: 5, Name: "web"}},
+	}
+
+	result, _, _, err := ovnRuleSubjectToOVNACLMatch("src", portGroupName, false, nil, addressSets, nil, "192.0.2.1", "$web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "ip4.src == 192.0.2.1 || ip6.src == $lxd_address_set5_ip6 || ip4.src == $lxd_address_set5_ip4"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	_, _, _, err = ovnRuleSubjectToOVNACLMatch("src", portGroupName, false, nil, addressSets, nil, "$missing")
+	if err == nil {
+		t.Error("Expected error for unknown address set")
+	}
+}
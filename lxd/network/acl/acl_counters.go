@@ -0,0 +1,373 @@
+package acl
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/canonical/lxd/lxd/db"
+	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// ruleCountersID returns the identifier used to gather the counters of an ACL rule.
+// The rule index is the position of the rule in the ACL's ingress or egress rules (including disabled rules).
+func ruleCountersID(aclID int64, direction string, ruleIndex int) string {
+	return fmt.Sprintf("acl%d-%s-%d", aclID, direction, ruleIndex)
+}
+
+// ovnRuleCounters gathers the counters of OVN ACL rules from the flows of the local OVN chassis.
+type ovnRuleCounters struct {
+	client        *openvswitch.OVN
+	flowsByCookie map[uint64][]openvswitch.OVSFlowStatistics
+
+	// Tunnel keys of the logical ports matched by the flows contributing to each conjunctive match.
+	conjunctionPorts map[uint32]map[uint64]struct{}
+}
+
+// load connects to OVN and reads the flow statistics of the local integration bridge.
+// Returns false if the local member is not an OVN chassis.
+func (o *ovnRuleCounters) load(s *state.State) (bool, error) {
+	if o.client != nil {
+		return true, nil
+	}
+
+	ovs := openvswitch.NewOVS()
+	if !ovs.Installed() {
+		return false, nil
+	}
+
+	integrationBridge := s.GlobalConfig.NetworkOVNIntegrationBridge()
+	exists, err := ovs.BridgeExists(integrationBridge)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	flows, err := ovs.BridgeFlowStatistics(integrationBridge)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting flow statistics of OVN integration bridge %q: %w", integrationBridge, err)
+	}
+
+	client, err := openvswitch.NewOVN(s.GlobalConfig.NetworkOVNNorthboundConnection(), s.GlobalConfig.NetworkOVNSSL)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting OVN client: %w", err)
+	}
+
+	o.flowsByCookie = make(map[uint64][]openvswitch.OVSFlowStatistics)
+	o.conjunctionPorts = make(map[uint32]map[uint64]struct{})
+	for _, flow := range flows {
+		o.flowsByCookie[flow.Cookie] = append(o.flowsByCookie[flow.Cookie], flow)
+
+		for _, conjID := range flow.Conjunctions {
+			if o.conjunctionPorts[conjID] == nil {
+				o.conjunctionPorts[conjID] = make(map[uint64]struct{})
+			}
+
+			for _, portKey := range []uint64{flow.InPort, flow.OutPort} {
+				if portKey != 0 {
+					o.conjunctionPorts[conjID][portKey] = struct{}{}
+				}
+			}
+		}
+	}
+
+	o.client = client
+
+	return true, nil
+}
+
+// flowMatchesPort returns whether the packets counted by a flow are the ones of the logical port with the given
+// tunnel key. OVN expands the port groups of the ACL rules into one flow per local port, unless it combines them
+// with other criteria in a conjunctive match, in which case the conjunctive match must only be for that port.
+func (o *ovnRuleCounters) flowMatchesPort(flow openvswitch.OVSFlowStatistics, portKey uint64) bool {
+	if flow.InPort == portKey || flow.OutPort == portKey {
+		return true
+	}
+
+	if flow.ConjunctionID == 0 {
+		return false
+	}
+
+	ports := o.conjunctionPorts[flow.ConjunctionID]
+	_, found := ports[portKey]
+
+	return found && len(ports) == 1
+}
+
+// counters returns the counters of the rules of an ACL applied to an OVN network, keyed by rule identifier.
+// If portKey is not zero, only the traffic of the logical port with that tunnel key is counted.
+func (o *ovnRuleCounters) counters(aclID int64, networkID int64, portKey uint64) (map[string]firewallDrivers.ACLRuleCounters, error) {
+	// Flows of logical flows shared by several logical switches are told apart by the datapath metadata.
+	tunnelKey, err := o.client.LogicalSwitchTunnelKey(OVNIntSwitchName(networkID))
+	if err != nil {
+		return nil, err
+	}
+
+	ruleCookies, err := o.client.PortGroupACLRuleFlowCookies(OVNACLPortGroupName(aclID), OVNACLNetworkPortGroupName(aclID, networkID))
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]firewallDrivers.ACLRuleCounters, len(ruleCookies))
+	for ruleID, cookies := range ruleCookies {
+		ruleCounters := firewallDrivers.ACLRuleCounters{}
+		for _, cookie := range cookies {
+			for _, flow := range o.flowsByCookie[cookie] {
+				// Flows only contributing to a conjunctive match don't count packets.
+				if flow.Metadata != tunnelKey || len(flow.Conjunctions) > 0 {
+					continue
+				}
+
+				if portKey != 0 && !o.flowMatchesPort(flow, portKey) {
+					continue
+				}
+
+				ruleCounters.Packets += flow.Packets
+				ruleCounters.Bytes += flow.Bytes
+			}
+		}
+
+		counters[ruleID] = ruleCounters
+	}
+
+	return counters, nil
+}
+
+// networkCounters returns the counters of the rules of an ACL applied to an OVN network, keyed by rule identifier.
+func (o *ovnRuleCounters) networkCounters(aclID int64, networkID int64) (map[string]firewallDrivers.ACLRuleCounters, error) {
+	return o.counters(aclID, networkID, 0)
+}
+
+// nicCounters returns the counters of the rules of an ACL applied to an instance NIC connected to an OVN network,
+// keyed by rule identifier. Returns nil if the logical switch port of the NIC isn't bound, for example because the
+// instance is stopped.
+func (o *ovnRuleCounters) nicCounters(aclID int64, networkID int64, portName openvswitch.OVNSwitchPort) (map[string]firewallDrivers.ACLRuleCounters, error) {
+	portKey, err := o.client.LogicalSwitchPortTunnelKey(portName)
+	if err != nil {
+		return nil, err
+	}
+
+	if portKey == 0 {
+		return nil, nil
+	}
+
+	return o.counters(aclID, networkID, portKey)
+}
+
+// ruleCountersToAPI converts the counters of the rules of an ACL keyed by rule identifier into the ingress and
+// egress rule counters of the API, in the same order as the rules.
+func ruleCountersToAPI(aclID int64, aclInfo *api.NetworkACL, counters map[string]firewallDrivers.ACLRuleCounters) (ingress []api.NetworkACLRuleCounters, egress []api.NetworkACLRuleCounters) {
+	ingress = make([]api.NetworkACLRuleCounters, len(aclInfo.Ingress))
+	for ruleIndex := range aclInfo.Ingress {
+		ruleCounters := counters[ruleCountersID(aclID, "ingress", ruleIndex)]
+		ingress[ruleIndex] = api.NetworkACLRuleCounters{Packets: ruleCounters.Packets, Bytes: ruleCounters.Bytes}
+	}
+
+	egress = make([]api.NetworkACLRuleCounters, len(aclInfo.Egress))
+	for ruleIndex := range aclInfo.Egress {
+		ruleCounters := counters[ruleCountersID(aclID, "egress", ruleIndex)]
+		egress[ruleIndex] = api.NetworkACLRuleCounters{Packets: ruleCounters.Packets, Bytes: ruleCounters.Bytes}
+	}
+
+	return ingress, egress
+}
+
+// ovnNICUsage represents an instance NIC on the local member that sets an ACL in its own security.acls option.
+type ovnNICUsage struct {
+	project      string
+	instance     string
+	instanceUUID string
+	name         string
+	network      NetworkACLUsage
+}
+
+// ovnNICUsages returns the NICs of the instances on the local member that set the ACL in their own security.acls
+// option and are connected to one of the specified OVN networks.
+func ovnNICUsages(ctx context.Context, s *state.State, aclProjectName string, aclName string, aclNets map[string]NetworkACLUsage) ([]ovnNICUsage, error) {
+	nics := []ovnNICUsage{}
+
+	// Finding the instance NICs is expensive so skip it when no OVN network uses the ACL.
+	if !slices.ContainsFunc(slices.Collect(maps.Values(aclNets)), func(aclNet NetworkACLUsage) bool { return aclNet.Type == "ovn" }) {
+		return nics, nil
+	}
+
+	err := UsedBy(ctx, s, aclProjectName, func(ctx context.Context, tx *db.ClusterTx, _ []string, usageType any, nicName string, nicConfig map[string]string) error {
+		inst, ok := usageType.(db.InstanceArgs)
+		if !ok || inst.Node != s.ServerName {
+			return nil
+		}
+
+		aclNet, found := aclNets[nicConfig["network"]]
+		if !found || aclNet.Type != "ovn" {
+			return nil
+		}
+
+		nics = append(nics, ovnNICUsage{
+			project:      inst.Project,
+			instance:     inst.Name,
+			instanceUUID: inst.Config["volatile.uuid"],
+			name:         nicName,
+			network:      aclNet,
+		})
+
+		return nil
+	}, aclName)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(nics, func(a ovnNICUsage, b ovnNICUsage) int {
+		return cmp.Or(cmp.Compare(a.project, b.project), cmp.Compare(a.instance, b.instance), cmp.Compare(a.name, b.name))
+	})
+
+	return nics, nil
+}
+
+// RuleCounters returns the counters of the rules of the specified ACLs gathered on the local member, for each network
+// using the ACLs and for each instance NIC on an OVN network that sets the ACLs in its own security.acls option.
+// The result is keyed by ACL name.
+// On OVN networks, the network counters include the traffic of the instance NICs using the ACLs as OVN applies the
+// rules of NIC ACLs through the same port groups.
+// Bridge networks only report counters when using the nftables firewall driver.
+func RuleCounters(ctx context.Context, s *state.State, aclProjectName string, aclNames ...string) (map[string]*api.NetworkACLState, error) {
+	aclIDs := make(map[string]int64, len(aclNames))
+	aclInfos := make(map[string]*api.NetworkACL, len(aclNames))
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, aclName := range aclNames {
+			aclID, aclInfo, err := tx.GetNetworkACL(ctx, aclProjectName, aclName)
+			if err != nil {
+				return fmt.Errorf("Failed loading ACL %q: %w", aclName, err)
+			}
+
+			aclIDs[aclName] = aclID
+			aclInfos[aclName] = aclInfo
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bridgeCounters := make(map[string]map[string]firewallDrivers.ACLRuleCounters)
+	ovnCounters := &ovnRuleCounters{}
+	result := make(map[string]*api.NetworkACLState, len(aclNames))
+
+	for _, aclName := range aclNames {
+		aclNets := make(map[string]NetworkACLUsage)
+		err = NetworkUsage(ctx, s, aclProjectName, []string{aclName}, aclNets)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting ACL %q network usage: %w", aclName, err)
+		}
+
+		aclID := aclIDs[aclName]
+		aclInfo := aclInfos[aclName]
+		networks := []api.NetworkACLStateNetwork{}
+
+		for _, netName := range slices.Sorted(maps.Keys(aclNets)) {
+			aclNet := aclNets[netName]
+
+			var counters map[string]firewallDrivers.ACLRuleCounters
+
+			switch aclNet.Type {
+			case "bridge":
+				// Bridge networks only apply the ACLs set on the network itself.
+				if !slices.Contains(shared.SplitNTrimSpace(aclNet.Config["security.acls"], ",", -1, true), aclName) {
+					continue
+				}
+
+				// The xtables driver does not support counters and stopped networks have no rules.
+				if s.Firewall.String() == "xtables" || !shared.PathExists("/sys/class/net/"+aclNet.Name) {
+					continue
+				}
+
+				var found bool
+				counters, found = bridgeCounters[aclNet.Name]
+				if !found {
+					counters, err = s.Firewall.NetworkACLRuleCounters(aclNet.Name)
+					if err != nil {
+						return nil, err
+					}
+
+					bridgeCounters[aclNet.Name] = counters
+				}
+
+			case "ovn":
+				loaded, err := ovnCounters.load(s)
+				if err != nil {
+					return nil, err
+				}
+
+				if !loaded {
+					continue
+				}
+
+				counters, err = ovnCounters.networkCounters(aclID, aclNet.ID)
+				if err != nil {
+					return nil, fmt.Errorf("Failed getting ACL %q rule counters for network %q: %w", aclName, aclNet.Name, err)
+				}
+			}
+
+			network := api.NetworkACLStateNetwork{
+				Name:     aclNet.Name,
+				Location: s.ServerName,
+			}
+
+			network.Ingress, network.Egress = ruleCountersToAPI(aclID, aclInfo, counters)
+			networks = append(networks, network)
+		}
+
+		nicUsages, err := ovnNICUsages(ctx, s, aclProjectName, aclName, aclNets)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting ACL %q instance NIC usage: %w", aclName, err)
+		}
+
+		nics := []api.NetworkACLStateNIC{}
+
+		for _, nicUsage := range nicUsages {
+			loaded, err := ovnCounters.load(s)
+			if err != nil {
+				return nil, err
+			}
+
+			if !loaded {
+				break
+			}
+
+			portName := OVNIntSwitchInstancePortName(nicUsage.network.ID, nicUsage.instanceUUID, nicUsage.name)
+			counters, err := ovnCounters.nicCounters(aclID, nicUsage.network.ID, portName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed getting ACL %q rule counters for NIC %q of instance %q: %w", aclName, nicUsage.name, nicUsage.instance, err)
+			}
+
+			// Skip the NICs of stopped instances.
+			if counters == nil {
+				continue
+			}
+
+			nic := api.NetworkACLStateNIC{
+				Project:  nicUsage.project,
+				Instance: nicUsage.instance,
+				Name:     nicUsage.name,
+				Network:  nicUsage.network.Name,
+				Location: s.ServerName,
+			}
+
+			nic.Ingress, nic.Egress = ruleCountersToAPI(aclID, aclInfo, counters)
+			nics = append(nics, nic)
+		}
+
+		result[aclName] = &api.NetworkACLState{
+			Networks: networks,
+			NICs:     nics,
+		}
+	}
+
+	return result, nil
+}
@@ -3,8 +3,10 @@ package acl
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
 	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/shared"
@@ -17,23 +19,82 @@ func FirewallApplyACLRules(ctx context.Context, s *state.State, aclProjectName s
 	var rejectRules []firewallDrivers.ACLRule
 	var allowRules []firewallDrivers.ACLRule
 
+	// Load the address sets that can be referenced by the rules.
+	addressSets := make(map[string][]string)
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectAddressSets, err := dbCluster.GetNetworkAddressSets(ctx, tx.Tx(), aclProjectName)
+		if err != nil {
+			return err
+		}
+
+		for _, addressSet := range projectAddressSets {
+			addressSets[addressSet.Row.Name] = addressSet.Row.Addresses
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network address sets for network %q: %w", aclNet.Name, err)
+	}
+
+	// The xtables driver does not support address sets, so their addresses are used in the rules instead.
+	inlineAddressSets := s.Firewall.String() == "xtables"
+	var usedAddressSets []string
+	var fqdnSets []firewallDrivers.AddressSet
+	var fqdns []string
+
 	// convertACLRules converts the ACL rules to Firewall ACL rules.
-	convertACLRules := func(direction string, logPrefix string, rules ...api.NetworkACLRule) error {
+	convertACLRules := func(aclID int64, direction string, logPrefix string, rules ...api.NetworkACLRule) error {
 		for ruleIndex, rule := range rules {
 			if rule.State == "disabled" {
 				continue
 			}
 
+			if inlineAddressSets {
+				var ok bool
+				rule.Source, ok = firewallRuleInlineAddressSets(rule.Source, rule.Protocol, addressSets)
+				if !ok {
+					continue // Skip rules whose source currently has no usable addresses.
+				}
+			}
+
+			// Replace any FQDN subjects with their currently resolved addresses or the address sets holding them.
+			destination, destinationSets, ok := firewallRuleDestination(rule, inlineAddressSets)
+			if !ok {
+				continue // Skip allow rules whose destination currently has no usable addresses.
+			}
+
+			for _, set := range destinationSets {
+				if !slices.ContainsFunc(fqdnSets, func(addressSet firewallDrivers.AddressSet) bool { return addressSet.Name == set.Name }) {
+					fqdnSets = append(fqdnSets, set)
+				}
+			}
+
+			if inlineAddressSets {
+				destination, ok = firewallRuleInlineAddressSets(destination, rule.Protocol, addressSets)
+				if !ok {
+					continue // Skip rules whose destination currently has no usable addresses.
+				}
+			} else {
+				for _, subject := range append(shared.SplitNTrimSpace(rule.Source, ",", -1, true), shared.SplitNTrimSpace(rule.Destination, ",", -1, true)...) {
+					name, isAddressSet := dbCluster.NetworkAddressSetSubject(subject)
+					if isAddressSet && !slices.Contains(usedAddressSets, name) {
+						usedAddressSets = append(usedAddressSets, name)
+					}
+				}
+			}
+
 			firewallACLRule := firewallDrivers.ACLRule{
 				Direction:       direction,
 				Action:          rule.Action,
 				Source:          rule.Source,
-				Destination:     rule.Destination,
+				Destination:     destination,
 				Protocol:        rule.Protocol,
 				SourcePort:      rule.SourcePort,
 				DestinationPort: rule.DestinationPort,
 				ICMPType:        rule.ICMPType,
 				ICMPCode:        rule.ICMPCode,
+				Identifier:      ruleCountersID(aclID, direction, ruleIndex),
 			}
 
 			if rule.State == "logged" {
@@ -61,12 +122,13 @@ func FirewallApplyACLRules(ctx context.Context, s *state.State, aclProjectName s
 
 	// Load ACLs specified by network.
 	for _, aclName := range shared.SplitNTrimSpace(aclNet.Config["security.acls"], ",", -1, true) {
+		var aclID int64
 		var aclInfo *api.NetworkACL
 
-		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			var err error
 
-			_, aclInfo, err = tx.GetNetworkACL(ctx, aclProjectName, aclName)
+			aclID, aclInfo, err = tx.GetNetworkACL(ctx, aclProjectName, aclName)
 
 			return err
 		})
@@ -74,15 +136,17 @@ func FirewallApplyACLRules(ctx context.Context, s *state.State, aclProjectName s
 			return fmt.Errorf("Failed loading ACL %q for network %q: %w", aclName, aclNet.Name, err)
 		}
 
-		err = convertACLRules("ingress", logPrefix, aclInfo.Ingress...)
+		err = convertACLRules(aclID, "ingress", logPrefix, aclInfo.Ingress...)
 		if err != nil {
 			return fmt.Errorf("Failed converting ACL %q ingress rules for network %q: %w", aclInfo.Name, aclNet.Name, err)
 		}
 
-		err = convertACLRules("egress", logPrefix, aclInfo.Egress...)
+		err = convertACLRules(aclID, "egress", logPrefix, aclInfo.Egress...)
 		if err != nil {
 			return fmt.Errorf("Failed converting ACL %q egress rules for network %q: %w", aclInfo.Name, aclNet.Name, err)
 		}
+
+		fqdns = append(fqdns, ruleFQDNs(aclInfo)...)
 	}
 
 	var rules = make([]firewallDrivers.ACLRule, 0, len(dropRules)+len(rejectRules)+len(allowRules)+2)
@@ -108,7 +172,35 @@ func FirewallApplyACLRules(ctx context.Context, s *state.State, aclProjectName s
 		LogName:   logPrefix + "-ingress",
 	})
 
-	return s.Firewall.NetworkApplyACLRules(aclNet.Name, rules)
+	// Apply the address sets used by the rules before the rules referencing them.
+	if len(usedAddressSets) > 0 || len(fqdnSets) > 0 {
+		sets := make([]firewallDrivers.AddressSet, 0, len(usedAddressSets)+len(fqdnSets))
+		for _, name := range usedAddressSets {
+			addresses, found := addressSets[name]
+			if !found {
+				return fmt.Errorf("Network address set %q not found", name)
+			}
+
+			sets = append(sets, firewallDrivers.AddressSet{Name: name, Addresses: addresses})
+		}
+
+		sets = append(sets, fqdnSets...)
+
+		err = s.Firewall.NetworkApplyAddressSets(aclNet.Name, sets)
+		if err != nil {
+			return fmt.Errorf("Failed applying network address sets for network %q: %w", aclNet.Name, err)
+		}
+	}
+
+	err = s.Firewall.NetworkApplyACLRules(aclNet.Name, rules)
+	if err != nil {
+		return err
+	}
+
+	// Resolve the DNS names used in the rules in the background if they haven't been resolved yet.
+	fqdnResolveAsync(s, fqdns, false)
+
+	return nil
 }
 
 // firewallACLDefaults returns the action and logging mode to use for the specified direction's default rule.
@@ -0,0 +1,569 @@
+package acl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+	"github.com/canonical/lxd/lxd/network/openvswitch"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// ruleSubjectFQDNPrefix is the prefix of rule subjects that refer to a DNS name rather than to IP addresses.
+const ruleSubjectFQDNPrefix = "fqdn:"
+
+// FQDNRefreshInterval is how often the addresses of FQDN rule subjects are checked for expiry.
+// It is also the minimum time resolved addresses are cached for, regardless of the TTL of their DNS records.
+const FQDNRefreshInterval = 30 * time.Second
+
+// fqdnMaxTTL is the maximum time resolved addresses are cached for, regardless of the TTL of their DNS records.
+const fqdnMaxTTL = time.Hour
+
+// fqdnResolveTimeout is the timeout of each DNS query used to resolve FQDN rule subjects.
+const fqdnResolveTimeout = 5 * time.Second
+
+// fqdnFailClosedAddresses are the addresses used for DNS names that haven't been resolved yet in the rules that
+// must fail closed.
+var fqdnFailClosedAddresses = []string{"0.0.0.0/0", "::/0"}
+
+// fqdnCacheEntry holds the resolved addresses of an FQDN rule subject and the error of its last resolution.
+type fqdnCacheEntry struct {
+	addresses []net.IP
+	expiry    time.Time
+	err       error
+}
+
+var fqdnCache = make(map[string]*fqdnCacheEntry)
+var fqdnCacheMu sync.Mutex
+
+// fqdnRefreshMu prevents concurrent refreshes of the FQDN subjects.
+var fqdnRefreshMu sync.Mutex
+
+// fqdnResolver resolves a DNS name into its addresses and the lowest TTL of the records returned.
+// It is a variable so that it can be replaced in tests.
+var fqdnResolver = fqdnResolve
+
+// fqdnSet identifies the address set holding the addresses of a DNS name used in rule destinations.
+// The addresses of a DNS name are unknown until it is first resolved, so drop and reject rules use a separate
+// fail closed address set which matches all addresses until then, while allow rules match no addresses.
+type fqdnSet struct {
+	name       string
+	failClosed bool
+}
+
+// ruleSubjectFQDN returns the normalised DNS name of an FQDN rule subject and whether the subject is one.
+func ruleSubjectFQDN(subject string) (string, bool) {
+	name, found := strings.CutPrefix(subject, ruleSubjectFQDNPrefix)
+	if !found {
+		return "", false
+	}
+
+	return strings.ToLower(strings.TrimSuffix(name, ".")), true
+}
+
+// ruleFQDNSets returns the address sets of the DNS names used in the destination subjects of the egress rules of
+// an ACL.
+func ruleFQDNSets(info *api.NetworkACL) []fqdnSet {
+	var sets []fqdnSet
+
+	for _, rule := range info.Egress {
+		for _, subject := range shared.SplitNTrimSpace(rule.Destination, ",", -1, true) {
+			name, isFQDN := ruleSubjectFQDN(subject)
+			set := fqdnSet{name: name, failClosed: rule.Action != "allow"}
+			if isFQDN && !slices.Contains(sets, set) {
+				sets = append(sets, set)
+			}
+		}
+	}
+
+	return sets
+}
+
+// ruleFQDNs returns the DNS names used in the destination subjects of the egress rules of an ACL.
+func ruleFQDNs(info *api.NetworkACL) []string {
+	var names []string
+
+	for _, set := range ruleFQDNSets(info) {
+		if !slices.Contains(names, set.name) {
+			names = append(names, set.name)
+		}
+	}
+
+	return names
+}
+
+// fqdnResolve queries the nameservers configured on the host for the A and AAAA records of a DNS name.
+func fqdnResolve(ctx context.Context, name string) ([]net.IP, time.Duration, error) {
+	config, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+	if err != nil {
+		return nil, 0, fmt.Errorf("Failed loading DNS client configuration: %w", err)
+	}
+
+	if len(config.Servers) == 0 {
+		return nil, 0, errors.New("No nameservers configured")
+	}
+
+	client := &dns.Client{Timeout: fqdnResolveTimeout}
+
+	var addresses []net.IP
+	ttl := fqdnMaxTTL
+
+	for _, queryType := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		msg := &dns.Msg{}
+		msg.SetQuestion(dns.Fqdn(name), queryType)
+
+		var reply *dns.Msg
+
+		// Try each nameserver in turn until one replies.
+		for _, server := range config.Servers {
+			reply, _, err = client.ExchangeContext(ctx, msg, net.JoinHostPort(server, config.Port))
+			if err == nil {
+				break
+			}
+		}
+
+		if err != nil {
+			return nil, 0, fmt.Errorf("Failed querying %s records: %w", dns.TypeToString[queryType], err)
+		}
+
+		if reply.Rcode != dns.RcodeSuccess {
+			return nil, 0, fmt.Errorf("Failed querying %s records: %s", dns.TypeToString[queryType], dns.RcodeToString[reply.Rcode])
+		}
+
+		// The answer also contains the records of any CNAME chain leading to the addresses.
+		for _, rr := range reply.Answer {
+			switch record := rr.(type) {
+			case *dns.A:
+				addresses = append(addresses, record.A)
+			case *dns.AAAA:
+				addresses = append(addresses, record.AAAA)
+			}
+
+			ttl = min(ttl, time.Duration(rr.Header().Ttl)*time.Second)
+		}
+	}
+
+	if len(addresses) == 0 {
+		return nil, 0, errors.New("No addresses found")
+	}
+
+	return addresses, ttl, nil
+}
+
+// fqdnAddresses returns the addresses of the address set of a DNS name. The DNS name is never resolved here, so
+// only the addresses from its last successful resolution are used.
+func fqdnAddresses(set fqdnSet) []string {
+	fqdnCacheMu.Lock()
+	defer fqdnCacheMu.Unlock()
+
+	entry := fqdnCache[set.name]
+	if entry == nil || len(entry.addresses) == 0 {
+		if set.failClosed {
+			return fqdnFailClosedAddresses
+		}
+
+		return []string{}
+	}
+
+	addresses := make([]string, 0, len(entry.addresses))
+	for _, ip := range entry.addresses {
+		addresses = append(addresses, ip.String())
+	}
+
+	return addresses
+}
+
+// fqdnResolveAsync resolves the DNS names that haven't been resolved yet in the background and applies their
+// addresses to the networks using them, so that applying ACL rules never waits for DNS queries.
+// If updateOVN is true the OVN address sets are updated too.
+func fqdnResolveAsync(s *state.State, names []string, updateOVN bool) {
+	fqdnCacheMu.Lock()
+	unresolved := slices.ContainsFunc(names, func(name string) bool { return fqdnCache[name] == nil })
+	fqdnCacheMu.Unlock()
+
+	if !unresolved {
+		return
+	}
+
+	go func() {
+		err := FQDNRefresh(context.Background(), s, updateOVN)
+		if err != nil {
+			logger.Warn("Failed resolving network ACL FQDN subjects", logger.Ctx{"err": err})
+		}
+	}()
+}
+
+// fqdnUpdate resolves a DNS name and caches its addresses for the TTL of its records, within the allowed bounds.
+// If resolution fails the previously resolved addresses are kept and resolution is retried on the next refresh.
+// Returns the addresses of the DNS name, whether they changed and the resolution error.
+func fqdnUpdate(ctx context.Context, name string) ([]net.IP, bool, error) {
+	addresses, ttl, err := fqdnResolver(ctx, name)
+
+	fqdnCacheMu.Lock()
+	defer fqdnCacheMu.Unlock()
+
+	entry := fqdnCache[name]
+	if entry == nil {
+		entry = &fqdnCacheEntry{}
+		fqdnCache[name] = entry
+	}
+
+	entry.err = err
+
+	if err != nil {
+		logger.Warn("Failed resolving network ACL FQDN subject", logger.Ctx{"fqdn": name, "err": err})
+		entry.expiry = time.Now().Add(FQDNRefreshInterval)
+
+		return entry.addresses, false, err
+	}
+
+	// Sort and deduplicate the addresses so that changes can be detected regardless of the record order.
+	slices.SortFunc(addresses, func(a net.IP, b net.IP) int { return bytes.Compare(a.To16(), b.To16()) })
+	addresses = slices.CompactFunc(addresses, net.IP.Equal)
+
+	changed := !slices.EqualFunc(entry.addresses, addresses, net.IP.Equal)
+	entry.addresses = addresses
+	entry.expiry = time.Now().Add(min(max(ttl, FQDNRefreshInterval), fqdnMaxTTL))
+
+	return addresses, changed, nil
+}
+
+// fqdnAddressSetName returns the name of the firewall address set holding the addresses of a DNS name.
+// The DNS name is hashed as it can be longer than allowed in set names. The underscore avoids conflicts with
+// network address sets whose names cannot contain one.
+func fqdnAddressSetName(set fqdnSet) string {
+	hash := sha256.Sum256([]byte(set.name))
+	name := fmt.Sprintf("fqdn_%x", hash[:8])
+	if set.failClosed {
+		name += "_deny"
+	}
+
+	return name
+}
+
+// firewallRuleDestination returns the destination of a rule with its FQDN subjects replaced by references to the
+// address sets holding the addresses of the DNS names, along with those address sets. If inline is true, the
+// FQDN subjects are replaced by the addresses instead, only including the ones of the IP families that can be
+// used with the rule's protocol and source.
+// Returns false if the rule has a destination but none of its subjects have usable addresses, in which case the
+// rule cannot match any traffic and should be skipped. This only happens for allow rules, as the DNS names of drop
+// and reject rules match all addresses until they are resolved.
+func firewallRuleDestination(rule api.NetworkACLRule, inline bool) (string, []firewallDrivers.AddressSet, bool) {
+	if rule.Destination == "" {
+		return "", nil, true
+	}
+
+	allowIPv4 := rule.Protocol != "icmp6"
+	allowIPv6 := rule.Protocol != "icmp4"
+
+	// The source and destination of a rule must use the same IP families.
+	if rule.Source != "" {
+		var srcHasIPv4, srcHasIPv6 bool
+		for _, subject := range shared.SplitNTrimSpace(rule.Source, ",", -1, true) {
+			firstIP, _, _ := strings.Cut(subject, "-")
+			ip := net.ParseIP(firstIP)
+			if ip == nil {
+				ip, _, _ = net.ParseCIDR(subject)
+			}
+
+			if ip != nil && ip.To4() != nil {
+				srcHasIPv4 = true
+			} else if ip != nil {
+				srcHasIPv6 = true
+			}
+		}
+
+		if srcHasIPv4 || srcHasIPv6 {
+			allowIPv4 = allowIPv4 && srcHasIPv4
+			allowIPv6 = allowIPv6 && srcHasIPv6
+		}
+	}
+
+	subjects := []string{}
+	var sets []firewallDrivers.AddressSet
+	for _, subject := range shared.SplitNTrimSpace(rule.Destination, ",", -1, true) {
+		name, isFQDN := ruleSubjectFQDN(subject)
+		if !isFQDN {
+			subjects = append(subjects, subject)
+			continue
+		}
+
+		set := fqdnSet{name: name, failClosed: rule.Action != "allow"}
+
+		if !inline {
+			setName := fqdnAddressSetName(set)
+			subjects = append(subjects, "$"+setName)
+			sets = append(sets, firewallDrivers.AddressSet{Name: setName, Addresses: fqdnAddresses(set)})
+			continue
+		}
+
+		for _, address := range fqdnAddresses(set) {
+			ip, _, err := net.ParseCIDR(address)
+			if err != nil {
+				ip = net.ParseIP(address)
+			}
+
+			if (ip.To4() != nil && allowIPv4) || (ip.To4() == nil && allowIPv6) {
+				subjects = append(subjects, address)
+			}
+		}
+	}
+
+	if len(subjects) == 0 {
+		return "", nil, false
+	}
+
+	return strings.Join(subjects, ","), sets, true
+}
+
+// ovnACLFQDNAddressSetPrefix returns the prefix of the address sets holding the addresses of a DNS name used in
+// the rules of the ACL with the specified port group. The DNS name is hashed as OVN names cannot contain "-".
+func ovnACLFQDNAddressSetPrefix(portGroupName openvswitch.OVNPortGroup, set fqdnSet) openvswitch.OVNAddressSet {
+	return openvswitch.OVNAddressSet(fmt.Sprintf("%s_%s", portGroupName, fqdnAddressSetName(set)))
+}
+
+// ovnACLFQDNAddressSetApply sets the addresses of the address sets used for a DNS name in the rules of an ACL.
+func ovnACLFQDNAddressSetApply(client *openvswitch.OVN, portGroupName openvswitch.OVNPortGroup, set fqdnSet) error {
+	ipNets, err := addressSetIPNets(fqdnAddresses(set))
+	if err != nil {
+		return err
+	}
+
+	return client.AddressSetReplace(ovnACLFQDNAddressSetPrefix(portGroupName, set), ipNets...)
+}
+
+// ovnACLFQDNAddressSetsDeleteUnused deletes the DNS name address sets of the ACL with the specified port group,
+// except for the ones in keepSets.
+func ovnACLFQDNAddressSetsDeleteUnused(client *openvswitch.OVN, portGroupName openvswitch.OVNPortGroup, keepSets []fqdnSet) error {
+	addressSets, err := client.AddressSetListByPrefix(string(portGroupName) + "_fqdn_")
+	if err != nil {
+		return err
+	}
+
+	keepAddressSets := make([]openvswitch.OVNAddressSet, 0, len(keepSets))
+	for _, set := range keepSets {
+		keepAddressSets = append(keepAddressSets, ovnACLFQDNAddressSetPrefix(portGroupName, set))
+	}
+
+	for _, addressSet := range addressSets {
+		if slices.Contains(keepAddressSets, addressSet) {
+			continue
+		}
+
+		err = client.AddressSetDelete(addressSet)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fqdnWarningUpdate raises a warning on an ACL if any of the specified DNS names failed to resolve on the local
+// member, or resolves it otherwise.
+func fqdnWarningUpdate(ctx context.Context, s *state.State, projectName string, aclID int64, names []string) error {
+	var failures []string
+
+	fqdnCacheMu.Lock()
+	for _, name := range names {
+		entry := fqdnCache[name]
+		if entry != nil && entry.err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, entry.err))
+		}
+	}
+
+	fqdnCacheMu.Unlock()
+
+	if len(failures) == 0 {
+		return warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, projectName, warningtype.NetworkACLFQDNResolutionFailure, entity.TypeNetworkACL, int(aclID))
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarningLocalNode(ctx, projectName, entity.TypeNetworkACL, int(aclID), warningtype.NetworkACLFQDNResolutionFailure, strings.Join(failures, "; "))
+	})
+}
+
+// FQDNRefresh resolves the FQDN subjects used in network ACL rules whose cached addresses have expired and applies
+// any changes to the networks using those ACLs. A warning is raised on the ACLs using DNS names that fail to
+// resolve. Bridge networks are updated on the local member only. The OVN address sets are shared by all cluster
+// members and so are only updated when updateOVN is true.
+func FQDNRefresh(ctx context.Context, s *state.State, updateOVN bool) error {
+	fqdnRefreshMu.Lock()
+	defer fqdnRefreshMu.Unlock()
+
+	type aclFQDNs struct {
+		projectName string
+		id          int64
+		name        string
+		sets        []fqdnSet
+	}
+
+	var acls []aclFQDNs
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectACLNames, err := tx.GetNetworkACLsAllProjects(ctx)
+		if err != nil {
+			return err
+		}
+
+		for projectName, aclNames := range projectACLNames {
+			for _, aclName := range aclNames {
+				id, info, err := tx.GetNetworkACL(ctx, projectName, aclName)
+				if err != nil {
+					return err
+				}
+
+				sets := ruleFQDNSets(info)
+				if len(sets) > 0 {
+					acls = append(acls, aclFQDNs{projectName: projectName, id: id, name: aclName, sets: sets})
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network ACLs: %w", err)
+	}
+
+	// Forget the DNS names no longer used by any ACL and find the ones that need resolving.
+	used := make(map[string]struct{})
+	for _, aclInfo := range acls {
+		for _, set := range aclInfo.sets {
+			used[set.name] = struct{}{}
+		}
+	}
+
+	expired := make(map[string]struct{})
+
+	fqdnCacheMu.Lock()
+	for name := range fqdnCache {
+		_, found := used[name]
+		if !found {
+			delete(fqdnCache, name)
+		}
+	}
+
+	for name := range used {
+		entry := fqdnCache[name]
+		if entry == nil || !time.Now().Before(entry.expiry) {
+			expired[name] = struct{}{}
+		}
+	}
+
+	fqdnCacheMu.Unlock()
+
+	if len(expired) == 0 {
+		return nil
+	}
+
+	changed := make(map[string]bool, len(expired))
+	for name := range expired {
+		_, changed[name], _ = fqdnUpdate(ctx, name)
+	}
+
+	var ovnClient *openvswitch.OVN
+
+	for _, aclInfo := range acls {
+		var aclChanged, aclRefreshed bool
+		var names []string
+		var refreshedSets []fqdnSet
+		for _, set := range aclInfo.sets {
+			_, found := expired[set.name]
+			if found {
+				refreshedSets = append(refreshedSets, set)
+			}
+
+			if !slices.Contains(names, set.name) {
+				names = append(names, set.name)
+			}
+
+			aclRefreshed = aclRefreshed || found
+			aclChanged = aclChanged || changed[set.name]
+		}
+
+		if !aclRefreshed {
+			continue
+		}
+
+		err = fqdnWarningUpdate(ctx, s, aclInfo.projectName, aclInfo.id, names)
+		if err != nil {
+			logger.Warn("Failed updating ACL FQDN resolution warning", logger.Ctx{"project": aclInfo.projectName, "networkACL": aclInfo.name, "err": err})
+		}
+
+		aclNets := map[string]NetworkACLUsage{}
+		err = NetworkUsage(ctx, s, aclInfo.projectName, []string{aclInfo.name}, aclNets)
+		if err != nil {
+			logger.Warn("Failed getting ACL network usage", logger.Ctx{"project": aclInfo.projectName, "networkACL": aclInfo.name, "err": err})
+			continue
+		}
+
+		usedByOVN := false
+		for _, aclNet := range aclNets {
+			if aclNet.Type == "ovn" {
+				usedByOVN = true
+				continue
+			}
+
+			// Only update bridge networks when the addresses changed and the network is running on this member.
+			if aclNet.Type != "bridge" || !aclChanged || !shared.PathExists("/sys/class/net/"+aclNet.Name) {
+				continue
+			}
+
+			// The xtables driver does not support address sets, so the addresses are part of the ACL rules.
+			if s.Firewall.String() == "xtables" {
+				err = FirewallApplyACLRules(ctx, s, aclInfo.projectName, aclNet)
+			} else {
+				sets := make([]firewallDrivers.AddressSet, 0, len(refreshedSets))
+				for _, set := range refreshedSets {
+					sets = append(sets, firewallDrivers.AddressSet{Name: fqdnAddressSetName(set), Addresses: fqdnAddresses(set)})
+				}
+
+				err = s.Firewall.NetworkApplyAddressSets(aclNet.Name, sets)
+			}
+
+			if err != nil {
+				logger.Warn("Failed applying ACL FQDN addresses", logger.Ctx{"project": aclInfo.projectName, "networkACL": aclInfo.name, "network": aclNet.Name, "err": err})
+			}
+		}
+
+		// Always update the OVN address sets of refreshed names as the address sets may have been set using
+		// addresses resolved by another cluster member.
+		if !usedByOVN || !updateOVN {
+			continue
+		}
+
+		if ovnClient == nil {
+			ovnClient, err = openvswitch.NewOVN(s.GlobalConfig.NetworkOVNNorthboundConnection(), s.GlobalConfig.NetworkOVNSSL)
+			if err != nil {
+				return fmt.Errorf("Failed getting OVN client: %w", err)
+			}
+		}
+
+		for _, set := range refreshedSets {
+			err = ovnACLFQDNAddressSetApply(ovnClient, OVNACLPortGroupName(aclInfo.id), set)
+			if err != nil {
+				logger.Warn("Failed updating ACL FQDN address set", logger.Ctx{"project": aclInfo.projectName, "networkACL": aclInfo.name, "fqdn": set.name, "err": err})
+			}
+		}
+	}
+
+	return nil
+}
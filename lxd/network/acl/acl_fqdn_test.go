@@ -0,0 +1,265 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// setFQDNResolver replaces the FQDN resolver and empties the FQDN cache for the duration of a test.
+func setFQDNResolver(t *testing.T, resolver func(ctx context.Context, name string) ([]net.IP, time.Duration, error)) {
+	oldResolver := fqdnResolver
+	fqdnResolver = resolver
+	fqdnCache = make(map[string]*fqdnCacheEntry)
+
+	t.Cleanup(func() {
+		fqdnResolver = oldResolver
+		fqdnCache = make(map[string]*fqdnCacheEntry)
+	})
+}
+
+func Test_fqdnUpdate(t *testing.T) {
+	var addresses []net.IP
+	var err error
+
+	setFQDNResolver(t, func(ctx context.Context, name string) ([]net.IP, time.Duration, error) {
+		return addresses, time.Second, err
+	})
+
+	addresses = []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.2"), net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.2")}
+
+	result, changed, _ := fqdnUpdate(context.Background(), "example.com")
+	if !changed {
+		t.Error("Expected first resolution to be a change")
+	}
+
+	if len(result) != 3 || !result[0].Equal(net.ParseIP("192.0.2.1")) || !result[2].Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("Expected sorted and deduplicated addresses, got %v", result)
+	}
+
+	// The TTL is raised to the minimum cache time.
+	expiry := fqdnCache["example.com"].expiry
+	if expiry.Before(time.Now().Add(FQDNRefreshInterval - time.Second)) {
+		t.Errorf("Expected expiry to be at least %v away, got %v", FQDNRefreshInterval, time.Until(expiry))
+	}
+
+	// Same addresses in a different order are not a change.
+	addresses = []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.2")}
+
+	_, changed, _ = fqdnUpdate(context.Background(), "example.com")
+	if changed {
+		t.Error("Expected reordered addresses not to be a change")
+	}
+
+	// Failures keep the previous addresses.
+	err = errors.New("Resolution failed")
+
+	result, changed, resolveErr := fqdnUpdate(context.Background(), "example.com")
+	if changed || len(result) != 3 {
+		t.Errorf("Expected previous addresses to be kept on failure, got %v (changed %v)", result, changed)
+	}
+
+	if resolveErr == nil || fqdnCache["example.com"].err == nil {
+		t.Error("Expected resolution failure to be returned and recorded")
+	}
+
+	err = nil
+	addresses = []net.IP{net.ParseIP("192.0.2.3")}
+
+	_, changed, _ = fqdnUpdate(context.Background(), "example.com")
+	if !changed {
+		t.Error("Expected new addresses to be a change")
+	}
+
+	if fqdnCache["example.com"].err != nil {
+		t.Error("Expected successful resolution to clear the recorded failure")
+	}
+}
+
+func Test_fqdnAddresses(t *testing.T) {
+	setFQDNResolver(t, func(ctx context.Context, name string) ([]net.IP, time.Duration, error) {
+		if name == "example.com" {
+			return []net.IP{net.ParseIP("192.0.2.1")}, time.Minute, nil
+		}
+
+		return nil, 0, errors.New("Not found")
+	})
+
+	// DNS names are never resolved when getting their addresses.
+	if len(fqdnAddresses(fqdnSet{name: "example.com"})) != 0 {
+		t.Error("Expected unresolved DNS name to have no addresses")
+	}
+
+	if !slices.Equal(fqdnAddresses(fqdnSet{name: "example.com", failClosed: true}), fqdnFailClosedAddresses) {
+		t.Error("Expected unresolved DNS name to match all addresses when failing closed")
+	}
+
+	_, _, _ = fqdnUpdate(context.Background(), "example.com")
+	_, _, _ = fqdnUpdate(context.Background(), "missing.example.com")
+
+	for _, failClosed := range []bool{false, true} {
+		addresses := fqdnAddresses(fqdnSet{name: "example.com", failClosed: failClosed})
+		if !slices.Equal(addresses, []string{"192.0.2.1"}) {
+			t.Errorf("Expected resolved addresses, got %v", addresses)
+		}
+	}
+
+	// Failing to resolve a DNS name keeps it failing closed.
+	if !slices.Equal(fqdnAddresses(fqdnSet{name: "missing.example.com", failClosed: true}), fqdnFailClosedAddresses) {
+		t.Error("Expected DNS name failing to resolve to match all addresses when failing closed")
+	}
+}
+
+func Test_firewallRuleDestination(t *testing.T) {
+	setFQDNResolver(t, func(ctx context.Context, name string) ([]net.IP, time.Duration, error) {
+		switch name {
+		case "example.com":
+			return []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")}, time.Minute, nil
+		case "v6.example.com":
+			return []net.IP{net.ParseIP("2001:db8::2")}, time.Minute, nil
+		}
+
+		return nil, 0, errors.New("Not found")
+	})
+
+	for _, name := range []string{"example.com", "v6.example.com", "missing.example.com"} {
+		_, _, _ = fqdnUpdate(context.Background(), name)
+	}
+
+	tests := []struct {
+		name     string
+		rule     api.NetworkACLRule
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "No destination",
+			rule:     api.NetworkACLRule{Action: "allow"},
+			expected: "",
+			ok:       true,
+		},
+		{
+			name:     "Static destination",
+			rule:     api.NetworkACLRule{Action: "allow", Destination: "198.51.100.0/24"},
+			expected: "198.51.100.0/24",
+			ok:       true,
+		},
+		{
+			name:     "FQDN and static destination",
+			rule:     api.NetworkACLRule{Action: "allow", Destination: "198.51.100.0/24,fqdn:Example.com."},
+			expected: "198.51.100.0/24,192.0.2.1,2001:db8::1",
+			ok:       true,
+		},
+		{
+			name:     "FQDN with ICMPv4",
+			rule:     api.NetworkACLRule{Action: "allow", Destination: "fqdn:example.com", Protocol: "icmp4"},
+			expected: "192.0.2.1",
+			ok:       true,
+		},
+		{
+			name:     "FQDN with IPv6 source",
+			rule:     api.NetworkACLRule{Action: "allow", Source: "2001:db8:1::/64", Destination: "fqdn:example.com"},
+			expected: "2001:db8::1",
+			ok:       true,
+		},
+		{
+			name: "FQDN without addresses of source family",
+			rule: api.NetworkACLRule{Action: "allow", Source: "192.0.2.0/24", Destination: "fqdn:v6.example.com"},
+			ok:   false,
+		},
+		{
+			name: "Unresolvable FQDN in allow rule",
+			rule: api.NetworkACLRule{Action: "allow", Destination: "fqdn:missing.example.com"},
+			ok:   false,
+		},
+		{
+			name:     "Unresolvable FQDN in drop rule",
+			rule:     api.NetworkACLRule{Action: "drop", Destination: "fqdn:missing.example.com"},
+			expected: "0.0.0.0/0,::/0",
+			ok:       true,
+		},
+		{
+			name:     "Unresolvable FQDN in reject rule with IPv4 source",
+			rule:     api.NetworkACLRule{Action: "reject", Source: "192.0.2.0/24", Destination: "fqdn:missing.example.com"},
+			expected: "0.0.0.0/0",
+			ok:       true,
+		},
+		{
+			name:     "Unresolved FQDN in drop rule",
+			rule:     api.NetworkACLRule{Action: "drop", Destination: "fqdn:unresolved.example.com"},
+			expected: "0.0.0.0/0,::/0",
+			ok:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, sets, ok := firewallRuleDestination(tt.rule, true)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %v, got %v", tt.ok, ok)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+
+			if len(sets) > 0 {
+				t.Errorf("Expected no address sets when inlining addresses, got %v", sets)
+			}
+		})
+	}
+
+	// Without inlining, the FQDN subjects reference address sets which are separate for allow and deny rules.
+	allowSet := fqdnAddressSetName(fqdnSet{name: "missing.example.com"})
+	denySet := fqdnAddressSetName(fqdnSet{name: "missing.example.com", failClosed: true})
+	if allowSet == denySet {
+		t.Fatal("Expected allow and deny rules to use different address sets")
+	}
+
+	result, sets, ok := firewallRuleDestination(api.NetworkACLRule{Action: "allow", Destination: "198.51.100.0/24,fqdn:missing.example.com"}, false)
+	if !ok || result != "198.51.100.0/24,$"+allowSet || len(sets) != 1 || sets[0].Name != allowSet || len(sets[0].Addresses) != 0 {
+		t.Errorf("Unexpected allow rule destination %q with address sets %v", result, sets)
+	}
+
+	result, sets, ok = firewallRuleDestination(api.NetworkACLRule{Action: "drop", Destination: "fqdn:missing.example.com"}, false)
+	if !ok || result != "$"+denySet || len(sets) != 1 || !slices.Equal(sets[0].Addresses, fqdnFailClosedAddresses) {
+		t.Errorf("Unexpected drop rule destination %q with address sets %v", result, sets)
+	}
+}
+
+func Test_ovnRuleSubjectToOVNACLMatch_FQDN(t *testing.T) {
+	portGroupName := OVNACLPortGroupName(1)
+	addrSetPrefix := ovnACLFQDNAddressSetPrefix(portGroupName, fqdnSet{name: "example.com"})
+
+	result, _, _, err := ovnRuleSubjectToOVNACLMatch("dst", portGroupName, false, nil, nil, nil, "192.0.2.1", "fqdn:example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "ip4.dst == 192.0.2.1 || ip6.dst == $" + string(addrSetPrefix) + "_ip6 || ip4.dst == $" + string(addrSetPrefix) + "_ip4"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	if ovnACLFQDNAddressSetPrefix(portGroupName, fqdnSet{name: "example.org"}) == addrSetPrefix {
+		t.Error("Expected different DNS names to use different address sets")
+	}
+
+	// Drop and reject rules use the fail closed address sets.
+	denyAddrSetPrefix := ovnACLFQDNAddressSetPrefix(portGroupName, fqdnSet{name: "example.com", failClosed: true})
+
+	result, _, _, err = ovnRuleSubjectToOVNACLMatch("dst", portGroupName, true, nil, nil, nil, "fqdn:example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = "ip6.dst == $" + string(denyAddrSetPrefix) + "_ip6 || ip4.dst == $" + string(denyAddrSetPrefix) + "_ip4"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
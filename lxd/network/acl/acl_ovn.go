@@ -79,6 +79,16 @@ func OVNIntSwitchRouterPortName(networkID int64) openvswitch.OVNSwitchPort {
 	return openvswitch.OVNSwitchPort(fmt.Sprintf("%s-lsp-router", OVNIntSwitchName(networkID)))
 }
 
+// OVNIntSwitchInstancePortPrefix returns OVN logical internal switch instance port name prefix for a Network ID.
+func OVNIntSwitchInstancePortPrefix(networkID int64) string {
+	return OVNNetworkPrefix(networkID) + "-instance"
+}
+
+// OVNIntSwitchInstancePortName returns OVN logical internal switch port name of an instance NIC.
+func OVNIntSwitchInstancePortName(networkID int64, instanceUUID string, deviceName string) openvswitch.OVNSwitchPort {
+	return openvswitch.OVNSwitchPort(fmt.Sprintf("%s-%s-%s", OVNIntSwitchInstancePortPrefix(networkID), instanceUUID, deviceName))
+}
+
 // OVNEnsureACLs ensures that the requested aclNames exist as OVN port groups (creates & applies ACL rules if not),
 // If reapplyRules is true then the current ACL rules in the database are applied to the existing port groups
 // rather than just new ones. Any ACLs referenced in the requested ACLs rules are also created as empty OVN port
@@ -92,13 +102,24 @@ func OVNEnsureACLs(ctx context.Context, s *state.State, l logger.Logger, client
 
 	var err error
 	var projectID int64
+	addressSets := make(map[string]cluster.NetworkAddressSet)
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		projectID, err = cluster.GetProjectID(ctx, tx.Tx(), aclProjectName)
 		if err != nil {
 			return fmt.Errorf("Failed getting project ID for project %q: %w", aclProjectName, err)
 		}
 
-		return err
+		// Load the address sets that can be referenced by the ACL rules.
+		projectAddressSets, err := cluster.GetNetworkAddressSets(ctx, tx.Tx(), aclProjectName)
+		if err != nil {
+			return fmt.Errorf("Failed getting network address sets for project %q: %w", aclProjectName, err)
+		}
+
+		for _, addressSet := range projectAddressSets {
+			addressSets[addressSet.Row.Name] = addressSet
+		}
+
+		return nil
 	})
 	if err != nil {
 		return nil, err
@@ -263,10 +284,12 @@ func OVNEnsureACLs(ctx context.Context, s *state.State, l logger.Logger, client
 		}
 
 		// Now apply our ACL rules to port group (and any per-ACL-per-network port groups needed).
-		err = ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs)
+		err = ovnApplyToPortGroup(ctx, l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, addressSets, aclNets, peerTargetNetIDs)
 		if err != nil {
 			return nil, fmt.Errorf("Failed applying ACL rules to port group %q for security ACL %q setup: %w", portGroupName, aclStatus.name, err)
 		}
+
+		fqdnResolveAsync(s, ruleFQDNs(aclStatus.aclInfo), true)
 	}
 
 	// Create any missing per-ACL-per-network port groups for existing ACL port groups, and apply the ACL rules
@@ -293,10 +316,12 @@ func OVNEnsureACLs(ctx context.Context, s *state.State, l logger.Logger, client
 		if aclStatus.aclInfo != nil {
 			l.Debug("Applying ACL rules to OVN port group", logger.Ctx{"networkACL": aclStatus.name, "portGroup": portGroupName})
 
-			err := ovnApplyToPortGroup(l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, aclNets, peerTargetNetIDs)
+			err := ovnApplyToPortGroup(ctx, l, client, aclStatus.aclInfo, portGroupName, aclNameIDs, addressSets, aclNets, peerTargetNetIDs)
 			if err != nil {
 				return nil, fmt.Errorf("Failed applying ACL rules to port group %q for security ACL %q setup: %w", portGroupName, aclStatus.name, err)
 			}
+
+			fqdnResolveAsync(s, ruleFQDNs(aclStatus.aclInfo), true)
 		}
 	}
 
@@ -318,6 +343,16 @@ func ovnAddReferencedACLs(info *api.NetworkACL, referencedACLNames map[string]st
 				continue // Skip special reserved subjects that are not ACL names.
 			}
 
+			_, isFQDN := ruleSubjectFQDN(subject)
+			if isFQDN {
+				continue // Skip DNS name subjects.
+			}
+
+			_, isAddressSet := cluster.NetworkAddressSetSubject(subject)
+			if isAddressSet {
+				continue // Skip network address set subjects.
+			}
+
 			if validate.IsNetworkAddressCIDR(subject) == nil || validate.IsNetworkRange(subject) == nil {
 				continue // Skip if the subject is an IP CIDR or IP range.
 			}
@@ -338,7 +373,7 @@ func ovnAddReferencedACLs(info *api.NetworkACL, referencedACLNames map[string]st
 }
 
 // ovnApplyToPortGroup applies the rules in the specified ACL to the specified port group.
-func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.NetworkACL, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, aclNets map[string]NetworkACLUsage, peerTargetNetIDs map[db.NetworkPeer]int64) error {
+func ovnApplyToPortGroup(ctx context.Context, l logger.Logger, client *openvswitch.OVN, aclInfo *api.NetworkACL, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, addressSets map[string]cluster.NetworkAddressSet, aclNets map[string]NetworkACLUsage, peerTargetNetIDs map[db.NetworkPeer]int64) error {
 	// Create slice for port group rules that has the capacity for ingress and egress rules, plus default rule.
 	portGroupRules := make([]openvswitch.OVNACLRule, 0, len(aclInfo.Ingress)+len(aclInfo.Egress)+1)
 	networkRules := make([]openvswitch.OVNACLRule, 0)
@@ -351,11 +386,13 @@ func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.
 				continue
 			}
 
-			ovnACLRule, networkSpecific, networkPeers, err := ovnRuleCriteriaToOVNACLRule(direction, &rule, portGroupName, aclNameIDs, peerTargetNetIDs)
+			ovnACLRule, networkSpecific, networkPeers, err := ovnRuleCriteriaToOVNACLRule(direction, &rule, portGroupName, aclNameIDs, addressSets, peerTargetNetIDs)
 			if err != nil {
 				return err
 			}
 
+			ovnACLRule.Identifier = ruleCountersID(aclNameIDs[aclInfo.Name], direction, ruleIndex)
+
 			if rule.State == "logged" {
 				ovnACLRule.Log = true
 				ovnACLRule.LogName = fmt.Sprintf("%s-%s-%d", portGroupName, direction, ruleIndex)
@@ -406,12 +443,43 @@ func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.
 		}
 	}
 
+	// Populate the address sets of any network address sets used in the rules before the rules referencing them
+	// are added.
+	for _, name := range ruleAddressSets(aclInfo) {
+		addressSet, found := addressSets[name]
+		if !found {
+			return fmt.Errorf("Network address set %q not found", name)
+		}
+
+		err = ovnNetworkAddressSetApply(client, addressSet)
+		if err != nil {
+			return fmt.Errorf("Failed applying ACL %q network address set %q: %w", aclInfo.Name, name, err)
+		}
+	}
+
+	// Populate the address sets of any DNS names used in the rules before the rules referencing them are added.
+	// Only the cached addresses are used, the DNS names that haven't been resolved yet are resolved in the
+	// background once the rules are applied.
+	fqdnSets := ruleFQDNSets(aclInfo)
+	for _, set := range fqdnSets {
+		err = ovnACLFQDNAddressSetApply(client, portGroupName, set)
+		if err != nil {
+			return fmt.Errorf("Failed applying ACL %q address set for FQDN %q: %w", aclInfo.Name, set.name, err)
+		}
+	}
+
 	// Clear all existing ACL rules from port group then add the new rules to the port group.
 	err = client.PortGroupSetACLRules(portGroupName, nil, portGroupRules...)
 	if err != nil {
 		return fmt.Errorf("Failed applying ACL %q rules to port group %q: %w", aclInfo.Name, portGroupName, err)
 	}
 
+	// Remove the address sets of DNS names no longer used in the rules.
+	err = ovnACLFQDNAddressSetsDeleteUnused(client, portGroupName, fqdnSets)
+	if err != nil {
+		return fmt.Errorf("Failed removing unused ACL %q FQDN address sets: %w", aclInfo.Name, err)
+	}
+
 	// Now apply the network specific rules to all networks requested (even if networkRules is empty).
 	for _, aclNet := range aclNets {
 		netPortGroupName := OVNACLNetworkPortGroupName(aclNameIDs[aclInfo.Name], aclNet.ID)
@@ -434,7 +502,7 @@ func ovnApplyToPortGroup(l logger.Logger, client *openvswitch.OVN, aclInfo *api.
 
 // ovnRuleCriteriaToOVNACLRule converts a LXD ACL rule into an OVNACLRule for an OVN port group or network.
 // Returns a bool indicating if any of the rule subjects are network specific.
-func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64) (openvswitch.OVNACLRule, bool, []db.NetworkPeer, error) {
+func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, portGroupName openvswitch.OVNPortGroup, aclNameIDs map[string]int64, addressSets map[string]cluster.NetworkAddressSet, peerTargetNetIDs map[db.NetworkPeer]int64) (openvswitch.OVNACLRule, bool, []db.NetworkPeer, error) {
 	networkSpecific := false
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
 	portGroupRule := openvswitch.OVNACLRule{
@@ -468,7 +536,7 @@ func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, por
 
 	// Add subject filters.
 	if rule.Source != "" {
-		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("src", aclNameIDs, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Source, ",", -1, false)...)
+		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("src", portGroupName, rule.Action != "allow", aclNameIDs, addressSets, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Source, ",", -1, false)...)
 		if err != nil {
 			return openvswitch.OVNACLRule{}, false, nil, err
 		}
@@ -482,7 +550,7 @@ func ovnRuleCriteriaToOVNACLRule(direction string, rule *api.NetworkACLRule, por
 	}
 
 	if rule.Destination != "" {
-		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("dst", aclNameIDs, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Destination, ",", -1, false)...)
+		match, netSpecificMatch, networkPeers, err := ovnRuleSubjectToOVNACLMatch("dst", portGroupName, rule.Action != "allow", aclNameIDs, addressSets, peerTargetNetIDs, shared.SplitNTrimSpace(rule.Destination, ",", -1, false)...)
 		if err != nil {
 			return openvswitch.OVNACLRule{}, false, nil, err
 		}
@@ -542,14 +610,41 @@ func ovnRulePortToOVNACLMatch(protocol string, direction string, portCriteria ..
 }
 
 // ovnRuleSubjectToOVNACLMatch converts direction (src/dst) and subject criteria list into an OVN match statement.
+// The port group name of the ACL the rule belongs to is used to reference the address sets of DNS name subjects,
+// using the fail closed address sets if failClosed is true.
 // Returns a bool indicating if any of the subjects are network specific.
-func ovnRuleSubjectToOVNACLMatch(direction string, aclNameIDs map[string]int64, peerTargetNetIDs map[db.NetworkPeer]int64, subjectCriteria ...string) (string, bool, []db.NetworkPeer, error) {
+func ovnRuleSubjectToOVNACLMatch(direction string, portGroupName openvswitch.OVNPortGroup, failClosed bool, aclNameIDs map[string]int64, addressSets map[string]cluster.NetworkAddressSet, peerTargetNetIDs map[db.NetworkPeer]int64, subjectCriteria ...string) (string, bool, []db.NetworkPeer, error) {
 	fieldParts := make([]string, 0, len(subjectCriteria))
 	networkSpecific := false
 	networkPeersNeeded := make([]db.NetworkPeer, 0)
 
 	// For each criterion check if value looks like an IP range or IP CIDR, and if not use it as an ACL name.
 	for _, subjectCriterion := range subjectCriteria {
+		name, isFQDN := ruleSubjectFQDN(subjectCriterion)
+		if isFQDN {
+			// Subject is a DNS name. Convert to address set criteria.
+			addrSetPrefix := ovnACLFQDNAddressSetPrefix(portGroupName, fqdnSet{name: name, failClosed: failClosed})
+
+			fieldParts = append(fieldParts, fmt.Sprintf("ip6.%s == $%s_ip6 || ip4.%s == $%s_ip4", direction, addrSetPrefix, direction, addrSetPrefix))
+
+			continue
+		}
+
+		addressSetName, isAddressSet := cluster.NetworkAddressSetSubject(subjectCriterion)
+		if isAddressSet {
+			// Subject is a network address set. Convert to address set criteria.
+			addressSet, found := addressSets[addressSetName]
+			if !found {
+				return "", false, nil, fmt.Errorf("Cannot find network address set %q", addressSetName)
+			}
+
+			addrSetPrefix := ovnNetworkAddressSetPrefix(addressSet.Row.ID)
+
+			fieldParts = append(fieldParts, fmt.Sprintf("ip6.%s == $%s_ip6 || ip4.%s == $%s_ip4", direction, addrSetPrefix, direction, addrSetPrefix))
+
+			continue
+		}
+
 		if validate.IsNetworkRange(subjectCriterion) == nil {
 			firstIP, lastIP, found := strings.Cut(subjectCriterion, "-")
 			if !found {
@@ -981,6 +1076,14 @@ func OVNPortGroupDeleteIfUnused(ctx context.Context, s *state.State, l logger.Lo
 		if err != nil {
 			return fmt.Errorf("Failed deleting unused OVN port groups: %w", err)
 		}
+
+		// Remove the address sets of DNS names used in the rules of the removed ACL port groups.
+		for _, removePortGroup := range removePortGroups {
+			err = ovnACLFQDNAddressSetsDeleteUnused(client, removePortGroup, nil)
+			if err != nil {
+				return fmt.Errorf("Failed deleting unused OVN FQDN address sets: %w", err)
+			}
+		}
 	}
 
 	return nil
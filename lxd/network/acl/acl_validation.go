@@ -3,8 +3,11 @@ package acl
 import (
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/validate"
 )
 
@@ -28,3 +31,42 @@ func ValidName(name string) error {
 
 	return nil
 }
+
+// ValidAddressSetName checks the network address set name is valid.
+func ValidAddressSetName(name string) error {
+	if name == "" {
+		return errors.New("Name is required")
+	}
+
+	// Ensures the name can be used in the names of the firewall sets derived from the address set.
+	err := validate.IsHostname(name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidAddressSet checks the modifiable fields of a network address set are valid.
+func ValidAddressSet(put api.NetworkAddressSetPut) error {
+	for i, address := range put.Addresses {
+		if validate.IsNetworkAddress(address) != nil && validate.IsNetworkAddressCIDR(address) != nil {
+			return fmt.Errorf("Invalid address %q, must be an IP address or CIDR subnet", address)
+		}
+
+		if slices.Contains(put.Addresses[:i], address) {
+			return fmt.Errorf("Duplicate address %q", address)
+		}
+	}
+
+	for k := range put.Config {
+		// User keys are not validated.
+		if config.IsUserConfig(k) {
+			continue
+		}
+
+		return fmt.Errorf("Invalid config option %q", k)
+	}
+
+	return nil
+}
@@ -284,12 +284,19 @@ func (d *common) validateRule(ctx context.Context, direction ruleDirection, rule
 	}
 
 	var acls map[string]int64
+	var addressSets []dbCluster.NetworkAddressSet
 
 	err := d.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
 		// Get map of ACL names to DB IDs (used for generating OVN port group names).
 		acls, err = tx.GetNetworkACLIDsByNames(ctx, d.Project())
+		if err != nil {
+			return err
+		}
+
+		// Get the address sets that can be referenced by the rule.
+		addressSets, err = dbCluster.GetNetworkAddressSets(ctx, tx.Tx(), d.Project())
 
 		return err
 	})
@@ -305,12 +312,17 @@ func (d *common) validateRule(ctx context.Context, direction ruleDirection, rule
 		validSubjectNames = append(validSubjectNames, aclName)
 	}
 
+	validAddressSetNames := make([]string, 0, len(addressSets))
+	for _, addressSet := range addressSets {
+		validAddressSetNames = append(validAddressSetNames, addressSet.Row.Name)
+	}
+
 	var srcHasName, srcHasIPv4, srcHasIPv6 bool
 	var dstHasName, dstHasIPv4, dstHasIPv6 bool
 
 	// Validate Source field.
 	if rule.Source != "" {
-		srcHasName, srcHasIPv4, srcHasIPv6, err = d.validateRuleSubjects("Source", direction, shared.SplitNTrimSpace(rule.Source, ",", -1, false), validSubjectNames)
+		srcHasName, srcHasIPv4, srcHasIPv6, err = d.validateRuleSubjects("Source", direction, shared.SplitNTrimSpace(rule.Source, ",", -1, false), validSubjectNames, validAddressSetNames)
 		if err != nil {
 			return fmt.Errorf("Invalid Source: %w", err)
 		}
@@ -318,7 +330,7 @@ func (d *common) validateRule(ctx context.Context, direction ruleDirection, rule
 
 	// Validate Destination field.
 	if rule.Destination != "" {
-		dstHasName, dstHasIPv4, dstHasIPv6, err = d.validateRuleSubjects("Destination", direction, shared.SplitNTrimSpace(rule.Destination, ",", -1, false), validSubjectNames)
+		dstHasName, dstHasIPv4, dstHasIPv6, err = d.validateRuleSubjects("Destination", direction, shared.SplitNTrimSpace(rule.Destination, ",", -1, false), validSubjectNames, validAddressSetNames)
 		if err != nil {
 			return fmt.Errorf("Invalid Destination: %w", err)
 		}
@@ -435,7 +447,7 @@ func (d *common) validateRule(ctx context.Context, direction ruleDirection, rule
 // validateRuleSubjects checks that the source or destination subjects for a rule are valid.
 // Accepts a validSubjectNames list of valid ACL or special classifier names.
 // Returns whether the subjects include names, IPv4 and IPv6 addresses respectively.
-func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection, subjects []string, validSubjectNames []string) (hasName bool, hasIPv4 bool, hasIPv6 bool, err error) {
+func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection, subjects []string, validSubjectNames []string, validAddressSetNames []string) (hasName bool, hasIPv4 bool, hasIPv6 bool, err error) {
 	// Check if named subjects are allowed in field/direction combination.
 	allowSubjectNames := (fieldName == "Source" && direction == ruleDirectionIngress) || (fieldName == "Destination" && direction == ruleDirectionEgress)
 
@@ -503,6 +515,32 @@ func (d *common) validateRuleSubjects(fieldName string, direction ruleDirection,
 			}
 		}
 
+		// Check if it is a DNS name. These resolve to addresses of either IP family so are treated like names.
+		name, isFQDN := ruleSubjectFQDN(subject)
+		if isFQDN {
+			if fieldName != "Destination" || direction != ruleDirectionEgress {
+				return 0, fmt.Errorf("FQDN subjects not allowed in %q for %q rules", fieldName, direction)
+			}
+
+			err := validate.IsDomainName(name)
+			if err != nil {
+				return 0, fmt.Errorf("Invalid FQDN subject %q: %w", subject, err)
+			}
+
+			return 0, nil // Found valid subject.
+		}
+
+		// Check if it is a reference to an address set. These can contain addresses of either IP family so are
+		// treated like names, but are allowed in any field/direction combination.
+		addressSetName, isAddressSet := dbCluster.NetworkAddressSetSubject(subject)
+		if isAddressSet {
+			if !slices.Contains(validAddressSetNames, addressSetName) {
+				return 0, fmt.Errorf("Network address set %q not found", addressSetName)
+			}
+
+			return 0, nil // Found valid subject.
+		}
+
 		// Check if it is one of the valid subject names.
 		for _, n := range validSubjectNames {
 			if subject == n {
@@ -838,3 +876,64 @@ func (d *common) GetLog(ctx context.Context, clientType request.ClientType) (str
 
 	return strings.Join(logEntries, "\n") + "\n", nil
 }
+
+// GetState returns the rule counters of the ACL for each network using it across the cluster.
+func (d *common) GetState(ctx context.Context, clientType request.ClientType) (*api.NetworkACLState, error) {
+	counters, err := RuleCounters(ctx, d.state, d.projectName, d.info.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	aclState := counters[d.info.Name]
+
+	// Aggregates the counters from the rest of the cluster.
+	if clientType == request.ClientTypeNormal {
+		// Setup notifier to reach the rest of the cluster.
+		notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return nil, err
+		}
+
+		mu := sync.Mutex{}
+		err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+			memberState, err := client.UseProject(d.projectName).GetNetworkACLState(d.info.Name)
+			if err != nil {
+				return err
+			}
+
+			// Prevent concurrent writes to the networks and NICs slices.
+			mu.Lock()
+			defer mu.Unlock()
+
+			aclState.Networks = append(aclState.Networks, memberState.Networks...)
+			aclState.NICs = append(aclState.NICs, memberState.NICs...)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(aclState.Networks, func(i, j int) bool {
+		if aclState.Networks[i].Name != aclState.Networks[j].Name {
+			return aclState.Networks[i].Name < aclState.Networks[j].Name
+		}
+
+		return aclState.Networks[i].Location < aclState.Networks[j].Location
+	})
+
+	sort.Slice(aclState.NICs, func(i, j int) bool {
+		if aclState.NICs[i].Project != aclState.NICs[j].Project {
+			return aclState.NICs[i].Project < aclState.NICs[j].Project
+		}
+
+		if aclState.NICs[i].Instance != aclState.NICs[j].Instance {
+			return aclState.NICs[i].Instance < aclState.NICs[j].Instance
+		}
+
+		return aclState.NICs[i].Name < aclState.NICs[j].Name
+	})
+
+	return aclState, nil
+}
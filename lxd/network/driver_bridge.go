@@ -29,6 +29,7 @@ import (
 	"github.com/canonical/lxd/lxd/dnsmasq"
 	"github.com/canonical/lxd/lxd/dnsmasq/dhcpalloc"
 	firewallDrivers "github.com/canonical/lxd/lxd/firewall/drivers"
+	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/ip"
 	"github.com/canonical/lxd/lxd/network/acl"
@@ -57,6 +58,16 @@ var forkdnsServersLock sync.Mutex
 // Default MTU for bridge interface.
 const bridgeMTUDefault = 1500
 
+// Default MTU and UDP port for the WireGuard interface of a bridge in WireGuard mode.
+const bridgeWireguardMTUDefault = 1420
+const bridgeWireguardPortDefault = 51820
+
+// Node-specific volatile key holding the WireGuard public key of each cluster member.
+const bridgeVolatileWireguardPublicKey = "volatile.wireguard.public_key"
+
+// Node-specific volatile key holding the index of each cluster member's subnet within the WireGuard overlay subnet.
+const bridgeVolatileWireguardIndex = "volatile.wireguard.index"
+
 // bridge represents a LXD bridge network.
 type bridge struct {
 	common
@@ -71,6 +82,7 @@ func (n *bridge) DBType() db.NetworkType {
 func (n *bridge) Info() Info {
 	info := n.common.Info()
 	info.AddressForwards = true
+	info.LoadBalancers = true
 
 	return info
 }
@@ -110,6 +122,11 @@ func (n *bridge) FillConfig(config map[string]string) error {
 		if config["ipv4.nat"] == "" {
 			config["ipv4.nat"] = "true"
 		}
+	} else if config["bridge.mode"] == "wireguard" {
+		// We enable NAT by default as the overlay subnet is usually private.
+		if config["ipv4.nat"] == "" {
+			config["ipv4.nat"] = "true"
+		}
 	} else {
 		if config["ipv4.address"] == "" {
 			config["ipv4.address"] = "auto"
@@ -234,6 +251,56 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  shortdesc: Peer session hold time
 		//  scope: global
 
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.peers.NAME.max_prefixes)
+		// When the peer advertises more prefixes than this limit, the session is closed.
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: (no limit)
+		//  required: no
+		//  shortdesc: Maximum number of prefixes accepted from the peer
+		//  scope: global
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.import)
+		// When enabled, the routes learned from the BGP peers are installed in the routing table set in `bgp.import.table` or `bgp.import.vrf`.
+		// ---
+		//  type: bool
+		//  condition: BGP server
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to install routes learned from BGP peers
+		//  scope: global
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.import.table)
+		// Specify `main` or the numeric ID of a routing table.
+		// This option cannot be set together with `bgp.import.vrf`.
+		// ---
+		//  type: string
+		//  condition: BGP route import
+		//  defaultdesc: `main`
+		//  shortdesc: Routing table in which learned routes are installed
+		//  scope: global
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.import.vrf)
+		// The learned routes are installed in the routing table of the VRF interface.
+		// This option cannot be set together with `bgp.import.table`.
+		// ---
+		//  type: string
+		//  condition: BGP route import
+		//  shortdesc: VRF in which learned routes are installed
+		//  scope: global
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.import.prefixes)
+		// Specify a comma-separated list of CIDR subnets.
+		// Only the learned routes for prefixes within these subnets are installed.
+		// This option must be set when route import is enabled.
+		// Default routes and routes within the subnets of the host or of LXD managed networks are never installed.
+		// ---
+		//  type: string
+		//  condition: BGP route import
+		//  required: yes
+		//  shortdesc: Prefixes of the learned routes to install
+		//  scope: global
+
 		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.ipv4.nexthop)
 		//
 		// ---
@@ -252,6 +319,42 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  shortdesc: Override the IPv6 next-hop for advertised prefixes
 		//  scope: local
 		"bgp.ipv6.nexthop": validate.Optional(validate.IsNetworkAddressV6),
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.communities)
+		// Specify a comma-separated list of standard communities in the `ASN:VALUE` format, or the well-known `no-export`, `no-advertise` and `blackhole` communities.
+		// The communities are attached to the prefixes advertised for the network, its forwards, load balancers and instance NICs.
+		// ---
+		//  type: string
+		//  condition: BGP server
+		//  shortdesc: BGP communities attached to advertised prefixes
+		//  scope: global
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.large_communities)
+		// Specify a comma-separated list of large communities in the `GLOBAL:LOCAL1:LOCAL2` format.
+		// ---
+		//  type: string
+		//  condition: BGP server
+		//  shortdesc: BGP large communities attached to advertised prefixes
+		//  scope: global
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.med)
+		//
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: (not sent)
+		//  shortdesc: BGP multi-exit discriminator of advertised prefixes
+		//  scope: global
+
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bgp.as_path_prepend)
+		// Specify how many times the local ASN is prepended to the AS path (up to 16).
+		// ---
+		//  type: integer
+		//  condition: BGP server
+		//  defaultdesc: `0`
+		//  shortdesc: AS path prepending of advertised prefixes
+		//  scope: global
+
 		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bridge.driver)
 		// Possible values are `native` and `openvswitch`.
 		// ---
@@ -288,18 +391,18 @@ func (n *bridge) Validate(config map[string]string) error {
 		// The default value varies depending on whether the bridge uses a tunnel or a fan setup.
 		// ---
 		//  type: integer
-		//  defaultdesc: `1400` when tunnels are configured, otherwise `1500` if `bridge.mode=standard` or `1450` if `bridge.mode=fan`
+		//  defaultdesc: `1400` when tunnels are configured, otherwise `1500` if `bridge.mode=standard`, `1450` if `bridge.mode=fan` or `1420` if `bridge.mode=wireguard`
 		//  shortdesc: Bridge MTU
 		//  scope: global
 		"bridge.mtu": validate.Optional(validate.IsNetworkMTU),
 		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=bridge.mode)
-		// Possible values are `standard` and `fan`.
+		// Possible values are `standard`, `fan` and `wireguard`.
 		// ---
 		//  type: string
 		//  defaultdesc: `standard`
 		//  shortdesc: Bridge operation mode
 		//  scope: global
-		"bridge.mode": validate.Optional(validate.IsOneOf("standard", "fan")),
+		"bridge.mode": validate.Optional(validate.IsOneOf("standard", "fan", "wireguard")),
 		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=fan.overlay_subnet)
 		// Use CIDR notation.
 		// ---
@@ -649,12 +752,36 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  scope: global
 		"security.acls.default.egress.logged": validate.Optional(validate.IsBool),
 
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=wireguard.overlay_subnet)
+		// Use CIDR notation.
+		//
+		// Each cluster member gets a `/24` subnet of the overlay subnet, allocated when the network first starts on it.
+		// ---
+		//  type: string
+		//  condition: WireGuard mode
+		//  shortdesc: Subnet routed between cluster members over WireGuard
+		//  scope: global
+		"wireguard.overlay_subnet": validate.Optional(validate.IsNetworkV4),
+		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=wireguard.port)
+		//
+		// ---
+		//  type: integer
+		//  condition: WireGuard mode
+		//  defaultdesc: `51820`
+		//  shortdesc: UDP port used for the WireGuard tunnels
+		//  scope: global
+		"wireguard.port": validate.Optional(validate.IsNetworkPort),
+
 		// lxdmeta:generate(entities=network-bridge; group=network-conf; key=user.*)
 		//
 		// ---
 		//  type: string
 		//  shortdesc: User-provided free-form key/value pairs
 		//  scope: global
+
+		// Volatile keys populated automatically as needed.
+		bridgeVolatileWireguardPublicKey: validate.Optional(validate.IsAny),
+		bridgeVolatileWireguardIndex:     validate.Optional(validate.IsUint32),
 	}
 
 	// Add dynamic validation rules.
@@ -756,6 +883,7 @@ func (n *bridge) Validate(config map[string]string) error {
 	}
 
 	maps.Copy(rules, bgpRules)
+	maps.Copy(rules, BGPPathAttributesValidationRules())
 
 	// Validate the configuration.
 	err = n.validate(config, rules)
@@ -784,22 +912,47 @@ func (n *bridge) Validate(config map[string]string) error {
 		return errors.New("Network name too long to use with the FAN (must be 11 characters or less)")
 	}
 
+	// Validate network name and overlay subnet when used in WireGuard mode.
+	if bridgeMode == "wireguard" {
+		if len(n.name) > 12 {
+			return errors.New("Network name too long to use with WireGuard (must be 12 characters or less)")
+		}
+
+		if config["wireguard.overlay_subnet"] == "" {
+			return errors.New(`"wireguard.overlay_subnet" must be set when in 'wireguard' mode`)
+		}
+
+		_, overlaySubnet, err := net.ParseCIDR(config["wireguard.overlay_subnet"])
+		if err != nil {
+			return err
+		}
+
+		overlaySize, _ := overlaySubnet.Mask.Size()
+		if overlaySize > 23 {
+			return errors.New(`"wireguard.overlay_subnet" must be /23 or larger`)
+		}
+	}
+
 	bridgeModeOptions := []string{"ipv4.dhcp.expiry", "ipv4.firewall", "ipv4.nat", "ipv4.nat.order"}
 	for k, v := range config {
 		key := k
 		// Bridge mode checks
-		if bridgeMode == "fan" && strings.HasPrefix(key, "ipv4.") && !slices.Contains(bridgeModeOptions, key) && v != "" {
-			return errors.New("IPv4 configuration may not be set when in 'fan' mode")
+		if slices.Contains([]string{"fan", "wireguard"}, bridgeMode) && strings.HasPrefix(key, "ipv4.") && !slices.Contains(bridgeModeOptions, key) && v != "" {
+			return fmt.Errorf("IPv4 configuration may not be set when in '%s' mode", bridgeMode)
 		}
 
-		if bridgeMode == "fan" && strings.HasPrefix(key, "ipv6.") && v != "" {
-			return errors.New("IPv6 configuration may not be set when in 'fan' mode")
+		if slices.Contains([]string{"fan", "wireguard"}, bridgeMode) && strings.HasPrefix(key, "ipv6.") && v != "" {
+			return fmt.Errorf("IPv6 configuration may not be set when in '%s' mode", bridgeMode)
 		}
 
 		if bridgeMode != "fan" && strings.HasPrefix(key, "fan.") && v != "" {
 			return errors.New("FAN configuration may only be set when in 'fan' mode")
 		}
 
+		if bridgeMode != "wireguard" && strings.HasPrefix(key, "wireguard.") && v != "" {
+			return errors.New("WireGuard configuration may only be set when in 'wireguard' mode")
+		}
+
 		// MTU checks
 		if key == "bridge.mtu" && v != "" {
 			mtu, err := strconv.ParseInt(v, 10, 64)
@@ -819,6 +972,8 @@ func (n *bridge) Validate(config map[string]string) error {
 
 			if config["bridge.mode"] == "fan" && mtu > 1450 {
 				return errors.New("Maximum MTU for a FAN bridge is 1450")
+			} else if config["bridge.mode"] == "wireguard" && mtu > 1420 {
+				return errors.New("Maximum MTU for a WireGuard bridge is 1420")
 			} else if n.hasTunnels(config) && mtu > 1400 {
 				return errors.New("Maximum MTU for a bridge with tunnels is 1400")
 			}
@@ -1144,11 +1299,12 @@ func (n *bridge) getDnsmasqArgs(bridge *ip.Bridge) ([]string, error) {
 	return dnsmasqCmd, nil
 }
 
-func (n *bridge) addDnsmasqFanArgs(args []string, address string, fanMTU uint32) ([]string, error) {
+// addDnsmasqOverlayArgs adds the dnsmasq arguments serving the local /24 subnet of an overlay (fan or WireGuard mode).
+func (n *bridge) addDnsmasqOverlayArgs(args []string, address string, mtu uint32) ([]string, error) {
 	// Parse the host subnet.
 	_, hostSubnet, err := net.ParseCIDR(address + "/24")
 	if err != nil {
-		return nil, fmt.Errorf("Failed parsing fan address: %w", err)
+		return nil, fmt.Errorf("Failed parsing overlay address: %w", err)
 	}
 
 	expiry := "1h"
@@ -1159,7 +1315,7 @@ func (n *bridge) addDnsmasqFanArgs(args []string, address string, fanMTU uint32)
 	args = append(args,
 		"--listen-address="+address,
 		"--dhcp-no-override", "--dhcp-authoritative",
-		fmt.Sprintf("--dhcp-option-force=26,%d", fanMTU),
+		fmt.Sprintf("--dhcp-option-force=26,%d", mtu),
 		"--dhcp-leasefile="+shared.VarPath("networks", n.name, "dnsmasq.leases"),
 		"--dhcp-hostsdir="+shared.VarPath("networks", n.name, "dnsmasq.hosts"),
 		"--dhcp-range", fmt.Sprintf("%s,%s,%s", dhcpalloc.GetIP(hostSubnet, 2).String(), dhcpalloc.GetIP(hostSubnet, -2).String(), expiry))
@@ -1328,6 +1484,8 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		bridge.MTU = 1400
 	} else if n.config["bridge.mode"] == "fan" {
 		bridge.MTU = 1450
+	} else if n.config["bridge.mode"] == "wireguard" {
+		bridge.MTU = bridgeWireguardMTUDefault
 	}
 
 	// Decide the MAC address of bridge interface.
@@ -1600,14 +1758,14 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
-	// Configure IPv4 firewall (includes fan).
-	if n.config["bridge.mode"] == "fan" || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"]) {
+	// Configure IPv4 firewall (includes fan and WireGuard).
+	if slices.Contains([]string{"fan", "wireguard"}, n.config["bridge.mode"]) || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"]) {
 		if n.hasDHCPv4() && n.hasIPv4Firewall() {
 			fwOpts.FeaturesV4.ICMPDHCPDNSAccess = true
 		}
 
 		// Allow forwarding.
-		if n.config["bridge.mode"] == "fan" || shared.IsTrueOrEmpty(n.config["ipv4.routing"]) {
+		if slices.Contains([]string{"fan", "wireguard"}, n.config["bridge.mode"]) || shared.IsTrueOrEmpty(n.config["ipv4.routing"]) {
 			err = util.SysctlSet("net/ipv4/ip_forward", "1")
 			if err != nil {
 				return err
@@ -1926,7 +2084,7 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 
 		// Update the dnsmasq config.
-		dnsmasqCmd, err = n.addDnsmasqFanArgs(dnsmasqCmd, address, fanMTU)
+		dnsmasqCmd, err = n.addDnsmasqOverlayArgs(dnsmasqCmd, address, fanMTU)
 		if err != nil {
 			return err
 		}
@@ -1996,6 +2154,124 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		dnsClusteredAddress, _, _ = strings.Cut(fanAddress, "/")
 	}
 
+	// Configure WireGuard.
+	if n.config["bridge.mode"] == "wireguard" {
+		wgName := n.name + "-wg"
+
+		_, err = exec.LookPath("wg")
+		if err != nil {
+			return errors.New(`WireGuard mode requires the "wg" tool`)
+		}
+
+		// Get the member index and address.
+		wgIndex, err := n.wireguardSetupIndex()
+		if err != nil {
+			return err
+		}
+
+		wgAddress, wgOverlaySubnet, err := n.wireguardAddress(wgIndex)
+		if err != nil {
+			return err
+		}
+
+		overlaySubnet = wgOverlaySubnet
+		address, _, _ := strings.Cut(wgAddress, "/")
+
+		// Load the private key and publish the public key.
+		privateKey, err := n.wireguardSetupKey()
+		if err != nil {
+			return err
+		}
+
+		// Setup the tunnel.
+		wg := &ip.Wireguard{
+			Link: ip.Link{
+				Name: wgName,
+				MTU:  bridge.MTU,
+			},
+		}
+
+		err = wg.Add()
+		if err != nil {
+			return err
+		}
+
+		revert.Add(func() { _ = wg.Delete() })
+
+		_, err = n.wireguardSyncPeers(privateKey, true)
+		if err != nil {
+			return err
+		}
+
+		// Have the next heartbeat refresh the peers.
+		wireguardPeersMembersMu.Lock()
+		delete(wireguardPeersMembers, n.id)
+		wireguardPeersMembersMu.Unlock()
+
+		err = wg.SetUp()
+		if err != nil {
+			return err
+		}
+
+		// Add the address.
+		ipAddr := &ip.Addr{
+			DevName: n.name,
+			Address: wgAddress,
+			Family:  ip.FamilyV4,
+		}
+
+		err = ipAddr.Add()
+		if err != nil {
+			return err
+		}
+
+		err = bridge.SetUp()
+		if err != nil {
+			return err
+		}
+
+		// Route the subnets of the other cluster members through the tunnel.
+		r := &ip.Route{
+			DevName: wgName,
+			Route:   overlaySubnet.String(),
+			Proto:   "static",
+			Family:  ip.FamilyV4,
+		}
+
+		err = r.Add()
+		if err != nil {
+			return err
+		}
+
+		// Update the dnsmasq config.
+		dnsmasqCmd, err = n.addDnsmasqOverlayArgs(dnsmasqCmd, address, bridge.MTU)
+		if err != nil {
+			return err
+		}
+
+		// Save the dnsmasq listen address so that firewall rules can be added later
+		ipv4Address = net.ParseIP(address)
+
+		// Configure NAT.
+		if shared.IsTrue(n.config["ipv4.nat"]) {
+			fwOpts.SNATV4 = &firewallDrivers.SNATOpts{
+				SNATAddress: nil, // Use MASQUERADE mode.
+				Subnet:      overlaySubnet,
+			}
+
+			if n.config["ipv4.nat.order"] == "after" {
+				fwOpts.SNATV4.Append = true
+			}
+		}
+
+		// Setup clustered DNS.
+		if n.state.LocalConfig.ClusterAddress() != "" {
+			dnsClustered = true
+		}
+
+		dnsClusteredAddress = address
+	}
+
 	// Configure tunnels.
 	for _, tunnel := range tunnels {
 		getConfig := func(key string) string {
@@ -2195,6 +2471,12 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Setup network load balancers.
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
 	nodeEvacuated := n.state.DB.Cluster.LocalNodeIsEvacuated()
 
 	// Setup BGP.
@@ -2217,6 +2499,14 @@ func (n *bridge) Stop() error {
 		return nil
 	}
 
+	// Stop probing load balancer targets.
+	loadBalancerHealthMonitorStop(n.id)
+
+	// Forget the WireGuard peers state.
+	wireguardPeersMembersMu.Lock()
+	delete(wireguardPeersMembers, n.id)
+	wireguardPeersMembersMu.Unlock()
+
 	// Clear BGP.
 	err := n.bgpClear(n.config)
 	if err != nil {
@@ -2329,6 +2619,14 @@ func (n *bridge) Update(newNetwork api.NetworkPut, targetNode string, clientType
 		return nil // Nothing changed.
 	}
 
+	// Check that the WireGuard member indexes already allocated fit in the new overlay subnet.
+	if newNetwork.Config["bridge.mode"] == "wireguard" && slices.Contains(changedKeys, "wireguard.overlay_subnet") {
+		err = n.wireguardValidateIndexes(newNetwork.Config["wireguard.overlay_subnet"])
+		if err != nil {
+			return err
+		}
+	}
+
 	// If the network as a whole has not had any previous creation attempts, or the node itself is still
 	// pending, then don't apply the new settings to the node, just to the database record (ready for the
 	// actual global create request to be initiated).
@@ -2455,6 +2753,35 @@ func (n *bridge) spawnForkDNS(listenAddress string) error {
 // HandleHeartbeat refreshes forkdns servers. Retrieves the IPv4 address of each cluster node (excluding ourselves)
 // for this network. It then updates the forkdns server list file if there are changes.
 func (n *bridge) HandleHeartbeat(heartbeatData *cluster.APIHeartbeat) error {
+	// Refresh the WireGuard peers as cluster members join, leave or change address, and until all the other
+	// members have published their keys. A member only generates a new key when the network is created on it,
+	// which happens either along with the network being recreated (new network ID) or with the member joining.
+	if n.config["bridge.mode"] == "wireguard" && shared.PathExists("/sys/class/net/"+n.name+"-wg") {
+		fingerprint := wireguardMembersFingerprint(heartbeatData.Members)
+
+		wireguardPeersMembersMu.Lock()
+		synced := wireguardPeersMembers[n.id] == fingerprint
+		wireguardPeersMembersMu.Unlock()
+
+		if !synced {
+			privateKey, err := os.ReadFile(shared.VarPath("networks", n.name, "wireguard.key"))
+			if err != nil {
+				return fmt.Errorf("Failed reading WireGuard private key: %w", err)
+			}
+
+			complete, err := n.wireguardSyncPeers(string(privateKey), false)
+			if err != nil {
+				return err
+			}
+
+			if complete {
+				wireguardPeersMembersMu.Lock()
+				wireguardPeersMembers[n.id] = fingerprint
+				wireguardPeersMembersMu.Unlock()
+			}
+		}
+	}
+
 	// Make sure forkdns has been setup.
 	if !shared.PathExists(shared.VarPath("networks", n.name, "forkdns.pid")) {
 		return nil
@@ -2662,89 +2989,346 @@ func (n *bridge) fanAddress(underlay *net.IPNet, overlay *net.IPNet) (cidr strin
 	return ipBytes.String() + "/" + strconv.Itoa(overlaySize), dev, ipStr, err
 }
 
-func (n *bridge) addressForSubnet(subnet *net.IPNet) (net.IP, string, error) {
-	ifaces, err := net.Interfaces()
+// wireguardAddress returns the bridge address in CIDR notation and the overlay subnet when in WireGuard mode.
+func (n *bridge) wireguardAddress(index int64) (string, *net.IPNet, error) {
+	_, overlaySubnet, err := net.ParseCIDR(n.config["wireguard.overlay_subnet"])
 	if err != nil {
-		return net.IP{}, "", err
+		return "", nil, fmt.Errorf("Failed parsing wireguard.overlay_subnet: %w", err)
 	}
 
-	for _, iface := range ifaces {
-		// Skip addresses on lo interface in case VIPs are being used on that interface that are part of
-		// the underlay subnet as is unlikely to be the actual intended underlay subnet interface.
-		if iface.Name == "lo" {
-			continue
-		}
-
-		addrs, err := iface.Addrs()
-		if err != nil {
-			continue
-		}
-
-		for _, addr := range addrs {
-			ip, _, err := net.ParseCIDR(addr.String())
-			if err != nil {
-				continue
-			}
-
-			if subnet.Contains(ip) {
-				return ip, iface.Name, nil
-			}
-		}
+	memberSubnet, err := wireguardMemberSubnet(overlaySubnet, index)
+	if err != nil {
+		return "", nil, err
 	}
 
-	return net.IP{}, "", errors.New("No address found in subnet")
+	return dhcpalloc.GetIP(memberSubnet, 1).String() + "/24", overlaySubnet, nil
 }
 
-func (n *bridge) killForkDNS() error {
-	// Check if we have a running forkdns at all
-	pidPath := shared.VarPath("networks", n.name, "forkdns.pid")
-
-	p, err := subprocess.ImportProcess(pidPath)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil
-		}
-
-		return fmt.Errorf("Could not read pid file: %s", err)
+// wireguardPort returns the UDP port used for the WireGuard tunnels.
+func (n *bridge) wireguardPort() (int, error) {
+	if n.config["wireguard.port"] == "" {
+		return bridgeWireguardPortDefault, nil
 	}
 
-	err = p.Stop()
-	if err != nil && err != subprocess.ErrNotRunning {
-		return fmt.Errorf("Cannot kill dnsmasq: %s", err)
+	port, err := strconv.Atoi(n.config["wireguard.port"])
+	if err != nil {
+		return -1, fmt.Errorf("Invalid wireguard.port %q: %w", n.config["wireguard.port"], err)
 	}
 
-	return nil
+	return port, nil
 }
 
-// updateForkdnsServersFile takes a list of node addresses and writes them atomically to
-// the forkdns.servers file ready for forkdns to notice and re-apply its config.
-func (n *bridge) updateForkdnsServersFile(addresses []string) error {
-	// We don't want to race with ourselves here
-	forkdnsServersLock.Lock()
-	defer forkdnsServersLock.Unlock()
+// wireguardSetupIndex returns the index of the local member's subnet within the WireGuard overlay subnet.
+// If the local member doesn't have one yet, the lowest index not used by another member is allocated and stored
+// in the node-specific network config.
+func (n *bridge) wireguardSetupIndex() (int64, error) {
+	if n.config[bridgeVolatileWireguardIndex] != "" {
+		index, err := strconv.ParseInt(n.config[bridgeVolatileWireguardIndex], 10, 64)
+		if err != nil {
+			return -1, fmt.Errorf("Invalid %s %q: %w", bridgeVolatileWireguardIndex, n.config[bridgeVolatileWireguardIndex], err)
+		}
 
-	permName := shared.VarPath("networks", n.name, ForkdnsServersListPath+"/"+ForkdnsServersListFile)
-	tmpName := permName + ".tmp"
+		return index, nil
+	}
 
-	// Open tmp file and truncate, using explicit permissions so the final servers.conf
-	// always ends up with mode 0644 regardless of the process umask.
-	tmpFile, err := os.OpenFile(tmpName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	_, overlaySubnet, err := net.ParseCIDR(n.config["wireguard.overlay_subnet"])
 	if err != nil {
-		return err
+		return -1, fmt.Errorf("Failed parsing wireguard.overlay_subnet: %w", err)
 	}
 
-	defer func() { _ = tmpFile.Close() }()
+	var index int64
 
-	for _, address := range addresses {
-		_, err := tmpFile.WriteString(address + "\n")
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		indexes, err := tx.GetNetworkMembersConfigValue(ctx, n.id, bridgeVolatileWireguardIndex)
+		if err != nil {
+			return fmt.Errorf("Failed getting WireGuard member indexes: %w", err)
+		}
+
+		index = wireguardAllocateIndex(indexes)
+
+		_, err = wireguardMemberSubnet(overlaySubnet, index)
 		if err != nil {
 			return err
 		}
-	}
 
-	err = tmpFile.Close()
+		config := maps.Clone(n.config)
+		config[bridgeVolatileWireguardIndex] = strconv.FormatInt(index, 10)
+
+		return tx.UpdateNetwork(ctx, n.project, n.name, n.description, config)
+	})
 	if err != nil {
-		return err
+		return -1, fmt.Errorf("Failed allocating WireGuard member index: %w", err)
+	}
+
+	n.config[bridgeVolatileWireguardIndex] = strconv.FormatInt(index, 10)
+
+	return index, nil
+}
+
+// wireguardValidateIndexes checks that the member indexes allocated by all cluster members fit in the given
+// WireGuard overlay subnet.
+func (n *bridge) wireguardValidateIndexes(overlay string) error {
+	_, overlaySubnet, err := net.ParseCIDR(overlay)
+	if err != nil {
+		return fmt.Errorf("Failed parsing wireguard.overlay_subnet: %w", err)
+	}
+
+	var indexes map[int64]string
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		indexes, err = tx.GetNetworkMembersConfigValue(ctx, n.id, bridgeVolatileWireguardIndex)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting WireGuard member indexes: %w", err)
+	}
+
+	for _, value := range indexes {
+		index, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid WireGuard member index %q: %w", value, err)
+		}
+
+		_, err = wireguardMemberSubnet(overlaySubnet, index)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// wireguardSetupKey loads the WireGuard private key of the local member, generating it if missing, and publishes
+// the matching public key in the node-specific network config so the other cluster members can use it.
+func (n *bridge) wireguardSetupKey() (string, error) {
+	keyPath := shared.VarPath("networks", n.name, "wireguard.key")
+
+	content, err := os.ReadFile(keyPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("Failed reading WireGuard private key: %w", err)
+	}
+
+	privateKey := strings.TrimSpace(string(content))
+	if privateKey == "" {
+		privateKey, err = wireguardGenerateKey()
+		if err != nil {
+			return "", err
+		}
+
+		err = os.WriteFile(keyPath, []byte(privateKey+"\n"), 0600)
+		if err != nil {
+			return "", fmt.Errorf("Failed writing WireGuard private key: %w", err)
+		}
+	}
+
+	publicKey, err := wireguardPublicKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	if n.config[bridgeVolatileWireguardPublicKey] != publicKey {
+		n.config[bridgeVolatileWireguardPublicKey] = publicKey
+
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetwork(ctx, n.project, n.name, n.description, n.config)
+		})
+		if err != nil {
+			return "", fmt.Errorf("Failed saving WireGuard public key: %w", err)
+		}
+	}
+
+	return privateKey, nil
+}
+
+// wireguardSyncPeers configures the other cluster members as peers of the WireGuard interface.
+// The configuration is only applied if it changed since the last sync, unless force is true.
+// Returns whether all the other cluster members were configured as peers.
+func (n *bridge) wireguardSyncPeers(privateKey string, force bool) (bool, error) {
+	_, overlaySubnet, err := net.ParseCIDR(n.config["wireguard.overlay_subnet"])
+	if err != nil {
+		return false, fmt.Errorf("Failed parsing wireguard.overlay_subnet: %w", err)
+	}
+
+	port, err := n.wireguardPort()
+	if err != nil {
+		return false, err
+	}
+
+	var members []db.NodeInfo
+	var publicKeys map[int64]string
+	var indexes map[int64]string
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		publicKeys, err = tx.GetNetworkMembersConfigValue(ctx, n.id, bridgeVolatileWireguardPublicKey)
+		if err != nil {
+			return fmt.Errorf("Failed getting WireGuard public keys: %w", err)
+		}
+
+		indexes, err = tx.GetNetworkMembersConfigValue(ctx, n.id, bridgeVolatileWireguardIndex)
+		if err != nil {
+			return fmt.Errorf("Failed getting WireGuard member indexes: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	complete := true
+	peers := make([]wireguardPeer, 0, len(members))
+	localMemberID := n.state.DB.Cluster.GetNodeID()
+	for _, member := range members {
+		if member.ID == localMemberID {
+			continue
+		}
+
+		// Skip members which haven't started the network yet.
+		publicKey := publicKeys[member.ID]
+		if publicKey == "" || indexes[member.ID] == "" {
+			complete = false
+			continue
+		}
+
+		host, _, err := net.SplitHostPort(member.Address)
+		if err != nil {
+			return false, fmt.Errorf("Failed parsing address of cluster member %q: %w", member.Name, err)
+		}
+
+		index, err := strconv.ParseInt(indexes[member.ID], 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("Invalid WireGuard member index of cluster member %q: %w", member.Name, err)
+		}
+
+		memberSubnet, err := wireguardMemberSubnet(overlaySubnet, index)
+		if err != nil {
+			n.logger.Warn("Excluding cluster member from WireGuard peers", logger.Ctx{"member": member.Name, "err": err})
+			complete = false
+			continue
+		}
+
+		peers = append(peers, wireguardPeer{
+			PublicKey: publicKey,
+			Endpoint:  net.JoinHostPort(host, strconv.Itoa(port)),
+			Subnet:    memberSubnet,
+		})
+	}
+
+	config := wireguardConfig(privateKey, port, peers)
+	configPath := shared.VarPath("networks", n.name, "wireguard.conf")
+
+	// If the configuration is unchanged, nothing to do.
+	if !force {
+		curConfig, err := os.ReadFile(configPath)
+		if err == nil && string(curConfig) == config {
+			return complete, nil
+		}
+	}
+
+	err = os.WriteFile(configPath, []byte(config), 0600)
+	if err != nil {
+		return false, fmt.Errorf("Failed writing WireGuard configuration: %w", err)
+	}
+
+	_, err = shared.RunCommand(context.TODO(), "wg", "syncconf", n.name+"-wg", configPath)
+	if err != nil {
+		return false, fmt.Errorf("Failed applying WireGuard configuration: %w", err)
+	}
+
+	n.logger.Info("Updated WireGuard peers", logger.Ctx{"peers": len(peers)})
+
+	return complete, nil
+}
+
+func (n *bridge) addressForSubnet(subnet *net.IPNet) (net.IP, string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return net.IP{}, "", err
+	}
+
+	for _, iface := range ifaces {
+		// Skip addresses on lo interface in case VIPs are being used on that interface that are part of
+		// the underlay subnet as is unlikely to be the actual intended underlay subnet interface.
+		if iface.Name == "lo" {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ip, _, err := net.ParseCIDR(addr.String())
+			if err != nil {
+				continue
+			}
+
+			if subnet.Contains(ip) {
+				return ip, iface.Name, nil
+			}
+		}
+	}
+
+	return net.IP{}, "", errors.New("No address found in subnet")
+}
+
+func (n *bridge) killForkDNS() error {
+	// Check if we have a running forkdns at all
+	pidPath := shared.VarPath("networks", n.name, "forkdns.pid")
+
+	p, err := subprocess.ImportProcess(pidPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("Could not read pid file: %s", err)
+	}
+
+	err = p.Stop()
+	if err != nil && err != subprocess.ErrNotRunning {
+		return fmt.Errorf("Cannot kill dnsmasq: %s", err)
+	}
+
+	return nil
+}
+
+// updateForkdnsServersFile takes a list of node addresses and writes them atomically to
+// the forkdns.servers file ready for forkdns to notice and re-apply its config.
+func (n *bridge) updateForkdnsServersFile(addresses []string) error {
+	// We don't want to race with ourselves here
+	forkdnsServersLock.Lock()
+	defer forkdnsServersLock.Unlock()
+
+	permName := shared.VarPath("networks", n.name, ForkdnsServersListPath+"/"+ForkdnsServersListFile)
+	tmpName := permName + ".tmp"
+
+	// Open tmp file and truncate, using explicit permissions so the final servers.conf
+	// always ends up with mode 0644 regardless of the process umask.
+	tmpFile, err := os.OpenFile(tmpName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tmpFile.Close() }()
+
+	for _, address := range addresses {
+		_, err := tmpFile.WriteString(address + "\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return err
 	}
 
 	// Atomically rename finished file into permanent location so forkdns can pick it up.
@@ -2758,9 +3342,9 @@ func (n *bridge) updateForkdnsServersFile(addresses []string) error {
 
 // hasIPv4Firewall indicates whether the network has IPv4 firewall enabled.
 func (n *bridge) hasIPv4Firewall() bool {
-	// IPv4 firewall is only enabled if there is a bridge ipv4.address or fan mode, and ipv4.firewall enabled.
-	// When using fan bridge.mode, there can be an empty ipv4.address, so we assume it is active.
-	if (n.config["bridge.mode"] == "fan" || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"])) && shared.IsTrueOrEmpty(n.config["ipv4.firewall"]) {
+	// IPv4 firewall is only enabled if there is a bridge ipv4.address or fan or WireGuard mode, and ipv4.firewall enabled.
+	// When using fan or WireGuard bridge.mode, there can be an empty ipv4.address, so we assume it is active.
+	if (slices.Contains([]string{"fan", "wireguard"}, n.config["bridge.mode"]) || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"])) && shared.IsTrueOrEmpty(n.config["ipv4.firewall"]) {
 		return true
 	}
 
@@ -2796,9 +3380,9 @@ func (n *bridge) DHCPv4Subnet() *net.IPNet {
 		return nil
 	}
 
-	// Fan mode. Extract DHCP subnet from fan bridge address. Only detectable once network has started.
-	// But if there is no address on the fan bridge then DHCP won't work anyway.
-	if n.config["bridge.mode"] == "fan" {
+	// Fan and WireGuard modes. Extract DHCP subnet from bridge address. Only detectable once network has started.
+	// But if there is no address on the bridge then DHCP won't work anyway.
+	if slices.Contains([]string{"fan", "wireguard"}, n.config["bridge.mode"]) {
 		iface, err := net.InterfaceByName(n.name)
 		if err != nil {
 			return nil
@@ -3135,86 +3719,179 @@ func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
 	return externalSubnets, nil
 }
 
-// forwardValidate validates the forward request.
-func (n *bridge) forwardValidate(listenAddress net.IP, forward api.NetworkForwardPut) ([]*forwardPortMap, error) {
-	err := n.checkAddressNotInOVNRange(listenAddress)
+// checkListenAddressNotInUse checks that a forward or load balancer listen address doesn't overlap with any
+// existing network external subnets, other than the subnet and SNAT address of this network.
+func (n *bridge) checkListenAddressNotInUse(listenAddressNet *net.IPNet) (bool, error) {
+	externalSubnetsInUse, err := n.getExternalSubnetInUse()
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	return n.common.forwardValidate(listenAddress, forward)
-}
-
-// ForwardCreate creates a network forward.
-func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType request.ClientType) (net.IP, error) {
-	memberSpecific := true // bridge supports per-member forwards.
-
-	// Convert listen address to subnet so we can check its valid and can be used.
-	listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
-	if err != nil {
-		return nil, fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
-	}
+	// Check the listen address subnet doesn't fall within any existing network external subnets.
+	for _, externalSubnetUser := range externalSubnetsInUse {
+		// Check if usage is from our own network.
+		if externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name {
+			// Skip checking conflict with our own network's subnet or SNAT address.
+			// But do not allow other conflict with other usage types within our own network.
+			if externalSubnetUser.usageType == subnetUsageNetwork || externalSubnetUser.usageType == subnetUsageNetworkSNAT {
+				continue
+			}
+		}
 
-	if listenAddressNet.IP.IsUnspecified() {
-		return nil, api.StatusErrorf(http.StatusNotImplemented, "Automatic listen address allocation not supported for drivers of type %q", n.netType)
+		if SubnetContains(&externalSubnetUser.subnet, listenAddressNet) || SubnetContains(listenAddressNet, &externalSubnetUser.subnet) {
+			return false, nil
+		}
 	}
 
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Check if there is an existing forward using the same listen address.
-		_, _, err := tx.GetNetworkForward(ctx, n.ID(), memberSpecific, forward.ListenAddress)
+	return true, nil
+}
 
-		return err
-	})
-	if err == nil {
-		return nil, api.StatusErrorf(http.StatusConflict, "A forward for that listen address already exists")
+// enableNICHairpinMode enables hairpin mode on the active NIC bridge ports when the first forward or load
+// balancer is added to the bridge.
+func (n *bridge) enableNICHairpinMode() error {
+	if n.config["bridge.driver"] == "openvswitch" {
+		return nil
 	}
 
-	_, err = n.forwardValidate(listenAddressNet.IP, forward.NetworkForwardPut)
-	if err != nil {
-		return nil, err
+	brNetfilterEnabled := false
+	for _, ipVersion := range []uint{4, 6} {
+		if BridgeNetfilterEnabled(ipVersion) == nil {
+			brNetfilterEnabled = true
+			break
+		}
 	}
 
-	externalSubnetsInUse, err := n.getExternalSubnetInUse()
-	if err != nil {
-		return nil, err
+	// If br_netfilter is enabled and bridge has forwards or load balancers, we enable hairpin mode on each
+	// NIC's bridge port in case any of them target the NIC and the instance attempts to connect to their
+	// listener. Without hairpin mode on the target will not be able to connect to the listener.
+	if !brNetfilterEnabled {
+		return nil
 	}
 
-	checkAddressNotInUse := func(netip *net.IPNet) (bool, error) {
-		// Check the listen address subnet doesn't fall within any existing network external subnets.
-		for _, externalSubnetUser := range externalSubnetsInUse {
-			// Check if usage is from our own network.
-			if externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name {
-				// Skip checking conflict with our own network's subnet or SNAT address.
-				// But do not allow other conflict with other usage types within our own network.
-				if externalSubnetUser.usageType == subnetUsageNetwork || externalSubnetUser.usageType == subnetUsageNetworkSNAT {
-					continue
-				}
-			}
+	var forwardListenAddresses map[int64]string
+	var loadBalancerListenAddresses map[int64]string
 
-			if SubnetContains(&externalSubnetUser.subnet, netip) || SubnetContains(netip, &externalSubnetUser.subnet) {
-				return false, nil
-			}
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		forwardListenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, n.ID(), true)
+		if err != nil {
+			return fmt.Errorf("Failed loading network forwards: %w", err)
 		}
 
-		return true, nil
-	}
+		loadBalancerListenAddresses, err = tx.GetNetworkLoadBalancerListenAddresses(ctx, n.ID(), true)
+		if err != nil {
+			return fmt.Errorf("Failed loading network load balancers: %w", err)
+		}
 
-	isValid, err := checkAddressNotInUse(listenAddressNet)
+		return nil
+	})
 	if err != nil {
-		return nil, err
-	} else if !isValid {
-		// This error is purposefully vague so that it doesn't reveal any names of
-		// resources potentially outside of the network.
-		return nil, fmt.Errorf("Forward listen address %q overlaps with another network or NIC", listenAddressNet.String())
+		return err
 	}
 
-	revert := revert.New()
-	defer revert.Fail()
+	// Only the first forward or load balancer on this bridge needs to enable hairpin mode on active NIC ports.
+	if len(forwardListenAddresses)+len(loadBalancerListenAddresses) > 1 {
+		return nil
+	}
 
-	var forwardID int64
+	filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
 
-	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		// Create forward DB record.
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			// Get the instance's effective network project name.
+			instNetworkProject := project.NetworkProjectFromRecord(&p)
+
+			if instNetworkProject != api.ProjectDefaultName {
+				return nil // Managed bridge networks can only exist in default project.
+			}
+
+			devices := instancetype.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
+
+			// Iterate through each of the instance's devices, looking for bridged NICs
+			// that are linked to this network.
+			for devName, devConfig := range devices {
+				if devConfig["type"] != "nic" {
+					continue
+				}
+
+				// Check whether the NIC device references our network..
+				if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
+					continue
+				}
+
+				hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
+				if InterfaceExists(hostName) {
+					link := &ip.Link{Name: hostName}
+					err := link.BridgeLinkSetHairpin(true)
+					if err != nil {
+						return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
+					}
+
+					n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
+				}
+			}
+
+			return nil
+		}, filter)
+	})
+}
+
+// forwardValidate validates the forward request.
+func (n *bridge) forwardValidate(listenAddress net.IP, forward api.NetworkForwardPut) ([]*forwardPortMap, error) {
+	err := n.checkAddressNotInOVNRange(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.common.forwardValidate(listenAddress, forward)
+}
+
+// ForwardCreate creates a network forward.
+func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType request.ClientType) (net.IP, error) {
+	memberSpecific := true // bridge supports per-member forwards.
+
+	// Convert listen address to subnet so we can check its valid and can be used.
+	listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+	}
+
+	if listenAddressNet.IP.IsUnspecified() {
+		return nil, api.StatusErrorf(http.StatusNotImplemented, "Automatic listen address allocation not supported for drivers of type %q", n.netType)
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check if there is an existing forward using the same listen address.
+		_, _, err := tx.GetNetworkForward(ctx, n.ID(), memberSpecific, forward.ListenAddress)
+
+		return err
+	})
+	if err == nil {
+		return nil, api.StatusErrorf(http.StatusConflict, "A forward for that listen address already exists")
+	}
+
+	_, err = n.forwardValidate(listenAddressNet.IP, forward.NetworkForwardPut)
+	if err != nil {
+		return nil, err
+	}
+
+	isValid, err := n.checkListenAddressNotInUse(listenAddressNet)
+	if err != nil {
+		return nil, err
+	} else if !isValid {
+		// This error is purposefully vague so that it doesn't reveal any names of
+		// resources potentially outside of the network.
+		return nil, fmt.Errorf("Forward listen address %q overlaps with another network or NIC", listenAddressNet.String())
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	var forwardID int64
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Create forward DB record.
 		forwardID, err = tx.CreateNetworkForward(ctx, n.ID(), memberSpecific, &forward)
 
 		return err
@@ -3236,79 +3913,10 @@ func (n *bridge) ForwardCreate(forward api.NetworkForwardsPost, clientType reque
 		return nil, err
 	}
 
-	// Check if hairpin mode needs to be enabled on active NIC bridge ports.
-	if n.config["bridge.driver"] != "openvswitch" {
-		brNetfilterEnabled := false
-		for _, ipVersion := range []uint{4, 6} {
-			if BridgeNetfilterEnabled(ipVersion) == nil {
-				brNetfilterEnabled = true
-				break
-			}
-		}
-
-		// If br_netfilter is enabled and bridge has forwards, we enable hairpin mode on each NIC's bridge
-		// port in case any of the forwards target the NIC and the instance attempts to connect to the
-		// forward's listener. Without hairpin mode on the target of the forward will not be able to
-		// connect to the listener.
-		if brNetfilterEnabled {
-			var listenAddresses map[int64]string
-
-			err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-				listenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, n.ID(), true)
-
-				return err
-			})
-			if err != nil {
-				return nil, fmt.Errorf("Failed loading network forwards: %w", err)
-			}
-
-			// If we are the first forward on this bridge, enable hairpin mode on active NIC ports.
-			if len(listenAddresses) <= 1 {
-				filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
-
-				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-					return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
-						// Get the instance's effective network project name.
-						instNetworkProject := project.NetworkProjectFromRecord(&p)
-
-						if instNetworkProject != api.ProjectDefaultName {
-							return nil // Managed bridge networks can only exist in default project.
-						}
-
-						devices := instancetype.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
-
-						// Iterate through each of the instance's devices, looking for bridged NICs
-						// that are linked to this network.
-						for devName, devConfig := range devices {
-							if devConfig["type"] != "nic" {
-								continue
-							}
-
-							// Check whether the NIC device references our network..
-							if !NICUsesNetwork(devConfig, &api.Network{Name: n.Name()}) {
-								continue
-							}
-
-							hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", devName)]
-							if InterfaceExists(hostName) {
-								link := &ip.Link{Name: hostName}
-								err = link.BridgeLinkSetHairpin(true)
-								if err != nil {
-									return fmt.Errorf("Error enabling hairpin mode on bridge port %q: %w", link.Name, err)
-								}
-
-								n.logger.Debug("Enabled hairpin mode on NIC bridge port", logger.Ctx{"inst": inst.Name, "project": inst.Project, "device": devName, "dev": link.Name})
-							}
-						}
-
-						return nil
-					}, filter)
-				})
-				if err != nil {
-					return nil, err
-				}
-			}
-		}
+	// Forward targets may connect to the forward's listen address.
+	err = n.enableNICHairpinMode()
+	if err != nil {
+		return nil, err
 	}
 
 	// Refresh exported BGP prefixes on local member.
@@ -3388,6 +3996,12 @@ func (n *bridge) ForwardUpdate(listenAddress string, req api.NetworkForwardPut,
 		return err
 	}
 
+	// Refresh exported BGP prefixes on local member (the BGP attributes may have changed).
+	err = n.forwardBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
+	}
+
 	revert.Success()
 	return nil
 }
@@ -3526,6 +4140,712 @@ func (n *bridge) forwardSetupFirewall() error {
 	return nil
 }
 
+// loadBalancerValidate validates the load balancer request.
+func (n *bridge) loadBalancerValidate(listenAddress net.IP, loadBalancer api.NetworkLoadBalancerPut) ([]*loadBalancerPortMap, error) {
+	err := n.checkAddressNotInOVNRange(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	portMaps, err := n.common.loadBalancerValidate(listenAddress, loadBalancer)
+	if err != nil {
+		return nil, err
+	}
+
+	portMapsPools, err := n.checkLoadBalancerPoolInstances(listenAddress, loadBalancer)
+	if err != nil {
+		return nil, err
+	}
+
+	portMaps = append(portMaps, portMapsPools...)
+	return portMaps, nil
+}
+
+// checkLoadBalancerPoolInstances check if any of the load balancer ports reference a pool of instances.
+// It checks the instances in the pool and returns port maps for the respective parent load balancer.
+// As load balancers are member specific, only instances running on the local member are included as targets.
+func (n *bridge) checkLoadBalancerPoolInstances(listenAddress net.IP, loadBalancer api.NetworkLoadBalancerPut) ([]*loadBalancerPortMap, error) {
+	var portMaps []*loadBalancerPortMap
+
+	for _, portSpec := range loadBalancer.Ports {
+		if portSpec.TargetPool == "" {
+			continue
+		}
+
+		var pool *api.NetworkLoadBalancerPool
+
+		err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			pool, err = n.getLoadBalancerPool(ctx, tx.Tx(), portSpec.TargetPool)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// If the pool protocol is unset, assume a default of "tcp".
+		poolProtocol := pool.Config["protocol"]
+		if poolProtocol == "" {
+			poolProtocol = "tcp"
+		}
+
+		if poolProtocol != portSpec.Protocol {
+			return nil, fmt.Errorf("Cannot use pool protocol %q with port protocol %q", poolProtocol, portSpec.Protocol)
+		}
+
+		listenPort, err := strconv.Atoi(portSpec.ListenPort)
+		if err != nil {
+			return nil, fmt.Errorf("Failed converting listen port %q to int: %w", portSpec.ListenPort, err)
+		}
+
+		portMap := loadBalancerPortMap{
+			listenPorts: []uint64{uint64(listenPort)},
+			protocol:    portSpec.Protocol,
+			targets:     make([]forwardTarget, 0, len(pool.Instances)),
+		}
+
+		for _, poolInstance := range pool.Instances {
+			// Load the instance from the DB by name.
+			inst, err := instance.LoadByProjectAndName(n.state, n.project, poolInstance.Name)
+			if err != nil {
+				return nil, fmt.Errorf("Failed loading instance %q: %w", poolInstance.Name, err)
+			}
+
+			// Instances on other members are targeted by the load balancers of those members.
+			instanceIsLocal := inst.Location() == n.state.ServerName && inst.IsRunning()
+			instanceHasNICInNetwork := false
+
+			// Find NICs connected to this network.
+			for devName, devConfig := range inst.ExpandedDevices() {
+				if devConfig["type"] != "nic" || !NICUsesNetwork(devConfig, &api.Network{Name: n.name}) {
+					continue
+				}
+
+				instanceHasNICInNetwork = true
+
+				if !instanceIsLocal {
+					continue
+				}
+
+				devIPs := n.loadBalancerInstanceNICAddresses(inst, devName, devConfig)
+				if len(devIPs) == 0 {
+					n.logger.Debug("Skipping load balancer pool instance as it's missing an IP in network", logger.Ctx{"instance": poolInstance.Name, "pool": pool.Name})
+					continue
+				}
+
+				targetPort := pool.Config["target_port"]
+
+				// An instance might use its own port.
+				if poolInstance.TargetPort != "" {
+					targetPort = poolInstance.TargetPort
+				}
+
+				targetPortInt, err := strconv.Atoi(targetPort)
+				if err != nil {
+					return nil, fmt.Errorf("Failed converting pool target port %q: %w", targetPort, err)
+				}
+
+				for _, ip := range devIPs {
+					// Skip IPs that don't match the listen address family.
+					if (listenAddress.To4() != nil) != (ip.To4() != nil) {
+						continue
+					}
+
+					portMap.targets = append(portMap.targets, forwardTarget{
+						address: ip,
+						instance: &forwardTargetInstance{
+							name:       inst.Name(),
+							uuid:       inst.LocalConfig()["volatile.uuid"],
+							deviceName: devName,
+						},
+						ports: []uint64{uint64(targetPortInt)},
+					})
+				}
+			}
+
+			if !instanceHasNICInNetwork {
+				return nil, fmt.Errorf("Instance %q does not have a device in network %q", poolInstance.Name, n.name)
+			}
+		}
+
+		// If the pool doesn't have any local instances, don't bother creating a port map.
+		if len(portMap.targets) == 0 {
+			continue
+		}
+
+		// Check and configure the health check.
+		portMap.healthCheck, err = n.checkPoolHealthCheck(pool)
+		if err != nil {
+			return nil, fmt.Errorf("Failed configuring load balancer health check for pool %q: %w", pool.Name, err)
+		}
+
+		portMaps = append(portMaps, &portMap)
+	}
+
+	return portMaps, nil
+}
+
+// loadBalancerInstanceNICAddresses returns the addresses of an instance NIC in the network.
+// Static addresses from the NIC config take precedence over the addresses leased to the NIC. Without stateful
+// DHCPv6 the NIC's IPv6 address is derived from its MAC address.
+func (n *bridge) loadBalancerInstanceNICAddresses(inst instance.Instance, devName string, devConfig map[string]string) []net.IP {
+	hwAddr := devConfig["hwaddr"]
+	if hwAddr == "" {
+		hwAddr = inst.LocalConfig()["volatile."+devName+".hwaddr"]
+	}
+
+	mac, _ := net.ParseMAC(hwAddr)
+
+	ipv4 := net.ParseIP(devConfig["ipv4.address"])
+	ipv6 := net.ParseIP(devConfig["ipv6.address"])
+
+	if mac != nil && (ipv4 == nil || ipv6 == nil) {
+		leaseIPs, err := GetLeaseAddresses(n.name, mac.String())
+		if err != nil {
+			n.logger.Debug("Failed getting lease addresses", logger.Ctx{"instance": inst.Name(), "device": devName, "err": err})
+		}
+
+		for _, leaseIP := range leaseIPs {
+			if ipv4 == nil && leaseIP.To4() != nil {
+				ipv4 = leaseIP
+			} else if ipv6 == nil && leaseIP.To4() == nil {
+				ipv6 = leaseIP
+			}
+		}
+	}
+
+	if ipv6 == nil && mac != nil && shared.IsFalseOrEmpty(n.config["ipv6.dhcp.stateful"]) {
+		_, netIP6, _ := net.ParseCIDR(n.config["ipv6.address"])
+		if netIP6 != nil {
+			eui64IP6, err := eui64.ParseMAC(netIP6.IP, mac)
+			if err == nil {
+				ipv6 = eui64IP6
+			}
+		}
+	}
+
+	addresses := make([]net.IP, 0, 2)
+	for _, ip := range []net.IP{ipv4, ipv6} {
+		if ip != nil {
+			addresses = append(addresses, ip)
+		}
+	}
+
+	return addresses
+}
+
+// LoadBalancerCreate creates a network load balancer.
+func (n *bridge) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) (net.IP, error) {
+	memberSpecific := true // bridge supports per-member load balancers.
+
+	// Convert listen address to subnet so we can check its valid and can be used.
+	listenAddressNet, err := ParseIPToNet(loadBalancer.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing load balancer listen address %q: %w", loadBalancer.ListenAddress, err)
+	}
+
+	if listenAddressNet.IP.IsUnspecified() {
+		return nil, api.StatusErrorf(http.StatusNotImplemented, "Automatic listen address allocation not supported for drivers of type %q", n.netType)
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check if there is an existing load balancer using the same listen address.
+		_, _, err := tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, loadBalancer.ListenAddress)
+
+		return err
+	})
+	if err == nil {
+		return nil, api.StatusErrorf(http.StatusConflict, "A load balancer for that listen address already exists")
+	}
+
+	_, err = n.loadBalancerValidate(listenAddressNet.IP, loadBalancer.NetworkLoadBalancerPut)
+	if err != nil {
+		return nil, err
+	}
+
+	isValid, err := n.checkListenAddressNotInUse(listenAddressNet)
+	if err != nil {
+		return nil, err
+	} else if !isValid {
+		// This error is purposefully vague so that it doesn't reveal any names of
+		// resources potentially outside of the network.
+		return nil, fmt.Errorf("Load balancer listen address %q overlaps with another network or NIC", listenAddressNet.String())
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	var loadBalancerID int64
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Create load balancer DB record.
+		loadBalancerID, err = tx.CreateNetworkLoadBalancer(ctx, n.ID(), memberSpecific, &loadBalancer)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteNetworkLoadBalancer(ctx, n.ID(), loadBalancerID)
+		})
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
+	})
+
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return nil, err
+	}
+
+	// Backends may connect to the load balancer's listen address and be picked as the target themselves.
+	err = n.enableNICHairpinMode()
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return nil, fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	revert.Success()
+	return listenAddressNet.IP, nil
+}
+
+// LoadBalancerUpdate updates a network load balancer.
+func (n *bridge) LoadBalancerUpdate(listenAddress string, req api.NetworkLoadBalancerPut, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member load balancers.
+
+	var curLoadBalancerID int64
+	var curLoadBalancer *api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		curLoadBalancerID, curLoadBalancer, err = tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, listenAddress)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = n.loadBalancerValidate(net.ParseIP(curLoadBalancer.ListenAddress), req)
+	if err != nil {
+		return err
+	}
+
+	curLoadBalancerEtagHash, err := util.EtagHash(curLoadBalancer.Etag())
+	if err != nil {
+		return err
+	}
+
+	newLoadBalancer := api.NetworkLoadBalancer{
+		ListenAddress: curLoadBalancer.ListenAddress,
+		Description:   req.Description,
+		Config:        req.Config,
+		Backends:      req.Backends,
+		Ports:         req.Ports,
+	}
+
+	newLoadBalancerEtagHash, err := util.EtagHash(newLoadBalancer.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curLoadBalancerEtagHash == newLoadBalancerEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateNetworkLoadBalancer(ctx, n.ID(), curLoadBalancerID, newLoadBalancer.Writable())
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpdateNetworkLoadBalancer(ctx, n.ID(), curLoadBalancerID, curLoadBalancer.Writable())
+		})
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
+	})
+
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Refresh exported BGP prefixes on local member (the BGP attributes may have changed).
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
+// LoadBalancerDelete deletes a network load balancer.
+func (n *bridge) LoadBalancerDelete(listenAddress string, clientType request.ClientType) error {
+	memberSpecific := true // bridge supports per-member load balancers.
+	var loadBalancerID int64
+	var loadBalancer *api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		loadBalancerID, loadBalancer, err = tx.GetNetworkLoadBalancer(ctx, n.ID(), memberSpecific, listenAddress)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteNetworkLoadBalancer(ctx, n.ID(), loadBalancerID)
+	})
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() {
+		newLoadBalancer := api.NetworkLoadBalancersPost{
+			NetworkLoadBalancerPut: loadBalancer.Writable(),
+			ListenAddress:          loadBalancer.ListenAddress,
+		}
+
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, _ = tx.CreateNetworkLoadBalancer(ctx, n.ID(), memberSpecific, &newLoadBalancer)
+
+			return nil
+		})
+
+		_ = n.loadBalancerSetupFirewall()
+		_ = n.loadBalancerBGPSetupPrefixes()
+	})
+
+	err = n.loadBalancerSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	// Refresh exported BGP prefixes on local member.
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	revert.Success()
+	return nil
+}
+
+// bridgeLoadBalancersMu serializes applying load balancers, as they are applied both by API requests and by
+// the health monitors.
+var bridgeLoadBalancersMu sync.Mutex
+
+// loadBalancerSetupFirewall applies all network load balancers defined for this network and this member.
+func (n *bridge) loadBalancerSetupFirewall() error {
+	return n.loadBalancerApply(true)
+}
+
+// loadBalancerApply applies all network load balancers defined for this network and this member.
+// Unless force is true, the firewall is only updated if the load balancer rules have changed since they were last
+// applied. Load balancer pool targets with health checks are monitored from the host and left out while offline.
+func (n *bridge) loadBalancerApply(force bool) error {
+	bridgeLoadBalancersMu.Lock()
+	defer bridgeLoadBalancersMu.Unlock()
+
+	memberSpecific := true // Get all load balancers for this cluster member.
+
+	var loadBalancers map[int64]*api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		loadBalancers, err = tx.GetNetworkLoadBalancers(ctx, n.ID(), memberSpecific)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
+	}
+
+	listenAddressPortMaps := make(map[string][]*loadBalancerPortMap, len(loadBalancers))
+	healthTargets := make(map[loadBalancerHealthTarget]loadBalancerHealthCheck)
+	usesPools := false
+
+	for _, loadBalancer := range loadBalancers {
+		listenAddressNet, err := ParseIPToNet(loadBalancer.ListenAddress)
+		if err != nil {
+			return fmt.Errorf("Failed parsing load balancer listen address %q: %w", loadBalancer.ListenAddress, err)
+		}
+
+		portMaps, err := n.loadBalancerValidate(listenAddressNet.IP, loadBalancer.Writable())
+		if err != nil {
+			return fmt.Errorf("Failed validating firewall load balancer for listen address %q: %w", loadBalancer.ListenAddress, err)
+		}
+
+		for _, port := range loadBalancer.Ports {
+			if port.TargetPool != "" {
+				usesPools = true
+			}
+		}
+
+		for _, portMap := range portMaps {
+			if portMap.healthCheck == nil {
+				continue
+			}
+
+			for _, target := range portMap.targets {
+				healthTargets[loadBalancerHealthTarget{protocol: portMap.protocol, address: target.address.String(), port: target.ports[0]}] = *portMap.healthCheck
+			}
+		}
+
+		listenAddressPortMaps[listenAddressNet.IP.String()] = portMaps
+	}
+
+	// Keep monitoring the network while any of its load balancers use pools, so that changes to the pool
+	// instances are picked up even if none of them are currently a target.
+	var monitor *loadBalancerHealthMonitor
+	if usesPools {
+		monitor = loadBalancerHealthMonitorStart(n.id, func() {
+			err := n.loadBalancerApply(false)
+			if err != nil {
+				n.logger.Warn("Failed refreshing load balancers", logger.Ctx{"err": err})
+			}
+		})
+
+		monitor.setTargets(healthTargets)
+	} else {
+		loadBalancerHealthMonitorStop(n.id)
+	}
+
+	var fwLoadBalancers []firewallDrivers.LoadBalancer
+	for listenAddress, portMaps := range listenAddressPortMaps {
+		fwLoadBalancers = append(fwLoadBalancers, n.loadBalancerConvertToFirewallLoadBalancers(net.ParseIP(listenAddress), portMaps, monitor)...)
+	}
+
+	// Apply the load balancers in a stable order so that unchanged rules can be detected.
+	slices.SortFunc(fwLoadBalancers, func(a firewallDrivers.LoadBalancer, b firewallDrivers.LoadBalancer) int {
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	})
+
+	if monitor != nil && !monitor.rulesChanged(fmt.Sprint(fwLoadBalancers)) && !force {
+		return nil
+	}
+
+	err = n.state.Firewall.NetworkApplyLoadBalancers(n.name, fwLoadBalancers)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall load balancers: %w", err)
+	}
+
+	return nil
+}
+
+// loadBalancerConvertToFirewallLoadBalancers converts load balancer port maps into format compatible with the
+// firewall package. Targets with health checks are left out while the monitor reports them as offline.
+func (n *bridge) loadBalancerConvertToFirewallLoadBalancers(listenAddress net.IP, portMaps []*loadBalancerPortMap, monitor *loadBalancerHealthMonitor) []firewallDrivers.LoadBalancer {
+	fwLoadBalancers := make([]firewallDrivers.LoadBalancer, 0, len(portMaps))
+
+	for _, portMap := range portMaps {
+		for i, lp := range portMap.listenPorts {
+			fwLoadBalancer := firewallDrivers.LoadBalancer{
+				ListenAddress: listenAddress,
+				Protocol:      portMap.protocol,
+				ListenPort:    lp,
+			}
+
+			for _, target := range portMap.targets {
+				targetPort := lp // Default to using same port as listen port for target port.
+				targetPortsLen := len(target.ports)
+
+				if targetPortsLen == 1 {
+					// If a single target port is specified, forward all listen ports to it.
+					targetPort = target.ports[0]
+				} else if targetPortsLen > 1 {
+					// If more than 1 target port specified, use listen port index to get the
+					// target port to use.
+					targetPort = target.ports[i]
+				}
+
+				if portMap.healthCheck != nil {
+					healthTarget := loadBalancerHealthTarget{protocol: portMap.protocol, address: target.address.String(), port: targetPort}
+					if monitor.status(healthTarget) == loadBalancerTargetStatusOffline {
+						continue
+					}
+				}
+
+				fwLoadBalancer.Targets = append(fwLoadBalancer.Targets, firewallDrivers.LoadBalancerTarget{
+					Address: target.address,
+					Port:    targetPort,
+					Weight:  target.weight,
+				})
+			}
+
+			// Skip listen ports without any healthy targets.
+			if len(fwLoadBalancer.Targets) == 0 {
+				continue
+			}
+
+			fwLoadBalancers = append(fwLoadBalancers, fwLoadBalancer)
+		}
+	}
+
+	return fwLoadBalancers
+}
+
+// LoadBalancerPoolCreate creates a network load balancer pool.
+func (n *bridge) LoadBalancerPoolCreate(pool api.NetworkLoadBalancerPoolsPost) error {
+	return n.loadBalancerPoolCreate(pool)
+}
+
+// LoadBalancerPoolUpdate updates a network load balancer pool.
+// Load balancers of other cluster members pick up the change when they are next refreshed.
+func (n *bridge) LoadBalancerPoolUpdate(poolName string, req api.NetworkLoadBalancerPoolPut) error {
+	return n.loadBalancerPoolUpdate(poolName, req, func(loadBalancer *api.NetworkLoadBalancer) error {
+		if loadBalancer.Location != "" && loadBalancer.Location != n.state.ServerName {
+			return nil
+		}
+
+		return n.loadBalancerSetupFirewall()
+	})
+}
+
+// LoadBalancerPoolDelete deletes a network load balancer pool.
+func (n *bridge) LoadBalancerPoolDelete(poolName string) error {
+	return n.loadBalancerPoolDelete(poolName)
+}
+
+// LoadBalancerPoolState returns the state of the targets of a network load balancer pool on this member.
+func (n *bridge) LoadBalancerPoolState(poolName string) (*api.NetworkLoadBalancerPoolState, error) {
+	memberSpecific := true // Get all load balancers for this cluster member.
+
+	var pool *api.NetworkLoadBalancerPool
+	var loadBalancers map[int64]*api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		pool, err = n.getLoadBalancerPool(ctx, tx.Tx(), poolName)
+		if err != nil {
+			return err
+		}
+
+		loadBalancers, err = tx.GetNetworkLoadBalancers(ctx, n.ID(), memberSpecific)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	healthCheck, err := n.checkPoolHealthCheck(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	poolProtocol := pool.Config["protocol"]
+	if poolProtocol == "" {
+		poolProtocol = "tcp"
+	}
+
+	// Build a map of load balancer listen addresses using the pool together with their listen ports.
+	poolListenAddresses := make(map[string][]string)
+	for _, lb := range loadBalancers {
+		for _, port := range lb.Ports {
+			if port.TargetPool == poolName {
+				poolListenAddresses[lb.ListenAddress] = append(poolListenAddresses[lb.ListenAddress], port.ListenPort)
+			}
+		}
+	}
+
+	monitor := loadBalancerHealthMonitorGet(n.id)
+
+	poolState := &api.NetworkLoadBalancerPoolState{
+		// For the initialize size assume each instance has at least one device in the network.
+		Targets: make([]api.NetworkLoadBalancerPoolTarget, 0, len(pool.Instances)),
+	}
+
+	for _, poolInstance := range pool.Instances {
+		inst, err := instance.LoadByProjectAndName(n.state, n.project, poolInstance.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading instance %q: %w", poolInstance.Name, err)
+		}
+
+		instanceIsLocal := inst.Location() == n.state.ServerName && inst.IsRunning()
+
+		targetPort := pool.Config["target_port"]
+		if poolInstance.TargetPort != "" {
+			targetPort = poolInstance.TargetPort
+		}
+
+		targetPortInt, err := strconv.ParseUint(targetPort, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed converting pool target port %q: %w", targetPort, err)
+		}
+
+		for devName, devConfig := range inst.ExpandedDevices() {
+			if devConfig["type"] != "nic" || !NICUsesNetwork(devConfig, &api.Network{Name: n.name}) {
+				continue
+			}
+
+			var devIPs []net.IP
+			if instanceIsLocal {
+				devIPs = n.loadBalancerInstanceNICAddresses(inst, devName, devConfig)
+			}
+
+			for listenAddr, listenPorts := range poolListenAddresses {
+				listenIP := net.ParseIP(listenAddr)
+				if listenIP == nil {
+					continue
+				}
+
+				target := api.NetworkLoadBalancerPoolTarget{
+					ListenAddress: listenAddr,
+					Name:          poolInstance.Name,
+					Port:          targetPort,
+					Device:        devName,
+					Status:        loadBalancerTargetStatusUnknown,
+				}
+
+				// Match IPv4 target to IPv4 load balancer, IPv6 to IPv6.
+				for _, devIP := range devIPs {
+					if (devIP.To4() != nil) == (listenIP.To4() != nil) {
+						target.Address = devIP.String()
+
+						if healthCheck != nil {
+							target.Status = monitor.status(loadBalancerHealthTarget{protocol: poolProtocol, address: target.Address, port: targetPortInt})
+						}
+
+						break
+					}
+				}
+
+				for _, port := range listenPorts {
+					target.ListenPort = port
+					poolState.Targets = append(poolState.Targets, target)
+				}
+			}
+		}
+	}
+
+	return poolState, nil
+}
+
 // Leases returns a list of leases for the bridged network. It will reach out to other cluster members as needed.
 // The projectName passed here refers to the initial project from the API request which may differ from the network's project.
 // If projectName is empty, get leases from all projects.
@@ -3759,7 +5079,7 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 
 // UsesDNSMasq indicates if network's config indicates if it needs to use dnsmasq.
 func (n *bridge) UsesDNSMasq() bool {
-	return n.config["bridge.mode"] == "fan" || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"]) || !slices.Contains([]string{"", "none"}, n.config["ipv6.address"])
+	return slices.Contains([]string{"fan", "wireguard"}, n.config["bridge.mode"]) || !slices.Contains([]string{"", "none"}, n.config["ipv4.address"]) || !slices.Contains([]string{"", "none"}, n.config["ipv6.address"])
 }
 
 // checkAddressNotInOVNRange checks that a given IP address does not overlap
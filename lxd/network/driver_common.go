@@ -2,10 +2,12 @@ package network
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"maps"
 	"net"
+	"net/http"
 	"os"
 	"slices"
 	"strconv"
@@ -19,6 +21,8 @@ import (
 	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/lxd/db"
 	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/ip"
 	"github.com/canonical/lxd/lxd/network/acl"
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
@@ -28,6 +32,7 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/validate"
 	"github.com/canonical/lxd/shared/version"
 )
@@ -56,6 +61,7 @@ type forwardTarget struct {
 	address  net.IP
 	instance *forwardTargetInstance
 	ports    []uint64
+	weight   uint64
 }
 
 // forwardPortMap represents a mapping of listen port(s) to target port(s) for a protocol/target address pair.
@@ -86,6 +92,13 @@ type loadBalancerPortMap struct {
 	healthCheck *loadBalancerHealthCheck
 }
 
+// loadBalancerBackendMaxWeight is the maximum weight of a load balancer backend.
+const loadBalancerBackendMaxWeight = 1000
+
+// bgpImportRouteProto is the routing protocol ID of the routes imported from BGP peers. It differs from the
+// "bgp" ID used by routing daemons, so that only the routes imported by LXD are ever removed.
+const bgpImportRouteProto = "250"
+
 // subnetUsageType indicates the type of use for a subnet.
 type subnetUsageType uint
 
@@ -738,7 +751,27 @@ func (n *common) handleDependencyChange(netName string, netConfig map[string]str
 
 // bgpValidate.
 func (n *common) bgpValidationRules(config map[string]string) (map[string]func(value string) error, error) {
-	rules := map[string]func(value string) error{}
+	rules := map[string]func(value string) error{
+		"bgp.import": validate.Optional(validate.IsBool),
+		"bgp.import.table": validate.Optional(func(value string) error {
+			if value == "main" {
+				return nil
+			}
+
+			return validate.IsInRange(1, 4294967295)(value)
+		}),
+		"bgp.import.vrf":      validate.Optional(validate.IsInterfaceName),
+		"bgp.import.prefixes": validate.Optional(validate.IsListOf(validate.IsNetwork)),
+	}
+
+	if config["bgp.import.table"] != "" && config["bgp.import.vrf"] != "" {
+		return nil, errors.New(`"bgp.import.table" and "bgp.import.vrf" cannot be set together`)
+	}
+
+	if shared.IsTrue(config["bgp.import"]) && config["bgp.import.prefixes"] == "" {
+		return nil, errors.New(`"bgp.import.prefixes" must be set when "bgp.import" is enabled`)
+	}
+
 	for k := range config {
 		// BGP keys have the peer name in their name, extract the suffix.
 		if !strings.HasPrefix(k, "bgp.peers.") {
@@ -763,6 +796,8 @@ func (n *common) bgpValidationRules(config map[string]string) (map[string]func(v
 			rules[k] = validate.IsAny
 		case "holdtime":
 			rules[k] = validate.Optional(validate.IsInRange(9, 65535))
+		case "max_prefixes":
+			rules[k] = validate.Optional(validate.IsUint32)
 		}
 	}
 
@@ -787,11 +822,24 @@ func (n *common) bgpSetup(oldConfig map[string]string) error {
 		return fmt.Errorf("Failed applying BGP prefixes for address forwards: %w", err)
 	}
 
+	err = n.loadBalancerBGPSetupPrefixes()
+	if err != nil {
+		return fmt.Errorf("Failed applying BGP prefixes for load balancers: %w", err)
+	}
+
+	err = n.bgpSetupImport()
+	if err != nil {
+		return fmt.Errorf("Failed setting up BGP route import: %w", err)
+	}
+
 	return nil
 }
 
 // bgpClear initializes BGP peers and prefixes.
 func (n *common) bgpClear(config map[string]string) error {
+	// Withdraw imported routes.
+	n.state.BGP.RemoveImportByOwner(fmt.Sprintf("network_%d_import", n.id))
+
 	// Clear all peers.
 	err := n.bgpClearPeers(config)
 	if err != nil {
@@ -810,6 +858,12 @@ func (n *common) bgpClear(config map[string]string) error {
 		return err
 	}
 
+	// Clear existing load balancer prefixes for network.
+	err = n.state.BGP.RemovePrefixByOwner(fmt.Sprintf("network_%d_load_balancer", n.id))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -869,7 +923,15 @@ func (n *common) bgpSetupPeers(oldConfig map[string]string) error {
 			}
 		}
 
-		err = n.state.BGP.AddPeer(net.ParseIP(fields[0]), uint32(asn), fields[2], holdTime)
+		var maxPrefixes uint64
+		if fields[4] != "" {
+			maxPrefixes, err = strconv.ParseUint(fields[4], 10, 32)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = n.state.BGP.AddPeer(net.ParseIP(fields[0]), uint32(asn), fields[2], holdTime, uint32(maxPrefixes))
 		if err != nil {
 			return err
 		}
@@ -907,6 +969,11 @@ func (n *common) bgpSetupPrefixes(oldConfig map[string]string) error {
 		}
 	}
 
+	attrs, err := BGPPathAttributes(n.config)
+	if err != nil {
+		return err
+	}
+
 	// Add the new prefixes.
 	for _, ipVersion := range []uint{4, 6} {
 		nextHopAddr := n.bgpNextHopAddress(ipVersion)
@@ -925,7 +992,7 @@ func (n *common) bgpSetupPrefixes(oldConfig map[string]string) error {
 					return err
 				}
 
-				err = n.state.BGP.AddPrefix(*subnet, nextHopAddr, bgpOwner)
+				err = n.state.BGP.AddPrefix(*subnet, nextHopAddr, bgpOwner, attrs)
 				if err != nil {
 					return err
 				}
@@ -938,7 +1005,7 @@ func (n *common) bgpSetupPrefixes(oldConfig map[string]string) error {
 				return fmt.Errorf("Failed parsing network address %q: %w", netAddress, err)
 			}
 
-			err = n.state.BGP.AddPrefix(*subnet, nextHopAddr, bgpOwner)
+			err = n.state.BGP.AddPrefix(*subnet, nextHopAddr, bgpOwner, attrs)
 			if err != nil {
 				return err
 			}
@@ -970,15 +1037,127 @@ func (n *common) bgpGetPeers(config map[string]string) []string {
 		peerASN := config[fmt.Sprintf("bgp.peers.%s.asn", peerName)]
 		peerPassword := config[fmt.Sprintf("bgp.peers.%s.password", peerName)]
 		peerHoldTime := config[fmt.Sprintf("bgp.peers.%s.holdtime", peerName)]
+		peerMaxPrefixes := config[fmt.Sprintf("bgp.peers.%s.max_prefixes", peerName)]
 
 		if peerAddress != "" && peerASN != "" {
-			peers = append(peers, fmt.Sprintf("%s,%s,%s,%s", peerAddress, peerASN, peerPassword, peerHoldTime))
+			peers = append(peers, fmt.Sprintf("%s,%s,%s,%s,%s", peerAddress, peerASN, peerPassword, peerHoldTime, peerMaxPrefixes))
 		}
 	}
 
 	return peers
 }
 
+// bgpSetupImport installs the routes learned from the network's BGP peers if route import is enabled.
+func (n *common) bgpSetupImport() error {
+	bgpOwner := fmt.Sprintf("network_%d_import", n.id)
+	if shared.IsFalseOrEmpty(n.config["bgp.import"]) {
+		n.state.BGP.RemoveImportByOwner(bgpOwner)
+		return nil
+	}
+
+	peers := []net.IP{}
+	for _, peer := range n.bgpGetPeers(n.config) {
+		addr, _, _ := strings.Cut(peer, ",")
+		peers = append(peers, net.ParseIP(addr))
+	}
+
+	prefixes := []net.IPNet{}
+	for _, prefix := range shared.SplitNTrimSpace(n.config["bgp.import.prefixes"], ",", -1, true) {
+		_, subnet, err := net.ParseCIDR(prefix)
+		if err != nil {
+			return fmt.Errorf("Failed parsing import prefix %q: %w", prefix, err)
+		}
+
+		prefixes = append(prefixes, *subnet)
+	}
+
+	// Never import routes within the subnets of LXD managed networks or of the host's own addresses.
+	var managedSubnets []*net.IPNet
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		projectNetworks, err := tx.GetCreatedNetworks(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, networks := range projectNetworks {
+			for _, network := range networks {
+				managedSubnets = append(managedSubnets, networkSubnets(network.Config)...)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting managed network subnets: %w", err)
+	}
+
+	reject := func(prefix net.IPNet) bool {
+		for _, subnet := range append(hostSubnets(), managedSubnets...) {
+			if SubnetContains(subnet, &prefix) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	table := n.config["bgp.import.table"]
+	vrf := n.config["bgp.import.vrf"]
+	if table == "" && vrf == "" {
+		table = "main"
+	}
+
+	handler := func(route bgp.Route, withdraw bool) error {
+		r := &ip.Route{
+			Route:  route.Prefix.String(),
+			Table:  table,
+			VRF:    vrf,
+			Proto:  bgpImportRouteProto,
+			Family: ip.FamilyV4,
+		}
+
+		if route.Prefix.IP.To4() == nil {
+			r.Family = ip.FamilyV6
+		}
+
+		// Deleting a route only matches the routes with the import protocol ID.
+		if withdraw {
+			return r.Delete()
+		}
+
+		// Routes are never replaced, so that a route for the prefix from another source is left untouched
+		// and the import fails instead. Remove the route previously imported for the prefix, if any.
+		_ = r.Delete()
+		r.Via = route.Nexthop.String()
+
+		return r.Add()
+	}
+
+	return n.state.BGP.AddImport(bgpOwner, peers, prefixes, reject, handler)
+}
+
+// bgpState returns the state of the routes learned from the network's BGP peers if route import is enabled.
+func (n *common) bgpState() *api.NetworkStateBGP {
+	if shared.IsFalseOrEmpty(n.config["bgp.import"]) {
+		return nil
+	}
+
+	state := &api.NetworkStateBGP{
+		Routes: []api.NetworkStateBGPRoute{},
+	}
+
+	for _, route := range n.state.BGP.LearnedRoutes(fmt.Sprintf("network_%d_import", n.id)) {
+		state.Routes = append(state.Routes, api.NetworkStateBGPRoute{
+			Prefix:   route.Prefix.String(),
+			Nexthop:  route.Nexthop.String(),
+			Peer:     route.Peer.String(),
+			Imported: route.Imported,
+		})
+	}
+
+	return state
+}
+
 // projectUplinkIPQuotaAvailable checks if a project has quota available to assign new uplink IPs in a certain network.
 func (n *common) projectUplinkIPQuotaAvailable(ctx context.Context, tx *db.ClusterTx, p *api.Project, uplinkName string) (ipv4QuotaAvailable bool, ipv6QuotaAvailable bool, err error) {
 	rawIPV4Quota, hasIPV4Quota := p.Config["limits.networks.uplink_ips.ipv4."+uplinkName]
@@ -1031,7 +1210,8 @@ func (n *common) forwardValidate(listenAddress net.IP, forward api.NetworkForwar
 	}
 
 	// Look for any unknown config fields.
-	for k := range forward.Config {
+	bgpRules := BGPPathAttributesValidationRules()
+	for k, v := range forward.Config {
 		if k == "target_address" {
 			continue
 		}
@@ -1041,6 +1221,16 @@ func (n *common) forwardValidate(listenAddress net.IP, forward api.NetworkForwar
 			continue
 		}
 
+		validator, ok := bgpRules[k]
+		if ok {
+			err := validator(v)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value for forward option %q: %w", k, err)
+			}
+
+			continue
+		}
+
 		return nil, fmt.Errorf("Invalid option %q", k)
 	}
 
@@ -1196,13 +1386,13 @@ func (n *common) ForwardDelete(listenAddress string, clientType request.ClientTy
 
 // forwardBGPSetupPrefixes exports external forward addresses as prefixes.
 func (n *common) forwardBGPSetupPrefixes() error {
-	var fwdListenAddresses map[int64]string
+	var forwards map[int64]*api.NetworkForward
 
 	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
-		// Retrieve network forwards before clearing existing prefixes, and separate them by IP family.
-		fwdListenAddresses, err = tx.GetNetworkForwardListenAddresses(ctx, n.ID(), true)
+		// Retrieve network forwards before clearing existing prefixes.
+		forwards, err = tx.GetNetworkForwards(ctx, n.ID(), true)
 
 		return err
 	})
@@ -1210,25 +1400,34 @@ func (n *common) forwardBGPSetupPrefixes() error {
 		return fmt.Errorf("Failed loading network forwards: %w", err)
 	}
 
-	fwdListenAddressesByFamily := map[uint][]string{
+	listenAddressConfigs := make(map[string]map[string]string, len(forwards))
+	for _, forward := range forwards {
+		listenAddressConfigs[forward.ListenAddress] = forward.Config
+	}
+
+	// Use forward specific owner string (different from the network prefixes) so that these can be reapplied
+	// independently of the network's own prefixes.
+	return n.bgpSetupListenAddressPrefixes(fmt.Sprintf("network_%d_forward", n.id), listenAddressConfigs)
+}
+
+// bgpSetupListenAddressPrefixes exports the external listen addresses of forwards or load balancers as prefixes.
+// The BGP path attributes set in the config of a listen address take precedence over the network's.
+func (n *common) bgpSetupListenAddressPrefixes(bgpOwner string, listenAddressConfigs map[string]map[string]string) error {
+	listenAddressesByFamily := map[uint][]string{
 		4: make([]string, 0),
 		6: make([]string, 0),
 	}
 
-	for _, fwdListenAddress := range fwdListenAddresses {
-		if strings.Contains(fwdListenAddress, ":") {
-			fwdListenAddressesByFamily[6] = append(fwdListenAddressesByFamily[6], fwdListenAddress)
+	for listenAddress := range listenAddressConfigs {
+		if strings.Contains(listenAddress, ":") {
+			listenAddressesByFamily[6] = append(listenAddressesByFamily[6], listenAddress)
 		} else {
-			fwdListenAddressesByFamily[4] = append(fwdListenAddressesByFamily[4], fwdListenAddress)
+			listenAddressesByFamily[4] = append(listenAddressesByFamily[4], listenAddress)
 		}
 	}
 
-	// Use forward specific owner string (different from the network prefixes) so that these can be reapplied
-	// independently of the network's own prefixes.
-	bgpOwner := fmt.Sprintf("network_%d_forward", n.id)
-
-	// Clear existing address forward prefixes for network.
-	err = n.state.BGP.RemovePrefixByOwner(bgpOwner)
+	// Clear existing listen address prefixes for network.
+	err := n.state.BGP.RemovePrefixByOwner(bgpOwner)
 	if err != nil {
 		return err
 	}
@@ -1244,21 +1443,26 @@ func (n *common) forwardBGPSetupPrefixes() error {
 			routeSubnetSize = 32
 		}
 
-		// Export external forward listen addresses.
-		for _, fwdListenAddress := range fwdListenAddressesByFamily[ipVersion] {
-			fwdListenAddr := net.ParseIP(fwdListenAddress)
+		// Export external listen addresses.
+		for _, listenAddress := range listenAddressesByFamily[ipVersion] {
+			listenAddr := net.ParseIP(listenAddress)
 
-			// Don't export internal address forwards (those inside the NAT enabled network's subnet).
-			if natEnabled && netSubnet != nil && netSubnet.Contains(fwdListenAddr) {
+			// Don't export internal listen addresses (those inside the NAT enabled network's subnet).
+			if natEnabled && netSubnet != nil && netSubnet.Contains(listenAddr) {
 				continue
 			}
 
-			_, ipRouteSubnet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", fwdListenAddr.String(), routeSubnetSize))
+			_, ipRouteSubnet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", listenAddr.String(), routeSubnetSize))
 			if err != nil {
 				return err
 			}
 
-			err = n.state.BGP.AddPrefix(*ipRouteSubnet, nextHopAddr, bgpOwner)
+			attrs, err := BGPPathAttributes(listenAddressConfigs[listenAddress], n.config)
+			if err != nil {
+				return err
+			}
+
+			err = n.state.BGP.AddPrefix(*ipRouteSubnet, nextHopAddr, bgpOwner, attrs)
 			if err != nil {
 				return err
 			}
@@ -1363,12 +1567,23 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward api.NetworkL
 	}
 
 	// Look for any unknown config fields.
-	for k := range forward.Config {
+	bgpRules := BGPPathAttributesValidationRules()
+	for k, v := range forward.Config {
 		// User keys are not validated.
 		if config.IsUserConfig(k) {
 			continue
 		}
 
+		validator, ok := bgpRules[k]
+		if ok {
+			err := validator(v)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value for load balancer option %q: %w", k, err)
+			}
+
+			continue
+		}
+
 		return nil, fmt.Errorf("Invalid option %q", k)
 	}
 
@@ -1418,9 +1633,14 @@ func (n *common) loadBalancerValidate(listenAddress net.IP, forward api.NetworkL
 			return nil, errors.New("Target address cannot be a network address")
 		}
 
+		if backendSpec.Weight > loadBalancerBackendMaxWeight {
+			return nil, fmt.Errorf("Weight cannot be greater than %d for backend %q", loadBalancerBackendMaxWeight, backendSpec.Name)
+		}
+
 		// Check valid target port(s) supplied.
 		target := forwardTarget{
 			address: targetAddress,
+			weight:  backendSpec.Weight,
 		}
 
 		for portSpecID, portSpec := range shared.SplitNTrimSpace(backendSpec.TargetPort, ",", -1, true) {
@@ -1547,76 +1767,28 @@ func (n *common) LoadBalancerDelete(listenAddress string, clientType request.Cli
 
 // loadBalancerBGPSetupPrefixes exports external load balancer addresses as prefixes.
 func (n *common) loadBalancerBGPSetupPrefixes() error {
-	var listenAddresses map[int64]string
+	var loadBalancers map[int64]*api.NetworkLoadBalancer
 
 	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 
-		// Retrieve network forwards before clearing existing prefixes, and separate them by IP family.
-		listenAddresses, err = tx.GetNetworkLoadBalancerListenAddresses(ctx, n.ID(), true)
+		// Retrieve network load balancers before clearing existing prefixes.
+		loadBalancers, err = tx.GetNetworkLoadBalancers(ctx, n.ID(), true)
 
 		return err
 	})
 	if err != nil {
-		return fmt.Errorf("Failed loading network forwards: %w", err)
+		return fmt.Errorf("Failed loading network load balancers: %w", err)
 	}
 
-	listenAddressesByFamily := map[uint][]string{
-		4: make([]string, 0),
-		6: make([]string, 0),
-	}
-
-	for _, listenAddress := range listenAddresses {
-		if strings.Contains(listenAddress, ":") {
-			listenAddressesByFamily[6] = append(listenAddressesByFamily[6], listenAddress)
-		} else {
-			listenAddressesByFamily[4] = append(listenAddressesByFamily[4], listenAddress)
-		}
+	listenAddressConfigs := make(map[string]map[string]string, len(loadBalancers))
+	for _, loadBalancer := range loadBalancers {
+		listenAddressConfigs[loadBalancer.ListenAddress] = loadBalancer.Config
 	}
 
 	// Use load balancer specific owner string (different from the network prefixes) so that these can be
 	// reapplied independently of the network's own prefixes.
-	bgpOwner := fmt.Sprintf("network_%d_load_balancer", n.id)
-
-	// Clear existing address load balancer prefixes for network.
-	err = n.state.BGP.RemovePrefixByOwner(bgpOwner)
-	if err != nil {
-		return err
-	}
-
-	// Add the new prefixes.
-	for _, ipVersion := range []uint{4, 6} {
-		nextHopAddr := n.bgpNextHopAddress(ipVersion)
-		natEnabled := shared.IsTrue(n.config[fmt.Sprintf("ipv%d.nat", ipVersion)])
-		_, netSubnet, _ := net.ParseCIDR(n.config[fmt.Sprintf("ipv%d.address", ipVersion)])
-
-		routeSubnetSize := 128
-		if ipVersion == 4 {
-			routeSubnetSize = 32
-		}
-
-		// Export external forward listen addresses.
-		for _, listenAddress := range listenAddressesByFamily[ipVersion] {
-			listenAddr := net.ParseIP(listenAddress)
-
-			// Don't export internal address forwards (those inside the NAT enabled network's subnet).
-			if natEnabled && netSubnet != nil && netSubnet.Contains(listenAddr) {
-				continue
-			}
-
-			_, ipRouteSubnet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", listenAddr.String(), routeSubnetSize))
-			if err != nil {
-				return err
-			}
-
-			err = n.state.BGP.AddPrefix(*ipRouteSubnet, nextHopAddr, bgpOwner)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return n.bgpSetupListenAddressPrefixes(fmt.Sprintf("network_%d_load_balancer", n.id), listenAddressConfigs)
 }
 
 // Leases returns ErrNotImplemented for drivers that don't support address leases.
@@ -1758,7 +1930,14 @@ func (n *common) peerUsedBy(peerName string, firstOnly bool) ([]string, error) {
 
 // State returns the api.NetworkState for the network.
 func (n *common) State() (*api.NetworkState, error) {
-	return resources.GetNetworkState(n.name)
+	state, err := resources.GetNetworkState(n.name)
+	if err != nil {
+		return nil, err
+	}
+
+	state.BGP = n.bgpState()
+
+	return state, nil
 }
 
 func (n *common) setUnavailable() {
@@ -1802,3 +1981,529 @@ func (n *common) LoadBalancerPoolDelete(poolName string) error {
 func (n *common) LoadBalancerPoolState(poolName string) (*api.NetworkLoadBalancerPoolState, error) {
 	return nil, ErrNotImplemented
 }
+
+// checkPoolHealthCheck checks the pool's health check settings and returns a health check struct if valid.
+func (n *common) checkPoolHealthCheck(pool *api.NetworkLoadBalancerPool) (*loadBalancerHealthCheck, error) {
+	// If health checks are disabled, return early.
+	if shared.IsFalse(pool.Config["healthcheck"]) {
+		return nil, nil
+	}
+
+	var err error
+
+	// Use defaults if none are provided in the pool's config.
+	// These are the values defined by OVN in https://github.com/ovn-org/ovn/blob/main/controller/pinctrl.c.
+	// Bridge networks use the same defaults for their host side health checks.
+	healthCheckConfig := map[string]uint64{
+		"healthcheck.interval":      5,
+		"healthcheck.timeout":       3,
+		"healthcheck.success_count": 1,
+		"healthcheck.failure_count": 1,
+	}
+
+	for k := range healthCheckConfig {
+		strVal, ok := pool.Config[k]
+		if !ok {
+			continue
+		}
+
+		bitSize := 64
+		if k == "healthcheck.interval" || k == "healthcheck.timeout" {
+			bitSize = 63
+		}
+
+		// We accept uint64 values for health check settings as OVN allows setting such high values.
+		// However it's unlikely those are ever used in practice, so we accept converting using a slightly smaller bitSize
+		// so some of the settings fit into an int64 when converted to time.Duration.
+		healthCheckConfig[k], err = strconv.ParseUint(strVal, 10, bitSize)
+		if err != nil {
+			return nil, fmt.Errorf("Failed converting %q: %w", k, err)
+		}
+	}
+
+	return &loadBalancerHealthCheck{
+		interval:     time.Second * time.Duration(healthCheckConfig["healthcheck.interval"]),
+		timeout:      time.Second * time.Duration(healthCheckConfig["healthcheck.timeout"]),
+		successCount: healthCheckConfig["healthcheck.success_count"],
+		failureCount: healthCheckConfig["healthcheck.failure_count"],
+	}, nil
+}
+
+// loadBalancerPoolValidate validates the load balancer pool request.
+// It also tries to fetch and returns the pool from the database in case it already exists.
+func (n *common) loadBalancerPoolValidate(ctx context.Context, tx *db.ClusterTx, poolName string, pool api.NetworkLoadBalancerPoolPut) (*dbCluster.NetworksLoadBalancerPool, error) {
+	var loadBalancerPoolDB *dbCluster.NetworksLoadBalancerPool
+
+	// Validate the pool names under the same constraints present for network names.
+	err := n.ValidateName(poolName)
+	if err != nil {
+		return nil, api.NewStatusError(http.StatusBadRequest, err.Error())
+	}
+
+	var allProjectInstances []string
+
+	// Fetch all instances in the current project.
+	// Do this before returning an error if the pool doesn't exist.
+	// This ensures the project instances are always loaded for validation.
+	allProjectInstances, err = tx.GetInstanceNames(ctx, n.project)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate if the pool exists.
+	loadBalancerPoolDB, err = dbCluster.GetNetworksLoadBalancerPool(ctx, tx.Tx(), n.ID(), poolName)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return nil, err
+	}
+
+	// Validate if the instances exist in the current project.
+	for _, instance := range pool.Instances {
+		if !slices.Contains(allProjectInstances, instance.Name) {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Instance %q does not exist in project %q", instance.Name, n.project)
+		}
+
+		// Setting the target port on an instance is optional.
+		// If unset it inherits the port from the parent pool.
+		if instance.TargetPort != "" {
+			// Validate target port.
+			err = validate.IsNetworkPort(instance.TargetPort)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	checkedFields := map[string]struct{}{}
+	rules := map[string]func(value string) error{
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=protocol)
+		// Can be either `tcp` or `udp`.
+		// ---
+		//  type: string
+		//  defaultdesc: `tcp`
+		//  required: no
+		//  shortdesc: Protocol used for ingress pool traffic.
+		"protocol": validate.Optional(validate.IsOneOf("tcp", "udp")),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=target_port)
+		//
+		// ---
+		//  type: string
+		//  required: yes
+		//  shortdesc: Port used on instances for ingress pool traffic
+		"target_port": validate.Required(validate.IsNetworkPort),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck)
+		//
+		// ---
+		//  type: bool
+		//  defaultdesc: `true`
+		//  required: no
+		//  shortdesc: Whether to enable or disable health checks
+		"healthcheck": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck.interval)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `5`
+		//  required: no
+		//  shortdesc: Interval in seconds between probes of the pool's instances.
+		"healthcheck.interval": validate.Optional(validate.IsUint64),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck.timeout)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `3`
+		//  required: no
+		//  shortdesc: Timeout in seconds after a probe appears to be faulty.
+		"healthcheck.timeout": validate.Optional(validate.IsUint64),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck.success_count)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `1`
+		//  required: no
+		//  shortdesc: Number of successful probe attempts after which an instance is considered healthy.
+		"healthcheck.success_count": validate.Optional(validate.IsUint64),
+		// lxdmeta:generate(entities=network-load-balancer-pool; group=properties; key=healthcheck.failure_count)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `1`
+		//  required: no
+		//  shortdesc: Number of failed probe attempts after which an instance is considered unhealthy.
+		"healthcheck.failure_count": validate.Optional(validate.IsUint64),
+	}
+
+	// Run the validator against each field.
+	for k, validator := range rules {
+		checkedFields[k] = struct{}{} // Mark field as checked.
+		err := validator(pool.Config[k])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for pool %q option %q: %w", poolName, k, err)
+		}
+	}
+
+	// Validate config fields.
+	for k := range pool.Config {
+		_, checked := checkedFields[k]
+		if checked {
+			continue
+		}
+
+		// User keys are not validated.
+		if config.IsUserConfig(k) {
+			continue
+		}
+
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid option %q", k)
+	}
+
+	return loadBalancerPoolDB, nil
+}
+
+// loadBalancerPoolCreate creates a network load balancer pool.
+func (n *common) loadBalancerPoolCreate(loadBalancerPool api.NetworkLoadBalancerPoolsPost) error {
+	// If no protocol is specified, default to "tcp".
+	if loadBalancerPool.Config["protocol"] == "" {
+		loadBalancerPool.Config["protocol"] = "tcp"
+	}
+
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		loadBalancerPoolDB, err := n.loadBalancerPoolValidate(ctx, tx, loadBalancerPool.Name, loadBalancerPool.NetworkLoadBalancerPoolPut)
+		if err != nil {
+			return err
+		}
+
+		if loadBalancerPoolDB != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Pool with name %q already exists on network %q", loadBalancerPool.Name, n.Name())
+		}
+
+		// Create load balancer pool DB record.
+		poolID, err := query.Create(ctx, tx.Tx(), dbCluster.NetworksLoadBalancerPoolRow{
+			NetworkID:   n.ID(),
+			Name:        loadBalancerPool.Name,
+			Description: loadBalancerPool.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Create load balancer pool config.
+		err = dbCluster.CreateNetworksLoadBalancerPoolConfig(ctx, tx.Tx(), poolID, loadBalancerPool.Config)
+		if err != nil {
+			return err
+		}
+
+		// Create load balancer pool instance records.
+		// The CLI does not make use of this but it ensures the API endpoint can be used to already add instances in a single request.
+		for _, instance := range loadBalancerPool.Instances {
+			err := n.loadBalancerPoolAddInstance(ctx, tx, poolID, instance)
+			if err != nil {
+				return fmt.Errorf("Failed adding instance %q to pool %q: %w", instance.Name, loadBalancerPool.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (n *common) loadBalancerPoolAddInstance(ctx context.Context, tx *db.ClusterTx, poolID int64, instance api.NetworkLoadBalancerPoolInstance) error {
+	// Fetch instance.
+	instanceID, err := tx.GetInstanceID(ctx, n.project, instance.Name)
+	if err != nil {
+		return err
+	}
+
+	targetPort := 0
+	if instance.TargetPort != "" {
+		targetPort, err = strconv.Atoi(instance.TargetPort)
+		if err != nil {
+			return fmt.Errorf("Failed parsing target port %q: %w", instance.TargetPort, err)
+		}
+	}
+
+	// Create load balancer pool instance DB record.
+	_, err = query.Create(ctx, tx.Tx(), dbCluster.NetworksLoadBalancerPoolInstanceRow{
+		PoolID:     poolID,
+		InstanceID: int64(instanceID),
+		TargetPort: int64(targetPort),
+	})
+	return err
+}
+
+func (n *common) loadBalancerPoolUpdateInstance(ctx context.Context, tx *db.ClusterTx, poolID int64, instance api.NetworkLoadBalancerPoolInstance) error {
+	// Fetch instance.
+	instanceID, err := tx.GetInstanceID(ctx, n.project, instance.Name)
+	if err != nil {
+		return err
+	}
+
+	targetPort := 0
+	if instance.TargetPort != "" {
+		targetPort, err = strconv.Atoi(instance.TargetPort)
+		if err != nil {
+			return fmt.Errorf("Failed parsing target port %q: %w", instance.TargetPort, err)
+		}
+	}
+
+	instanceDB := &dbCluster.NetworksLoadBalancerPoolInstanceRow{
+		PoolID:     poolID,
+		InstanceID: int64(instanceID),
+		TargetPort: int64(targetPort),
+	}
+
+	// Update load balancer pool instance DB record.
+	return dbCluster.UpdateNetworkLoadBalancerPoolInstanceRow(ctx, tx.Tx(), instanceDB)
+}
+
+func (n *common) loadBalancerPoolRemoveInstance(ctx context.Context, tx *db.ClusterTx, poolID int64, instanceName string) error {
+	// Fetch instance.
+	instanceID, err := tx.GetInstanceID(ctx, n.project, instanceName)
+	if err != nil {
+		return err
+	}
+
+	// Remove load balancer pool instance DB record.
+	return dbCluster.DeleteNetworksLoadBalancerPoolInstanceRow(ctx, tx.Tx(), poolID, int64(instanceID))
+}
+
+// loadBalancerPoolUpdate updates a network load balancer pool.
+// The updateLoadBalancer function is called to apply the pool changes to each of the load balancers using the pool,
+// and again with the original pool if anything fails.
+func (n *common) loadBalancerPoolUpdate(poolName string, loadBalancerPoolPut api.NetworkLoadBalancerPoolPut, updateLoadBalancer func(loadBalancer *api.NetworkLoadBalancer) error) error {
+	// Create two reverters.
+	// It's essential that the load balancer revert gets executed last.
+	// Therefore defer it first.
+	// Before updating (reverting) the load balancer, the database already needs to be cleaned up.
+	lbRevert := revert.New()
+	defer lbRevert.Fail()
+
+	dbRevert := revert.New()
+	defer dbRevert.Fail()
+
+	// Track whether or not the load balancer requires an update.
+	// Skip the update of the load balancers if it's a DB only update.
+	loadBalancerRequiresUpdate := false
+
+	var loadBalancerPoolDB *dbCluster.NetworksLoadBalancerPool
+	var loadBalancerPool *api.NetworkLoadBalancerPool
+
+	// Populated if pool requires an update of the parent load balancer(s).
+	var loadBalancers map[int64]*api.NetworkLoadBalancer
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		loadBalancerPoolDB, err = n.loadBalancerPoolValidate(ctx, tx, poolName, loadBalancerPoolPut)
+		if err != nil {
+			return err
+		}
+
+		if loadBalancerPoolDB == nil {
+			return api.StatusErrorf(http.StatusNotFound, "Pool with name %q does not exist on network %q", poolName, n.Name())
+		}
+
+		allConfigs, err := dbCluster.GetNetworksLoadBalancerPoolConfig(ctx, tx.Tx(), n.ID(), &loadBalancerPoolDB.Row.ID)
+		if err != nil {
+			return err
+		}
+
+		allInstances, err := dbCluster.GetNetworksLoadBalancerPoolInstances(ctx, tx.Tx(), &loadBalancerPoolDB.Row.ID)
+		if err != nil {
+			return err
+		}
+
+		loadBalancerPool, err = loadBalancerPoolDB.ToAPI(allConfigs, allInstances)
+		if err != nil {
+			return err
+		}
+
+		// Create simple list of instances currently set on the pool.
+		var poolInstances []string
+		for _, instance := range loadBalancerPool.Instances {
+			poolInstances = append(poolInstances, instance.Name)
+		}
+
+		// Check if list of instances requires an update.
+		for _, instance := range loadBalancerPoolPut.Instances {
+			// Handle new instances not present in the DB.
+			if !slices.Contains(poolInstances, instance.Name) {
+				loadBalancerRequiresUpdate = true
+
+				// Add instance to the pool.
+				// If the pool is currently referenced by a port, this requires modification of the load balancer.
+				// If the pool is unused, this only adds the instance in the database.
+				err := n.loadBalancerPoolAddInstance(ctx, tx, loadBalancerPoolDB.Row.ID, instance)
+				if err != nil {
+					return fmt.Errorf("Failed adding instance %q to pool %q: %w", instance.Name, poolName, err)
+				}
+			} else {
+				for _, instanceDB := range loadBalancerPool.Instances {
+					if instanceDB.Name == instance.Name && instanceDB.TargetPort != instance.TargetPort {
+						// Ensure the target port is up to date.
+						err := n.loadBalancerPoolUpdateInstance(ctx, tx, loadBalancerPoolDB.Row.ID, instance)
+						if err != nil {
+							return fmt.Errorf("Failed updating instance %q in pool %q: %w", instance.Name, poolName, err)
+						}
+
+						// Indicate the load balancers requires and update too.
+						loadBalancerRequiresUpdate = true
+					}
+				}
+			}
+		}
+
+		// Create simple list of instances requested to be on the pool.
+		var requestedPoolInstances []string
+		for _, instance := range loadBalancerPoolPut.Instances {
+			requestedPoolInstances = append(requestedPoolInstances, instance.Name)
+		}
+
+		// Check if list of DB instances requires an update.
+		for _, instance := range loadBalancerPool.Instances {
+			// Handle existing instances present in the DB.
+			if !slices.Contains(requestedPoolInstances, instance.Name) {
+				loadBalancerRequiresUpdate = true
+
+				// Remove instance from the pool.
+				err := n.loadBalancerPoolRemoveInstance(ctx, tx, loadBalancerPoolDB.Row.ID, instance.Name)
+				if err != nil {
+					return fmt.Errorf("Failed removing instance %q from pool %q: %w", instance.Name, poolName, err)
+				}
+			}
+		}
+
+		// If no protocol is specified, default to "tcp".
+		// This happens when the protocol gets unset.
+		if loadBalancerPoolPut.Config["protocol"] == "" {
+			loadBalancerPoolPut.Config["protocol"] = "tcp"
+		}
+
+		// Check if load balancer requires an update based on config changes.
+		for k, v := range loadBalancerPoolPut.Config {
+			if loadBalancerPool.Config[k] != v {
+				loadBalancerRequiresUpdate = true
+
+				// Stop checking further config options as the load balancer will require an update anyway.
+				break
+			}
+		}
+
+		// Check if any config options got removed which means the defaults should be applied.
+		if len(loadBalancerPool.Config) != len(loadBalancerPoolPut.Config) {
+			loadBalancerRequiresUpdate = true
+		}
+
+		// Update the pool description and config.
+		poolDBNew := &dbCluster.NetworksLoadBalancerPoolRow{
+			ID:          loadBalancerPoolDB.Row.ID,
+			NetworkID:   loadBalancerPoolDB.Row.NetworkID,
+			Name:        loadBalancerPoolDB.Row.Name,
+			Description: loadBalancerPoolPut.Description,
+		}
+
+		err = dbCluster.UpdateNetworksLoadBalancerPool(ctx, tx.Tx(), poolDBNew, loadBalancerPoolPut.Config)
+		if err != nil {
+			return err
+		}
+
+		// Fetch a list of parent load balancers that might require an update.
+		if loadBalancerRequiresUpdate {
+			loadBalancers, err = tx.GetNetworkLoadBalancers(ctx, n.ID(), false)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	dbRevert.Add(func() {
+		_ = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.UpdateNetworksLoadBalancerPool(ctx, tx.Tx(), &loadBalancerPoolDB.Row, loadBalancerPool.Config)
+		})
+	})
+
+	// Update the parent load balancer in case the pool was modified.
+	for _, loadBalancer := range loadBalancers {
+		for _, port := range loadBalancer.Ports {
+			if port.TargetPool == poolName {
+				// If it returns an error here, updateLoadBalancer takes care of reverting the changes.
+				err = updateLoadBalancer(loadBalancer)
+				if err != nil {
+					return fmt.Errorf("Failed updating load balancer %q: %w", loadBalancer.ListenAddress, err)
+				}
+
+				// If something fails, trigger an update (revert) of this load balancer.
+				// This requires that the DB is already reverted.
+				lbRevert.Add(func() {
+					_ = updateLoadBalancer(loadBalancer)
+				})
+
+				// If the pool is used by multiple ports of the same load balancer, continue if it got updated already.
+				break
+			}
+		}
+	}
+
+	lbRevert.Success()
+	dbRevert.Success()
+	return nil
+}
+
+// loadBalancerPoolDelete deletes a network load balancer pool.
+func (n *common) loadBalancerPoolDelete(poolName string) error {
+	var allLoadBalancers map[string][]string
+
+	// Check if the pool is still referenced by any load balancer port.
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		// Get all load balancers referencing the pool with any of their ports.
+		allLoadBalancers, err = dbCluster.GetNetworksLoadBalancersByPool(ctx, tx.Tx(), n.ID(), &poolName)
+		if err != nil {
+			return fmt.Errorf("Failed getting load balancers for network %q: %w", n.Name(), err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(allLoadBalancers) > 0 {
+		return api.StatusErrorf(http.StatusBadRequest, "Pool %q is still referenced by at least one load balancer port", poolName)
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Try to delete the pool.
+		// If it doesn't exist a not found error is returned.
+		return dbCluster.DeleteNetworksLoadBalancerPool(ctx, tx.Tx(), n.ID(), poolName)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getLoadBalancerPool returns a load balancer pool by its name.
+func (n *common) getLoadBalancerPool(ctx context.Context, tx *sql.Tx, poolName string) (*api.NetworkLoadBalancerPool, error) {
+	poolDB, err := dbCluster.GetNetworksLoadBalancerPool(ctx, tx, n.ID(), poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	allConfigs, err := dbCluster.GetNetworksLoadBalancerPoolConfig(ctx, tx, n.ID(), &poolDB.Row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	allInstances, err := dbCluster.GetNetworksLoadBalancerPoolInstances(ctx, tx, &poolDB.Row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return poolDB.ToAPI(allConfigs, allInstances)
+}
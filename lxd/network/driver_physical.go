@@ -191,6 +191,56 @@ func (n *physical) Validate(config map[string]string) error {
 	//  required: no
 	//  shortdesc: Peer session hold time
 	//  scope: global
+
+	// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.peers.NAME.max_prefixes)
+	// When the peer advertises more prefixes than this limit, the session is closed.
+	// ---
+	//  type: integer
+	//  condition: BGP server
+	//  defaultdesc: (no limit)
+	//  required: no
+	//  shortdesc: Maximum number of prefixes accepted from the peer
+	//  scope: global
+
+	// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.import)
+	// When enabled, the routes learned from the BGP peers are installed in the routing table set in `bgp.import.table` or `bgp.import.vrf`.
+	// ---
+	//  type: bool
+	//  condition: BGP server
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to install routes learned from BGP peers
+	//  scope: global
+
+	// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.import.table)
+	// Specify `main` or the numeric ID of a routing table.
+	// This option cannot be set together with `bgp.import.vrf`.
+	// ---
+	//  type: string
+	//  condition: BGP route import
+	//  defaultdesc: `main`
+	//  shortdesc: Routing table in which learned routes are installed
+	//  scope: global
+
+	// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.import.vrf)
+	// The learned routes are installed in the routing table of the VRF interface.
+	// This option cannot be set together with `bgp.import.table`.
+	// ---
+	//  type: string
+	//  condition: BGP route import
+	//  shortdesc: VRF in which learned routes are installed
+	//  scope: global
+
+	// lxdmeta:generate(entities=network-physical; group=network-conf; key=bgp.import.prefixes)
+	// Specify a comma-separated list of CIDR subnets.
+	// Only the learned routes for prefixes within these subnets are installed.
+	// This option must be set when route import is enabled.
+	// Default routes and routes within the subnets of the host or of LXD managed networks are never installed.
+	// ---
+	//  type: string
+	//  condition: BGP route import
+	//  required: yes
+	//  shortdesc: Prefixes of the learned routes to install
+	//  scope: global
 	bgpRules, err := n.bgpValidationRules(config)
 	if err != nil {
 		return err
@@ -608,5 +658,7 @@ func (n *physical) State() (*api.NetworkState, error) {
 		return nil, err
 	}
 
+	state.BGP = n.bgpState()
+
 	return state, nil
 }
@@ -21,6 +21,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/canonical/lxd/lxd/bgp"
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
@@ -1295,6 +1296,44 @@ func SubnetParseAppend(subnets []*net.IPNet, parseSubnet ...string) ([]*net.IPNe
 	return subnets, nil
 }
 
+// networkSubnets returns the subnets used by a network, from its addresses, gateways and routes.
+func networkSubnets(config map[string]string) []*net.IPNet {
+	subnets := []*net.IPNet{}
+	for _, key := range []string{"ipv4.address", "ipv6.address", "ipv4.gateway", "ipv6.gateway", "ipv4.routes", "ipv6.routes"} {
+		for _, value := range shared.SplitNTrimSpace(config[key], ",", -1, true) {
+			_, subnet, err := net.ParseCIDR(value)
+			if err != nil {
+				continue // Skip values such as "none" and "auto".
+			}
+
+			subnets = append(subnets, subnet)
+		}
+	}
+
+	return subnets
+}
+
+// hostSubnets returns the subnets of the addresses configured on the host's interfaces.
+func hostSubnets() []*net.IPNet {
+	subnets := []*net.IPNet{}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return subnets
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+
+		subnets = append(subnets, &net.IPNet{IP: ipNet.IP.Mask(ipNet.Mask), Mask: ipNet.Mask})
+	}
+
+	return subnets
+}
+
 // InterfaceStatus returns the global unicast IP addresses configured on an interface and whether it is up or not.
 func InterfaceStatus(nicName string) ([]net.IP, bool, error) {
 	iface, err := net.InterfaceByName(nicName)
@@ -1630,3 +1669,62 @@ func ipInRanges(ipAddr net.IP, ipRanges []shared.IPRange) bool {
 
 	return false
 }
+
+// BGPPathAttributesValidationRules returns the validation rules for the keys setting the attributes of the
+// prefixes advertised over BGP.
+func BGPPathAttributesValidationRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		"bgp.communities": validate.Optional(validate.IsListOf(func(value string) error {
+			_, err := bgp.ParseCommunity(value)
+			return err
+		})),
+		"bgp.large_communities": validate.Optional(validate.IsListOf(func(value string) error {
+			_, err := bgp.ParseLargeCommunity(value)
+			return err
+		})),
+		"bgp.med":             validate.Optional(validate.IsUint32),
+		"bgp.as_path_prepend": validate.Optional(validate.IsInRange(0, bgp.MaxASPathPrepend)),
+	}
+}
+
+// BGPPathAttributes returns the attributes of the prefixes advertised over BGP.
+// Each key is taken from the first config setting it, so that more specific configs can override the network's.
+func BGPPathAttributes(configs ...map[string]string) (bgp.PathAttributes, error) {
+	value := func(key string) string {
+		for _, config := range configs {
+			if config[key] != "" {
+				return config[key]
+			}
+		}
+
+		return ""
+	}
+
+	attrs := bgp.PathAttributes{
+		Communities:      shared.SplitNTrimSpace(value("bgp.communities"), ",", -1, true),
+		LargeCommunities: shared.SplitNTrimSpace(value("bgp.large_communities"), ",", -1, true),
+	}
+
+	med := value("bgp.med")
+	if med != "" {
+		medValue, err := strconv.ParseUint(med, 10, 32)
+		if err != nil {
+			return attrs, fmt.Errorf("Invalid BGP MED %q: %w", med, err)
+		}
+
+		medUint32 := uint32(medValue)
+		attrs.MED = &medUint32
+	}
+
+	prepend := value("bgp.as_path_prepend")
+	if prepend != "" {
+		prependValue, err := strconv.ParseUint(prepend, 10, 32)
+		if err != nil {
+			return attrs, fmt.Errorf("Invalid BGP AS path prepend %q: %w", prepend, err)
+		}
+
+		attrs.ASPathPrepend = uint32(prependValue)
+	}
+
+	return attrs, nil
+}
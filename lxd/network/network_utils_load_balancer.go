@@ -0,0 +1,293 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Health statuses of load balancer pool targets probed from the host.
+// These match the statuses reported by OVN service monitors.
+const (
+	loadBalancerTargetStatusPending = "pending"
+	loadBalancerTargetStatusOnline  = "online"
+	loadBalancerTargetStatusOffline = "offline"
+	loadBalancerTargetStatusUnknown = "unknown"
+)
+
+// loadBalancerHealthRefreshInterval is how often the load balancers of a network are refreshed while its pool
+// targets are monitored. This picks up instance address changes and pool changes made on other cluster members.
+const loadBalancerHealthRefreshInterval = 30 * time.Second
+
+// loadBalancerHealthTarget identifies a load balancer pool target probed from the host.
+type loadBalancerHealthTarget struct {
+	protocol string
+	address  string
+	port     uint64
+}
+
+// loadBalancerHealthProbe tracks the health of a single load balancer pool target.
+type loadBalancerHealthProbe struct {
+	healthCheck loadBalancerHealthCheck
+	status      string
+	cancel      context.CancelFunc
+}
+
+// loadBalancerHealthMonitor probes the load balancer pool targets of a network from the host.
+// The refresh function is called whenever the status of a target changes and periodically while the monitor runs.
+type loadBalancerHealthMonitor struct {
+	mu      sync.Mutex
+	probes  map[loadBalancerHealthTarget]*loadBalancerHealthProbe
+	refresh func()
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	// The load balancer rules last applied to the firewall, used to skip refreshes that change nothing.
+	appliedRules string
+}
+
+var loadBalancerHealthMonitors = make(map[int64]*loadBalancerHealthMonitor)
+var loadBalancerHealthMonitorsMu sync.Mutex
+
+// loadBalancerHealthMonitorGet returns the health monitor of a network or nil if it isn't running.
+func loadBalancerHealthMonitorGet(networkID int64) *loadBalancerHealthMonitor {
+	loadBalancerHealthMonitorsMu.Lock()
+	defer loadBalancerHealthMonitorsMu.Unlock()
+
+	return loadBalancerHealthMonitors[networkID]
+}
+
+// loadBalancerHealthMonitorStart returns the health monitor of a network, starting it if it isn't running.
+func loadBalancerHealthMonitorStart(networkID int64, refresh func()) *loadBalancerHealthMonitor {
+	loadBalancerHealthMonitorsMu.Lock()
+	defer loadBalancerHealthMonitorsMu.Unlock()
+
+	m := loadBalancerHealthMonitors[networkID]
+	if m != nil {
+		m.mu.Lock()
+		m.refresh = refresh
+		m.mu.Unlock()
+
+		return m
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m = &loadBalancerHealthMonitor{
+		probes:  make(map[loadBalancerHealthTarget]*loadBalancerHealthProbe),
+		refresh: refresh,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	loadBalancerHealthMonitors[networkID] = m
+
+	go func() {
+		ticker := time.NewTicker(loadBalancerHealthRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.runRefresh()
+			}
+		}
+	}()
+
+	return m
+}
+
+// loadBalancerHealthMonitorStop stops the health monitor of a network and all of its probes.
+func loadBalancerHealthMonitorStop(networkID int64) {
+	loadBalancerHealthMonitorsMu.Lock()
+	defer loadBalancerHealthMonitorsMu.Unlock()
+
+	m := loadBalancerHealthMonitors[networkID]
+	if m == nil {
+		return
+	}
+
+	m.cancel()
+	delete(loadBalancerHealthMonitors, networkID)
+}
+
+// runRefresh calls the refresh function unless the monitor has been stopped.
+func (m *loadBalancerHealthMonitor) runRefresh() {
+	m.mu.Lock()
+	refresh := m.refresh
+	m.mu.Unlock()
+
+	if m.ctx.Err() == nil && refresh != nil {
+		refresh()
+	}
+}
+
+// setTargets starts probing new targets and stops probing targets which are no longer in use.
+// Targets whose health check settings changed are probed again from scratch.
+func (m *loadBalancerHealthMonitor) setTargets(targets map[loadBalancerHealthTarget]loadBalancerHealthCheck) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for target, probe := range m.probes {
+		healthCheck, ok := targets[target]
+		if ok && healthCheck == probe.healthCheck {
+			continue
+		}
+
+		probe.cancel()
+		delete(m.probes, target)
+	}
+
+	for target, healthCheck := range targets {
+		_, ok := m.probes[target]
+		if ok {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(m.ctx)
+		probe := &loadBalancerHealthProbe{
+			healthCheck: healthCheck,
+			status:      loadBalancerTargetStatusPending,
+			cancel:      cancel,
+		}
+
+		m.probes[target] = probe
+
+		go m.runProbe(ctx, target, probe)
+	}
+}
+
+// status returns the health status of a target or "unknown" if the target isn't probed.
+func (m *loadBalancerHealthMonitor) status(target loadBalancerHealthTarget) string {
+	if m == nil {
+		return loadBalancerTargetStatusUnknown
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	probe, ok := m.probes[target]
+	if !ok {
+		return loadBalancerTargetStatusUnknown
+	}
+
+	return probe.status
+}
+
+// rulesChanged records the load balancer rules being applied and returns whether they differ from the
+// previously applied ones.
+func (m *loadBalancerHealthMonitor) rulesChanged(rules string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := m.appliedRules != rules
+	m.appliedRules = rules
+
+	return changed
+}
+
+// runProbe periodically checks the health of a target until its context is cancelled.
+// The refresh function is called whenever the target goes online or offline.
+func (m *loadBalancerHealthMonitor) runProbe(ctx context.Context, target loadBalancerHealthTarget, probe *loadBalancerHealthProbe) {
+	var successes, failures uint64
+
+	// Guard against intervals that are zero or overflowed when converted to a duration.
+	interval := probe.healthCheck.interval
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := loadBalancerProbeTarget(ctx, target, probe.healthCheck.timeout)
+		if ctx.Err() != nil {
+			return
+		}
+
+		newStatus := ""
+		if err == nil {
+			successes++
+			failures = 0
+
+			if successes >= probe.healthCheck.successCount {
+				newStatus = loadBalancerTargetStatusOnline
+			}
+		} else {
+			failures++
+			successes = 0
+
+			if failures >= probe.healthCheck.failureCount {
+				newStatus = loadBalancerTargetStatusOffline
+			}
+		}
+
+		m.mu.Lock()
+		changed := newStatus != "" && newStatus != probe.status
+		if changed {
+			probe.status = newStatus
+		}
+
+		m.mu.Unlock()
+
+		if changed {
+			logger.Debug("Load balancer target health changed", logger.Ctx{"protocol": target.protocol, "address": target.address, "port": target.port, "status": newStatus, "err": err})
+			m.runRefresh()
+		}
+	}
+}
+
+// loadBalancerProbeTarget checks whether a target accepts connections.
+// TCP targets must accept a connection within the timeout. As UDP is connectionless, UDP targets are only
+// considered failed if the target host rejects the probe datagram.
+func loadBalancerProbeTarget(ctx context.Context, target loadBalancerHealthTarget, timeout time.Duration) error {
+	dialer := net.Dialer{Timeout: timeout}
+	address := net.JoinHostPort(target.address, strconv.FormatUint(target.port, 10))
+
+	conn, err := dialer.DialContext(ctx, target.protocol, address)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	if target.protocol != "udp" {
+		return nil
+	}
+
+	err = conn.SetDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Write([]byte{0})
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Read(make([]byte, 1))
+	if err != nil {
+		// No reply within the timeout means the datagram wasn't rejected.
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
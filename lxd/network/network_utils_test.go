@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/canonical/lxd/lxd/cluster"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 )
@@ -253,3 +254,200 @@ func Example_complementRangesIP4() {
 	// Range2: 10.1.1.1-10.1.1.9, 10.1.1.101-10.1.1.199, 10.1.1.231-10.1.1.255
 	// Range3: 10.1.1.1-10.1.1.9, 10.1.1.26-10.1.1.255
 }
+
+func TestBGPPathAttributes(t *testing.T) {
+	netConfig := map[string]string{
+		"bgp.communities":     "65000:1, 65000:2",
+		"bgp.med":             "100",
+		"bgp.as_path_prepend": "2",
+	}
+
+	nicConfig := map[string]string{
+		"bgp.communities":       "65000:10",
+		"bgp.large_communities": "65000:1:1",
+	}
+
+	attrs, err := BGPPathAttributes(netConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"65000:1", "65000:2"}, attrs.Communities)
+	assert.Nil(t, attrs.LargeCommunities)
+	require.NotNil(t, attrs.MED)
+	assert.Equal(t, uint32(100), *attrs.MED)
+	assert.Equal(t, uint32(2), attrs.ASPathPrepend)
+
+	// The first config setting a key takes precedence.
+	attrs, err = BGPPathAttributes(nicConfig, netConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"65000:10"}, attrs.Communities)
+	assert.Equal(t, []string{"65000:1:1"}, attrs.LargeCommunities)
+	assert.Equal(t, uint32(100), *attrs.MED)
+
+	// No attributes are set by default.
+	attrs, err = BGPPathAttributes(map[string]string{})
+	require.NoError(t, err)
+	assert.Nil(t, attrs.MED)
+	assert.Zero(t, attrs.ASPathPrepend)
+
+	_, err = BGPPathAttributes(map[string]string{"bgp.med": "-1"})
+	assert.Error(t, err)
+
+	rules := BGPPathAttributesValidationRules()
+	assert.NoError(t, rules["bgp.communities"]("65000:1,no-export"))
+	assert.Error(t, rules["bgp.communities"]("65000:1,foo"))
+	assert.NoError(t, rules["bgp.large_communities"]("4200000000:1:2"))
+	assert.Error(t, rules["bgp.large_communities"]("65000:1"))
+	assert.Error(t, rules["bgp.as_path_prepend"]("17"))
+}
+
+func Test_networkSubnets(t *testing.T) {
+	subnets := networkSubnets(map[string]string{
+		"ipv4.address": "10.0.0.1/24",
+		"ipv6.address": "none",
+		"ipv4.gateway": "192.0.2.1/24",
+		"ipv6.routes":  "2001:db8:1::/64, 2001:db8:2::/64",
+	})
+
+	got := make([]string, 0, len(subnets))
+	for _, subnet := range subnets {
+		got = append(got, subnet.String())
+	}
+
+	assert.Equal(t, []string{"10.0.0.0/24", "192.0.2.0/24", "2001:db8:1::/64", "2001:db8:2::/64"}, got)
+}
+
+func Test_wireguardPublicKey(t *testing.T) {
+	// Key pair from RFC 7748 section 6.1.
+	privateKey := "dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo="
+
+	publicKey, err := wireguardPublicKey(privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo=", publicKey)
+
+	_, err = wireguardPublicKey("foo")
+	assert.Error(t, err)
+
+	generatedKey, err := wireguardGenerateKey()
+	require.NoError(t, err)
+
+	_, err = wireguardPublicKey(generatedKey)
+	assert.NoError(t, err)
+}
+
+func Test_wireguardMemberSubnet(t *testing.T) {
+	tests := []struct {
+		overlay string
+		index   int64
+		want    string
+		wantErr bool
+	}{
+		{
+			overlay: "10.100.0.0/16",
+			index:   0,
+			want:    "10.100.0.0/24",
+		},
+		{
+			overlay: "10.100.0.0/16",
+			index:   1,
+			want:    "10.100.1.0/24",
+		},
+		{
+			overlay: "10.100.0.0/16",
+			index:   255,
+			want:    "10.100.255.0/24",
+		},
+		{
+			overlay: "10.0.0.0/8",
+			index:   300,
+			want:    "10.1.44.0/24",
+		},
+		{
+			overlay: "10.100.0.0/16",
+			index:   256,
+			wantErr: true,
+		},
+		{
+			overlay: "10.100.0.0/23",
+			index:   2,
+			wantErr: true,
+		},
+		{
+			overlay: "10.100.0.0/25",
+			index:   0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s_%d", tt.overlay, tt.index), func(t *testing.T) {
+			_, overlay, err := net.ParseCIDR(tt.overlay)
+			require.NoError(t, err)
+
+			subnet, err := wireguardMemberSubnet(overlay, tt.index)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, subnet.String())
+		})
+	}
+}
+
+func Test_wireguardAllocateIndex(t *testing.T) {
+	assert.Equal(t, int64(0), wireguardAllocateIndex(nil))
+	assert.Equal(t, int64(1), wireguardAllocateIndex(map[int64]string{1: "0", 5: "2"}))
+	assert.Equal(t, int64(3), wireguardAllocateIndex(map[int64]string{1: "0", 2: "1", 3: "2"}))
+	assert.Equal(t, int64(0), wireguardAllocateIndex(map[int64]string{2: "1", 3: "foo"}))
+}
+
+func Test_wireguardMembersFingerprint(t *testing.T) {
+	members := map[int64]cluster.APIHeartbeatMember{
+		1: {ID: 1, Address: "10.0.0.1:8443"},
+		2: {ID: 2, Address: "10.0.0.2:8443", LastHeartbeat: time.Now()},
+	}
+
+	fingerprint := wireguardMembersFingerprint(members)
+	assert.Equal(t, "1=10.0.0.1:8443,2=10.0.0.2:8443", fingerprint)
+
+	// Heartbeat times don't change the fingerprint.
+	members[2] = cluster.APIHeartbeatMember{ID: 2, Address: "10.0.0.2:8443"}
+	assert.Equal(t, fingerprint, wireguardMembersFingerprint(members))
+
+	// Address and membership changes do.
+	members[2] = cluster.APIHeartbeatMember{ID: 2, Address: "10.0.0.3:8443"}
+	assert.NotEqual(t, fingerprint, wireguardMembersFingerprint(members))
+
+	delete(members, 2)
+	assert.NotEqual(t, fingerprint, wireguardMembersFingerprint(members))
+}
+
+func Test_wireguardConfig(t *testing.T) {
+	_, subnet1, _ := net.ParseCIDR("10.100.1.0/24")
+	_, subnet2, _ := net.ParseCIDR("10.100.2.0/24")
+
+	peers := []wireguardPeer{
+		{PublicKey: "key2", Endpoint: "[2001:db8::2]:51820", Subnet: subnet2},
+		{PublicKey: "key1", Endpoint: "192.0.2.1:51820", Subnet: subnet1},
+	}
+
+	want := `[Interface]
+PrivateKey = private
+ListenPort = 51820
+
+[Peer]
+PublicKey = key1
+Endpoint = 192.0.2.1:51820
+AllowedIPs = 10.100.1.0/24
+PersistentKeepalive = 25
+
+[Peer]
+PublicKey = key2
+Endpoint = [2001:db8::2]:51820
+AllowedIPs = 10.100.2.0/24
+PersistentKeepalive = 25
+`
+
+	assert.Equal(t, want, wireguardConfig("private\n", 51820, peers))
+	assert.Equal(t, "key2", peers[0].PublicKey)
+}
@@ -0,0 +1,126 @@
+package network
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/cluster"
+)
+
+// wireguardPeersMembers holds, per network ID, the cluster members fingerprint of the last peers sync that
+// configured all the other cluster members.
+var wireguardPeersMembers = make(map[int64]string)
+var wireguardPeersMembersMu sync.Mutex
+
+// wireguardPeer represents a WireGuard peer of a bridge in WireGuard mode.
+type wireguardPeer struct {
+	PublicKey string
+	Endpoint  string
+	Subnet    *net.IPNet
+}
+
+// wireguardGenerateKey generates a new base64 encoded WireGuard private key.
+func wireguardGenerateKey() (string, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("Failed generating WireGuard private key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key.Bytes()), nil
+}
+
+// wireguardPublicKey returns the base64 encoded WireGuard public key of a base64 encoded private key.
+func wireguardPublicKey(privateKey string) (string, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil {
+		return "", fmt.Errorf("Failed decoding WireGuard private key: %w", err)
+	}
+
+	key, err := ecdh.X25519().NewPrivateKey(keyBytes)
+	if err != nil {
+		return "", fmt.Errorf("Invalid WireGuard private key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// wireguardAllocateIndex returns the lowest member index not already used in the given member indexes.
+func wireguardAllocateIndex(indexes map[int64]string) int64 {
+	used := make(map[int64]bool, len(indexes))
+	for _, value := range indexes {
+		index, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			used[index] = true
+		}
+	}
+
+	var index int64
+	for used[index] {
+		index++
+	}
+
+	return index
+}
+
+// wireguardMembersFingerprint returns a string identifying the IDs and addresses of the given cluster members.
+func wireguardMembersFingerprint(members map[int64]cluster.APIHeartbeatMember) string {
+	entries := make([]string, 0, len(members))
+	for _, member := range members {
+		entries = append(entries, strconv.FormatInt(member.ID, 10)+"="+member.Address)
+	}
+
+	slices.Sort(entries)
+
+	return strings.Join(entries, ",")
+}
+
+// wireguardMemberSubnet returns the /24 subnet of the overlay subnet used by the cluster member with the given index.
+func wireguardMemberSubnet(overlaySubnet *net.IPNet, index int64) (*net.IPNet, error) {
+	overlaySize, _ := overlaySubnet.Mask.Size()
+	if overlaySize > 24 {
+		return nil, fmt.Errorf("Overlay subnet %q is too small", overlaySubnet.String())
+	}
+
+	if index < 0 || index >= int64(1)<<(24-overlaySize) {
+		return nil, fmt.Errorf("Overlay subnet %q is too small for cluster member index %d", overlaySubnet.String(), index)
+	}
+
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(overlaySubnet.IP.To4())+uint32(index)<<8)
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(24, 32)}, nil
+}
+
+// wireguardConfig renders the WireGuard configuration of an interface with the given peers, in the format
+// expected by "wg syncconf".
+func wireguardConfig(privateKey string, port int, peers []wireguardPeer) string {
+	var sb strings.Builder
+
+	sb.WriteString("[Interface]\n")
+	fmt.Fprintf(&sb, "PrivateKey = %s\n", strings.TrimSpace(privateKey))
+	fmt.Fprintf(&sb, "ListenPort = %d\n", port)
+
+	// Sort the peers so the configuration is stable.
+	peers = slices.Clone(peers)
+	slices.SortFunc(peers, func(a wireguardPeer, b wireguardPeer) int {
+		return strings.Compare(a.PublicKey, b.PublicKey)
+	})
+
+	for _, peer := range peers {
+		sb.WriteString("\n[Peer]\n")
+		fmt.Fprintf(&sb, "PublicKey = %s\n", peer.PublicKey)
+		fmt.Fprintf(&sb, "Endpoint = %s\n", peer.Endpoint)
+		fmt.Fprintf(&sb, "AllowedIPs = %s\n", peer.Subnet.String())
+		sb.WriteString("PersistentKeepalive = 25\n")
+	}
+
+	return sb.String()
+}
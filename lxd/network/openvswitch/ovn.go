@@ -5,6 +5,7 @@ import (
 	"encoding/csv"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
 	"os"
@@ -85,6 +86,7 @@ const ovnExtIDLXDSwitchPort = "lxd_switch_port"
 const ovnExtIDLXDProjectID = "lxd_project_id"
 const ovnExtIDLXDPortGroup = "lxd_port_group"
 const ovnExtIDLXDLocation = "lxd_location"
+const ovnExtIDLXDACLRule = "lxd_acl_rule"
 
 // OVNIPv6RAOpts IPv6 router advertisements options that can be applied to a router.
 type OVNIPv6RAOpts struct {
@@ -136,12 +138,13 @@ type OVNSwitchPortOpts struct {
 
 // OVNACLRule represents an ACL rule that can be added to a logical switch or port group.
 type OVNACLRule struct {
-	Direction string // Either "from-lport" or "to-lport".
-	Action    string // Either "allow-related", "allow", "drop", or "reject".
-	Match     string // Match criteria. See OVN Southbound database's Logical_Flow table match column usage.
-	Priority  int    // Priority (between 0 and 32767, inclusive). Higher values take precedence.
-	Log       bool   // Whether or not to log matched packets.
-	LogName   string // Log label name (requires Log be true).
+	Direction  string // Either "from-lport" or "to-lport".
+	Action     string // Either "allow-related", "allow", "drop", or "reject".
+	Match      string // Match criteria. See OVN Southbound database's Logical_Flow table match column usage.
+	Priority   int    // Priority (between 0 and 32767, inclusive). Higher values take precedence.
+	Log        bool   // Whether or not to log matched packets.
+	LogName    string // Log label name (requires Log be true).
+	Identifier string // Identifies the rule when gathering its counters (optional).
 }
 
 // OVNLoadBalancerTarget represents an OVN load balancer Virtual IP target.
@@ -1764,6 +1767,150 @@ func (o *OVN) PortGroupListByProject(projectID int64) ([]OVNPortGroup, error) {
 	return portGroups, nil
 }
 
+// PortGroupACLRuleFlowCookies returns the OpenFlow cookies of the flows implementing the ACL rules of the specified
+// port groups, keyed by the rule identifier. Rules without an identifier are not included.
+// The cookie of an OpenFlow flow installed by ovn-controller is the first 32 bits of its logical flow's UUID.
+func (o *OVN) PortGroupACLRuleFlowCookies(portGroupNames ...OVNPortGroup) (map[string][]uint64, error) {
+	cookies := make(map[string][]uint64)
+	if len(portGroupNames) == 0 {
+		return cookies, nil
+	}
+
+	args := []string{"--format=csv", "--no-headings", "--data=bare"}
+	for i, portGroupName := range portGroupNames {
+		if i > 0 {
+			args = append(args, "--")
+		}
+
+		args = append(args, "--columns=_uuid,external_ids", "find", "acl", "external_ids:"+ovnExtIDLXDPortGroup+"="+string(portGroupName))
+	}
+
+	output, err := o.nbctl(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing ACL rules: %w", err)
+	}
+
+	// Northd records the first 8 hex digits of the ACL rule UUID as the stage hint of the logical flows it
+	// generates for the rule.
+	stageHintRules := make(map[string]string)
+	for _, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("Unexpected column count %d in ACL rules output", len(record))
+		}
+
+		ruleID := ovnExternalIDs(record[1])[ovnExtIDLXDACLRule]
+		if ruleID == "" || len(record[0]) < 8 {
+			continue
+		}
+
+		stageHintRules[record[0][:8]] = ruleID
+	}
+
+	if len(stageHintRules) == 0 {
+		return cookies, nil
+	}
+
+	args = []string{"--format=csv", "--no-headings", "--data=bare"}
+	for i, stageHint := range slices.Sorted(maps.Keys(stageHintRules)) {
+		if i > 0 {
+			args = append(args, "--")
+		}
+
+		args = append(args, "--columns=_uuid,external_ids", "find", "logical_flow", "external_ids:stage-hint="+stageHint)
+	}
+
+	output, err = o.sbctl(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err = csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing logical flows: %w", err)
+	}
+
+	for _, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("Unexpected column count %d in logical flows output", len(record))
+		}
+
+		ruleID, found := stageHintRules[ovnExternalIDs(record[1])["stage-hint"]]
+		if !found || len(record[0]) < 8 {
+			continue
+		}
+
+		cookie, err := strconv.ParseUint(record[0][:8], 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid logical flow UUID %q: %w", record[0], err)
+		}
+
+		cookies[ruleID] = append(cookies[ruleID], cookie)
+	}
+
+	return cookies, nil
+}
+
+// LogicalSwitchTunnelKey returns the tunnel key of the southbound datapath of a logical switch.
+// This is the metadata value matched by the OpenFlow flows of the logical switch.
+func (o *OVN) LogicalSwitchTunnelKey(switchName OVNSwitch) (uint64, error) {
+	output, err := o.sbctl("--format=csv", "--no-headings", "--data=bare", "--columns=tunnel_key", "find", "datapath_binding", "external_ids:name="+string(switchName))
+	if err != nil {
+		return 0, err
+	}
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return 0, fmt.Errorf("Datapath of logical switch %q not found", switchName)
+	}
+
+	tunnelKey, err := strconv.ParseUint(output, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid tunnel key %q of logical switch %q: %w", output, switchName, err)
+	}
+
+	return tunnelKey, nil
+}
+
+// LogicalSwitchPortTunnelKey returns the tunnel key of the southbound port binding of a logical switch port.
+// This is the value matched by the OpenFlow flows of the logical switch on the logical input and output ports.
+// Returns zero if the port has no port binding.
+func (o *OVN) LogicalSwitchPortTunnelKey(portName OVNSwitchPort) (uint64, error) {
+	output, err := o.sbctl("--format=csv", "--no-headings", "--data=bare", "--columns=tunnel_key", "find", "port_binding", "logical_port="+string(portName))
+	if err != nil {
+		return 0, err
+	}
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return 0, nil
+	}
+
+	tunnelKey, err := strconv.ParseUint(output, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid tunnel key %q of logical switch port %q: %w", output, portName, err)
+	}
+
+	return tunnelKey, nil
+}
+
+// ovnExternalIDs parses an external_ids column output in bare data format.
+func ovnExternalIDs(output string) map[string]string {
+	externalIDs := make(map[string]string)
+	for _, field := range strings.Fields(output) {
+		key, value, found := strings.Cut(field, "=")
+		if found {
+			externalIDs[key] = strings.Trim(value, `"`)
+		}
+	}
+
+	return externalIDs
+}
+
 // PortGroupMemberChange adds/removes logical switch ports (by UUID) to/from existing port groups.
 func (o *OVN) PortGroupMemberChange(addMembers map[OVNPortGroup][]OVNSwitchPortUUID, removeMembers map[OVNPortGroup][]OVNSwitchPortUUID) error {
 	args := []string{}
@@ -1849,6 +1996,10 @@ func (o *OVN) aclRuleAddAppendArgs(args []string, entityTable string, entityName
 			args = append(args, "external_ids:"+k+"="+v)
 		}
 
+		if rule.Identifier != "" {
+			args = append(args, "external_ids:"+ovnExtIDLXDACLRule+"="+rule.Identifier)
+		}
+
 		// Add command to assign ACL rule to entity.
 		args = append(args, "--", "add", entityTable, entityName, "acl", "@id"+strconv.Itoa(i))
 	}
@@ -2575,6 +2726,64 @@ func (o *OVN) AddressSetDelete(addressSetPrefix OVNAddressSet) error {
 	return nil
 }
 
+// AddressSetReplace replaces the addresses of the address sets in the format "<addressSetPrefix>_ip<IP version>"
+// with the supplied addresses, creating the address sets if needed. This is done in a single transaction so that
+// ACL rules referencing the address sets never see them missing.
+func (o *OVN) AddressSetReplace(addressSetPrefix OVNAddressSet, addresses ...net.IPNet) error {
+	args := []string{
+		"--if-exists", "destroy", "address_set", fmt.Sprintf("%s_ip%d", addressSetPrefix, 4),
+		"--", "--if-exists", "destroy", "address_set", fmt.Sprintf("%s_ip%d", addressSetPrefix, 6),
+		"--", "create", "address_set", fmt.Sprintf("name=%s_ip%d", addressSetPrefix, 4),
+		"--", "create", "address_set", fmt.Sprintf("name=%s_ip%d", addressSetPrefix, 6),
+	}
+
+	for _, address := range addresses {
+		var ipVersion uint = 4
+		if address.IP.To4() == nil {
+			ipVersion = 6
+		}
+
+		args = append(args, "--", "add", "address_set", fmt.Sprintf("%s_ip%d", addressSetPrefix, ipVersion), "addresses", fmt.Sprintf(`"%s"`, address.String()))
+	}
+
+	_, err := o.nbctl(args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddressSetListByPrefix returns the prefixes of the address sets whose names start with the supplied prefix.
+// The returned prefixes have the "_ip<IP version>" suffix removed and can be used with the other address set functions.
+func (o *OVN) AddressSetListByPrefix(prefix string) ([]OVNAddressSet, error) {
+	output, err := o.nbctl("--format=csv", "--no-headings", "--data=bare", "--columns=name", "list", "address_set")
+	if err != nil {
+		return nil, err
+	}
+
+	addressSets := []OVNAddressSet{}
+	for _, name := range shared.SplitNTrimSpace(strings.TrimSpace(output), "\n", -1, true) {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		addressSetPrefix, found := strings.CutSuffix(name, "_ip4")
+		if !found {
+			addressSetPrefix, found = strings.CutSuffix(name, "_ip6")
+			if !found {
+				continue
+			}
+		}
+
+		if !slices.Contains(addressSets, OVNAddressSet(addressSetPrefix)) {
+			addressSets = append(addressSets, OVNAddressSet(addressSetPrefix))
+		}
+	}
+
+	return addressSets, nil
+}
+
 // LogicalRouterPolicyApply removes any existing policies and applies the new policies to the specified router.
 func (o *OVN) LogicalRouterPolicyApply(routerName OVNRouter, policies ...OVNRouterPolicy) error {
 	args := make([]string, 0, 2+6*len(policies))
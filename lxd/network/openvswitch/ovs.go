@@ -371,6 +371,98 @@ func (o *OVS) BridgePortList(bridgeName string) ([]string, error) {
 	return ports, nil
 }
 
+// OVSFlowStatistics represents the statistics of an OpenFlow flow.
+type OVSFlowStatistics struct {
+	Cookie        uint64
+	Metadata      uint64   // Zero if the flow does not match on metadata.
+	InPort        uint64   // Tunnel key of the logical input port matched by the flow (reg14), zero if none.
+	OutPort       uint64   // Tunnel key of the logical output port matched by the flow (reg15), zero if none.
+	ConjunctionID uint32   // Identifier of the conjunctive match of the flow (conj_id), zero if none.
+	Conjunctions  []uint32 // Identifiers of the conjunctive matches the flow only contributes to.
+	Packets       uint64
+	Bytes         uint64
+}
+
+// BridgeFlowStatistics returns the statistics of the OpenFlow flows of a bridge.
+// Flows that only contribute to a conjunctive match have their Conjunctions set and don't count packets, as the
+// packets are counted by the conjunctive flow.
+func (o *OVS) BridgeFlowStatistics(bridgeName string) ([]OVSFlowStatistics, error) {
+	output, err := shared.RunCommand(context.TODO(), "ovs-ofctl", "dump-flows", bridgeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFlowStatistics(output)
+}
+
+// parseFlowStatistics parses the output of ovs-ofctl dump-flows.
+func parseFlowStatistics(output string) ([]OVSFlowStatistics, error) {
+	flows := []OVSFlowStatistics{}
+
+	for line := range strings.SplitSeq(output, "\n") {
+		fields, actions, found := strings.Cut(strings.TrimSpace(line), " actions=")
+		if !found {
+			continue // Skip the reply header.
+		}
+
+		flow := OVSFlowStatistics{}
+
+		// Flows that only contribute to a conjunctive match have conjunction(id,k/n) actions.
+		for _, action := range strings.Split(actions, "conjunction(")[1:] {
+			conjID, _, _ := strings.Cut(action, ",")
+			id, err := strconv.ParseUint(conjID, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing conjunction of flow %q: %w", line, err)
+			}
+
+			flow.Conjunctions = append(flow.Conjunctions, uint32(id))
+		}
+
+		for _, field := range strings.FieldsFunc(fields, func(r rune) bool { return r == ',' || r == ' ' }) {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				continue
+			}
+
+			var err error
+			var conjID uint64
+
+			switch key {
+			case "cookie":
+				flow.Cookie, err = strconv.ParseUint(value, 0, 64)
+			case "metadata":
+				flow.Metadata, err = strconv.ParseUint(value, 0, 64)
+			case "reg14":
+				// Masked matches don't identify a single logical port.
+				if !strings.Contains(value, "/") {
+					flow.InPort, err = strconv.ParseUint(value, 0, 64)
+				}
+
+			case "reg15":
+				if !strings.Contains(value, "/") {
+					flow.OutPort, err = strconv.ParseUint(value, 0, 64)
+				}
+
+			case "conj_id":
+				conjID, err = strconv.ParseUint(value, 10, 32)
+				flow.ConjunctionID = uint32(conjID)
+			case "n_packets":
+				flow.Packets, err = strconv.ParseUint(value, 10, 64)
+			case "n_bytes":
+				flow.Bytes, err = strconv.ParseUint(value, 10, 64)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing %q of flow %q: %w", key, line, err)
+			}
+		}
+
+		flows = append(flows, flow)
+	}
+
+	return flows, nil
+}
+
 // HardwareOffloadingEnabled returns true if hardware offloading is enabled.
 func (o *OVS) HardwareOffloadingEnabled() bool {
 	// ovs-vsctl's get command doesn't support its --format flag, so we always get the output quoted.
@@ -0,0 +1,560 @@
+package zone
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/canonical/lxd/lxd/db"
+	lxdDNS "github.com/canonical/lxd/lxd/dns"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+const (
+	// dnssecFlagsZSK are the DNSKEY flags of a zone signing key.
+	dnssecFlagsZSK = 256
+
+	// dnssecFlagsKSK are the DNSKEY flags of a key signing key.
+	dnssecFlagsKSK = 257
+)
+
+// dnssecAlgorithm is the algorithm of the generated DNSSEC keys.
+const dnssecAlgorithm = dns.ECDSAP256SHA256
+
+// dnssecKeyTTL is the TTL of the DNSKEY records.
+const dnssecKeyTTL = 3600
+
+// dnssecSignatureValidity is how long the generated signatures are valid for.
+const dnssecSignatureValidity = 14 * 24 * time.Hour
+
+// dnssecRolloverDelay is how long a new zone signing key is published before being used, and how
+// long the key it replaces remains published afterwards, so that cached records remain valid.
+const dnssecRolloverDelay = 24 * time.Hour
+
+// dnssecDefaultZSKLifetime is the default number of days after which the zone signing key is replaced.
+const dnssecDefaultZSKLifetime = 30
+
+// dnssecKey is a loaded DNSSEC key.
+type dnssecKey struct {
+	info   db.NetworkZoneDNSSECKey
+	dnskey *dns.DNSKEY
+	signer crypto.Signer
+}
+
+// generateDNSSECKey generates a new DNSSEC key with the given flags, to be used from activateAt.
+func generateDNSSECKey(zoneName string, flags uint16, now time.Time, activateAt time.Time) (*db.NetworkZoneDNSSECKey, error) {
+	dnskey := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: dns.Fqdn(zoneName), Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: dnssecKeyTTL},
+		Flags:     flags,
+		Protocol:  3,
+		Algorithm: dnssecAlgorithm,
+	}
+
+	privateKey, err := dnskey.Generate(256)
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating DNSSEC key: %w", err)
+	}
+
+	return &db.NetworkZoneDNSSECKey{
+		Flags:      flags,
+		Algorithm:  dnssecAlgorithm,
+		PublicKey:  dnskey.PublicKey,
+		PrivateKey: dnskey.PrivateKeyString(privateKey),
+		CreatedAt:  now,
+		ActivateAt: activateAt,
+	}, nil
+}
+
+// loadDNSSECKey returns the DNSKEY record and signer of the stored key.
+func loadDNSSECKey(zoneName string, key db.NetworkZoneDNSSECKey) (*dnssecKey, error) {
+	dnskey := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: dns.Fqdn(zoneName), Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: dnssecKeyTTL},
+		Flags:     key.Flags,
+		Protocol:  3,
+		Algorithm: key.Algorithm,
+		PublicKey: key.PublicKey,
+	}
+
+	privateKey, err := dnskey.NewPrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading DNSSEC key %d: %w", dnskey.KeyTag(), err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported DNSSEC key %d", dnskey.KeyTag())
+	}
+
+	return &dnssecKey{info: key, dnskey: dnskey, signer: signer}, nil
+}
+
+// activeDNSSECKey returns the most recently activated key with the given flags.
+func activeDNSSECKey(keys []*dnssecKey, flags uint16, now time.Time) *dnssecKey {
+	var active *dnssecKey
+	for _, key := range keys {
+		if key.info.Flags != flags || key.info.ActivateAt.After(now) {
+			continue
+		}
+
+		if active == nil || key.info.ActivateAt.After(active.info.ActivateAt) {
+			active = key
+		}
+	}
+
+	return active
+}
+
+// signZone returns the zone records along with the DNSKEY, NSEC and RRSIG records of the zone.
+// The records must start with the SOA record of the zone. The returned records are in canonical order,
+// starting with the signed SOA record.
+func signZone(zoneName string, records []dns.RR, storedKeys []db.NetworkZoneDNSSECKey, now time.Time) ([]dns.RR, error) {
+	apex := strings.ToLower(dns.Fqdn(zoneName))
+
+	if len(records) == 0 || records[0].Header().Rrtype != dns.TypeSOA {
+		return nil, errors.New("Zone content does not start with a SOA record")
+	}
+
+	soa, _ := records[0].(*dns.SOA)
+
+	keys := make([]*dnssecKey, 0, len(storedKeys))
+	for _, storedKey := range storedKeys {
+		key, err := loadDNSSECKey(zoneName, storedKey)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	ksk := activeDNSSECKey(keys, dnssecFlagsKSK, now)
+	zsk := activeDNSSECKey(keys, dnssecFlagsZSK, now)
+	if ksk == nil || zsk == nil {
+		return nil, errors.New("No active DNSSEC keys")
+	}
+
+	// Group the records into RRsets, skipping the SOA duplicated at the end of the zone content and
+	// any DNSSEC records, which are generated below.
+	type rrsetKey struct {
+		owner  string
+		rrtype uint16
+	}
+
+	rrsets := map[rrsetKey][]dns.RR{}
+	owners := map[string][]uint16{}
+
+	addRecord := func(rr dns.RR) {
+		hdr := rr.Header()
+		hdr.Name = strings.ToLower(hdr.Name)
+
+		key := rrsetKey{owner: hdr.Name, rrtype: hdr.Rrtype}
+		if rrsets[key] == nil {
+			owners[hdr.Name] = append(owners[hdr.Name], hdr.Rrtype)
+		}
+
+		for _, existing := range rrsets[key] {
+			if dns.IsDuplicate(existing, rr) {
+				return
+			}
+		}
+
+		rrsets[key] = append(rrsets[key], rr)
+	}
+
+	for _, rr := range records {
+		switch rr.Header().Rrtype {
+		case dns.TypeRRSIG, dns.TypeNSEC, dns.TypeNSEC3, dns.TypeNSEC3PARAM, dns.TypeDNSKEY:
+			continue
+		case dns.TypeSOA:
+			if strings.EqualFold(rr.Header().Name, apex) && rrsets[rrsetKey{owner: apex, rrtype: dns.TypeSOA}] != nil {
+				continue
+			}
+		}
+
+		if !dns.IsSubDomain(apex, strings.ToLower(rr.Header().Name)) {
+			return nil, fmt.Errorf("Record %q is outside of zone %q", rr.Header().Name, apex)
+		}
+
+		addRecord(dns.Copy(rr))
+	}
+
+	for _, key := range keys {
+		addRecord(dns.Copy(key.dnskey))
+	}
+
+	// Find the delegation points, below which records are glue and aren't signed.
+	var delegations []string
+	for owner, types := range owners {
+		if owner != apex && slices.Contains(types, dns.TypeNS) {
+			delegations = append(delegations, owner)
+		}
+	}
+
+	isGlue := func(owner string) bool {
+		for _, delegation := range delegations {
+			if owner != delegation && dns.IsSubDomain(delegation, owner) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// Sort the owner names in canonical order.
+	names := make([]string, 0, len(owners))
+	for owner := range owners {
+		names = append(names, owner)
+	}
+
+	slices.SortFunc(names, func(a string, b string) int {
+		if a == b {
+			return 0
+		}
+
+		if lxdDNS.CanonicalNameLess(a, b) {
+			return -1
+		}
+
+		return 1
+	})
+
+	// Build the NSEC chain over the authoritative names.
+	authoritative := slices.DeleteFunc(slices.Clone(names), isGlue)
+	negativeTTL := min(soa.Hdr.Ttl, soa.Minttl)
+
+	for i, owner := range authoritative {
+		types := append(slices.Clone(owners[owner]), dns.TypeNSEC, dns.TypeRRSIG)
+		slices.Sort(types)
+
+		addRecord(&dns.NSEC{
+			Hdr:        dns.RR_Header{Name: owner, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: negativeTTL},
+			NextDomain: authoritative[(i+1)%len(authoritative)],
+			TypeBitMap: types,
+		})
+	}
+
+	// Sign the RRsets.
+	signed := make([]dns.RR, 0, len(records)*3)
+	for _, owner := range names {
+		types := slices.Clone(owners[owner])
+		slices.Sort(types)
+
+		// Keep the SOA record first.
+		if owner == apex {
+			types = slices.DeleteFunc(types, func(t uint16) bool { return t == dns.TypeSOA })
+			types = append([]uint16{dns.TypeSOA}, types...)
+		}
+
+		for _, rrtype := range types {
+			rrset := rrsets[rrsetKey{owner: owner, rrtype: rrtype}]
+
+			// All the records of a RRset must have the same TTL.
+			ttl := rrset[0].Header().Ttl
+			for _, rr := range rrset {
+				ttl = min(ttl, rr.Header().Ttl)
+			}
+
+			for _, rr := range rrset {
+				rr.Header().Ttl = ttl
+			}
+
+			signed = append(signed, rrset...)
+
+			// Glue and delegation NS records aren't signed.
+			if isGlue(owner) || (owner != apex && rrtype == dns.TypeNS) {
+				continue
+			}
+
+			key := zsk
+			if rrtype == dns.TypeDNSKEY {
+				key = ksk
+			}
+
+			sig := &dns.RRSIG{
+				Hdr:        dns.RR_Header{Name: owner, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: ttl},
+				Algorithm:  key.dnskey.Algorithm,
+				SignerName: apex,
+				KeyTag:     key.dnskey.KeyTag(),
+				Inception:  uint32(now.Add(-time.Hour).Unix()),
+				Expiration: uint32(now.Add(dnssecSignatureValidity).Unix()),
+			}
+
+			err := sig.Sign(key.signer, rrset)
+			if err != nil {
+				return nil, fmt.Errorf("Failed signing %s records of %q: %w", dns.TypeToString[rrtype], owner, err)
+			}
+
+			signed = append(signed, sig)
+		}
+	}
+
+	return signed, nil
+}
+
+// signContent returns the DNSSEC signed zone content.
+func (d *zone) signContent(ctx context.Context, content string) (*strings.Builder, error) {
+	var keys []db.NetworkZoneDNSSECKey
+
+	err := d.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		keys, err = tx.GetNetworkZoneDNSSECKeys(ctx, d.id)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading DNSSEC keys: %w", err)
+	}
+
+	var records []dns.RR
+
+	zoneRR := dns.NewZoneParser(strings.NewReader(content), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			err := zoneRR.Err()
+			if err != nil {
+				return nil, err
+			}
+
+			break
+		}
+
+		records = append(records, rr)
+	}
+
+	signed, err := signZone(d.info.Name, records, keys, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("Failed signing zone %q: %w", d.info.Name, err)
+	}
+
+	// Zone transfers start and end with the SOA record.
+	sb := &strings.Builder{}
+	for _, rr := range signed {
+		sb.WriteString(rr.String() + "\n")
+	}
+
+	sb.WriteString(signed[0].String() + "\n")
+
+	return sb, nil
+}
+
+// updateDNSSECKeys generates the DNSSEC keys of the zone when DNSSEC is enabled, and removes them otherwise.
+func updateDNSSECKeys(ctx context.Context, tx *db.ClusterTx, zoneID int64, zoneName string, zoneConfig map[string]string) error {
+	if shared.IsFalseOrEmpty(zoneConfig["dnssec.enabled"]) {
+		return tx.DeleteNetworkZoneDNSSECKeys(ctx, zoneID)
+	}
+
+	keys, err := tx.GetNetworkZoneDNSSECKeys(ctx, zoneID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, flags := range []uint16{dnssecFlagsKSK, dnssecFlagsZSK} {
+		if slices.ContainsFunc(keys, func(key db.NetworkZoneDNSSECKey) bool { return key.Flags == flags }) {
+			continue
+		}
+
+		key, err := generateDNSSECKey(zoneName, flags, now, now)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateNetworkZoneDNSSECKey(ctx, zoneID, *key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planDNSSECRollover returns whether a new zone signing key must be published, and the IDs of the
+// zone signing keys that were replaced long enough ago to be removed.
+func planDNSSECRollover(keys []db.NetworkZoneDNSSECKey, lifetime time.Duration, now time.Time) (bool, []int64) {
+	var active *db.NetworkZoneDNSSECKey
+	pending := false
+
+	for i, key := range keys {
+		if key.Flags != dnssecFlagsZSK {
+			continue
+		}
+
+		if key.ActivateAt.After(now) {
+			pending = true
+			continue
+		}
+
+		if active == nil || key.ActivateAt.After(active.ActivateAt) {
+			active = &keys[i]
+		}
+	}
+
+	if active == nil {
+		return false, nil
+	}
+
+	// Publish the next key ahead of the end of the lifetime of the active one.
+	publish := !pending && !now.Before(active.ActivateAt.Add(lifetime-dnssecRolloverDelay))
+
+	// Remove the keys replaced by the active one once the records they signed expired from caches.
+	var remove []int64
+	if !now.Before(active.ActivateAt.Add(dnssecRolloverDelay)) {
+		for _, key := range keys {
+			if key.Flags == dnssecFlagsZSK && key.ActivateAt.Before(active.ActivateAt) {
+				remove = append(remove, key.ID)
+			}
+		}
+	}
+
+	return publish, remove
+}
+
+// RolloverDNSSECKeys publishes new zone signing keys for the zones with DNSSEC enabled whose
+// keys reached the end of their lifetime, and removes the keys which were replaced.
+func RolloverDNSSECKeys(ctx context.Context, s *state.State) error {
+	var zones map[string]string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		zones, err = tx.GetNetworkZones(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading network zones: %w", err)
+	}
+
+	for zoneName := range zones {
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			zoneID, _, info, err := tx.GetNetworkZone(ctx, zoneName)
+			if err != nil {
+				return err
+			}
+
+			if shared.IsFalseOrEmpty(info.Config["dnssec.enabled"]) {
+				return nil
+			}
+
+			lifetimeDays := int64(dnssecDefaultZSKLifetime)
+			if info.Config["dnssec.zsk.lifetime"] != "" {
+				lifetimeDays, err = strconv.ParseInt(info.Config["dnssec.zsk.lifetime"], 10, 64)
+				if err != nil {
+					return err
+				}
+			}
+
+			keys, err := tx.GetNetworkZoneDNSSECKeys(ctx, zoneID)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now().UTC()
+			publish, remove := planDNSSECRollover(keys, time.Duration(lifetimeDays)*24*time.Hour, now)
+
+			if publish {
+				key, err := generateDNSSECKey(zoneName, dnssecFlagsZSK, now, now.Add(dnssecRolloverDelay))
+				if err != nil {
+					return err
+				}
+
+				_, err = tx.CreateNetworkZoneDNSSECKey(ctx, zoneID, *key)
+				if err != nil {
+					return err
+				}
+
+				logger.Info("Published new DNSSEC zone signing key", logger.Ctx{"zone": zoneName, "activateAt": key.ActivateAt})
+			}
+
+			for _, id := range remove {
+				err = tx.DeleteNetworkZoneDNSSECKey(ctx, id)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("Failed rolling over DNSSEC keys of network zone %q: %w", zoneName, err)
+		}
+	}
+
+	return nil
+}
+
+// dnssecState returns the DNSSEC state of the zone.
+func (d *zone) dnssecState(ctx context.Context) (*api.NetworkZoneStateDNSSEC, error) {
+	var storedKeys []db.NetworkZoneDNSSECKey
+
+	err := d.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		storedKeys, err = tx.GetNetworkZoneDNSSECKeys(ctx, d.id)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading DNSSEC keys: %w", err)
+	}
+
+	now := time.Now()
+	keys := make([]*dnssecKey, 0, len(storedKeys))
+	for _, storedKey := range storedKeys {
+		key, err := loadDNSSECKey(d.info.Name, storedKey)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	activeKSK := activeDNSSECKey(keys, dnssecFlagsKSK, now)
+	activeZSK := activeDNSSECKey(keys, dnssecFlagsZSK, now)
+
+	zoneState := &api.NetworkZoneStateDNSSEC{
+		DS:   []string{},
+		Keys: []api.NetworkZoneStateDNSSECKey{},
+	}
+
+	for _, key := range keys {
+		keyState := api.NetworkZoneStateDNSSECKey{
+			KeyTag:     key.dnskey.KeyTag(),
+			Algorithm:  dns.AlgorithmToString[key.dnskey.Algorithm],
+			DNSKEY:     key.dnskey.String(),
+			CreatedAt:  key.info.CreatedAt,
+			ActivateAt: key.info.ActivateAt,
+		}
+
+		if key.info.Flags == dnssecFlagsKSK {
+			keyState.Type = "ksk"
+			zoneState.DS = append(zoneState.DS, key.dnskey.ToDS(dns.SHA256).String())
+		} else {
+			keyState.Type = "zsk"
+		}
+
+		switch {
+		case key == activeKSK || key == activeZSK:
+			keyState.Status = "active"
+		case key.info.ActivateAt.After(now):
+			keyState.Status = "published"
+		default:
+			keyState.Status = "retired"
+		}
+
+		zoneState.Keys = append(zoneState.Keys, keyState)
+	}
+
+	return zoneState, nil
+}
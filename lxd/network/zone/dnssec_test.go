@@ -0,0 +1,254 @@
+package zone
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/db"
+)
+
+const testSignZoneContent = `
+lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1 120 60 86400 30
+lxd.example.net. 300 IN NS ns1.lxd.example.net.
+c1.lxd.example.net. 300 IN A 192.0.2.42
+c1.lxd.example.net. 60 IN A 192.0.2.43
+c1.lxd.example.net. 300 IN AAAA fd42::42
+sub.lxd.example.net. 300 IN NS ns.sub.lxd.example.net.
+ns.sub.lxd.example.net. 300 IN A 192.0.2.53
+lxd.example.net. 3600 IN SOA lxd.example.net. ns1.lxd.example.net. 1 120 60 86400 30
+`
+
+// testDNSSECKeys returns an active KSK and ZSK for the test zone.
+func testDNSSECKeys(t *testing.T, now time.Time) []db.NetworkZoneDNSSECKey {
+	t.Helper()
+
+	ksk, err := generateDNSSECKey("lxd.example.net", dnssecFlagsKSK, now, now)
+	require.NoError(t, err)
+
+	zsk, err := generateDNSSECKey("lxd.example.net", dnssecFlagsZSK, now, now)
+	require.NoError(t, err)
+
+	return []db.NetworkZoneDNSSECKey{*ksk, *zsk}
+}
+
+// parseTestZone parses zone content into records.
+func parseTestZone(t *testing.T, content string) []dns.RR {
+	t.Helper()
+
+	var records []dns.RR
+
+	zp := dns.NewZoneParser(strings.NewReader(content), "", "")
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		records = append(records, rr)
+	}
+
+	require.NoError(t, zp.Err())
+
+	return records
+}
+
+func TestSignZone(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	keys := testDNSSECKeys(t, now)
+
+	signed, err := signZone("lxd.example.net", parseTestZone(t, testSignZoneContent), keys, now)
+	require.NoError(t, err)
+
+	require.IsType(t, &dns.SOA{}, signed[0])
+
+	dnskeys := map[uint16]*dns.DNSKEY{}
+	sigs := map[string][]*dns.RRSIG{}
+	rrsets := map[string][]dns.RR{}
+	nsecs := map[string]*dns.NSEC{}
+	soaCount := 0
+
+	for _, rr := range signed {
+		switch r := rr.(type) {
+		case *dns.DNSKEY:
+			dnskeys[r.KeyTag()] = r
+		case *dns.RRSIG:
+			key := r.Hdr.Name + "/" + dns.TypeToString[r.TypeCovered]
+			sigs[key] = append(sigs[key], r)
+			continue
+		case *dns.NSEC:
+			nsecs[r.Hdr.Name] = r
+		case *dns.SOA:
+			soaCount++
+		}
+
+		key := rr.Header().Name + "/" + dns.TypeToString[rr.Header().Rrtype]
+		rrsets[key] = append(rrsets[key], rr)
+	}
+
+	assert.Equal(t, 1, soaCount)
+	assert.Len(t, dnskeys, 2)
+
+	// RRsets have a single TTL.
+	for _, rr := range rrsets["c1.lxd.example.net./A"] {
+		assert.Equal(t, uint32(60), rr.Header().Ttl)
+	}
+
+	// Every authoritative RRset has a valid signature.
+	for key, rrset := range rrsets {
+		if key == "sub.lxd.example.net./NS" || key == "ns.sub.lxd.example.net./A" {
+			assert.Empty(t, sigs[key], key)
+			continue
+		}
+
+		require.Len(t, sigs[key], 1, key)
+
+		sig := sigs[key][0]
+		dnskey := dnskeys[sig.KeyTag]
+		require.NotNil(t, dnskey, key)
+
+		if key == "lxd.example.net./DNSKEY" {
+			assert.Equal(t, uint16(dnssecFlagsKSK), dnskey.Flags)
+		} else {
+			assert.Equal(t, uint16(dnssecFlagsZSK), dnskey.Flags)
+		}
+
+		require.NoError(t, sig.Verify(dnskey, rrset), key)
+		assert.True(t, sig.ValidityPeriod(now), key)
+	}
+
+	// The NSEC chain covers the authoritative names in canonical order and skips glue.
+	assert.Equal(t, "c1.lxd.example.net.", nsecs["lxd.example.net."].NextDomain)
+	assert.Equal(t, "sub.lxd.example.net.", nsecs["c1.lxd.example.net."].NextDomain)
+	assert.Equal(t, "lxd.example.net.", nsecs["sub.lxd.example.net."].NextDomain)
+	assert.NotContains(t, nsecs, "ns.sub.lxd.example.net.")
+	assert.Equal(t, []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeRRSIG, dns.TypeNSEC}, nsecs["c1.lxd.example.net."].TypeBitMap)
+	assert.Equal(t, uint32(30), nsecs["c1.lxd.example.net."].Hdr.Ttl)
+}
+
+func TestSignZone_Errors(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	records := parseTestZone(t, testSignZoneContent)
+
+	t.Run("No keys", func(t *testing.T) {
+		_, err := signZone("lxd.example.net", records, nil, now)
+		assert.Error(t, err)
+	})
+
+	t.Run("Keys not active yet", func(t *testing.T) {
+		_, err := signZone("lxd.example.net", records, testDNSSECKeys(t, now.Add(time.Hour)), now)
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing SOA", func(t *testing.T) {
+		_, err := signZone("lxd.example.net", records[1:], testDNSSECKeys(t, now), now)
+		assert.Error(t, err)
+	})
+
+	t.Run("Record outside of zone", func(t *testing.T) {
+		outside := append(parseTestZone(t, testSignZoneContent), parseTestZone(t, "c1.example.org. 300 IN A 192.0.2.1")...)
+		_, err := signZone("lxd.example.net", outside, testDNSSECKeys(t, now), now)
+		assert.Error(t, err)
+	})
+}
+
+func TestSignZone_Rollover(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	keys := testDNSSECKeys(t, now.Add(-48*time.Hour))
+
+	next, err := generateDNSSECKey("lxd.example.net", dnssecFlagsZSK, now, now.Add(dnssecRolloverDelay))
+	require.NoError(t, err)
+
+	keys = append(keys, *next)
+
+	signed, err := signZone("lxd.example.net", parseTestZone(t, testSignZoneContent), keys, now)
+	require.NoError(t, err)
+
+	nextKey, err := loadDNSSECKey("lxd.example.net", *next)
+	require.NoError(t, err)
+
+	published := false
+	for _, rr := range signed {
+		switch r := rr.(type) {
+		case *dns.DNSKEY:
+			if r.KeyTag() == nextKey.dnskey.KeyTag() {
+				published = true
+			}
+
+		case *dns.RRSIG:
+			// The pending key isn't used for signing yet.
+			assert.NotEqual(t, nextKey.dnskey.KeyTag(), r.KeyTag)
+		}
+	}
+
+	assert.True(t, published)
+}
+
+func TestPlanDNSSECRollover(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	day := 24 * time.Hour
+	lifetime := 30 * day
+
+	ksk := db.NetworkZoneDNSSECKey{ID: 1, Flags: dnssecFlagsKSK, ActivateAt: now.Add(-100 * day)}
+
+	tests := []struct {
+		name        string
+		keys        []db.NetworkZoneDNSSECKey
+		wantPublish bool
+		wantRemove  []int64
+	}{
+		{
+			name: "Fresh key",
+			keys: []db.NetworkZoneDNSSECKey{ksk, {ID: 2, Flags: dnssecFlagsZSK, ActivateAt: now.Add(-day)}},
+		},
+		{
+			name:        "Key reaching the end of its lifetime",
+			keys:        []db.NetworkZoneDNSSECKey{ksk, {ID: 2, Flags: dnssecFlagsZSK, ActivateAt: now.Add(-29 * day)}},
+			wantPublish: true,
+		},
+		{
+			name: "Next key already published",
+			keys: []db.NetworkZoneDNSSECKey{
+				ksk,
+				{ID: 2, Flags: dnssecFlagsZSK, ActivateAt: now.Add(-29 * day)},
+				{ID: 3, Flags: dnssecFlagsZSK, ActivateAt: now.Add(12 * time.Hour)},
+			},
+		},
+		{
+			name: "Replaced key still published after rollover",
+			keys: []db.NetworkZoneDNSSECKey{
+				ksk,
+				{ID: 2, Flags: dnssecFlagsZSK, ActivateAt: now.Add(-30 * day)},
+				{ID: 3, Flags: dnssecFlagsZSK, ActivateAt: now.Add(-12 * time.Hour)},
+			},
+		},
+		{
+			name: "Replaced key removed",
+			keys: []db.NetworkZoneDNSSECKey{
+				ksk,
+				{ID: 2, Flags: dnssecFlagsZSK, ActivateAt: now.Add(-31 * day)},
+				{ID: 3, Flags: dnssecFlagsZSK, ActivateAt: now.Add(-day)},
+			},
+			wantRemove: []int64{2},
+		},
+		{
+			name: "No zone signing key",
+			keys: []db.NetworkZoneDNSSECKey{ksk},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publish, remove := planDNSSECRollover(tt.keys, lifetime, now)
+			assert.Equal(t, tt.wantPublish, publish)
+			assert.Equal(t, tt.wantRemove, remove)
+		})
+	}
+}
@@ -22,6 +22,7 @@ type NetworkZone interface {
 	UsedBy(ctx context.Context) ([]string, error)
 	Content(ctx context.Context) (*strings.Builder, error)
 	SOA() (*strings.Builder, error)
+	State(ctx context.Context) (*api.NetworkZoneState, error)
 
 	// Records.
 	AddRecord(ctx context.Context, req api.NetworkZoneRecordsPost) error
@@ -108,9 +108,13 @@ func Create(ctx context.Context, s *state.State, projectName string, zoneInfo *a
 
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Insert DB record.
-		_, err = tx.CreateNetworkZone(ctx, projectName, zoneInfo)
+		zoneID, err := tx.CreateNetworkZone(ctx, projectName, zoneInfo)
+		if err != nil {
+			return err
+		}
 
-		return err
+		// Generate the DNSSEC keys.
+		return updateDNSSECKeys(ctx, tx, zoneID, zoneInfo.Name, zoneInfo.Config)
 	})
 	if err != nil {
 		return err
@@ -122,6 +126,8 @@ func Create(ctx context.Context, s *state.State, projectName string, zoneInfo *a
 		return err
 	}
 
+	s.DNS.InvalidateZones()
+
 	return nil
 }
 
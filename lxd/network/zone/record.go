@@ -64,6 +64,8 @@ func (d *zone) AddRecord(ctx context.Context, req api.NetworkZoneRecordsPost) er
 		return err
 	}
 
+	d.state.DNS.InvalidateZones()
+
 	return nil
 }
 
@@ -157,6 +159,8 @@ func (d *zone) UpdateRecord(ctx context.Context, name string, req api.NetworkZon
 		return err
 	}
 
+	d.state.DNS.InvalidateZones()
+
 	return nil
 }
 
@@ -183,6 +187,8 @@ func (d *zone) DeleteRecord(ctx context.Context, name string) error {
 		return err
 	}
 
+	d.state.DNS.InvalidateZones()
+
 	return nil
 }
 
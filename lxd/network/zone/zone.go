@@ -176,6 +176,38 @@ func (d *zone) validateConfig(info *api.NetworkZonePut) error {
 	//  required: no
 	//  shortdesc: Comma-separated list of DNS server FQDNs (for NS records)
 	rules["dns.nameservers"] = validate.IsListOf(validate.IsAny)
+	// lxdmeta:generate(entities=network-zone; group=config-options; key=dns.query.enabled)
+	// When enabled, the built-in DNS server answers queries for the records of the zone, in addition to zone transfers.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  required: no
+	//  shortdesc: Whether to answer DNS queries for the zone
+	rules["dns.query.enabled"] = validate.Optional(validate.IsBool)
+	// lxdmeta:generate(entities=network-zone; group=config-options; key=dns.query.sources)
+	// If not set, queries are answered for any client.
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Comma-separated list of subnets (in CIDR notation) allowed to query the zone
+	rules["dns.query.sources"] = validate.Optional(validate.IsListOf(validate.IsNetwork))
+	// lxdmeta:generate(entities=network-zone; group=config-options; key=dnssec.enabled)
+	// When enabled, LXD generates the DNSSEC keys of the zone and signs its records.
+	// The DS records to add to the parent zone are shown in the zone state.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  required: no
+	//  shortdesc: Whether to sign the zone with DNSSEC
+	rules["dnssec.enabled"] = validate.Optional(validate.IsBool)
+	// lxdmeta:generate(entities=network-zone; group=config-options; key=dnssec.zsk.lifetime)
+	// The new zone signing key is published a day before being used to sign the zone.
+	// ---
+	//  type: integer
+	//  defaultdesc: `30`
+	//  required: no
+	//  shortdesc: Number of days after which the zone signing key is replaced
+	rules["dnssec.zsk.lifetime"] = validate.Optional(validate.IsInRange(2, 365))
 	// lxdmeta:generate(entities=network-zone; group=config-options; key=network.nat)
 	//
 	// ---
@@ -297,6 +329,14 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 			d.init(d.state, d.id, d.projectName, d.info)
 		})
 
+		// Generate or remove the DNSSEC keys.
+		err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return updateDNSSECKeys(ctx, tx, d.id, d.info.Name, d.info.Config)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed updating DNSSEC keys: %w", err)
+		}
+
 		// Notify all other nodes to update the network zone if no target specified.
 		notifier, err := cluster.NewOperationNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
 		if err != nil {
@@ -322,6 +362,8 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 		return err
 	}
 
+	d.state.DNS.InvalidateZones()
+
 	revert.Success()
 	return nil
 }
@@ -353,6 +395,8 @@ func (d *zone) Delete(ctx context.Context) error {
 		return err
 	}
 
+	d.state.DNS.InvalidateZones()
+
 	return nil
 }
 
@@ -556,9 +600,29 @@ func (d *zone) Content(ctx context.Context) (*strings.Builder, error) {
 		return nil, err
 	}
 
+	if shared.IsTrue(d.info.Config["dnssec.enabled"]) {
+		return d.signContent(ctx, sb.String())
+	}
+
 	return sb, nil
 }
 
+// State returns the state of the zone.
+func (d *zone) State(ctx context.Context) (*api.NetworkZoneState, error) {
+	zoneState := &api.NetworkZoneState{}
+
+	if shared.IsTrue(d.info.Config["dnssec.enabled"]) {
+		dnssec, err := d.dnssecState(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		zoneState.DNSSEC = dnssec
+	}
+
+	return zoneState, nil
+}
+
 // SOA returns just the DNS zone SOA record.
 func (d *zone) SOA() (*strings.Builder, error) {
 	// Get the nameservers.
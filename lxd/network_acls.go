@@ -7,17 +7,21 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/operationtype"
 	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/metrics"
 	"github.com/canonical/lxd/lxd/network/acl"
 	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
@@ -54,6 +58,14 @@ var networkACLLogCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: networkACLLogGet, AccessHandler: allowPermission(entity.TypeNetworkACL, auth.EntitlementCanView, "name")},
 }
 
+var networkACLStateCmd = APIEndpoint{
+	Path:            "network-acls/{name}/state",
+	MetricsType:     entity.TypeNetwork,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: networkACLStateGet, AccessHandler: allowPermission(entity.TypeNetworkACL, auth.EntitlementCanView, "name")},
+}
+
 // API endpoints.
 
 // swagger:operation GET /1.0/network-acls network-acls network_acls_get
@@ -821,3 +833,128 @@ func networkACLLogGet(d *Daemon, r *http.Request) response.Response {
 
 	return response.FileResponse([]response.FileResponseEntry{ent}, nil)
 }
+
+// swagger:operation GET /1.0/network-acls/{name}/state network-acls network_acl_state_get
+//
+//	Get the network ACL state
+//
+//	Gets the packet and byte counters of the rules of a specific network ACL for each network using it.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: ACL state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkACLState"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkACLStateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	aclName := r.PathValue("name")
+	netACL, err := acl.LoadByName(r.Context(), s, projectName, aclName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	aclState, err := netACL.GetState(r.Context(), requestor.ClientType())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, aclState)
+}
+
+// networkACLMetrics returns the rule counters of the network ACLs of a project gathered on the local member.
+func networkACLMetrics(ctx context.Context, s *state.State, projectName string) (*metrics.MetricSet, error) {
+	var aclNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		aclNames, err = tx.GetNetworkACLs(ctx, projectName)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counters, err := acl.RuleCounters(ctx, s, projectName, aclNames...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := metrics.NewMetricSet(map[string]string{"project": projectName})
+	for aclName, aclState := range counters {
+		for _, network := range aclState.Networks {
+			for direction, rules := range map[string][]api.NetworkACLRuleCounters{"ingress": network.Ingress, "egress": network.Egress} {
+				for ruleIndex, rule := range rules {
+					labels := map[string]string{"acl": aclName, "network": network.Name, "direction": direction, "rule": strconv.Itoa(ruleIndex)}
+					out.AddSamples(metrics.NetworkACLRulePacketsTotal, metrics.Sample{Labels: labels, Value: float64(rule.Packets)})
+					out.AddSamples(metrics.NetworkACLRuleBytesTotal, metrics.Sample{Labels: labels, Value: float64(rule.Bytes)})
+				}
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// networkACLFQDNRefreshTask returns a task that refreshes the addresses of the DNS names used in network ACL rules.
+func networkACLFQDNRefreshTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Error("Failed getting leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		err = acl.FQDNRefresh(ctx, s, leaderInfo.Leader)
+		if err != nil {
+			logger.Error("Failed refreshing network ACL FQDN subjects", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(acl.FQDNRefreshInterval, task.SkipFirst)
+}
@@ -0,0 +1,786 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/network/acl"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+var networkAddressSetsCmd = APIEndpoint{
+	Path:            "network-address-sets",
+	MetricsType:     entity.TypeNetwork,
+	ProjectSpecific: true,
+
+	Get:  APIEndpointAction{Handler: networkAddressSetsGet, AccessHandler: allowAuthenticated, AllProjectsMode: allProjectsModeDisallowRestrictedTLSClients},
+	Post: APIEndpointAction{Handler: networkAddressSetsPost, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanCreateNetworkAddressSets)},
+}
+
+var networkAddressSetCmd = APIEndpoint{
+	Path:            "network-address-sets/{name}",
+	MetricsType:     entity.TypeNetwork,
+	ProjectSpecific: true,
+
+	Delete: APIEndpointAction{Handler: networkAddressSetDelete, AccessHandler: allowPermission(entity.TypeNetworkAddressSet, auth.EntitlementCanDelete, "name")},
+	Get:    APIEndpointAction{Handler: networkAddressSetGet, AccessHandler: allowPermission(entity.TypeNetworkAddressSet, auth.EntitlementCanView, "name")},
+	Put:    APIEndpointAction{Handler: networkAddressSetPut, AccessHandler: allowPermission(entity.TypeNetworkAddressSet, auth.EntitlementCanEdit, "name")},
+	Patch:  APIEndpointAction{Handler: networkAddressSetPut, AccessHandler: allowPermission(entity.TypeNetworkAddressSet, auth.EntitlementCanEdit, "name")},
+	Post:   APIEndpointAction{Handler: networkAddressSetPost, AccessHandler: allowPermission(entity.TypeNetworkAddressSet, auth.EntitlementCanEdit, "name")},
+}
+
+func networkAddressSetEtag(addressSet api.NetworkAddressSet) any {
+	return []any{addressSet.Name, addressSet.Project, addressSet.Description, addressSet.Addresses, addressSet.Config}
+}
+
+// API endpoints.
+
+// swagger:operation GET /1.0/network-address-sets network-address-sets network_address_sets_get
+//
+//	Get the network address sets
+//
+//	Returns a list of network address sets (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve network address sets from all projects
+//	    type: boolean
+//	    example: true
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/network-address-sets/web-servers",
+//	              "/1.0/network-address-sets/db-servers"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/network-address-sets?recursion=1 network-address-sets network_address_sets_get_recursion1
+//
+//	Get the network address sets
+//
+//	Returns a list of network address sets (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve network address sets from all projects
+//	    type: boolean
+//	    example: true
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of network address sets
+//	          items:
+//	            $ref: "#/definitions/NetworkAddressSet"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	requestProjectName, allProjects, err := request.ProjectParams(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var projectNameFilter *string
+	if !allProjects {
+		// Project specific requests require an effective project, when "features.networks" is enabled this is the requested project, otherwise it is the default project.
+		effectiveProjectName, _, err := project.NetworkProject(s.DB.Cluster, requestProjectName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// If the request is project specific, then set effective project name in the request context so that the authorizer can generate the correct URL.
+		request.SetContextValue(r, request.CtxEffectiveProjectName, effectiveProjectName)
+		projectNameFilter = &effectiveProjectName
+	}
+
+	recursion, _ := util.IsRecursionRequest(r)
+	withEntitlements, err := extractEntitlementsFromQuery(r, entity.TypeNetworkAddressSet, true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	canViewAddressSet, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeNetworkAddressSet)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	var addressSets []dbCluster.NetworkAddressSet
+	var addressSetURLs []string
+	var allConfigs map[int64]map[string]string
+	var usedByURLs map[string]map[string][]string
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		addressSets, addressSetURLs, err = dbCluster.GetNetworkAddressSetsAndURLs(ctx, tx.Tx(), projectNameFilter, func(addressSet dbCluster.NetworkAddressSet) bool {
+			return canViewAddressSet(entity.NetworkAddressSetURL(addressSet.ProjectName, addressSet.Row.Name))
+		})
+		if err != nil {
+			return err
+		}
+
+		if recursion == 0 {
+			return nil
+		}
+
+		configStore := dbCluster.NetworkAddressSetsConfigStore()
+		if projectNameFilter == nil {
+			allConfigs, err = configStore.GetAll(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+		} else {
+			allConfigs, err = configStore.Select(ctx, tx.Tx(), "JOIN projects ON networks_address_sets.project_id = projects.id WHERE projects.name = ?", *projectNameFilter)
+			if err != nil {
+				return err
+			}
+		}
+
+		usedByURLs, err = dbCluster.GetNetworkAddressSetsUsedBy(ctx, tx.Tx(), dbCluster.NetworkAddressSetFilter{Project: projectNameFilter}, false)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion == 0 {
+		return response.SyncResponse(true, addressSetURLs)
+	}
+
+	entitlementReportingMap := make(map[*api.URL]auth.EntitlementReporter)
+	apiAddressSets := make([]*api.NetworkAddressSet, 0, len(addressSets))
+	for _, addressSet := range addressSets {
+		apiAddressSet := addressSet.ToAPI(allConfigs)
+		apiAddressSet.UsedBy = project.FilterUsedBy(r.Context(), s.Authorizer, usedByURLs[addressSet.ProjectName][addressSet.Row.Name])
+
+		apiAddressSets = append(apiAddressSets, apiAddressSet)
+		entitlementReportingMap[entity.NetworkAddressSetURL(addressSet.ProjectName, addressSet.Row.Name)] = apiAddressSet
+	}
+
+	if len(withEntitlements) > 0 {
+		err = reportEntitlements(r.Context(), s.Authorizer, entity.TypeNetworkAddressSet, withEntitlements, entitlementReportingMap)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	return response.SyncResponse(true, apiAddressSets)
+}
+
+// swagger:operation POST /1.0/network-address-sets network-address-sets network_address_sets_post
+//
+//	Add a network address set
+//
+//	Creates a new network address set.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: addressSet
+//	    description: The new network address set
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkAddressSetsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.NetworkAddressSetsPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = acl.ValidAddressSetName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = acl.ValidAddressSet(req.NetworkAddressSetPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	newAddressSet := dbCluster.NetworkAddressSetsRow{
+		Name:        req.Name,
+		Description: req.Description,
+		Addresses:   req.Addresses,
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		projectID, err := dbCluster.GetProjectID(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return fmt.Errorf("Failed getting project ID: %w", err)
+		}
+
+		newAddressSet.ProjectID = projectID
+		id, err := query.Create(ctx, tx.Tx(), newAddressSet)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.NetworkAddressSetsConfigStore().Set(ctx, tx.Tx(), id, req.Config)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.NetworkAddressSetCreated.Event(projectName, req.Name, request.CreateRequestor(r.Context()), nil)
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/network-address-sets/{name} network-address-sets network_address_set_delete
+//
+//	Delete the network address set
+//
+//	Removes the network address set.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = doNetworkAddressSetDelete(r.Context(), s, r.PathValue("name"), projectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// doNetworkAddressSetDelete deletes the network address set if it is not referenced by any network ACL.
+func doNetworkAddressSetDelete(ctx context.Context, s *state.State, name string, projectName string) error {
+	var addressSetID int64
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbAddressSet, err := dbCluster.GetNetworkAddressSet(ctx, tx.Tx(), name, projectName)
+		if err != nil {
+			return err
+		}
+
+		usedBy, err := dbCluster.GetNetworkAddressSetUsedBy(ctx, tx.Tx(), dbAddressSet.ProjectName, dbAddressSet.Row.Name, true)
+		if err != nil {
+			return err
+		}
+
+		if len(usedBy) > 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "Network address set %q is currently in use", name)
+		}
+
+		addressSetID = dbAddressSet.Row.ID
+
+		return query.DeleteByPrimaryKey(ctx, tx.Tx(), dbAddressSet.Row)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = acl.AddressSetDelete(ctx, s, projectName, addressSetID)
+	if err != nil {
+		return err
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.NetworkAddressSetDeleted.Event(projectName, name, request.CreateRequestor(ctx), nil))
+
+	return nil
+}
+
+// swagger:operation GET /1.0/network-address-sets/{name} network-address-sets network_address_set_get
+//
+//	Get the network address set
+//
+//	Gets a specific network address set.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Network address set
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkAddressSet"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	addressSetName := r.PathValue("name")
+	withEntitlements, err := extractEntitlementsFromQuery(r, entity.TypeNetworkAddressSet, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var addressSet *dbCluster.NetworkAddressSet
+	var configs map[int64]map[string]string
+	var usedBy []string
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		addressSet, err = dbCluster.GetNetworkAddressSet(ctx, tx.Tx(), addressSetName, projectName)
+		if err != nil {
+			return err
+		}
+
+		configs, err = dbCluster.NetworkAddressSetsConfigStore().GetByEntityIDs(ctx, tx.Tx(), addressSet.Row.ID)
+		if err != nil {
+			return err
+		}
+
+		usedBy, err = dbCluster.GetNetworkAddressSetUsedBy(ctx, tx.Tx(), projectName, addressSetName, false)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	apiAddressSet := addressSet.ToAPI(configs)
+	apiAddressSet.UsedBy = project.FilterUsedBy(r.Context(), s.Authorizer, usedBy)
+
+	if len(withEntitlements) > 0 {
+		err = reportEntitlements(r.Context(), s.Authorizer, entity.TypeNetworkAddressSet, withEntitlements, map[*api.URL]auth.EntitlementReporter{entity.NetworkAddressSetURL(projectName, addressSetName): apiAddressSet})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	return response.SyncResponseETag(true, apiAddressSet, networkAddressSetEtag(*apiAddressSet))
+}
+
+// swagger:operation PATCH /1.0/network-address-sets/{name} network-address-sets network_address_set_patch
+//
+//	Partially update the network address set
+//
+//	Updates a subset of the network address set configuration.
+//	The new addresses are applied to the networks using the network ACLs that reference the address set.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: addressSet
+//	    description: Address set configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkAddressSetPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation PUT /1.0/network-address-sets/{name} network-address-sets network_address_set_put
+//
+//	Update the network address set
+//
+//	Updates the entire network address set configuration.
+//	The new addresses are applied to the networks using the network ACLs that reference the address set.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: addressSet
+//	    description: Address set configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkAddressSetPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	addressSetName := r.PathValue("name")
+
+	req := api.NetworkAddressSetPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	clientType := requestor.ClientType()
+
+	// Notifications from other cluster members only apply the already stored addresses to the local networks.
+	if clientType.IsClusterNotification() {
+		err = acl.AddressSetApply(r.Context(), s, projectName, addressSetName, clientType)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+
+	var addressSet *dbCluster.NetworkAddressSet
+	var configs map[int64]map[string]string
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		addressSet, err = dbCluster.GetNetworkAddressSet(ctx, tx.Tx(), addressSetName, projectName)
+		if err != nil {
+			return err
+		}
+
+		configs, err = dbCluster.NetworkAddressSetsConfigStore().GetByEntityIDs(ctx, tx.Tx(), addressSet.Row.ID)
+		if err != nil {
+			return fmt.Errorf("Failed getting network address set config: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	apiAddressSet := addressSet.ToAPI(configs)
+	err = util.EtagCheck(r, networkAddressSetEtag(*apiAddressSet))
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	if r.Method == http.MethodPatch {
+		// If config being updated via "patch" method, then merge all existing config with the keys that
+		// are present in the request config, and keep the existing description and addresses if not specified.
+		if req.Config == nil {
+			req.Config = map[string]string{}
+		}
+
+		for k, v := range apiAddressSet.Config {
+			_, ok := req.Config[k]
+			if !ok {
+				req.Config[k] = v
+			}
+		}
+
+		if req.Description == "" {
+			req.Description = apiAddressSet.Description
+		}
+
+		if req.Addresses == nil {
+			req.Addresses = apiAddressSet.Addresses
+		}
+	}
+
+	err = acl.ValidAddressSet(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	updatedRow := addressSet.Row
+	updatedRow.Description = req.Description
+	updatedRow.Addresses = req.Addresses
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := query.UpdateByPrimaryKey(ctx, tx.Tx(), updatedRow)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.NetworkAddressSetsConfigStore().Set(ctx, tx.Tx(), updatedRow.ID, req.Config)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Apply the new addresses to the networks using the address set on this member and in OVN.
+	err = acl.AddressSetApply(r.Context(), s, projectName, addressSetName, clientType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Notify the other cluster members so that they apply the new addresses to their local networks.
+	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+		return client.UseProject(projectName).UpdateNetworkAddressSet(addressSetName, req, "")
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.NetworkAddressSetUpdated.Event(projectName, addressSetName, request.CreateRequestor(r.Context()), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/network-address-sets/{name} network-address-sets network_address_set_post
+//
+//	Rename the network address set
+//
+//	Renames an existing network address set.
+//	Address sets referenced by network ACLs cannot be renamed.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: addressSet
+//	    description: Address set rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkAddressSetPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkAddressSetPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	addressSetName := r.PathValue("name")
+
+	req := api.NetworkAddressSetPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = acl.ValidAddressSetName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbAddressSet, err := dbCluster.GetNetworkAddressSet(ctx, tx.Tx(), addressSetName, projectName)
+		if err != nil {
+			return err
+		}
+
+		usedBy, err := dbCluster.GetNetworkAddressSetUsedBy(ctx, tx.Tx(), dbAddressSet.ProjectName, dbAddressSet.Row.Name, true)
+		if err != nil {
+			return err
+		}
+
+		if len(usedBy) > 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "Network address set %q is currently in use", addressSetName)
+		}
+
+		dbAddressSet.Row.Name = req.Name
+		return query.UpdateByPrimaryKey(ctx, tx.Tx(), dbAddressSet.Row)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.NetworkAddressSetRenamed.Event(projectName, req.Name, request.CreateRequestor(r.Context()), map[string]any{"old_name": addressSetName})
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
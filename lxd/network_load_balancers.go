@@ -1120,6 +1120,12 @@ func networkLoadBalancerPoolGet(d *Daemon, r *http.Request) response.Response {
 func networkLoadBalancerPoolStateGet(d *Daemon, r *http.Request) response.Response {
 	s := d.State()
 
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
 	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
 	if err != nil {
 		return response.SmartError(err)
@@ -1135,7 +1141,7 @@ func networkLoadBalancerPoolStateGet(d *Daemon, r *http.Request) response.Respon
 		return response.SmartError(fmt.Errorf("Failed loading network: %w", err))
 	}
 
-	resp := networkLoadBalancerPoolCheckAccess(n, details)
+	resp = networkLoadBalancerPoolCheckAccess(n, details)
 	if resp != nil {
 		return resp
 	}
@@ -17,9 +17,11 @@ import (
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/version"
 )
 
@@ -43,6 +45,14 @@ var networkZoneCmd = APIEndpoint{
 	Patch:  APIEndpointAction{Handler: networkZonePut, AccessHandler: networkZoneAccessHandler(auth.EntitlementCanEdit)},
 }
 
+var networkZoneStateCmd = APIEndpoint{
+	Path:            "network-zones/{zone}/state",
+	MetricsType:     entity.TypeNetwork,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: networkZoneStateGet, AccessHandler: networkZoneAccessHandler(auth.EntitlementCanView)},
+}
+
 // ctxNetworkZoneDetails should be used only for getting/setting networkZoneDetails in the request context.
 const ctxNetworkZoneDetails request.CtxKey = "network-zone-details"
 
@@ -557,6 +567,72 @@ func networkZoneGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponseETag(true, info, netzone.Etag())
 }
 
+// swagger:operation GET /1.0/network-zones/{zone}/state network-zones network_zone_state_get
+//
+//	Get the network zone state
+//
+//	Gets the state of a specific network zone, including its DNSSEC keys.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: zone state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkZoneState"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkZoneStateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	details, err := request.GetContextValue[networkZoneDetails](r.Context(), ctxNetworkZoneDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	netzone, err := zone.LoadByNameAndProject(r.Context(), s, effectiveProjectName, details.zoneName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	zoneState, err := netzone.State(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, zoneState)
+}
+
 // swagger:operation PATCH /1.0/network-zones/{zone} network-zones network_zone_patch
 //
 //  Partially update the network zone
@@ -721,3 +797,45 @@ func networkZonePut(d *Daemon, r *http.Request) response.Response {
 
 	return response.OperationResponse(op)
 }
+
+// rolloverNetworkZoneKeysTask returns a task that rolls over the DNSSEC zone signing keys of the network zones.
+func rolloverNetworkZoneKeysTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Error("Failed getting leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		if !leaderInfo.Leader {
+			logger.Debug("Skipping network zone DNSSEC keys rollover task since we're not leader")
+			return
+		}
+
+		opRun := func(ctx context.Context, op *operations.Operation) error {
+			return zone.RolloverDNSSECKeys(ctx, s)
+		}
+
+		args := operations.OperationArgs{
+			Type:    operationtype.NetworkZoneKeysRollover,
+			Class:   operationtype.OperationClassTask,
+			RunHook: opRun,
+		}
+
+		op, err := operations.ScheduleServerOperation(s, args)
+		if err != nil {
+			logger.Error("Failed creating network zone DNSSEC keys rollover operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed rolling over network zone DNSSEC keys", logger.Ctx{"err": err})
+			return
+		}
+	}
+
+	return f, task.Daily()
+}
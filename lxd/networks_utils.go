@@ -65,7 +65,7 @@ func networkUpdateForkdnsServersTask(s *state.State, heartbeatData *cluster.APIH
 			continue
 		}
 
-		if n.Type() == "bridge" && n.Config()["bridge.mode"] == "fan" {
+		if n.Type() == "bridge" && slices.Contains([]string{"fan", "wireguard"}, n.Config()["bridge.mode"]) {
 			err := n.HandleHeartbeat(heartbeatData)
 			if err != nil {
 				return err
@@ -93,6 +93,16 @@ func (c *Config) MetricsAddress() string {
 	return metricsAddress
 }
 
+// StorageBucketsAddress returns the address and port to setup the storage buckets listener on.
+func (c *Config) StorageBucketsAddress() string {
+	storageBucketsAddress := c.m.GetString("core.storage_buckets_address")
+	if storageBucketsAddress != "" {
+		return util.CanonicalNetworkAddress(storageBucketsAddress, shared.HTTPSStorageBucketsDefaultPort)
+	}
+
+	return storageBucketsAddress
+}
+
 // daemonStorageVolume returns the volume configured as images or backups storage for target project.
 // If project is not specified, or if project has no specified storage volume configured, the daemon
 // storage volume is returned.
@@ -233,6 +243,17 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Address to bind the metrics server to (HTTPS)
 		"core.metrics_address": {Validator: validate.Optional(validate.IsListenAddress(true, true, false))},
 
+		// Network address for the storage buckets server
+
+		// lxdmeta:generate(entities=server; group=core; key=core.storage_buckets_address)
+		// Storage buckets on local storage pools are served through this S3-compatible endpoint.
+		// See {ref}`howto-storage-buckets`.
+		// ---
+		//  type: string
+		//  scope: local
+		//  shortdesc: Address to bind the storage buckets server to (HTTPS)
+		"core.storage_buckets_address": {Validator: validate.Optional(validate.IsListenAddress(true, true, false))},
+
 		// Syslog socket
 
 		// lxdmeta:generate(entities=server; group=core; key=core.syslog_socket)
@@ -129,6 +129,7 @@ var patches = []patch{
 	{name: "storage_rename_nvme_mode", stage: patchPreLoadClusterConfig, run: patchStoragePoolConnectorNVMeMode},
 	{name: "replicators_remove_snapshot_config_key", stage: patchPreLoadClusterConfig, run: patchReplicatorsRemoveSnapshotConfigKey},
 	{name: "config_remove_legacy_nvidia_keys", stage: patchPreLoadClusterConfig, run: patchRemoveLegacyNvidiaConfigKeys},
+	{name: "storage_create_local_bucket_structure", stage: patchPostDaemonStorage, run: patchGenericStorage},
 }
 
 type patch struct {
@@ -3,8 +3,11 @@ package placement
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
 	"net/http"
 	"slices"
+	"strconv"
 
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
@@ -30,8 +33,13 @@ func Filter(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, api
 		return nil, err
 	}
 
+	domains, err := getPlacementDomains(ctx, tx, apiPlacementGroup)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get compliant cluster members using the placement group.
-	filteredCandidates, err := getCompliantMembers(policy, rigor, candidates, memberToInst)
+	filteredCandidates, err := getCompliantMembers(policy, rigor, candidates, memberToInst, domains)
 	if err != nil {
 		return nil, api.StatusErrorf(http.StatusConflict, "Failed filtering candidate cluster members using placement group %q with %q policy and %q rigor: %w", apiPlacementGroup.Name, policy, rigor, err)
 	}
@@ -39,17 +47,149 @@ func Filter(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, api
 	return filteredCandidates, nil
 }
 
+// Allows returns whether the placement group allows moving the instance with the given ID to the cluster member.
+// This is used when restoring evacuated instances to their original cluster member.
+func Allows(ctx context.Context, tx *db.ClusterTx, member db.NodeInfo, apiPlacementGroup api.PlacementGroup, instanceID int64) (bool, error) {
+	memberToInst, err := cluster.GetInstancesInPlacementGroup(ctx, tx.Tx(), apiPlacementGroup.Name, apiPlacementGroup.Project, nil)
+	if err != nil {
+		return false, err
+	}
+
+	// Don't count the instance being moved.
+	for id, instances := range memberToInst {
+		memberToInst[id] = slices.DeleteFunc(instances, func(id int64) bool { return id == instanceID })
+		if len(memberToInst[id]) == 0 {
+			delete(memberToInst, id)
+		}
+	}
+
+	domains, err := getPlacementDomains(ctx, tx, apiPlacementGroup)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = getCompliantMembers(apiPlacementGroup.Config["policy"], apiPlacementGroup.Config["rigor"], []db.NodeInfo{member}, memberToInst, domains)
+	if err != nil {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// placementDomains maps cluster members to the domains the placement policy applies to.
+type placementDomains struct {
+	// Domains of each cluster member. When nil, each cluster member is its own domain.
+	memberDomains map[int64][]string
+
+	// Maximum number of instances per domain for the spread policy.
+	maxPerDomain int
+}
+
+// of returns the domains of the cluster member.
+func (d placementDomains) of(memberID int64) []string {
+	if d.memberDomains == nil {
+		return []string{strconv.FormatInt(memberID, 10)}
+	}
+
+	return d.memberDomains[memberID]
+}
+
+// getPlacementDomains returns the domains the placement group policy applies to, based on its "scope".
+func getPlacementDomains(ctx context.Context, tx *db.ClusterTx, apiPlacementGroup api.PlacementGroup) (placementDomains, error) {
+	domains := placementDomains{maxPerDomain: 1}
+
+	if apiPlacementGroup.Config["max_per_domain"] != "" {
+		maxPerDomain, err := strconv.Atoi(apiPlacementGroup.Config["max_per_domain"])
+		if err != nil {
+			return domains, fmt.Errorf("Invalid max_per_domain: %w", err)
+		}
+
+		domains.maxPerDomain = maxPerDomain
+	}
+
+	scope := apiPlacementGroup.Config["scope"]
+	if scope == "" || scope == api.PlacementScopeMember {
+		return domains, nil
+	}
+
+	members, err := tx.GetNodes(ctx)
+	if err != nil {
+		return domains, fmt.Errorf("Failed getting cluster members: %w", err)
+	}
+
+	domains.memberDomains = make(map[int64][]string, len(members))
+
+	switch scope {
+	case api.PlacementScopeFailureDomain:
+		memberFailureDomains, err := tx.GetNodesFailureDomains(ctx)
+		if err != nil {
+			return domains, fmt.Errorf("Failed getting failure domains: %w", err)
+		}
+
+		for _, member := range members {
+			domains.memberDomains[member.ID] = []string{strconv.FormatUint(memberFailureDomains[member.Address], 10)}
+		}
+
+	case api.PlacementScopeClusterGroup:
+		for _, member := range members {
+			domains.memberDomains[member.ID] = member.Groups
+		}
+
+	default:
+		return domains, fmt.Errorf("Invalid placement scope %q", scope)
+	}
+
+	return domains, nil
+}
+
 // getCompliantMembers gets compliant cluster members from the provided candidates based on the given placement policy and rigor.
-func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo, memberToInst map[int64][]int64) ([]db.NodeInfo, error) {
+// The policy is applied to the domains of the cluster members.
+func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo, memberToInst map[int64][]int64, domains placementDomains) ([]db.NodeInfo, error) {
 	var compliantCandidates []db.NodeInfo
 
+	// Count the instances in each domain.
+	domainToInst := make(map[string]int)
+	for memberID, instances := range memberToInst {
+		for _, domain := range domains.of(memberID) {
+			domainToInst[domain] += len(instances)
+		}
+	}
+
+	// candidateInstances returns the highest number of instances within the domains of a candidate.
+	candidateInstances := func(c db.NodeInfo) int {
+		count := 0
+		for _, domain := range domains.of(c.ID) {
+			count = max(count, domainToInst[domain])
+		}
+
+		return count
+	}
+
+	// inDomain returns whether the candidate is part of the domain.
+	inDomain := func(c db.NodeInfo, domain string) bool {
+		return slices.Contains(domains.of(c.ID), domain)
+	}
+
+	// mostInstancesDomain returns the domain with the most instances from this placement group.
+	mostInstancesDomain := func() string {
+		var targetDomain string
+		maxInstances := -1
+		for _, domain := range slices.Sorted(maps.Keys(domainToInst)) {
+			if domainToInst[domain] > maxInstances {
+				maxInstances = domainToInst[domain]
+				targetDomain = domain
+			}
+		}
+
+		return targetDomain
+	}
+
 	switch {
 	case policy == api.PlacementPolicySpread && rigor == api.PlacementRigorStrict:
-		// Spread + Strict: Place at most one instance per cluster member.
-		// Filter out candidates that already have instances.
+		// Spread + Strict: Place at most max_per_domain (default one) instances per domain.
+		// Filter out candidates whose domains are already full.
 		for _, c := range candidates {
-			_, hasInst := memberToInst[c.ID]
-			if !hasInst {
+			if candidateInstances(c) < domains.maxPerDomain {
 				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
@@ -61,13 +201,13 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicySpread && rigor == api.PlacementRigorPermissive:
-		// Spread + Permissive: Prefer spreading instances evenly across cluster members.
-		// The number of instances per cluster member differs by at most one.
+		// Spread + Permissive: Prefer spreading instances evenly across domains.
+		// The number of instances per domain differs by at most one.
 
 		// Find the minimum instance count among candidates.
 		counts := make([]int, 0, len(candidates))
 		for _, c := range candidates {
-			counts = append(counts, len(memberToInst[c.ID]))
+			counts = append(counts, candidateInstances(c))
 		}
 
 		minInstances := 0
@@ -76,10 +216,9 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		}
 
 		// Filter candidates to only those with at most minInstances instances.
-		// This ensures the number of instances per cluster member differs by at most one.
+		// This ensures the number of instances per domain differs by at most one.
 		for _, c := range candidates {
-			instanceCount := len(memberToInst[c.ID])
-			if instanceCount <= minInstances {
+			if candidateInstances(c) <= minInstances {
 				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
@@ -91,29 +230,19 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicyCompact && rigor == api.PlacementRigorStrict:
-		// Compact + Strict: Place all instances on the same cluster member.
-		// The member with the most instances determines the cluster member.
+		// Compact + Strict: Place all instances in the same domain.
+		// The domain with the most instances determines the domain.
 		if len(memberToInst) == 0 {
 			// No instances yet.
-			// All candidates are valid (first instance determines the member).
+			// All candidates are valid (first instance determines the domain).
 			return candidates, nil
 		}
 
-		// Find which member has the most instances from this placement group.
-		var targetMemberID int64
-		maxInstances := -1
-		for memberID, instances := range memberToInst {
-			if len(instances) > maxInstances {
-				maxInstances = len(instances)
-				targetMemberID = memberID
-			}
-		}
-
-		// Filter candidates to only include the node with the most instances.
+		// Filter candidates to only include the ones in the domain with the most instances.
+		targetDomain := mostInstancesDomain()
 		for _, c := range candidates {
-			if c.ID == targetMemberID {
+			if inDomain(c, targetDomain) {
 				compliantCandidates = append(compliantCandidates, c)
-				break
 			}
 		}
 
@@ -124,32 +253,26 @@ func getCompliantMembers(policy string, rigor string, candidates []db.NodeInfo,
 		return compliantCandidates, nil
 
 	case policy == api.PlacementPolicyCompact && rigor == api.PlacementRigorPermissive:
-		// Compact + Permissive: Prefer to place all instances on the same cluster member.
+		// Compact + Permissive: Prefer to place all instances in the same domain.
 		if len(memberToInst) == 0 {
 			// No instances yet.
-			// All candidates are valid (first instance determines preferred member).
+			// All candidates are valid (first instance determines preferred domain).
 			return candidates, nil
 		}
 
-		// Find which member has the most instances from this placement group.
-		var preferredMemberID int64
-		maxInstances := -1
-		for memberID, instances := range memberToInst {
-			if len(instances) > maxInstances {
-				maxInstances = len(instances)
-				preferredMemberID = memberID
+		// Check if candidates are available in the preferred domain.
+		preferredDomain := mostInstancesDomain()
+		for _, c := range candidates {
+			if inDomain(c, preferredDomain) {
+				compliantCandidates = append(compliantCandidates, c)
 			}
 		}
 
-		// Check if preferred member is in candidates.
-		for _, c := range candidates {
-			if c.ID == preferredMemberID {
-				// Preferred member is available.
-				return []db.NodeInfo{c}, nil
-			}
+		if len(compliantCandidates) > 0 {
+			return compliantCandidates, nil
 		}
 
-		// Preferred node is not available - fall back to all candidates.
+		// Preferred domain is not available - fall back to all candidates.
 		return candidates, nil
 
 	default:
@@ -631,3 +631,58 @@ func (s *filteringSuite) TestFilter() {
 		}
 	}
 }
+
+func (s *filteringSuite) TestGetCompliantMembersDomains() {
+	// Members 1 and 2 are in rack1, members 3 and 4 in rack2 and member 5 in rack3.
+	candidates := []db.NodeInfo{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	domains := placementDomains{
+		memberDomains: map[int64][]string{1: {"rack1"}, 2: {"rack1"}, 3: {"rack2"}, 4: {"rack2"}, 5: {"rack3"}},
+		maxPerDomain:  1,
+	}
+
+	// One instance in rack1.
+	memberToInst := map[int64][]int64{1: {100}}
+
+	got, err := getCompliantMembers(api.PlacementPolicySpread, api.PlacementRigorStrict, candidates, memberToInst, domains)
+	s.Require().NoError(err)
+	s.Equal([]db.NodeInfo{{ID: 3}, {ID: 4}, {ID: 5}}, got)
+
+	// Two instances per rack are allowed.
+	domains.maxPerDomain = 2
+	got, err = getCompliantMembers(api.PlacementPolicySpread, api.PlacementRigorStrict, candidates, memberToInst, domains)
+	s.Require().NoError(err)
+	s.Equal(candidates, got)
+
+	// All racks are full.
+	domains.maxPerDomain = 1
+	memberToInst = map[int64][]int64{1: {100}, 3: {101}, 5: {102}}
+	_, err = getCompliantMembers(api.PlacementPolicySpread, api.PlacementRigorStrict, candidates, memberToInst, domains)
+	s.Error(err)
+
+	// Permissive spread falls back to the least used racks.
+	memberToInst = map[int64][]int64{1: {100}, 2: {101}, 3: {102}, 5: {103}}
+	got, err = getCompliantMembers(api.PlacementPolicySpread, api.PlacementRigorPermissive, candidates, memberToInst, domains)
+	s.Require().NoError(err)
+	s.Equal([]db.NodeInfo{{ID: 3}, {ID: 4}, {ID: 5}}, got)
+
+	// Compact places instances in the rack with the most instances.
+	got, err = getCompliantMembers(api.PlacementPolicyCompact, api.PlacementRigorStrict, candidates, memberToInst, domains)
+	s.Require().NoError(err)
+	s.Equal([]db.NodeInfo{{ID: 1}, {ID: 2}}, got)
+
+	got, err = getCompliantMembers(api.PlacementPolicyCompact, api.PlacementRigorPermissive, candidates[2:], memberToInst, domains)
+	s.Require().NoError(err)
+	s.Equal(candidates[2:], got)
+
+	// Members in several cluster groups count the instances of all of them.
+	domains.memberDomains = map[int64][]string{1: {"default", "gpu"}, 2: {"default"}, 3: {"gpu"}}
+	memberToInst = map[int64][]int64{3: {100}}
+	got, err = getCompliantMembers(api.PlacementPolicySpread, api.PlacementRigorStrict, candidates[:3], memberToInst, domains)
+	s.Require().NoError(err)
+	s.Equal([]db.NodeInfo{{ID: 2}}, got)
+
+	// Without member domains each member is its own domain.
+	got, err = getCompliantMembers(api.PlacementPolicySpread, api.PlacementRigorStrict, candidates, memberToInst, placementDomains{maxPerDomain: 1})
+	s.Require().NoError(err)
+	s.Equal([]db.NodeInfo{{ID: 1}, {ID: 2}, {ID: 4}, {ID: 5}}, got)
+}
@@ -0,0 +1,374 @@
+package placement
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/db"
+	pcidev "github.com/canonical/lxd/lxd/device/pci"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/resources"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// SchedulerInstances places instances on the candidate cluster member with the least instances.
+const SchedulerInstances = "instances"
+
+// SchedulerResources places instances on the candidate cluster member with the most resource headroom.
+const SchedulerResources = "resources"
+
+// defaultVMMemory is the memory assumed for virtual machines without a memory limit.
+const defaultVMMemory = "1GiB"
+
+// defaultVMCPU is the number of CPU threads assumed for virtual machines without a CPU limit.
+const defaultVMCPU = 1
+
+// PCIMatch represents a PCI device the instance requires on the cluster member.
+// Empty fields match any device.
+type PCIMatch struct {
+	Address   string
+	VendorID  string
+	ProductID string
+}
+
+// Requirements represents the resources an instance needs on the cluster member it is placed on.
+type Requirements struct {
+	// Number of CPU threads needed (0 for no requirement).
+	CPU uint64
+
+	// IDs of the CPU threads the instance is pinned to, which must exist on the member.
+	PinnedCPUs []int64
+
+	// Memory needed in bytes (0 for no requirement).
+	Memory uint64
+
+	// Memory needed as a percentage of the member's total memory (0 for no requirement).
+	MemoryPercent float64
+
+	// Storage pool used by the root disk and the space it needs in bytes.
+	RootDiskPool string
+	RootDiskSize uint64
+
+	// Physical GPUs and PCI devices passed to the instance.
+	GPUs       []PCIMatch
+	PCIDevices []PCIMatch
+}
+
+// MemberResources represents the resources reported by a cluster member.
+type MemberResources struct {
+	// Resources is the member's hardware resources.
+	Resources *api.Resources
+
+	// Pools is the space of the member's storage pools, indexed by pool name.
+	Pools map[string]*api.ResourcesStoragePool
+
+	// AllocatedCPU is the number of CPU threads allocated to the instances on the member.
+	AllocatedCPU uint64
+
+	// UsedPCIAddresses are the addresses of the GPUs and PCI devices passed through to the instances on the member.
+	UsedPCIAddresses []string
+}
+
+// AddInstance records the resources allocated to an instance located on the member.
+// Only virtual machines get exclusive access to the GPUs and PCI devices passed to them.
+func (m *MemberResources) AddInstance(instanceType instancetype.Type, config map[string]string, devices map[string]map[string]string) error {
+	req, err := InstanceRequirements(instanceType, config, devices)
+	if err != nil {
+		return err
+	}
+
+	m.AllocatedCPU += req.CPU
+
+	if instanceType != instancetype.VM {
+		return nil
+	}
+
+	for name, dev := range devices {
+		var address string
+
+		switch dev["type"] {
+		case "gpu":
+			if dev["gputype"] != "" && dev["gputype"] != "physical" {
+				continue
+			}
+
+			address = dev["pci"]
+		case "pci":
+			address = dev["address"]
+		default:
+			continue
+		}
+
+		// Prefer the device actually passed through to a running instance.
+		slotName := config["volatile."+name+".last_state.pci.slot.name"]
+		if slotName != "" {
+			address = slotName
+		}
+
+		if address != "" {
+			m.UsedPCIAddresses = append(m.UsedPCIAddresses, pcidev.NormaliseAddress(address))
+		}
+	}
+
+	return nil
+}
+
+// pciAddressUsed returns whether the PCI device with the given address is passed through to an instance.
+func (m MemberResources) pciAddressUsed(address string) bool {
+	return slices.Contains(m.UsedPCIAddresses, pcidev.NormaliseAddress(address))
+}
+
+// InstanceRequirements returns the resource requirements of an instance from its expanded config and devices.
+func InstanceRequirements(instanceType instancetype.Type, config map[string]string, devices map[string]map[string]string) (*Requirements, error) {
+	req := &Requirements{}
+
+	limitsCPU := config["limits.cpu"]
+	if limitsCPU != "" {
+		count, err := strconv.ParseUint(limitsCPU, 10, 64)
+		if err == nil {
+			req.CPU = count
+		} else {
+			// Pinned CPUs must exist on the member.
+			cpus, err := resources.ParseCpuset(limitsCPU)
+			if err != nil {
+				return nil, err
+			}
+
+			req.CPU = uint64(len(cpus))
+			req.PinnedCPUs = cpus
+		}
+	} else if instanceType == instancetype.VM {
+		req.CPU = defaultVMCPU
+	}
+
+	limitsMemory := config["limits.memory"]
+	if limitsMemory == "" && instanceType == instancetype.VM {
+		limitsMemory = defaultVMMemory
+	}
+
+	if limitsMemory != "" {
+		percent, isPercent := strings.CutSuffix(limitsMemory, "%")
+		if isPercent {
+			value, err := strconv.ParseFloat(percent, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid memory limit %q: %w", limitsMemory, err)
+			}
+
+			req.MemoryPercent = value
+		} else {
+			value, err := units.ParseByteSizeString(limitsMemory)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid memory limit %q: %w", limitsMemory, err)
+			}
+
+			req.Memory = uint64(value)
+		}
+	}
+
+	_, rootDisk, err := api.GetRootDiskDevice(devices)
+	if err == nil {
+		req.RootDiskPool = rootDisk["pool"]
+
+		if rootDisk["size"] != "" {
+			size, err := units.ParseByteSizeString(rootDisk["size"])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid root disk size %q: %w", rootDisk["size"], err)
+			}
+
+			req.RootDiskSize = uint64(size)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(devices)) {
+		dev := devices[name]
+
+		switch dev["type"] {
+		case "gpu":
+			if dev["gputype"] != "" && dev["gputype"] != "physical" {
+				continue
+			}
+
+			req.GPUs = append(req.GPUs, PCIMatch{Address: dev["pci"], VendorID: dev["vendorid"], ProductID: dev["productid"]})
+		case "pci":
+			req.PCIDevices = append(req.PCIDevices, PCIMatch{Address: dev["address"]})
+		}
+	}
+
+	return req, nil
+}
+
+// matches returns whether the PCI device matches.
+func (m PCIMatch) matches(address string, vendorID string, productID string) bool {
+	if m.Address != "" && pcidev.NormaliseAddress(m.Address) != pcidev.NormaliseAddress(address) {
+		return false
+	}
+
+	if m.VendorID != "" && !strings.EqualFold(m.VendorID, vendorID) {
+		return false
+	}
+
+	if m.ProductID != "" && !strings.EqualFold(m.ProductID, productID) {
+		return false
+	}
+
+	return true
+}
+
+// Score returns how well an instance with the given requirements fits on the member, between 0 and 1.
+// The score is the average share of memory, CPU and root disk pool space left once the instance is placed,
+// taking into account the CPU threads already allocated to other instances.
+// An error is returned if the member cannot fit the instance.
+func (r Requirements) Score(member MemberResources) (float64, error) {
+	if member.Resources == nil {
+		return 0, errors.New("Resources unavailable")
+	}
+
+	res := member.Resources
+	var scores []float64
+
+	// Memory.
+	memTotal := res.Memory.Total
+	if memTotal > 0 {
+		memFree := memTotal - min(res.Memory.Used, memTotal)
+		memNeeded := max(r.Memory, uint64(float64(memTotal)*r.MemoryPercent/100))
+		if memNeeded > memFree {
+			return 0, fmt.Errorf("Not enough free memory (%s needed, %s free)", units.GetByteSizeStringIEC(int64(memNeeded), 2), units.GetByteSizeStringIEC(int64(memFree), 2))
+		}
+
+		scores = append(scores, float64(memFree-memNeeded)/float64(memTotal))
+	}
+
+	// CPU.
+	cpuTotal := res.CPU.Total
+	if cpuTotal > 0 {
+		cpuFree := cpuTotal - min(member.AllocatedCPU, cpuTotal)
+		if r.CPU > cpuFree {
+			return 0, fmt.Errorf("Not enough free CPU threads (%d needed, %d free)", r.CPU, cpuFree)
+		}
+
+		scores = append(scores, float64(cpuFree-r.CPU)/float64(cpuTotal))
+	}
+
+	// Pinned CPUs.
+	for _, id := range r.PinnedCPUs {
+		if !cpuThreadExists(res.CPU, id) {
+			return 0, fmt.Errorf("CPU thread %d not found", id)
+		}
+	}
+
+	// Root disk pool.
+	if r.RootDiskPool != "" {
+		pool := member.Pools[r.RootDiskPool]
+		if pool != nil && pool.Space.Total > 0 {
+			poolFree := pool.Space.Total - min(pool.Space.Used, pool.Space.Total)
+			if r.RootDiskSize > poolFree {
+				return 0, fmt.Errorf("Not enough free space in storage pool %q (%s needed, %s free)", r.RootDiskPool, units.GetByteSizeStringIEC(int64(r.RootDiskSize), 2), units.GetByteSizeStringIEC(int64(poolFree), 2))
+			}
+
+			scores = append(scores, float64(poolFree-r.RootDiskSize)/float64(pool.Space.Total))
+		}
+	}
+
+	// GPUs, each requested GPU needs its own card.
+	usedCards := make([]bool, len(res.GPU.Cards))
+	for _, gpu := range r.GPUs {
+		found := false
+		for i, card := range res.GPU.Cards {
+			if usedCards[i] || member.pciAddressUsed(card.PCIAddress) || !gpu.matches(card.PCIAddress, card.VendorID, card.ProductID) {
+				continue
+			}
+
+			usedCards[i] = true
+			found = true
+			break
+		}
+
+		if !found {
+			return 0, errors.New("No matching GPU available")
+		}
+	}
+
+	// PCI devices.
+	for _, pci := range r.PCIDevices {
+		found := slices.ContainsFunc(res.PCI.Devices, func(dev api.ResourcesPCIDevice) bool {
+			return pci.matches(dev.PCIAddress, dev.VendorID, dev.ProductID)
+		})
+
+		if !found {
+			return 0, fmt.Errorf("PCI device %q not found", pci.Address)
+		}
+
+		if member.pciAddressUsed(pci.Address) {
+			return 0, fmt.Errorf("PCI device %q is already in use", pci.Address)
+		}
+	}
+
+	if len(scores) == 0 {
+		return 0, nil
+	}
+
+	var total float64
+	for _, score := range scores {
+		total += score
+	}
+
+	return total / float64(len(scores)), nil
+}
+
+// cpuThreadExists returns whether the CPU thread with the given ID exists.
+// If the CPU topology isn't reported, thread IDs are assumed to range from 0 to the number of threads.
+func cpuThreadExists(cpu api.ResourcesCPU, id int64) bool {
+	if len(cpu.Sockets) == 0 {
+		return id >= 0 && uint64(id) < cpu.Total
+	}
+
+	for _, socket := range cpu.Sockets {
+		for _, core := range socket.Cores {
+			for _, thread := range core.Threads {
+				if thread.ID == id {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// SelectByResources returns the candidate cluster member with the highest resource score for the requirements.
+// Candidates without reported resources or that cannot fit the instance are rejected.
+// Ties are resolved in favour of the earliest candidate.
+func SelectByResources(candidates []db.NodeInfo, req Requirements, memberResources map[string]MemberResources) (*db.NodeInfo, error) {
+	var selected *db.NodeInfo
+	bestScore := -1.0
+	var rejections []string
+
+	for i, candidate := range candidates {
+		score, err := req.Score(memberResources[candidate.Name])
+		if err != nil {
+			rejections = append(rejections, candidate.Name+": "+err.Error())
+			continue
+		}
+
+		if score > bestScore {
+			bestScore = score
+			selected = &candidates[i]
+		}
+	}
+
+	if selected == nil {
+		if len(rejections) == 0 {
+			return nil, api.StatusErrorf(http.StatusNotFound, "No suitable cluster member could be found")
+		}
+
+		return nil, api.StatusErrorf(http.StatusNotFound, "No cluster member has enough resources for the instance (%s)", strings.Join(rejections, "; "))
+	}
+
+	return selected, nil
+}
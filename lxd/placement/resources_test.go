@@ -0,0 +1,190 @@
+package placement
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared/api"
+)
+
+const gib = 1024 * 1024 * 1024
+
+// memberResources returns the resources of a member with the given CPU threads, memory, free memory and free root pool space.
+func memberResources(cpus uint64, memTotal uint64, memFree uint64, poolFree uint64) MemberResources {
+	return MemberResources{
+		Resources: &api.Resources{
+			CPU:    api.ResourcesCPU{Total: cpus},
+			Memory: api.ResourcesMemory{Total: memTotal, Used: memTotal - memFree},
+			GPU: api.ResourcesGPU{
+				Cards: []api.ResourcesGPUCard{{PCIAddress: "0000:01:00.0", VendorID: "10de", ProductID: "1eb8"}},
+				Total: 1,
+			},
+			PCI: api.ResourcesPCI{
+				Devices: []api.ResourcesPCIDevice{{PCIAddress: "0000:02:00.0", VendorID: "8086", ProductID: "1572"}},
+				Total:   1,
+			},
+		},
+		Pools: map[string]*api.ResourcesStoragePool{
+			"default": {Space: api.ResourcesStoragePoolSpace{Total: 100 * gib, Used: 100*gib - poolFree}},
+		},
+	}
+}
+
+func TestInstanceRequirements(t *testing.T) {
+	devices := map[string]map[string]string{
+		"root": {"type": "disk", "path": "/", "pool": "default", "size": "10GiB"},
+		"gpu0": {"type": "gpu", "vendorid": "10de"},
+		"gpu1": {"type": "gpu", "gputype": "mdev", "mdev": "i915-GVTg_V5_4"},
+		"pci0": {"type": "pci", "address": "02:00.0"},
+	}
+
+	req, err := InstanceRequirements(instancetype.Container, map[string]string{"limits.cpu": "4", "limits.memory": "2GiB"}, devices)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), req.CPU)
+	assert.Equal(t, uint64(2*gib), req.Memory)
+	assert.Equal(t, "default", req.RootDiskPool)
+	assert.Equal(t, uint64(10*gib), req.RootDiskSize)
+	assert.Equal(t, []PCIMatch{{VendorID: "10de"}}, req.GPUs)
+	assert.Equal(t, []PCIMatch{{Address: "02:00.0"}}, req.PCIDevices)
+
+	// Pinned CPUs and relative memory.
+	req, err = InstanceRequirements(instancetype.Container, map[string]string{"limits.cpu": "0,6-7", "limits.memory": "50%"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), req.CPU)
+	assert.Equal(t, []int64{0, 6, 7}, req.PinnedCPUs)
+	assert.Equal(t, 50.0, req.MemoryPercent)
+
+	// Virtual machines have a default memory size and CPU count.
+	req, err = InstanceRequirements(instancetype.VM, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(gib), req.Memory)
+	assert.Equal(t, uint64(1), req.CPU)
+
+	_, err = InstanceRequirements(instancetype.Container, map[string]string{"limits.memory": "foo"}, nil)
+	assert.Error(t, err)
+}
+
+func TestRequirementsScore(t *testing.T) {
+	member := memberResources(8, 16*gib, 8*gib, 50*gib)
+
+	tests := []struct {
+		name    string
+		req     Requirements
+		wantErr bool
+	}{
+		{name: "No requirements", req: Requirements{}},
+		{name: "Fits", req: Requirements{CPU: 8, Memory: 8 * gib, RootDiskPool: "default", RootDiskSize: 50 * gib}},
+		{name: "Too many CPUs", req: Requirements{CPU: 9}, wantErr: true},
+		{name: "Pinned CPUs", req: Requirements{CPU: 2, PinnedCPUs: []int64{0, 7}}},
+		{name: "Missing pinned CPU", req: Requirements{CPU: 2, PinnedCPUs: []int64{0, 8}}, wantErr: true},
+		{name: "Not enough memory", req: Requirements{Memory: 9 * gib}, wantErr: true},
+		{name: "Not enough relative memory", req: Requirements{MemoryPercent: 75}, wantErr: true},
+		{name: "Not enough pool space", req: Requirements{RootDiskPool: "default", RootDiskSize: 51 * gib}, wantErr: true},
+		{name: "Unknown pool", req: Requirements{RootDiskPool: "other", RootDiskSize: 51 * gib}},
+		{name: "Matching GPU", req: Requirements{GPUs: []PCIMatch{{VendorID: "10DE"}}}},
+		{name: "Not enough GPUs", req: Requirements{GPUs: []PCIMatch{{}, {}}}, wantErr: true},
+		{name: "No matching GPU", req: Requirements{GPUs: []PCIMatch{{VendorID: "1002"}}}, wantErr: true},
+		{name: "Matching PCI device", req: Requirements{PCIDevices: []PCIMatch{{Address: "02:00.0"}}}},
+		{name: "Missing PCI device", req: Requirements{PCIDevices: []PCIMatch{{Address: "03:00.0"}}}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			score, err := tc.req.Score(member)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, score, 0.0)
+			assert.LessOrEqual(t, score, 1.0)
+		})
+	}
+
+	_, err := Requirements{}.Score(MemberResources{})
+	assert.Error(t, err)
+}
+
+func TestRequirementsScoreAllocations(t *testing.T) {
+	member := memberResources(8, 16*gib, 8*gib, 50*gib)
+
+	// A VM with 6 CPUs and the GPU, and a container sharing the PCI device which isn't passed through.
+	require.NoError(t, member.AddInstance(instancetype.VM, map[string]string{"limits.cpu": "6", "volatile.gpu0.last_state.pci.slot.name": "0000:01:00.0"}, map[string]map[string]string{
+		"gpu0": {"type": "gpu", "vendorid": "10de"},
+	}))
+
+	require.NoError(t, member.AddInstance(instancetype.Container, map[string]string{"limits.cpu": "1"}, map[string]map[string]string{
+		"gpu0": {"type": "gpu", "pci": "0000:02:00.0"},
+	}))
+
+	assert.Equal(t, uint64(7), member.AllocatedCPU)
+	assert.Equal(t, []string{"0000:01:00.0"}, member.UsedPCIAddresses)
+
+	tests := []struct {
+		name    string
+		req     Requirements
+		wantErr bool
+	}{
+		{name: "Fits in free CPUs", req: Requirements{CPU: 1}},
+		{name: "Allocated CPUs are not free", req: Requirements{CPU: 2}, wantErr: true},
+		{name: "GPU in use", req: Requirements{GPUs: []PCIMatch{{VendorID: "10de"}}}, wantErr: true},
+		{name: "PCI device not in use", req: Requirements{PCIDevices: []PCIMatch{{Address: "02:00.0"}}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.req.Score(member)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+
+	// PCI devices passed through to a VM are in use.
+	require.NoError(t, member.AddInstance(instancetype.VM, nil, map[string]map[string]string{
+		"nic0": {"type": "pci", "address": "02:00.0"},
+	}))
+
+	_, err := Requirements{PCIDevices: []PCIMatch{{Address: "0000:02:00.0"}}}.Score(member)
+	assert.Error(t, err)
+}
+
+func TestSelectByResources(t *testing.T) {
+	candidates := []db.NodeInfo{{Name: "member01"}, {Name: "member02"}, {Name: "member03"}, {Name: "member04"}}
+	resources := map[string]MemberResources{
+		"member01": memberResources(4, 16*gib, 2*gib, 80*gib),
+		"member02": memberResources(16, 64*gib, 48*gib, 60*gib),
+		"member03": memberResources(16, 64*gib, 60*gib, 90*gib),
+		// member04 did not report its resources.
+	}
+
+	// The member with the most headroom is selected.
+	member, err := SelectByResources(candidates, Requirements{CPU: 2, Memory: gib}, resources)
+	require.NoError(t, err)
+	assert.Equal(t, "member03", member.Name)
+
+	// Members which cannot fit the instance are rejected.
+	member, err = SelectByResources(candidates, Requirements{RootDiskPool: "default", RootDiskSize: 70 * gib}, resources)
+	require.NoError(t, err)
+	assert.Equal(t, "member03", member.Name)
+
+	member, err = SelectByResources(candidates[:2], Requirements{Memory: 4 * gib}, resources)
+	require.NoError(t, err)
+	assert.Equal(t, "member02", member.Name)
+
+	// An error is returned when no member can fit the instance.
+	_, err = SelectByResources(candidates, Requirements{CPU: 32}, resources)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	_, err = SelectByResources(nil, Requirements{}, resources)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 
@@ -750,6 +751,27 @@ func placementGroupValidateConfig(config map[string]string) error {
 		//  required: "yes"
 		//  shortdesc: Enforcement level of the placement policy
 		"rigor": validate.IsOneOf(api.PlacementRigorStrict, api.PlacementRigorPermissive),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=scope)
+		// Determines what the policy applies to.
+		//
+		// Possible values are `member` (individual cluster members), `failure-domain`
+		// (failure domains of the cluster members) and `cluster-group` (cluster groups).
+		// See {ref}`cluster-placement-groups-domains` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: `member`
+		//  shortdesc: Domain the placement policy applies to
+		"scope": validate.Optional(validate.IsOneOf(api.PlacementScopeMember, api.PlacementScopeFailureDomain, api.PlacementScopeClusterGroup)),
+
+		// lxdmeta:generate(entities=placement-group; group=placement-group; key=max_per_domain)
+		// Maximum number of instances placed in each domain (as defined by `scope`) with the `spread` policy.
+		// With `strict` rigor, placement fails once all domains are full.
+		// ---
+		//  type: integer
+		//  defaultdesc: `1`
+		//  shortdesc: Maximum number of instances per domain
+		"max_per_domain": validate.Optional(validate.IsInRange(1, math.MaxInt32)),
 	}
 
 	for k, v := range config {
@@ -781,5 +803,9 @@ func placementGroupValidateConfig(config map[string]string) error {
 		}
 	}
 
+	if config["max_per_domain"] != "" && config["policy"] != api.PlacementPolicySpread {
+		return api.StatusErrorf(http.StatusBadRequest, "Config key %q requires the %q policy", "max_per_domain", api.PlacementPolicySpread)
+	}
+
 	return nil
 }
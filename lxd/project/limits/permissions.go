@@ -306,6 +306,32 @@ func AllowVolumeCreation(ctx context.Context, globalConfig *clusterConfig.Config
 	return nil
 }
 
+// AllowBucketCreation returns an error if any project-specific limit or
+// restriction is violated when creating a new storage bucket on a local pool in a project.
+func AllowBucketCreation(ctx context.Context, globalConfig *clusterConfig.Config, tx *db.ClusterTx, projectName string, poolName string, req api.StorageBucketsPost) error {
+	info, err := FetchProject(ctx, tx, projectName, true)
+	if err != nil {
+		return err
+	}
+
+	if info == nil {
+		return nil
+	}
+
+	// Add the bucket being created.
+	bucket := &db.StorageBucket{PoolName: poolName}
+	bucket.Name = req.Name
+	bucket.Config = req.Config
+	info.Buckets = append(info.Buckets, bucket)
+
+	err = checkInstanceRestrictionsAndAggregateLimits(globalConfig, info)
+	if err != nil {
+		return fmt.Errorf("Failed checking if bucket creation allowed: %w", err)
+	}
+
+	return nil
+}
+
 // AllowVolumeMove returns an error if any project-specific limit or restriction
 // is violated when moving an existing custom volume to targetPoolName in
 // targetProjectName. sourcePoolName and sourceVolumeName identify the volume
@@ -1096,6 +1122,35 @@ func AllowVolumeUpdate(ctx context.Context, globalConfig *clusterConfig.Config,
 	return nil
 }
 
+// AllowBucketUpdate returns an error if any project-specific limit or
+// restriction is violated when updating an existing storage bucket on a local pool.
+func AllowBucketUpdate(ctx context.Context, globalConfig *clusterConfig.Config, tx *db.ClusterTx, projectName string, poolName string, bucketName string, req api.StorageBucketPut) error {
+	info, err := FetchProject(ctx, tx, projectName, true)
+	if err != nil {
+		return err
+	}
+
+	if info == nil {
+		return nil
+	}
+
+	// Change the bucket being updated.
+	for _, bucket := range info.Buckets {
+		if bucket.Name != bucketName || bucket.PoolName != poolName {
+			continue
+		}
+
+		bucket.Config = req.Config
+	}
+
+	err = checkInstanceRestrictionsAndAggregateLimits(globalConfig, info)
+	if err != nil {
+		return fmt.Errorf("Failed checking if bucket update allowed: %w", err)
+	}
+
+	return nil
+}
+
 // AllowProfileUpdate checks that project limits and restrictions are not
 // violated when changing a profile.
 func AllowProfileUpdate(ctx context.Context, globalConfig *clusterConfig.Config, tx *db.ClusterTx, projectName, profileName string, req api.ProfilePut) error {
@@ -1352,6 +1407,7 @@ type ProjectInfo struct {
 	Profiles  []api.Profile
 	Instances []api.Instance
 	Volumes   []db.StorageVolumeArgs
+	Buckets   []*db.StorageBucket
 	Networks  []string
 
 	// poolName: driverName
@@ -1359,9 +1415,9 @@ type ProjectInfo struct {
 }
 
 // FetchProject fetches the given project from the database along with its
-// profiles, instances, networks, and possibly custom volumes.
+// profiles, instances, networks, and possibly custom volumes and storage buckets.
 //
-// If the skipIfNoLimits flag is true, then profiles, instances and volumes
+// If the skipIfNoLimits flag is true, then profiles, instances, volumes and buckets
 // won't be loaded if the project has no restrictions or limits set on it, and nil will be returned.
 func FetchProject(ctx context.Context, tx *db.ClusterTx, projectName string, skipIfNoLimits bool) (*ProjectInfo, error) {
 	dbProject, err := cluster.GetProject(ctx, tx.Tx(), projectName)
@@ -1401,6 +1457,18 @@ func FetchProject(ctx context.Context, tx *db.ClusterTx, projectName string, ski
 		return nil, fmt.Errorf("Fetch project custom volumes from database: %w", err)
 	}
 
+	buckets, err := tx.GetStoragePoolBuckets(ctx, false, db.StorageBucketFilter{Project: &projectName})
+	if err != nil {
+		return nil, fmt.Errorf("Fetch project storage buckets from database: %w", err)
+	}
+
+	// Only buckets on local pools consume space in the pool, cephobject buckets are stored by radosgw.
+	for _, bucket := range buckets {
+		if info.StoragePoolDrivers[bucket.PoolName] != "cephobject" {
+			info.Buckets = append(info.Buckets, bucket)
+		}
+	}
+
 	info.Networks, err = tx.GetCreatedNetworkNamesByProject(ctx, projectName)
 	if err != nil {
 		return nil, fmt.Errorf("Fetch project networks from database: %w", err)
@@ -1471,6 +1539,28 @@ func getTotalsAcrossProjectEntities(info *ProjectInfo, keys []string, skipUnset
 
 				totals[key] += limit
 			}
+
+			for _, bucket := range info.Buckets {
+				if poolName != "" && bucket.PoolName != poolName {
+					continue
+				}
+
+				value, ok := bucket.Config["size"]
+				if !ok {
+					if skipUnset {
+						continue
+					}
+
+					return nil, fmt.Errorf(`Storage bucket %q in project %q has no "size" config set`, bucket.Name, info.Project.Name)
+				}
+
+				limit, err := units.ParseByteSizeString(value)
+				if err != nil {
+					return nil, fmt.Errorf(`Parse "size" for storage bucket %q in project %q: %w`, bucket.Name, info.Project.Name, err)
+				}
+
+				totals[key] += limit
+			}
 		}
 	}
 
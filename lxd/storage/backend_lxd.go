@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
@@ -378,6 +379,29 @@ func (b *lxdBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return b.driver.GetResources()
 }
 
+// GetHealth returns the health of the pool and the devices backing it.
+func (b *lxdBackend) GetHealth() (*api.StoragePoolHealth, error) {
+	l := b.logger.AddContext(nil)
+	l.Debug("GetHealth started")
+	defer l.Debug("GetHealth finished")
+
+	return b.driver.GetHealth()
+}
+
+// Scrub starts verifying the integrity of the data stored in the pool.
+func (b *lxdBackend) Scrub() error {
+	l := b.logger.AddContext(nil)
+	l.Debug("Scrub started")
+	defer l.Debug("Scrub finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	return b.driver.Scrub()
+}
+
 // IsUsed returns whether the storage pool is used by any volumes or profiles (excluding image volumes).
 func (b *lxdBackend) IsUsed() (bool, error) {
 	usedBy, err := UsedBy(context.TODO(), b.state, b, true, true, cluster.StoragePoolVolumeTypeNameImage)
@@ -778,7 +802,7 @@ func (b *lxdBackend) CreateInstance(inst instance.Instance, progressReporter iop
 	}
 
 	// Validate config and create database entry for new storage volume.
-	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, true, false)
+	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, true, false, true)
 	if err != nil {
 		return err
 	}
@@ -901,6 +925,23 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 	// Don't use GetNewVolume as the new volume' UUID got already set beforehand.
 	vol := b.GetVolume(volType, contentType, volStorageName, volumeConfig)
 
+	// Replace the encryption key if it cannot be unwrapped by this server.
+	// This is done before unpacking as the unpacked volume is encrypted with it.
+	wrappedKey := vol.Config()["volatile.encryption.key"]
+	err = ensureVolumeEncryptionKey(b.state, vol, true, b.driver.Info().Remote)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = checkOptimizedBackupKey(srcBackup, vol, wrappedKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if vol.IsEncrypted() {
+		volumeConfig["volatile.encryption.key"] = vol.Config()["volatile.encryption.key"]
+	}
+
 	sourceSnapshots := make([]drivers.Volume, 0, len(rootVol.Snapshots))
 	for i, volSnap := range rootVol.Snapshots {
 		if volSnap == nil {
@@ -981,7 +1022,7 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 
 		// Validate config and create database entry for new storage volume.
 		// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-		err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), volumeDescription, volType, false, volumeConfig, volumeCreationDate, time.Time{}, contentType, true, true)
+		err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), volumeDescription, volType, false, volumeConfig, volumeCreationDate, time.Time{}, contentType, true, true, true)
 		if err != nil {
 			return err
 		}
@@ -1022,7 +1063,7 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 
 			// Validate config and create database entry for new storage volume.
 			// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, volumeSnapDescription, volType, true, volumeSnapConfig, volumeSnapCreationDate, volumeSnapExpiryDate, contentType, true, true)
+			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, volumeSnapDescription, volType, true, volumeSnapConfig, volumeSnapCreationDate, volumeSnapExpiryDate, contentType, true, true, true)
 			if err != nil {
 				return err
 			}
@@ -1131,6 +1172,150 @@ func (b *lxdBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io.
 	return postHook, revertHook, nil
 }
 
+// RefreshInstanceFromBackup applies an incremental backup on top of an existing instance's root volume.
+// The volume's data is updated and volume records for the snapshots included in the backup are created.
+// The returned revert hook can be used to undo the changes if creating the instance snapshot records fails.
+func (b *lxdBackend) RefreshInstanceFromBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, progressReporter ioprogress.ProgressReporter) (revert.Hook, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "snapshots": srcBackup.Snapshots, "baseSnapshot": srcBackup.BaseSnapshot})
+	l.Debug("RefreshInstanceFromBackup started")
+	defer l.Debug("RefreshInstanceFromBackup finished")
+
+	if srcBackup.Config == nil {
+		return nil, errors.New("Backup config is missing")
+	}
+
+	if !b.driver.Info().IncrementalBackups {
+		return nil, fmt.Errorf("Storage pool %q does not support incremental backups", b.name)
+	}
+
+	if inst.IsRunning() {
+		return nil, errors.New("Cannot apply incremental backup to a running instance")
+	}
+
+	rootVol, err := srcBackup.Config.RootVolume()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting the root volume: %w", err)
+	}
+
+	// Validate the names in the backup.yaml file as these could be malicious.
+	for _, snapName := range srcBackup.Snapshots {
+		err = instancetype.ValidName(inst.Name()+shared.SnapshotDelimiter+snapName, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Check the backup follows the most recent snapshot of the instance.
+	instSnapshots, err := inst.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	snapNames := make([]string, 0, len(instSnapshots))
+	for _, instSnapshot := range instSnapshots {
+		_, snapName, _ := api.GetParentAndSnapshotName(instSnapshot.Name())
+		snapNames = append(snapNames, snapName)
+	}
+
+	err = srcBackup.CheckIncrementalBase(snapNames)
+	if err != nil {
+		return nil, err
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := InstanceContentType(inst)
+
+	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the effective root device volume for instance.
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, contentType, volStorageName, dbVol.Config)
+	err = b.applyInstanceRootDiskOverrides(inst, &vol)
+	if err != nil {
+		return nil, err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Create database entries for the snapshots included in the backup.
+	sourceSnapshots := make([]drivers.Volume, 0, len(srcBackup.Snapshots))
+	for _, snapName := range srcBackup.Snapshots {
+		var volSnap *api.StorageVolumeSnapshot
+		for _, s := range rootVol.Snapshots {
+			if s != nil && s.Name == snapName {
+				volSnap = s
+				break
+			}
+		}
+
+		if volSnap == nil {
+			return nil, fmt.Errorf("Root volume snapshot %q not found in backup config", snapName)
+		}
+
+		var snapExpiryDate time.Time
+		if volSnap.ExpiresAt != nil {
+			snapExpiryDate = *volSnap.ExpiresAt
+		}
+
+		// Don't reuse the UUID of the backed up snapshot.
+		snapConfig := maps.Clone(volSnap.Config)
+		if snapConfig == nil {
+			snapConfig = make(map[string]string)
+		}
+
+		snapConfig["volatile.uuid"] = uuid.New().String()
+
+		fullSnapName := drivers.GetSnapshotVolumeName(inst.Name(), snapName)
+		err = VolumeDBCreate(b, inst.Project().Name, fullSnapName, volSnap.Description, volType, true, snapConfig, volSnap.CreatedAt, snapExpiryDate, contentType, true, true, true)
+		if err != nil {
+			return nil, err
+		}
+
+		revert.Add(func() { _ = VolumeDBDelete(b, inst.Project().Name, fullSnapName, volType) })
+
+		snapshotStorageName := project.Instance(inst.Project().Name, fullSnapName)
+		sourceSnapshots = append(sourceSnapshots, b.GetVolume(volType, contentType, snapshotStorageName, snapConfig))
+	}
+
+	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
+
+	// Apply the backup on top of the existing storage volume(s).
+	volPostHook, revertHook, err := b.driver.CreateVolumeFromBackup(volCopy, srcBackup, srcData, progressReporter)
+	if err != nil {
+		return nil, err
+	}
+
+	if revertHook != nil {
+		revert.Add(revertHook)
+	}
+
+	if volPostHook != nil {
+		err = volPostHook(vol)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(srcBackup.Snapshots) > 0 {
+		err = b.ensureInstanceSnapshotSymlink(inst.Type(), inst.Project().Name, inst.Name())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cleanup := revert.Clone().Fail
+	revert.Success()
+	return cleanup, nil
+}
+
 // CreateInstanceFromCopy copies an instance volume and optionally its snapshots to new volume(s).
 func (b *lxdBackend) CreateInstanceFromCopy(ctx context.Context, inst instance.Instance, src instance.Instance, snapshots bool, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error {
 	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "src": src.Name(), "snapshots": snapshots})
@@ -1258,7 +1443,7 @@ func (b *lxdBackend) CreateInstanceFromCopy(ctx context.Context, inst instance.I
 		l.Debug("CreateInstanceFromCopy same-pool mode detected")
 
 		// Validate config and create database entry for new storage volume.
-		err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", vol.Type(), false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, false, true)
+		err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", vol.Type(), false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, false, true, true)
 		if err != nil {
 			return err
 		}
@@ -1279,7 +1464,7 @@ func (b *lxdBackend) CreateInstanceFromCopy(ctx context.Context, inst instance.I
 			snapVol := b.GetNewVolume(volType, contentType, newSnapshotStorageName, rootVol.Snapshots[i].Config)
 
 			// Validate config and create database entry for new storage volume.
-			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, rootVol.Snapshots[i].Description, vol.Type(), true, snapVol.Config(), rootVol.Snapshots[i].CreatedAt, volumeSnapExpiryDate, vol.ContentType(), false, true)
+			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, rootVol.Snapshots[i].Description, vol.Type(), true, snapVol.Config(), rootVol.Snapshots[i].CreatedAt, volumeSnapExpiryDate, vol.ContentType(), false, true, true)
 			if err != nil {
 				return err
 			}
@@ -1564,7 +1749,7 @@ func (b *lxdBackend) RefreshCustomVolume(ctx context.Context, projectName, srcPr
 			targetSnapVol := b.GetNewVolume(drivers.VolumeTypeCustom, contentType, targetSnapVolStorageName, srcSnap.Config)
 
 			// Validate config and create database entry for new storage volume from source volume config.
-			err = VolumeDBCreate(b, projectName, newSnapshotName, srcSnap.Description, drivers.VolumeTypeCustom, true, targetSnapVol.Config(), srcSnap.CreatedAt, snapExpiryDate, contentType, false, true)
+			err = VolumeDBCreate(b, projectName, newSnapshotName, srcSnap.Description, drivers.VolumeTypeCustom, true, targetSnapVol.Config(), srcSnap.CreatedAt, snapExpiryDate, contentType, false, true, true)
 			if err != nil {
 				return err
 			}
@@ -1861,7 +2046,7 @@ func (b *lxdBackend) RefreshInstance(ctx context.Context, inst instance.Instance
 			snapVol := b.GetNewVolume(volType, contentType, newSnapshotName, rootVol.Snapshots[i].Config)
 
 			// Validate config and create database entry for new storage volume.
-			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, rootVol.Snapshots[i].Description, volType, true, snapVol.Config(), rootVol.Snapshots[i].CreatedAt, volumeSnapExpiryDate, contentType, false, true)
+			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, rootVol.Snapshots[i].Description, volType, true, snapVol.Config(), rootVol.Snapshots[i].CreatedAt, volumeSnapExpiryDate, contentType, false, true, true)
 			if err != nil {
 				return err
 			}
@@ -2200,7 +2385,7 @@ func (b *lxdBackend) CreateInstanceFromImage(ctx context.Context, inst instance.
 	}
 
 	// Validate config and create database entry for new storage volume.
-	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, true, false)
+	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, true, false, true)
 	if err != nil {
 		return err
 	}
@@ -2220,9 +2405,13 @@ func (b *lxdBackend) CreateInstanceFromImage(ctx context.Context, inst instance.
 	}
 
 	// Ensure the required image variant exists; nil means fall back to slow-unpack.
-	imgVol, err := b.EnsureImage(ctx, fingerprint, inst.Project().Name, inst, progressReporter)
-	if err != nil {
-		return err
+	// Encrypted volumes are always unpacked as they cannot be cloned from an unencrypted image volume.
+	var imgVol *drivers.Volume
+	if !vol.IsEncrypted() {
+		imgVol, err = b.EnsureImage(ctx, fingerprint, inst.Project().Name, inst, progressReporter)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Clone from the cached image volume when one was prepared; otherwise
@@ -2412,7 +2601,7 @@ func (b *lxdBackend) CreateInstanceFromMigration(ctx context.Context, inst insta
 		} else {
 			// Validate config and create database entry for new storage volume if not refreshing.
 			// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-			err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), volumeDescription, volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, true, true)
+			err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), volumeDescription, volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, true, true, true)
 			if err != nil {
 				return err
 			}
@@ -2457,7 +2646,7 @@ func (b *lxdBackend) CreateInstanceFromMigration(ctx context.Context, inst insta
 
 			// Validate config and create database entry for new storage volume.
 			// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, snapDescription, volType, true, snapVol.Config(), snapCreationDate, snapExpiryDate, contentType, true, true)
+			err = VolumeDBCreate(b, inst.Project().Name, newSnapshotName, snapDescription, volType, true, snapVol.Config(), snapCreationDate, snapExpiryDate, contentType, true, true, true)
 			if err != nil {
 				return err
 			}
@@ -2663,7 +2852,7 @@ func (b *lxdBackend) CreateInstanceFromConversion(inst instance.Instance, conn i
 
 	// Validate config and create database entry for new storage volume if not refreshing.
 	// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), args.Description, volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, false, true)
+	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), args.Description, volType, false, vol.Config(), inst.CreationDate(), time.Time{}, contentType, false, true, true)
 	if err != nil {
 		return err
 	}
@@ -3050,6 +3239,8 @@ var instanceVolumeConfigPolicy = api.ConfigKeyPolicy{
 		"size",
 		"size.state",
 		"block.filesystem",
+		"security.encrypted",
+		"volatile.encryption.key",
 	},
 }
 
@@ -3058,6 +3249,10 @@ var customVolumeConfigPolicy = api.ConfigKeyPolicy{
 	Immutable: []string{
 		"block.filesystem",
 		"volatile.uuid",
+		"security.encrypted",
+		"volatile.encryption.key",
+		"volatile.mirror.source",
+		"volatile.mirror.last_refresh",
 	},
 }
 
@@ -3385,12 +3580,35 @@ func (b *lxdBackend) CleanupInstancePaths(inst instance.Instance, _ ioprogress.P
 	return nil
 }
 
+// checkIncrementalBackup checks whether an incremental backup sent from baseSnapshot can be created.
+func (b *lxdBackend) checkIncrementalBackup(optimized bool, baseSnapshot string) error {
+	if baseSnapshot == "" {
+		return nil
+	}
+
+	if !b.driver.Info().IncrementalBackups {
+		return fmt.Errorf("Storage pool %q does not support incremental backups", b.name)
+	}
+
+	if !optimized {
+		return errors.New("Incremental backups require the optimized storage format")
+	}
+
+	return nil
+}
+
 // BackupInstance creates an instance backup.
-func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, version uint32, progressReporter ioprogress.ProgressReporter) error {
-	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots})
+// If baseSnapshot is set only the changes since that snapshot are included in the backup.
+func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, baseSnapshot string, version uint32, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "optimized": optimized, "snapshots": snapshots, "baseSnapshot": baseSnapshot})
 	l.Debug("BackupInstance started")
 	defer l.Debug("BackupInstance finished")
 
+	err := b.checkIncrementalBackup(optimized, baseSnapshot)
+	if err != nil {
+		return err
+	}
+
 	volType, err := InstanceTypeToVolumeType(inst.Type())
 	if err != nil {
 		return err
@@ -3414,7 +3632,7 @@ func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancew
 
 	var snapNames []string
 	var sourceSnapshots []drivers.Volume
-	if snapshots {
+	if snapshots || baseSnapshot != "" {
 		// Get snapshots in age order, oldest first, and pass names to storage driver.
 		instSnapshots, err := inst.Snapshots()
 		if err != nil {
@@ -3437,9 +3655,14 @@ func (b *lxdBackend) BackupInstance(inst instance.Instance, tarWriter *instancew
 		}
 	}
 
+	snapNames, err = backupSnapshotNames(snapNames, snapshots, baseSnapshot)
+	if err != nil {
+		return err
+	}
+
 	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
 
-	err = b.driver.BackupVolume(volCopy, inst.Project().Name, tarWriter, optimized, snapNames, progressReporter)
+	err = b.driver.BackupVolume(volCopy, inst.Project().Name, tarWriter, optimized, snapNames, baseSnapshot, progressReporter)
 	if err != nil {
 		return err
 	}
@@ -3738,7 +3961,7 @@ func (b *lxdBackend) CreateInstanceSnapshot(inst instance.Instance, src instance
 	defer unlock()
 
 	// Validate config and create database entry for new storage volume.
-	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), srcDBVol.Description, volType, true, vol.Config(), inst.CreationDate(), time.Time{}, contentType, false, true)
+	err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), srcDBVol.Description, volType, true, vol.Config(), inst.CreationDate(), time.Time{}, contentType, false, true, true)
 	if err != nil {
 		return err
 	}
@@ -4194,6 +4417,50 @@ func (b *lxdBackend) UnmountInstanceSnapshot(inst instance.Instance, progressRep
 	return err
 }
 
+// DiffInstanceSnapshot returns the paths which differ between an instance snapshot and either a later snapshot of
+// the instance or, if againstName is empty, the instance itself.
+func (b *lxdBackend) DiffInstanceSnapshot(inst instance.Instance, snapshotName string, againstName string, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "snapshotName": snapshotName, "againstName": againstName})
+	l.Debug("DiffInstanceSnapshot started")
+	defer l.Debug("DiffInstanceSnapshot finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return nil, err
+	}
+
+	if inst.IsSnapshot() {
+		return nil, errors.New("Instance cannot be a snapshot")
+	}
+
+	// The root disk of virtual machines is a block volume.
+	if inst.Type() != instancetype.Container {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Only snapshots of containers can be compared")
+	}
+
+	// Check we can convert the instance to the volume type needed.
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	// Load storage volume from database.
+	dbVol, err := VolumeDBGet(b, inst.Project().Name, inst.Name(), volType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the effective root device volume for instance.
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, InstanceContentType(inst), volStorageName, dbVol.Config)
+	err = b.applyInstanceRootDiskOverrides(inst, &vol)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.diffVolumeSnapshot(inst.Project().Name, inst.Name(), vol, snapshotName, againstName, progressReporter)
+}
+
 // EnsureImage materialises the cached image variant the caller needs and returns
 // a handle for use as a clone source. When inst is supplied the variant is derived
 // from its root-disk config; otherwise pool defaults are used.
@@ -4298,6 +4565,11 @@ func (b *lxdBackend) EnsureImage(ctx context.Context, fingerprint string, projec
 	}
 
 	err = b.driver.EnsureImage(imgVol, &volFiller, progressReporter)
+	if errors.Is(err, drivers.ErrNotSupported) {
+		// The driver doesn't cache images of this type, signal the caller to slow-unpack instead.
+		return nil, nil
+	}
+
 	if errors.Is(err, drivers.ErrImageVariantNotSupported) {
 		if !isPoolDefault {
 			// Per-instance variant exists but doesn't match the requested
@@ -4359,7 +4631,7 @@ func (b *lxdBackend) EnsureImage(ctx context.Context, fingerprint string, projec
 	}
 
 	// Validate config and create database entry for new storage volume.
-	err = VolumeDBCreate(b, api.ProjectDefaultName, image.Fingerprint, "", drivers.VolumeTypeImage, false, imgVol.Config(), time.Now().UTC(), time.Time{}, contentType, false, false)
+	err = VolumeDBCreate(b, api.ProjectDefaultName, image.Fingerprint, "", drivers.VolumeTypeImage, false, imgVol.Config(), time.Now().UTC(), time.Time{}, contentType, false, false, true)
 	if err != nil {
 		return nil, err
 	}
@@ -4506,6 +4778,16 @@ func (b *lxdBackend) CreateBucket(projectName string, bucket api.StorageBucketsP
 	// Set the new bucket volume's UUID.
 	bucket.Config["volatile.uuid"] = bucketVol.Config()["volatile.uuid"]
 
+	// Buckets on local pools consume space in the pool, so check they fit in the project limits.
+	if !b.driver.Info().Remote {
+		err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return limits.AllowBucketCreation(ctx, b.state.GlobalConfig, tx, projectName, b.name, bucket)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed checking bucket creation allowed: %w", err)
+		}
+	}
+
 	bucketID, err := BucketDBCreate(context.TODO(), b, projectName, &bucket)
 	if err != nil {
 		return err
@@ -4513,10 +4795,20 @@ func (b *lxdBackend) CreateBucket(projectName string, bucket api.StorageBucketsP
 
 	revert.Add(func() { _ = BucketDBDelete(context.TODO(), b, bucketID) })
 
-	// Handle per-driver implementation for remote storage drivers.
-	err = b.driver.CreateBucket(bucketVol)
-	if err != nil {
-		return err
+	memberSpecific := !b.driver.Info().Remote // Member specific if storage pool isn't remote.
+	if memberSpecific {
+		// Handle common implementation for local storage drivers.
+		// The bucket contents are stored in a volume served by the storage buckets listener.
+		err = b.driver.CreateVolume(bucketVol, nil, nil)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Handle per-driver implementation for remote storage drivers.
+		err = b.driver.CreateBucket(bucketVol)
+		if err != nil {
+			return err
+		}
 	}
 
 	revert.Success()
@@ -4541,7 +4833,7 @@ func (b *lxdBackend) UpdateBucket(projectName string, bucketName string, bucket
 	// Get current config to compare what has changed.
 	var curBucket *db.StorageBucket
 	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		curBucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, false, bucketName)
+		curBucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, !b.driver.Info().Remote, bucketName)
 		return err
 	})
 	if err != nil {
@@ -4587,10 +4879,38 @@ func (b *lxdBackend) UpdateBucket(projectName string, bucketName string, bucket
 
 	changedConfig, userOnly := b.detectChangedConfig(curBucket.Config, bucket.Config)
 	if len(changedConfig) > 0 && !userOnly {
-		// Handle per-driver implementation for remote storage drivers.
-		err = b.driver.UpdateBucket(curBucketVol, changedConfig)
-		if err != nil {
-			return err
+		memberSpecific := !b.driver.Info().Remote // Member specific if storage pool isn't remote.
+		if memberSpecific {
+			// Handle common implementation for local storage drivers.
+			err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				return limits.AllowBucketUpdate(ctx, b.state.GlobalConfig, tx, projectName, b.name, curBucket.Name, bucket)
+			})
+			if err != nil {
+				return fmt.Errorf("Failed checking bucket update allowed: %w", err)
+			}
+
+			_, sizeChanged := changedConfig["size"]
+			if sizeChanged {
+				// An unset size removes the quota.
+				newSize := int64(0)
+				if bucket.Config["size"] != "" {
+					newSize, err = units.ParseByteSizeString(bucket.Config["size"])
+					if err != nil {
+						return err
+					}
+				}
+
+				err = b.driver.SetVolumeQuota(curBucketVol, strconv.FormatInt(newSize, 10), false, nil)
+				if err != nil {
+					return err
+				}
+			}
+		} else {
+			// Handle per-driver implementation for remote storage drivers.
+			err = b.driver.UpdateBucket(curBucketVol, changedConfig)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -4622,7 +4942,7 @@ func (b *lxdBackend) DeleteBucket(projectName string, bucketName string) error {
 
 	var bucket *db.StorageBucket
 	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, false, bucketName)
+		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, !b.driver.Info().Remote, bucketName)
 		return err
 	})
 	if err != nil {
@@ -4632,10 +4952,24 @@ func (b *lxdBackend) DeleteBucket(projectName string, bucketName string) error {
 	bucketVolName := project.StorageVolume(projectName, bucket.Name)
 	bucketVol := b.GetVolume(drivers.VolumeTypeBucket, drivers.ContentTypeFS, bucketVolName, bucket.Config)
 
-	// Handle per-driver implementation for remote storage drivers.
-	err = b.driver.DeleteBucket(bucketVol)
-	if err != nil {
-		return err
+	memberSpecific := !b.driver.Info().Remote // Member specific if storage pool isn't remote.
+	if memberSpecific {
+		// Handle common implementation for local storage drivers.
+		_, err = b.driver.UnmountVolume(bucketVol, false, nil)
+		if err != nil && !errors.Is(err, drivers.ErrInUse) {
+			return err
+		}
+
+		err = b.driver.DeleteVolume(bucketVol, nil)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Handle per-driver implementation for remote storage drivers.
+		err = b.driver.DeleteBucket(bucketVol)
+		if err != nil {
+			return err
+		}
 	}
 
 	return BucketDBDelete(context.TODO(), b, bucket.ID)
@@ -4661,7 +4995,7 @@ func (b *lxdBackend) CreateBucketKey(projectName string, bucketName string, key
 
 	var bucket *db.StorageBucket
 	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, false, bucketName)
+		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, !b.driver.Info().Remote, bucketName)
 		return err
 	})
 	if err != nil {
@@ -4682,13 +5016,25 @@ func (b *lxdBackend) CreateBucketKey(projectName string, bucketName string, key
 		return nil, err
 	}
 
-	// Handle per-driver implementation for remote storage drivers.
-	newCreds, err := b.driver.CreateBucketKey(bucketVol, key.Name, creds, key.Role)
-	if err != nil {
-		return nil, err
-	}
+	var newCreds *drivers.S3Credentials
+
+	memberSpecific := !b.driver.Info().Remote // Member specific if storage pool isn't remote.
+	if memberSpecific {
+		// Handle common implementation for local storage drivers.
+		// The keys are only stored in the database, from where the storage buckets listener loads them.
+		newCreds, err = b.localBucketKeyCredentials(creds)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Handle per-driver implementation for remote storage drivers.
+		newCreds, err = b.driver.CreateBucketKey(bucketVol, key.Name, creds, key.Role)
+		if err != nil {
+			return nil, err
+		}
 
-	revert.Add(func() { _ = b.driver.DeleteBucketKey(bucketVol, key.Name) })
+		revert.Add(func() { _ = b.driver.DeleteBucketKey(bucketVol, key.Name) })
+	}
 
 	key.AccessKey = newCreds.AccessKey
 	key.SecretKey = newCreds.SecretKey
@@ -4733,7 +5079,7 @@ func (b *lxdBackend) UpdateBucketKey(projectName string, bucketName string, keyN
 	var bucket *db.StorageBucket
 	var curBucketKey *db.StorageBucketKey
 	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, false, bucketName)
+		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, !b.driver.Info().Remote, bucketName)
 		if err != nil {
 			return err
 		}
@@ -4784,10 +5130,21 @@ func (b *lxdBackend) UpdateBucketKey(projectName string, bucketName string, keyN
 		return err
 	}
 
-	// Handle per-driver implementation for remote storage drivers.
-	newCreds, err := b.driver.UpdateBucketKey(bucketVol, keyName, creds, key.Role)
-	if err != nil {
-		return err
+	var newCreds *drivers.S3Credentials
+
+	memberSpecific := !b.driver.Info().Remote // Member specific if storage pool isn't remote.
+	if memberSpecific {
+		// Handle common implementation for local storage drivers.
+		newCreds, err = b.localBucketKeyCredentials(creds)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Handle per-driver implementation for remote storage drivers.
+		newCreds, err = b.driver.UpdateBucketKey(bucketVol, keyName, creds, key.Role)
+		if err != nil {
+			return err
+		}
 	}
 
 	key.AccessKey = newCreds.AccessKey
@@ -4822,7 +5179,7 @@ func (b *lxdBackend) DeleteBucketKey(projectName string, bucketName string, keyN
 	var bucket *db.StorageBucket
 	var bucketKey *db.StorageBucketKey
 	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, false, bucketName)
+		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, !b.driver.Info().Remote, bucketName)
 		if err != nil {
 			return err
 		}
@@ -4838,14 +5195,16 @@ func (b *lxdBackend) DeleteBucketKey(projectName string, bucketName string, keyN
 		return err
 	}
 
-	// Handle per-driver implementation for remote storage drivers.
-	bucketVolName := project.StorageVolume(projectName, bucket.Name)
-	bucketVol := b.GetVolume(drivers.VolumeTypeBucket, drivers.ContentTypeFS, bucketVolName, bucket.Config)
+	if b.driver.Info().Remote {
+		// Handle per-driver implementation for remote storage drivers.
+		bucketVolName := project.StorageVolume(projectName, bucket.Name)
+		bucketVol := b.GetVolume(drivers.VolumeTypeBucket, drivers.ContentTypeFS, bucketVolName, bucket.Config)
 
-	// Delete the bucket key from the storage device.
-	err = b.driver.DeleteBucketKey(bucketVol, keyName)
-	if err != nil {
-		return err
+		// Delete the bucket key from the storage device.
+		err = b.driver.DeleteBucketKey(bucketVol, keyName)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -4869,10 +5228,109 @@ func (b *lxdBackend) GetBucketURL(bucketName string) *url.URL {
 		return nil
 	}
 
+	memberSpecific := !b.driver.Info().Remote // Member specific if storage pool isn't remote.
+	if memberSpecific {
+		// Handle common implementation for local storage drivers.
+		// Check that the storage buckets listener is configured via core.storage_buckets_address.
+		storageBucketsAddress := b.state.Endpoints.StorageBucketsAddress()
+		if storageBucketsAddress == "" {
+			return nil
+		}
+
+		return &api.NewURL().Scheme("https").Host(storageBucketsAddress).Path(bucketName).URL
+	}
+
 	// Handle per-driver implementation for remote storage drivers.
 	return b.driver.GetBucketURL(bucketName)
 }
 
+// MountBucket mounts the volume of a bucket on a local storage pool and returns its mount path.
+func (b *lxdBackend) MountBucket(projectName string, bucketName string, progressReporter ioprogress.ProgressReporter) (string, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "bucketName": bucketName})
+	l.Debug("MountBucket started")
+	defer l.Debug("MountBucket finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return "", err
+	}
+
+	if b.driver.Info().Remote {
+		return "", errors.New("Buckets on remote storage pools cannot be mounted")
+	}
+
+	var bucket *db.StorageBucket
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, true, bucketName)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	bucketVolName := project.StorageVolume(projectName, bucket.Name)
+	bucketVol := b.GetVolume(drivers.VolumeTypeBucket, drivers.ContentTypeFS, bucketVolName, bucket.Config)
+
+	err = b.driver.MountVolume(bucketVol, progressReporter)
+	if err != nil {
+		return "", err
+	}
+
+	return bucketVol.MountPath(), nil
+}
+
+// UnmountBucket unmounts the volume of a bucket on a local storage pool.
+func (b *lxdBackend) UnmountBucket(projectName string, bucketName string, progressReporter ioprogress.ProgressReporter) (bool, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "bucketName": bucketName})
+	l.Debug("UnmountBucket started")
+	defer l.Debug("UnmountBucket finished")
+
+	if b.driver.Info().Remote {
+		return false, errors.New("Buckets on remote storage pools cannot be unmounted")
+	}
+
+	var bucket *db.StorageBucket
+	err := b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		bucket, err = tx.GetStoragePoolBucket(ctx, b.id, projectName, true, bucketName)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	bucketVolName := project.StorageVolume(projectName, bucket.Name)
+	bucketVol := b.GetVolume(drivers.VolumeTypeBucket, drivers.ContentTypeFS, bucketVolName, bucket.Config)
+
+	return b.driver.UnmountVolume(bucketVol, false, progressReporter)
+}
+
+// localBucketKeyCredentials returns the credentials of a key of a bucket on a local storage pool.
+// Random access and secret keys are generated when not supplied. As the storage buckets listener finds
+// the bucket of a request from its access key, the database ensures that no other key uses the access key.
+func (b *lxdBackend) localBucketKeyCredentials(creds drivers.S3Credentials) (*drivers.S3Credentials, error) {
+	var err error
+
+	if creds.AccessKey == "" {
+		creds.AccessKey, err = shared.RandomCryptoString()
+		if err != nil {
+			return nil, err
+		}
+
+		// Use the same length as AWS access keys.
+		creds.AccessKey = strings.ToUpper(creds.AccessKey[:20])
+	}
+
+	if creds.SecretKey == "" {
+		creds.SecretKey, err = shared.RandomCryptoString()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &creds, nil
+}
+
 // CreateCustomVolume creates an empty custom volume.
 func (b *lxdBackend) CreateCustomVolume(ctx context.Context, projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, progressReporter ioprogress.ProgressReporter) error {
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "desc": desc, "config": config, "contentType": contentType})
@@ -4898,7 +5356,7 @@ func (b *lxdBackend) CreateCustomVolume(ctx context.Context, projectName string,
 	defer revert.Fail()
 
 	// Validate config and create database entry for new storage volume.
-	err = VolumeDBCreate(b, projectName, volName, desc, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), false, false)
+	err = VolumeDBCreate(b, projectName, volName, desc, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), false, false, true)
 	if err != nil {
 		return err
 	}
@@ -5034,7 +5492,7 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(ctx context.Context, projectName
 		vol := b.GetNewVolume(drivers.VolumeTypeCustom, contentType, volStorageName, config)
 
 		// Validate config and create database entry for new storage volume.
-		err = VolumeDBCreate(b, projectName, volName, desc, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), false, true)
+		err = VolumeDBCreate(b, projectName, volName, desc, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), false, true, true)
 		if err != nil {
 			return err
 		}
@@ -5055,7 +5513,7 @@ func (b *lxdBackend) CreateCustomVolumeFromCopy(ctx context.Context, projectName
 			snapVol := b.GetNewVolume(vol.Type(), contentType, newSnapshotName, customVol.Snapshots[i].Config)
 
 			// Validate config and create database entry for new storage volume.
-			err = VolumeDBCreate(b, projectName, newSnapshotName, customVol.Snapshots[i].Description, vol.Type(), true, snapVol.Config(), customVol.Snapshots[i].CreatedAt, volumeSnapExpiryDate, vol.ContentType(), false, true)
+			err = VolumeDBCreate(b, projectName, newSnapshotName, customVol.Snapshots[i].Description, vol.Type(), true, snapVol.Config(), customVol.Snapshots[i].CreatedAt, volumeSnapExpiryDate, vol.ContentType(), false, true, true)
 			if err != nil {
 				return err
 			}
@@ -5468,7 +5926,7 @@ func (b *lxdBackend) CreateCustomVolumeFromMigration(ctx context.Context, projec
 	if !args.Refresh {
 		// Validate config and create database entry for new storage volume.
 		// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-		err = VolumeDBCreate(b, projectName, args.Name, args.Description, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), true, true)
+		err = VolumeDBCreate(b, projectName, args.Name, args.Description, vol.Type(), false, vol.Config(), time.Now().UTC(), time.Time{}, vol.ContentType(), true, true, true)
 		if err != nil {
 			return err
 		}
@@ -5520,7 +5978,7 @@ func (b *lxdBackend) CreateCustomVolumeFromMigration(ctx context.Context, projec
 
 			// Validate config and create database entry for new storage volume.
 			// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-			err = VolumeDBCreate(b, projectName, newSnapshotName, snapDescription, vol.Type(), true, snapVol.Config(), snapCreationDate, snapExpiryDate, vol.ContentType(), true, true)
+			err = VolumeDBCreate(b, projectName, newSnapshotName, snapDescription, vol.Type(), true, snapVol.Config(), snapCreationDate, snapExpiryDate, vol.ContentType(), true, true, true)
 			if err != nil {
 				return err
 			}
@@ -6189,7 +6647,7 @@ func (b *lxdBackend) ImportCustomVolume(projectName string, poolVol *backupConfi
 	}
 
 	// Validate config and create database entry for restored storage volume.
-	err = VolumeDBCreate(b, projectName, customVol.Name, customVol.Description, drivers.VolumeTypeCustom, false, vol.Config(), customVol.CreatedAt, time.Time{}, drivers.ContentType(customVol.ContentType), false, true)
+	err = VolumeDBCreate(b, projectName, customVol.Name, customVol.Description, drivers.VolumeTypeCustom, false, vol.Config(), customVol.CreatedAt, time.Time{}, drivers.ContentType(customVol.ContentType), false, true, false)
 	if err != nil {
 		return nil, err
 	}
@@ -6217,7 +6675,7 @@ func (b *lxdBackend) ImportCustomVolume(projectName string, poolVol *backupConfi
 		}
 
 		// Validate config and create database entry for restored storage volume.
-		err = VolumeDBCreate(b, projectName, fullSnapName, poolVolSnap.Description, drivers.VolumeTypeCustom, true, snapVol.Config(), poolVolSnap.CreatedAt, time.Time{}, drivers.ContentType(poolVolSnap.ContentType), false, true)
+		err = VolumeDBCreate(b, projectName, fullSnapName, poolVolSnap.Description, drivers.VolumeTypeCustom, true, snapVol.Config(), poolVolSnap.CreatedAt, time.Time{}, drivers.ContentType(poolVolSnap.ContentType), false, true, false)
 		if err != nil {
 			return nil, err
 		}
@@ -6386,7 +6844,7 @@ func (b *lxdBackend) CreateCustomVolumeSnapshot(ctx context.Context, projectName
 
 	// Validate config and create database entry for new storage volume.
 	// Copy volume config from parent.
-	err = VolumeDBCreate(b, projectName, fullSnapshotName, description, drivers.VolumeTypeCustom, true, vol.Config(), snapshotCreationDate, *newExpiryDate, drivers.ContentType(parentVol.ContentType), false, true)
+	err = VolumeDBCreate(b, projectName, fullSnapshotName, description, drivers.VolumeTypeCustom, true, vol.Config(), snapshotCreationDate, *newExpiryDate, drivers.ContentType(parentVol.ContentType), false, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -6702,6 +7160,89 @@ func (b *lxdBackend) RestoreCustomVolume(ctx context.Context, projectName string
 	return nil
 }
 
+// DiffCustomVolumeSnapshot returns the paths which differ between a custom volume snapshot and either a later
+// snapshot of the volume or, if againstName is empty, the volume itself.
+func (b *lxdBackend) DiffCustomVolumeSnapshot(projectName string, volName string, snapshotName string, againstName string, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "snapshotName": snapshotName, "againstName": againstName})
+	l.Debug("DiffCustomVolumeSnapshot started")
+	defer l.Debug("DiffCustomVolumeSnapshot finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return nil, err
+	}
+
+	if shared.IsSnapshot(volName) {
+		return nil, errors.New("Volume cannot be snapshot")
+	}
+
+	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return nil, err
+	}
+
+	dbContentType, err := cluster.StoragePoolVolumeContentTypeFromName(volume.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+	vol := b.GetVolume(drivers.VolumeTypeCustom, VolumeDBContentTypeToContentType(dbContentType), volStorageName, volume.Config)
+
+	return b.diffVolumeSnapshot(projectName, volName, vol, snapshotName, againstName, progressReporter)
+}
+
+// diffVolumeSnapshot returns the paths which differ between the named snapshot of a volume and either its named
+// later snapshot or, if againstName is empty, the volume itself.
+func (b *lxdBackend) diffVolumeSnapshot(projectName string, volName string, vol drivers.Volume, snapshotName string, againstName string, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	if vol.ContentType() != drivers.ContentTypeFS {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Only snapshots of filesystem volumes can be compared")
+	}
+
+	loadSnapshot := func(snapshotName string) (drivers.Volume, time.Time, error) {
+		if snapshotName == "" || shared.IsSnapshot(snapshotName) {
+			return drivers.Volume{}, time.Time{}, api.StatusErrorf(http.StatusBadRequest, "Invalid snapshot name %q", snapshotName)
+		}
+
+		dbSnap, err := VolumeDBGet(b, projectName, volName+shared.SnapshotDelimiter+snapshotName, vol.Type())
+		if err != nil {
+			return drivers.Volume{}, time.Time{}, err
+		}
+
+		snapVol := b.GetVolume(vol.Type(), vol.ContentType(), drivers.GetSnapshotVolumeName(vol.Name(), snapshotName), dbSnap.Config)
+
+		// Set the parent volume UUID.
+		if b.driver.Info().PopulateParentVolumeUUID {
+			snapVol.SetParentUUID(vol.Config()["volatile.uuid"])
+		}
+
+		return snapVol, dbSnap.CreatedAt, nil
+	}
+
+	snapVol, snapCreatedAt, err := loadSnapshot(snapshotName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare with the volume itself unless another snapshot is specified.
+	targetVol := vol
+	if againstName != "" {
+		againstVol, againstCreatedAt, err := loadSnapshot(againstName)
+		if err != nil {
+			return nil, err
+		}
+
+		if againstCreatedAt.Before(snapCreatedAt) {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Snapshot %q is older than snapshot %q", againstName, snapshotName)
+		}
+
+		targetVol = againstVol
+	}
+
+	return b.driver.DiffVolumeSnapshot(snapVol, targetVol, progressReporter)
+}
+
 func (b *lxdBackend) createStorageStructure(path string) error {
 	for _, volType := range b.driver.Info().VolumeTypes {
 		for _, name := range drivers.BaseDirectories[volType].Paths {
@@ -7737,7 +8278,7 @@ func (b *lxdBackend) ImportInstance(inst instance.Instance, poolVol *backupConfi
 		}
 
 		// Validate config and create database entry for recovered storage volume.
-		err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", volType, false, vol.Config(), creationDate, time.Time{}, contentType, false, true)
+		err = VolumeDBCreate(b, inst.Project().Name, inst.Name(), "", volType, false, vol.Config(), creationDate, time.Time{}, contentType, false, true, false)
 		if err != nil {
 			return nil, err
 		}
@@ -7767,7 +8308,7 @@ func (b *lxdBackend) ImportInstance(inst instance.Instance, poolVol *backupConfi
 				}
 
 				// Validate config and create database entry for recovered storage volume.
-				err = VolumeDBCreate(b, inst.Project().Name, fullSnapName, poolVolSnap.Description, volType, true, snapVol.Config(), poolVolSnap.CreatedAt, time.Time{}, contentType, false, true)
+				err = VolumeDBCreate(b, inst.Project().Name, fullSnapName, poolVolSnap.Description, volType, true, snapVol.Config(), poolVolSnap.CreatedAt, time.Time{}, contentType, false, true, false)
 				if err != nil {
 					return nil, err
 				}
@@ -7789,7 +8330,7 @@ func (b *lxdBackend) ImportInstance(inst instance.Instance, poolVol *backupConfi
 				snapVol := b.GetNewVolume(volType, contentType, fullSnapName, volumeConfig)
 
 				// Validate config and create database entry for new storage volume.
-				err = VolumeDBCreate(b, inst.Project().Name, fullSnapName, "", volType, true, snapVol.Config(), time.Time{}, time.Time{}, contentType, false, true)
+				err = VolumeDBCreate(b, inst.Project().Name, fullSnapName, "", volType, true, snapVol.Config(), time.Time{}, time.Time{}, contentType, false, true, false)
 				if err != nil {
 					return nil, err
 				}
@@ -7875,11 +8416,17 @@ func (b *lxdBackend) ImportInstance(inst instance.Instance, poolVol *backupConfi
 }
 
 // BackupCustomVolume creates a backup of an existing custom volume.
-func (b *lxdBackend) BackupCustomVolume(projectName string, volName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, progressReporter ioprogress.ProgressReporter) error {
-	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volume": volName, "optimized": optimized, "snapshots": snapshots})
+// If baseSnapshot is set only the changes since that snapshot are included in the backup.
+func (b *lxdBackend) BackupCustomVolume(projectName string, volName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, baseSnapshot string, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volume": volName, "optimized": optimized, "snapshots": snapshots, "baseSnapshot": baseSnapshot})
 	l.Debug("BackupCustomVolume started")
 	defer l.Debug("BackupCustomVolume finished")
 
+	err := b.checkIncrementalBackup(optimized, baseSnapshot)
+	if err != nil {
+		return err
+	}
+
 	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
 	if err != nil {
 		return err
@@ -7901,7 +8448,7 @@ func (b *lxdBackend) BackupCustomVolume(projectName string, volName string, tarW
 
 	var snapNames []string
 	var sourceSnapshots []drivers.Volume
-	if snapshots {
+	if snapshots || baseSnapshot != "" {
 		// Get snapshots in age order, oldest first, and pass names to storage driver.
 		volSnaps, err := VolumeDBSnapshotsGet(b, projectName, volName, drivers.VolumeTypeCustom)
 		if err != nil {
@@ -7921,9 +8468,14 @@ func (b *lxdBackend) BackupCustomVolume(projectName string, volName string, tarW
 
 	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, volume.Config)
 
+	snapNames, err = backupSnapshotNames(snapNames, snapshots, baseSnapshot)
+	if err != nil {
+		return err
+	}
+
 	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
 
-	err = b.driver.BackupVolume(volCopy, projectName, tarWriter, optimized, snapNames, progressReporter)
+	err = b.driver.BackupVolume(volCopy, projectName, tarWriter, optimized, snapNames, baseSnapshot, progressReporter)
 	if err != nil {
 		return err
 	}
@@ -7978,7 +8530,7 @@ func (b *lxdBackend) CreateCustomVolumeFromISO(ctx context.Context, projectName
 	}
 
 	// Validate config and create database entry for new storage volume.
-	err = VolumeDBCreate(b, projectName, volName, "", vol.Type(), false, vol.Config(), time.Now(), time.Time{}, vol.ContentType(), true, true)
+	err = VolumeDBCreate(b, projectName, volName, "", vol.Type(), false, vol.Config(), time.Now(), time.Time{}, vol.ContentType(), true, true, true)
 	if err != nil {
 		return fmt.Errorf("Failed creating database entry for custom volume: %w", err)
 	}
@@ -8056,7 +8608,7 @@ func (b *lxdBackend) CreateCustomVolumeFromTarball(ctx context.Context, projectN
 	}
 
 	// Validate config and create database entry for new storage volume.
-	err = VolumeDBCreate(b, projectName, volName, "", vol.Type(), false, vol.Config(), time.Now(), time.Time{}, vol.ContentType(), true, true)
+	err = VolumeDBCreate(b, projectName, volName, "", vol.Type(), false, vol.Config(), time.Now(), time.Time{}, vol.ContentType(), true, true, true)
 	if err != nil {
 		return fmt.Errorf("Failed creating database entry for custom volume: %w", err)
 	}
@@ -8191,13 +8743,20 @@ func (b *lxdBackend) CreateCustomVolumeFromBackup(ctx context.Context, srcBackup
 
 	// Validate config and create database entry for new storage volume.
 	// Strip unsupported config keys (in case the export was made from a different type of storage pool).
-	err = VolumeDBCreate(b, srcBackup.Project, srcBackup.Name, customVol.Description, vol.Type(), false, vol.Config(), customVol.CreatedAt, time.Time{}, vol.ContentType(), true, true)
+	// This replaces the encryption key if it cannot be unwrapped by this server.
+	wrappedKey := vol.Config()["volatile.encryption.key"]
+	err = VolumeDBCreate(b, srcBackup.Project, srcBackup.Name, customVol.Description, vol.Type(), false, vol.Config(), customVol.CreatedAt, time.Time{}, vol.ContentType(), true, true, true)
 	if err != nil {
 		return err
 	}
 
 	revert.Add(func() { _ = VolumeDBDelete(b, srcBackup.Project, srcBackup.Name, vol.Type()) })
 
+	err = checkOptimizedBackupKey(srcBackup, vol, wrappedKey)
+	if err != nil {
+		return err
+	}
+
 	sourceSnapshots := make([]drivers.Volume, 0, len(customVol.Snapshots))
 
 	// Create database entries for new storage volume snapshots.
@@ -8226,7 +8785,7 @@ func (b *lxdBackend) CreateCustomVolumeFromBackup(ctx context.Context, srcBackup
 			snapExpiryDate = *snapshot.ExpiresAt
 		}
 
-		err = VolumeDBCreate(b, srcBackup.Project, fullSnapName, snapshot.Description, snapVol.Type(), true, snapVol.Config(), snapshot.CreatedAt, snapExpiryDate, snapVol.ContentType(), true, true)
+		err = VolumeDBCreate(b, srcBackup.Project, fullSnapName, snapshot.Description, snapVol.Type(), true, snapVol.Config(), snapshot.CreatedAt, snapExpiryDate, snapVol.ContentType(), true, true, true)
 		if err != nil {
 			return err
 		}
@@ -8266,6 +8825,164 @@ func (b *lxdBackend) CreateCustomVolumeFromBackup(ctx context.Context, srcBackup
 	return nil
 }
 
+// RefreshCustomVolumeFromBackup applies an incremental backup on top of an existing custom volume.
+// The volume's data is updated and the snapshots included in the backup are created, the volume's config is
+// left unchanged.
+func (b *lxdBackend) RefreshCustomVolumeFromBackup(ctx context.Context, srcBackup backup.Info, srcData io.ReadSeeker, progressReporter ioprogress.ProgressReporter) error {
+	l := b.logger.AddContext(logger.Ctx{"project": srcBackup.Project, "volume": srcBackup.Name, "snapshots": srcBackup.Snapshots, "baseSnapshot": srcBackup.BaseSnapshot})
+	l.Debug("RefreshCustomVolumeFromBackup started")
+	defer l.Debug("RefreshCustomVolumeFromBackup finished")
+
+	if srcBackup.Config == nil {
+		return errors.New("Valid volume config not found in index")
+	}
+
+	if !b.driver.Info().IncrementalBackups {
+		return fmt.Errorf("Storage pool %q does not support incremental backups", b.name)
+	}
+
+	customVol, err := srcBackup.Config.CustomVolume()
+	if err != nil {
+		return fmt.Errorf("Failed getting the custom volume: %w", err)
+	}
+
+	// Validate the names in the index.yaml file as these could be malicious.
+	err = drivers.ValidVolumeName(srcBackup.Name)
+	if err != nil {
+		return fmt.Errorf("Invalid backup name %q: %w", srcBackup.Name, err)
+	}
+
+	for _, snapName := range srcBackup.Snapshots {
+		err = drivers.ValidVolumeName(snapName)
+		if err != nil {
+			return fmt.Errorf("Invalid backup snapshot name %q: %w", snapName, err)
+		}
+	}
+
+	// Get the volume the backup is applied to.
+	curVol, err := VolumeDBGet(b, srcBackup.Project, srcBackup.Name, drivers.VolumeTypeCustom)
+	if err != nil {
+		if response.IsNotFoundError(err) {
+			return fmt.Errorf("Volume %q not found, import the backups of the chain in order", srcBackup.Name)
+		}
+
+		return err
+	}
+
+	if curVol.ContentType != customVol.ContentType {
+		return fmt.Errorf("Content type %q of the incremental backup does not match content type %q of volume %q", customVol.ContentType, curVol.ContentType, srcBackup.Name)
+	}
+
+	// Check that the volume isn't in use by running instances.
+	err = VolumeUsedByInstanceDevices(b.state, b.Name(), srcBackup.Project, &curVol.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, _ []string) error {
+		inst, err := instance.Load(b.state, dbInst, project)
+		if err != nil {
+			return err
+		}
+
+		if inst.IsRunning() {
+			return errors.New("Cannot apply incremental backup to custom volume used by running instances")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Check the backup follows the most recent snapshot of the volume.
+	volSnaps, err := VolumeDBSnapshotsGet(b, srcBackup.Project, srcBackup.Name, drivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	snapNames := make([]string, 0, len(volSnaps))
+	for _, volSnap := range volSnaps {
+		_, snapName, _ := api.GetParentAndSnapshotName(volSnap.Name)
+		snapNames = append(snapNames, snapName)
+	}
+
+	err = srcBackup.CheckIncrementalBase(snapNames)
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	contentType := drivers.ContentType(curVol.ContentType)
+	volStorageName := project.StorageVolume(srcBackup.Project, srcBackup.Name)
+	vol := b.GetVolume(drivers.VolumeTypeCustom, contentType, volStorageName, curVol.Config)
+
+	// Create database entries for the snapshots included in the backup.
+	sourceSnapshots := make([]drivers.Volume, 0, len(srcBackup.Snapshots))
+	for _, snapName := range srcBackup.Snapshots {
+		var snapshot *api.StorageVolumeSnapshot
+		for _, s := range customVol.Snapshots {
+			if s == nil {
+				continue
+			}
+
+			// Due to a historical bug, the volume snapshot names were sometimes written in their full form
+			// (<parent>/<snap>) rather than the expected snapshot name only form, so we need to handle both.
+			name := s.Name
+			if shared.IsSnapshot(s.Name) {
+				_, name, _ = api.GetParentAndSnapshotName(s.Name)
+			}
+
+			if name == snapName {
+				snapshot = s
+				break
+			}
+		}
+
+		if snapshot == nil {
+			return fmt.Errorf("Volume snapshot %q not found in index", snapName)
+		}
+
+		fullSnapName := drivers.GetSnapshotVolumeName(srcBackup.Name, snapName)
+		snapVolStorageName := project.StorageVolume(srcBackup.Project, fullSnapName)
+		snapVol := b.GetNewVolume(drivers.VolumeTypeCustom, contentType, snapVolStorageName, snapshot.Config)
+
+		var snapExpiryDate time.Time
+		if snapshot.ExpiresAt != nil {
+			snapExpiryDate = *snapshot.ExpiresAt
+		}
+
+		err = VolumeDBCreate(b, srcBackup.Project, fullSnapName, snapshot.Description, snapVol.Type(), true, snapVol.Config(), snapshot.CreatedAt, snapExpiryDate, snapVol.ContentType(), true, true, true)
+		if err != nil {
+			return err
+		}
+
+		revert.Add(func() { _ = VolumeDBDelete(b, srcBackup.Project, fullSnapName, snapVol.Type()) })
+
+		sourceSnapshots = append(sourceSnapshots, snapVol)
+	}
+
+	volCopy := drivers.NewVolumeCopy(vol, sourceSnapshots...)
+
+	// Apply the backup on top of the existing storage volume.
+	volPostHook, revertHook, err := b.driver.CreateVolumeFromBackup(volCopy, srcBackup, srcData, progressReporter)
+	if err != nil {
+		return err
+	}
+
+	if revertHook != nil {
+		revert.Add(revertHook)
+	}
+
+	if volPostHook != nil {
+		return errors.New("Custom volume restore does not support post hooks")
+	}
+
+	for _, snapVol := range sourceSnapshots {
+		b.state.Events.SendLifecycle(srcBackup.Project, lifecycle.StorageVolumeSnapshotCreated.Event(ctx, snapVol, string(snapVol.Type()), srcBackup.Project, logger.Ctx{"type": snapVol.Type()}))
+	}
+
+	revert.Success()
+	return nil
+}
+
 // getParentVolumeUUID returns the UUID of the parent's volume.
 // If the volume has no parent, an empty string is returned.
 func (b *lxdBackend) getParentVolumeUUID(vol drivers.Volume, projectName string) (string, error) {
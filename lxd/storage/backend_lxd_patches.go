@@ -20,7 +20,9 @@ var lxdEarlyPatches = map[string]func(b *lxdBackend) error{
 	"storage_delete_old_snapshot_records": patchDeleteOldSnapshotRecords,
 }
 
-var lxdLatePatches = map[string]func(b *lxdBackend) error{}
+var lxdLatePatches = map[string]func(b *lxdBackend) error{
+	"storage_create_local_bucket_structure": patchCreateLocalBucketStructure,
+}
 
 // Patches start here.
 
@@ -132,7 +134,7 @@ func patchMissingSnapshotRecords(b *lxdBackend) error {
 				}
 
 				b.logger.Info("Creating missing volume snapshot record", logger.Ctx{"project": snapshots[i].Project, "instance": snapshots[i].Name})
-				err = VolumeDBCreate(b, snapshots[i].Project, snapshots[i].Name, "Auto repaired", volType, true, config, snapshots[i].CreationDate, time.Time{}, contentType, false, true)
+				err = VolumeDBCreate(b, snapshots[i].Project, snapshots[i].Name, "Auto repaired", volType, true, config, snapshots[i].CreationDate, time.Time{}, contentType, false, true, false)
 				if err != nil {
 					return err
 				}
@@ -171,3 +173,13 @@ DELETE FROM storage_volumes WHERE id IN (
 
 	return nil
 }
+
+// patchCreateLocalBucketStructure recreates the "buckets" directory of local storage pools removed by the
+// "storage_remove_local_buckets" patch, for use by the buckets served by the embedded S3 gateway.
+func patchCreateLocalBucketStructure(b *lxdBackend) error {
+	if !b.driver.Info().Buckets || b.driver.Info().Remote {
+		return nil
+	}
+
+	return b.createStorageStructure(drivers.GetPoolMountPath(b.name))
+}
@@ -90,6 +90,16 @@ func (b *mockBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return nil, nil
 }
 
+// GetHealth ...
+func (b *mockBackend) GetHealth() (*api.StoragePoolHealth, error) {
+	return nil, nil
+}
+
+// Scrub ...
+func (b *mockBackend) Scrub() error {
+	return nil
+}
+
 // IsUsed ...
 func (b *mockBackend) IsUsed() (bool, error) {
 	return false, nil
@@ -140,6 +150,11 @@ func (b *mockBackend) CreateInstanceFromBackup(srcBackup backup.Info, srcData io
 	return nil, nil, nil
 }
 
+// RefreshInstanceFromBackup ...
+func (b *mockBackend) RefreshInstanceFromBackup(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, progressReporter ioprogress.ProgressReporter) (revert.Hook, error) {
+	return nil, nil
+}
+
 // CreateInstanceFromCopy ...
 func (b *mockBackend) CreateInstanceFromCopy(ctx context.Context, inst instance.Instance, src instance.Instance, snapshots bool, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error {
 	return nil
@@ -236,7 +251,7 @@ func (b *mockBackend) RefreshInstance(ctx context.Context, inst instance.Instanc
 }
 
 // BackupInstance ...
-func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, version uint32, progressReporter ioprogress.ProgressReporter) error {
+func (b *mockBackend) BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, baseSnapshot string, version uint32, progressReporter ioprogress.ProgressReporter) error {
 	return nil
 }
 
@@ -295,6 +310,11 @@ func (b *mockBackend) UpdateInstanceSnapshot(ctx context.Context, inst instance.
 	return nil
 }
 
+// DiffInstanceSnapshot ...
+func (b *mockBackend) DiffInstanceSnapshot(inst instance.Instance, snapshotName string, againstName string, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	return nil, nil
+}
+
 // EnsureImage ...
 func (b *mockBackend) EnsureImage(ctx context.Context, fingerprint string, projectName string, inst instance.Instance, progressReporter ioprogress.ProgressReporter) (*drivers.Volume, error) {
 	return nil, nil
@@ -345,6 +365,16 @@ func (b *mockBackend) GetBucketURL(bucketName string) *url.URL {
 	return nil
 }
 
+// MountBucket ...
+func (b *mockBackend) MountBucket(projectName string, bucketName string, progressReporter ioprogress.ProgressReporter) (string, error) {
+	return "", nil
+}
+
+// UnmountBucket ...
+func (b *mockBackend) UnmountBucket(projectName string, bucketName string, progressReporter ioprogress.ProgressReporter) (bool, error) {
+	return true, nil
+}
+
 // CreateCustomVolume ...
 func (b *mockBackend) CreateCustomVolume(ctx context.Context, projectName string, volName string, desc string, config map[string]string, contentType drivers.ContentType, progressReporter ioprogress.ProgressReporter) error {
 	return nil
@@ -425,8 +455,13 @@ func (b *mockBackend) RestoreCustomVolume(ctx context.Context, projectName strin
 	return nil
 }
 
+// DiffCustomVolumeSnapshot ...
+func (b *mockBackend) DiffCustomVolumeSnapshot(projectName string, volName string, snapshotName string, againstName string, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	return nil, nil
+}
+
 // BackupCustomVolume ...
-func (b *mockBackend) BackupCustomVolume(projectName string, volName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, progressReporter ioprogress.ProgressReporter) error {
+func (b *mockBackend) BackupCustomVolume(projectName string, volName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, baseSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return nil
 }
 
@@ -435,6 +470,11 @@ func (b *mockBackend) CreateCustomVolumeFromBackup(ctx context.Context, srcBacku
 	return nil
 }
 
+// RefreshCustomVolumeFromBackup ...
+func (b *mockBackend) RefreshCustomVolumeFromBackup(ctx context.Context, srcBackup backup.Info, srcData io.ReadSeeker, progressReporter ioprogress.ProgressReporter) error {
+	return nil
+}
+
 // CreateCustomVolumeFromISO ...
 func (b *mockBackend) CreateCustomVolumeFromISO(ctx context.Context, projectName string, volName string, srcData io.ReadSeeker, size int64, progressReporter ioprogress.ProgressReporter) error {
 	return nil
@@ -2,8 +2,11 @@ package block
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"os/exec"
 	"path"
@@ -60,7 +63,11 @@ func findDiskDevicePath(diskNamePrefix string, diskPathFilter DevicePathFilterFu
 	return "", nil
 }
 
-// DiskSizeBytes returns the size of a block disk (path can be either block device or raw file).
+// qcow2Magic is the magic string at the start of qcow2 disk image files.
+const qcow2Magic = "QFI\xfb"
+
+// DiskSizeBytes returns the size of a block disk (path can be either block device, raw or qcow2 file).
+// The format of disk image files is derived from their name, qcow2 files must use the ".qcow2" extension.
 func DiskSizeBytes(blockDiskPath string) (int64, error) {
 	if shared.IsBlockdevPath(blockDiskPath) {
 		// Attempt to open the device path.
@@ -81,6 +88,10 @@ func DiskSizeBytes(blockDiskPath string) (int64, error) {
 		return int64(res), nil
 	}
 
+	if strings.HasSuffix(blockDiskPath, ".qcow2") {
+		return qcow2VirtualSize(blockDiskPath)
+	}
+
 	// Block device is assumed to be a raw file.
 	fi, err := os.Lstat(blockDiskPath)
 	if err != nil {
@@ -90,6 +101,34 @@ func DiskSizeBytes(blockDiskPath string) (int64, error) {
 	return fi.Size(), nil
 }
 
+// qcow2VirtualSize returns the virtual disk size recorded in the header of a qcow2 disk image file.
+func qcow2VirtualSize(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return -1, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	// The header starts with the magic string and stores the virtual size as a big-endian uint64 at offset 24.
+	header := make([]byte, 32)
+	_, err = io.ReadFull(f, header)
+	if err != nil {
+		return -1, fmt.Errorf("Failed reading qcow2 header of %q: %w", path, err)
+	}
+
+	if string(header[0:4]) != qcow2Magic {
+		return -1, fmt.Errorf("Invalid qcow2 header in %q", path)
+	}
+
+	size := binary.BigEndian.Uint64(header[24:32])
+	if size > math.MaxInt64 {
+		return -1, fmt.Errorf("Invalid qcow2 virtual size in %q", path)
+	}
+
+	return int64(size), nil
+}
+
 // DiskBlockSize returns the physical block size of a block device.
 func DiskBlockSize(path string) (uint32, error) {
 	f, err := os.Open(path)
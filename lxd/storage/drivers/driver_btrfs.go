@@ -41,6 +41,7 @@ func (d *btrfs) load() error {
 		"storage_zfs_drop_block_volume_filesystem_extension": nil,
 		"storage_prefix_bucket_names_with_project":           nil,
 		"storage_zfs_remove_local_bucket_datasets":           nil,
+		"storage_create_local_bucket_structure":              nil,
 	}
 
 	// Done if previously loaded.
@@ -98,16 +99,17 @@ func (d *btrfs) Info() Info {
 		DefaultVMBlockFilesystemSize: d.defaultVMBlockFilesystemSize(),
 		OptimizedImages:              true,
 		OptimizedBackups:             true,
+		IncrementalBackups:           true,
 		OptimizedBackupHeader:        true,
 		PreservesInodes:              !d.state.OS.RunningInUserNS,
 		Remote:                       d.isRemote(),
-		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		VolumeTypes:                  []VolumeType{VolumeTypeBucket, VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
 		BlockBacking:                 false,
 		RunningCopyFreeze:            false,
 		DirectIO:                     true,
 		IOUring:                      true,
 		MountedRoot:                  true,
-		Buckets:                      false,
+		Buckets:                      true,
 		PopulateParentVolumeUUID:     false,
 	}
 }
@@ -357,6 +359,9 @@ func (d *btrfs) Validate(config map[string]string) error {
 	// Append common local pool rules.
 	maps.Insert(rules, maps.All(d.commonRules.LocalPoolRules()))
 
+	// Append scrub rules.
+	maps.Insert(rules, maps.All(scrubPoolRules()))
+
 	return d.validatePool(config, rules, nil)
 }
 
@@ -521,6 +526,66 @@ func (d *btrfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return genericVFSGetResources(d)
 }
 
+// GetHealth returns the health of the filesystem and the devices backing it.
+func (d *btrfs) GetHealth() (*api.StoragePoolHealth, error) {
+	poolMntPath := GetPoolMountPath(d.name)
+
+	out, err := shared.RunCommand(context.TODO(), "btrfs", "device", "stats", poolMntPath)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := btrfsParseDeviceStats(out)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &api.StoragePoolHealth{
+		State:   api.StoragePoolHealthStateHealthy,
+		Devices: devices,
+	}
+
+	for _, device := range devices {
+		if device.ReadErrors+device.WriteErrors+device.ChecksumErrors > 0 {
+			health.State = api.StoragePoolHealthStateDegraded
+			health.Message = "One or more devices have experienced errors"
+		}
+	}
+
+	// Devices which can't be found are only reported when showing the filesystem.
+	out, err = shared.RunCommand(context.TODO(), "btrfs", "filesystem", "show", poolMntPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(strings.ToLower(out), "missing") {
+		health.State = api.StoragePoolHealthStateDegraded
+		health.Message = "One or more devices are missing"
+	}
+
+	out, err = shared.RunCommand(context.TODO(), "btrfs", "scrub", "status", "-R", poolMntPath)
+	if err != nil {
+		return nil, err
+	}
+
+	health.Scrub, err = btrfsParseScrubStatus(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return health, nil
+}
+
+// Scrub starts a scrub of the filesystem in the background.
+func (d *btrfs) Scrub() error {
+	_, err := shared.RunCommand(context.TODO(), "btrfs", "scrub", "start", GetPoolMountPath(d.name))
+	if err != nil {
+		return fmt.Errorf("Failed starting scrub of btrfs pool %q: %w", d.name, err)
+	}
+
+	return nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *btrfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
 	var rsyncFeatures []string
@@ -8,12 +8,14 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unsafe"
 
 	"github.com/google/uuid"
@@ -306,6 +308,51 @@ func (d *btrfs) deleteSubvolume(rootPath string, recursion bool) error {
 	return nil
 }
 
+// subvolumeGeneration returns the generation of the last transaction which modified the subvolume at the given path.
+func (d *btrfs) subvolumeGeneration(path string) (uint64, error) {
+	// Looking for changes past any possible generation only prints the current one.
+	out, err := shared.RunCommand(context.TODO(), "btrfs", "subvolume", "find-new", path, strconv.FormatInt(math.MaxInt64, 10))
+	if err != nil {
+		return 0, err
+	}
+
+	_, generation, found := strings.Cut(strings.TrimSpace(out), "transid marker was ")
+	if !found {
+		return 0, fmt.Errorf("Failed getting generation of subvolume %q: Unexpected output %q", path, out)
+	}
+
+	return strconv.ParseUint(generation, 10, 64)
+}
+
+// btrfsFindNewPaths parses the output of "btrfs subvolume find-new" and returns the paths of the files with
+// extents written to, relative to the subvolume.
+func btrfsFindNewPaths(out string) []string {
+	var paths []string
+	seen := map[string]bool{}
+
+	for line := range strings.SplitSeq(out, "\n") {
+		// Each extent is printed as "inode INO file offset OFF len LEN disk start START offset OFF gen GEN flags FLAGS PATH".
+		_, rest, found := strings.Cut(line, " flags ")
+		if !strings.HasPrefix(line, "inode ") || !found {
+			continue
+		}
+
+		_, path, found := strings.Cut(rest, " ")
+		if !found {
+			continue
+		}
+
+		// Files are printed once per extent written to.
+		path = "/" + path
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
 func (d *btrfs) getQGroup(path string) (string, int64, error) {
 	// Try to get the qgroup details.
 	output, err := shared.RunCommand(context.TODO(), "btrfs", "qgroup", "show", "-e", "-f", "--raw", path)
@@ -666,3 +713,107 @@ func (d *btrfs) getDiskPathFromFSUUID(uuid string) (string, error) {
 
 	return strings.TrimSpace(uuid), nil
 }
+
+// btrfsParseDeviceStats parses the output of "btrfs device stats" and returns the error counters of each device.
+func btrfsParseDeviceStats(out string) ([]api.StoragePoolHealthDevice, error) {
+	devices := []api.StoragePoolHealthDevice{}
+	deviceIndex := map[string]int{}
+
+	for line := range strings.SplitSeq(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !strings.HasPrefix(fields[0], "[") {
+			continue
+		}
+
+		name, counter, found := strings.Cut(strings.TrimPrefix(fields[0], "["), "].")
+		if !found {
+			return nil, fmt.Errorf("Failed parsing btrfs device stats line %q", line)
+		}
+
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing btrfs device stats line %q: %w", line, err)
+		}
+
+		i, ok := deviceIndex[name]
+		if !ok {
+			i = len(devices)
+			deviceIndex[name] = i
+			devices = append(devices, api.StoragePoolHealthDevice{Name: name, State: "ok"})
+		}
+
+		switch counter {
+		case "read_io_errs":
+			devices[i].ReadErrors += value
+		case "write_io_errs", "flush_io_errs":
+			devices[i].WriteErrors += value
+		case "corruption_errs", "generation_errs":
+			devices[i].ChecksumErrors += value
+		}
+	}
+
+	return devices, nil
+}
+
+// btrfsParseScrubStatus parses the output of "btrfs scrub status -R" and returns the state of the last scrub.
+// Nil is returned if the filesystem was never scrubbed.
+func btrfsParseScrubStatus(out string) (*api.StoragePoolHealthScrub, error) {
+	values := map[string]string{}
+	for line := range strings.SplitSeq(out, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	if values["Status"] == "" || values["Scrub started"] == "" {
+		return nil, nil
+	}
+
+	scrub := &api.StoragePoolHealthScrub{}
+
+	switch values["Status"] {
+	case "running":
+		scrub.Status = api.StoragePoolScrubStatusRunning
+	case "finished":
+		scrub.Status = api.StoragePoolScrubStatusCompleted
+	case "aborted", "interrupted":
+		scrub.Status = api.StoragePoolScrubStatusCanceled
+	default:
+		return nil, fmt.Errorf("Unknown btrfs scrub status %q", values["Status"])
+	}
+
+	var err error
+	scrub.StartedAt, err = time.ParseInLocation(time.ANSIC, values["Scrub started"], time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing btrfs scrub start time: %w", err)
+	}
+
+	if scrub.Status != api.StoragePoolScrubStatusRunning {
+		// The duration is reported as hours:minutes:seconds.
+		var hours, minutes, seconds int64
+		_, err = fmt.Sscanf(values["Duration"], "%d:%d:%d", &hours, &minutes, &seconds)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing btrfs scrub duration %q: %w", values["Duration"], err)
+		}
+
+		scrub.FinishedAt = scrub.StartedAt.Add(time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
+	}
+
+	for _, key := range []string{"read_errors", "csum_errors", "verify_errors", "super_errors"} {
+		if values[key] == "" {
+			continue
+		}
+
+		value, err := strconv.ParseUint(values[key], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing btrfs scrub %s: %w", key, err)
+		}
+
+		scrub.Errors += value
+	}
+
+	return scrub, nil
+}
@@ -161,10 +161,28 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 		return nil, nil, err
 	}
 
-	if volExists {
+	// Incremental backups are applied on top of the volume restored from the previous backup of the chain.
+	incremental := srcBackup.BaseSnapshot != ""
+	if incremental && !volExists {
+		return nil, nil, fmt.Errorf("Cannot apply incremental backup, volume %q not found on target, import the backups of the chain in order", vol.name)
+	} else if !incremental && volExists {
 		return nil, nil, errors.New("Cannot restore volume, already exists on target")
 	}
 
+	// The incremental streams are received on top of the base snapshot, which btrfs finds by the received UUID
+	// kept when restoring the previous backup of the chain.
+	var baseVol Volume
+	if incremental {
+		baseVol, err = vol.NewSnapshot(srcBackup.BaseSnapshot)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !d.isSubvolume(baseVol.MountPath()) {
+			return nil, nil, fmt.Errorf("Base snapshot %q of the incremental backup not found on target, import the backups of the chain in order", srcBackup.BaseSnapshot)
+		}
+	}
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -177,6 +195,12 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 			_ = d.DeleteVolumeSnapshot(snapVol, progressReporter)
 		}
 
+		// Return the volume to the state of the base snapshot when applying an incremental backup.
+		if incremental {
+			_ = d.RestoreVolume(vol.Volume, baseVol, progressReporter)
+			return
+		}
+
 		// And lastly the main volume.
 		_ = d.DeleteVolume(vol.Volume, progressReporter)
 	}
@@ -256,7 +280,9 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 
 			if hdr.Name == srcFile {
 				subVolRecvPath, err := d.receiveSubVolume(io.NopCloser(tr), targetPath, nil)
-				if err != nil {
+				if err != nil && incremental {
+					return "", fmt.Errorf("Failed applying incremental backup on top of snapshot %q, the backup chain is broken: %w", srcBackup.BaseSnapshot, err)
+				} else if err != nil {
 					return "", err
 				}
 
@@ -269,8 +295,9 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 	}
 
 	type btrfsCopyOp struct {
-		src  string
-		dest string
+		src          string
+		dest         string
+		receivedUUID string
 	}
 
 	var copyOps []btrfsCopyOp
@@ -310,9 +337,20 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 				return err
 			}
 
+			receivedVol := Volume{
+				pool:            d.name,
+				mountCustomPath: unpackedSubVolPath,
+			}
+
+			receivedUUID, err := d.getSubVolumeReceivedUUID(receivedVol)
+			if err != nil {
+				return fmt.Errorf("Failed getting UUID: %w", err)
+			}
+
 			copyOps = append(copyOps, btrfsCopyOp{
-				src:  unpackedSubVolPath,
-				dest: subVolTargetPath,
+				src:          unpackedSubVolPath,
+				dest:         subVolTargetPath,
+				receivedUUID: receivedUUID,
 			})
 		}
 
@@ -381,14 +419,31 @@ func (d *btrfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, sr
 			return nil, nil, err
 		}
 
-		// Clear the target for the subvol to use.
-		_ = os.Remove(copyOp.dest)
+		// Clear the target for the subvol to use. When applying an incremental backup the
+		// destination is the existing volume, which os.Remove cannot delete.
+		if d.isSubvolume(copyOp.dest) {
+			err = d.deleteSubvolume(copyOp.dest, true)
+			if err != nil {
+				return nil, nil, err
+			}
+		} else {
+			_ = os.Remove(copyOp.dest)
+		}
 
 		// Move unpacked subvolume into its final location.
 		err = os.Rename(copyOp.src, copyOp.dest)
 		if err != nil {
 			return nil, nil, err
 		}
+
+		// Making the subvolume writable above cleared its received UUID. Set it again so that the
+		// snapshots can be used as the base of the next incremental backup of the chain.
+		if copyOp.receivedUUID != "" {
+			err = setReceivedUUID(copyOp.dest, copyOp.receivedUUID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed setting received UUID: %w", err)
+			}
+		}
 	}
 
 	// Restore readonly property on subvolumes that need it.
@@ -1440,8 +1495,8 @@ func (d *btrfs) migrateVolumeOptimized(vol Volume, conn io.ReadWriteCloser, volS
 }
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
-// This driver does not support optimized backups.
-func (d *btrfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+// If baseSnapshot is set, the optimized backup is sent incrementally from that snapshot.
+func (d *btrfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -1614,6 +1669,21 @@ func (d *btrfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *inst
 
 	// Backup snapshots if populated.
 	lastVolPath := "" // Used as parent for differential exports.
+
+	// Incremental backups only contain the changes since the base snapshot.
+	if baseSnapshot != "" {
+		baseVol, err := vol.NewSnapshot(baseSnapshot)
+		if err != nil {
+			return err
+		}
+
+		if !d.isSubvolume(baseVol.MountPath()) {
+			return fmt.Errorf("Base snapshot %q of the incremental backup not found", baseSnapshot)
+		}
+
+		lastVolPath = baseVol.MountPath()
+	}
+
 	for _, snapName := range snapshots {
 		snapVol, _ := vol.NewSnapshot(snapName)
 
@@ -1913,6 +1983,71 @@ func (d *btrfs) RestoreVolume(vol Volume, snapVol Volume, progressReporter iopro
 	return d.deleteSubvolume(backupSubvolume, true)
 }
 
+// DiffVolumeSnapshot returns the paths which differ between a volume snapshot and a later snapshot or the volume
+// itself. The subvolumes are compared along with the files btrfs reports as written to since the snapshot was taken.
+func (d *btrfs) DiffVolumeSnapshot(snapVol Volume, vol Volume, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	var entries []api.SnapshotDiffEntry
+
+	err := diffMountTask(snapVol, vol, func(oldRoot string, newRoot string) error {
+		var err error
+		entries, err = diffTrees(oldRoot, newRoot)
+		if err != nil {
+			return err
+		}
+
+		// Comparing the trees misses files rewritten in place with the same size and modification time.
+		oldGeneration, err := d.subvolumeGeneration(oldRoot)
+		if err != nil {
+			return err
+		}
+
+		newGeneration, err := d.subvolumeGeneration(newRoot)
+		if err != nil {
+			return err
+		}
+
+		if newGeneration <= oldGeneration {
+			return nil
+		}
+
+		out, err := shared.RunCommand(context.TODO(), "btrfs", "subvolume", "find-new", newRoot, strconv.FormatUint(oldGeneration, 10))
+		if err != nil {
+			return err
+		}
+
+		changed := make(map[string]bool, len(entries))
+		for _, entry := range entries {
+			changed[entry.Path] = true
+		}
+
+		var paths []string
+		for _, path := range btrfsFindNewPaths(out) {
+			if !changed[path] {
+				paths = append(paths, path)
+			}
+		}
+
+		rewritten, err := diffPaths(oldRoot, newRoot, paths)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, rewritten...)
+		if len(entries) > maxDiffEntries {
+			return errTooManyDiffEntries
+		}
+
+		sortDiffEntries(entries)
+
+		return nil
+	}, progressReporter)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
 // RenameVolumeSnapshot renames a volume snapshot.
 func (d *btrfs) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, progressReporter ioprogress.ProgressReporter) error {
 	return genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, progressReporter)
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os/exec"
 	"slices"
 	"strconv"
@@ -47,6 +48,7 @@ func (d *ceph) load() error {
 		"storage_zfs_drop_block_volume_filesystem_extension": nil,
 		"storage_prefix_bucket_names_with_project":           nil,
 		"storage_zfs_remove_local_bucket_datasets":           nil,
+		"storage_create_local_bucket_structure":              nil,
 	}
 
 	// Done if previously loaded.
@@ -399,6 +401,9 @@ func (d *ceph) Validate(config map[string]string) error {
 		"volatile.pool.pristine": validate.IsAny,
 	}
 
+	// Append scrub rules.
+	maps.Insert(rules, maps.All(scrubPoolRules()))
+
 	for configOption, configOptionValue := range config {
 		oldValue, ok := d.config[configOption]
 
@@ -531,6 +536,47 @@ func (d *ceph) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetHealth returns the health of the Ceph cluster and its OSDs.
+func (d *ceph) GetHealth() (*api.StoragePoolHealth, error) {
+	health, err := shared.RunCommand(context.TODO(), "ceph",
+		"--name", "client."+d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"health",
+		"detail",
+		"-f", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	osdTree, err := shared.RunCommand(context.TODO(), "ceph",
+		"--name", "client."+d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"osd",
+		"tree",
+		"-f", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	return cephParseHealth(health, osdTree)
+}
+
+// Scrub instructs the Ceph cluster to deep scrub the placement groups of the OSD pool.
+func (d *ceph) Scrub() error {
+	_, err := shared.RunCommand(context.TODO(), "ceph",
+		"--name", "client."+d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"osd",
+		"pool",
+		"deep-scrub",
+		d.config["ceph.osd.pool_name"])
+	if err != nil {
+		return fmt.Errorf("Failed starting deep scrub of OSD pool %q: %w", d.config["ceph.osd.pool_name"], err)
+	}
+
+	return nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *ceph) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
 	var rsyncFeatures []string
@@ -19,6 +19,7 @@ import (
 
 	"github.com/canonical/lxd/lxd/db/cluster"
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/storage/block"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
@@ -200,13 +201,21 @@ func (d *ceph) rbdMapVolume(vol Volume) (string, error) {
 // rbdUnmapVolume unmaps a given RBD storage volume.
 // This is a precondition in order to delete an RBD storage volume can.
 func (d *ceph) rbdUnmapVolume(vol Volume, unmapUntilEINVAL bool) error {
+	// Lock the unlocked LUKS device of encrypted images first as it keeps the RBD device busy.
+	// This doesn't rely on the volume config as callers don't always have it, and does nothing for
+	// images which are not unlocked.
+	err := luksClose(d.luksID(vol))
+	if err != nil {
+		return err
+	}
+
 	busyCount := 0
 	rbdVol := d.getRBDVolumeName(vol, "", false, false)
 
 	ourDeactivate := false
 
 again:
-	_, err := shared.RunCommand(
+	_, err = shared.RunCommand(
 		context.TODO(),
 		"rbd",
 		"--id", d.config["ceph.user.name"],
@@ -637,6 +646,86 @@ func (d *ceph) getOSDPoolDefaultSize() (int, error) {
 	return defaultSizeInt, nil
 }
 
+// cephParseHealth parses the JSON output of "ceph health detail" and "ceph osd tree" and returns the health of the
+// Ceph cluster backing the pool. The OSDs of the cluster are reported as its devices.
+func cephParseHealth(healthOut string, osdTreeOut string) (*api.StoragePoolHealth, error) {
+	// Temporary structs for parsing.
+	type cephHealthCheck struct {
+		Summary struct {
+			Message string `json:"message"`
+		} `json:"summary"`
+	}
+
+	type cephHealth struct {
+		Status string                     `json:"status"`
+		Checks map[string]cephHealthCheck `json:"checks"`
+	}
+
+	type cephOSDTreeNode struct {
+		Name   string `json:"name"`
+		Type   string `json:"type"`
+		Status string `json:"status"`
+	}
+
+	type cephOSDTree struct {
+		Nodes []cephOSDTreeNode `json:"nodes"`
+		Stray []cephOSDTreeNode `json:"stray"`
+	}
+
+	var health cephHealth
+	err := json.Unmarshal([]byte(healthOut), &health)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing ceph health: %w", err)
+	}
+
+	var osdTree cephOSDTree
+	err = json.Unmarshal([]byte(osdTreeOut), &osdTree)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing ceph OSD tree: %w", err)
+	}
+
+	res := &api.StoragePoolHealth{
+		Devices: []api.StoragePoolHealthDevice{},
+	}
+
+	switch health.Status {
+	case "HEALTH_OK":
+		res.State = api.StoragePoolHealthStateHealthy
+	case "HEALTH_WARN", "HEALTH_ERR":
+		res.State = api.StoragePoolHealthStateDegraded
+	default:
+		return nil, fmt.Errorf("Unknown ceph health status %q", health.Status)
+	}
+
+	// Sort the checks by name for a stable message.
+	checkNames := make([]string, 0, len(health.Checks))
+	for name := range health.Checks {
+		checkNames = append(checkNames, name)
+	}
+
+	slices.Sort(checkNames)
+
+	messages := make([]string, 0, len(checkNames))
+	for _, name := range checkNames {
+		messages = append(messages, health.Checks[name].Summary.Message)
+	}
+
+	res.Message = strings.Join(messages, "; ")
+
+	for _, node := range append(osdTree.Nodes, osdTree.Stray...) {
+		if node.Type != "osd" {
+			continue
+		}
+
+		res.Devices = append(res.Devices, api.StoragePoolHealthDevice{
+			Name:  node.Name,
+			State: node.Status,
+		})
+	}
+
+	return res, nil
+}
+
 // copyVolumeDiff creates a sparse copy of a volume by exporting and importing the diff
 // between `sourceVolumeName` and its optional `sourceParentSnapshot` onto `targetVolumeName`.
 // This does not introduce a dependency relation between the source RBD storage
@@ -1137,6 +1226,77 @@ func (d *ceph) getRBDMappedDevPath(vol Volume, mapIfMissing bool) (bool, string,
 	return false, "", fmt.Errorf("Volume %q not mapped to an RBD device", vol.Name())
 }
 
+// isEncrypted returns whether the RBD image of the volume is encrypted.
+func (d *ceph) isEncrypted(vol Volume) bool {
+	return vol.IsEncrypted() && encryptableVolume(vol)
+}
+
+// luksID returns the identifier of the unlocked LUKS device of the RBD image of a volume.
+// The RBD image name is used as RBD device paths change when images are mapped again.
+func (d *ceph) luksID(vol Volume) string {
+	return "ceph:" + d.getRBDVolumeName(vol, "", false, true)
+}
+
+// formatEncryptedVolume formats the mapped RBD image at devPath of a new encrypted volume as a LUKS device.
+// Returns the path of the unlocked device. Block volumes are also cleared as unwritten blocks would otherwise
+// not read as zeroes.
+func (d *ceph) formatEncryptedVolume(vol Volume, devPath string) (string, error) {
+	key, err := d.volumeEncryptionKey(vol)
+	if err != nil {
+		return "", err
+	}
+
+	err = luksFormat(devPath, key)
+	if err != nil {
+		return "", err
+	}
+
+	dataPath, err := luksOpen(devPath, d.luksID(vol), key, false)
+	if err != nil {
+		return "", err
+	}
+
+	if IsContentBlock(vol.contentType) {
+		err = block.ClearBlock(dataPath, 0)
+		if err != nil {
+			_ = luksClose(d.luksID(vol))
+			return "", fmt.Errorf("Failed clearing encrypted RBD volume: %w", err)
+		}
+	}
+
+	return dataPath, nil
+}
+
+// openEncryptedVolume unlocks the mapped RBD image rbdVol at devPath if vol is encrypted.
+// The RBD image can differ from the volume, for example for the temporary clones used to mount snapshots.
+// Returns the path of the unlocked device, or devPath if the volume isn't encrypted.
+func (d *ceph) openEncryptedVolume(vol Volume, rbdVol Volume, devPath string, readOnly bool) (string, error) {
+	if !d.isEncrypted(vol) {
+		return devPath, nil
+	}
+
+	key, err := d.volumeEncryptionKey(vol)
+	if err != nil {
+		return "", err
+	}
+
+	return luksOpen(devPath, d.luksID(rbdVol), key, readOnly)
+}
+
+// resizeEncryptedVolume resizes the unlocked device of an encrypted volume to match its RBD image.
+func (d *ceph) resizeEncryptedVolume(vol Volume) error {
+	if !d.isEncrypted(vol) {
+		return nil
+	}
+
+	key, err := d.volumeEncryptionKey(vol)
+	if err != nil {
+		return err
+	}
+
+	return luksResize(d.luksID(vol), key)
+}
+
 // generateUUID regenerates the XFS/btrfs UUID as needed.
 func (d *ceph) generateUUID(fsType string, devPath string) error {
 	if !regenerateFilesystemUUIDNeeded(fsType) {
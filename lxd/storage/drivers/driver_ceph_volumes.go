@@ -140,8 +140,19 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter i
 		}
 	}
 
+	// Reserve space for the LUKS header so that the usable size matches the volume size.
+	size := vol.ConfigSize()
+	if d.isEncrypted(vol) {
+		sizeBytes, err := units.ParseByteSizeString(size)
+		if err != nil {
+			return err
+		}
+
+		size = fmt.Sprintf("%dB", sizeBytes+luksHeaderSize)
+	}
+
 	// Create volume.
-	err := d.rbdCreateVolume(vol, vol.ConfigSize())
+	err := d.rbdCreateVolume(vol, size)
 	if err != nil {
 		return err
 	}
@@ -155,6 +166,13 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter i
 
 	revert.Add(func() { _ = d.rbdUnmapVolume(vol, true) })
 
+	if d.isEncrypted(vol) {
+		devPath, err = d.formatEncryptedVolume(vol, devPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get filesystem.
 	RBDFilesystem := vol.ConfigBlockFilesystem()
 
@@ -323,6 +341,18 @@ func (d *ceph) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, src
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *ceph) CreateVolumeFromCopy(vol VolumeCopy, srcVol VolumeCopy, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error {
+	// Encrypted volumes can only be copied with RBD if they share the same key, otherwise run the generic copy.
+	if !sameEncryptionKey(vol.Volume, srcVol.Volume) {
+		snapshotNames := make([]string, 0, len(vol.Snapshots))
+		for _, snapshot := range vol.Snapshots {
+			_, snapshotName, _ := api.GetParentAndSnapshotName(snapshot.name)
+			snapshotNames = append(snapshotNames, snapshotName)
+		}
+
+		_, err := genericVFSCopyVolume(d, nil, vol, srcVol, snapshotNames, false, allowInconsistent, progressReporter)
+		return err
+	}
+
 	var err error
 	revert := revert.New()
 	defer revert.Fail()
@@ -338,6 +368,11 @@ func (d *ceph) CreateVolumeFromCopy(vol VolumeCopy, srcVol VolumeCopy, allowInco
 
 		defer func() { _ = d.rbdUnmapVolume(v, true) }()
 
+		devPath, err = d.openEncryptedVolume(v, v, devPath, false)
+		if err != nil {
+			return err
+		}
+
 		if vol.contentType == ContentTypeFS {
 			// Re-generate the UUID. Do this first as ensuring permissions and setting quota can
 			// rely on being able to mount the volume.
@@ -759,8 +794,8 @@ func (d *ceph) EnsureImage(imgVol Volume, filler *VolumeFiller, progressReporter
 // refreshVolume updates an existing volume to match the state of another.
 // It returns the cleanup hooks required to revert any changes made during the refresh.
 func (d *ceph) refreshVolume(vol VolumeCopy, srcVol VolumeCopy, refreshSnapshots []string, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) (revert.Hook, error) {
-	// Copy volumes with content type filesystem using the generic approach.
-	if vol.contentType == ContentTypeFS {
+	// Copy volumes with content type filesystem or encrypted with another key using the generic approach.
+	if vol.contentType == ContentTypeFS || !sameEncryptionKey(vol.Volume, srcVol.Volume) {
 		return genericVFSCopyVolume(d, nil, vol, srcVol, refreshSnapshots, true, allowInconsistent, progressReporter)
 	}
 
@@ -1128,12 +1163,18 @@ func (d *ceph) HasVolume(vol Volume) (bool, error) {
 func (d *ceph) FillVolumeConfig(vol Volume) error {
 	// Copy volume.* configuration options from pool.
 	// Exclude 'block.filesystem' and 'block.mount_options'
-	// as this ones are handled below in this function and depends from volume type
-	err := d.fillVolumeConfig(&vol, "block.filesystem", "block.mount_options")
+	// as this ones are handled below in this function and depends from volume type.
+	// Exclude 'security.encrypted' as it only applies to instance and custom volumes (handled below).
+	err := d.fillVolumeConfig(&vol, "block.filesystem", "block.mount_options", "security.encrypted")
 	if err != nil {
 		return err
 	}
 
+	// Inherit encryption from pool if not set.
+	if vol.config["security.encrypted"] == "" && d.config["volume.security.encrypted"] != "" && encryptableVolume(vol) {
+		vol.config["security.encrypted"] = d.config["volume.security.encrypted"]
+	}
+
 	// Only validate filesystem config keys for filesystem volumes or VM block volumes (which have an
 	// associated filesystem volume).
 	if vol.ContentType() == ContentTypeFS || vol.IsVMBlock() {
@@ -1190,6 +1231,19 @@ func (d *ceph) commonVolumeRules() map[string]func(value string) error {
 		//  shortdesc: Mount options for block-backed file system volumes
 		//  scope: global
 		"block.mount_options": validate.IsAny,
+		// lxdmeta:generate(entities=storage-ceph; group=volume-conf; key=security.encrypted)
+		// Enable this option to store the volume encrypted with LUKS2.
+		// LXD generates the encryption key when creating the volume and unlocks the volume automatically when using it,
+		// on any cluster member.
+		// This option cannot be changed after the volume is created.
+		// See {ref}`storage-ceph-encryption` for more information.
+		// ---
+		//  type: bool
+		//  condition: instance or custom volume
+		//  defaultdesc: same as `volume.security.encrypted` or `false`
+		//  shortdesc: Whether to encrypt the volume
+		//  scope: global
+		"security.encrypted": validate.Optional(validate.IsBool),
 	}
 }
 
@@ -1206,7 +1260,25 @@ func (d *ceph) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "block.mount_options")
 	}
 
-	return d.validateVolume(vol, commonRules, removeUnknownKeys)
+	// lxdmeta:generate(entities=storage-ceph; group=volume-conf; key=volatile.encryption.key)
+	// The key is wrapped with a key that is shared by all cluster members.
+	// ---
+	//  type: string
+	//  condition: encrypted volume
+	//  shortdesc: Encryption key of the volume
+	//  scope: global
+	commonRules["volatile.encryption.key"] = validate.IsAny
+
+	err := d.validateVolume(vol, commonRules, removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	if vol.IsEncrypted() && !encryptableVolume(vol) {
+		return errors.New("security.encrypted can only be used with instance and custom volumes")
+	}
+
+	return nil
 }
 
 // UpdateVolume applies config changes to the volume.
@@ -1337,6 +1409,17 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 		defer func() { _ = d.rbdUnmapVolume(vol, true) }()
 	}
 
+	// The data of encrypted volumes is stored on the unlocked device, after the LUKS header.
+	var dataOffset int64
+	if d.isEncrypted(vol) {
+		dataOffset = luksHeaderSize
+
+		devPath, err = d.openEncryptedVolume(vol, vol, devPath, false)
+		if err != nil {
+			return err
+		}
+	}
+
 	oldSizeBytes, err := block.DiskSizeBytes(devPath)
 	if err != nil {
 		return fmt.Errorf("Error getting current size: %w", err)
@@ -1370,13 +1453,23 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 			}
 
 			// Shrink the block device.
-			err = d.resizeVolume(vol, sizeBytes, true)
+			err = d.resizeVolume(vol, sizeBytes+dataOffset, true)
+			if err != nil {
+				return err
+			}
+
+			err = d.resizeEncryptedVolume(vol)
 			if err != nil {
 				return err
 			}
 		} else if sizeBytes > oldSizeBytes {
 			// Grow block device first.
-			err = d.resizeVolume(vol, sizeBytes, false)
+			err = d.resizeVolume(vol, sizeBytes+dataOffset, false)
+			if err != nil {
+				return err
+			}
+
+			err = d.resizeEncryptedVolume(vol)
 			if err != nil {
 				return err
 			}
@@ -1401,11 +1494,24 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 		}
 
 		// Resize block device.
-		err = d.resizeVolume(vol, sizeBytes, allowUnsafeResize)
+		err = d.resizeVolume(vol, sizeBytes+dataOffset, allowUnsafeResize)
+		if err != nil {
+			return err
+		}
+
+		err = d.resizeEncryptedVolume(vol)
 		if err != nil {
 			return err
 		}
 
+		// The new blocks of grown encrypted volumes need clearing as they would otherwise not read as zeroes.
+		if d.isEncrypted(vol) && sizeBytes > oldSizeBytes {
+			err = block.ClearBlock(devPath, oldSizeBytes)
+			if err != nil {
+				return fmt.Errorf("Failed clearing encrypted RBD volume: %w", err)
+			}
+		}
+
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as it is
 		// expected the caller will do all necessary post resize actions themselves).
 		if vol.IsVMBlock() && !allowUnsafeResize {
@@ -1423,7 +1529,16 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, p
 func (d *ceph) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
 		_, devPath, err := d.getRBDMappedDevPath(vol, false)
-		return devPath, err
+		if err != nil {
+			return "", err
+		}
+
+		// For encrypted volumes this is the unlocked LUKS device backed by the RBD image.
+		if d.isEncrypted(vol) {
+			return luksMapperPath(d.luksID(vol)), nil
+		}
+
+		return devPath, nil
 	}
 
 	return "", ErrNotSupported
@@ -1559,6 +1674,11 @@ func (d *ceph) MountVolume(vol Volume, progressReporter ioprogress.ProgressRepor
 		revert.Add(func() { _ = d.rbdUnmapVolume(vol, true) })
 	}
 
+	volDevPath, err = d.openEncryptedVolume(vol, vol, volDevPath, false)
+	if err != nil {
+		return err
+	}
+
 	switch vol.contentType {
 	case ContentTypeFS:
 		mountPath := vol.MountPath()
@@ -1879,7 +1999,7 @@ func (d *ceph) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *ceph) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *ceph) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, progressReporter)
 }
 
@@ -2048,6 +2168,13 @@ func (d *ceph) MountVolumeSnapshot(snapVol Volume, progressReporter ioprogress.P
 		revert.Add(func() { _ = d.rbdUnmapVolume(cloneVol, true) })
 
 		RBDFilesystem := snapVol.ConfigBlockFilesystem()
+
+		// The clone only needs to be writable when its filesystem UUID is regenerated below.
+		rbdDevPath, err = d.openEncryptedVolume(snapVol, cloneVol, rbdDevPath, !regenerateFilesystemUUIDNeeded(RBDFilesystem) || RBDFilesystem == "xfs")
+		if err != nil {
+			return err
+		}
+
 		mountFlags, mountOptions := filesystem.ResolveMountOptions(strings.Split(snapVol.ConfigBlockMountOptions(), ","))
 		mountOptions = addNoRecoveryMountOption(mountOptions, RBDFilesystem)
 
@@ -2080,7 +2207,16 @@ func (d *ceph) MountVolumeSnapshot(snapVol Volume, progressReporter ioprogress.P
 		d.logger.Debug("Mounted RBD volume snapshot", logger.Ctx{"dev": rbdDevPath, "path": mountPath, "options": mountOptions})
 	} else if snapVol.contentType == ContentTypeBlock {
 		// Activate RBD volume if needed.
-		_, _, err := d.getRBDMappedDevPath(snapVol, true)
+		activated, devPath, err := d.getRBDMappedDevPath(snapVol, true)
+		if err != nil {
+			return err
+		}
+
+		if activated {
+			revert.Add(func() { _ = d.rbdUnmapVolume(snapVol, true) })
+		}
+
+		_, err = d.openEncryptedVolume(snapVol, snapVol, devPath, true)
 		if err != nil {
 			return err
 		}
@@ -2242,6 +2378,11 @@ func (d *ceph) restoreVolume(vol Volume, snapVol Volume, progressReporter ioprog
 
 	defer func() { _ = d.rbdUnmapVolume(vol, true) }()
 
+	devPath, err = d.openEncryptedVolume(vol, vol, devPath, false)
+	if err != nil {
+		return err
+	}
+
 	// Re-generate the UUID.
 	if vol.contentType == ContentTypeFS {
 		err = d.generateUUID(vol.ConfigBlockFilesystem(), devPath)
@@ -35,6 +35,7 @@ func (d *cephobject) load() error {
 		"storage_zfs_drop_block_volume_filesystem_extension": nil,
 		"storage_prefix_bucket_names_with_project":           nil,
 		"storage_zfs_remove_local_bucket_datasets":           nil,
+		"storage_create_local_bucket_structure":              nil,
 	}
 
 	// Done if previously loaded.
@@ -136,6 +136,11 @@ func (d *common) fillVolumeConfig(vol *Volume, excludedKeys ...string) error {
 			continue
 		}
 
+		// I/O limits are only relevant for custom volumes.
+		if vol.Type() != VolumeTypeCustom && strings.HasPrefix(volKey, "limits.") {
+			continue
+		}
+
 		if vol.config[volKey] == "" {
 			vol.config[volKey] = d.config[k]
 		}
@@ -218,6 +223,16 @@ func (d *common) ValidateSource() error {
 	return ErrNotSupported
 }
 
+// GetHealth returns the health of the pool and the devices backing it.
+func (d *common) GetHealth() (*api.StoragePoolHealth, error) {
+	return nil, ErrNotSupported
+}
+
+// Scrub starts verifying the integrity of the data stored in the pool.
+func (d *common) Scrub() error {
+	return ErrNotSupported
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing migrations between pools
 // in preference order.
 func (d *common) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
@@ -386,7 +401,8 @@ func (d *common) CreateVolumeFromCopy(vol VolumeCopy, srcVol VolumeCopy, allowIn
 // implementation is a no-op for drivers that do not optimise image storage;
 // the backend short-circuits before reaching this method when
 // OptimizedImages is false. Drivers with OptimizedImages = true must
-// override.
+// override. Drivers that only cache some image types return ErrNotSupported
+// for the others and the backend then unpacks the image instead.
 func (d *common) EnsureImage(imgVol Volume, filler *VolumeFiller, progressReporter ioprogress.ProgressReporter) error {
 	return ErrNotSupported
 }
@@ -473,7 +489,7 @@ func (d *common) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcAr
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *common) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *common) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return ErrNotSupported
 }
 
@@ -539,6 +555,12 @@ func (d *common) RestoreVolume(vol Volume, snapVol Volume, progressReporter iopr
 	return ErrNotSupported
 }
 
+// DiffVolumeSnapshot returns the paths which differ between a volume snapshot and a later snapshot or the volume
+// itself by comparing their mounted filesystems.
+func (d *common) DiffVolumeSnapshot(snapVol Volume, vol Volume, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	return genericVFSDiffVolumeSnapshot(snapVol, vol, progressReporter)
+}
+
 // RenameVolumeSnapshot renames a snapshot.
 func (d *common) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, progressReporter ioprogress.ProgressReporter) error {
 	return ErrNotSupported
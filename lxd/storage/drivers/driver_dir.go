@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
+	"github.com/canonical/lxd/shared/validate"
 )
 
 type dir struct {
@@ -30,6 +33,7 @@ func (d *dir) load() error {
 		"storage_zfs_drop_block_volume_filesystem_extension": nil,
 		"storage_prefix_bucket_names_with_project":           nil,
 		"storage_zfs_remove_local_bucket_datasets":           nil,
+		"storage_create_local_bucket_structure":              nil,
 	}
 
 	return nil
@@ -42,16 +46,16 @@ func (d *dir) Info() Info {
 		Version:                      "1",
 		DefaultBlockSize:             d.defaultBlockVolumeSize(),
 		DefaultVMBlockFilesystemSize: d.defaultVMBlockFilesystemSize(),
-		OptimizedImages:              false,
+		OptimizedImages:              d.usesQcow2(),
 		PreservesInodes:              false,
 		Remote:                       d.isRemote(),
-		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		VolumeTypes:                  []VolumeType{VolumeTypeBucket, VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
 		BlockBacking:                 false,
 		RunningCopyFreeze:            true,
 		DirectIO:                     true,
 		IOUring:                      true,
 		MountedRoot:                  true,
-		Buckets:                      false,
+		Buckets:                      true,
 		PopulateParentVolumeUUID:     false,
 	}
 }
@@ -145,8 +149,38 @@ func (d *dir) Delete(progressReporter ioprogress.ProgressReporter) error {
 
 // Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
 func (d *dir) Validate(config map[string]string) error {
-	// Use common local pool rules.
-	return d.validatePool(config, d.commonRules.LocalPoolRules(), nil)
+	rules := map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-dir; group=pool-conf; key=dir.block_format)
+		// When set to `qcow2`, virtual-machine root disks are stored as qcow2 files.
+		// Instances created from an image are then thin clones that are backed by the cached image,
+		// and snapshots of stopped instances only store the changes since the previous snapshot.
+		// Changing this option only affects newly created volumes.
+		// Using `qcow2` requires the `qemu-img` tool.
+		// See {ref}`storage-dir-qcow2` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: `raw`
+		//  shortdesc: Format of virtual-machine root disk files (`raw` or `qcow2`)
+		//  scope: global
+		"dir.block_format": validate.Optional(validate.IsOneOf(DiskImageFormatRaw, DiskImageFormatQcow2)),
+	}
+
+	// Append common local pool rules.
+	maps.Insert(rules, maps.All(d.commonRules.LocalPoolRules()))
+
+	err := d.validatePool(config, rules, nil)
+	if err != nil {
+		return err
+	}
+
+	if config["dir.block_format"] == DiskImageFormatQcow2 {
+		_, err := exec.LookPath("qemu-img")
+		if err != nil {
+			return errors.New(`Required tool "qemu-img" is missing`)
+		}
+	}
+
+	return nil
 }
 
 // Update applies any driver changes required from a configuration change.
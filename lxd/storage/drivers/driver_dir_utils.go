@@ -3,13 +3,24 @@ package drivers
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
 
+	"github.com/google/uuid"
+
+	"github.com/canonical/lxd/lxd/rsync"
+	"github.com/canonical/lxd/lxd/storage/block"
 	"github.com/canonical/lxd/lxd/storage/quota"
+	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/units"
 )
 
+// dirQcow2MaxLayers limits the length of the backing chain of qcow2 disks that snapshots add layers to.
+const dirQcow2MaxLayers = 32
+
 // withoutGetVolID returns a copy of this struct but with a volIDFunc which will cause quotas to be skipped.
 func (d *dir) withoutGetVolID() Driver {
 	newDriver := &dir{}
@@ -136,3 +147,438 @@ func (d *dir) setQuota(path string, volID int64, sizeBytes int64) error {
 	// Set the project quota size.
 	return quota.SetProjectQuota(path, projectID, sizeBytes)
 }
+
+// usesQcow2 returns whether new virtual-machine root disks are stored as qcow2 files.
+func (d *dir) usesQcow2() bool {
+	return d.config["dir.block_format"] == DiskImageFormatQcow2
+}
+
+// vmDiskFilesSizeBytes returns the combined size of all the disk files in a virtual-machine volume's directory.
+// For qcow2 disk files the virtual size is used as that is the most they can grow to.
+func (d *dir) vmDiskFilesSizeBytes(volPath string) (int64, error) {
+	var sizeBytes int64
+
+	for _, diskFile := range genericVolumeDiskFiles {
+		diskPaths, err := filepath.Glob(filepath.Join(volPath, diskFile))
+		if err != nil {
+			return -1, err
+		}
+
+		for _, diskPath := range diskPaths {
+			diskSizeBytes, err := block.DiskSizeBytes(diskPath)
+			if err != nil {
+				return -1, err
+			}
+
+			sizeBytes += diskSizeBytes
+		}
+	}
+
+	return sizeBytes, nil
+}
+
+// convertVolumeToQcow2 converts the raw root disk of a virtual-machine volume into a qcow2 disk.
+func (d *dir) convertVolumeToQcow2(vol Volume) error {
+	rawPath := filepath.Join(vol.MountPath(), genericVolumeDiskFile)
+	qcow2Path := filepath.Join(vol.MountPath(), genericVolumeQcow2DiskFile)
+
+	d.logger.Debug("Converting virtual machine disk to qcow2", logger.Ctx{"volName": vol.name, "path": rawPath})
+
+	err := convertDiskImage(rawPath, qcow2Path)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(rawPath)
+	if err != nil {
+		_ = os.Remove(qcow2Path)
+		return fmt.Errorf("Failed removing raw disk %q: %w", rawPath, err)
+	}
+
+	return nil
+}
+
+// createVolumeFromImageQcow2 creates a virtual-machine volume whose qcow2 disk is a thin clone of the raw disk of
+// the image volume. The image's disk is shared with the new volume as its read-only base disk.
+func (d *dir) createVolumeFromImageQcow2(vol Volume, imgVol Volume) error {
+	imgDiskPath, err := d.GetVolumeDiskPath(imgVol)
+	if err != nil {
+		return err
+	}
+
+	baseSizeBytes, err := block.DiskSizeBytes(imgDiskPath)
+	if err != nil {
+		return err
+	}
+
+	sizeBytes, err := units.ParseByteSizeString(vol.ConfigSize())
+	if err != nil {
+		return err
+	}
+
+	// Get rounded block size to avoid QEMU boundary issues.
+	sizeBytes = d.roundVolumeBlockSizeBytes(vol, sizeBytes)
+	if sizeBytes < baseSizeBytes {
+		return fmt.Errorf("Block volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+	}
+
+	volPath := vol.MountPath()
+	if shared.PathExists(volPath) {
+		return fmt.Errorf("Volume path %q already exists", volPath)
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err = vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = os.RemoveAll(volPath) })
+
+	// Copy the config filesystem of the image.
+	bwlimit := d.config["rsync.bwlimit"]
+	rsyncArgs := genericVFSDiskFilesRsyncArgs()
+	d.Logger().Debug("Copying fileystem volume", logger.Ctx{"sourcePath": imgVol.MountPath(), "targetPath": volPath, "bwlimit": bwlimit, "rsyncArgs": rsyncArgs})
+	_, err = rsync.LocalCopy(imgVol.MountPath(), volPath, bwlimit, true, rsyncArgs...)
+	if err != nil {
+		return err
+	}
+
+	// Share the image's disk with the volume. If the files can't be shared (for example when the directories
+	// use different project quotas), fall back to copying it.
+	basePath := filepath.Join(volPath, genericVolumeBaseDiskFile)
+	err = os.Link(imgDiskPath, basePath)
+	if err != nil {
+		d.logger.Debug("Failed sharing image disk, copying it instead", logger.Ctx{"imgDiskPath": imgDiskPath, "err": err})
+
+		err = ensureSparseFile(basePath, 0)
+		if err != nil {
+			return err
+		}
+
+		err = copyDevice(imgDiskPath, basePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The GPT alternative header isn't moved to the end of the larger disk as sgdisk can't operate on qcow2
+	// files, this is left to the guest.
+	err = qcow2Create(filepath.Join(volPath, genericVolumeQcow2DiskFile), basePath, sizeBytes)
+	if err != nil {
+		return err
+	}
+
+	// Run EnsureMountPath after copying to ensure the directory has the correct permissions set.
+	err = vol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// setQcow2VolumeSize changes the virtual size of the qcow2 disk of a volume.
+func (d *dir) setQcow2VolumeSize(vol Volume, diskPath string, sizeBytes int64, allowUnsafeResize bool) error {
+	// Get rounded block size to avoid QEMU boundary issues.
+	sizeBytes = d.roundVolumeBlockSizeBytes(vol, sizeBytes)
+
+	oldSizeBytes, err := block.DiskSizeBytes(diskPath)
+	if err != nil {
+		return err
+	}
+
+	if sizeBytes == oldSizeBytes {
+		return nil
+	}
+
+	// Only perform pre-resize checks if we are not in "unsafe" mode.
+	// In unsafe mode we expect the caller to know what they are doing and understand the risks.
+	if !allowUnsafeResize {
+		if sizeBytes < oldSizeBytes {
+			return fmt.Errorf("Block volumes cannot be shrunk: %w", ErrCannotBeShrunk)
+		}
+
+		if vol.MountInUse() {
+			return ErrInUse // We do not allow online resizing of block volumes.
+		}
+	}
+
+	return qcow2Resize(diskPath, sizeBytes, allowUnsafeResize)
+}
+
+// createQcow2VolumeSnapshot creates a snapshot of a volume with a qcow2 disk.
+// When the volume isn't in use, the current disk becomes a read-only layer that both the volume's new disk and
+// the snapshot's disk are backed by, so no data is copied. Otherwise the snapshot's disk is a full copy.
+func (d *dir) createQcow2VolumeSnapshot(vol Volume, snapVol Volume, diskPath string) error {
+	snapDiskPath := filepath.Join(snapVol.MountPath(), genericVolumeQcow2DiskFile)
+
+	chain, err := DiskImageBackingChain(diskPath)
+	if err != nil {
+		return err
+	}
+
+	// A running instance has its disk open, and long backing chains slow down disk I/O.
+	if vol.MountInUse() || len(chain) >= dirQcow2MaxLayers {
+		d.Logger().Debug("Copying qcow2 block volume", logger.Ctx{"srcDevPath": diskPath, "targetPath": snapDiskPath})
+		return convertDiskImage(diskPath, snapDiskPath)
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Keep the layer in the volume's directory so its relative backing file stays valid.
+	layerPath := filepath.Join(vol.MountPath(), "layer-"+uuid.New().String()+".qcow2")
+
+	d.Logger().Debug("Creating qcow2 block volume layer", logger.Ctx{"devPath": diskPath, "layerPath": layerPath})
+	err = os.Rename(diskPath, layerPath)
+	if err != nil {
+		return fmt.Errorf("Failed moving %q to %q: %w", diskPath, layerPath, err)
+	}
+
+	revert.Add(func() { _ = os.Rename(layerPath, diskPath) })
+
+	err = qcow2Create(diskPath, layerPath, 0)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = os.Remove(diskPath) })
+
+	err = qcow2Create(snapDiskPath, layerPath, 0)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// restoreQcow2Volume replaces the qcow2 disk of a volume with a new disk backed by the disk of the snapshot.
+// Snapshots taken as a full copy are shared with the volume as a new layer.
+func (d *dir) restoreQcow2Volume(vol Volume, snapVol Volume, diskPath string) error {
+	snapDiskPath, err := d.GetVolumeDiskPath(snapVol)
+	if err != nil {
+		return err
+	}
+
+	sizeBytes, err := block.DiskSizeBytes(snapDiskPath)
+	if err != nil {
+		return err
+	}
+
+	var layerPath string
+
+	if DiskImageFormat(snapDiskPath) == DiskImageFormatQcow2 {
+		chain, err := DiskImageBackingChain(snapDiskPath)
+		if err != nil {
+			return err
+		}
+
+		if len(chain) > 1 && filepath.Dir(chain[1].Path) == vol.MountPath() {
+			// The snapshot's disk is an empty view of one of the volume's layers.
+			layerPath = chain[1].Path
+		} else if len(chain) == 1 {
+			// Share the snapshot's standalone disk with the volume as a new layer.
+			newLayerPath := filepath.Join(vol.MountPath(), "layer-"+uuid.New().String()+".qcow2")
+			err = os.Link(snapDiskPath, newLayerPath)
+			if err == nil {
+				layerPath = newLayerPath
+			} else {
+				d.logger.Debug("Failed sharing snapshot disk, copying it instead", logger.Ctx{"snapDiskPath": snapDiskPath, "err": err})
+			}
+		}
+	}
+
+	// Fall back to a full copy of the snapshot's disk.
+	if layerPath == "" {
+		d.Logger().Debug("Restoring block volume", logger.Ctx{"srcDevPath": snapDiskPath, "targetPath": diskPath})
+		return convertDiskImage(snapDiskPath, diskPath)
+	}
+
+	d.Logger().Debug("Restoring qcow2 block volume", logger.Ctx{"layerPath": layerPath, "targetPath": diskPath})
+
+	// Create the new disk next to the current one so it can atomically replace it.
+	tmpPath := filepath.Join(vol.MountPath(), "."+genericVolumeQcow2DiskFile+".tmp")
+	_ = os.Remove(tmpPath)
+
+	err = qcow2Create(tmpPath, layerPath, sizeBytes)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, diskPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("Failed moving %q to %q: %w", tmpPath, diskPath, err)
+	}
+
+	return nil
+}
+
+// cleanupQcow2Layers removes the layers of a volume with a qcow2 disk that are no longer used by the volume or its
+// snapshots. When the volume isn't in use, layers that aren't shared with any snapshot are also merged into the
+// disk or layer on top of them to keep backing chains short.
+func (d *dir) cleanupQcow2Layers(vol Volume) error {
+	volPath := vol.MountPath()
+	diskPath := filepath.Join(volPath, genericVolumeQcow2DiskFile)
+	if !shared.PathExists(diskPath) {
+		return nil
+	}
+
+	snapshots, err := d.VolumeSnapshots(vol)
+	if err != nil {
+		return err
+	}
+
+	diskPaths := []string{diskPath}
+	for _, snapName := range snapshots {
+		snapVol, err := vol.NewSnapshot(snapName)
+		if err != nil {
+			return err
+		}
+
+		snapDiskPath, err := d.GetVolumeDiskPath(snapVol)
+		if err != nil {
+			return err
+		}
+
+		if DiskImageFormat(snapDiskPath) == DiskImageFormatQcow2 && shared.PathExists(snapDiskPath) {
+			diskPaths = append(diskPaths, snapDiskPath)
+		}
+	}
+
+	backingPaths := map[string]string{} // Backing file of each disk file in use.
+	pinned := map[string]bool{}         // Layers that snapshots are directly backed by.
+	for i, diskPath := range diskPaths {
+		chain, err := DiskImageBackingChain(diskPath)
+		if err != nil {
+			return err
+		}
+
+		for j, image := range chain {
+			backingPaths[image.Path] = ""
+			if j+1 < len(chain) {
+				backingPaths[image.Path] = chain[j+1].Path
+			}
+		}
+
+		if i > 0 && len(chain) > 1 {
+			pinned[chain[1].Path] = true
+		}
+	}
+
+	layerPaths, err := filepath.Glob(filepath.Join(volPath, genericVolumeLayerDiskFilePattern))
+	if err != nil {
+		return err
+	}
+
+	// Remove the files that nothing is backed by anymore.
+	for _, path := range append(layerPaths, filepath.Join(volPath, genericVolumeBaseDiskFile)) {
+		_, used := backingPaths[path]
+		if used {
+			continue
+		}
+
+		d.logger.Debug("Removing unused qcow2 block volume layer", logger.Ctx{"path": path})
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("Failed removing %q: %w", path, err)
+		}
+	}
+
+	// Layers can only be merged when the disk isn't open.
+	if vol.MountInUse() {
+		return nil
+	}
+
+	for _, layerPath := range layerPaths {
+		_, used := backingPaths[layerPath]
+		if !used || pinned[layerPath] {
+			continue
+		}
+
+		// Keep layers that share their data with a snapshot's standalone disk.
+		fileInfo, err := os.Stat(layerPath)
+		if err != nil {
+			return err
+		}
+
+		stat, ok := fileInfo.Sys().(*syscall.Stat_t)
+		if ok && stat.Nlink > 1 {
+			continue
+		}
+
+		var children []string
+		for path, backingPath := range backingPaths {
+			if backingPath == layerPath {
+				children = append(children, path)
+			}
+		}
+
+		if len(children) != 1 {
+			continue
+		}
+
+		d.logger.Debug("Merging qcow2 block volume layer", logger.Ctx{"path": layerPath, "into": children[0]})
+		err = qcow2Rebase(children[0], backingPaths[layerPath], false)
+		if err != nil {
+			return err
+		}
+
+		backingPaths[children[0]] = backingPaths[layerPath]
+		delete(backingPaths, layerPath)
+
+		err = os.Remove(layerPath)
+		if err != nil {
+			return fmt.Errorf("Failed removing %q: %w", layerPath, err)
+		}
+	}
+
+	return nil
+}
+
+// rebaseQcow2Snapshots updates the backing files recorded in the qcow2 disks of the snapshots of a volume after the
+// volume was renamed from oldVolPath. The layers themselves are in the volume's directory and don't need updating.
+func (d *dir) rebaseQcow2Snapshots(vol Volume, oldVolPath string) error {
+	snapshots, err := d.VolumeSnapshots(vol)
+	if err != nil {
+		return err
+	}
+
+	for _, snapName := range snapshots {
+		snapVol, err := vol.NewSnapshot(snapName)
+		if err != nil {
+			return err
+		}
+
+		snapDiskPath := filepath.Join(snapVol.MountPath(), genericVolumeQcow2DiskFile)
+		if !shared.PathExists(snapDiskPath) {
+			continue
+		}
+
+		info, err := qcow2Info(snapDiskPath)
+		if err != nil {
+			return err
+		}
+
+		if info.BackingFilename == "" {
+			continue
+		}
+
+		oldLayerPath := filepath.Join(filepath.Dir(snapDiskPath), info.BackingFilename)
+		if filepath.Dir(oldLayerPath) != oldVolPath {
+			continue
+		}
+
+		err = qcow2Rebase(snapDiskPath, filepath.Join(vol.MountPath(), filepath.Base(oldLayerPath)), true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/canonical/lxd/lxd/instancewriter"
 	"github.com/canonical/lxd/lxd/migration"
 	"github.com/canonical/lxd/lxd/rsync"
-	"github.com/canonical/lxd/lxd/storage/block"
 	"github.com/canonical/lxd/lxd/storage/filesystem"
 	"github.com/canonical/lxd/lxd/storage/quota"
 	"github.com/canonical/lxd/shared"
@@ -92,6 +91,14 @@ func (d *dir) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter io
 				return err
 			}
 		}
+
+		// Store the root disk of virtual machines as qcow2 if configured.
+		if vol.volType == VolumeTypeVM && vol.contentType == ContentTypeBlock && d.usesQcow2() {
+			err = d.convertVolumeToQcow2(vol)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	revert.Success()
@@ -106,6 +113,15 @@ func (d *dir) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 		return nil, nil, err
 	}
 
+	// Backups always contain raw disks, convert the restored root disk of virtual machines if configured.
+	if vol.volType == VolumeTypeVM && vol.contentType == ContentTypeBlock && d.usesQcow2() {
+		err = d.convertVolumeToQcow2(vol.Volume)
+		if err != nil {
+			revertHook()
+			return nil, nil, err
+		}
+	}
+
 	// genericVFSBackupUnpack returns a nil postHook when volume's type is VolumeTypeCustom which
 	// doesn't need any post hook processing after DB record creation.
 	if postHook != nil {
@@ -138,12 +154,22 @@ func (d *dir) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 }
 
 // EnsureImage materialises the cached image volume on disk if it is not already present.
+// Only virtual-machine images are cached, and only when their root disks are stored as qcow2 thin clones.
 func (d *dir) EnsureImage(imgVol Volume, filler *VolumeFiller, progressReporter ioprogress.ProgressReporter) error {
-	return d.CreateVolume(imgVol, filler, progressReporter)
+	if !imgVol.IsVMBlock() || !d.usesQcow2() {
+		return ErrNotSupported
+	}
+
+	return ensureImageVolume(imgVol, filler, progressReporter)
 }
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *dir) CreateVolumeFromCopy(vol VolumeCopy, srcVol VolumeCopy, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error {
+	// Virtual machines created from a cached image are thin qcow2 clones of the image.
+	if srcVol.volType == VolumeTypeImage && vol.volType == VolumeTypeVM && vol.contentType == ContentTypeBlock && d.usesQcow2() {
+		return d.createVolumeFromImageQcow2(vol.Volume, srcVol.Volume)
+	}
+
 	var srcSnapshots []string
 
 	if len(vol.Snapshots) > 0 && !srcVol.IsSnapshot() {
@@ -307,6 +333,11 @@ func (d *dir) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 			return err
 		}
 
+		// The GPT alternative header of qcow2 disks isn't moved as sgdisk can't operate on them.
+		if DiskImageFormat(rootBlockPath) == DiskImageFormatQcow2 {
+			return d.setQcow2VolumeSize(vol, rootBlockPath, sizeBytes, allowUnsafeResize)
+		}
+
 		resized, err := ensureVolumeBlockFile(vol, rootBlockPath, sizeBytes, allowUnsafeResize)
 		if err != nil {
 			return err
@@ -335,9 +366,8 @@ func (d *dir) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 	volPath := vol.MountPath()
 	if sizeBytes > 0 && vol.volType == VolumeTypeVM {
 		// Get the size of the VM image.
-		diskFile := filepath.Join(volPath, genericVolumeDiskFile)
-		blockSize, err := block.DiskSizeBytes(diskFile)
-		if err != nil && !errors.Is(err, os.ErrNotExist) {
+		blockSize, err := d.vmDiskFilesSizeBytes(volPath)
+		if err != nil {
 			return err
 		}
 
@@ -352,7 +382,15 @@ func (d *dir) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 }
 
 // GetVolumeDiskPath returns the location of a disk volume.
+// Virtual-machine volumes use a qcow2 disk file if present.
 func (d *dir) GetVolumeDiskPath(vol Volume) (string, error) {
+	if vol.IsVMBlock() {
+		qcow2Path := filepath.Join(vol.MountPath(), genericVolumeQcow2DiskFile)
+		if shared.PathExists(qcow2Path) {
+			return qcow2Path, nil
+		}
+	}
+
 	return genericVFSGetVolumeDiskPath(vol)
 }
 
@@ -404,7 +442,22 @@ func (d *dir) UnmountVolume(vol Volume, keepBlockDev bool, progressReporter iopr
 
 // RenameVolume renames a volume and its snapshots.
 func (d *dir) RenameVolume(vol Volume, newVolName string, progressReporter ioprogress.ProgressReporter) error {
-	return genericVFSRenameVolume(d, vol, newVolName)
+	err := genericVFSRenameVolume(d, vol, newVolName)
+	if err != nil {
+		return err
+	}
+
+	// Snapshots with qcow2 disks are backed by layers in the volume's directory.
+	if vol.IsVMBlock() {
+		newVol := NewVolume(d, d.name, vol.volType, vol.contentType, newVolName, vol.config, vol.poolConfig)
+		err = d.rebaseQcow2Snapshots(newVol, vol.MountPath())
+		if err != nil {
+			_ = genericVFSRenameVolume(d, newVol, vol.name)
+			return err
+		}
+	}
+
+	return nil
 }
 
 // MigrateVolume sends a volume for migration.
@@ -414,7 +467,7 @@ func (d *dir) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *dir) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *dir) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, progressReporter)
 }
 
@@ -438,7 +491,7 @@ func (d *dir) CreateVolumeSnapshot(snapVol Volume, progressReporter ioprogress.P
 		var rsyncArgs []string
 
 		if snapVol.IsVMBlock() {
-			rsyncArgs = append(rsyncArgs, "--exclude", genericVolumeDiskFile)
+			rsyncArgs = append(rsyncArgs, genericVFSDiskFilesRsyncArgs()...)
 		}
 
 		bwlimit := d.config["rsync.bwlimit"]
@@ -459,6 +512,16 @@ func (d *dir) CreateVolumeSnapshot(snapVol Volume, progressReporter ioprogress.P
 			return err
 		}
 
+		if DiskImageFormat(srcDevPath) == DiskImageFormatQcow2 {
+			err = d.createQcow2VolumeSnapshot(parentVol, snapVol, srcDevPath)
+			if err != nil {
+				return err
+			}
+
+			revert.Success()
+			return nil
+		}
+
 		targetDevPath, err := d.GetVolumeDiskPath(snapVol)
 		if err != nil {
 			return err
@@ -500,6 +563,15 @@ func (d *dir) DeleteVolumeSnapshot(snapVol Volume, progressReporter ioprogress.P
 		return err
 	}
 
+	// Clean up the layers the snapshot's qcow2 disk was backed by.
+	if snapVol.IsVMBlock() {
+		parentVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, parentName, nil, d.config)
+		err = d.cleanupQcow2Layers(parentVol)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -583,7 +655,7 @@ func (d *dir) RestoreVolume(vol Volume, snapVol Volume, progressReporter ioprogr
 		var rsyncArgs []string
 
 		if vol.IsVMBlock() {
-			rsyncArgs = append(rsyncArgs, "--exclude", genericVolumeDiskFile)
+			rsyncArgs = append(rsyncArgs, genericVFSDiskFilesRsyncArgs()...)
 		}
 
 		bwlimit := d.config["rsync.bwlimit"]
@@ -605,6 +677,15 @@ func (d *dir) RestoreVolume(vol Volume, snapVol Volume, progressReporter ioprogr
 			return err
 		}
 
+		if DiskImageFormat(targetDevPath) == DiskImageFormatQcow2 {
+			err = d.restoreQcow2Volume(vol, snapVol, targetDevPath)
+			if err != nil {
+				return err
+			}
+
+			return d.cleanupQcow2Layers(vol)
+		}
+
 		d.Logger().Debug("Restoring block volume", logger.Ctx{"srcDevPath": srcDevPath, "targetPath": targetDevPath})
 
 		err = ensureSparseFile(targetDevPath, 0)
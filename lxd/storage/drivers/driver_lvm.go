@@ -41,6 +41,7 @@ func (d *lvm) load() error {
 		"storage_zfs_drop_block_volume_filesystem_extension": nil,
 		"storage_prefix_bucket_names_with_project":           nil,
 		"storage_zfs_remove_local_bucket_datasets":           nil,
+		"storage_create_local_bucket_structure":              nil,
 	}
 
 	// Done if previously loaded.
@@ -95,13 +96,13 @@ func (d *lvm) Info() Info {
 		OptimizedImages:              d.usesThinpool(), // Only thinpool pools support optimized images.
 		PreservesInodes:              false,
 		Remote:                       d.isRemote(),
-		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		VolumeTypes:                  []VolumeType{VolumeTypeBucket, VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
 		BlockBacking:                 true,
 		RunningCopyFreeze:            true,
 		DirectIO:                     true,
 		IOUring:                      true,
 		MountedRoot:                  false,
-		Buckets:                      false,
+		Buckets:                      true,
 		PopulateParentVolumeUUID:     false,
 	}
 }
@@ -832,6 +833,24 @@ func (d *lvm) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetHealth returns the health of the volume group, its physical volumes and its thin pool.
+func (d *lvm) GetHealth() (*api.StoragePoolHealth, error) {
+	pvsOut, err := shared.RunCommand(d.state.ShutdownCtx, "pvs", "--noheadings", "--separator", ",", "-o", "pv_name,vg_name,pv_attr")
+	if err != nil {
+		return nil, err
+	}
+
+	var thinpoolHealth string
+	if d.usesThinpool() {
+		thinpoolHealth, err = shared.RunCommand(d.state.ShutdownCtx, "lvs", "--noheadings", "-o", "lv_health_status", d.config["lvm.vg_name"]+"/"+d.thinpoolName())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return lvmParseHealth(pvsOut, d.config["lvm.vg_name"], thinpoolHealth)
+}
+
 // roundVolumeBlockSizeBytes returns sizeBytes rounded up to the next multiple
 // of the volume group extent size.
 func (d *lvm) roundVolumeBlockSizeBytes(vol Volume, sizeBytes int64) int64 {
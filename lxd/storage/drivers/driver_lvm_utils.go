@@ -301,6 +301,11 @@ func (d *lvm) createLogicalVolume(vgName, thinPoolName string, vol Volume, makeT
 		return err
 	}
 
+	// Reserve space for the LUKS header so that the usable size matches the volume size.
+	if vol.IsEncrypted() {
+		lvSizeBytes += luksHeaderSize
+	}
+
 	lvFullName := d.lvmFullVolumeName(vol.volType, vol.contentType, vol.name)
 
 	args := []string{
@@ -345,15 +350,34 @@ func (d *lvm) createLogicalVolume(vgName, thinPoolName string, vol Volume, makeT
 	}
 
 	volDevPath := d.lvmDevPath(vgName, vol.volType, vol.contentType, vol.name)
+	dataPath := volDevPath
+
+	if vol.IsEncrypted() {
+		key, err := d.volumeEncryptionKey(vol)
+		if err != nil {
+			return err
+		}
+
+		err = luksFormat(volDevPath, key)
+		if err != nil {
+			return err
+		}
+
+		dataPath, err = luksOpen(volDevPath, volDevPath, key, false)
+		if err != nil {
+			return err
+		}
+	}
 
 	if vol.contentType == ContentTypeFS {
-		_, err = makeFSType(volDevPath, vol.ConfigBlockFilesystem(), nil)
+		_, err = makeFSType(dataPath, vol.ConfigBlockFilesystem(), nil)
 		if err != nil {
 			return fmt.Errorf("Error making filesystem on LVM logical volume: %w", err)
 		}
-	} else if !d.usesThinpool() {
+	} else if !d.usesThinpool() || vol.IsEncrypted() {
 		// Make sure we get an empty LV.
-		err := block.ClearBlock(volDevPath, 0)
+		// Encrypted volumes are also cleared as unwritten blocks would otherwise not read as zeroes.
+		err := block.ClearBlock(dataPath, 0)
 		if err != nil {
 			return fmt.Errorf("Error clearing LVM logical volume: %w", err)
 		}
@@ -413,7 +437,13 @@ func (d *lvm) createLogicalVolumeSnapshot(vgName string, srcVol Volume, snapVol
 
 // removeLogicalVolume removes a logical volume.
 func (d *lvm) removeLogicalVolume(volDevPath string) error {
-	_, err := shared.RunCommandRetry(context.TODO(), noKillRetryOpts, "lvremove", "-f", volDevPath)
+	// Lock the volume first in case it is encrypted.
+	err := luksClose(volDevPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = shared.RunCommandRetry(context.TODO(), noKillRetryOpts, "lvremove", "-f", volDevPath)
 	if err != nil {
 		return err
 	}
@@ -425,7 +455,13 @@ func (d *lvm) removeLogicalVolume(volDevPath string) error {
 
 // renameLogicalVolume renames a logical volume.
 func (d *lvm) renameLogicalVolume(volDevPath string, newVolDevPath string) error {
-	_, err := shared.RunCommandRetry(context.TODO(), noKillRetryOpts, "lvrename", volDevPath, newVolDevPath)
+	// Lock the volume first in case it is encrypted, as the unlocked device is named after the volume path.
+	err := luksClose(volDevPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = shared.RunCommandRetry(context.TODO(), noKillRetryOpts, "lvrename", volDevPath, newVolDevPath)
 	if err != nil {
 		return err
 	}
@@ -458,6 +494,58 @@ func (d *lvm) lvmFullVolumeName(volType VolumeType, contentType ContentType, vol
 	return string(volType) + "_" + lvName + contentTypeSuffix
 }
 
+// volumeDataPath returns the path of the device holding the volume's data.
+// For encrypted volumes this is the unlocked LUKS device backed by the logical volume.
+func (d *lvm) volumeDataPath(vol Volume) string {
+	volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
+	if vol.IsEncrypted() {
+		return luksMapperPath(volDevPath)
+	}
+
+	return volDevPath
+}
+
+// openEncryptedVolume unlocks the logical volume of an encrypted volume.
+func (d *lvm) openEncryptedVolume(vol Volume) error {
+	key, err := d.volumeEncryptionKey(vol)
+	if err != nil {
+		return err
+	}
+
+	volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
+	_, err = luksOpen(volDevPath, volDevPath, key, false)
+	return err
+}
+
+// closeEncryptedVolume locks the logical volume of an encrypted volume.
+// For non-thinpool volumes, the parent volume and its snapshots are locked too as they are deactivated together.
+func (d *lvm) closeEncryptedVolume(vol Volume) error {
+	volNames := []string{vol.name}
+
+	if !d.usesThinpool() {
+		parentName, _, _ := api.GetParentAndSnapshotName(vol.name)
+		parentVol := NewVolume(d, d.name, vol.volType, vol.contentType, parentName, vol.config, vol.poolConfig)
+		snapshots, err := d.VolumeSnapshots(parentVol)
+		if err != nil {
+			return err
+		}
+
+		volNames = []string{parentName}
+		for _, snapshot := range snapshots {
+			volNames = append(volNames, GetSnapshotVolumeName(parentName, snapshot))
+		}
+	}
+
+	for _, volName := range volNames {
+		err := luksClose(d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, volName))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // lvmDevPath returns the path to the LVM volume device. Empty string is returned if invalid volType supplied.
 func (d *lvm) lvmDevPath(vgName string, volType VolumeType, contentType ContentType, volName string) string {
 	fullVolName := d.lvmFullVolumeName(volType, contentType, volName)
@@ -607,7 +695,7 @@ func (d *lvm) copyThinpoolVolume(vol, srcVol Volume, srcSnapshots []string, refr
 			}
 
 			d.logger.Debug("Regenerating filesystem UUID", logger.Ctx{"dev": volDevPath, "fs": vol.ConfigBlockFilesystem()})
-			err = regenerateFilesystemUUID(vol.ConfigBlockFilesystem(), volDevPath)
+			err = regenerateFilesystemUUID(vol.ConfigBlockFilesystem(), d.volumeDataPath(vol))
 			if err != nil {
 				return err
 			}
@@ -785,6 +873,7 @@ func (d *lvm) activateVolume(vol Volume) (bool, error) {
 		volDevPath = d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, parent)
 	}
 
+	activated := false
 	if !shared.PathExists(volDevPath) {
 		_, err := shared.RunCommand(context.TODO(), "lvchange", "--activate", "y", "--ignoreactivationskip", volDevPath)
 		if err != nil {
@@ -792,13 +881,23 @@ func (d *lvm) activateVolume(vol Volume) (bool, error) {
 		}
 
 		d.logger.Debug("Activated logical volume", logger.Ctx{"volName": vol.Name(), "dev": volDevPath})
+		activated = true
+	}
 
-		d.activationRefCountIncrement(vol)
-		return true, nil
+	// Unlock encrypted volumes.
+	if vol.IsEncrypted() {
+		err := d.openEncryptedVolume(vol)
+		if err != nil {
+			if activated {
+				_, _ = shared.RunCommand(context.TODO(), "lvchange", "--activate", "n", "--ignoreactivationskip", volDevPath)
+			}
+
+			return false, err
+		}
 	}
 
 	d.activationRefCountIncrement(vol)
-	return false, nil
+	return activated, nil
 }
 
 // deactivateVolume deactivates an LVM logical volume if present. Returns true if deactivated, false if not.
@@ -824,6 +923,14 @@ func (d *lvm) deactivateVolume(vol Volume) (bool, error) {
 	}
 
 	if shared.PathExists(volDevPath) {
+		// Lock encrypted volumes before deactivating them.
+		if vol.IsEncrypted() {
+			err := d.closeEncryptedVolume(vol)
+			if err != nil {
+				return false, err
+			}
+		}
+
 		// Keep trying to deactivate a few times in case the device is still being flushed.
 		var err error
 		for i := range 20 {
@@ -846,3 +953,52 @@ func (d *lvm) deactivateVolume(vol Volume) (bool, error) {
 
 	return false, nil
 }
+
+// lvmParseHealth parses the output of "pvs -o pv_name,vg_name,pv_attr" and the health status of the thin pool
+// (if any) and returns the health of the volume group.
+func lvmParseHealth(pvsOut string, vgName string, thinpoolHealth string) (*api.StoragePoolHealth, error) {
+	health := &api.StoragePoolHealth{
+		State:   api.StoragePoolHealthStateHealthy,
+		Devices: []api.StoragePoolHealthDevice{},
+	}
+
+	for line := range strings.SplitSeq(pvsOut, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("Unexpected output from pvs command: %q", line)
+		}
+
+		if fields[1] != vgName {
+			continue
+		}
+
+		device := api.StoragePoolHealthDevice{Name: fields[0], State: "ok"}
+
+		// The missing attribute is set when the physical volume can't be found.
+		if strings.Contains(fields[2], "m") {
+			device.State = "missing"
+			health.State = api.StoragePoolHealthStateDegraded
+			health.Message = "One or more physical volumes are missing"
+		}
+
+		health.Devices = append(health.Devices, device)
+	}
+
+	thinpoolHealth = strings.TrimSpace(thinpoolHealth)
+	if thinpoolHealth != "" {
+		if thinpoolHealth == "failed" {
+			health.State = api.StoragePoolHealthStateUnavailable
+		} else if health.State == api.StoragePoolHealthStateHealthy {
+			health.State = api.StoragePoolHealthStateDegraded
+		}
+
+		health.Message = fmt.Sprintf("Thin pool health is %q", thinpoolHealth)
+	}
+
+	return health, nil
+}
@@ -146,7 +146,8 @@ func (d *lvm) CreateVolumeFromCopy(vol VolumeCopy, srcVol VolumeCopy, allowIncon
 	}
 
 	// We can use optimised copying when the pool is backed by an LVM thinpool.
-	if d.usesThinpool() {
+	// Encrypted volumes can only be copied this way if they share the same key.
+	if d.usesThinpool() && sameEncryptionKey(vol.Volume, srcVol.Volume) {
 		err = d.copyThinpoolVolume(vol.Volume, srcVol.Volume, srcSnapshots, false)
 		if err != nil {
 			return err
@@ -181,7 +182,8 @@ func (d *lvm) EnsureImage(imgVol Volume, filler *VolumeFiller, progressReporter
 // RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
 func (d *lvm) RefreshVolume(vol VolumeCopy, srcVol VolumeCopy, refreshSnapshots []string, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error {
 	// We can use optimised copying when the pool is backed by an LVM thinpool.
-	if d.usesThinpool() {
+	// Encrypted volumes can only be copied this way if they share the same key.
+	if d.usesThinpool() && sameEncryptionKey(vol.Volume, srcVol.Volume) {
 		return d.copyThinpoolVolume(vol.Volume, srcVol.Volume, refreshSnapshots, true)
 	}
 
@@ -262,11 +264,17 @@ func (d *lvm) FillVolumeConfig(vol Volume) error {
 	// Copy volume.* configuration options from pool.
 	// Exclude "block.filesystem" and "block.mount_options" as they depend on volume type (handled below).
 	// Exclude "lvm.stripes", "lvm.stripes.size" as they only work on non-thin storage pools (handled below).
-	err := d.fillVolumeConfig(&vol, "block.filesystem", "block.mount_options", "lvm.stripes", "lvm.stripes.size")
+	// Exclude "security.encrypted" as it only applies to instance and custom volumes (handled below).
+	err := d.fillVolumeConfig(&vol, "block.filesystem", "block.mount_options", "lvm.stripes", "lvm.stripes.size", "security.encrypted")
 	if err != nil {
 		return err
 	}
 
+	// Inherit encryption from pool if not set.
+	if vol.config["security.encrypted"] == "" && d.config["volume.security.encrypted"] != "" && encryptableVolume(vol) {
+		vol.config["security.encrypted"] = d.config["volume.security.encrypted"]
+	}
+
 	// Only validate filesystem config keys for filesystem volumes or VM block volumes (which have an
 	// associated filesystem volume).
 	if vol.ContentType() == ContentTypeFS || vol.IsVMBlock() {
@@ -333,6 +341,18 @@ func (d *lvm) commonVolumeRules() map[string]func(value string) error {
 		//  shortdesc: Size of stripes to use
 		//  scope: global
 		"lvm.stripes.size": validate.Optional(validate.IsSize),
+		// lxdmeta:generate(entities=storage-lvm; group=volume-conf; key=security.encrypted)
+		// Enable this option to store the volume encrypted with LUKS2.
+		// LXD generates the encryption key when creating the volume and unlocks the volume automatically when using it.
+		// This option cannot be changed after the volume is created.
+		// See {ref}`storage-lvm-encryption` for more information.
+		// ---
+		//  type: bool
+		//  condition: instance or custom volume
+		//  defaultdesc: same as `volume.security.encrypted` or `false`
+		//  shortdesc: Whether to encrypt the volume
+		//  scope: global
+		"security.encrypted": validate.Optional(validate.IsBool),
 	}
 }
 
@@ -349,11 +369,24 @@ func (d *lvm) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "block.mount_options")
 	}
 
+	// lxdmeta:generate(entities=storage-lvm; group=volume-conf; key=volatile.encryption.key)
+	// The key is wrapped with a key that is specific to each server.
+	// ---
+	//  type: string
+	//  condition: encrypted volume
+	//  shortdesc: Encryption key of the volume
+	//  scope: global
+	commonRules["volatile.encryption.key"] = validate.IsAny
+
 	err := d.validateVolume(vol, commonRules, removeUnknownKeys)
 	if err != nil {
 		return err
 	}
 
+	if vol.IsEncrypted() && !encryptableVolume(vol) {
+		return errors.New("security.encrypted can only be used with instance and custom volumes")
+	}
+
 	if d.usesThinpool() && vol.config["lvm.stripes"] != "" {
 		return errors.New("lvm.stripes cannot be used with thin pool volumes")
 	}
@@ -435,6 +468,13 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 		return err
 	}
 
+	// The data of encrypted volumes is stored after the LUKS header.
+	var dataOffset int64
+	if vol.IsEncrypted() {
+		dataOffset = luksHeaderSize
+		sizeBytes += dataOffset
+	}
+
 	// Read actual size of current volume.
 	volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
 	oldSizeBytes, err := d.logicalVolumeSize(volDevPath)
@@ -462,6 +502,21 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 	l := d.logger.AddContext(logger.Ctx{"dev": volDevPath, "size": strconv.FormatInt(sizeBytes, 10) + "b"})
 
 	inUse := vol.MountInUse()
+	dataPath := d.volumeDataPath(vol)
+
+	// resizeEncryptedVolume resizes the unlocked device of encrypted volumes to match the logical volume.
+	resizeEncryptedVolume := func() error {
+		if !vol.IsEncrypted() {
+			return nil
+		}
+
+		key, err := d.volumeEncryptionKey(vol)
+		if err != nil {
+			return err
+		}
+
+		return luksResize(volDevPath, key)
+	}
 
 	// Resize filesystem if needed.
 	if vol.contentType == ContentTypeFS {
@@ -494,7 +549,7 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 			// so that we can have more control over when we trigger unsafe filesystem resize mode,
 			// otherwise by passing -f to lvresize (required for other reasons) this would then pass
 			// -f onto resize2fs as well.
-			err = shrinkFileSystem(fsType, volDevPath, vol, sizeBytes, allowUnsafeResize)
+			err = shrinkFileSystem(fsType, dataPath, vol, sizeBytes-dataOffset, allowUnsafeResize)
 			if err != nil {
 				_, _ = d.deactivateVolume(vol)
 				return err
@@ -532,8 +587,13 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 				}()
 			}
 
+			err = resizeEncryptedVolume()
+			if err != nil {
+				return err
+			}
+
 			// Grow the filesystem to fill block device.
-			err = growFileSystem(fsType, volDevPath, vol)
+			err = growFileSystem(fsType, dataPath, vol)
 			if err != nil {
 				return err
 			}
@@ -558,8 +618,13 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 			return err
 		}
 
-		// The new blocks in a grown volume will need clearing if using a thick pool.
-		needsClearing := !d.usesThinpool() && (oldSizeBytes < sizeBytes)
+		err = resizeEncryptedVolume()
+		if err != nil {
+			return err
+		}
+
+		// The new blocks in a grown volume will need clearing if using a thick pool or encryption.
+		needsClearing := (!d.usesThinpool() || vol.IsEncrypted()) && (oldSizeBytes < sizeBytes)
 
 		// VM block volumes need the GPT header moved on normal resize scenarios.
 		needsGPTHeaderMove := vol.IsVMBlock() && !allowUnsafeResize
@@ -584,7 +649,7 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 		// On thick pools, discard the blocks in the additional space when the volume is grown.
 		if needsClearing {
 			// Discard blocks from the end of the old volume's size.
-			err := block.ClearBlock(volDevPath, oldSizeBytes)
+			err := block.ClearBlock(dataPath, oldSizeBytes-dataOffset)
 			if err != nil {
 				return err
 			}
@@ -594,7 +659,7 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 		// expected the caller will do all necessary post resize actions themselves).
 		// Do this after the new blocks have been cleared.
 		if needsGPTHeaderMove {
-			err = d.moveGPTAltHeader(volDevPath)
+			err = d.moveGPTAltHeader(dataPath)
 			if err != nil {
 				return err
 			}
@@ -607,8 +672,7 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 // GetVolumeDiskPath returns the location of a disk volume.
 func (d *lvm) GetVolumeDiskPath(vol Volume) (string, error) {
 	if vol.IsVMBlock() || (vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
-		volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
-		return volDevPath, nil
+		return d.volumeDataPath(vol), nil
 	}
 
 	return "", ErrNotSupported
@@ -748,7 +812,7 @@ func (d *lvm) mountCommon(vol Volume, progressReporter ioprogress.ProgressReport
 		// Default to mounting the original snapshot directly. This may be changed below if a temporary
 		// snapshot needs to be taken.
 		mountVol := vol
-		volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], mountVol.volType, mountVol.contentType, mountVol.name)
+		volDevPath := d.volumeDataPath(mountVol)
 		mountFlags, mountOptions := filesystem.ResolveMountOptions(strings.Split(mountVol.ConfigBlockMountOptions(), ","))
 		isSnapshot := vol.IsSnapshot()
 
@@ -783,7 +847,15 @@ func (d *lvm) mountCommon(vol Volume, progressReporter ioprogress.ProgressReport
 
 			// We are going to mount the temporary volume instead.
 			mountVol = tmpVol
-			volDevPath = d.lvmDevPath(d.config["lvm.vg_name"], mountVol.volType, mountVol.contentType, mountVol.name)
+			volDevPath = d.volumeDataPath(mountVol)
+
+			// Unlock the temporary volume so its filesystem can be modified.
+			if mountVol.IsEncrypted() {
+				err = d.openEncryptedVolume(mountVol)
+				if err != nil {
+					return err
+				}
+			}
 
 			tmpVolFsType := mountVol.ConfigBlockFilesystem()
 			mountOptions = addNoRecoveryMountOption(mountOptions, tmpVolFsType)
@@ -886,8 +958,16 @@ func (d *lvm) unmountCommon(vol Volume, keepBlockDev bool, progressReporter iopr
 
 	// Check if already mounted.
 	if vol.contentType == ContentTypeFS && filesystem.IsMountPoint(mountPath) {
+		err = TryUnmount(mountPath, 0)
+		if err != nil {
+			return false, fmt.Errorf("Failed unmounting LVM logical volume: %w", err)
+		}
+
+		d.logger.Debug("Unmounted logical volume", logger.Ctx{"volName": vol.name, "path": mountPath, "keepBlockDev": keepBlockDev})
+
 		if vol.IsSnapshot() {
 			// Check if a temporary snapshot exists, and if so remove it.
+			// This is done after unmounting as the temporary snapshot may be the mounted volume.
 			tmpVolName := vol.name + tmpVolSuffix
 			tmpVolDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, tmpVolName)
 			exists, err := d.logicalVolumeExists(tmpVolDevPath)
@@ -903,13 +983,6 @@ func (d *lvm) unmountCommon(vol Volume, keepBlockDev bool, progressReporter iopr
 			}
 		}
 
-		err = TryUnmount(mountPath, 0)
-		if err != nil {
-			return false, fmt.Errorf("Failed unmounting LVM logical volume: %w", err)
-		}
-
-		d.logger.Debug("Unmounted logical volume", logger.Ctx{"volName": vol.name, "path": mountPath, "keepBlockDev": keepBlockDev})
-
 		ourUnmount = true
 	} else if IsContentBlock(vol.contentType) {
 		volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
@@ -1024,7 +1097,7 @@ func (d *lvm) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *lvm) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *lvm) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, _ bool, snapshots []string, _ string, progressReporter ioprogress.ProgressReporter) error {
 	return genericVFSBackupVolume(d, vol, tarWriter, snapshots, progressReporter)
 }
 
@@ -1243,7 +1316,7 @@ func (d *lvm) RestoreVolume(vol Volume, snapVol Volume, progressReporter ioprogr
 			}
 
 			d.logger.Debug("Regenerating filesystem UUID", logger.Ctx{"dev": volDevPath, "fs": restoreVol.ConfigBlockFilesystem()})
-			err = regenerateFilesystemUUID(restoreVol.ConfigBlockFilesystem(), volDevPath)
+			err = regenerateFilesystemUUID(restoreVol.ConfigBlockFilesystem(), d.volumeDataPath(restoreVol))
 			if err != nil {
 				return nil, err
 			}
@@ -79,6 +79,16 @@ func (d *mock) GetResources() (*api.ResourcesStoragePool, error) {
 	return nil, nil
 }
 
+// GetHealth returns the health of the pool and the devices backing it.
+func (d *mock) GetHealth() (*api.StoragePoolHealth, error) {
+	return &api.StoragePoolHealth{State: api.StoragePoolHealthStateHealthy}, nil
+}
+
+// Scrub starts verifying the integrity of the data stored in the pool.
+func (d *mock) Scrub() error {
+	return nil
+}
+
 // CreateVolume creates an empty volume and can optionally fill it by executing the supplied filler function.
 func (d *mock) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter ioprogress.ProgressReporter) error {
 	return nil
@@ -180,7 +190,7 @@ func (d *mock) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs
 
 // BackupVolume copies a volume (and optionally its snapshots) to a specified target path.
 // This driver does not support optimized backups.
-func (d *mock) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *mock) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	return nil
 }
 
@@ -215,6 +225,11 @@ func (d *mock) RestoreVolume(vol Volume, snapVol Volume, progressReporter ioprog
 	return nil
 }
 
+// DiffVolumeSnapshot returns the paths which differ between a volume snapshot and a later snapshot or the volume itself.
+func (d *mock) DiffVolumeSnapshot(snapVol Volume, vol Volume, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	return nil, nil
+}
+
 // RenameVolumeSnapshot renames a volume snapshot.
 func (d *mock) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, progressReporter ioprogress.ProgressReporter) error {
 	return nil
@@ -0,0 +1,273 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/canonical/lxd/lxd/migration"
+	"github.com/canonical/lxd/lxd/storage/filesystem"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/ioprogress"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+var nfsVersion string
+var nfsLoaded bool
+
+// nfs uses the same on-disk layout as the dir driver, but on top of an NFS export mounted on each member.
+type nfs struct {
+	dir
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *nfs) load() error {
+	// Register the patches.
+	d.patches = map[string]func() error{
+		"storage_lvm_skipactivation":                         nil,
+		"storage_missing_snapshot_records":                   nil,
+		"storage_delete_old_snapshot_records":                nil,
+		"storage_zfs_drop_block_volume_filesystem_extension": nil,
+		"storage_prefix_bucket_names_with_project":           nil,
+		"storage_zfs_remove_local_bucket_datasets":           nil,
+		"storage_create_local_bucket_structure":              nil,
+	}
+
+	// Done if previously loaded.
+	if nfsLoaded {
+		return nil
+	}
+
+	// Validate the required binaries.
+	for _, tool := range []string{"mount.nfs", "cp"} {
+		_, err := exec.LookPath(tool)
+		if err != nil {
+			return fmt.Errorf("Required tool %q is missing", tool)
+		}
+	}
+
+	// Detect and record the version.
+	if nfsVersion == "" {
+		ver, err := nfsUtilsVersion()
+		if err != nil {
+			return err
+		}
+
+		nfsVersion = ver
+	}
+
+	nfsLoaded = true
+	return nil
+}
+
+// isRemote returns true indicating this driver uses remote storage.
+func (d *nfs) isRemote() bool {
+	return true
+}
+
+// Info returns the pool driver information.
+func (d *nfs) Info() Info {
+	return Info{
+		Name:                         "nfs",
+		Version:                      nfsVersion,
+		DefaultBlockSize:             d.defaultBlockVolumeSize(),
+		DefaultVMBlockFilesystemSize: d.defaultVMBlockFilesystemSize(),
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom},
+		VolumeMultiNode:              true,
+		BlockBacking:                 false,
+		RunningCopyFreeze:            false,
+		DirectIO:                     true,
+		MountedRoot:                  true,
+		PopulateParentVolumeUUID:     false,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *nfs) FillConfig() error {
+	return nil
+}
+
+// SourceIdentifier returns the NFS export used by the pool.
+func (d *nfs) SourceIdentifier() (string, error) {
+	export := d.config["nfs.export"]
+	if export == "" {
+		return "", errors.New("Cannot derive identifier from empty export")
+	}
+
+	return export, nil
+}
+
+// ValidateSource checks whether the required config keys are set to access the remote source.
+func (d *nfs) ValidateSource() error {
+	if d.config["nfs.export"] == "" {
+		return errors.New("Missing required NFS export")
+	}
+
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *nfs) Create() error {
+	// Create a temporary mountpoint.
+	mountPath, err := os.MkdirTemp("", "lxd_nfs_")
+	if err != nil {
+		return fmt.Errorf("Failed creating temporary directory under: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(mountPath) }()
+
+	err = os.Chmod(mountPath, 0700)
+	if err != nil {
+		return fmt.Errorf("Failed chmoding %q: %w", mountPath, err)
+	}
+
+	mountPoint := filepath.Join(mountPath, "mount")
+
+	err = os.Mkdir(mountPoint, 0700)
+	if err != nil {
+		return fmt.Errorf("Failed creating directory %q: %w", mountPoint, err)
+	}
+
+	// Mount the export to check that it is reachable and unused.
+	err = d.mountExport(mountPoint)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = forceUnmount(mountPoint) }()
+
+	// Check that the export is empty, the "lost+found" directory of an exported file system root is acceptable.
+	entries, err := os.ReadDir(mountPoint)
+	if err != nil {
+		return fmt.Errorf("Failed reading content of NFS export %q: %w", d.config["nfs.export"], err)
+	}
+
+	for _, e := range entries {
+		if e.Name() != "lost+found" {
+			return errors.New("Only empty NFS exports can be used as a LXD storage pool")
+		}
+	}
+
+	return nil
+}
+
+// Delete clears any local and remote data related to this driver instance.
+func (d *nfs) Delete(progressReporter ioprogress.ProgressReporter) error {
+	// Ensure the export is mounted so its content can be wiped.
+	_, err := d.Mount()
+	if err != nil {
+		return err
+	}
+
+	// On delete, wipe everything in the directory.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	// Make sure the existing pool is unmounted.
+	_, err = d.Unmount()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *nfs) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-nfs; group=pool-conf; key=nfs.export)
+		// The export must be specified as `<server>:<path>`, for example `nfs.example.com:/srv/lxd`.
+		// Every cluster member mounts the same export.
+		// ---
+		//  type: string
+		//  shortdesc: NFS export to use for the storage pool
+		//  scope: global
+		"nfs.export": validate.Optional(validateNFSExport),
+		// lxdmeta:generate(entities=storage-nfs; group=pool-conf; key=nfs.mount_options)
+		// Comma-separated list of options passed to `mount.nfs`, for example `vers=4.2,hard`.
+		// ---
+		//  type: string
+		//  defaultdesc: `nfs-utils` defaults
+		//  shortdesc: Mount options for the NFS export
+		//  scope: global
+		"nfs.mount_options": validate.IsAny,
+	}
+
+	// This overrides the common driver rule for security.shared.
+	volumeRules := map[string]func(value string) error{
+		"security.shared": func(value string) error {
+			if value != "" {
+				return errors.New(`Setting "security.shared" is not allowed for nfs as it does not support block volumes`)
+			}
+
+			return nil
+		},
+	}
+
+	return d.validatePool(config, rules, volumeRules)
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *nfs) Update(changedConfig map[string]string) error {
+	_, changed := changedConfig["nfs.export"]
+	if changed {
+		return errors.New("NFS export cannot be changed")
+	}
+
+	return nil
+}
+
+// Mount brings up the driver and sets it up to be used.
+func (d *nfs) Mount() (bool, error) {
+	path := GetPoolMountPath(d.name)
+
+	// Check if already mounted.
+	if filesystem.IsMountPoint(path) {
+		return false, nil
+	}
+
+	err := d.mountExport(path)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unmount clears any of the runtime state of the driver.
+func (d *nfs) Unmount() (bool, error) {
+	return forceUnmount(GetPoolMountPath(d.name))
+}
+
+// MigrationTypes returns the supported migration types and options supported by the driver.
+func (d *nfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
+	var rsyncFeatures []string
+
+	// Do not pass compression argument to rsync if the associated
+	// config key, that is rsync.compression, is set to false.
+	if shared.IsFalse(d.Config()["rsync.compression"]) {
+		rsyncFeatures = []string{"delete", "bidirectional"}
+	} else {
+		rsyncFeatures = []string{"delete", "compress", "bidirectional"}
+	}
+
+	if contentType != ContentTypeFS {
+		return nil
+	}
+
+	// Do not support xattr transfer as most NFS servers don't support them.
+	return []migration.Type{
+		{
+			FSType:   migration.MigrationFSType_RSYNC,
+			Features: rsyncFeatures,
+		},
+	}
+}
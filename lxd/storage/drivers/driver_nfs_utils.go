@@ -0,0 +1,82 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+)
+
+// nfsUtilsVersion returns the version of the installed nfs-utils.
+func nfsUtilsVersion() (string, error) {
+	// Output is of the form "mount.nfs: (linux nfs-utils 2.6.4)".
+	out, err := shared.RunCommand(context.TODO(), "mount.nfs", "-V")
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(strings.TrimSpace(out))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("Failed parsing nfs-utils version %q", out)
+	}
+
+	return strings.TrimSuffix(fields[len(fields)-1], ")"), nil
+}
+
+// withoutGetVolID returns a copy of this struct but with a volIDFunc which will cause quotas to be skipped.
+func (d *nfs) withoutGetVolID() Driver {
+	newDriver := &nfs{}
+	getVolID := func(volType VolumeType, volName string) (int64, error) { return volIDQuotaSkip, nil }
+	newDriver.init(d.state, d.name, d.config, d.logger, getVolID, d.commonRules)
+	_ = newDriver.load()
+
+	return newDriver
+}
+
+// mountExport mounts the pool's NFS export on the given path.
+// The mount is done through mount.nfs as the kernel doesn't resolve the server's address on its own.
+func (d *nfs) mountExport(path string) error {
+	args := []string{"-t", "nfs"}
+
+	options := d.config["nfs.mount_options"]
+	if options != "" {
+		args = append(args, "-o", options)
+	}
+
+	args = append(args, d.config["nfs.export"], path)
+
+	// Unreachable servers would otherwise block for minutes while mount.nfs keeps retrying.
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+
+	_, err := shared.RunCommand(ctx, "mount", args...)
+	if err != nil {
+		return fmt.Errorf("Failed mounting NFS export %q on %q: %w", d.config["nfs.export"], path, err)
+	}
+
+	return nil
+}
+
+// copyDirectory copies the content of srcPath into dstPath.
+// Files are cloned server side when both the client and the NFS server support it (NFS 4.2),
+// otherwise they are fully copied.
+func (d *nfs) copyDirectory(srcPath string, dstPath string) error {
+	_, err := shared.RunCommand(context.TODO(), "cp", "-a", "--reflink=auto", shared.AddSlash(srcPath)+".", dstPath)
+	if err != nil {
+		return fmt.Errorf("Failed copying %q to %q: %w", srcPath, dstPath, err)
+	}
+
+	return nil
+}
+
+// validateNFSExport checks that the value is an NFS export in the form "<server>:<path>".
+func validateNFSExport(value string) error {
+	server, _, ok := strings.Cut(value, ":/")
+	if !ok || server == "" {
+		return fmt.Errorf("NFS export %q must be in the form <server>:<path>", value)
+	}
+
+	return nil
+}
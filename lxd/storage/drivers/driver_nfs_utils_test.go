@@ -0,0 +1,17 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNFSExport(t *testing.T) {
+	for _, value := range []string{"nfs.example.com:/srv/lxd", "192.0.2.10:/", "[2001:db8::10]:/srv/lxd"} {
+		assert.NoError(t, validateNFSExport(value), value)
+	}
+
+	for _, value := range []string{"/srv/lxd", "nfs.example.com", "nfs.example.com:srv/lxd", ":/srv/lxd"} {
+		assert.Error(t, validateNFSExport(value), value)
+	}
+}
@@ -0,0 +1,169 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/canonical/lxd/lxd/backup"
+	"github.com/canonical/lxd/lxd/migration"
+	"github.com/canonical/lxd/lxd/rsync"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/ioprogress"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+)
+
+// CreateVolume creates an empty volume and can optionally fill it by executing the supplied
+// filler function.
+func (d *nfs) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter ioprogress.ProgressReporter) error {
+	if vol.volType != VolumeTypeCustom || vol.contentType != ContentTypeFS {
+		return ErrNotSupported
+	}
+
+	return d.dir.CreateVolume(vol, filler, progressReporter)
+}
+
+// CreateVolumeFromBackup restores a backup tarball onto the storage device.
+func (d *nfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcData io.ReadSeeker, progressReporter ioprogress.ProgressReporter) (VolumePostHook, revert.Hook, error) {
+	return genericVFSBackupUnpack(d.withoutGetVolID(), d.state, vol, srcBackup.Snapshots, srcData, progressReporter)
+}
+
+// CreateVolumeFromCopy provides same-pool volume copying functionality.
+// As volumes and their snapshots are plain directories on the export, they are copied directly
+// which lets the NFS server clone the files rather than transferring their data through the client.
+func (d *nfs) CreateVolumeFromCopy(vol VolumeCopy, srcVol VolumeCopy, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error {
+	if vol.contentType != srcVol.contentType {
+		return errors.New("Content type of source and target must be the same")
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	err := d.CreateVolume(vol.Volume, nil, progressReporter)
+	if err != nil {
+		return err
+	}
+
+	revert.Add(func() { _ = d.DeleteVolume(vol.Volume, progressReporter) })
+
+	// If copying snapshots is indicated, check the source isn't itself a snapshot.
+	if len(vol.Snapshots) > 0 && !srcVol.IsSnapshot() {
+		for _, snapVol := range vol.Snapshots {
+			_, snapName, _ := api.GetParentAndSnapshotName(snapVol.name)
+			srcSnapPath := GetVolumeMountPath(d.name, srcVol.volType, GetSnapshotVolumeName(srcVol.name, snapName))
+
+			err = snapVol.EnsureMountPath()
+			if err != nil {
+				return err
+			}
+
+			revert.Add(func() { _ = d.DeleteVolumeSnapshot(snapVol, progressReporter) })
+
+			d.Logger().Debug("Copying snapshot", logger.Ctx{"sourcePath": srcSnapPath, "targetPath": snapVol.MountPath()})
+			err = d.copyDirectory(srcSnapPath, snapVol.MountPath())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	d.Logger().Debug("Copying volume", logger.Ctx{"sourcePath": srcVol.MountPath(), "targetPath": vol.MountPath()})
+	err = d.copyDirectory(srcVol.MountPath(), vol.MountPath())
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// CreateVolumeFromMigration creates a volume being sent via a migration.
+func (d *nfs) CreateVolumeFromMigration(vol VolumeCopy, conn io.ReadWriteCloser, volTargetArgs migration.VolumeTargetArgs, preFiller *VolumeFiller, progressReporter ioprogress.ProgressReporter) error {
+	if volTargetArgs.MigrationType.FSType != migration.MigrationFSType_RSYNC {
+		return ErrNotSupported
+	}
+
+	_, err := genericVFSCreateVolumeFromMigration(d, d.setupInitialQuota, vol, conn, volTargetArgs, preFiller, progressReporter)
+	return err
+}
+
+// RefreshVolume provides same-pool volume and specific snapshots syncing functionality.
+func (d *nfs) RefreshVolume(vol VolumeCopy, srcVol VolumeCopy, refreshSnapshots []string, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error {
+	_, err := genericVFSCopyVolume(d, d.setupInitialQuota, vol, srcVol, refreshSnapshots, true, allowInconsistent, progressReporter)
+	return err
+}
+
+// ListVolumes returns a list of LXD volumes in storage pool.
+func (d *nfs) ListVolumes() ([]Volume, error) {
+	return genericVFSListVolumes(d)
+}
+
+// RenameVolume renames a volume and its snapshots.
+func (d *nfs) RenameVolume(vol Volume, newVolName string, progressReporter ioprogress.ProgressReporter) error {
+	return genericVFSRenameVolume(d, vol, newVolName)
+}
+
+// MigrateVolume sends a volume for migration.
+func (d *nfs) MigrateVolume(vol VolumeCopy, conn io.ReadWriteCloser, volSrcArgs *migration.VolumeSourceArgs, progressReporter ioprogress.ProgressReporter) error {
+	return genericVFSMigrateVolume(d, d.state, vol, conn, volSrcArgs, progressReporter)
+}
+
+// CreateVolumeSnapshot creates a snapshot of a volume.
+// The snapshot is a copy of the volume, cloned server side when supported by the NFS server.
+func (d *nfs) CreateVolumeSnapshot(snapVol Volume, progressReporter ioprogress.ProgressReporter) error {
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+
+	// Create snapshot directory.
+	err := snapVol.EnsureMountPath()
+	if err != nil {
+		return err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	snapPath := snapVol.MountPath()
+	revert.Add(func() { _ = os.RemoveAll(snapPath) })
+
+	srcPath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
+	d.Logger().Debug("Copying filesystem volume", logger.Ctx{"sourcePath": srcPath, "targetPath": snapPath})
+
+	err = d.copyDirectory(srcPath, snapPath)
+	if err != nil {
+		return err
+	}
+
+	revert.Success()
+	return nil
+}
+
+// RestoreVolume restores a volume from a snapshot.
+func (d *nfs) RestoreVolume(vol Volume, snapVol Volume, progressReporter ioprogress.ProgressReporter) error {
+	_, snapshotName, _ := api.GetParentAndSnapshotName(snapVol.name)
+	snapVol, err := vol.NewSnapshot(snapshotName)
+	if err != nil {
+		return err
+	}
+
+	srcPath := snapVol.MountPath()
+	if !shared.PathExists(srcPath) {
+		return errors.New("Snapshot not found")
+	}
+
+	// Restore using rsync, without xattrs as most NFS servers don't support them.
+	bwlimit := d.config["rsync.bwlimit"]
+	output, err := rsync.LocalCopy(srcPath, vol.MountPath(), bwlimit, false)
+	if err != nil {
+		return fmt.Errorf("Failed rsyncing volume: %s: %w", output, err)
+	}
+
+	return nil
+}
+
+// RenameVolumeSnapshot renames a volume snapshot.
+func (d *nfs) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, progressReporter ioprogress.ProgressReporter) error {
+	return genericVFSRenameVolumeSnapshot(d, snapVol, newSnapshotName, progressReporter)
+}
@@ -35,6 +35,10 @@ type Info struct {
 	// Whether driver generates an optimised backup header file in backup.
 	OptimizedBackupHeader bool
 
+	// Whether driver supports incremental optimized volume backups.
+	// This requires restored snapshots to be usable as the base of the next incremental stream.
+	IncrementalBackups bool
+
 	// Whether driver preserves inodes when volumes are moved hosts.
 	PreservesInodes bool
 
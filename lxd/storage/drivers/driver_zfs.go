@@ -55,6 +55,7 @@ func (d *zfs) load() error {
 		"storage_zfs_drop_block_volume_filesystem_extension": d.patchDropBlockVolumeFilesystemExtension,
 		"storage_prefix_bucket_names_with_project":           nil,
 		"storage_zfs_remove_local_bucket_datasets":           d.patchRemoveLocalBucketDatasets,
+		"storage_create_local_bucket_structure":              d.patchCreateLocalBucketDatasets,
 	}
 
 	// Done if previously loaded.
@@ -143,14 +144,15 @@ func (d *zfs) Info() Info {
 		DefaultVMBlockFilesystemSize: d.defaultVMBlockFilesystemSize(),
 		OptimizedImages:              true,
 		OptimizedBackups:             true,
+		IncrementalBackups:           true,
 		PreservesInodes:              true,
 		Remote:                       d.isRemote(),
-		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
+		VolumeTypes:                  []VolumeType{VolumeTypeBucket, VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer, VolumeTypeVM},
 		BlockBacking:                 shared.IsTrue(d.config["volume.zfs.block_mode"]),
 		RunningCopyFreeze:            false,
 		DirectIO:                     zfsDirectIO,
 		MountedRoot:                  false,
-		Buckets:                      false,
+		Buckets:                      true,
 		PopulateParentVolumeUUID:     false,
 	}
 
@@ -518,6 +520,9 @@ func (d *zfs) Validate(config map[string]string) error {
 	// Append common local pool rules.
 	maps.Insert(rules, maps.All(d.commonRules.LocalPoolRules()))
 
+	// Append scrub rules.
+	maps.Insert(rules, maps.All(scrubPoolRules()))
+
 	return d.validatePool(config, rules, d.commonVolumeRules())
 }
 
@@ -721,6 +726,30 @@ func (d *zfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetHealth returns the health of the zpool and the devices backing it.
+func (d *zfs) GetHealth() (*api.StoragePoolHealth, error) {
+	poolName, _, _ := strings.Cut(d.config["zfs.pool_name"], "/")
+
+	out, err := shared.RunCommand(context.TODO(), "zpool", "status", "-p", poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return zpoolParseStatus(out)
+}
+
+// Scrub starts a scrub of the zpool.
+func (d *zfs) Scrub() error {
+	poolName, _, _ := strings.Cut(d.config["zfs.pool_name"], "/")
+
+	_, err := shared.RunCommand(context.TODO(), "zpool", "scrub", poolName)
+	if err != nil {
+		return fmt.Errorf("Failed starting scrub of zpool %q: %w", poolName, err)
+	}
+
+	return nil
+}
+
 // MigrationTypes returns the type of transfer methods to be used when doing
 // migrations between pools in preference order.
 func (d *zfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool) []migration.Type {
@@ -836,6 +865,12 @@ func (d *zfs) patchRemoveLocalBucketDatasets() error {
 	return nil
 }
 
+// patchCreateLocalBucketDatasets recreates the "<pool>/buckets" and "<pool>/deleted/buckets" datasets removed by
+// patchRemoveLocalBucketDatasets, for use by the buckets served by the embedded S3 gateway.
+func (d *zfs) patchCreateLocalBucketDatasets() error {
+	return d.ensureInitialDatasets(false)
+}
+
 // roundVolumeBlockSizeBytes returns sizeBytes rounded up to the next multiple
 // of `vol`'s "zfs.blocksize".
 func (d *zfs) roundVolumeBlockSizeBytes(vol Volume, sizeBytes int64) int64 {
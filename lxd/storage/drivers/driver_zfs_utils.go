@@ -7,13 +7,16 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/canonical/lxd/lxd/migration"
+	"github.com/canonical/lxd/lxd/storage/block"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
@@ -633,3 +636,344 @@ func (d *zfs) variantNeedsRecreateForBlocksize(dataset string, vol Volume) (bool
 
 	return currentBytes != desiredBytes, nil
 }
+
+// zfsDiffPaths parses the output of "zfs diff -H" and returns the changed paths relative to the mount path of the
+// compared filesystem. Renamed paths are returned under both their old and new names.
+func zfsDiffPaths(out string, mountPath string) ([]string, error) {
+	var paths []string
+
+	for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("Unexpected line in zfs diff output: %q", line)
+		}
+
+		for _, field := range fields[1:] {
+			path, err := zfsUnescapePath(field)
+			if err != nil {
+				return nil, err
+			}
+
+			relPath, found := strings.CutPrefix(path, mountPath)
+			if !found || (relPath != "" && !strings.HasPrefix(relPath, "/")) {
+				return nil, fmt.Errorf("Path %q in zfs diff output is outside of %q", path, mountPath)
+			}
+
+			paths = append(paths, "/"+strings.TrimPrefix(relPath, "/"))
+		}
+	}
+
+	return paths, nil
+}
+
+// zfsUnescapePath decodes a path printed by zfs diff, where whitespace, non-printable characters and backslashes
+// are escaped as a backslash followed by their value as four octal digits.
+func zfsUnescapePath(path string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		if path[i] != '\\' {
+			b.WriteByte(path[i])
+			continue
+		}
+
+		if i+5 > len(path) {
+			return "", fmt.Errorf("Invalid escape sequence in path %q", path)
+		}
+
+		value, err := strconv.ParseUint(path[i+1:i+5], 8, 8)
+		if err != nil {
+			return "", fmt.Errorf("Invalid escape sequence in path %q", path)
+		}
+
+		b.WriteByte(byte(value))
+		i += 4
+	}
+
+	return b.String(), nil
+}
+
+// zpoolScrubRegex matches the scan line of "zpool status" for a completed scrub.
+var zpoolScrubRegex = regexp.MustCompile(`^scrub repaired \S+ in (?:(\d+) days )?(\d+):(\d+):(\d+) with (\d+) errors on (.+)$`)
+
+// zpoolHealthState returns the health state of a pool in the given zpool state.
+func zpoolHealthState(state string) string {
+	switch state {
+	case "ONLINE":
+		return api.StoragePoolHealthStateHealthy
+	case "DEGRADED":
+		return api.StoragePoolHealthStateDegraded
+	default:
+		return api.StoragePoolHealthStateUnavailable
+	}
+}
+
+// zpoolParseStatus parses the output of "zpool status -p" for a single pool.
+func zpoolParseStatus(out string) (*api.StoragePoolHealth, error) {
+	var key string
+	var configLines []string
+	values := map[string]string{}
+
+	for _, line := range strings.Split(out, "\n") {
+		// Indented lines either continue the previous field or are part of the device table.
+		if strings.HasPrefix(line, "\t") {
+			if key == "config" {
+				if strings.TrimSpace(line) != "" {
+					configLines = append(configLines, line[1:])
+				}
+			} else if key != "" {
+				values[key] += " " + strings.TrimSpace(line)
+			}
+
+			continue
+		}
+
+		fieldKey, fieldValue, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		key = strings.TrimSpace(fieldKey)
+		values[key] = strings.TrimSpace(fieldValue)
+	}
+
+	if values["state"] == "" {
+		return nil, errors.New("Failed parsing zpool status: Missing pool state")
+	}
+
+	health := &api.StoragePoolHealth{
+		State:   zpoolHealthState(values["state"]),
+		Message: values["status"],
+		Devices: []api.StoragePoolHealthDevice{},
+	}
+
+	// Only report the leaf devices of the table, skipping the header, the pool itself, the vdev groups and
+	// section labels (logs, cache, spares...).
+	for i, line := range configLines {
+		if i == 0 {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent == 0 {
+			continue
+		}
+
+		if i+1 < len(configLines) {
+			nextLine := configLines[i+1]
+			if len(nextLine)-len(strings.TrimLeft(nextLine, " ")) > indent {
+				continue
+			}
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		device := api.StoragePoolHealthDevice{
+			Name:  fields[0],
+			State: fields[1],
+		}
+
+		if len(fields) >= 5 {
+			var counters [3]uint64
+			for j := range counters {
+				value, err := strconv.ParseUint(fields[j+2], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("Failed parsing zpool status: Invalid error count for device %q: %w", device.Name, err)
+				}
+
+				counters[j] = value
+			}
+
+			device.ReadErrors = counters[0]
+			device.WriteErrors = counters[1]
+			device.ChecksumErrors = counters[2]
+		}
+
+		if health.State == api.StoragePoolHealthStateHealthy && device.ReadErrors+device.WriteErrors+device.ChecksumErrors > 0 {
+			health.State = api.StoragePoolHealthStateDegraded
+		}
+
+		health.Devices = append(health.Devices, device)
+	}
+
+	dataErrors := values["errors"]
+	if dataErrors != "" && dataErrors != "No known data errors" {
+		if health.State == api.StoragePoolHealthStateHealthy {
+			health.State = api.StoragePoolHealthStateDegraded
+		}
+
+		if health.Message == "" {
+			health.Message = dataErrors
+		}
+	}
+
+	scrub, err := zpoolParseScrub(values["scan"])
+	if err != nil {
+		return nil, err
+	}
+
+	health.Scrub = scrub
+
+	return health, nil
+}
+
+// zpoolParseScrub parses the scan field of "zpool status" and returns the state of the last scrub.
+// Nil is returned if the pool was never scrubbed or the last scan was a resilver.
+func zpoolParseScrub(scan string) (*api.StoragePoolHealthScrub, error) {
+	parseTime := func(value string) (time.Time, error) {
+		// Remove the progress reported on the following lines of a running scrub.
+		fields := strings.Fields(value)
+		if len(fields) > 5 {
+			fields = fields[:5]
+		}
+
+		t, err := time.ParseInLocation(time.ANSIC, strings.Join(fields, " "), time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Failed parsing zpool scrub time: %w", err)
+		}
+
+		return t, nil
+	}
+
+	after, found := strings.CutPrefix(scan, "scrub in progress since ")
+	if found {
+		startedAt, err := parseTime(after)
+		if err != nil {
+			return nil, err
+		}
+
+		return &api.StoragePoolHealthScrub{Status: api.StoragePoolScrubStatusRunning, StartedAt: startedAt}, nil
+	}
+
+	after, found = strings.CutPrefix(scan, "scrub canceled on ")
+	if found {
+		finishedAt, err := parseTime(after)
+		if err != nil {
+			return nil, err
+		}
+
+		return &api.StoragePoolHealthScrub{Status: api.StoragePoolScrubStatusCanceled, FinishedAt: finishedAt}, nil
+	}
+
+	match := zpoolScrubRegex.FindStringSubmatch(scan)
+	if match == nil {
+		return nil, nil
+	}
+
+	finishedAt, err := parseTime(match[6])
+	if err != nil {
+		return nil, err
+	}
+
+	var duration time.Duration
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if match[i+1] == "" {
+			continue
+		}
+
+		value, err := strconv.ParseInt(match[i+1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		duration += time.Duration(value) * unit
+	}
+
+	scrubErrors, err := strconv.ParseUint(match[5], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.StoragePoolHealthScrub{
+		Status:     api.StoragePoolScrubStatusCompleted,
+		StartedAt:  finishedAt.Add(-duration),
+		FinishedAt: finishedAt,
+		Errors:     scrubErrors,
+	}, nil
+}
+
+// isEncrypted returns whether the volume is stored on an encrypted zvol.
+// Only zvols are encrypted, so the filesystem volumes of virtual machines never are.
+func (d *zfs) isEncrypted(vol Volume) bool {
+	return vol.IsEncrypted() && (IsContentBlock(vol.contentType) || d.isBlockBacked(vol))
+}
+
+// luksID returns the identifier of the unlocked LUKS device of an encrypted zvol.
+// The dataset name is used as zvol device paths change when zvols are activated again.
+func (d *zfs) luksID(dataset string) string {
+	return "zfs:" + dataset
+}
+
+// formatEncryptedVolume formats the zvol of a new encrypted volume as a LUKS device.
+// Block volumes are also cleared as unwritten blocks would otherwise not read as zeroes.
+func (d *zfs) formatEncryptedVolume(vol Volume) error {
+	key, err := d.volumeEncryptionKey(vol)
+	if err != nil {
+		return err
+	}
+
+	dataset := d.dataset(vol, false)
+
+	current, err := d.getDatasetProperty(dataset, "volmode")
+	if err != nil {
+		return err
+	}
+
+	if current != "dev" {
+		err = d.setDatasetProperties(dataset, "volmode=dev")
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = d.setDatasetProperties(dataset, "volmode="+current) }()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	devPath, err := d.tryGetVolumeDiskPathFromDataset(ctx, dataset)
+	if err != nil {
+		return err
+	}
+
+	err = luksFormat(devPath, key)
+	if err != nil {
+		return err
+	}
+
+	if vol.contentType == ContentTypeFS {
+		return nil
+	}
+
+	dataPath, err := luksOpen(devPath, d.luksID(dataset), key, false)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = luksClose(d.luksID(dataset)) }()
+
+	err = block.ClearBlock(dataPath, 0)
+	if err != nil {
+		return fmt.Errorf("Failed clearing encrypted ZFS volume: %w", err)
+	}
+
+	return nil
+}
+
+// openEncryptedVolume unlocks the zvol at devPath holding the given dataset of an encrypted volume.
+// Returns the path of the unlocked device.
+func (d *zfs) openEncryptedVolume(vol Volume, dataset string, devPath string, readOnly bool) (string, error) {
+	key, err := d.volumeEncryptionKey(vol)
+	if err != nil {
+		return "", err
+	}
+
+	return luksOpen(devPath, d.luksID(dataset), key, readOnly)
+}
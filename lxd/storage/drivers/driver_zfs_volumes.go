@@ -27,6 +27,7 @@ import (
 	"github.com/canonical/lxd/lxd/instancewriter"
 	"github.com/canonical/lxd/lxd/linux"
 	"github.com/canonical/lxd/lxd/migration"
+	"github.com/canonical/lxd/lxd/storage/block"
 	"github.com/canonical/lxd/lxd/storage/filesystem"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
@@ -145,12 +146,24 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, progressReporter io
 
 		sizeBytes = d.roundVolumeBlockSizeBytes(vol, sizeBytes)
 
+		// Reserve space for the LUKS header so that the usable size matches the volume size.
+		if d.isEncrypted(vol) {
+			sizeBytes += luksHeaderSize
+		}
+
 		// Create the volume dataset.
 		err = d.createVolume(d.dataset(vol, false), sizeBytes, opts...)
 		if err != nil {
 			return err
 		}
 
+		if d.isEncrypted(vol) {
+			err = d.formatEncryptedVolume(vol)
+			if err != nil {
+				return err
+			}
+		}
+
 		if vol.contentType == ContentTypeFS {
 			activated, volPath, err := d.activateVolume(vol)
 			if err != nil {
@@ -433,7 +446,11 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 		return nil, nil, err
 	}
 
-	if volExists {
+	// Incremental backups are applied on top of the volume restored from the previous backup of the chain.
+	incremental := srcBackup.BaseSnapshot != ""
+	if incremental && !volExists {
+		return nil, nil, fmt.Errorf("Cannot apply incremental backup, volume %q not found on target, import the backups of the chain in order", vol.name)
+	} else if !incremental && volExists {
 		return nil, nil, errors.New("Cannot restore volume, already exists on target")
 	}
 
@@ -449,6 +466,20 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 			_ = d.DeleteVolumeSnapshot(snapVol, progressReporter)
 		}
 
+		// Return the volume to the state of the base snapshot when applying an incremental backup.
+		if incremental {
+			vols := []Volume{vol.Volume}
+			if vol.IsVMBlock() {
+				vols = append(vols, vol.NewVMBlockFilesystemVolume())
+			}
+
+			for _, v := range vols {
+				_, _ = shared.RunCommand(context.TODO(), "zfs", "rollback", "-r", d.dataset(v, false)+"@snapshot-"+srcBackup.BaseSnapshot)
+			}
+
+			return
+		}
+
 		// And lastly the main volume.
 		_ = d.DeleteVolume(vol.Volume, progressReporter)
 	}
@@ -486,7 +517,9 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 					err = shared.RunCommandWithFds(context.TODO(), tr, nil, "zfs", "receive", "-x", "mountpoint", "-F", target)
 				}
 
-				if err != nil {
+				if err != nil && incremental {
+					return fmt.Errorf("Failed applying incremental backup on top of snapshot %q, the backup chain is broken: %w", srcBackup.BaseSnapshot, err)
+				} else if err != nil {
 					return err
 				}
 
@@ -520,6 +553,18 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 			return nil, nil, err
 		}
 
+		if incremental {
+			// Check the base snapshot of the incremental backup exists.
+			exists, err := d.datasetExists(d.dataset(v, false) + "@snapshot-" + srcBackup.BaseSnapshot)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if !exists {
+				return nil, nil, fmt.Errorf("Base snapshot %q of the incremental backup not found on target, import the backups of the chain in order", srcBackup.BaseSnapshot)
+			}
+		}
+
 		if len(srcBackup.Snapshots) > 0 {
 			// Create new snapshots directory.
 			err := createParentSnapshotDirIfMissing(d.name, v.volType, v.name)
@@ -642,6 +687,18 @@ func (d *zfs) CreateVolumeFromBackup(vol VolumeCopy, srcBackup backup.Info, srcD
 
 // CreateVolumeFromCopy provides same-pool volume copying functionality.
 func (d *zfs) CreateVolumeFromCopy(vol VolumeCopy, srcVol VolumeCopy, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error {
+	// Encrypted volumes can only be copied with ZFS if they share the same key, otherwise run the generic copy.
+	if !sameEncryptionKey(vol.Volume, srcVol.Volume) {
+		snapshotNames := make([]string, 0, len(vol.Snapshots))
+		for _, snapshot := range vol.Snapshots {
+			_, snapshotName, _ := api.GetParentAndSnapshotName(snapshot.name)
+			snapshotNames = append(snapshotNames, snapshotName)
+		}
+
+		_, err := genericVFSCopyVolume(d, nil, vol, srcVol, snapshotNames, false, allowInconsistent, progressReporter)
+		return err
+	}
+
 	// Revert handling
 	revert := revert.New()
 	defer revert.Fail()
@@ -1365,6 +1422,12 @@ func (d *zfs) EnsureImage(imgVol Volume, filler *VolumeFiller, progressReporter
 
 // RefreshVolume updates an existing volume to match the state of another.
 func (d *zfs) RefreshVolume(vol VolumeCopy, srcVol VolumeCopy, refreshSnapshots []string, allowInconsistent bool, progressReporter ioprogress.ProgressReporter) error {
+	// Encrypted volumes can only be refreshed with ZFS if they share the same key, otherwise run the generic refresh.
+	if !sameEncryptionKey(vol.Volume, srcVol.Volume) {
+		_, err := genericVFSCopyVolume(d, nil, vol, srcVol, refreshSnapshots, true, allowInconsistent, progressReporter)
+		return err
+	}
+
 	var err error
 	var targetSnapshots []Volume
 	var srcSnapshotsAll []Volume
@@ -1861,6 +1924,14 @@ func (d *zfs) deleteVolume(vol Volume, progressReporter ioprogress.ProgressRepor
 	}
 
 	if exists {
+		// Lock the volume first in case it is encrypted.
+		if d.isEncrypted(vol) {
+			err = luksClose(d.luksID(dataset))
+			if err != nil {
+				return err
+			}
+		}
+
 		// Handle clones.
 		clones, err := d.getClones(dataset)
 		if err != nil {
@@ -2072,6 +2143,19 @@ func (d *zfs) commonVolumeRules() map[string]func(value string) error {
 		//  shortdesc: Whether to promote the ZFS dataset
 		//  scope: global
 		"zfs.promote": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=storage-zfs; group=volume-conf; key=security.encrypted)
+		// Enable this option to store the volume encrypted with LUKS2.
+		// Only volumes backed by a `zvol` can be encrypted, which are block volumes and volumes with `zfs.block_mode` enabled.
+		// LXD generates the encryption key when creating the volume and unlocks the volume automatically when using it.
+		// This option cannot be changed after the volume is created.
+		// See {ref}`storage-zfs-encryption` for more information.
+		// ---
+		//  type: bool
+		//  condition: instance or custom volume backed by a `zvol`
+		//  defaultdesc: same as `volume.security.encrypted` or `false`
+		//  shortdesc: Whether to encrypt the volume
+		//  scope: global
+		"security.encrypted": validate.Optional(validate.IsBool),
 	}
 }
 
@@ -2092,7 +2176,31 @@ func (d *zfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "block.mount_options")
 	}
 
-	return d.validateVolume(vol, commonRules, removeUnknownKeys)
+	// lxdmeta:generate(entities=storage-zfs; group=volume-conf; key=volatile.encryption.key)
+	// The key is wrapped with a key that is specific to each server.
+	// ---
+	//  type: string
+	//  condition: encrypted volume
+	//  shortdesc: Encryption key of the volume
+	//  scope: global
+	commonRules["volatile.encryption.key"] = validate.IsAny
+
+	err := d.validateVolume(vol, commonRules, removeUnknownKeys)
+	if err != nil {
+		return err
+	}
+
+	if vol.IsEncrypted() {
+		if !encryptableVolume(vol) {
+			return errors.New("security.encrypted can only be used with instance and custom volumes")
+		}
+
+		if vol.contentType == ContentTypeFS && !d.isBlockBacked(vol) {
+			return errors.New("security.encrypted can only be used with filesystem volumes when zfs.block_mode is enabled")
+		}
+	}
+
+	return nil
 }
 
 // UpdateVolume applies config changes to the volume.
@@ -2196,6 +2304,27 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 
 		sizeBytes = d.roundVolumeBlockSizeBytes(vol, sizeBytes)
 
+		// The data of encrypted volumes is stored after the LUKS header.
+		var dataOffset int64
+		if d.isEncrypted(vol) {
+			dataOffset = luksHeaderSize
+			sizeBytes += dataOffset
+		}
+
+		// resizeEncryptedVolume resizes the unlocked device of encrypted volumes to match the zvol.
+		resizeEncryptedVolume := func() error {
+			if !d.isEncrypted(vol) {
+				return nil
+			}
+
+			key, err := d.volumeEncryptionKey(vol)
+			if err != nil {
+				return err
+			}
+
+			return luksResize(d.luksID(dataset), key)
+		}
+
 		oldSizeBytesStr, err := d.getDatasetProperty(dataset, "volsize")
 		if err != nil {
 			return err
@@ -2238,7 +2367,7 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 
 				// Shrink filesystem first.
 				// Pass allowUnsafeResize to allow disabling of filesystem resize safety checks.
-				err = shrinkFileSystem(fsType, volDevPath, vol, sizeBytes, allowUnsafeResize)
+				err = shrinkFileSystem(fsType, volDevPath, vol, sizeBytes-dataOffset, allowUnsafeResize)
 				if err != nil {
 					return err
 				}
@@ -2250,6 +2379,11 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 				if err != nil {
 					return err
 				}
+
+				err = resizeEncryptedVolume()
+				if err != nil {
+					return err
+				}
 			} else if sizeBytes > oldVolSizeBytes {
 				// Grow block device first.
 				err = d.setDatasetProperties(dataset, fmt.Sprintf("volsize=%d", sizeBytes))
@@ -2257,6 +2391,11 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 					return err
 				}
 
+				err = resizeEncryptedVolume()
+				if err != nil {
+					return err
+				}
+
 				// Grow the filesystem to fill block device.
 				err = growFileSystem(fsType, volDevPath, vol)
 				if err != nil {
@@ -2289,6 +2428,26 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, pr
 			if err != nil {
 				return err
 			}
+
+			err = resizeEncryptedVolume()
+			if err != nil {
+				return err
+			}
+		}
+
+		// The new blocks of grown encrypted block volumes need clearing as they would otherwise not read as zeroes.
+		if d.isEncrypted(vol) && IsContentBlock(vol.contentType) && sizeBytes > oldVolSizeBytes {
+			err = vol.MountTask(func(mountPath string, progressReporter ioprogress.ProgressReporter) error {
+				devPath, err := d.GetVolumeDiskPath(vol)
+				if err != nil {
+					return err
+				}
+
+				return block.ClearBlock(devPath, oldVolSizeBytes-dataOffset)
+			}, progressReporter)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Move the VM GPT alt header to end of disk if needed (not needed in unsafe resize mode as
@@ -2452,14 +2611,24 @@ func (d *zfs) getVolumeDiskPathFromDataset(dataset string) (string, error) {
 }
 
 // GetVolumeDiskPath returns the location of a root disk block device.
+// For encrypted volumes this is the unlocked LUKS device backed by the zvol.
 func (d *zfs) GetVolumeDiskPath(vol Volume) (string, error) {
+	if d.isEncrypted(vol) {
+		return luksMapperPath(d.luksID(d.dataset(vol, false))), nil
+	}
+
+	return d.zvolDiskPath(d.dataset(vol, false))
+}
+
+// zvolDiskPath returns the location of the zvol of the given dataset.
+func (d *zfs) zvolDiskPath(dataset string) (string, error) {
 	// Wait up to 30 seconds for the device to appear.
 	// Don't use d.state.ShutdownCtx here as this is used during instance stop during LXD shutdown after it is
 	// canceled.
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	return d.tryGetVolumeDiskPathFromDataset(ctx, d.dataset(vol, false))
+	return d.tryGetVolumeDiskPathFromDataset(ctx, dataset)
 }
 
 // ListVolumes returns a list of LXD volumes in storage pool.
@@ -2613,11 +2782,28 @@ func (d *zfs) activateVolume(vol Volume) (bool, string, error) {
 		d.logger.Debug("Activated ZFS volume", logger.Ctx{"volName": vol.Name(), "dev": dataset})
 	}
 
-	volumeDiskPath, err := d.GetVolumeDiskPath(vol)
+	volumeDiskPath, err := d.zvolDiskPath(dataset)
 	if err != nil {
 		return false, "", fmt.Errorf("Failed getting volume disk path: %v", err)
 	}
 
+	// Unlock encrypted volumes.
+	// A volume that wasn't unlocked yet isn't in use, so it is reported as activated to have it locked again.
+	if d.isEncrypted(vol) {
+		unlocked := shared.PathExists(luksMapperPath(d.luksID(dataset)))
+
+		volumeDiskPath, err = d.openEncryptedVolume(vol, dataset, volumeDiskPath, false)
+		if err != nil {
+			if activated {
+				_ = d.setDatasetProperties(dataset, "volmode=none")
+			}
+
+			return false, "", err
+		}
+
+		activated = activated || !unlocked
+	}
+
 	return activated, volumeDiskPath, nil
 }
 
@@ -2639,11 +2825,19 @@ func (d *zfs) deactivateVolume(vol Volume) (bool, error) {
 		return false, nil
 	}
 
-	devPath, err := d.GetVolumeDiskPath(vol)
+	devPath, err := d.zvolDiskPath(dataset)
 	if err != nil {
 		return false, fmt.Errorf("Failed locating zvol for deactivation: %w", err)
 	}
 
+	// Lock encrypted volumes before deactivating them.
+	if d.isEncrypted(vol) {
+		err = luksClose(d.luksID(dataset))
+		if err != nil {
+			return false, err
+		}
+	}
+
 	// We cannot wait longer than the operationlock.TimeoutShutdown to avoid continuing
 	// the unmount process beyond the ongoing request.
 	waitDuration := time.Minute * 5
@@ -2874,6 +3068,14 @@ func (d *zfs) RenameVolume(vol Volume, newVolName string, progressReporter iopro
 		_ = genericVFSRenameVolume(d, newVol, vol.name)
 	})
 
+	// Lock the volume first in case it is encrypted, as the unlocked device is named after the dataset.
+	if d.isEncrypted(vol) {
+		err = luksClose(d.luksID(d.dataset(vol, false)))
+		if err != nil {
+			return err
+		}
+	}
+
 	// Rename the ZFS datasets.
 	_, err = shared.RunCommand(context.TODO(), "zfs", "rename", d.dataset(vol, false), d.dataset(newVol, false))
 	if err != nil {
@@ -3229,7 +3431,7 @@ func (d *zfs) readonlySnapshot(vol Volume) (string, revert.Hook, error) {
 }
 
 // BackupVolume creates an exported version of a volume.
-func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, progressReporter ioprogress.ProgressReporter) error {
+func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots []string, baseSnapshot string, progressReporter ioprogress.ProgressReporter) error {
 	// Handle the non-optimized tarballs through the generic packer.
 	if !optimized {
 		// Because the generic backup method will not take a consistent backup if files are being modified
@@ -3264,7 +3466,7 @@ func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instan
 	// Backup VM config volumes first.
 	if vol.IsVMBlock() {
 		fsVol := NewVolumeCopy(vol.NewVMBlockFilesystemVolume())
-		err := d.BackupVolume(fsVol, projectName, tarWriter, optimized, snapshots, progressReporter)
+		err := d.BackupVolume(fsVol, projectName, tarWriter, optimized, snapshots, baseSnapshot, progressReporter)
 		if err != nil {
 			return err
 		}
@@ -3322,14 +3524,20 @@ func (d *zfs) BackupVolume(vol VolumeCopy, projectName string, tarWriter *instan
 		return tmpFile.Close()
 	}
 
-	// Handle snapshots.
+	// Incremental backups only contain the changes since the base snapshot.
 	finalParent := ""
+	if baseSnapshot != "" {
+		baseVol, _ := vol.NewSnapshot(baseSnapshot)
+		finalParent = d.dataset(baseVol, false)
+	}
+
+	// Handle snapshots.
 	if len(snapshots) > 0 {
 		for i, snapName := range snapshots {
 			snapshot, _ := vol.NewSnapshot(snapName)
 
 			// Figure out parent and current subvolumes.
-			parent := ""
+			parent := finalParent
 			if i > 0 {
 				oldSnapshot, _ := vol.NewSnapshot(snapshots[i-1])
 				parent = d.dataset(oldSnapshot, false)
@@ -3571,6 +3779,21 @@ func (d *zfs) mountVolumeSnapshot(snapVol Volume, snapshotDataset string, mountP
 			d.logger.Debug("Activated ZFS snapshot volume", logger.Ctx{"dev": snapshotDataset})
 		}
 
+		// Unlock the snapshots of encrypted block volumes so their data can be read.
+		if snapVol.contentType == ContentTypeBlock && d.isEncrypted(snapVol) {
+			devPath, err := d.zvolDiskPath(snapshotDataset)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = d.openEncryptedVolume(snapVol, snapshotDataset, devPath, true)
+			if err != nil {
+				return nil, err
+			}
+
+			revert.Add(func() { _ = luksClose(d.luksID(snapshotDataset)) })
+		}
+
 		if snapVol.contentType != ContentTypeBlock && d.isBlockBacked(snapVol) && !filesystem.IsMountPoint(mountPath) {
 			err = snapVol.EnsureMountPath()
 			if err != nil {
@@ -3620,6 +3843,16 @@ func (d *zfs) mountVolumeSnapshot(snapVol Volume, snapshotDataset string, mountP
 				return nil, err
 			}
 
+			// Unlock encrypted volumes, read-only unless a temporary writable snapshot is mounted.
+			if d.isEncrypted(snapVol) {
+				volPath, err = d.openEncryptedVolume(snapVol, dataset, volPath, !regenerateFSUUID)
+				if err != nil {
+					return nil, err
+				}
+
+				revert.Add(func() { _ = luksClose(d.luksID(dataset)) })
+			}
+
 			tmpVolFsType := mountVol.ConfigBlockFilesystem()
 			mountOptions = addNoRecoveryMountOption(mountOptions, tmpVolFsType)
 
@@ -3717,6 +3950,16 @@ func (d *zfs) UnmountVolumeSnapshot(snapVol Volume, progressReporter ioprogress.
 			parentDataset := d.dataset(parentVol, false)
 			dataset := parentDataset + "_" + snapshotOnlyName + tmpVolSuffix
 
+			// Lock encrypted volumes, which were unlocked from either the snapshot or its temporary clone.
+			if d.isEncrypted(snapVol) {
+				for _, unlockedDataset := range []string{snapshotDataset, dataset} {
+					err = luksClose(d.luksID(unlockedDataset))
+					if err != nil {
+						return true, err
+					}
+				}
+			}
+
 			exists, err := d.datasetExists(dataset)
 			if err != nil {
 				return true, fmt.Errorf("Failed checking existence of temporary ZFS snapshot volume %q: %w", dataset, err)
@@ -3745,6 +3988,14 @@ func (d *zfs) UnmountVolumeSnapshot(snapVol Volume, progressReporter ioprogress.
 				return false, ErrInUse
 			}
 
+			// Lock the snapshots of encrypted block volumes before hiding them.
+			if snapVol.contentType == ContentTypeBlock && d.isEncrypted(snapVol) {
+				err = luksClose(d.luksID(snapshotDataset))
+				if err != nil {
+					return false, err
+				}
+			}
+
 			err := d.setDatasetProperties(parentDataset, "snapdev=hidden")
 			if err != nil {
 				return false, err
@@ -3910,6 +4161,44 @@ func (d *zfs) restoreVolume(vol Volume, snapVol Volume, migration bool, progress
 	return nil
 }
 
+// DiffVolumeSnapshot returns the paths which differ between a volume snapshot and a later snapshot or the volume
+// itself, as reported by zfs diff.
+func (d *zfs) DiffVolumeSnapshot(snapVol Volume, vol Volume, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	// The filesystem of block backed volumes isn't a dataset, so compare the mounted filesystems instead.
+	if d.isBlockBacked(snapVol) {
+		return genericVFSDiffVolumeSnapshot(snapVol, vol, progressReporter)
+	}
+
+	// zfs diff requires the parent filesystem to be mounted and reports paths within its mount path.
+	parentName, _, _ := api.GetParentAndSnapshotName(snapVol.name)
+	parentVol := NewVolume(d, d.name, snapVol.volType, snapVol.contentType, parentName, snapVol.config, snapVol.poolConfig)
+
+	var entries []api.SnapshotDiffEntry
+
+	err := parentVol.MountTask(func(parentMountPath string, progressReporter ioprogress.ProgressReporter) error {
+		return diffMountTask(snapVol, vol, func(oldRoot string, newRoot string) error {
+			out, err := shared.RunCommand(context.TODO(), "zfs", "diff", "-H", d.dataset(snapVol, false), d.dataset(vol, false))
+			if err != nil {
+				return fmt.Errorf("Failed comparing %q with %q: %w", d.dataset(snapVol, false), d.dataset(vol, false), err)
+			}
+
+			paths, err := zfsDiffPaths(out, parentMountPath)
+			if err != nil {
+				return err
+			}
+
+			// The paths reported by zfs diff are described using the mounted snapshots.
+			entries, err = diffPaths(oldRoot, newRoot, paths)
+			return err
+		}, progressReporter)
+	}, progressReporter)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
 // RenameVolumeSnapshot renames a volume snapshot.
 func (d *zfs) RenameVolumeSnapshot(vol Volume, newSnapshotName string, progressReporter ioprogress.ProgressReporter) error {
 	parentName, _, _ := api.GetParentAndSnapshotName(vol.name)
@@ -3961,14 +4250,15 @@ func (d *zfs) RenameVolumeSnapshot(vol Volume, newSnapshotName string, progressR
 
 // FillVolumeConfig populate volume with default config.
 func (d *zfs) FillVolumeConfig(vol Volume) error {
-	var excludedKeys []string
+	// Exclude "security.encrypted" as it only applies to instance and custom volumes (handled below).
+	excludedKeys := []string{"security.encrypted"}
 
 	// Copy volume.* configuration options from pool.
 	// If vol has a source, ignore the block mode related config keys from the pool.
 	if vol.hasSource || vol.IsVMBlock() || vol.volType == VolumeTypeCustom && vol.contentType == ContentTypeBlock {
-		excludedKeys = []string{"zfs.block_mode", "block.filesystem", "block.mount_options"}
+		excludedKeys = append(excludedKeys, "zfs.block_mode", "block.filesystem", "block.mount_options")
 	} else if vol.volType == VolumeTypeCustom && !vol.IsBlockBacked() {
-		excludedKeys = []string{"block.filesystem", "block.mount_options"}
+		excludedKeys = append(excludedKeys, "block.filesystem", "block.mount_options")
 	}
 
 	err := d.fillVolumeConfig(&vol, excludedKeys...)
@@ -3976,6 +4266,11 @@ func (d *zfs) FillVolumeConfig(vol Volume) error {
 		return err
 	}
 
+	// Inherit encryption from pool if not set.
+	if vol.config["security.encrypted"] == "" && d.config["volume.security.encrypted"] != "" && encryptableVolume(vol) {
+		vol.config["security.encrypted"] = d.config["volume.security.encrypted"]
+	}
+
 	// Only validate filesystem config keys for filesystem volumes.
 	if d.isBlockBacked(vol) && vol.ContentType() == ContentTypeFS {
 		// Inherit block mode from pool if not set.
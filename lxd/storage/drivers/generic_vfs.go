@@ -35,6 +35,30 @@ const genericVolumeBlockExtension = "img"
 // genericVolumeDiskFile used to indicate the file name used for block volume disk files.
 const genericVolumeDiskFile = "root.img"
 
+// genericVolumeQcow2DiskFile used to indicate the file name used for qcow2 block volume disk files.
+const genericVolumeQcow2DiskFile = "root.qcow2"
+
+// genericVolumeBaseDiskFile used to indicate the file name used for the read-only raw disk file that qcow2 block
+// volume disk files cloned from an image volume are backed by.
+const genericVolumeBaseDiskFile = "base.img"
+
+// genericVolumeLayerDiskFilePattern matches the file names used for the read-only qcow2 layers that qcow2 block
+// volume disk files and their snapshots are backed by.
+const genericVolumeLayerDiskFilePattern = "layer-*.qcow2"
+
+// genericVolumeDiskFiles lists the patterns of all the disk file names that can be found in a volume's directory.
+var genericVolumeDiskFiles = []string{genericVolumeDiskFile, genericVolumeQcow2DiskFile, genericVolumeBaseDiskFile, genericVolumeLayerDiskFilePattern}
+
+// genericVFSDiskFilesRsyncArgs returns the rsync arguments needed to exclude the disk files of a volume.
+func genericVFSDiskFilesRsyncArgs() []string {
+	rsyncArgs := make([]string, 0, len(genericVolumeDiskFiles)*2)
+	for _, diskFile := range genericVolumeDiskFiles {
+		rsyncArgs = append(rsyncArgs, "--exclude", diskFile)
+	}
+
+	return rsyncArgs
+}
+
 // genericISOVolumeSuffix suffix used for generic iso content type volumes.
 const genericISOVolumeSuffix = ".iso"
 
@@ -131,6 +155,23 @@ func genericVFSVolumeSnapshots(d Driver, vol Volume) ([]string, error) {
 	return snapshots, nil
 }
 
+// genericVFSDiffVolumeSnapshot is a generic DiffVolumeSnapshot implementation comparing the mounted filesystems of
+// a volume snapshot and a later snapshot or the volume itself.
+func genericVFSDiffVolumeSnapshot(snapVol Volume, vol Volume, progressReporter ioprogress.ProgressReporter) ([]api.SnapshotDiffEntry, error) {
+	var entries []api.SnapshotDiffEntry
+
+	err := diffMountTask(snapVol, vol, func(oldRoot string, newRoot string) error {
+		var err error
+		entries, err = diffTrees(oldRoot, newRoot)
+		return err
+	}, progressReporter)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
 // genericVFSRenameVolumeSnapshot is a generic RenameVolumeSnapshot implementation for VFS-only drivers.
 func genericVFSRenameVolumeSnapshot(d Driver, snapVol Volume, newSnapshotName string, progressReporter ioprogress.ProgressReporter) error {
 	if !snapVol.IsSnapshot() {
@@ -154,14 +195,14 @@ func genericVFSMigrateVolume(d Driver, s *state.State, vol VolumeCopy, conn io.R
 	bwlimit := d.Config()["rsync.bwlimit"]
 	var rsyncArgs []string
 
-	// For VM volumes, exclude the generic root disk image file from being transferred via rsync, as it will
-	// be transferred later using a different method.
+	// For VM volumes, exclude the generic root disk image files from being transferred via rsync, as the disk
+	// will be transferred later using a different method.
 	if vol.IsVMBlock() {
 		if volSrcArgs.MigrationType.FSType != migration.MigrationFSType_BLOCK_AND_RSYNC {
 			return ErrNotSupported
 		}
 
-		rsyncArgs = []string{"--exclude", genericVolumeDiskFile}
+		rsyncArgs = genericVFSDiskFilesRsyncArgs()
 	} else if vol.contentType == ContentTypeBlock {
 		if volSrcArgs.MigrationType.FSType != migration.MigrationFSType_BLOCK_AND_RSYNC {
 			return ErrNotSupported
@@ -197,11 +238,19 @@ func genericVFSMigrateVolume(d Driver, s *state.State, vol VolumeCopy, conn io.R
 		// Close when done to indicate to target side we are finished sending this volume.
 		defer func() { _ = conn.Close() }()
 
-		path, err := d.GetVolumeDiskPath(vol)
+		diskPath, err := d.GetVolumeDiskPath(vol)
 		if err != nil {
 			return fmt.Errorf("Error getting VM block volume disk path: %w", err)
 		}
 
+		// Disk image files that aren't raw are always sent as raw for compatibility.
+		path, cleanup, err := RawDiskImage(diskPath, GetPoolMountPath(vol.pool))
+		if err != nil {
+			return err
+		}
+
+		defer cleanup()
+
 		from, err := os.Open(path)
 		if err != nil {
 			return fmt.Errorf("Error opening file for reading %q: %w", path, err)
@@ -327,6 +376,20 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 	}
 
 	recvBlockVol := func(volName string, conn io.ReadWriteCloser, path string) error {
+		// Block volumes are always received as raw. Disk image files that aren't raw are converted from a
+		// temporary raw file once the volume has been received.
+		diskPath := path
+		if DiskImageFormat(diskPath) != DiskImageFormatRaw {
+			path = filepath.Join(filepath.Dir(diskPath), "."+genericVolumeDiskFile+".recv")
+
+			err := ensureSparseFile(path, 0)
+			if err != nil {
+				return err
+			}
+
+			defer func() { _ = os.Remove(path) }()
+		}
+
 		to, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
 		if err != nil {
 			return fmt.Errorf("Error opening file for writing %q: %w", path, err)
@@ -348,7 +411,16 @@ func genericVFSCreateVolumeFromMigration(d Driver, initVolume func(vol Volume) (
 			return fmt.Errorf("Error copying from migration connection to %q: %w", path, err)
 		}
 
-		return to.Close()
+		err = to.Close()
+		if err != nil {
+			return err
+		}
+
+		if path != diskPath {
+			return convertDiskImage(path, diskPath)
+		}
+
+		return nil
 	}
 
 	// Ensure the volume is mounted.
@@ -565,17 +637,32 @@ func genericVFSBackupVolume(d Driver, vol VolumeCopy, tarWriter *instancewriter.
 				return fmt.Errorf(errMsg+": %w", err)
 			}
 
-			// Get size of disk block device for tarball header.
-			blockDiskSize, err := block.DiskSizeBytes(blockPath)
+			var exclude []string // Files to exclude from filesystem volume backup.
+			if !shared.IsBlockdevPath(blockPath) {
+				// Exclude the volume root disk files from the filesystem volume backup.
+				// We will read the disk as a block device later instead.
+				for _, diskFile := range genericVolumeDiskFiles {
+					diskPaths, err := filepath.Glob(filepath.Join(filepath.Dir(blockPath), diskFile))
+					if err != nil {
+						return err
+					}
+
+					exclude = append(exclude, diskPaths...)
+				}
+			}
+
+			// Disk image files that aren't raw are always exported as raw for compatibility.
+			rawBlockPath, cleanup, err := RawDiskImage(blockPath, GetPoolMountPath(v.pool))
 			if err != nil {
-				return fmt.Errorf("Error getting block device size %q: %w", blockPath, err)
+				return err
 			}
 
-			var exclude []string // Files to exclude from filesystem volume backup.
-			if !shared.IsBlockdevPath(blockPath) {
-				// Exclude the volume root disk file from the filesystem volume backup.
-				// We will read it as a block device later instead.
-				exclude = append(exclude, blockPath)
+			defer cleanup()
+
+			// Get size of disk block device for tarball header.
+			blockDiskSize, err := block.DiskSizeBytes(rawBlockPath)
+			if err != nil {
+				return fmt.Errorf("Error getting block device size %q: %w", rawBlockPath, err)
 			}
 
 			if v.IsVMBlock() {
@@ -614,10 +701,10 @@ func genericVFSBackupVolume(d Driver, vol VolumeCopy, tarWriter *instancewriter.
 				logMsg = "Copying custom block volume"
 			}
 
-			d.Logger().Debug(logMsg, logger.Ctx{"sourcePath": blockPath, "file": name, "size": blockDiskSize})
-			from, err := os.Open(blockPath)
+			d.Logger().Debug(logMsg, logger.Ctx{"sourcePath": rawBlockPath, "file": name, "size": blockDiskSize})
+			from, err := os.Open(rawBlockPath)
 			if err != nil {
-				return fmt.Errorf("Error opening file for reading %q: %w", blockPath, err)
+				return fmt.Errorf("Error opening file for reading %q: %w", rawBlockPath, err)
 			}
 
 			defer func() { _ = from.Close() }()
@@ -631,12 +718,12 @@ func genericVFSBackupVolume(d Driver, vol VolumeCopy, tarWriter *instancewriter.
 
 			err = tarWriter.WriteFileFromReader(from, &fi)
 			if err != nil {
-				return fmt.Errorf("Error copying %q as %q to tarball: %w", blockPath, name, err)
+				return fmt.Errorf("Error copying %q as %q to tarball: %w", rawBlockPath, name, err)
 			}
 
 			err = from.Close()
 			if err != nil {
-				return fmt.Errorf("Failed closing file %q: %w", blockPath, err)
+				return fmt.Errorf("Failed closing file %q: %w", rawBlockPath, err)
 			}
 
 			return nil
@@ -1002,7 +1089,7 @@ func genericVFSCopyVolume(d Driver, initVolume func(vol Volume) (revert.Hook, er
 	var rsyncArgs []string
 
 	if srcVol.IsVMBlock() {
-		rsyncArgs = append(rsyncArgs, "--exclude", genericVolumeDiskFile)
+		rsyncArgs = append(rsyncArgs, genericVFSDiskFilesRsyncArgs()...)
 	}
 
 	revert := revert.New()
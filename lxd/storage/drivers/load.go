@@ -12,6 +12,7 @@ var drivers = map[string]func() driver{
 	"cephobject": func() driver { return &cephobject{} },
 	"dir":        func() driver { return &dir{} },
 	"lvm":        func() driver { return &lvm{} },
+	"nfs":        func() driver { return &nfs{} },
 	"powerflex":  func() driver { return &powerflex{} },
 	"powerstore": func() driver { return &powerstore{} },
 	"pure":       func() driver { return &pure{} },
@@ -26,6 +26,7 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/validate"
 )
 
 // noKillRetryOpts is used as the default [shared.RunCommandRetryOpts] for storage operations.
@@ -640,6 +641,11 @@ func addNoRecoveryMountOption(mountOptions string, filesystem string) string {
 // copyDevice copies one device path to another using dd running at low priority.
 // It expects outputPath to exist already, so will not create it.
 func copyDevice(inputPath string, outputPath string) error {
+	// Disk image files that aren't raw need converting rather than copying.
+	if DiskImageFormat(inputPath) != DiskImageFormatRaw || DiskImageFormat(outputPath) != DiskImageFormatRaw {
+		return copyDiskImage(inputPath, outputPath)
+	}
+
 	cmd := []string{
 		"nice", "-n19", // Run dd with low priority to reduce CPU impact on other processes.
 		"dd", "if=" + inputPath, "of=" + outputPath,
@@ -668,11 +674,55 @@ func copyDevice(inputPath string, outputPath string) error {
 	return nil
 }
 
+// copyDiskImage copies the disk image at inputPath into the existing disk image at outputPath, converting
+// between formats as needed. Like with copyDevice, the output disk image keeps its size if it is larger.
+func copyDiskImage(inputPath string, outputPath string) error {
+	outputSizeBytes, err := block.DiskSizeBytes(outputPath)
+	if err != nil {
+		return err
+	}
+
+	err = convertDiskImage(inputPath, outputPath)
+	if err != nil {
+		return err
+	}
+
+	sizeBytes, err := block.DiskSizeBytes(outputPath)
+	if err != nil {
+		return err
+	}
+
+	if sizeBytes >= outputSizeBytes {
+		return nil
+	}
+
+	if DiskImageFormat(outputPath) == DiskImageFormatQcow2 {
+		return qcow2Resize(outputPath, outputSizeBytes, false)
+	}
+
+	return ensureSparseFile(outputPath, outputSizeBytes)
+}
+
 // loopFilePath returns the loop file path for a storage pool.
 func loopFilePath(poolName string) string {
 	return filepath.Join(shared.VarPath("disks"), poolName+".img")
 }
 
+// scrubPoolRules returns the pool config rules of the drivers supporting scheduled scrubs.
+func scrubPoolRules() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		// lxdmeta:generate(entities=storage-btrfs,storage-ceph,storage-zfs; group=pool-conf; key=scrub.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic scrubs (the default).
+		//
+		// A scrub reads all the data stored in the pool to detect and, where redundancy allows, repair corruption.
+		// ---
+		//  type: string
+		//  shortdesc: Schedule for automatic scrubs of the pool
+		//  scope: global
+		"scrub.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+	}
+}
+
 // ShiftBtrfsRootfs shifts the BTRFS root filesystem.
 func ShiftBtrfsRootfs(path string, diskIdmap *idmap.IdmapSet) error {
 	return shiftBtrfsRootfs(path, diskIdmap, true)
@@ -0,0 +1,421 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/ioprogress"
+)
+
+// maxDiffEntries is the maximum number of changed paths returned when comparing a volume snapshot.
+const maxDiffEntries = 10000
+
+// errTooManyDiffEntries is returned when more than maxDiffEntries paths differ between the compared trees.
+var errTooManyDiffEntries = api.StatusErrorf(http.StatusBadRequest, "More than %d paths differ, compare more recent snapshots instead", maxDiffEntries)
+
+// diffMountTask mounts a volume snapshot and the newer volume or snapshot it is compared with and runs the
+// supplied task with their mount paths.
+func diffMountTask(snapVol Volume, vol Volume, task func(oldRoot string, newRoot string) error, progressReporter ioprogress.ProgressReporter) error {
+	return snapVol.MountTask(func(oldRoot string, progressReporter ioprogress.ProgressReporter) error {
+		return vol.MountTask(func(newRoot string, _ ioprogress.ProgressReporter) error {
+			return task(oldRoot, newRoot)
+		}, progressReporter)
+	}, progressReporter)
+}
+
+// diffFileType returns the name of the type of file for the given mode.
+func diffFileType(mode fs.FileMode) string {
+	switch mode.Type() {
+	case fs.ModeDir:
+		return "directory"
+	case fs.ModeSymlink:
+		return "symlink"
+	case fs.ModeDevice:
+		return "block-device"
+	case fs.ModeDevice | fs.ModeCharDevice:
+		return "char-device"
+	case fs.ModeNamedPipe:
+		return "fifo"
+	case fs.ModeSocket:
+		return "socket"
+	default:
+		return "file"
+	}
+}
+
+// diffFileMode returns the permissions of the given mode in octal notation, including the special bits.
+func diffFileMode(mode fs.FileMode) string {
+	perm := uint32(mode.Perm())
+
+	if mode&fs.ModeSetuid != 0 {
+		perm |= syscall.S_ISUID
+	}
+
+	if mode&fs.ModeSetgid != 0 {
+		perm |= syscall.S_ISGID
+	}
+
+	if mode&fs.ModeSticky != 0 {
+		perm |= syscall.S_ISVTX
+	}
+
+	return fmt.Sprintf("%04o", perm)
+}
+
+// diffOpen opens the given path relative to the directory file descriptor without following any symlink, and
+// without leaving the directory. A symlink is opened itself rather than its target. It returns nil if the path
+// does not exist in the tree, including if it could only be reached through a symlink.
+func diffOpen(dirFd int, path string) (*os.File, error) {
+	how := unix.OpenHow{
+		Flags:   unix.O_PATH | unix.O_NOFOLLOW | unix.O_CLOEXEC,
+		Resolve: unix.RESOLVE_BENEATH | unix.RESOLVE_NO_SYMLINKS | unix.RESOLVE_NO_MAGICLINKS,
+	}
+
+	for {
+		fd, err := unix.Openat2(dirFd, path, &how)
+		if err == nil {
+			return os.NewFile(uintptr(fd), path), nil
+		}
+
+		// Resolution is retried if the tree was modified while resolving the path.
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			continue
+		}
+
+		if errors.Is(err, unix.ENOENT) || errors.Is(err, unix.ENOTDIR) || errors.Is(err, unix.ELOOP) || errors.Is(err, unix.EXDEV) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Failed opening %q: %w", path, err)
+	}
+}
+
+// diffStat returns the file info of the given file or nil if the file is nil.
+func diffStat(f *os.File) (fs.FileInfo, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	return f.Stat()
+}
+
+// diffReadlink returns the target of the symlink opened as the given file.
+func diffReadlink(f *os.File) (string, error) {
+	buf := make([]byte, unix.PathMax)
+	n, err := unix.Readlinkat(int(f.Fd()), "", buf)
+	if err != nil {
+		return "", fmt.Errorf("Failed reading symlink %q: %w", f.Name(), err)
+	}
+
+	return string(buf[:n]), nil
+}
+
+// diffReadDirNames returns the sorted names of the entries of the directory opened as the given file.
+// A directory which disappeared in the meantime is considered empty.
+func diffReadDirNames(f *os.File) ([]string, error) {
+	fd, err := unix.Openat(int(f.Fd()), ".", unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Failed opening directory %q: %w", f.Name(), err)
+	}
+
+	dir := os.NewFile(uintptr(fd), f.Name())
+	defer func() { _ = dir.Close() }()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading directory %q: %w", f.Name(), err)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// diffEntries returns the entries describing the change of the path relative to the roots between the old and new
+// file info. A path whose type changed is reported as removed and then added. No entries are returned if the path
+// exists in neither of the trees.
+func diffEntries(relPath string, oldInfo fs.FileInfo, newInfo fs.FileInfo) []api.SnapshotDiffEntry {
+	entryPath := filepath.Join("/", relPath)
+
+	if oldInfo != nil && newInfo != nil && oldInfo.Mode().Type() == newInfo.Mode().Type() {
+		return []api.SnapshotDiffEntry{{
+			Path:    entryPath,
+			Change:  api.SnapshotDiffChangeModified,
+			Type:    diffFileType(newInfo.Mode()),
+			OldSize: oldInfo.Size(),
+			NewSize: newInfo.Size(),
+			OldMode: diffFileMode(oldInfo.Mode()),
+			NewMode: diffFileMode(newInfo.Mode()),
+		}}
+	}
+
+	var entries []api.SnapshotDiffEntry
+
+	if oldInfo != nil {
+		entries = append(entries, api.SnapshotDiffEntry{
+			Path:    entryPath,
+			Change:  api.SnapshotDiffChangeRemoved,
+			Type:    diffFileType(oldInfo.Mode()),
+			OldSize: oldInfo.Size(),
+			OldMode: diffFileMode(oldInfo.Mode()),
+		})
+	}
+
+	if newInfo != nil {
+		entries = append(entries, api.SnapshotDiffEntry{
+			Path:    entryPath,
+			Change:  api.SnapshotDiffChangeAdded,
+			Type:    diffFileType(newInfo.Mode()),
+			NewSize: newInfo.Size(),
+			NewMode: diffFileMode(newInfo.Mode()),
+		})
+	}
+
+	return entries
+}
+
+// diffModified returns whether a path of the same type in both trees was modified, based on its mode, ownership,
+// modification time, size and symlink target.
+func diffModified(oldFile *os.File, newFile *os.File, oldInfo fs.FileInfo, newInfo fs.FileInfo) (bool, error) {
+	if oldInfo.Mode() != newInfo.Mode() || !oldInfo.ModTime().Equal(newInfo.ModTime()) {
+		return true, nil
+	}
+
+	// The size of directories depends on the filesystem and changes along with their modification time.
+	if !oldInfo.IsDir() && oldInfo.Size() != newInfo.Size() {
+		return true, nil
+	}
+
+	oldStat, oldOK := oldInfo.Sys().(*syscall.Stat_t)
+	newStat, newOK := newInfo.Sys().(*syscall.Stat_t)
+	if oldOK && newOK && (oldStat.Uid != newStat.Uid || oldStat.Gid != newStat.Gid || oldStat.Rdev != newStat.Rdev) {
+		return true, nil
+	}
+
+	if oldInfo.Mode().Type() == fs.ModeSymlink {
+		oldTarget, err := diffReadlink(oldFile)
+		if err != nil {
+			return false, err
+		}
+
+		newTarget, err := diffReadlink(newFile)
+		if err != nil {
+			return false, err
+		}
+
+		return oldTarget != newTarget, nil
+	}
+
+	return false, nil
+}
+
+// diffTrees compares the trees at the given roots and returns the paths that were added, removed or modified in
+// the new tree. The content of files with the same size and modification time isn't compared.
+// The trees are walked relative to their roots without following symlinks, so that their content can't cause
+// paths outside of them to be accessed.
+func diffTrees(oldRoot string, newRoot string) ([]api.SnapshotDiffEntry, error) {
+	var entries []api.SnapshotDiffEntry
+
+	var walk func(relPath string, oldFile *os.File, newFile *os.File) error
+	walk = func(relPath string, oldFile *os.File, newFile *os.File) error {
+		oldInfo, err := diffStat(oldFile)
+		if err != nil {
+			return err
+		}
+
+		newInfo, err := diffStat(newFile)
+		if err != nil {
+			return err
+		}
+
+		// Changes of the roots themselves aren't reported.
+		if relPath != "/" {
+			if oldInfo != nil && newInfo != nil && oldInfo.Mode().Type() == newInfo.Mode().Type() {
+				modified, err := diffModified(oldFile, newFile, oldInfo, newInfo)
+				if err != nil {
+					return err
+				}
+
+				if modified {
+					entries = append(entries, diffEntries(relPath, oldInfo, newInfo)...)
+				}
+			} else {
+				entries = append(entries, diffEntries(relPath, oldInfo, newInfo)...)
+			}
+
+			// Stop walking the trees as soon as too many changes are found.
+			if len(entries) > maxDiffEntries {
+				return errTooManyDiffEntries
+			}
+		}
+
+		// Recurse into directories, children only present on one side are reported as removed or added.
+		var oldNames, newNames []string
+
+		if oldInfo != nil && oldInfo.IsDir() {
+			oldNames, err = diffReadDirNames(oldFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		if newInfo != nil && newInfo.IsDir() {
+			newNames, err = diffReadDirNames(newFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		names := append(slices.Clone(oldNames), newNames...)
+		slices.Sort(names)
+		names = slices.Compact(names)
+
+		for _, name := range names {
+			var childOldFile, childNewFile *os.File
+
+			_, found := slices.BinarySearch(oldNames, name)
+			if found {
+				childOldFile, err = diffOpen(int(oldFile.Fd()), name)
+				if err != nil {
+					return err
+				}
+			}
+
+			_, found = slices.BinarySearch(newNames, name)
+			if found {
+				childNewFile, err = diffOpen(int(newFile.Fd()), name)
+				if err != nil {
+					if childOldFile != nil {
+						_ = childOldFile.Close()
+					}
+
+					return err
+				}
+			}
+
+			err = walk(filepath.Join(relPath, name), childOldFile, childNewFile)
+
+			if childOldFile != nil {
+				_ = childOldFile.Close()
+			}
+
+			if childNewFile != nil {
+				_ = childNewFile.Close()
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	oldFile, err := os.OpenFile(oldRoot, unix.O_PATH|unix.O_DIRECTORY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = oldFile.Close() }()
+
+	newFile, err := os.OpenFile(newRoot, unix.O_PATH|unix.O_DIRECTORY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = newFile.Close() }()
+
+	err = walk("/", oldFile, newFile)
+	if err != nil {
+		return nil, err
+	}
+
+	sortDiffEntries(entries)
+
+	return entries, nil
+}
+
+// diffPaths returns the entries for the given paths relative to the roots, as reported as changed by the storage.
+// Duplicate paths and paths which exist in neither of the trees are ignored. Paths are resolved relative to the
+// roots without following symlinks, so a path only reachable through a symlink is considered not to exist.
+func diffPaths(oldRoot string, newRoot string, relPaths []string) ([]api.SnapshotDiffEntry, error) {
+	var entries []api.SnapshotDiffEntry
+
+	oldRootFile, err := os.OpenFile(oldRoot, unix.O_PATH|unix.O_DIRECTORY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = oldRootFile.Close() }()
+
+	newRootFile, err := os.OpenFile(newRoot, unix.O_PATH|unix.O_DIRECTORY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = newRootFile.Close() }()
+
+	// diffPathInfo returns the file info of the path relative to the root or nil if it does not exist.
+	diffPathInfo := func(rootFile *os.File, relPath string) (fs.FileInfo, error) {
+		f, err := diffOpen(int(rootFile.Fd()), "."+relPath)
+		if err != nil || f == nil {
+			return nil, err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		return f.Stat()
+	}
+
+	relPaths = slices.Clone(relPaths)
+	slices.Sort(relPaths)
+	relPaths = slices.Compact(relPaths)
+
+	if len(relPaths) > maxDiffEntries {
+		return nil, errTooManyDiffEntries
+	}
+
+	for _, relPath := range relPaths {
+		relPath = filepath.Join("/", relPath)
+
+		oldInfo, err := diffPathInfo(oldRootFile, relPath)
+		if err != nil {
+			return nil, err
+		}
+
+		newInfo, err := diffPathInfo(newRootFile, relPath)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, diffEntries(relPath, oldInfo, newInfo)...)
+		if len(entries) > maxDiffEntries {
+			return nil, errTooManyDiffEntries
+		}
+	}
+
+	sortDiffEntries(entries)
+
+	return entries, nil
+}
+
+// sortDiffEntries sorts the entries by path, keeping removals before additions of the same path.
+func sortDiffEntries(entries []api.SnapshotDiffEntry) {
+	slices.SortStableFunc(entries, func(a api.SnapshotDiffEntry, b api.SnapshotDiffEntry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+}
@@ -0,0 +1,206 @@
+package drivers
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// writeDiffTree creates the files, directories and symlinks of a tree with identical timestamps.
+func writeDiffTree(t *testing.T, root string, files map[string]string, modes map[string]fs.FileMode, links map[string]string) {
+	for path, content := range files {
+		fullPath := filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), 0644))
+		require.NoError(t, os.Chmod(fullPath, 0644))
+	}
+
+	for path, mode := range modes {
+		require.NoError(t, os.Chmod(filepath.Join(root, path), mode))
+	}
+
+	for path, target := range links {
+		require.NoError(t, os.Symlink(target, filepath.Join(root, path)))
+	}
+
+	ts := []unix.Timespec{unix.NsecToTimespec(1e18), unix.NsecToTimespec(1e18)}
+	err := filepath.WalkDir(root, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		return unix.UtimesNanoAt(unix.AT_FDCWD, path, ts, unix.AT_SYMLINK_NOFOLLOW)
+	})
+	require.NoError(t, err)
+}
+
+func TestDiffTrees(t *testing.T) {
+	oldRoot := t.TempDir()
+	newRoot := t.TempDir()
+
+	writeDiffTree(t, oldRoot, map[string]string{
+		"same":         "a",
+		"modified":     "abc",
+		"chmod":        "x",
+		"removed":      "r",
+		"olddir/child": "c",
+		"typechange":   "t",
+		"keepdir/file": "k",
+	}, nil, map[string]string{"link": "same"})
+
+	writeDiffTree(t, newRoot, map[string]string{
+		"same":             "a",
+		"modified":         "abcdef",
+		"chmod":            "x",
+		"added":            "hello",
+		"newdir/child":     "c",
+		"typechange/inner": "i",
+		"keepdir/file":     "k",
+	}, map[string]fs.FileMode{"chmod": 0600}, map[string]string{"link": "modified"})
+
+	entries, err := diffTrees(oldRoot, newRoot)
+	require.NoError(t, err)
+
+	changes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		changes = append(changes, entry.Change+" "+entry.Type+" "+entry.Path)
+	}
+
+	assert.Equal(t, []string{
+		"added file /added",
+		"modified file /chmod",
+		"modified symlink /link",
+		"modified file /modified",
+		"added directory /newdir",
+		"added file /newdir/child",
+		"removed directory /olddir",
+		"removed file /olddir/child",
+		"removed file /removed",
+		"removed file /typechange",
+		"added directory /typechange",
+		"added file /typechange/inner",
+	}, changes)
+
+	assert.Equal(t, api.SnapshotDiffEntry{Path: "/added", Change: api.SnapshotDiffChangeAdded, Type: "file", NewSize: 5, NewMode: "0644"}, entries[0])
+	assert.Equal(t, api.SnapshotDiffEntry{Path: "/chmod", Change: api.SnapshotDiffChangeModified, Type: "file", OldSize: 1, NewSize: 1, OldMode: "0644", NewMode: "0600"}, entries[1])
+	assert.Equal(t, api.SnapshotDiffEntry{Path: "/modified", Change: api.SnapshotDiffChangeModified, Type: "file", OldSize: 3, NewSize: 6, OldMode: "0644", NewMode: "0644"}, entries[3])
+	assert.Equal(t, api.SnapshotDiffEntry{Path: "/removed", Change: api.SnapshotDiffChangeRemoved, Type: "file", OldSize: 1, OldMode: "0644"}, entries[8])
+
+	// Identical trees have no differences.
+	entries, err = diffTrees(oldRoot, oldRoot)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDiffPaths(t *testing.T) {
+	oldRoot := t.TempDir()
+	newRoot := t.TempDir()
+
+	writeDiffTree(t, oldRoot, map[string]string{"file": "a", "removed": "r"}, nil, nil)
+	writeDiffTree(t, newRoot, map[string]string{"file": "a", "added": "b"}, map[string]fs.FileMode{"file": fs.ModeSetuid | 0755}, nil)
+
+	entries, err := diffPaths(oldRoot, newRoot, []string{"/removed", "/file", "/added", "/missing", "/file"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []api.SnapshotDiffEntry{
+		{Path: "/added", Change: api.SnapshotDiffChangeAdded, Type: "file", NewSize: 1, NewMode: "0644"},
+		{Path: "/file", Change: api.SnapshotDiffChangeModified, Type: "file", OldSize: 1, NewSize: 1, OldMode: "0644", NewMode: "4755"},
+		{Path: "/removed", Change: api.SnapshotDiffChangeRemoved, Type: "file", OldSize: 1, OldMode: "0644"},
+	}, entries)
+}
+
+func TestDiffSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	oldRoot := t.TempDir()
+	newRoot := t.TempDir()
+
+	writeDiffTree(t, outside, map[string]string{"secret": "s"}, nil, nil)
+	writeDiffTree(t, oldRoot, map[string]string{"dir/secret": "a"}, nil, nil)
+	writeDiffTree(t, newRoot, nil, nil, map[string]string{"dir": outside, "abs": "/"})
+
+	// Symlinks are compared themselves and never followed.
+	entries, err := diffTrees(oldRoot, newRoot)
+	require.NoError(t, err)
+
+	changes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		changes = append(changes, entry.Change+" "+entry.Type+" "+entry.Path)
+	}
+
+	assert.Equal(t, []string{
+		"added symlink /abs",
+		"removed directory /dir",
+		"added symlink /dir",
+		"removed file /dir/secret",
+	}, changes)
+
+	// Paths reached through a symlink don't exist in the tree.
+	entries, err = diffPaths(oldRoot, newRoot, []string{"/dir/secret", "/abs/etc", "/../secret"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []api.SnapshotDiffEntry{
+		{Path: "/dir/secret", Change: api.SnapshotDiffChangeRemoved, Type: "file", OldSize: 1, OldMode: "0644"},
+	}, entries)
+}
+
+func TestDiffTooManyEntries(t *testing.T) {
+	oldRoot := t.TempDir()
+	newRoot := t.TempDir()
+
+	paths := make([]string, 0, maxDiffEntries+1)
+	for i := range maxDiffEntries + 1 {
+		name := strconv.Itoa(i)
+		require.NoError(t, os.WriteFile(filepath.Join(newRoot, name), nil, 0644))
+		paths = append(paths, "/"+name)
+	}
+
+	_, err := diffTrees(oldRoot, newRoot)
+	assert.ErrorIs(t, err, errTooManyDiffEntries)
+
+	_, err = diffPaths(oldRoot, newRoot, paths)
+	assert.ErrorIs(t, err, errTooManyDiffEntries)
+
+	// Up to the maximum number of entries are returned.
+	entries, err := diffPaths(oldRoot, newRoot, paths[:maxDiffEntries])
+	require.NoError(t, err)
+	assert.Len(t, entries, maxDiffEntries)
+}
+
+func TestZFSDiffPaths(t *testing.T) {
+	out := "M\t/mnt/c1/rootfs/etc\n" +
+		"+\t/mnt/c1/rootfs/etc/new\\0040file\n" +
+		"-\t/mnt/c1/rootfs/etc/back\\0134slash\n" +
+		"R\t/mnt/c1/rootfs/old\t/mnt/c1/rootfs/new\n" +
+		"M\t/mnt/c1\n"
+
+	paths, err := zfsDiffPaths(out, "/mnt/c1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/rootfs/etc", "/rootfs/etc/new file", "/rootfs/etc/back\\slash", "/rootfs/old", "/rootfs/new", "/"}, paths)
+
+	paths, err = zfsDiffPaths("", "/mnt/c1")
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	for _, out := range []string{"M\t/mnt/c10/file", "M\t/other/file", "M", "M\t/mnt/c1/bad\\09"} {
+		_, err = zfsDiffPaths(out, "/mnt/c1")
+		assert.Error(t, err, out)
+	}
+}
+
+func TestBTRFSFindNewPaths(t *testing.T) {
+	out := "inode 257 file offset 0 len 4096 disk start 13631488 offset 0 gen 9 flags NONE etc/hostname\n" +
+		"inode 257 file offset 4096 len 4096 disk start 13635584 offset 0 gen 9 flags NONE etc/hostname\n" +
+		"inode 258 file offset 0 len 12 disk start 0 offset 0 gen 10 flags INLINE rootfs/file with flags in name\n" +
+		"transid marker was 10\n"
+
+	assert.Equal(t, []string{"/etc/hostname", "/rootfs/file with flags in name"}, btrfsFindNewPaths(out))
+	assert.Empty(t, btrfsFindNewPaths("transid marker was 10\n"))
+}
@@ -0,0 +1,218 @@
+package drivers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestZpoolParseStatus(t *testing.T) {
+	out := `  pool: tank
+ state: DEGRADED
+status: One or more devices could not be used because the label is missing or
+	invalid.  Sufficient replicas exist for the pool to continue
+	functioning in a degraded state.
+action: Replace the device using 'zpool replace'.
+   see: https://openzfs.github.io/openzfs-docs/msg/ZFS-8000-4J
+  scan: scrub repaired 0B in 01:02:03 with 2 errors on Sun Oct 11 00:24:01 2026
+config:
+
+	NAME        STATE     READ WRITE CKSUM
+	tank        DEGRADED     0     0     0
+	  mirror-0  DEGRADED     0     0     0
+	    sda     ONLINE       0     0     3
+	    sdb     UNAVAIL      1     2     0  invalid label
+	logs
+	  sdc       ONLINE       0     0     0
+	spares
+	  sdd       AVAIL
+
+errors: No known data errors
+`
+
+	health, err := zpoolParseStatus(out)
+	require.NoError(t, err)
+
+	assert.Equal(t, api.StoragePoolHealthStateDegraded, health.State)
+	assert.Equal(t, "One or more devices could not be used because the label is missing or invalid.  Sufficient replicas exist for the pool to continue functioning in a degraded state.", health.Message)
+	assert.Equal(t, []api.StoragePoolHealthDevice{
+		{Name: "sda", State: "ONLINE", ChecksumErrors: 3},
+		{Name: "sdb", State: "UNAVAIL", ReadErrors: 1, WriteErrors: 2},
+		{Name: "sdc", State: "ONLINE"},
+		{Name: "sdd", State: "AVAIL"},
+	}, health.Devices)
+
+	finishedAt := time.Date(2026, time.October, 11, 0, 24, 1, 0, time.Local)
+	assert.Equal(t, &api.StoragePoolHealthScrub{
+		Status:     api.StoragePoolScrubStatusCompleted,
+		StartedAt:  finishedAt.Add(-(time.Hour + 2*time.Minute + 3*time.Second)),
+		FinishedAt: finishedAt,
+		Errors:     2,
+	}, health.Scrub)
+
+	// Errors on an online pool degrade it.
+	health, err = zpoolParseStatus(" state: ONLINE\n  scan: none requested\nconfig:\n\n\tNAME STATE READ WRITE CKSUM\n\ttank ONLINE 0 0 0\n\t  sda ONLINE 0 0 1\n\nerrors: 1 data errors, use '-v' for a list\n")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthStateDegraded, health.State)
+	assert.Equal(t, "1 data errors, use '-v' for a list", health.Message)
+	assert.Nil(t, health.Scrub)
+
+	health, err = zpoolParseStatus(" state: SUSPENDED\n")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthStateUnavailable, health.State)
+
+	_, err = zpoolParseStatus("no pools available\n")
+	assert.Error(t, err)
+}
+
+func TestZpoolParseScrub(t *testing.T) {
+	startedAt := time.Date(2026, time.October, 11, 0, 24, 1, 0, time.Local)
+
+	scrub, err := zpoolParseScrub("scrub in progress since Sun Oct 11 00:24:01 2026 1.23G scanned at 100M/s, 1.00G issued at 80M/s, 10.0G total 0B repaired, 10.00% done, 00:01:50 to go")
+	require.NoError(t, err)
+	assert.Equal(t, &api.StoragePoolHealthScrub{Status: api.StoragePoolScrubStatusRunning, StartedAt: startedAt}, scrub)
+
+	scrub, err = zpoolParseScrub("scrub canceled on Sun Oct 11 00:24:01 2026")
+	require.NoError(t, err)
+	assert.Equal(t, &api.StoragePoolHealthScrub{Status: api.StoragePoolScrubStatusCanceled, FinishedAt: startedAt}, scrub)
+
+	scrub, err = zpoolParseScrub("scrub repaired 0 in 1 days 00:00:00 with 0 errors on Sun Oct 11 00:24:01 2026")
+	require.NoError(t, err)
+	assert.Equal(t, startedAt.Add(-24*time.Hour), scrub.StartedAt)
+
+	for _, scan := range []string{"", "none requested", "resilvered 1.2G in 00:00:10 with 0 errors on Sun Oct 11 00:24:01 2026"} {
+		scrub, err = zpoolParseScrub(scan)
+		require.NoError(t, err)
+		assert.Nil(t, scrub, scan)
+	}
+
+	_, err = zpoolParseScrub("scrub canceled on yesterday")
+	assert.Error(t, err)
+}
+
+func TestBTRFSParseDeviceStats(t *testing.T) {
+	out := `[/dev/sda].write_io_errs    1
+[/dev/sda].read_io_errs     2
+[/dev/sda].flush_io_errs    3
+[/dev/sda].corruption_errs  4
+[/dev/sda].generation_errs  5
+[/dev/sdb].write_io_errs    0
+[/dev/sdb].read_io_errs     0
+[/dev/sdb].flush_io_errs    0
+[/dev/sdb].corruption_errs  0
+[/dev/sdb].generation_errs  0
+`
+
+	devices, err := btrfsParseDeviceStats(out)
+	require.NoError(t, err)
+	assert.Equal(t, []api.StoragePoolHealthDevice{
+		{Name: "/dev/sda", State: "ok", ReadErrors: 2, WriteErrors: 4, ChecksumErrors: 9},
+		{Name: "/dev/sdb", State: "ok"},
+	}, devices)
+
+	_, err = btrfsParseDeviceStats("[/dev/sda].write_io_errs    many\n")
+	assert.Error(t, err)
+}
+
+func TestBTRFSParseScrubStatus(t *testing.T) {
+	out := `UUID:             0d6a5d1e-4a4e-4ab5-a8b6-3c8c9e1e0a11
+Scrub started:    Sun Oct 11 00:24:01 2026
+Status:           finished
+Duration:         1:02:03
+	data_extents_scrubbed: 3
+	tree_extents_scrubbed: 17
+	read_errors: 1
+	csum_errors: 2
+	verify_errors: 0
+	no_csum: 0
+	csum_discards: 0
+	super_errors: 0
+	malloc_errors: 0
+	uncorrectable_errors: 1
+	unverified_errors: 0
+	corrected_errors: 2
+	last_physical: 1048576
+`
+
+	startedAt := time.Date(2026, time.October, 11, 0, 24, 1, 0, time.Local)
+
+	scrub, err := btrfsParseScrubStatus(out)
+	require.NoError(t, err)
+	assert.Equal(t, &api.StoragePoolHealthScrub{
+		Status:     api.StoragePoolScrubStatusCompleted,
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(time.Hour + 2*time.Minute + 3*time.Second),
+		Errors:     3,
+	}, scrub)
+
+	scrub, err = btrfsParseScrubStatus("Scrub started:    Sun Oct 11 00:24:01 2026\nStatus:           running\nDuration:         0:00:05\n")
+	require.NoError(t, err)
+	assert.Equal(t, &api.StoragePoolHealthScrub{Status: api.StoragePoolScrubStatusRunning, StartedAt: startedAt}, scrub)
+
+	scrub, err = btrfsParseScrubStatus("UUID:             0d6a5d1e-4a4e-4ab5-a8b6-3c8c9e1e0a11\n\tno stats available\n")
+	require.NoError(t, err)
+	assert.Nil(t, scrub)
+
+	_, err = btrfsParseScrubStatus("Scrub started:    Sun Oct 11 00:24:01 2026\nStatus:           unknown\n")
+	assert.Error(t, err)
+}
+
+func TestLVMParseHealth(t *testing.T) {
+	out := "  /dev/sda,vg0,a--\n  [unknown],vg0,a-m\n  /dev/sdc,other,a--\n"
+
+	health, err := lvmParseHealth(out, "vg0", "")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthStateDegraded, health.State)
+	assert.Equal(t, []api.StoragePoolHealthDevice{
+		{Name: "/dev/sda", State: "ok"},
+		{Name: "[unknown]", State: "missing"},
+	}, health.Devices)
+
+	health, err = lvmParseHealth("  /dev/sda,vg0,a--\n", "vg0", "  \n")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthStateHealthy, health.State)
+
+	health, err = lvmParseHealth("  /dev/sda,vg0,a--\n", "vg0", "  out_of_data\n")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthStateDegraded, health.State)
+	assert.Equal(t, `Thin pool health is "out_of_data"`, health.Message)
+
+	health, err = lvmParseHealth("  /dev/sda,vg0,a--\n", "vg0", "failed")
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthStateUnavailable, health.State)
+
+	_, err = lvmParseHealth("  /dev/sda\n", "vg0", "")
+	assert.Error(t, err)
+}
+
+func TestCephParseHealth(t *testing.T) {
+	healthOut := `{"status":"HEALTH_WARN","checks":{"PG_DEGRADED":{"severity":"HEALTH_WARN","summary":{"message":"Degraded data redundancy: 12 pgs degraded","count":12}},"OSD_DOWN":{"severity":"HEALTH_WARN","summary":{"message":"1 osds down","count":1}}},"mutes":[]}`
+	osdTreeOut := `{"nodes":[{"id":-1,"name":"default","type":"root","children":[-3]},{"id":-3,"name":"host1","type":"host","children":[1,0]},{"id":0,"name":"osd.0","type":"osd","status":"up"},{"id":1,"name":"osd.1","type":"osd","status":"down"}],"stray":[{"id":2,"name":"osd.2","type":"osd","status":"down"}]}`
+
+	health, err := cephParseHealth(healthOut, osdTreeOut)
+	require.NoError(t, err)
+	assert.Equal(t, &api.StoragePoolHealth{
+		State:   api.StoragePoolHealthStateDegraded,
+		Message: "1 osds down; Degraded data redundancy: 12 pgs degraded",
+		Devices: []api.StoragePoolHealthDevice{
+			{Name: "osd.0", State: "up"},
+			{Name: "osd.1", State: "down"},
+			{Name: "osd.2", State: "down"},
+		},
+	}, health)
+
+	health, err = cephParseHealth(`{"status":"HEALTH_OK","checks":{}}`, `{"nodes":[],"stray":[]}`)
+	require.NoError(t, err)
+	assert.Equal(t, api.StoragePoolHealthStateHealthy, health.State)
+	assert.Empty(t, health.Message)
+
+	_, err = cephParseHealth(`{"status":"HEALTH_UNKNOWN"}`, `{}`)
+	assert.Error(t, err)
+
+	_, err = cephParseHealth(`not json`, `{}`)
+	assert.Error(t, err)
+}
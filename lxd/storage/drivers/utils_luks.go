@@ -0,0 +1,156 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/lxd/storage/keystore"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// luksHeaderSize is the space reserved at the start of encrypted devices for the LUKS2 header.
+// It is set explicitly when formatting so the usable size of an encrypted device is predictable.
+const luksHeaderSize = 16 * 1024 * 1024
+
+// luksMapperName returns the device mapper name of the unlocked LUKS device identified by id.
+// The id is the device path for drivers with stable device paths.
+// A hash is used as device mapper names are limited in length.
+func luksMapperName(id string) string {
+	hash := sha256.Sum256([]byte(id))
+	return "lxd-luks-" + hex.EncodeToString(hash[:16])
+}
+
+// luksMapperPath returns the path of the unlocked LUKS device identified by id.
+func luksMapperPath(id string) string {
+	return "/dev/mapper/" + luksMapperName(id)
+}
+
+// luksFormat formats devPath as a LUKS2 device using the supplied key.
+func luksFormat(devPath string, key []byte) error {
+	// The key is long and random, so a fast key derivation function is sufficient.
+	args := []string{
+		"luksFormat",
+		"--type", "luks2",
+		"--batch-mode",
+		"--pbkdf", "pbkdf2",
+		"--pbkdf-force-iterations", "1000",
+		"--offset", strconv.Itoa(luksHeaderSize / 512),
+		"--key-file", "-",
+		devPath,
+	}
+
+	err := shared.RunCommandWithFds(context.TODO(), bytes.NewReader(key), nil, "cryptsetup", args...)
+	if err != nil {
+		return fmt.Errorf("Failed formatting LUKS device %q: %w", devPath, err)
+	}
+
+	return nil
+}
+
+// luksOpen unlocks the LUKS device at devPath if not already unlocked and returns the path of the unlocked device.
+// The unlocked device is named after id, which identifies the volume as devPath may not be stable.
+func luksOpen(devPath string, id string, key []byte, readOnly bool) (string, error) {
+	mapperPath := luksMapperPath(id)
+	if shared.PathExists(mapperPath) {
+		return mapperPath, nil
+	}
+
+	args := []string{"open", "--type", "luks2", "--allow-discards", "--key-file", "-"}
+	if readOnly {
+		args = append(args, "--readonly")
+	}
+
+	args = append(args, devPath, luksMapperName(id))
+
+	err := shared.RunCommandWithFds(context.TODO(), bytes.NewReader(key), nil, "cryptsetup", args...)
+	if err != nil {
+		return "", fmt.Errorf("Failed unlocking LUKS device %q: %w", devPath, err)
+	}
+
+	logger.Debug("Unlocked LUKS device", logger.Ctx{"dev": devPath, "mapper": mapperPath})
+
+	return mapperPath, nil
+}
+
+// luksClose locks the LUKS device identified by id if unlocked.
+func luksClose(id string) error {
+	mapperPath := luksMapperPath(id)
+	if !shared.PathExists(mapperPath) {
+		return nil
+	}
+
+	// Keep trying to close a few times in case the device is still being flushed.
+	var err error
+	for range 20 {
+		_, err = shared.RunCommand(context.TODO(), "cryptsetup", "close", luksMapperName(id))
+		if err == nil {
+			break
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed locking LUKS device %q: %w", mapperPath, err)
+	}
+
+	logger.Debug("Locked LUKS device", logger.Ctx{"mapper": mapperPath})
+
+	return nil
+}
+
+// luksResize grows the unlocked LUKS device identified by id to the size of its backing device.
+func luksResize(id string, key []byte) error {
+	mapperPath := luksMapperPath(id)
+	if !shared.PathExists(mapperPath) {
+		return nil
+	}
+
+	err := shared.RunCommandWithFds(context.TODO(), bytes.NewReader(key), nil, "cryptsetup", "resize", "--key-file", "-", luksMapperName(id))
+	if err != nil {
+		return fmt.Errorf("Failed resizing LUKS device %q: %w", mapperPath, err)
+	}
+
+	return nil
+}
+
+// volumeEncryptionKey returns the key of an encrypted volume.
+func (d *common) volumeEncryptionKey(vol Volume) ([]byte, error) {
+	wrappedKey := vol.config["volatile.encryption.key"]
+	if wrappedKey == "" {
+		return nil, fmt.Errorf("Encrypted volume %q has no encryption key", vol.name)
+	}
+
+	key, err := keystore.UnwrapKey(d.state, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting encryption key of volume %q: %w", vol.name, err)
+	}
+
+	return key, nil
+}
+
+// encryptableVolume returns whether the volume can be encrypted.
+// Only instance and custom volumes can be encrypted, image and ISO volumes cannot.
+func encryptableVolume(vol Volume) bool {
+	if vol.contentType == ContentTypeISO {
+		return false
+	}
+
+	return vol.volType == VolumeTypeContainer || vol.volType == VolumeTypeVM || vol.volType == VolumeTypeCustom
+}
+
+// sameEncryptionKey returns whether both volumes are unencrypted or encrypted with the same key.
+// Copying the raw content of a volume is only possible when this is the case.
+func sameEncryptionKey(vol Volume, srcVol Volume) bool {
+	if !vol.IsEncrypted() && !srcVol.IsEncrypted() {
+		return true
+	}
+
+	return vol.IsEncrypted() && srcVol.IsEncrypted() && vol.config["volatile.encryption.key"] == srcVol.config["volatile.encryption.key"]
+}
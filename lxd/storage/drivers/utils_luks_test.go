@@ -0,0 +1,51 @@
+package drivers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLuksMapperName(t *testing.T) {
+	name := luksMapperName("/dev/vg/custom_default_" + strings.Repeat("a", 200))
+	assert.Len(t, name, len("lxd-luks-")+32)
+	assert.Equal(t, "/dev/mapper/"+name, luksMapperPath("/dev/vg/custom_default_"+strings.Repeat("a", 200)))
+
+	// Each device gets its own name.
+	assert.NotEqual(t, luksMapperName("/dev/vg/custom_default_vol1"), luksMapperName("/dev/vg/custom_default_vol2"))
+}
+
+func TestEncryptableVolume(t *testing.T) {
+	tests := []struct {
+		volType     VolumeType
+		contentType ContentType
+		want        bool
+	}{
+		{VolumeTypeContainer, ContentTypeFS, true},
+		{VolumeTypeVM, ContentTypeBlock, true},
+		{VolumeTypeCustom, ContentTypeFS, true},
+		{VolumeTypeCustom, ContentTypeBlock, true},
+		{VolumeTypeCustom, ContentTypeISO, false},
+		{VolumeTypeImage, ContentTypeBlock, false},
+		{VolumeTypeBucket, ContentTypeFS, false},
+	}
+
+	for _, tc := range tests {
+		vol := Volume{volType: tc.volType, contentType: tc.contentType}
+		assert.Equal(t, tc.want, encryptableVolume(vol), "%s/%s", tc.volType, tc.contentType)
+	}
+}
+
+func TestSameEncryptionKey(t *testing.T) {
+	plain := Volume{config: map[string]string{}}
+	encrypted := Volume{config: map[string]string{"security.encrypted": "true", "volatile.encryption.key": "key1"}}
+	sameKey := Volume{config: map[string]string{"security.encrypted": "true", "volatile.encryption.key": "key1"}}
+	otherKey := Volume{config: map[string]string{"security.encrypted": "true", "volatile.encryption.key": "key2"}}
+
+	assert.True(t, sameEncryptionKey(plain, plain))
+	assert.True(t, sameEncryptionKey(encrypted, sameKey))
+	assert.False(t, sameEncryptionKey(encrypted, otherKey))
+	assert.False(t, sameEncryptionKey(plain, encrypted))
+	assert.False(t, sameEncryptionKey(encrypted, plain))
+}
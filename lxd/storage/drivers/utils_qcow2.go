@@ -0,0 +1,264 @@
+package drivers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+)
+
+// DiskImageFormatRaw is the format of raw disk image files.
+const DiskImageFormatRaw = "raw"
+
+// DiskImageFormatQcow2 is the format of qcow2 disk image files.
+const DiskImageFormatQcow2 = "qcow2"
+
+// qcow2MaxBackingChainLength limits how many backing files are followed when resolving a qcow2 disk image.
+const qcow2MaxBackingChainLength = 256
+
+// DiskImage represents a single file in the backing chain of a disk image.
+type DiskImage struct {
+	// Path is the absolute path of the disk image file.
+	Path string
+
+	// Format is the format of the disk image file (either DiskImageFormatRaw or DiskImageFormatQcow2).
+	Format string
+}
+
+// qcow2ImageInfo represents the subset of the "qemu-img info" output used by LXD.
+type qcow2ImageInfo struct {
+	Format                string `json:"format"`
+	VirtualSize           int64  `json:"virtual-size"`
+	BackingFilename       string `json:"backing-filename"`
+	BackingFilenameFormat string `json:"backing-filename-format"`
+}
+
+// DiskImageFormat returns the format of the disk image file at the given path.
+// The format is derived from the file name only. It is never probed from the file content as the content of
+// raw disk images is controlled by the guest, which could otherwise make LXD interpret it as a qcow2 image.
+func DiskImageFormat(path string) string {
+	if strings.HasSuffix(path, "."+DiskImageFormatQcow2) {
+		return DiskImageFormatQcow2
+	}
+
+	return DiskImageFormatRaw
+}
+
+// DiskImageBackingChain returns the disk image at the given path followed by all of its backing files.
+// Only qcow2 disk images can have backing files, and a backing file is only followed if its format is recorded
+// in the image header and matches the format expected from the backing file name.
+func DiskImageBackingChain(path string) ([]DiskImage, error) {
+	chain := []DiskImage{}
+	seen := map[string]bool{}
+
+	for {
+		if len(chain) >= qcow2MaxBackingChainLength {
+			return nil, fmt.Errorf("Backing chain of %q is too long", chain[0].Path)
+		}
+
+		if seen[path] {
+			return nil, fmt.Errorf("Backing chain of %q contains a loop", chain[0].Path)
+		}
+
+		seen[path] = true
+
+		image := DiskImage{Path: path, Format: DiskImageFormat(path)}
+		chain = append(chain, image)
+
+		if image.Format != DiskImageFormatQcow2 {
+			return chain, nil
+		}
+
+		info, err := qcow2Info(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.BackingFilename == "" {
+			return chain, nil
+		}
+
+		backingPath, err := qcow2BackingPath(path, info)
+		if err != nil {
+			return nil, err
+		}
+
+		path = backingPath
+	}
+}
+
+// RawDiskImage returns the path to a raw version of the disk image at the given path.
+// For raw disk images the path itself is returned. For qcow2 disk images the image (including its backing
+// chain) is converted into a temporary sparse raw file inside tmpDir. The returned cleanup function must be
+// called once the raw file is not needed anymore.
+func RawDiskImage(path string, tmpDir string) (string, func(), error) {
+	if DiskImageFormat(path) == DiskImageFormatRaw {
+		return path, func() {}, nil
+	}
+
+	f, err := os.CreateTemp(tmpDir, ".lxd-disk-*.img")
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed creating temporary raw disk image: %w", err)
+	}
+
+	rawPath := f.Name()
+	_ = f.Close()
+
+	cleanup := func() { _ = os.Remove(rawPath) }
+
+	err = convertDiskImage(path, rawPath)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return rawPath, cleanup, nil
+}
+
+// qcow2Info returns the header information of the qcow2 disk image at the given path.
+// The backing chain is not opened and the image may be in use by a running instance.
+func qcow2Info(path string) (*qcow2ImageInfo, error) {
+	out, err := shared.RunCommand(context.TODO(), "qemu-img", "info", "-f", DiskImageFormatQcow2, "-U", "--output=json", path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading qcow2 disk image %q: %w", path, err)
+	}
+
+	info := qcow2ImageInfo{}
+	err = json.Unmarshal([]byte(out), &info)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing qcow2 disk image information of %q: %w", path, err)
+	}
+
+	return &info, nil
+}
+
+// qcow2BackingPath returns the absolute path of the backing file of the qcow2 disk image at the given path.
+// LXD only ever records relative backing files along with their format, anything else is rejected.
+func qcow2BackingPath(path string, info *qcow2ImageInfo) (string, error) {
+	if filepath.IsAbs(info.BackingFilename) || strings.Contains(info.BackingFilename, ":") {
+		return "", fmt.Errorf("Disk image %q has an unsupported backing file %q", path, info.BackingFilename)
+	}
+
+	backingPath := filepath.Join(filepath.Dir(path), info.BackingFilename)
+	if info.BackingFilenameFormat != DiskImageFormat(backingPath) {
+		return "", fmt.Errorf("Disk image %q has a backing file %q with unexpected format %q", path, info.BackingFilename, info.BackingFilenameFormat)
+	}
+
+	return backingPath, nil
+}
+
+// qcow2RelativeBackingPath returns the backing file path to record in the qcow2 disk image at the given path.
+func qcow2RelativeBackingPath(path string, backingPath string) (string, error) {
+	return filepath.Rel(filepath.Dir(path), backingPath)
+}
+
+// qcow2Create creates a qcow2 disk image of the given size. If backingPath is set, the new image uses it as its
+// backing file and the size may be zero to inherit the size of the backing file.
+func qcow2Create(path string, backingPath string, sizeBytes int64) error {
+	args := []string{"create", "-q", "-f", DiskImageFormatQcow2}
+
+	if backingPath != "" {
+		relPath, err := qcow2RelativeBackingPath(path, backingPath)
+		if err != nil {
+			return err
+		}
+
+		args = append(args, "-b", relPath, "-F", DiskImageFormat(backingPath))
+	}
+
+	args = append(args, path)
+
+	if sizeBytes > 0 {
+		args = append(args, strconv.FormatInt(sizeBytes, 10))
+	}
+
+	_, err := shared.RunCommand(context.TODO(), "qemu-img", args...)
+	if err != nil {
+		return fmt.Errorf("Failed creating qcow2 disk image %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// qcow2Resize changes the virtual size of the qcow2 disk image at the given path.
+func qcow2Resize(path string, sizeBytes int64, allowShrink bool) error {
+	args := []string{"resize", "-q", "-f", DiskImageFormatQcow2}
+
+	if allowShrink {
+		args = append(args, "--shrink")
+	}
+
+	args = append(args, path, strconv.FormatInt(sizeBytes, 10))
+
+	_, err := shared.RunCommand(context.TODO(), "qemu-img", args...)
+	if err != nil {
+		return fmt.Errorf("Failed resizing qcow2 disk image %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// qcow2Rebase changes the backing file of the qcow2 disk image at the given path.
+// In safe mode the data that differs between the old and the new backing file is copied into the image first,
+// which also allows removing the backing file entirely by passing an empty backingPath. In unsafe mode only the
+// recorded backing file is changed, which is used when the backing file itself was moved.
+func qcow2Rebase(path string, backingPath string, unsafe bool) error {
+	args := []string{"rebase", "-q", "-f", DiskImageFormatQcow2}
+
+	if unsafe {
+		args = append(args, "-u")
+	}
+
+	if backingPath != "" {
+		relPath, err := qcow2RelativeBackingPath(path, backingPath)
+		if err != nil {
+			return err
+		}
+
+		args = append(args, "-b", relPath, "-F", DiskImageFormat(backingPath))
+	} else {
+		args = append(args, "-b", "")
+	}
+
+	args = append(args, path)
+
+	_, err := shared.RunCommand(context.TODO(), "qemu-img", args...)
+	if err != nil {
+		return fmt.Errorf("Failed rebasing qcow2 disk image %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// convertDiskImage converts the disk image at srcPath (including its backing chain) into a standalone disk image
+// at dstPath. The formats of both images are derived from their file names. The image is written to a temporary
+// file first and then moved into place, replacing any existing file at dstPath.
+func convertDiskImage(srcPath string, dstPath string) error {
+	// Validate the backing chain before letting qemu-img open it.
+	_, err := DiskImageBackingChain(srcPath)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := filepath.Join(filepath.Dir(dstPath), "."+filepath.Base(dstPath)+".tmp")
+	_ = os.Remove(tmpPath)
+
+	_, err = shared.RunCommand(context.TODO(), "nice", "-n19", "qemu-img", "convert", "-U", "-f", DiskImageFormat(srcPath), "-O", DiskImageFormat(dstPath), "-t", "writeback", srcPath, tmpPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("Failed converting disk image %q to %q: %w", srcPath, dstPath, err)
+	}
+
+	err = os.Rename(tmpPath, dstPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("Failed moving converted disk image to %q: %w", dstPath, err)
+	}
+
+	return nil
+}
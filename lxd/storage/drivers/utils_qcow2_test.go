@@ -0,0 +1,88 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskImageFormat(t *testing.T) {
+	assert.Equal(t, DiskImageFormatRaw, DiskImageFormat("/pool/virtual-machines/vm1/root.img"))
+	assert.Equal(t, DiskImageFormatRaw, DiskImageFormat("/pool/virtual-machines/vm1/base.img"))
+	assert.Equal(t, DiskImageFormatQcow2, DiskImageFormat("/pool/virtual-machines/vm1/root.qcow2"))
+	assert.Equal(t, DiskImageFormatQcow2, DiskImageFormat("/pool/virtual-machines/vm1/layer-1234.qcow2"))
+
+	// The format is never derived from anything but the extension.
+	assert.Equal(t, DiskImageFormatRaw, DiskImageFormat("/pool/virtual-machines/vm1/root.qcow2.img"))
+	assert.Equal(t, DiskImageFormatRaw, DiskImageFormat("/pool/virtual-machines/qcow2"))
+}
+
+func TestQcow2BackingPath(t *testing.T) {
+	diskPath := "/pool/virtual-machines-snapshots/vm1/snap0/root.qcow2"
+	layerPath := "/pool/virtual-machines/vm1/layer-1234.qcow2"
+
+	relPath, err := qcow2RelativeBackingPath(diskPath, layerPath)
+	require.NoError(t, err)
+	assert.Equal(t, "../../../virtual-machines/vm1/layer-1234.qcow2", relPath)
+
+	tests := []struct {
+		name    string
+		info    qcow2ImageInfo
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Relative qcow2 layer",
+			info: qcow2ImageInfo{BackingFilename: relPath, BackingFilenameFormat: DiskImageFormatQcow2},
+			want: layerPath,
+		},
+		{
+			name: "Relative raw base",
+			info: qcow2ImageInfo{BackingFilename: "../../../virtual-machines/vm1/base.img", BackingFilenameFormat: DiskImageFormatRaw},
+			want: "/pool/virtual-machines/vm1/base.img",
+		},
+		{
+			name:    "Absolute path",
+			info:    qcow2ImageInfo{BackingFilename: layerPath, BackingFilenameFormat: DiskImageFormatQcow2},
+			wantErr: true,
+		},
+		{
+			name:    "Protocol",
+			info:    qcow2ImageInfo{BackingFilename: "nbd:localhost:10809", BackingFilenameFormat: DiskImageFormatRaw},
+			wantErr: true,
+		},
+		{
+			name:    "Missing format",
+			info:    qcow2ImageInfo{BackingFilename: relPath},
+			wantErr: true,
+		},
+		{
+			name:    "Format mismatch",
+			info:    qcow2ImageInfo{BackingFilename: "../../../virtual-machines/vm1/base.img", BackingFilenameFormat: DiskImageFormatQcow2},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backingPath, err := qcow2BackingPath(diskPath, &tt.info)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, backingPath)
+		})
+	}
+}
+
+func TestGenericVFSDiskFilesRsyncArgs(t *testing.T) {
+	assert.Equal(t, []string{
+		"--exclude", "root.img",
+		"--exclude", "root.qcow2",
+		"--exclude", "base.img",
+		"--exclude", "layer-*.qcow2",
+	}, genericVFSDiskFilesRsyncArgs())
+}
@@ -84,6 +84,7 @@ type baseDirectory struct {
 
 // BaseDirectories maps volume types to the expected directories.
 var BaseDirectories = map[VolumeType]baseDirectory{
+	VolumeTypeBucket:    {Paths: []string{"buckets"}, Mode: 0o700},
 	VolumeTypeContainer: {Paths: []string{"containers", "containers-snapshots"}, Mode: 0o711}, // Containers may be run as non-root, so 0700 won't work, however as containers have their own sub-directory with correct ownership that is 0100 this is OK.
 	VolumeTypeCustom:    {Paths: []string{"custom", "custom-snapshots"}, Mode: 0o700},
 	VolumeTypeImage:     {Paths: []string{"images"}, Mode: 0o700},
@@ -401,6 +402,11 @@ func (v Volume) IsCustomBlock() bool {
 	return (v.volType == VolumeTypeCustom && v.contentType == ContentTypeBlock)
 }
 
+// IsEncrypted returns true if the volume's block device is encrypted.
+func (v Volume) IsEncrypted() bool {
+	return shared.IsTrue(v.config["security.encrypted"])
+}
+
 // NewVMBlockFilesystemVolume returns a copy of the volume with the content type set to ContentTypeFS and the
 // config "size" property set to "size.state" or DefaultVMBlockFilesystemSize if not set.
 func (v Volume) NewVMBlockFilesystemVolume() Volume {
@@ -0,0 +1,222 @@
+package keystore
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/state"
+)
+
+// KeySize is the size in bytes of volume encryption keys.
+const KeySize = 64
+
+// ErrKeyUnwrap is returned when a volume key cannot be unwrapped with the server or cluster key.
+// This is the case for keys wrapped by another server or cluster, for example in imported backups.
+var ErrKeyUnwrap = errors.New("Volume encryption key was not wrapped by this server")
+
+// serverKeyFile is the name of the file holding the server key, relative to the LXD directory.
+const serverKeyFile = "storage-encryption.key"
+
+// clusterKeyPrefix marks volume keys wrapped with the cluster key rather than the server key.
+const clusterKeyPrefix = "cluster:"
+
+// serverKey caches the server key as it never changes once created.
+var serverKey []byte
+var serverKeyPath string
+var serverKeyMu sync.Mutex
+
+// NewKey returns a new random volume encryption key.
+func NewKey() []byte {
+	key := make([]byte, KeySize)
+	_, _ = rand.Read(key)
+	return key
+}
+
+// newAEAD returns the AES-256-GCM cipher derived from the server key.
+func newAEAD(serverKey []byte) (cipher.AEAD, error) {
+	if len(serverKey) == 0 {
+		return nil, errors.New("Server key is empty")
+	}
+
+	aesKey := sha256.Sum256(serverKey)
+	block, err := aes.NewCipher(aesKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Wrap encrypts the volume key with the server key and returns it base64 encoded.
+func Wrap(serverKey []byte, key []byte) (string, error) {
+	aead, err := newAEAD(serverKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, key, nil)), nil
+}
+
+// Unwrap decrypts a volume key wrapped with Wrap.
+// Returns ErrKeyUnwrap if the key was wrapped with another server key.
+func Unwrap(serverKey []byte, wrappedKey string) ([]byte, error) {
+	aead, err := newAEAD(serverKey)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid volume encryption key: %w", err)
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("Invalid volume encryption key: Too short")
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	key, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrKeyUnwrap
+	}
+
+	return key, nil
+}
+
+// ServerKey returns the key of this server wrapping the volume encryption keys, creating it if needed.
+// The key is stored in a file that only root can read, outside of the database, and is specific to each
+// cluster member.
+func ServerKey(s *state.State) ([]byte, error) {
+	serverKeyMu.Lock()
+	defer serverKeyMu.Unlock()
+
+	path := filepath.Join(s.OS.VarDir, serverKeyFile)
+	if serverKey != nil && serverKeyPath == path {
+		return serverKey, nil
+	}
+
+	key, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("Failed reading storage encryption key: %w", err)
+	}
+
+	if err != nil {
+		key, err = createServerKey(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("Invalid storage encryption key %q: Expected %d bytes, got %d", path, KeySize, len(key))
+	}
+
+	serverKey = key
+	serverKeyPath = path
+	return serverKey, nil
+}
+
+// createServerKey generates a new server key and writes it to path.
+// The key is written to a temporary file first so that an interrupted write never leaves a truncated key behind.
+func createServerKey(path string) ([]byte, error) {
+	key := NewKey()
+
+	tmpPath := path + ".tmp"
+	err := os.WriteFile(tmpPath, key, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed writing storage encryption key: %w", err)
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, fmt.Errorf("Failed writing storage encryption key: %w", err)
+	}
+
+	return key, nil
+}
+
+// WrapKey encrypts the volume key with the server key.
+func WrapKey(s *state.State, key []byte) (string, error) {
+	serverKey, err := ServerKey(s)
+	if err != nil {
+		return "", err
+	}
+
+	return Wrap(serverKey, key)
+}
+
+// UnwrapKey decrypts a volume key wrapped with either the server key or the cluster key.
+func UnwrapKey(s *state.State, wrappedKey string) ([]byte, error) {
+	clusterWrappedKey, found := strings.CutPrefix(wrappedKey, clusterKeyPrefix)
+	if found {
+		clusterKey, err := ClusterKey(s)
+		if err != nil {
+			return nil, err
+		}
+
+		return Unwrap(clusterKey, clusterWrappedKey)
+	}
+
+	serverKey, err := ServerKey(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return Unwrap(serverKey, wrappedKey)
+}
+
+// ClusterKey returns the key wrapping the volume encryption keys of remote storage pools, creating it if needed.
+// Unlike the server key, it is stored in the cluster database so that every cluster member can unlock the volumes.
+func ClusterKey(s *state.State) ([]byte, error) {
+	var key []byte
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		key, err = cluster.GetStorageEncryptionKey(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// WrapClusterKey encrypts the volume key with the cluster key.
+func WrapClusterKey(s *state.State, key []byte) (string, error) {
+	clusterKey, err := ClusterKey(s)
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := Wrap(clusterKey, key)
+	if err != nil {
+		return "", err
+	}
+
+	return clusterKeyPrefix + wrappedKey, nil
+}
+
+// IsClusterWrapped returns whether the volume key was wrapped with the cluster key.
+func IsClusterWrapped(wrappedKey string) bool {
+	return strings.HasPrefix(wrappedKey, clusterKeyPrefix)
+}
@@ -0,0 +1,85 @@
+package keystore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/sys"
+)
+
+func TestWrapUnwrap(t *testing.T) {
+	serverKey := NewKey()
+	key := NewKey()
+	require.Len(t, key, KeySize)
+	assert.NotEqual(t, serverKey, key)
+
+	wrappedKey, err := Wrap(serverKey, key)
+	require.NoError(t, err)
+
+	// Wrapping is randomised.
+	otherWrappedKey, err := Wrap(serverKey, key)
+	require.NoError(t, err)
+	assert.NotEqual(t, wrappedKey, otherWrappedKey)
+
+	unwrappedKey, err := Unwrap(serverKey, wrappedKey)
+	require.NoError(t, err)
+	assert.Equal(t, key, unwrappedKey)
+
+	// Keys wrapped by another server cannot be unwrapped.
+	_, err = Unwrap(NewKey(), wrappedKey)
+	assert.ErrorIs(t, err, ErrKeyUnwrap)
+
+	// Malformed keys are rejected.
+	_, err = Unwrap(serverKey, "not base64")
+	assert.Error(t, err)
+
+	_, err = Unwrap(serverKey, "Zm9v")
+	assert.Error(t, err)
+
+	_, err = Wrap(nil, key)
+	assert.Error(t, err)
+}
+
+func TestServerKey(t *testing.T) {
+	s := &state.State{OS: &sys.OS{VarDir: t.TempDir()}}
+
+	// The key is created on first use and only readable by root.
+	key, err := ServerKey(s)
+	require.NoError(t, err)
+	require.Len(t, key, KeySize)
+
+	info, err := os.Stat(filepath.Join(s.OS.VarDir, serverKeyFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Keys wrapped by this server can be unwrapped.
+	wrappedKey, err := WrapKey(s, []byte("volume key"))
+	require.NoError(t, err)
+
+	volKey, err := UnwrapKey(s, wrappedKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("volume key"), volKey)
+	assert.False(t, IsClusterWrapped(wrappedKey))
+
+	// Another server has its own key.
+	otherState := &state.State{OS: &sys.OS{VarDir: t.TempDir()}}
+	_, err = UnwrapKey(otherState, wrappedKey)
+	assert.ErrorIs(t, err, ErrKeyUnwrap)
+
+	// The key is loaded from disk after a restart.
+	serverKey = nil
+	sameKey, err := ServerKey(s)
+	require.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+
+	// Truncated keys are rejected.
+	serverKey = nil
+	require.NoError(t, os.WriteFile(filepath.Join(s.OS.VarDir, serverKeyFile), key[:10], 0600))
+	_, err = ServerKey(s)
+	assert.Error(t, err)
+}
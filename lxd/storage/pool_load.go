@@ -18,6 +18,10 @@ import (
 // PoolIDTemporary is used to indicate a temporary pool instance that is not in the database.
 const PoolIDTemporary = -1
 
+// bucketVolIDOffset is added to the bucket IDs returned as volume IDs so they don't collide with
+// the IDs of storage volumes (some drivers derive quota project IDs from the volume ID).
+const bucketVolIDOffset = 1 << 30
+
 // volIDFuncMake returns a function that can be supplied to the underlying storage drivers allowing
 // them to lookup the volume ID for a specific volume type and volume name. This function is tied
 // to the Pool ID that it is generated for, meaning the storage drivers do not need to know the ID
@@ -25,6 +29,23 @@ const PoolIDTemporary = -1
 func volIDFuncMake(state *state.State, poolID int64) func(volType drivers.VolumeType, volName string) (int64, error) {
 	// Return a function to retrieve a volume ID for a volume Name for use in driver.
 	return func(volType drivers.VolumeType, volName string) (int64, error) {
+		// Buckets on local pools are backed by a volume without a storage volume record.
+		if volType == drivers.VolumeTypeBucket {
+			projectName, bucketName := project.StorageVolumeParts(volName)
+
+			var bucket *db.StorageBucket
+			err := state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				var err error
+				bucket, err = tx.GetStoragePoolBucket(ctx, poolID, projectName, true, bucketName)
+				return err
+			})
+			if err != nil {
+				return -1, err
+			}
+
+			return bucketVolIDOffset + bucket.ID, nil
+		}
+
 		volTypeID, err := VolumeTypeToDBType(volType)
 		if err != nil {
 			return -1, err
@@ -0,0 +1,501 @@
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	signV4Algorithm      = "AWS4-HMAC-SHA256"
+	signV4ChunkAlgorithm = "AWS4-HMAC-SHA256-PAYLOAD"
+	signV4Terminator     = "aws4_request"
+	signV4Service        = "s3"
+
+	amzDateFormat  = "20060102T150405Z"
+	amzScopeFormat = "20060102"
+
+	emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	payloadUnsigned                 = "UNSIGNED-PAYLOAD"
+	payloadStreamingSigned          = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	payloadStreamingSignedTrailer   = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER"
+	payloadStreamingUnsignedTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+
+	// maxPresignExpiry is the maximum validity of a presigned URL.
+	maxPresignExpiry = 7 * 24 * time.Hour
+
+	// maxChunkSize is the maximum size of a chunk of a streaming upload.
+	maxChunkSize = 16 * 1024 * 1024
+)
+
+// authInfo holds the AWS Signature Version 4 details of a request.
+type authInfo struct {
+	accessKey     string
+	date          time.Time
+	region        string
+	signedHeaders []string
+	signature     string
+	payloadHash   string
+	expires       time.Duration // Only set for presigned requests.
+	presigned     bool
+
+	signingKey []byte // Set once the request has been verified.
+}
+
+// scope returns the credential scope of the request.
+func (a *authInfo) scope() string {
+	return a.date.Format(amzScopeFormat) + "/" + a.region + "/" + signV4Service + "/" + signV4Terminator
+}
+
+// parseCredential parses the value of a SigV4 credential and returns the access key and region.
+func parseCredential(credential string, date time.Time) (string, string, *Error) {
+	parts := strings.Split(credential, "/")
+	if len(parts) != 5 || parts[0] == "" {
+		return "", "", newError(http.StatusBadRequest, "AuthorizationHeaderMalformed", "The credential is malformed")
+	}
+
+	if parts[1] != date.Format(amzScopeFormat) || parts[3] != signV4Service || parts[4] != signV4Terminator {
+		return "", "", newError(http.StatusBadRequest, "AuthorizationHeaderMalformed", "The credential scope is invalid")
+	}
+
+	return parts[0], parts[2], nil
+}
+
+// parseAuth extracts the AWS Signature Version 4 details from either the Authorization header
+// or the query string of a presigned request.
+func parseAuth(r *http.Request) (*authInfo, *Error) {
+	query := r.URL.Query()
+
+	if query.Has("X-Amz-Algorithm") {
+		return parsePresignedAuth(query)
+	}
+
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, errAccessDenied
+	}
+
+	fields, ok := strings.CutPrefix(header, signV4Algorithm+" ")
+	if !ok {
+		return nil, errUnsupportedAuth
+	}
+
+	values := map[string]string{}
+	for field := range strings.SplitSeq(fields, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		values[key] = value
+	}
+
+	info := &authInfo{
+		signature:   values["Signature"],
+		payloadHash: r.Header.Get("X-Amz-Content-Sha256"),
+	}
+
+	if values["SignedHeaders"] != "" {
+		info.signedHeaders = strings.Split(values["SignedHeaders"], ";")
+	}
+
+	if info.payloadHash == "" {
+		return nil, errMissingSecurityHeader
+	}
+
+	var err error
+	amzDate := r.Header.Get("X-Amz-Date")
+	if amzDate != "" {
+		info.date, err = time.Parse(amzDateFormat, amzDate)
+	} else {
+		info.date, err = http.ParseTime(r.Header.Get("Date"))
+	}
+
+	if err != nil {
+		return nil, errMissingSecurityHeader
+	}
+
+	var authErr *Error
+	info.accessKey, info.region, authErr = parseCredential(values["Credential"], info.date)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return info, nil
+}
+
+// parsePresignedAuth extracts the AWS Signature Version 4 details from the query string of a presigned request.
+func parsePresignedAuth(query url.Values) (*authInfo, *Error) {
+	if query.Get("X-Amz-Algorithm") != signV4Algorithm {
+		return nil, errUnsupportedAuth
+	}
+
+	info := &authInfo{
+		signature:   query.Get("X-Amz-Signature"),
+		payloadHash: query.Get("X-Amz-Content-Sha256"),
+		presigned:   true,
+	}
+
+	if info.payloadHash == "" {
+		info.payloadHash = payloadUnsigned
+	}
+
+	if query.Get("X-Amz-SignedHeaders") != "" {
+		info.signedHeaders = strings.Split(query.Get("X-Amz-SignedHeaders"), ";")
+	}
+
+	var err error
+	info.date, err = time.Parse(amzDateFormat, query.Get("X-Amz-Date"))
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "AuthorizationQueryParametersError", "X-Amz-Date must be in the ISO8601 Long Format")
+	}
+
+	expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires < 0 || time.Duration(expires)*time.Second > maxPresignExpiry {
+		return nil, newError(http.StatusBadRequest, "AuthorizationQueryParametersError", "X-Amz-Expires must be a number of seconds between 0 and 604800")
+	}
+
+	info.expires = time.Duration(expires) * time.Second
+
+	var authErr *Error
+	info.accessKey, info.region, authErr = parseCredential(query.Get("X-Amz-Credential"), info.date)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return info, nil
+}
+
+// verify checks the signature of the request using the given secret key.
+func (a *authInfo) verify(r *http.Request, secretKey string, now time.Time) *Error {
+	if a.presigned {
+		if now.Before(a.date.Add(-maxClockSkew)) {
+			return errRequestTimeTooSkewed
+		}
+
+		if now.After(a.date.Add(a.expires)) {
+			return errRequestExpired
+		}
+	} else if now.Sub(a.date).Abs() > maxClockSkew {
+		return errRequestTimeTooSkewed
+	}
+
+	if !slices.Contains(a.signedHeaders, "host") {
+		return errAccessDenied
+	}
+
+	canonicalRequest := canonicalRequest(r, a.signedHeaders, a.payloadHash, a.presigned)
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := signV4Algorithm + "\n" + a.date.Format(amzDateFormat) + "\n" + a.scope() + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	key := signingKey(secretKey, a.date, a.region)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	if !hmac.Equal([]byte(signature), []byte(a.signature)) {
+		return errSignatureDoesNotMatch
+	}
+
+	a.signingKey = key
+
+	return nil
+}
+
+// signingKey derives the SigV4 signing key for the given secret key, date and region.
+func signingKey(secretKey string, date time.Time, region string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), date.Format(amzScopeFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, signV4Service)
+
+	return hmacSHA256(key, signV4Terminator)
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the data using the given key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalRequest returns the SigV4 canonical form of the request.
+func canonicalRequest(r *http.Request, signedHeaders []string, payloadHash string, presigned bool) string {
+	var b strings.Builder
+
+	b.WriteString(r.Method + "\n")
+	b.WriteString(canonicalURI(r.URL.Path) + "\n")
+	b.WriteString(canonicalQuery(r.URL.RawQuery, presigned) + "\n")
+
+	for _, name := range signedHeaders {
+		b.WriteString(name + ":" + canonicalHeaderValue(r, name) + "\n")
+	}
+
+	b.WriteString("\n" + strings.Join(signedHeaders, ";") + "\n")
+	b.WriteString(payloadHash)
+
+	return b.String()
+}
+
+// canonicalURI returns the SigV4 canonical form of the request path.
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return uriEncode(path, false)
+}
+
+// canonicalQuery returns the SigV4 canonical form of the query string.
+func canonicalQuery(rawQuery string, presigned bool) string {
+	var params []string
+
+	for param := range strings.SplitSeq(rawQuery, "&") {
+		if param == "" {
+			continue
+		}
+
+		rawKey, rawValue, _ := strings.Cut(param, "=")
+
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			value = rawValue
+		}
+
+		// The signature of presigned requests is not part of the signed content.
+		if presigned && key == "X-Amz-Signature" {
+			continue
+		}
+
+		params = append(params, uriEncode(key, true)+"="+uriEncode(value, true))
+	}
+
+	slices.Sort(params)
+
+	return strings.Join(params, "&")
+}
+
+// canonicalHeaderValue returns the SigV4 canonical value of the request header with the given name.
+func canonicalHeaderValue(r *http.Request, name string) string {
+	var values []string
+
+	switch name {
+	case "host":
+		values = []string{r.Host}
+	case "content-length":
+		values = r.Header.Values("Content-Length")
+		if len(values) == 0 && r.ContentLength >= 0 {
+			values = []string{strconv.FormatInt(r.ContentLength, 10)}
+		}
+
+	case "transfer-encoding":
+		values = r.TransferEncoding
+	default:
+		values = r.Header.Values(name)
+	}
+
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		normalized = append(normalized, strings.Join(strings.Fields(value), " "))
+	}
+
+	return strings.Join(normalized, ",")
+}
+
+// uriEncode encodes the given string as specified by SigV4, escaping every byte other than
+// the unreserved characters and, unless encodeSlash is set, the slashes.
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+
+	var b strings.Builder
+
+	for i := range len(s) {
+		c := s[i]
+
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&0x0f])
+		}
+	}
+
+	return b.String()
+}
+
+// payloadReader returns a reader of the request body that checks it against the signed payload.
+// The returned function must be called once the body has been fully read to verify its integrity.
+func payloadReader(r *http.Request, auth *authInfo) (io.Reader, func() *Error) {
+	switch auth.payloadHash {
+	case payloadUnsigned:
+		return r.Body, func() *Error { return nil }
+	case payloadStreamingSigned, payloadStreamingSignedTrailer, payloadStreamingUnsignedTrailer:
+		reader := &chunkedReader{
+			r:      bufio.NewReader(r.Body),
+			signed: auth.payloadHash != payloadStreamingUnsignedTrailer,
+			auth:   auth,
+			prev:   auth.signature,
+		}
+
+		return reader, func() *Error {
+			if !reader.done {
+				return errIncompleteBody
+			}
+
+			return nil
+		}
+	}
+
+	h := sha256.New()
+
+	return io.TeeReader(r.Body, h), func() *Error {
+		if hex.EncodeToString(h.Sum(nil)) != auth.payloadHash {
+			return errXAmzContentSHA256
+		}
+
+		return nil
+	}
+}
+
+// decodedContentLength returns the length of the payload of the request, or -1 if unknown.
+func decodedContentLength(r *http.Request, auth *authInfo) int64 {
+	switch auth.payloadHash {
+	case payloadStreamingSigned, payloadStreamingSignedTrailer, payloadStreamingUnsignedTrailer:
+		length, err := strconv.ParseInt(r.Header.Get("X-Amz-Decoded-Content-Length"), 10, 64)
+		if err != nil {
+			return -1
+		}
+
+		return length
+	}
+
+	return r.ContentLength
+}
+
+// chunkedReader decodes the aws-chunked content encoding used by streaming uploads,
+// verifying the signature of each chunk when the payload is signed.
+type chunkedReader struct {
+	r      *bufio.Reader
+	signed bool
+	auth   *authInfo
+	prev   string // Signature of the previous chunk.
+
+	buf  []byte
+	hash hash.Hash
+	err  error
+	done bool
+}
+
+// Read implements io.Reader.
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	for len(c.buf) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+
+		c.err = c.readChunk()
+	}
+
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+
+	return n, nil
+}
+
+// readLine reads a CRLF terminated line.
+func (c *chunkedReader) readLine() (string, error) {
+	line, err := c.r.ReadSlice('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, bufio.ErrBufferFull) {
+			return "", errIncompleteBody
+		}
+
+		return "", err
+	}
+
+	return string(bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))), nil
+}
+
+// readChunk reads the next chunk into the buffer. It returns io.EOF after the last chunk.
+func (c *chunkedReader) readChunk() error {
+	header, err := c.readLine()
+	if err != nil {
+		return err
+	}
+
+	sizeField, extension, _ := strings.Cut(header, ";")
+
+	size, err := strconv.ParseInt(sizeField, 16, 64)
+	if err != nil || size < 0 || size > maxChunkSize {
+		return errIncompleteBody
+	}
+
+	data := make([]byte, size)
+	_, err = io.ReadFull(c.r, data)
+	if err != nil {
+		return errIncompleteBody
+	}
+
+	if c.signed {
+		signature, ok := strings.CutPrefix(extension, "chunk-signature=")
+		if !ok {
+			return errSignatureDoesNotMatch
+		}
+
+		if c.hash == nil {
+			c.hash = sha256.New()
+		}
+
+		c.hash.Reset()
+		c.hash.Write(data)
+
+		stringToSign := signV4ChunkAlgorithm + "\n" + c.auth.date.Format(amzDateFormat) + "\n" + c.auth.scope() + "\n" + c.prev + "\n" + emptySHA256 + "\n" + hex.EncodeToString(c.hash.Sum(nil))
+		expected := hex.EncodeToString(hmacSHA256(c.auth.signingKey, stringToSign))
+		if !hmac.Equal([]byte(expected), []byte(signature)) {
+			return errSignatureDoesNotMatch
+		}
+
+		c.prev = signature
+	}
+
+	if size == 0 {
+		// Skip the trailing headers (if any) up to the final empty line.
+		for {
+			line, err := c.readLine()
+			if err != nil || line == "" {
+				break
+			}
+		}
+
+		c.done = true
+
+		return io.EOF
+	}
+
+	// The chunk data is terminated by CRLF.
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+
+	if line != "" {
+		return errIncompleteBody
+	}
+
+	c.buf = data
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultPartSize is the initial size of the parts of a multipart upload.
+const defaultPartSize = 16 * 1024 * 1024
+
+// maxPartSize is the largest size of a part of a multipart upload.
+const maxPartSize = 5 * 1024 * 1024 * 1024
+
+// partSizeGrowthInterval is the number of parts after which the part size is doubled, allowing
+// uploads of unknown size to exceed the 10000 parts limit multiplied by the initial part size.
+const partSizeGrowthInterval = 1000
+
+// Client uploads and downloads objects to and from an S3 compatible endpoint.
+//
+// Requests are path-style and authenticated using AWS Signature Version 4.
+type Client struct {
+	endpoint  *url.URL
+	region    string
+	accessKey string
+	secretKey string
+
+	httpClient *http.Client
+	partSize   int
+	now        func() time.Time
+}
+
+// NewClient returns a client for the S3 endpoint at the given URL.
+// If httpClient is nil, http.DefaultClient is used.
+func NewClient(endpoint string, region string, accessKey string, secretKey string, httpClient *http.Client) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid S3 endpoint %q: %w", endpoint, err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("Invalid S3 endpoint %q: Must be an HTTP or HTTPS URL", endpoint)
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		endpoint:   u,
+		region:     region,
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+		httpClient: httpClient,
+		partSize:   defaultPartSize,
+		now:        time.Now,
+	}, nil
+}
+
+// ParseURL parses an object URL of the form s3://BUCKET/KEY and returns the bucket and key.
+func ParseURL(objectURL string) (string, string, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", "", fmt.Errorf("Invalid object URL %q: %w", objectURL, err)
+	}
+
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Scheme != "s3" || u.Host == "" || key == "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", "", fmt.Errorf("Invalid object URL %q: Must be of the form s3://BUCKET/KEY", objectURL)
+	}
+
+	if len(key) > maxKeyLength {
+		return "", "", fmt.Errorf("Invalid object URL %q: Key is too long", objectURL)
+	}
+
+	return u.Host, key, nil
+}
+
+// ObjectInfo holds the details of an uploaded object.
+type ObjectInfo struct {
+	Size   int64
+	ETag   string
+	SHA256 string
+}
+
+// Upload streams the content of the reader to the object with the given key using a multipart upload.
+//
+// Each part is sent along with its MD5 and SHA256 sums so that the endpoint checks its integrity.
+// Entity tags are opaque and not checked as they are not guaranteed to be MD5 sums (for example with
+// server side encryption). The upload is aborted if any part is rejected.
+func (c *Client) Upload(ctx context.Context, bucket string, key string, r io.Reader) (*ObjectInfo, error) {
+	var initiate initiateMultipartUploadResult
+	err := c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploads": {""}}, nil, &initiate)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating multipart upload: %w", err)
+	}
+
+	uploadQuery := url.Values{"uploadId": {initiate.UploadID}}
+
+	info, err := c.uploadParts(ctx, bucket, key, initiate.UploadID, r)
+	if err != nil {
+		// Use a new context so that the upload is aborted even if the request was cancelled.
+		abortErr := c.do(context.Background(), http.MethodDelete, bucket, key, uploadQuery, nil, nil)
+		if abortErr != nil {
+			return nil, fmt.Errorf("%w (failed aborting multipart upload: %v)", err, abortErr)
+		}
+
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// uploadParts uploads the content of the reader as the parts of the given multipart upload and completes it.
+func (c *Client) uploadParts(ctx context.Context, bucket string, key string, uploadID string, r io.Reader) (*ObjectInfo, error) {
+	info := &ObjectInfo{}
+	complete := completeMultipartUpload{}
+	objectHash := sha256.New()
+
+	partSize := c.partSize
+	buf := make([]byte, partSize)
+
+	for partNumber := 1; ; partNumber++ {
+		if partNumber > maxPartNumber {
+			return nil, errors.New("Object exceeds the maximum number of parts of a multipart upload")
+		}
+
+		nextPartSize := uploadPartSize(c.partSize, partNumber)
+		if nextPartSize != partSize {
+			partSize = nextPartSize
+			buf = make([]byte, partSize)
+		}
+
+		n, err := io.ReadFull(r, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+
+		// Only the first part may be empty, in which case the object is empty.
+		last := n < partSize
+		if n == 0 && partNumber > 1 {
+			break
+		}
+
+		part := buf[:n]
+		md5Sum := md5.Sum(part)
+		sha256Sum := sha256.Sum256(part)
+
+		query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+		headers := http.Header{"Content-Md5": {base64.StdEncoding.EncodeToString(md5Sum[:])}}
+
+		resp, err := c.request(ctx, http.MethodPut, bucket, key, query, headers, part, hex.EncodeToString(sha256Sum[:]))
+		if err != nil {
+			return nil, fmt.Errorf("Failed uploading part %d: %w", partNumber, err)
+		}
+
+		_ = resp.Body.Close()
+
+		etag := resp.Header.Get("ETag")
+		if etag == "" {
+			return nil, fmt.Errorf("Failed uploading part %d: No entity tag returned", partNumber)
+		}
+
+		complete.Parts = append(complete.Parts, completedPart{PartNumber: partNumber, ETag: etag})
+		objectHash.Write(part)
+		info.Size += int64(n)
+
+		if last {
+			break
+		}
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return nil, err
+	}
+
+	var result completeMultipartUploadResult
+	err = c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploadId": {uploadID}}, body, &result)
+	if err != nil {
+		return nil, fmt.Errorf("Failed completing multipart upload: %w", err)
+	}
+
+	info.ETag = strings.Trim(result.ETag, `"`)
+	info.SHA256 = hex.EncodeToString(objectHash.Sum(nil))
+
+	return info, nil
+}
+
+// uploadPartSize returns the size of the given part of a multipart upload starting with the given part size.
+// The part size doubles every partSizeGrowthInterval parts, up to maxPartSize.
+func uploadPartSize(initialSize int, partNumber int) int {
+	size := initialSize
+	for i := partSizeGrowthInterval; i < partNumber && size < maxPartSize; i += partSizeGrowthInterval {
+		size *= 2
+	}
+
+	return min(size, maxPartSize)
+}
+
+// Download returns a reader of the content of the object with the given key.
+// The caller is responsible for closing it.
+func (c *Client) Download(ctx context.Context, bucket string, key string) (io.ReadCloser, error) {
+	resp, err := c.request(ctx, http.MethodGet, bucket, key, nil, nil, nil, emptySHA256)
+	if err != nil {
+		return nil, fmt.Errorf("Failed downloading object %q from bucket %q: %w", key, bucket, err)
+	}
+
+	return resp.Body, nil
+}
+
+// do performs a request with the given body and decodes the XML response into result if not nil.
+func (c *Client) do(ctx context.Context, method string, bucket string, key string, query url.Values, body []byte, result any) error {
+	sum := sha256.Sum256(body)
+
+	resp, err := c.request(ctx, method, bucket, key, query, nil, body, hex.EncodeToString(sum[:]))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if result == nil {
+		return nil
+	}
+
+	err = xml.NewDecoder(io.LimitReader(resp.Body, maxXMLBodySize)).Decode(result)
+	if err != nil {
+		return fmt.Errorf("Failed parsing response: %w", err)
+	}
+
+	return nil
+}
+
+// request performs a signed request and returns the response if successful.
+// Error responses are returned as an *Error.
+func (c *Client) request(ctx context.Context, method string, bucket string, key string, query url.Values, headers http.Header, body []byte, payloadHash string) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket + "/" + key
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+
+	c.sign(req, payloadHash)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
+
+		s3Err := &Error{status: resp.StatusCode}
+		err = xml.NewDecoder(io.LimitReader(resp.Body, maxXMLBodySize)).Decode(s3Err)
+		if err != nil || s3Err.Code == "" {
+			return nil, fmt.Errorf("Unexpected response status %q", resp.Status)
+		}
+
+		return nil, s3Err
+	}
+
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to the request.
+func (c *Client) sign(req *http.Request, payloadHash string) {
+	auth := &authInfo{date: c.now().UTC(), region: c.region}
+
+	req.Header.Set("X-Amz-Date", auth.date.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("Content-Md5") != "" {
+		signedHeaders = []string{"content-md5", "host", "x-amz-content-sha256", "x-amz-date"}
+	}
+
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest(req, signedHeaders, payloadHash, false)))
+	stringToSign := signV4Algorithm + "\n" + auth.date.Format(amzDateFormat) + "\n" + auth.scope() + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+	signature := hex.EncodeToString(hmacSHA256(signingKey(c.secretKey, auth.date, c.region), stringToSign))
+
+	req.Header.Set("Authorization", signV4Algorithm+" Credential="+c.accessKey+"/"+auth.scope()+", SignedHeaders="+strings.Join(signedHeaders, ";")+", Signature="+signature)
+}
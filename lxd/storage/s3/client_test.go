@@ -0,0 +1,158 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient returns a client using the given key against a server running the test handler.
+func newTestClient(t *testing.T, h *Handler, accessKey string) *Client {
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.URL, "us-east-1", accessKey, accessKey+"-secret", server.Client())
+	require.NoError(t, err)
+
+	c.now = func() time.Time { return testNow }
+
+	return c
+}
+
+func TestClientUploadDownload(t *testing.T) {
+	h := newTestHandler(t, 0)
+	c := newTestClient(t, h, "admin")
+	c.partSize = 4
+
+	for _, data := range []string{"", "abc", "abcd", "hello world"} {
+		info, err := c.Upload(context.Background(), "foo", "backups/c1 backup+0", strings.NewReader(data))
+		require.NoError(t, err)
+
+		sum := sha256.Sum256([]byte(data))
+		assert.Equal(t, int64(len(data)), info.Size)
+		assert.Equal(t, hex.EncodeToString(sum[:]), info.SHA256)
+
+		r, err := c.Download(context.Background(), "foo", "backups/c1 backup+0")
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		assert.Equal(t, data, string(content))
+	}
+
+	// The object was uploaded in three parts.
+	rec := do(h, http.MethodHead, "/foo/backups/c1%20backup%2B0", "", "admin", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasSuffix(rec.Header().Get("ETag"), `-3"`))
+}
+
+func TestClientErrors(t *testing.T) {
+	h := newTestHandler(t, 0)
+
+	// Failed uploads are aborted.
+	c := newTestClient(t, h, "admin")
+	_, err := c.Upload(context.Background(), "foo", "broken", io.MultiReader(strings.NewReader("data"), iotest.ErrReader(errors.New("read failure"))))
+	require.Error(t, err)
+
+	rec := do(h, http.MethodGet, "/foo?uploads", "", "admin", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var uploads listMultipartUploadsResult
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &uploads))
+	assert.Empty(t, uploads.Uploads)
+
+	_, err = c.Download(context.Background(), "foo", "missing")
+
+	var s3Err *Error
+	require.ErrorAs(t, err, &s3Err)
+	assert.Equal(t, "NoSuchKey", s3Err.Code)
+
+	// Read-only keys can't upload.
+	c = newTestClient(t, h, "reader")
+	_, err = c.Upload(context.Background(), "foo", "object", strings.NewReader("data"))
+	require.ErrorAs(t, err, &s3Err)
+	assert.Equal(t, "AccessDenied", s3Err.Code)
+}
+
+func TestClientUploadCorruptedPart(t *testing.T) {
+	h := newTestHandler(t, 0)
+
+	// Corrupt the body of the second part on its way to the endpoint.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Query().Get("partNumber") == "2" {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			body[0] ^= 0xff
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := NewClient(server.URL, "us-east-1", "admin", "admin-secret", server.Client())
+	require.NoError(t, err)
+
+	c.now = func() time.Time { return testNow }
+	c.partSize = 4
+
+	// The endpoint rejects the corrupted part and the upload is aborted.
+	_, err = c.Upload(context.Background(), "foo", "corrupted", strings.NewReader("hello world"))
+
+	var s3Err *Error
+	require.ErrorAs(t, err, &s3Err)
+	assert.Equal(t, http.StatusBadRequest, s3Err.status)
+
+	rec := do(h, http.MethodGet, "/foo?uploads", "", "admin", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var uploads listMultipartUploadsResult
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &uploads))
+	assert.Empty(t, uploads.Uploads)
+}
+
+func TestUploadPartSize(t *testing.T) {
+	tests := []struct {
+		partNumber int
+		want       int
+	}{
+		{partNumber: 1, want: defaultPartSize},
+		{partNumber: 1000, want: defaultPartSize},
+		{partNumber: 1001, want: 2 * defaultPartSize},
+		{partNumber: 2001, want: 4 * defaultPartSize},
+		{partNumber: 8001, want: 256 * defaultPartSize},
+		{partNumber: 9001, want: maxPartSize},
+		{partNumber: maxPartNumber, want: maxPartSize},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, uploadPartSize(defaultPartSize, tt.partNumber), "part %d", tt.partNumber)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	bucket, key, err := ParseURL("s3://backups/default/c1/backup0")
+	require.NoError(t, err)
+	assert.Equal(t, "backups", bucket)
+	assert.Equal(t, "default/c1/backup0", key)
+
+	for _, u := range []string{"backups/c1", "https://backups/c1", "s3://backups", "s3://backups/", "s3:///c1", "s3://backups/c1?versionId=1"} {
+		_, _, err = ParseURL(u)
+		assert.Error(t, err, u)
+	}
+}
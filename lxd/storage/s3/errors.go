@@ -0,0 +1,84 @@
+package s3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// Error represents an S3 error response.
+type Error struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource,omitempty"`
+
+	status int
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// newError returns a new S3 error with the given HTTP status, code and message.
+func newError(status int, code string, message string) *Error {
+	return &Error{status: status, Code: code, Message: message}
+}
+
+var (
+	errAccessDenied          = newError(http.StatusForbidden, "AccessDenied", "Access Denied")
+	errBadDigest             = newError(http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what was received")
+	errBucketAlreadyOwned    = newError(http.StatusConflict, "BucketAlreadyOwnedByYou", "The bucket already exists and is owned by you")
+	errEntityTooLarge        = newError(http.StatusBadRequest, "EntityTooLarge", "Your proposed upload exceeds the maximum allowed object size")
+	errIncompleteBody        = newError(http.StatusBadRequest, "IncompleteBody", "The request body was not fully received")
+	errInternal              = newError(http.StatusInternalServerError, "InternalError", "We encountered an internal error, please try again")
+	errInvalidAccessKeyID    = newError(http.StatusForbidden, "InvalidAccessKeyId", "The access key ID you provided does not exist in our records")
+	errInvalidArgument       = newError(http.StatusBadRequest, "InvalidArgument", "Invalid argument")
+	errInvalidDigest         = newError(http.StatusBadRequest, "InvalidDigest", "The Content-MD5 you specified is not valid")
+	errInvalidPart           = newError(http.StatusBadRequest, "InvalidPart", "One or more of the specified parts could not be found")
+	errInvalidPartOrder      = newError(http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order")
+	errInvalidRequest        = newError(http.StatusBadRequest, "InvalidRequest", "Invalid request")
+	errKeyTooLong            = newError(http.StatusBadRequest, "KeyTooLongError", "Your key is too long")
+	errMalformedXML          = newError(http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed")
+	errMethodNotAllowed      = newError(http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource")
+	errMissingContentLength  = newError(http.StatusLengthRequired, "MissingContentLength", "You must provide the Content-Length HTTP header")
+	errMissingSecurityHeader = newError(http.StatusBadRequest, "MissingSecurityHeader", "Your request is missing a required header")
+	errNoSuchBucket          = newError(http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+	errNoSuchKey             = newError(http.StatusNotFound, "NoSuchKey", "The specified key does not exist")
+	errNoSuchUpload          = newError(http.StatusNotFound, "NoSuchUpload", "The specified multipart upload does not exist")
+	errNotImplemented        = newError(http.StatusNotImplemented, "NotImplemented", "A header or query you provided implies functionality that is not implemented")
+	errQuotaExceeded         = newError(http.StatusForbidden, "QuotaExceeded", "The bucket size limit has been reached")
+	errRequestExpired        = newError(http.StatusForbidden, "AccessDenied", "Request has expired")
+	errRequestTimeTooSkewed  = newError(http.StatusForbidden, "RequestTimeTooSkewed", "The difference between the request time and the server's time is too large")
+	errSignatureDoesNotMatch = newError(http.StatusForbidden, "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided")
+	errUnsupportedAuth       = newError(http.StatusBadRequest, "InvalidRequest", "Only AWS Signature Version 4 authentication is supported")
+	errXAmzContentSHA256     = newError(http.StatusBadRequest, "XAmzContentSHA256Mismatch", "The provided 'x-amz-content-sha256' header does not match what was computed")
+)
+
+// writeError writes the S3 error response for the given error to the client.
+func writeError(w http.ResponseWriter, r *http.Request, err *Error) {
+	resp := *err
+	resp.Resource = r.URL.Path
+
+	// Responses to HEAD requests have no body.
+	if r.Method == http.MethodHead {
+		w.WriteHeader(resp.status)
+		return
+	}
+
+	writeXML(w, resp.status, resp)
+}
+
+// writeXML writes the given value as an XML document to the client.
+func writeXML(w http.ResponseWriter, status int, v any) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(body)
+}
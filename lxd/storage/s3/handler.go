@@ -0,0 +1,297 @@
+package s3
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Handler serves the S3 API for the buckets provided by a backend.
+type Handler struct {
+	backend Backend
+	locks   lockSet
+	usage   usageSet
+	now     func() time.Time
+}
+
+// NewHandler returns a new S3 API handler serving the buckets of the given backend.
+func NewHandler(backend Backend) *Handler {
+	return &Handler{
+		backend: backend,
+		now:     time.Now,
+	}
+}
+
+// request holds the state of an authenticated S3 API request.
+type request struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	auth   *authInfo
+	bucket *Bucket
+	store  *store
+	key    string
+}
+
+// unsupportedBucketSubresources are the bucket subresources that are not implemented.
+var unsupportedBucketSubresources = []string{"accelerate", "acl", "analytics", "cors", "encryption", "intelligent-tiering", "inventory", "lifecycle", "logging", "metrics", "notification", "object-lock", "ownershipControls", "policy", "publicAccessBlock", "replication", "requestPayment", "tagging", "website"}
+
+// unsupportedObjectSubresources are the object subresources that are not implemented.
+var unsupportedObjectSubresources = []string{"acl", "attributes", "legal-hold", "restore", "retention", "select", "tagging", "torrent"}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Amz-Request-Id", randomHex(8))
+
+	err := h.serve(w, r)
+	if err != nil {
+		var s3Err *Error
+		if !errors.As(err, &s3Err) {
+			logger.Warn("Failed handling S3 request", logger.Ctx{"method": r.Method, "path": r.URL.Path, "err": err})
+			s3Err = errInternal
+		}
+
+		writeError(w, r, s3Err)
+	}
+}
+
+// serve authenticates the request and dispatches it to the relevant operation.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) error {
+	auth, authErr := parseAuth(r)
+	if authErr != nil {
+		return authErr
+	}
+
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucketName == "" {
+		if r.Method != http.MethodGet {
+			return errMethodNotAllowed
+		}
+
+		return h.listBuckets(w, r, auth)
+	}
+
+	// Access keys are unique, so the bucket is identified by the access key of the request rather than by
+	// its name, which is only unique within a storage pool.
+	bucket, err := h.backend.LoadBucketByAccessKey(r.Context(), auth.accessKey)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return errInvalidAccessKeyID
+		}
+
+		return err
+	}
+
+	bucketKey := bucket.key(auth.accessKey)
+	if bucketKey == nil {
+		return errInvalidAccessKeyID
+	}
+
+	authErr = auth.verify(r, bucketKey.SecretKey, h.now())
+	if authErr != nil {
+		return authErr
+	}
+
+	if bucket.Name != bucketName {
+		return errNoSuchBucket
+	}
+
+	if bucketKey.ReadOnly && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return errAccessDenied
+	}
+
+	if len(key) > maxKeyLength {
+		return errKeyTooLong
+	}
+
+	if !utf8.ValidString(key) {
+		return errInvalidArgument
+	}
+
+	path, unmount, err := h.backend.MountBucket(r.Context(), bucket)
+	if err != nil {
+		return err
+	}
+
+	defer unmount()
+
+	req := &request{
+		w:      w,
+		r:      r,
+		auth:   auth,
+		bucket: bucket,
+		key:    key,
+		store: &store{
+			bucketName: bucket.Name,
+			path:       path,
+			size:       bucket.Size,
+			locks:      &h.locks,
+			usage:      h.usage.get(bucket.ID),
+		},
+	}
+
+	if key == "" {
+		return h.serveBucket(req)
+	}
+
+	return h.serveObject(req)
+}
+
+// serveBucket dispatches a bucket level request.
+func (h *Handler) serveBucket(req *request) error {
+	query := req.r.URL.Query()
+
+	for _, subresource := range unsupportedBucketSubresources {
+		if query.Has(subresource) {
+			return errNotImplemented
+		}
+	}
+
+	switch req.r.Method {
+	case http.MethodHead:
+		req.w.Header().Set("X-Amz-Bucket-Region", req.auth.region)
+		req.w.WriteHeader(http.StatusOK)
+		return nil
+	case http.MethodGet:
+		switch {
+		case query.Has("location"):
+			writeXML(req.w, http.StatusOK, locationConstraint{})
+			return nil
+		case query.Has("versioning"):
+			writeXML(req.w, http.StatusOK, versioningConfiguration{})
+			return nil
+		case query.Has("uploads"):
+			return h.listUploads(req)
+		case query.Get("list-type") == "2":
+			return h.listObjectsV2(req)
+		}
+
+		return h.listObjectsV1(req)
+	case http.MethodPut:
+		return errBucketAlreadyOwned
+	case http.MethodPost:
+		if query.Has("delete") {
+			return h.deleteObjects(req)
+		}
+
+	case http.MethodDelete:
+		return newError(http.StatusForbidden, "AccessDenied", "Buckets can only be deleted using the LXD API")
+	}
+
+	return errMethodNotAllowed
+}
+
+// serveObject dispatches an object level request.
+func (h *Handler) serveObject(req *request) error {
+	query := req.r.URL.Query()
+
+	for _, subresource := range unsupportedObjectSubresources {
+		if query.Has(subresource) {
+			return errNotImplemented
+		}
+	}
+
+	switch req.r.Method {
+	case http.MethodGet, http.MethodHead:
+		if query.Has("uploadId") {
+			return h.listParts(req)
+		}
+
+		return h.getObject(req)
+	case http.MethodPut:
+		if query.Has("uploadId") {
+			return h.uploadPart(req)
+		}
+
+		if req.r.Header.Get("X-Amz-Copy-Source") != "" {
+			return h.copyObject(req)
+		}
+
+		return h.putObject(req)
+	case http.MethodPost:
+		if query.Has("uploads") {
+			return h.createUpload(req)
+		}
+
+		if query.Has("uploadId") {
+			return h.completeUpload(req)
+		}
+
+	case http.MethodDelete:
+		if query.Has("uploadId") {
+			return h.abortUpload(req)
+		}
+
+		err := req.store.deleteObject(req.key)
+		if err != nil {
+			return err
+		}
+
+		req.w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	return errMethodNotAllowed
+}
+
+// listBuckets returns the bucket that the access key of the request belongs to.
+func (h *Handler) listBuckets(w http.ResponseWriter, r *http.Request, auth *authInfo) error {
+	bucket, err := h.backend.LoadBucketByAccessKey(r.Context(), auth.accessKey)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return errInvalidAccessKeyID
+		}
+
+		return err
+	}
+
+	bucketKey := bucket.key(auth.accessKey)
+	if bucketKey == nil {
+		return errInvalidAccessKeyID
+	}
+
+	authErr := auth.verify(r, bucketKey.SecretKey, h.now())
+	if authErr != nil {
+		return authErr
+	}
+
+	path, unmount, err := h.backend.MountBucket(r.Context(), bucket)
+	if err != nil {
+		return err
+	}
+
+	defer unmount()
+
+	var creationDate time.Time
+	fi, err := os.Stat(path)
+	if err == nil {
+		creationDate = fi.ModTime()
+	}
+
+	writeXML(w, http.StatusOK, listAllMyBucketsResult{
+		Owner:   owner{ID: auth.accessKey, DisplayName: auth.accessKey},
+		Buckets: []bucketEntry{{Name: bucket.Name, CreationDate: formatTime(creationDate)}},
+	})
+
+	return nil
+}
+
+// formatTime formats the given time as expected in S3 XML documents.
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// randomHex returns a random hex string of the given number of bytes.
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,355 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxPartNumber is the highest part number of a multipart upload.
+const maxPartNumber = 10000
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ InitiateMultipartUploadResult"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CompleteMultipartUploadResult"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+type partEntry struct {
+	PartNumber   int    `xml:"PartNumber"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+}
+
+type listPartsResult struct {
+	XMLName     xml.Name    `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListPartsResult"`
+	Bucket      string      `xml:"Bucket"`
+	Key         string      `xml:"Key"`
+	UploadID    string      `xml:"UploadId"`
+	MaxParts    int         `xml:"MaxParts"`
+	IsTruncated bool        `xml:"IsTruncated"`
+	Parts       []partEntry `xml:"Part"`
+}
+
+type uploadEntry struct {
+	Key          string `xml:"Key"`
+	UploadID     string `xml:"UploadId"`
+	Initiated    string `xml:"Initiated"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type listMultipartUploadsResult struct {
+	XMLName     xml.Name      `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListMultipartUploadsResult"`
+	Bucket      string        `xml:"Bucket"`
+	Prefix      string        `xml:"Prefix"`
+	MaxUploads  int           `xml:"MaxUploads"`
+	IsTruncated bool          `xml:"IsTruncated"`
+	Uploads     []uploadEntry `xml:"Upload"`
+}
+
+// loadRequestUpload returns the multipart upload referenced by the request.
+func loadRequestUpload(req *request) (*uploadInfo, error) {
+	upload, err := req.store.loadUpload(req.r.URL.Query().Get("uploadId"))
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.Key != req.key {
+		return nil, errNoSuchUpload
+	}
+
+	return upload, nil
+}
+
+// createUpload starts a new multipart upload.
+func (h *Handler) createUpload(req *request) error {
+	headers, metadata := requestHeaders(req.r)
+
+	upload := &uploadInfo{
+		ID:          randomHex(16),
+		Key:         req.key,
+		ContentType: req.r.Header.Get("Content-Type"),
+		Headers:     headers,
+		Metadata:    metadata,
+		Initiated:   h.now().UTC(),
+	}
+
+	err := req.store.createUpload(upload)
+	if err != nil {
+		return err
+	}
+
+	writeXML(req.w, http.StatusOK, initiateMultipartUploadResult{
+		Bucket:   req.bucket.Name,
+		Key:      req.key,
+		UploadID: upload.ID,
+	})
+
+	return nil
+}
+
+// uploadPart stores the request body as a part of a multipart upload.
+func (h *Handler) uploadPart(req *request) error {
+	if req.r.Header.Get("X-Amz-Copy-Source") != "" {
+		return errNotImplemented
+	}
+
+	partNumber, err := strconv.Atoi(req.r.URL.Query().Get("partNumber"))
+	if err != nil || partNumber < 1 || partNumber > maxPartNumber {
+		return newError(http.StatusBadRequest, "InvalidArgument", "Part number must be an integer between 1 and 10000, inclusive")
+	}
+
+	upload, err := loadRequestUpload(req)
+	if err != nil {
+		return err
+	}
+
+	tmpPath, size, sum, err := h.receiveBody(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	part := partInfo{
+		Number:       partNumber,
+		Size:         size,
+		ETag:         hex.EncodeToString(sum),
+		LastModified: h.now().UTC(),
+	}
+
+	err = req.store.commitPart(upload.ID, tmpPath, part)
+	if err != nil {
+		return err
+	}
+
+	req.w.Header().Set("ETag", quoteETag(part.ETag))
+	req.w.WriteHeader(http.StatusOK)
+
+	return nil
+}
+
+// completeUpload assembles the parts of a multipart upload into an object.
+func (h *Handler) completeUpload(req *request) error {
+	upload, err := loadRequestUpload(req)
+	if err != nil {
+		return err
+	}
+
+	var complete completeMultipartUpload
+	err = readXMLBody(req, &complete)
+	if err != nil {
+		return err
+	}
+
+	if len(complete.Parts) == 0 {
+		return errMalformedXML
+	}
+
+	storedParts, err := req.store.listParts(upload.ID)
+	if err != nil {
+		return err
+	}
+
+	partsByNumber := make(map[int]partInfo, len(storedParts))
+	for _, part := range storedParts {
+		partsByNumber[part.Number] = part
+	}
+
+	// Check the requested parts and compute the entity tag of the object, which is
+	// the MD5 sum of the concatenated MD5 sums of the parts followed by their count.
+	var size int64
+	etagHash := md5.New()
+	for i, requested := range complete.Parts {
+		if i > 0 && requested.PartNumber <= complete.Parts[i-1].PartNumber {
+			return errInvalidPartOrder
+		}
+
+		part, ok := partsByNumber[requested.PartNumber]
+		if !ok || strings.Trim(requested.ETag, `"`) != part.ETag {
+			return errInvalidPart
+		}
+
+		sum, err := hex.DecodeString(part.ETag)
+		if err != nil {
+			return err
+		}
+
+		etagHash.Write(sum)
+		size += part.Size
+	}
+
+	readers := make([]io.Reader, 0, len(complete.Parts))
+	for _, requested := range complete.Parts {
+		f, err := req.store.openPart(upload.ID, requested.PartNumber)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		readers = append(readers, f)
+	}
+
+	// The parts are already accounted for and are deleted once the object is in place, so the space
+	// temporarily used by the assembled object isn't checked against the bucket size.
+	err = req.store.reserve(size, false)
+	if err != nil {
+		return err
+	}
+
+	tmpPath, written, _, err := req.store.writeTemp(io.MultiReader(readers...), size)
+	if err != nil {
+		req.store.release(size)
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if written != size {
+		req.store.release(size)
+		return errInvalidPart
+	}
+
+	info := objectInfo{
+		Key:          upload.Key,
+		Size:         size,
+		ETag:         hex.EncodeToString(etagHash.Sum(nil)) + "-" + strconv.Itoa(len(complete.Parts)),
+		ContentType:  upload.ContentType,
+		Headers:      upload.Headers,
+		Metadata:     upload.Metadata,
+		LastModified: h.now().UTC(),
+	}
+
+	err = req.store.commitObject(tmpPath, info)
+	if err != nil {
+		return err
+	}
+
+	err = req.store.deleteUpload(upload.ID)
+	if err != nil {
+		return err
+	}
+
+	writeXML(req.w, http.StatusOK, completeMultipartUploadResult{
+		Location: req.r.URL.Path,
+		Bucket:   req.bucket.Name,
+		Key:      upload.Key,
+		ETag:     quoteETag(info.ETag),
+	})
+
+	return nil
+}
+
+// abortUpload deletes a multipart upload along with its parts.
+func (h *Handler) abortUpload(req *request) error {
+	upload, err := loadRequestUpload(req)
+	if err != nil {
+		return err
+	}
+
+	err = req.store.deleteUpload(upload.ID)
+	if err != nil {
+		return err
+	}
+
+	req.w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// listParts lists the parts of a multipart upload.
+func (h *Handler) listParts(req *request) error {
+	upload, err := loadRequestUpload(req)
+	if err != nil {
+		return err
+	}
+
+	parts, err := req.store.listParts(upload.ID)
+	if err != nil {
+		return err
+	}
+
+	result := listPartsResult{
+		Bucket:   req.bucket.Name,
+		Key:      upload.Key,
+		UploadID: upload.ID,
+		MaxParts: maxPartNumber,
+	}
+
+	for _, part := range parts {
+		result.Parts = append(result.Parts, partEntry{
+			PartNumber:   part.Number,
+			LastModified: formatTime(part.LastModified),
+			ETag:         quoteETag(part.ETag),
+			Size:         part.Size,
+		})
+	}
+
+	writeXML(req.w, http.StatusOK, result)
+
+	return nil
+}
+
+// listUploads lists the pending multipart uploads of the bucket.
+func (h *Handler) listUploads(req *request) error {
+	uploads, err := req.store.listUploads()
+	if err != nil {
+		return err
+	}
+
+	prefix := req.r.URL.Query().Get("prefix")
+
+	result := listMultipartUploadsResult{
+		Bucket:     req.bucket.Name,
+		Prefix:     prefix,
+		MaxUploads: maxListKeys,
+	}
+
+	for _, upload := range uploads {
+		if !strings.HasPrefix(upload.Key, prefix) {
+			continue
+		}
+
+		if len(result.Uploads) == maxListKeys {
+			result.IsTruncated = true
+			break
+		}
+
+		result.Uploads = append(result.Uploads, uploadEntry{
+			Key:          upload.Key,
+			UploadID:     upload.ID,
+			Initiated:    formatTime(upload.Initiated),
+			StorageClass: "STANDARD",
+		})
+	}
+
+	writeXML(req.w, http.StatusOK, result)
+
+	return nil
+}
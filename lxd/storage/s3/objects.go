@@ -0,0 +1,596 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const xmlNamespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// defaultContentType is the content type of objects uploaded without one.
+const defaultContentType = "binary/octet-stream"
+
+// storedHeaders are the request headers stored alongside an object and returned when it is retrieved.
+var storedHeaders = []string{"Cache-Control", "Content-Disposition", "Content-Encoding", "Content-Language", "Expires"}
+
+// responseOverrides maps the query parameters of a GET request to the response headers they override.
+var responseOverrides = map[string]string{
+	"response-cache-control":       "Cache-Control",
+	"response-content-disposition": "Content-Disposition",
+	"response-content-encoding":    "Content-Encoding",
+	"response-content-language":    "Content-Language",
+	"response-content-type":        "Content-Type",
+	"response-expires":             "Expires",
+}
+
+type owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type bucketEntry struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name      `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResult"`
+	Owner   owner         `xml:"Owner"`
+	Buckets []bucketEntry `xml:"Buckets>Bucket"`
+}
+
+type locationConstraint struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+}
+
+type versioningConfiguration struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersioningConfiguration"`
+}
+
+type objectEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type listBucketResult struct {
+	XMLName        xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name           string         `xml:"Name"`
+	Prefix         string         `xml:"Prefix"`
+	Marker         string         `xml:"Marker"`
+	NextMarker     string         `xml:"NextMarker,omitempty"`
+	Delimiter      string         `xml:"Delimiter,omitempty"`
+	MaxKeys        int            `xml:"MaxKeys"`
+	EncodingType   string         `xml:"EncodingType,omitempty"`
+	IsTruncated    bool           `xml:"IsTruncated"`
+	Contents       []objectEntry  `xml:"Contents"`
+	CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type listBucketResultV2 struct {
+	XMLName               xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	StartAfter            string         `xml:"StartAfter,omitempty"`
+	ContinuationToken     string         `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	KeyCount              int            `xml:"KeyCount"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	EncodingType          string         `xml:"EncodingType,omitempty"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	Contents              []objectEntry  `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type deleteRequest struct {
+	XMLName xml.Name `xml:"Delete"`
+	Quiet   bool     `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type deletedEntry struct {
+	Key string `xml:"Key"`
+}
+
+type deleteErrorEntry struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+type deleteResult struct {
+	XMLName xml.Name           `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteResult"`
+	Deleted []deletedEntry     `xml:"Deleted"`
+	Errors  []deleteErrorEntry `xml:"Error"`
+}
+
+type copyObjectResult struct {
+	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyObjectResult"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+}
+
+// quoteETag returns the given entity tag as a quoted string.
+func quoteETag(etag string) string {
+	return `"` + etag + `"`
+}
+
+// requestHeaders returns the object headers and user metadata supplied in the request.
+func requestHeaders(r *http.Request) (map[string]string, map[string]string) {
+	headers := map[string]string{}
+	for _, name := range storedHeaders {
+		value := r.Header.Get(name)
+		if value != "" {
+			headers[name] = value
+		}
+	}
+
+	metadata := map[string]string{}
+	for name, values := range r.Header {
+		key, ok := strings.CutPrefix(strings.ToLower(name), "x-amz-meta-")
+		if ok && key != "" {
+			metadata[key] = strings.Join(values, ",")
+		}
+	}
+
+	return headers, metadata
+}
+
+// readXMLBody reads and verifies the XML document of the request body into the given value.
+func readXMLBody(req *request, v any) error {
+	body, verify := payloadReader(req.r, req.auth)
+
+	data, err := io.ReadAll(io.LimitReader(body, maxXMLBodySize+1))
+	if err != nil {
+		var s3Err *Error
+		if errors.As(err, &s3Err) {
+			return s3Err
+		}
+
+		return errIncompleteBody
+	}
+
+	if len(data) > maxXMLBodySize {
+		return errEntityTooLarge
+	}
+
+	verifyErr := verify()
+	if verifyErr != nil {
+		return verifyErr
+	}
+
+	err = xml.Unmarshal(data, v)
+	if err != nil {
+		return errMalformedXML
+	}
+
+	return nil
+}
+
+// receiveBody stores the verified request body into a temporary file and returns its path, size and MD5 sum.
+// The size of the body is reserved in the bucket until the file is committed.
+func (h *Handler) receiveBody(req *request) (string, int64, []byte, error) {
+	length := decodedContentLength(req.r, req.auth)
+	if length < 0 {
+		return "", -1, nil, errMissingContentLength
+	}
+
+	if length > maxObjectSize {
+		return "", -1, nil, errEntityTooLarge
+	}
+
+	var expectedMD5 []byte
+	contentMD5 := req.r.Header.Get("Content-MD5")
+	if contentMD5 != "" {
+		var err error
+		expectedMD5, err = base64.StdEncoding.DecodeString(contentMD5)
+		if err != nil || len(expectedMD5) != md5.Size {
+			return "", -1, nil, errInvalidDigest
+		}
+	}
+
+	err := req.store.reserve(length, true)
+	if err != nil {
+		return "", -1, nil, err
+	}
+
+	body, verify := payloadReader(req.r, req.auth)
+
+	tmpPath, size, sum, err := req.store.writeTemp(body, length)
+	if err != nil {
+		req.store.release(length)
+		return "", -1, nil, err
+	}
+
+	fail := func(err error) (string, int64, []byte, error) {
+		_ = os.Remove(tmpPath)
+		req.store.release(length)
+		return "", -1, nil, err
+	}
+
+	if size != length {
+		return fail(errIncompleteBody)
+	}
+
+	verifyErr := verify()
+	if verifyErr != nil {
+		return fail(verifyErr)
+	}
+
+	if expectedMD5 != nil && !bytes.Equal(expectedMD5, sum) {
+		return fail(errBadDigest)
+	}
+
+	return tmpPath, size, sum, nil
+}
+
+// putObject stores the request body as an object.
+func (h *Handler) putObject(req *request) error {
+	tmpPath, size, sum, err := h.receiveBody(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	headers, metadata := requestHeaders(req.r)
+
+	info := objectInfo{
+		Key:          req.key,
+		Size:         size,
+		ETag:         hex.EncodeToString(sum),
+		ContentType:  req.r.Header.Get("Content-Type"),
+		Headers:      headers,
+		Metadata:     metadata,
+		LastModified: h.now().UTC(),
+	}
+
+	err = req.store.commitObject(tmpPath, info)
+	if err != nil {
+		return err
+	}
+
+	req.w.Header().Set("ETag", quoteETag(info.ETag))
+	req.w.WriteHeader(http.StatusOK)
+
+	return nil
+}
+
+// getObject returns the content (or only the headers for HEAD requests) of an object.
+// Range and conditional requests are handled by http.ServeContent.
+func (h *Handler) getObject(req *request) error {
+	f, info, err := req.store.openObject(req.key)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	header := req.w.Header()
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+
+	header.Set("Content-Type", contentType)
+	header.Set("ETag", quoteETag(info.ETag))
+	header.Set("Accept-Ranges", "bytes")
+
+	for name, value := range info.Headers {
+		header.Set(name, value)
+	}
+
+	for name, value := range info.Metadata {
+		header.Set("X-Amz-Meta-"+name, value)
+	}
+
+	query := req.r.URL.Query()
+	for param, name := range responseOverrides {
+		if query.Has(param) {
+			header.Set(name, query.Get(param))
+		}
+	}
+
+	http.ServeContent(req.w, req.r, "", info.LastModified, f)
+
+	return nil
+}
+
+// copyObject copies an object of the same bucket.
+func (h *Handler) copyObject(req *request) error {
+	source, err := url.PathUnescape(req.r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		return errInvalidArgument
+	}
+
+	source, _, _ = strings.Cut(source, "?")
+	srcBucket, srcKey, _ := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	if srcKey == "" {
+		return errInvalidArgument
+	}
+
+	if srcBucket != req.bucket.Name {
+		return newError(http.StatusForbidden, "AccessDenied", "Objects can only be copied within the same bucket")
+	}
+
+	replaceMetadata := req.r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE"
+	if srcKey == req.key && !replaceMetadata {
+		return newError(http.StatusBadRequest, "InvalidRequest", "This copy request is illegal because it is trying to copy an object to itself without changing the object's metadata")
+	}
+
+	src, srcInfo, err := req.store.openObject(srcKey)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = src.Close() }()
+
+	err = req.store.reserve(srcInfo.Size, true)
+	if err != nil {
+		return err
+	}
+
+	tmpPath, _, sum, err := req.store.writeTemp(src, srcInfo.Size)
+	if err != nil {
+		req.store.release(srcInfo.Size)
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	info := *srcInfo
+	info.Key = req.key
+	info.ETag = hex.EncodeToString(sum)
+	info.LastModified = h.now().UTC()
+
+	if replaceMetadata {
+		info.ContentType = req.r.Header.Get("Content-Type")
+		info.Headers, info.Metadata = requestHeaders(req.r)
+	}
+
+	err = req.store.commitObject(tmpPath, info)
+	if err != nil {
+		return err
+	}
+
+	writeXML(req.w, http.StatusOK, copyObjectResult{
+		LastModified: formatTime(info.LastModified),
+		ETag:         quoteETag(info.ETag),
+	})
+
+	return nil
+}
+
+// deleteObjects deletes multiple objects.
+func (h *Handler) deleteObjects(req *request) error {
+	var delReq deleteRequest
+
+	err := readXMLBody(req, &delReq)
+	if err != nil {
+		return err
+	}
+
+	if len(delReq.Objects) > maxListKeys {
+		return errMalformedXML
+	}
+
+	result := deleteResult{}
+	for _, object := range delReq.Objects {
+		err := req.store.deleteObject(object.Key)
+		if err != nil {
+			result.Errors = append(result.Errors, deleteErrorEntry{Key: object.Key, Code: errInternal.Code, Message: errInternal.Message})
+			continue
+		}
+
+		if !delReq.Quiet {
+			result.Deleted = append(result.Deleted, deletedEntry{Key: object.Key})
+		}
+	}
+
+	writeXML(req.w, http.StatusOK, result)
+
+	return nil
+}
+
+// listEntries returns the objects and common prefixes matching the given prefix which come after the given marker.
+// Keys containing the delimiter after the prefix are grouped into common prefixes. It returns at most maxKeys
+// entries along with whether the listing was truncated and the last entry returned.
+func listEntries(objects []objectInfo, prefix string, delimiter string, marker string, maxKeys int) ([]objectEntry, []commonPrefix, bool, string) {
+	var contents []objectEntry
+	var prefixes []commonPrefix
+	var last string
+
+	count := 0
+	for _, object := range objects {
+		if !strings.HasPrefix(object.Key, prefix) || object.Key <= marker {
+			continue
+		}
+
+		if delimiter != "" {
+			before, _, found := strings.Cut(object.Key[len(prefix):], delimiter)
+			if found {
+				cp := prefix + before + delimiter
+
+				// Skip the keys of a common prefix that has already been returned.
+				if cp <= marker || cp == last {
+					continue
+				}
+
+				if count == maxKeys {
+					return contents, prefixes, true, last
+				}
+
+				prefixes = append(prefixes, commonPrefix{Prefix: cp})
+				last = cp
+				count++
+				continue
+			}
+		}
+
+		if count == maxKeys {
+			return contents, prefixes, true, last
+		}
+
+		contents = append(contents, objectEntry{
+			Key:          object.Key,
+			LastModified: formatTime(object.LastModified),
+			ETag:         quoteETag(object.ETag),
+			Size:         object.Size,
+			StorageClass: "STANDARD",
+		})
+
+		last = object.Key
+		count++
+	}
+
+	return contents, prefixes, false, last
+}
+
+// parseMaxKeys returns the maximum number of keys requested.
+func parseMaxKeys(value string) (int, error) {
+	if value == "" {
+		return maxListKeys, nil
+	}
+
+	maxKeys, err := strconv.Atoi(value)
+	if err != nil || maxKeys < 0 {
+		return -1, errInvalidArgument
+	}
+
+	return min(maxKeys, maxListKeys), nil
+}
+
+// encodeListing applies the requested encoding to the keys and prefixes of a listing.
+func encodeListing(encodingType string, contents []objectEntry, prefixes []commonPrefix, values ...*string) error {
+	if encodingType == "" {
+		return nil
+	}
+
+	if encodingType != "url" {
+		return errInvalidArgument
+	}
+
+	for i := range contents {
+		contents[i].Key = url.QueryEscape(contents[i].Key)
+	}
+
+	for i := range prefixes {
+		prefixes[i].Prefix = url.QueryEscape(prefixes[i].Prefix)
+	}
+
+	for _, value := range values {
+		*value = url.QueryEscape(*value)
+	}
+
+	return nil
+}
+
+// listObjectsV1 lists the objects of the bucket using the original listing API.
+func (h *Handler) listObjectsV1(req *request) error {
+	query := req.r.URL.Query()
+
+	maxKeys, err := parseMaxKeys(query.Get("max-keys"))
+	if err != nil {
+		return err
+	}
+
+	objects, err := req.store.listObjects()
+	if err != nil {
+		return err
+	}
+
+	result := listBucketResult{
+		Name:         req.bucket.Name,
+		Prefix:       query.Get("prefix"),
+		Marker:       query.Get("marker"),
+		Delimiter:    query.Get("delimiter"),
+		MaxKeys:      maxKeys,
+		EncodingType: query.Get("encoding-type"),
+	}
+
+	var last string
+	result.Contents, result.CommonPrefixes, result.IsTruncated, last = listEntries(objects, result.Prefix, result.Delimiter, result.Marker, maxKeys)
+	if result.IsTruncated {
+		result.NextMarker = last
+	}
+
+	err = encodeListing(result.EncodingType, result.Contents, result.CommonPrefixes, &result.Prefix, &result.Marker, &result.NextMarker, &result.Delimiter)
+	if err != nil {
+		return err
+	}
+
+	writeXML(req.w, http.StatusOK, result)
+
+	return nil
+}
+
+// listObjectsV2 lists the objects of the bucket.
+func (h *Handler) listObjectsV2(req *request) error {
+	query := req.r.URL.Query()
+
+	maxKeys, err := parseMaxKeys(query.Get("max-keys"))
+	if err != nil {
+		return err
+	}
+
+	result := listBucketResultV2{
+		Name:              req.bucket.Name,
+		Prefix:            query.Get("prefix"),
+		StartAfter:        query.Get("start-after"),
+		ContinuationToken: query.Get("continuation-token"),
+		MaxKeys:           maxKeys,
+		Delimiter:         query.Get("delimiter"),
+		EncodingType:      query.Get("encoding-type"),
+	}
+
+	marker := result.StartAfter
+	if result.ContinuationToken != "" {
+		token, err := base64.RawURLEncoding.DecodeString(result.ContinuationToken)
+		if err != nil {
+			return newError(http.StatusBadRequest, "InvalidArgument", "The continuation token provided is incorrect")
+		}
+
+		marker = string(token)
+	}
+
+	objects, err := req.store.listObjects()
+	if err != nil {
+		return err
+	}
+
+	var last string
+	result.Contents, result.CommonPrefixes, result.IsTruncated, last = listEntries(objects, result.Prefix, result.Delimiter, marker, maxKeys)
+	result.KeyCount = len(result.Contents) + len(result.CommonPrefixes)
+	if result.IsTruncated {
+		result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(last))
+	}
+
+	err = encodeListing(result.EncodingType, result.Contents, result.CommonPrefixes, &result.Prefix, &result.StartAfter, &result.Delimiter)
+	if err != nil {
+		return err
+	}
+
+	writeXML(req.w, http.StatusOK, result)
+
+	return nil
+}
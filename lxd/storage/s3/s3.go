@@ -0,0 +1,72 @@
+// Package s3 implements a minimal S3 compatible gateway which serves storage buckets from local directories.
+//
+// Only path-style requests authenticated using AWS Signature Version 4 are supported. Objects are stored as
+// regular files named after the hash of their key, alongside a JSON file holding their metadata.
+//
+// The package also provides a client used to store objects such as backups on external S3 compatible endpoints.
+package s3
+
+import (
+	"context"
+	"time"
+)
+
+// Key represents an access key of a bucket.
+type Key struct {
+	AccessKey string
+	SecretKey string
+	ReadOnly  bool
+}
+
+// Bucket represents a bucket served by the gateway.
+type Bucket struct {
+	// ID uniquely identifies the bucket. It is used to track the space used by the bucket contents.
+	ID   int64
+	Name string
+
+	// Project and Pool identify the storage volume holding the bucket contents.
+	Project string
+	Pool    string
+
+	// Size is the maximum size in bytes of the bucket contents (0 means unlimited).
+	Size int64
+
+	Keys []Key
+}
+
+// key returns the bucket key with the given access key.
+func (b *Bucket) key(accessKey string) *Key {
+	for i := range b.Keys {
+		if b.Keys[i].AccessKey == accessKey {
+			return &b.Keys[i]
+		}
+	}
+
+	return nil
+}
+
+// Backend provides the buckets served by the gateway.
+type Backend interface {
+	// LoadBucketByAccessKey returns the bucket that the given access key belongs to.
+	// An api.StatusError with http.StatusNotFound is returned if the access key doesn't exist.
+	LoadBucketByAccessKey(ctx context.Context, accessKey string) (*Bucket, error)
+
+	// MountBucket makes the bucket contents available and returns their path along with a function
+	// that must be called once the caller is done with them.
+	MountBucket(ctx context.Context, bucket *Bucket) (string, func(), error)
+}
+
+// maxClockSkew is the maximum difference allowed between the request date and the server time.
+const maxClockSkew = 15 * time.Minute
+
+// maxKeyLength is the maximum length in bytes of an object key.
+const maxKeyLength = 1024
+
+// maxObjectSize is the maximum size of an object uploaded in a single request.
+const maxObjectSize = 5 * 1024 * 1024 * 1024
+
+// maxXMLBodySize is the maximum size of the XML documents accepted in request bodies.
+const maxXMLBodySize = 4 * 1024 * 1024
+
+// maxListKeys is the maximum number of keys returned by a listing.
+const maxListKeys = 1000
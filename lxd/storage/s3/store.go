@@ -0,0 +1,617 @@
+package s3
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	objectsDir     = "objects"
+	uploadsDir     = "uploads"
+	tmpDir         = "tmp"
+	metadataSuffix = ".json"
+	uploadMetadata = "upload.json"
+	partPrefix     = "part-"
+)
+
+// objectInfo holds the metadata of an object.
+type objectInfo struct {
+	Key          string            `json:"key"`
+	Size         int64             `json:"size"`
+	ETag         string            `json:"etag"`
+	ContentType  string            `json:"content_type,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	LastModified time.Time         `json:"last_modified"`
+}
+
+// uploadInfo holds the metadata of a multipart upload.
+type uploadInfo struct {
+	ID          string            `json:"-"`
+	Key         string            `json:"key"`
+	ContentType string            `json:"content_type,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Initiated   time.Time         `json:"initiated"`
+}
+
+// partInfo holds the metadata of a part of a multipart upload.
+type partInfo struct {
+	Number       int       `json:"number"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// lockSet provides striped read/write locks protecting the objects of all buckets.
+type lockSet [256]sync.RWMutex
+
+// get returns the lock of the given object.
+func (l *lockSet) get(bucketName string, key string) *sync.RWMutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(bucketName))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+
+	return &l[h.Sum32()%uint32(len(l))]
+}
+
+// bucketUsage tracks the number of bytes used by the contents of a bucket.
+type bucketUsage struct {
+	mu     sync.Mutex
+	loaded bool
+	used   int64
+}
+
+// usageSet tracks the usage of all buckets, so that quotas can be enforced without walking their contents.
+type usageSet struct {
+	mu      sync.Mutex
+	buckets map[int64]*bucketUsage
+}
+
+// get returns the usage tracker of the bucket with the given ID.
+func (u *usageSet) get(bucketID int64) *bucketUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.buckets == nil {
+		u.buckets = make(map[int64]*bucketUsage)
+	}
+
+	usage, ok := u.buckets[bucketID]
+	if !ok {
+		usage = &bucketUsage{}
+		u.buckets[bucketID] = usage
+	}
+
+	return usage
+}
+
+// store manages the objects of a bucket stored in a directory.
+//
+// Each object is stored in a file named after the SHA-256 hash of its key, next to a JSON file holding
+// its metadata. Multipart uploads are kept in a directory per upload until they are completed.
+//
+// The space used by the bucket is tracked incrementally: writes reserve their size before any data is
+// written and the space is released when objects or parts are replaced or deleted.
+type store struct {
+	bucketName string
+	path       string
+	size       int64
+	locks      *lockSet
+	usage      *bucketUsage
+}
+
+// reserve accounts for the given number of bytes about to be written to the bucket.
+// If check is true, the reservation fails with errQuotaExceeded if it would exceed the bucket size.
+func (s *store) reserve(size int64, check bool) error {
+	s.usage.mu.Lock()
+	defer s.usage.mu.Unlock()
+
+	if !s.usage.loaded {
+		used, err := s.diskUsage()
+		if err != nil {
+			return err
+		}
+
+		s.usage.used = used
+		s.usage.loaded = true
+	}
+
+	if check && s.size > 0 && s.usage.used+size > s.size {
+		return errQuotaExceeded
+	}
+
+	s.usage.used += size
+
+	return nil
+}
+
+// release accounts for the given number of bytes being freed from the bucket.
+func (s *store) release(size int64) {
+	s.usage.mu.Lock()
+	defer s.usage.mu.Unlock()
+
+	if !s.usage.loaded {
+		return
+	}
+
+	s.usage.used = max(s.usage.used-size, 0)
+}
+
+// objectPaths returns the paths of the data and metadata files of the object with the given key.
+func (s *store) objectPaths(key string) (string, string) {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	dir := filepath.Join(s.path, objectsDir, name[:2])
+
+	return filepath.Join(dir, name), filepath.Join(dir, name+metadataSuffix)
+}
+
+// uploadPath returns the path of the directory of the multipart upload with the given ID.
+func (s *store) uploadPath(uploadID string) string {
+	return filepath.Join(s.path, uploadsDir, uploadID)
+}
+
+// isValidUploadID returns whether the given multipart upload ID has been generated by the store.
+func isValidUploadID(uploadID string) bool {
+	if len(uploadID) != 32 {
+		return false
+	}
+
+	_, err := hex.DecodeString(uploadID)
+
+	return err == nil
+}
+
+// storeError converts filesystem errors into their S3 equivalent.
+func storeError(err error) error {
+	if errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EDQUOT) {
+		return errQuotaExceeded
+	}
+
+	return err
+}
+
+// writeTemp writes the given reader into a temporary file, reading at most limit bytes.
+// It returns the path of the file, the number of bytes written and the MD5 sum of the content.
+func (s *store) writeTemp(r io.Reader, limit int64) (string, int64, []byte, error) {
+	err := os.MkdirAll(filepath.Join(s.path, tmpDir), 0o700)
+	if err != nil {
+		return "", -1, nil, err
+	}
+
+	f, err := os.CreateTemp(filepath.Join(s.path, tmpDir), "object-")
+	if err != nil {
+		return "", -1, nil, storeError(err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	h := md5.New()
+	size, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(r, limit+1))
+	if err == nil && size > limit {
+		err = errEntityTooLarge
+	}
+
+	if err == nil {
+		err = f.Close()
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name())
+
+		var s3Err *Error
+		if errors.As(err, &s3Err) {
+			return "", -1, nil, s3Err
+		}
+
+		return "", -1, nil, storeError(err)
+	}
+
+	return f.Name(), size, h.Sum(nil), nil
+}
+
+// writeJSON atomically writes the given value as JSON into the file at the given path.
+func (s *store) writeJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tmpPath, _, _, err := s.writeTemp(strings.NewReader(string(data)), int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// readJSON reads the JSON file at the given path into the given value.
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// commitObject moves the temporary file at the given path into place as the content of the given object.
+// The size of the object must have been reserved beforehand. On failure, the reservation is released.
+func (s *store) commitObject(tmpPath string, info objectInfo) error {
+	dataPath, metaPath := s.objectPaths(info.Key)
+
+	err := os.MkdirAll(filepath.Dir(dataPath), 0o700)
+	if err != nil {
+		s.release(info.Size)
+		return storeError(err)
+	}
+
+	lock := s.locks.get(s.bucketName, info.Key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// The replaced object, if any, no longer uses space once the new one is in place.
+	var oldInfo objectInfo
+	err = readJSON(metaPath, &oldInfo)
+	if err != nil {
+		oldInfo.Size = 0
+	}
+
+	err = os.Rename(tmpPath, dataPath)
+	if err != nil {
+		s.release(info.Size)
+		return err
+	}
+
+	s.release(oldInfo.Size)
+
+	return storeError(s.writeJSON(metaPath, info))
+}
+
+// statObject returns the metadata of the given object.
+func (s *store) statObject(key string) (*objectInfo, error) {
+	_, metaPath := s.objectPaths(key)
+
+	lock := s.locks.get(s.bucketName, key)
+	lock.RLock()
+	defer lock.RUnlock()
+
+	info := &objectInfo{}
+	err := readJSON(metaPath, info)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errNoSuchKey
+		}
+
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// openObject opens the content of the given object and returns it along with its metadata.
+func (s *store) openObject(key string) (*os.File, *objectInfo, error) {
+	dataPath, metaPath := s.objectPaths(key)
+
+	lock := s.locks.get(s.bucketName, key)
+	lock.RLock()
+	defer lock.RUnlock()
+
+	info := &objectInfo{}
+	err := readJSON(metaPath, info)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, errNoSuchKey
+		}
+
+		return nil, nil, err
+	}
+
+	f, err := os.Open(dataPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, errNoSuchKey
+		}
+
+		return nil, nil, err
+	}
+
+	return f, info, nil
+}
+
+// deleteObject deletes the given object. Deleting a missing object isn't an error.
+func (s *store) deleteObject(key string) error {
+	dataPath, metaPath := s.objectPaths(key)
+
+	lock := s.locks.get(s.bucketName, key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	var info objectInfo
+	err := readJSON(metaPath, &info)
+	if err != nil {
+		info.Size = 0
+	}
+
+	err = os.Remove(metaPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	err = os.Remove(dataPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	s.release(info.Size)
+
+	return nil
+}
+
+// listObjects returns the metadata of all the objects sorted by key.
+func (s *store) listObjects() ([]objectInfo, error) {
+	var objects []objectInfo
+
+	err := filepath.WalkDir(filepath.Join(s.path, objectsDir), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), metadataSuffix) {
+			return nil
+		}
+
+		var info objectInfo
+		err = readJSON(path, &info)
+		if err != nil {
+			// The object may have been deleted in the meantime.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		objects = append(objects, info)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(objects, func(a objectInfo, b objectInfo) int { return strings.Compare(a.Key, b.Key) })
+
+	return objects, nil
+}
+
+// diskUsage returns the number of bytes used by the content of the objects and of the parts of pending uploads.
+// It walks the whole bucket, so it is only used to initialise the usage tracking of the bucket.
+func (s *store) diskUsage() (int64, error) {
+	var total int64
+
+	err := filepath.WalkDir(s.path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() && path == filepath.Join(s.path, tmpDir) {
+			return filepath.SkipDir
+		}
+
+		if !entry.Type().IsRegular() || strings.HasSuffix(entry.Name(), metadataSuffix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		total += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	return total, nil
+}
+
+// createUpload records a new multipart upload.
+func (s *store) createUpload(info *uploadInfo) error {
+	path := s.uploadPath(info.ID)
+
+	err := os.MkdirAll(path, 0o700)
+	if err != nil {
+		return storeError(err)
+	}
+
+	err = s.writeJSON(filepath.Join(path, uploadMetadata), info)
+	if err != nil {
+		_ = os.RemoveAll(path)
+		return storeError(err)
+	}
+
+	return nil
+}
+
+// loadUpload returns the multipart upload with the given ID.
+func (s *store) loadUpload(uploadID string) (*uploadInfo, error) {
+	if !isValidUploadID(uploadID) {
+		return nil, errNoSuchUpload
+	}
+
+	info := &uploadInfo{ID: uploadID}
+	err := readJSON(filepath.Join(s.uploadPath(uploadID), uploadMetadata), info)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errNoSuchUpload
+		}
+
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// listUploads returns all the pending multipart uploads sorted by key.
+func (s *store) listUploads() ([]uploadInfo, error) {
+	entries, err := os.ReadDir(filepath.Join(s.path, uploadsDir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var uploads []uploadInfo
+	for _, entry := range entries {
+		info, err := s.loadUpload(entry.Name())
+		if err != nil {
+			continue // Ignore uploads being created or deleted.
+		}
+
+		uploads = append(uploads, *info)
+	}
+
+	slices.SortFunc(uploads, func(a uploadInfo, b uploadInfo) int { return strings.Compare(a.Key, b.Key) })
+
+	return uploads, nil
+}
+
+// uploadLock returns the lock of the given multipart upload.
+func (s *store) uploadLock(uploadID string) *sync.RWMutex {
+	return s.locks.get(s.bucketName, uploadsDir+"/"+uploadID)
+}
+
+// deleteUpload deletes the given multipart upload along with its parts.
+func (s *store) deleteUpload(uploadID string) error {
+	lock := s.uploadLock(uploadID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	parts, err := s.listParts(uploadID)
+	if err != nil {
+		if errors.Is(err, errNoSuchUpload) {
+			return nil
+		}
+
+		return err
+	}
+
+	err = os.RemoveAll(s.uploadPath(uploadID))
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		s.release(part.Size)
+	}
+
+	return nil
+}
+
+// commitPart moves the temporary file at the given path into place as the content of a part of an upload.
+// The size of the part must have been reserved beforehand. On failure, the reservation is released.
+func (s *store) commitPart(uploadID string, tmpPath string, info partInfo) error {
+	partPath := filepath.Join(s.uploadPath(uploadID), partPrefix+strconv.Itoa(info.Number))
+
+	lock := s.uploadLock(uploadID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// A part uploaded again with the same number replaces the previous one.
+	var oldInfo partInfo
+	err := readJSON(partPath+metadataSuffix, &oldInfo)
+	if err != nil {
+		oldInfo.Size = 0
+	}
+
+	err = os.Rename(tmpPath, partPath)
+	if err != nil {
+		s.release(info.Size)
+
+		if errors.Is(err, fs.ErrNotExist) {
+			return errNoSuchUpload
+		}
+
+		return err
+	}
+
+	s.release(oldInfo.Size)
+
+	return storeError(s.writeJSON(partPath+metadataSuffix, info))
+}
+
+// listParts returns the parts of the given upload sorted by number.
+func (s *store) listParts(uploadID string) ([]partInfo, error) {
+	entries, err := os.ReadDir(s.uploadPath(uploadID))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errNoSuchUpload
+		}
+
+		return nil, err
+	}
+
+	var parts []partInfo
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), partPrefix) || !strings.HasSuffix(entry.Name(), metadataSuffix) {
+			continue
+		}
+
+		var info partInfo
+		err := readJSON(filepath.Join(s.uploadPath(uploadID), entry.Name()), &info)
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, info)
+	}
+
+	slices.SortFunc(parts, func(a partInfo, b partInfo) int { return a.Number - b.Number })
+
+	return parts, nil
+}
+
+// openPart opens the content of the given part of an upload.
+func (s *store) openPart(uploadID string, number int) (*os.File, error) {
+	return os.Open(filepath.Join(s.uploadPath(uploadID), partPrefix+strconv.Itoa(number)))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"slices"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/canonical/lxd/lxd/apparmor"
 	"github.com/canonical/lxd/lxd/archive"
+	"github.com/canonical/lxd/lxd/backup"
 	"github.com/canonical/lxd/lxd/config"
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
@@ -32,11 +34,13 @@ import (
 	"github.com/canonical/lxd/lxd/state"
 	"github.com/canonical/lxd/lxd/storage/block"
 	"github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/storage/keystore"
 	"github.com/canonical/lxd/lxd/sys"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/ioprogress"
 	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/validate"
 )
 
@@ -199,7 +203,9 @@ func VolumeDBGet(pool Pool, projectName string, volumeName string, volumeType dr
 // VolumeDBCreate creates a volume in the database.
 // If volumeConfig is supplied, it is modified with any driver level default config options (if not set).
 // If removeUnknownKeys is true, any unknown config keys are removed from volumeConfig rather than failing.
-func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDescription string, volumeType drivers.VolumeType, snapshot bool, volumeConfig map[string]string, creationDate time.Time, expiryDate time.Time, contentType drivers.ContentType, removeUnknownKeys bool, hasSource bool) error {
+// If newEncryptionKey is true, the volume is being newly formatted, and a new encryption key is generated if it is
+// encrypted and has no key usable by this server. Otherwise the volume must have a usable key.
+func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDescription string, volumeType drivers.VolumeType, snapshot bool, volumeConfig map[string]string, creationDate time.Time, expiryDate time.Time, contentType drivers.ContentType, removeUnknownKeys bool, hasSource bool, newEncryptionKey bool) error {
 	p, ok := pool.(*lxdBackend)
 	if !ok {
 		return errors.New("Pool is not a lxdBackend")
@@ -249,6 +255,29 @@ func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDesc
 		return err
 	}
 
+	// Set encryption key.
+	if snapshot {
+		// Snapshots share the encryption of their parent volume.
+		parentName, _, _ := api.GetParentAndSnapshotName(volumeName)
+		parentVol, err := VolumeDBGet(pool, projectName, parentName, volumeType)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range []string{"security.encrypted", "volatile.encryption.key"} {
+			if parentVol.Config[key] != "" {
+				vol.Config()[key] = parentVol.Config[key]
+			} else {
+				delete(vol.Config(), key)
+			}
+		}
+	} else {
+		err = ensureVolumeEncryptionKey(p.state, vol, newEncryptionKey, p.driver.Info().Remote)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Validate config.
 	err = pool.Driver().ValidateVolume(vol, removeUnknownKeys)
 	if err != nil {
@@ -285,6 +314,85 @@ func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDesc
 	return nil
 }
 
+// ensureVolumeEncryptionKey sets the wrapped encryption key of encrypted volumes in "volatile.encryption.key".
+// The keys of volumes on remote storage pools are wrapped with the cluster key so that every cluster member can
+// unlock them, the others with the key of this server. An existing key wrapped the other way is wrapped again.
+// When newKey is true, the volume is being written to a newly formatted device, and a new key is generated if the
+// volume has none or if its key was wrapped by another server, for example when importing a backup or receiving a
+// volume from another server. Otherwise, such as when recovering a volume which already exists on disk, an error is
+// returned as a new key couldn't unlock the volume.
+func ensureVolumeEncryptionKey(s *state.State, vol drivers.Volume, newKey bool, clusterWide bool) error {
+	config := vol.Config()
+	if !vol.IsEncrypted() {
+		delete(config, "volatile.encryption.key")
+		return nil
+	}
+
+	wrapKey := keystore.WrapKey
+	if clusterWide {
+		wrapKey = keystore.WrapClusterKey
+	}
+
+	key := keystore.NewKey()
+	if config["volatile.encryption.key"] != "" {
+		existingKey, err := keystore.UnwrapKey(s, config["volatile.encryption.key"])
+		if err == nil && keystore.IsClusterWrapped(config["volatile.encryption.key"]) == clusterWide {
+			return nil
+		}
+
+		if err == nil {
+			key = existingKey
+		} else if !errors.Is(err, keystore.ErrKeyUnwrap) || !newKey {
+			return fmt.Errorf("Failed unwrapping the encryption key of volume %q: %w", vol.Name(), err)
+		}
+	} else if !newKey {
+		return fmt.Errorf("Encrypted volume %q has no encryption key", vol.Name())
+	}
+
+	wrappedKey, err := wrapKey(s, key)
+	if err != nil {
+		return fmt.Errorf("Failed wrapping volume encryption key: %w", err)
+	}
+
+	config["volatile.encryption.key"] = wrappedKey
+	return nil
+}
+
+// checkOptimizedBackupKey returns an error if an optimized backup of an encrypted volume is restored with a new key.
+// Optimized backups hold the encrypted data as is, so they can only be restored on the server that created them.
+func checkOptimizedBackupKey(srcBackup backup.Info, vol drivers.Volume, wrappedKey string) error {
+	if !vol.IsEncrypted() || srcBackup.OptimizedStorage == nil || !*srcBackup.OptimizedStorage {
+		return nil
+	}
+
+	if vol.Config()["volatile.encryption.key"] != wrappedKey {
+		return errors.New("Optimized backups of encrypted volumes can only be restored on the server that created them")
+	}
+
+	return nil
+}
+
+// SourceMigrationTypes returns the migration types offered when migrating the given volume.
+// Encrypted volumes are only offered non-optimized types, as optimized types send the encrypted data as is and the
+// target, which has its own encryption keys, could not unlock it.
+func SourceMigrationTypes(pool Pool, projectName string, volName string, volType drivers.VolumeType, contentType drivers.ContentType, copySnapshots bool) ([]migration.Type, error) {
+	// The refresh argument is always false as only the migration sink needs to know whether it is a refresh.
+	types := pool.MigrationTypes(contentType, false, copySnapshots)
+
+	dbVol, err := VolumeDBGet(pool, projectName, volName, volType)
+	if err != nil {
+		return nil, err
+	}
+
+	if shared.IsFalseOrEmpty(dbVol.Config["security.encrypted"]) {
+		return types, nil
+	}
+
+	return slices.DeleteFunc(types, func(t migration.Type) bool {
+		return t.FSType != migration.MigrationFSType_RSYNC && t.FSType != migration.MigrationFSType_BLOCK_AND_RSYNC
+	}), nil
+}
+
 // VolumeDBDelete deletes a volume from the database.
 func VolumeDBDelete(pool Pool, projectName string, volumeName string, volumeType drivers.VolumeType) error {
 	p, ok := pool.(*lxdBackend)
@@ -373,7 +481,8 @@ func BucketDBCreate(ctx context.Context, pool Pool, projectName string, bucket *
 
 	err = p.state.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Create the database entry for the storage bucket.
-		bucketID, err = tx.CreateStoragePoolBucket(ctx, p.ID(), projectName, *bucket)
+		// Buckets on local pools are associated to the member they were created on.
+		bucketID, err = tx.CreateStoragePoolBucket(ctx, p.ID(), projectName, !pool.Driver().Info().Remote, *bucket)
 
 		return err
 	})
@@ -421,7 +530,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  shortdesc: Size of the storage pool (for loop-based pools)
 		//  scope: local
 
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs; group=volume-conf; key=size)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs; group=volume-conf; key=size)
 		//
 		// ---
 		//  type: string
@@ -430,14 +539,14 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  shortdesc: Size/quota of the storage volume
 		//  scope: global
 
-		// lxdmeta:generate(entities=storage-cephobject; group=bucket-conf; key=size)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephobject,storage-dir,storage-lvm,storage-zfs; group=bucket-conf; key=size)
 		//
 		// ---
 		//  type: string
 		//  shortdesc: Quota of the storage bucket
 		//  scope: local
 		"size": validate.Optional(validate.IsSize),
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=snapshots.expiry)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=snapshots.expiry)
 		// Specify an expression like `1M 2H 3d 4w 5m 6y`.
 		// ---
 		//  type: string
@@ -450,7 +559,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 			_, err := shared.GetExpiry(time.Time{}, value)
 			return err
 		},
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=snapshots.schedule)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=snapshots.schedule)
 		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic snapshots (the default).
 		// ---
 		//  type: string
@@ -459,7 +568,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  shortdesc: Schedule for automatic volume snapshots
 		//  scope: global
 		"snapshots.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=snapshots.pattern)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=snapshots.pattern)
 		// You can specify a naming template for scheduled snapshots and unnamed snapshots.
 		//
 		// {{snapshot_pattern_detail}}
@@ -470,11 +579,42 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  shortdesc: Template for the snapshot name
 		//  scope: global
 		"snapshots.pattern": validate.IsAny,
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=backups.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups (the default).
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.schedule`
+		//  shortdesc: Schedule for automatic volume backups
+		//  scope: global
+		"backups.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=backups.expiry)
+		// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.expiry`
+		//  shortdesc: Time until scheduled backups are deleted
+		//  scope: global
+		"backups.expiry": func(value string) error {
+			// Validate expression
+			_, err := shared.GetExpiry(time.Time{}, value)
+			return err
+		},
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=backups.retain)
+		// Only the given number of most recent scheduled backups is kept. Backups created manually are never deleted.
+		// ---
+		//  type: integer
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.backups.retain` or unlimited
+		//  shortdesc: Number of scheduled backups to keep
+		//  scope: global
+		"backups.retain": validate.Optional(validate.IsInRange(1, 4294967295)),
 	}
 
 	// security.shifted and security.unmapped are only relevant for custom filesystem volumes.
 	if vol == nil || (vol.Type() == drivers.VolumeTypeCustom && vol.ContentType() == drivers.ContentTypeFS) {
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=security.shifted)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=security.shifted)
 		// Enable this option to allow the volume to be attached to multiple isolated instances.
 		// ---
 		//  type: bool
@@ -483,7 +623,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  shortdesc: Enable ID shifting overlay
 		//  scope: global
 		rules["security.shifted"] = validate.Optional(validate.IsBool)
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=security.unmapped)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=security.unmapped)
 		//
 		// ---
 		//  type: bool
@@ -508,9 +648,120 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		rules["security.shared"] = validate.Optional(validate.IsBool)
 	}
 
+	// I/O limits are only relevant for custom volumes.
+	if vol == nil || vol.Type() == drivers.VolumeTypeCustom {
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.iops.read)
+		// The limit is a budget shared by all the instances the volume is attached to on a cluster member.
+		// The limits of the disk devices attaching the volume also apply to each attachment.
+		// See {ref}`storage-configure-IO`.
+		// ---
+		//  type: integer
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.limits.iops.read` or no limit
+		//  shortdesc: Read I/O limit in IOPS
+		//  scope: global
+		rules["limits.iops.read"] = validate.Optional(validate.IsInRange(1, math.MaxInt64))
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.iops.write)
+		// The limit is a budget shared by all the instances the volume is attached to on a cluster member.
+		// The limits of the disk devices attaching the volume also apply to each attachment.
+		// See {ref}`storage-configure-IO`.
+		// ---
+		//  type: integer
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.limits.iops.write` or no limit
+		//  shortdesc: Write I/O limit in IOPS
+		//  scope: global
+		rules["limits.iops.write"] = validate.Optional(validate.IsInRange(1, math.MaxInt64))
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.bandwidth.read)
+		// Specify a value in byte/s (various suffixes supported, see {ref}`instances-limit-units`).
+		// The limit is a budget shared by all the instances the volume is attached to on a cluster member.
+		// The limits of the disk devices attaching the volume also apply to each attachment.
+		// See {ref}`storage-configure-IO`.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.limits.bandwidth.read` or no limit
+		//  shortdesc: Read I/O limit in byte/s
+		//  scope: global
+		rules["limits.bandwidth.read"] = validate.Optional(validate.IsSize)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=limits.bandwidth.write)
+		// Specify a value in byte/s (various suffixes supported, see {ref}`instances-limit-units`).
+		// The limit is a budget shared by all the instances the volume is attached to on a cluster member.
+		// The limits of the disk devices attaching the volume also apply to each attachment.
+		// See {ref}`storage-configure-IO`.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: same as `volume.limits.bandwidth.write` or no limit
+		//  shortdesc: Write I/O limit in byte/s
+		//  scope: global
+		rules["limits.bandwidth.write"] = validate.Optional(validate.IsSize)
+	}
+
+	// Mirroring is only supported for custom volumes.
+	if vol != nil && vol.Type() == drivers.VolumeTypeCustom {
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=mirror.pool)
+		// Setting this option enables the mirroring of the volume to the given storage pool.
+		// See {ref}`storage-mirror-volume`.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  shortdesc: Storage pool to mirror the volume to
+		//  scope: global
+		rules["mirror.pool"] = validate.Optional(func(value string) error {
+			if value == vol.Pool() {
+				return errors.New("A volume cannot be mirrored to its own storage pool")
+			}
+
+			return nil
+		})
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=mirror.volume)
+		//
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: name of the volume
+		//  shortdesc: Name of the mirror volume
+		//  scope: global
+		rules["mirror.volume"] = validate.Optional(drivers.ValidVolumeName)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=mirror.target)
+		// This option only applies in a cluster when `mirror.pool` is a local storage pool.
+		// It is required when the volume itself is on a remote storage pool.
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: cluster member of the volume
+		//  shortdesc: Cluster member to mirror the volume to
+		//  scope: global
+		rules["mirror.target"] = validate.IsAny
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=mirror.schedule)
+		// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`) or a comma-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`).
+		// ---
+		//  type: string
+		//  condition: custom volume
+		//  defaultdesc: `@hourly`
+		//  shortdesc: Schedule for the refreshes of the mirror volume
+		//  scope: global
+		rules["mirror.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.mirror.source)
+		// This option is set on mirror volumes and cleared when the mirror volume is promoted.
+		// ---
+		//  type: string
+		//  shortdesc: UUID of the volume that is mirrored
+		//  scope: global
+		rules["volatile.mirror.source"] = validate.Optional(validate.IsUUID)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.mirror.last_refresh)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: Start time of the last successful mirror refresh
+		//  scope: global
+		rules["volatile.mirror.last_refresh"] = validate.IsAny
+	}
+
 	// Those keys are only valid for volumes.
 	if vol != nil {
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.uuid)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.uuid)
 		//
 		// ---
 		//  type: string
@@ -519,7 +770,7 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		//  scope: global
 		rules["volatile.uuid"] = validate.Optional(validate.IsUUID)
 
-		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.devlxd.owner)
+		// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.devlxd.owner)
 		//
 		// ---
 		//  type: string
@@ -544,7 +795,7 @@ func validatePoolCommonRules() map[string]func(string) error {
 		//  shortdesc: Whether to wipe the block device before creating the pool
 		//  scope: local
 		"source.wipe": validate.Optional(validate.IsBool),
-		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-btrfs,storage-zfs,storage-ceph,storage-cephfs; group=pool-conf; key=source.recover)
+		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-nfs,storage-btrfs,storage-zfs,storage-ceph,storage-cephfs; group=pool-conf; key=source.recover)
 		// Set this option to true to recover an existing source which was previously created by LXD.
 		// ---
 		//  type: bool
@@ -553,7 +804,7 @@ func validatePoolCommonRules() map[string]func(string) error {
 		//  scope: local
 		"source.recover":          validate.Optional(validate.IsBool),
 		"volatile.initial_source": validate.IsAny,
-		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=pool-conf; key=rsync.bwlimit)
+		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-nfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=pool-conf; key=rsync.bwlimit)
 		// When `rsync` must be used to transfer storage entities, this option specifies the upper limit
 		// to be placed on the socket I/O.
 		// ---
@@ -562,7 +813,7 @@ func validatePoolCommonRules() map[string]func(string) error {
 		//  shortdesc: Upper limit on the socket I/O for `rsync`
 		//  scope: global
 		"rsync.bwlimit": validate.Optional(validate.IsSize),
-		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=pool-conf; key=rsync.compression)
+		// lxdmeta:generate(entities=storage-dir,storage-lvm,storage-nfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=pool-conf; key=rsync.compression)
 		//
 		// ---
 		//  type: bool
@@ -620,14 +871,14 @@ func validateLocalPoolCommonRules() map[string]func(string) error {
 func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error {
 	rules := poolAndVolumeCommonRules(&vol)
 
-	// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.idmap.last)
+	// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.idmap.last)
 	//
 	// ---
 	//   type: string
 	//   shortdesc: JSON-serialized UID/GID map that has been applied to the volume
 	//   condition: filesystem
 
-	// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.idmap.next)
+	// lxdmeta:generate(entities=storage-btrfs,storage-cephfs,storage-ceph,storage-dir,storage-lvm,storage-nfs,storage-zfs,storage-powerflex,storage-powerstore,storage-pure,storage-alletra; group=volume-conf; key=volatile.idmap.next)
 	//
 	// ---
 	//   type: string
@@ -657,6 +908,58 @@ func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error
 	return rules
 }
 
+// VolumeIOLimits parses the I/O limits of a custom volume from its config.
+// A zero value means that there is no limit.
+func VolumeIOLimits(config map[string]string) (readBps int64, readIops int64, writeBps int64, writeIops int64, err error) {
+	parseBps := func(key string) (int64, error) {
+		if config[key] == "" {
+			return 0, nil
+		}
+
+		value, err := units.ParseByteSizeString(config[key])
+		if err != nil {
+			return -1, fmt.Errorf("Failed parsing %q: %w", key, err)
+		}
+
+		return value, nil
+	}
+
+	parseIops := func(key string) (int64, error) {
+		if config[key] == "" {
+			return 0, nil
+		}
+
+		value, err := strconv.ParseInt(config[key], 10, 64)
+		if err != nil {
+			return -1, fmt.Errorf("Failed parsing %q: %w", key, err)
+		}
+
+		return value, nil
+	}
+
+	readBps, err = parseBps("limits.bandwidth.read")
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+
+	readIops, err = parseIops("limits.iops.read")
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+
+	writeBps, err = parseBps("limits.bandwidth.write")
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+
+	writeIops, err = parseIops("limits.iops.write")
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+
+	return readBps, readIops, writeBps, writeIops, nil
+}
+
 // ImageUnpack unpacks a filesystem image into the destination path.
 // There are several formats that images can come in:
 // Container Format A: Separate metadata tarball and root squashfs file.
@@ -1419,3 +1722,22 @@ func VolumeDetermineNextSnapshotName(ctx context.Context, s *state.State, pool s
 
 	return pattern, nil
 }
+
+// backupSnapshotNames returns the names of the snapshots (oldest first) to include in a backup.
+// Incremental backups sent from baseSnapshot only include the snapshots more recent than the base.
+func backupSnapshotNames(snapNames []string, snapshots bool, baseSnapshot string) ([]string, error) {
+	if baseSnapshot == "" {
+		return snapNames, nil
+	}
+
+	included, err := backup.IncrementalSnapshots(snapNames, baseSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if !snapshots {
+		return nil, nil
+	}
+
+	return included, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/storage/s3"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// storageBucketsServer returns the server serving the S3 API of the buckets on local storage pools.
+func storageBucketsServer(d *Daemon) *http.Server {
+	return &http.Server{
+		Handler:           s3.NewHandler(&storageBucketsBackend{d: d}),
+		IdleTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+}
+
+// storageBucketsBackend provides the buckets on the local storage pools of this member to the S3 API handler.
+type storageBucketsBackend struct {
+	d *Daemon
+}
+
+// LoadBucketByAccessKey returns the local bucket that the given access key belongs to, along with its keys.
+func (b *storageBucketsBackend) LoadBucketByAccessKey(ctx context.Context, accessKey string) (*s3.Bucket, error) {
+	var dbBucket *db.StorageBucket
+	var dbKeys []*db.StorageBucketKey
+
+	err := b.d.State().DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbBucket, err = tx.GetStoragePoolLocalBucketByAccessKey(ctx, accessKey)
+		if err != nil {
+			return err
+		}
+
+		dbKeys, err = tx.GetStoragePoolBucketKeys(ctx, dbBucket.ID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := &s3.Bucket{
+		ID:      dbBucket.ID,
+		Name:    dbBucket.Name,
+		Project: dbBucket.Project,
+		Pool:    dbBucket.PoolName,
+		Keys:    make([]s3.Key, 0, len(dbKeys)),
+	}
+
+	if dbBucket.Config["size"] != "" {
+		bucket.Size, err = units.ParseByteSizeString(dbBucket.Config["size"])
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing size of storage bucket %q: %w", dbBucket.Name, err)
+		}
+	}
+
+	for _, dbKey := range dbKeys {
+		bucket.Keys = append(bucket.Keys, s3.Key{
+			AccessKey: dbKey.AccessKey,
+			SecretKey: dbKey.SecretKey,
+			ReadOnly:  dbKey.Role == "read-only",
+		})
+	}
+
+	return bucket, nil
+}
+
+// MountBucket mounts the volume holding the bucket contents.
+func (b *storageBucketsBackend) MountBucket(_ context.Context, bucket *s3.Bucket) (string, func(), error) {
+	pool, err := storagePools.LoadByName(b.d.State(), bucket.Pool)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed loading storage pool: %w", err)
+	}
+
+	path, err := pool.MountBucket(bucket.Project, bucket.Name, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed mounting storage bucket: %w", err)
+	}
+
+	unmount := func() {
+		_, err := pool.UnmountBucket(bucket.Project, bucket.Name, nil)
+		if err != nil {
+			logger.Debug("Failed unmounting storage bucket", logger.Ctx{"project": bucket.Project, "pool": bucket.Pool, "bucket": bucket.Name, "err": err})
+		}
+	}
+
+	return path, unmount, nil
+}
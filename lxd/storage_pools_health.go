@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var storagePoolHealthCmd = APIEndpoint{
+	Path:        "storage-pools/{name}/health",
+	MetricsType: entity.TypeStoragePool,
+
+	Get: APIEndpointAction{Handler: storagePoolHealthGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewResources)},
+}
+
+// storagePoolHealthCache holds the health of the local storage pools as of the last run of the health check task.
+var storagePoolHealthCache map[string]*api.StoragePoolHealth
+var storagePoolHealthCacheMu sync.Mutex
+
+// swagger:operation GET /1.0/storage-pools/{name}/health storage storage_pool_health_get
+//
+//	Get the storage pool health
+//
+//	Gets the health of the storage pool and the devices backing it on the cluster member.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	responses:
+//	  "200":
+//	    description: Storage pool health
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/StoragePoolHealth"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+//	  "501":
+//	    $ref: "#/responses/NotImplemented"
+func storagePoolHealthGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	poolName := r.PathValue("name")
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	health, err := pool.GetHealth()
+	if err != nil {
+		if errors.Is(err, storageDrivers.ErrNotSupported) {
+			return response.NotImplemented(fmt.Errorf("Storage pool driver %q does not support health checks", pool.Driver().Info().Name))
+		}
+
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, health)
+}
+
+// storagePoolsHealthCheckTask returns a task that checks the health of the local storage pools every five minutes.
+func storagePoolsHealthCheckTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := storagePoolsHealthCheck(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed checking storage pools health", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(5 * time.Minute)
+}
+
+// storagePoolsHealthCheck checks the health of the local storage pools, raising a warning for each pool which is
+// not healthy and resolving it once the pool recovers.
+func storagePoolsHealthCheck(ctx context.Context, s *state.State) error {
+	var poolNames []string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+		return err
+	})
+	if err != nil && !response.IsNotFoundError(err) {
+		return fmt.Errorf("Failed loading storage pool names: %w", err)
+	}
+
+	healths := make(map[string]*api.StoragePoolHealth, len(poolNames))
+	for _, poolName := range poolNames {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			logger.Warn("Failed loading storage pool for health check", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		health, err := pool.GetHealth()
+		if err != nil {
+			if errors.Is(err, storageDrivers.ErrNotSupported) {
+				continue
+			}
+
+			health = &api.StoragePoolHealth{
+				State:   api.StoragePoolHealthStateUnavailable,
+				Message: err.Error(),
+				Devices: []api.StoragePoolHealthDevice{},
+			}
+		}
+
+		healths[poolName] = health
+
+		if health.State == api.StoragePoolHealthStateHealthy {
+			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningtype.StoragePoolDegraded, entity.TypeStoragePool, int(pool.ID()))
+			if err != nil {
+				logger.Warn("Failed resolving storage pool degraded warning", logger.Ctx{"pool": poolName, "err": err})
+			}
+
+			continue
+		}
+
+		logger.Warn("Storage pool is not healthy", logger.Ctx{"pool": poolName, "state": health.State, "message": health.Message})
+
+		message := "Storage pool is " + health.State
+		if health.Message != "" {
+			message += ": " + health.Message
+		}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, "", entity.TypeStoragePool, int(pool.ID()), warningtype.StoragePoolDegraded, message)
+		})
+		if err != nil {
+			logger.Warn("Failed creating storage pool degraded warning", logger.Ctx{"pool": poolName, "err": err})
+		}
+	}
+
+	storagePoolHealthCacheMu.Lock()
+	storagePoolHealthCache = healths
+	storagePoolHealthCacheMu.Unlock()
+
+	return nil
+}
+
+// storagePoolHealthMetrics adds the health of the local storage pools as of the last health check to the metric set.
+func storagePoolHealthMetrics(out *metrics.MetricSet) {
+	storagePoolHealthCacheMu.Lock()
+	defer storagePoolHealthCacheMu.Unlock()
+
+	for poolName, health := range storagePoolHealthCache {
+		for _, state := range []string{api.StoragePoolHealthStateHealthy, api.StoragePoolHealthStateDegraded, api.StoragePoolHealthStateUnavailable} {
+			var value float64
+			if health.State == state {
+				value = 1
+			}
+
+			out.AddSamples(metrics.StoragePoolHealthState, metrics.Sample{
+				Labels: map[string]string{"pool": poolName, "state": state},
+				Value:  value,
+			})
+		}
+
+		for _, device := range health.Devices {
+			for errorType, count := range map[string]uint64{"read": device.ReadErrors, "write": device.WriteErrors, "checksum": device.ChecksumErrors} {
+				out.AddSamples(metrics.StoragePoolDeviceErrorsTotal, metrics.Sample{
+					Labels: map[string]string{"pool": poolName, "device": device.Name, "type": errorType},
+					Value:  float64(count),
+				})
+			}
+		}
+
+		if health.Scrub != nil && health.Scrub.Status == api.StoragePoolScrubStatusCompleted {
+			out.AddSamples(metrics.StoragePoolScrubErrors, metrics.Sample{
+				Labels: map[string]string{"pool": poolName},
+				Value:  float64(health.Scrub.Errors),
+			})
+
+			out.AddSamples(metrics.StoragePoolScrubTimestampSeconds, metrics.Sample{
+				Labels: map[string]string{"pool": poolName},
+				Value:  float64(health.Scrub.FinishedAt.Unix()),
+			})
+		}
+	}
+}
+
+// autoScrubStoragePoolsTask returns a task that starts the scrubs of the storage pools whose schedule is due.
+func autoScrubStoragePoolsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := autoScrubStoragePools(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed running scheduled storage pool scrub task", logger.Ctx{"err": err})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoScrubStoragePools starts the scrubs of the storage pools whose scrub.schedule is due.
+// Local pools are scrubbed by every member while remote pools are scrubbed by a single online member.
+func autoScrubStoragePools(ctx context.Context, s *state.State) error {
+	var poolNames []string
+	var memberCount int
+	var onlineMemberIDs []int64
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		poolNames, err = tx.GetCreatedStoragePoolNames(ctx)
+		if err != nil && !response.IsNotFoundError(err) {
+			return fmt.Errorf("Failed loading storage pool names: %w", err)
+		}
+
+		members, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		memberCount = len(members)
+
+		for _, member := range members {
+			if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+				continue
+			}
+
+			onlineMemberIDs = append(onlineMemberIDs, member.ID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	localMemberID := s.DB.Cluster.GetNodeID()
+
+	for _, poolName := range poolNames {
+		pool, err := storagePools.LoadByName(s, poolName)
+		if err != nil {
+			logger.Warn("Failed loading storage pool for scrub task", logger.Ctx{"pool": poolName, "err": err})
+			continue
+		}
+
+		schedule := pool.Driver().Config()["scrub.schedule"]
+		if schedule == "" || !snapshotIsScheduledNow(schedule, pool.ID()) {
+			continue
+		}
+
+		// A stable random online member is chosen to scrub remote pools, spreading the load across the
+		// cluster. Skip them if there are no online members, as the cluster may be partitioned.
+		if pool.Driver().Info().Remote && memberCount > 1 {
+			if len(onlineMemberIDs) <= 0 {
+				logger.Error("Skipping remote storage pool scrub due to no online members", logger.Ctx{"pool": poolName})
+				continue
+			}
+
+			selectedMemberID, err := util.GetStableRandomInt64FromList(pool.ID(), onlineMemberIDs)
+			if err != nil {
+				logger.Error("Failed scheduling remote storage pool scrub", logger.Ctx{"pool": poolName, "err": err})
+				continue
+			}
+
+			if localMemberID != selectedMemberID {
+				continue
+			}
+		}
+
+		logger.Info("Starting scheduled storage pool scrub", logger.Ctx{"pool": poolName})
+
+		err = pool.Scrub()
+		if err != nil {
+			logger.Error("Failed starting scheduled storage pool scrub", logger.Ctx{"pool": poolName, "err": err})
+		}
+	}
+
+	return nil
+}
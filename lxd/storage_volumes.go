@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"crypto/x509"
 	"encoding/json"
@@ -1093,6 +1094,19 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		// Check that the project is allowed to mirror the volume as requested.
+		if req.Config["mirror.pool"] != "" {
+			targetMember := ""
+			if s.ServerClustered {
+				targetMember = req.Config["mirror.target"]
+			}
+
+			err = volumeMirrorTargetCheck(ctx, s, tx, projectName, cmp.Or(req.Config["mirror.volume"], req.Name), req.ContentType, req.Config, targetMember, true)
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -2300,11 +2314,24 @@ func storagePoolVolumePut(d *Daemon, r *http.Request) response.Response {
 					return err
 				}
 
+				err = storagePoolVolumeMirrorCheck(ctx, s, effectiveProjectName, dbVolume, req.Config)
+				if err != nil {
+					return err
+				}
+
 				err = details.pool.UpdateCustomVolume(ctx, effectiveProjectName, dbVolume.Name, req.Description, req.Config, op)
 				if err != nil {
 					return err
 				}
+
+				if req.Config != nil && storagePoolVolumeLimitsChanged(dbVolume.Config, req.Config) {
+					err = storagePoolVolumeApplyLimits(s, details.pool, effectiveProjectName, dbVolume.Name)
+					if err != nil {
+						return err
+					}
+				}
 			}
+
 		case cluster.StoragePoolVolumeTypeContainer, cluster.StoragePoolVolumeTypeVM:
 			inst, err := instance.LoadByProjectAndName(s, effectiveProjectName, dbVolume.Name)
 			if err != nil {
@@ -2459,7 +2486,21 @@ func storagePoolVolumePatch(d *Daemon, r *http.Request) response.Response {
 	}
 
 	run := func(ctx context.Context, op *operations.Operation) error {
-		return details.pool.UpdateCustomVolume(ctx, effectiveProjectName, dbVolume.Name, req.Description, req.Config, op)
+		err := storagePoolVolumeMirrorCheck(ctx, s, effectiveProjectName, dbVolume, req.Config)
+		if err != nil {
+			return err
+		}
+
+		err = details.pool.UpdateCustomVolume(ctx, effectiveProjectName, dbVolume.Name, req.Description, req.Config, op)
+		if err != nil {
+			return err
+		}
+
+		if storagePoolVolumeLimitsChanged(dbVolume.Config, req.Config) {
+			return storagePoolVolumeApplyLimits(s, details.pool, effectiveProjectName, dbVolume.Name)
+		}
+
+		return nil
 	}
 
 	volumeURL := entity.StorageVolumeURL(effectiveProjectName, details.location, details.pool.Name(), details.volumeTypeName, details.volumeName)
@@ -2892,6 +2933,32 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		return response.InternalError(err)
 	}
 
+	// Incremental backups modify an existing volume so require permission to edit it.
+	if bInfo.BaseSnapshot != "" {
+		targetPool, err := storagePools.LoadByName(s, bInfo.Pool)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		var location string
+		if s.ServerClustered && !targetPool.Driver().Info().Remote {
+			location = s.ServerName
+		}
+
+		volumeURL := entity.StorageVolumeURL(bInfo.Project, location, bInfo.Pool, cluster.StoragePoolVolumeTypeNameCustom, bInfo.Name)
+		err = s.Authorizer.CheckPermission(r.Context(), volumeURL, auth.EntitlementCanEdit)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if len(bInfo.Snapshots) > 0 {
+			err = s.Authorizer.CheckPermission(r.Context(), volumeURL, auth.EntitlementCanManageSnapshots)
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+	}
+
 	// Copy reverter so far so we can use it inside run after this function has finished.
 	runRevert := revert.Clone()
 
@@ -2909,6 +2976,17 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 			return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
 		}
 
+		// Incremental backups are applied on top of the existing volume.
+		if bInfo.BaseSnapshot != "" {
+			err = pool.RefreshCustomVolumeFromBackup(ctx, *bInfo, backupFile, op)
+			if err != nil {
+				return fmt.Errorf("Apply incremental backup to custom volume: %w", err)
+			}
+
+			runRevert.Success()
+			return nil
+		}
+
 		// Dump tarball to storage.
 		err = pool.CreateCustomVolumeFromBackup(ctx, *bInfo, backupFile, op)
 		if err != nil {
@@ -377,30 +377,7 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			return response.BadRequest(err)
 		}
 
-		base := details.volumeName + shared.SnapshotDelimiter + "backup"
-		length := len(base)
-		backupNo := 0
-
-		// Iterate over previous backups to autoincrement the backup number.
-		for _, backup := range backups {
-			// Ignore backups not containing base.
-			if !strings.HasPrefix(backup, base) {
-				continue
-			}
-
-			substr := backup[length:]
-			var num int
-			count, err := fmt.Sscanf(substr, "%d", &num)
-			if err != nil || count != 1 {
-				continue
-			}
-
-			if num >= backupNo {
-				backupNo = num + 1
-			}
-		}
-
-		req.Name = fmt.Sprintf("backup%d", backupNo)
+		req.Name = backupNextName(details.volumeName, backups, "backup")
 	}
 
 	// In case no version was selected for the backup format use the globally set format by default.
@@ -421,6 +398,32 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 	fullName := details.volumeName + shared.SnapshotDelimiter + backupName
 	volumeOnly := req.VolumeOnly
 
+	// Find the snapshot an incremental backup is sent from.
+	var baseSnapshot string
+	if req.IncrementalFrom != "" {
+		if !req.OptimizedStorage {
+			return response.BadRequest(errors.New("Incremental backups require the optimized storage format"))
+		}
+
+		if !details.pool.Driver().Info().IncrementalBackups {
+			return response.BadRequest(fmt.Errorf("Storage pool %q does not support incremental backups", details.pool.Name()))
+		}
+
+		prevFullName := details.volumeName + shared.SnapshotDelimiter + req.IncrementalFrom
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := tx.GetStoragePoolVolumeBackup(ctx, effectiveProjectName, details.pool.Name(), prevFullName)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed loading backup %q: %w", req.IncrementalFrom, err))
+		}
+
+		baseSnapshot, err = backupIncrementalBase(s, filepath.Join(s.BackupsStoragePath(effectiveProjectName), "custom", details.pool.Name(), project.StorageVolume(effectiveProjectName, prevFullName)))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
 	backup := func(ctx context.Context, op *operations.Operation) error {
 		args := db.StoragePoolVolumeBackup{
 			Name:                 fullName,
@@ -432,7 +435,7 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			CompressionAlgorithm: req.CompressionAlgorithm,
 		}
 
-		err := volumeBackupCreate(s, args, effectiveProjectName, details.pool.Name(), details.volumeName, req.Version)
+		err := volumeBackupCreate(s, args, effectiveProjectName, details.pool.Name(), details.volumeName, baseSnapshot, req.Version)
 		if err != nil {
 			return fmt.Errorf("Create volume backup: %w", err)
 		}
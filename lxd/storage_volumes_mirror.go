@@ -0,0 +1,626 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	lxdCluster "github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/locking"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	storagePools "github.com/canonical/lxd/lxd/storage"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// volumeMirrorDefaultSchedule is used to refresh the mirrors of the volumes which don't set mirror.schedule.
+const volumeMirrorDefaultSchedule = "@hourly"
+
+// volumeMirrorsRefreshTask returns a task that refreshes the mirrors of the custom volumes whose schedule is due.
+func volumeMirrorsRefreshTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := autoRefreshVolumeMirrors(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed running scheduled volume mirror refresh task", logger.Ctx{"err": err})
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+// autoRefreshVolumeMirrors refreshes the mirrors of the custom volumes whose mirror.schedule is due.
+// Volumes on local pools are handled by their own member while volumes on remote pools are handled by a
+// single online member.
+func autoRefreshVolumeMirrors(ctx context.Context, s *state.State) error {
+	var volumes, remoteVolumes []db.StorageVolumeArgs
+	var memberCount int
+	var onlineMemberIDs []int64
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, dbCluster.StoragePoolVolumeTypeCustom, true)
+		if err != nil {
+			return fmt.Errorf("Failed getting volumes for volume mirror refresh task: %w", err)
+		}
+
+		for _, v := range allVolumes {
+			if v.Config["mirror.pool"] == "" {
+				continue
+			}
+
+			schedule := cmp.Or(v.Config["mirror.schedule"], volumeMirrorDefaultSchedule)
+			if !snapshotIsScheduledNow(schedule, v.ID) {
+				continue
+			}
+
+			if v.NodeID < 0 {
+				// Keep a separate list of remote volumes in order to select a member to
+				// perform the refresh later.
+				remoteVolumes = append(remoteVolumes, v)
+			} else {
+				logger.Debug("Scheduling local volume mirror refresh", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+				volumes = append(volumes, v) // Always include local volumes.
+			}
+		}
+
+		if len(remoteVolumes) > 0 {
+			members, err := tx.GetNodes(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed getting cluster members: %w", err)
+			}
+
+			memberCount = len(members)
+
+			for _, member := range members {
+				if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+					continue
+				}
+
+				onlineMemberIDs = append(onlineMemberIDs, member.ID)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting volume mirror schedule info: %w", err)
+	}
+
+	if len(remoteVolumes) > 0 {
+		// Skip refreshing the mirrors of remote custom volumes if there are no online members, as we can't
+		// be sure that the cluster isn't partitioned and we may end up refreshing them from multiple members.
+		if memberCount > 1 && len(onlineMemberIDs) <= 0 {
+			logger.Error("Skipping remote volumes for volume mirror refresh task due to no online members")
+		} else {
+			localMemberID := s.DB.Cluster.GetNodeID()
+
+			for _, v := range remoteVolumes {
+				// If there are multiple cluster members, a stable random member is chosen
+				// to perform the refresh from. This spreads the load across the online cluster members.
+				if memberCount > 1 {
+					selectedMemberID, err := util.GetStableRandomInt64FromList(v.ID, onlineMemberIDs)
+					if err != nil {
+						logger.Error("Failed scheduling remote volume mirror refresh task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+						continue
+					}
+
+					if localMemberID != selectedMemberID {
+						continue
+					}
+				}
+
+				logger.Debug("Scheduling remote volume mirror refresh", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+				volumes = append(volumes, v)
+			}
+		}
+	}
+
+	// Refresh the mirrors sequentially, each one in its own operation so that a refresh requested
+	// through the API for the same volume is rejected while the scheduled one is running.
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		l := logger.AddContext(logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+
+		pool, err := storagePools.LoadByName(s, v.PoolName)
+		if err != nil {
+			l.Error("Failed loading pool for volume mirror refresh", logger.Ctx{"err": err})
+			continue
+		}
+
+		op, err := operations.ScheduleServerOperation(s, volumeMirrorRefreshOperationArgs(s, pool, v.ProjectName, v.Name, v.ID))
+		if err != nil {
+			l.Warn("Failed creating scheduled volume mirror refresh operation", logger.Ctx{"err": err})
+			continue
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			l.Error("Failed scheduled volume mirror refresh", logger.Ctx{"err": err})
+		}
+	}
+
+	return nil
+}
+
+// volumeMirrorVolumeURL returns the URL of a custom volume, including its location if it is on a local pool.
+func volumeMirrorVolumeURL(s *state.State, pool storagePools.Pool, projectName string, volName string) *api.URL {
+	location := ""
+	if s.ServerClustered && !pool.Driver().Info().Remote {
+		location = s.ServerName
+	}
+
+	return entity.StorageVolumeURL(projectName, location, pool.Name(), dbCluster.StoragePoolVolumeTypeNameCustom, volName)
+}
+
+// volumeMirrorRefreshOperationArgs returns the arguments of an operation refreshing the mirror of a custom volume.
+// A failed refresh raises a warning on the volume which is resolved by the next successful one.
+func volumeMirrorRefreshOperationArgs(s *state.State, pool storagePools.Pool, projectName string, volName string, volID int64) operations.OperationArgs {
+	run := func(ctx context.Context, op *operations.Operation) error {
+		refreshErr := refreshVolumeMirror(ctx, s, pool, projectName, volName, op)
+		if refreshErr == nil {
+			err := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, projectName, warningtype.VolumeMirrorRefreshFailure, entity.TypeStorageVolume, int(volID))
+			if err != nil {
+				logger.Warn("Failed resolving volume mirror refresh failure warning", logger.Ctx{"err": err})
+			}
+
+			return nil
+		}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.UpsertWarningLocalNode(ctx, projectName, entity.TypeStorageVolume, int(volID), warningtype.VolumeMirrorRefreshFailure, refreshErr.Error())
+		})
+		if err != nil {
+			logger.Warn("Failed creating volume mirror refresh failure warning", logger.Ctx{"err": err})
+		}
+
+		return refreshErr
+	}
+
+	volumeURL := volumeMirrorVolumeURL(s, pool, projectName, volName)
+
+	return operations.OperationArgs{
+		ProjectName:       projectName,
+		EntityURL:         volumeURL,
+		Type:              operationtype.VolumeMirrorRefresh,
+		Class:             operationtype.OperationClassTask,
+		ConflictReference: volumeURL.String(), // Prevents concurrent refreshes of the same mirror across the cluster.
+		RunHook:           run,
+	}
+}
+
+// volumeMirrorOperationLock acquires a lock for refreshing or promoting the mirror volume with the given name and
+// returns the unlock function.
+func volumeMirrorOperationLock(ctx context.Context, poolName string, projectName string, volName string) (locking.UnlockFunc, error) {
+	l := logger.AddContext(logger.Ctx{"pool": poolName, "project": projectName, "volume": volName})
+	l.Debug("Acquiring lock for mirror volume")
+	defer l.Debug("Lock acquired for mirror volume")
+
+	return locking.Lock(ctx, "VolumeMirrorOperation_"+poolName+"/"+project.StorageVolume(projectName, volName))
+}
+
+// refreshVolumeMirror creates the mirror of a custom volume on its first refresh and then incrementally
+// refreshes it, including its snapshots, from the source volume.
+func refreshVolumeMirror(ctx context.Context, s *state.State, srcPool storagePools.Pool, projectName string, volName string, op *operations.Operation) error {
+	var srcVol *db.StorageVolume
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		srcVol, err = tx.GetStoragePoolVolume(ctx, srcPool.ID(), projectName, dbCluster.StoragePoolVolumeTypeCustom, volName, true)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading volume %q: %w", volName, err)
+	}
+
+	targetPoolName := srcVol.Config["mirror.pool"]
+	if targetPoolName == "" {
+		return api.StatusErrorf(http.StatusBadRequest, "Volume %q is not mirrored", volName)
+	}
+
+	srcUUID := srcVol.Config["volatile.uuid"]
+	if srcUUID == "" {
+		return fmt.Errorf(`Volume %q is missing the required "volatile.uuid" setting`, volName)
+	}
+
+	targetPool, err := storagePools.LoadByName(s, targetPoolName)
+	if err != nil {
+		return fmt.Errorf("Failed loading mirror storage pool %q: %w", targetPoolName, err)
+	}
+
+	targetVolName := cmp.Or(srcVol.Config["mirror.volume"], volName)
+
+	// Prevent the mirror from being promoted while it is being refreshed.
+	unlock, err := volumeMirrorOperationLock(ctx, targetPoolName, projectName, targetVolName)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	// Volumes on local pools live on a specific cluster member, by default the one of the source volume.
+	targetMember := ""
+	if s.ServerClustered && !targetPool.Driver().Info().Remote {
+		targetMember = srcVol.Config["mirror.target"]
+		if targetMember == "" {
+			if srcPool.Driver().Info().Remote {
+				return fmt.Errorf(`Mirroring volume %q to local storage pool %q requires "mirror.target" to be set`, volName, targetPoolName)
+			}
+
+			targetMember = s.ServerName
+		}
+	}
+
+	var targetVol *db.StorageVolume
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		targetVol, err = volumeMirrorTargetGet(ctx, tx, targetPool.ID(), projectName, targetVolName, targetMember)
+		if err != nil && !response.IsNotFoundError(err) {
+			return fmt.Errorf("Failed loading mirror volume %q: %w", targetVolName, err)
+		}
+
+		// The project restrictions may have changed since mirroring was enabled.
+		return volumeMirrorTargetCheck(ctx, s, tx, projectName, targetVolName, srcVol.ContentType, srcVol.Config, targetMember, targetVol == nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Never overwrite a volume which isn't a mirror of this volume.
+	if targetVol != nil && targetVol.Config["volatile.mirror.source"] != srcUUID {
+		return api.StatusErrorf(http.StatusConflict, "Volume %q in storage pool %q is not a mirror of volume %q", targetVolName, targetPoolName, volName)
+	}
+
+	// The start of the refresh is the point in time the mirror can be recovered to.
+	refreshedAt := time.Now().UTC()
+
+	targetConfig := volumeMirrorTargetConfig(srcVol.Config, srcUUID)
+
+	if targetMember == "" || targetMember == s.ServerName {
+		if targetVol == nil {
+			err = targetPool.CreateCustomVolumeFromCopy(ctx, projectName, projectName, targetVolName, srcVol.Description, targetConfig, srcPool.Name(), volName, true, op)
+		} else {
+			err = targetPool.RefreshCustomVolume(ctx, projectName, projectName, targetVolName, "", nil, srcPool.Name(), volName, true, op)
+		}
+	} else {
+		err = refreshVolumeMirrorOnMember(ctx, s, targetMember, projectName, srcPool.Name(), srcVol, targetPoolName, targetVolName, targetConfig, targetVol != nil)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed refreshing mirror volume %q in storage pool %q: %w", targetVolName, targetPoolName, err)
+	}
+
+	// Record the refresh on both volumes so that the lag of the mirror remains known if the source is lost.
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		curSrcVol, err := tx.GetStoragePoolVolume(ctx, srcPool.ID(), projectName, dbCluster.StoragePoolVolumeTypeCustom, volName, true)
+		if err != nil {
+			return err
+		}
+
+		// The mirroring may have been stopped while the volume was being copied.
+		if curSrcVol.Config["mirror.pool"] != targetPoolName {
+			return api.StatusErrorf(http.StatusConflict, "Mirroring of volume %q to storage pool %q was stopped during the refresh", volName, targetPoolName)
+		}
+
+		curTargetVol, err := volumeMirrorTargetGet(ctx, tx, targetPool.ID(), projectName, targetVolName, targetMember)
+		if err != nil {
+			return err
+		}
+
+		// The mirror may have been promoted or replaced by another volume on another cluster member. A mirror
+		// created by this refresh may not have recorded its source yet.
+		curSrcUUID := curTargetVol.Config["volatile.mirror.source"]
+		if curSrcUUID != srcUUID && (targetVol != nil || curSrcUUID != "") {
+			return api.StatusErrorf(http.StatusConflict, "Volume %q in storage pool %q is no longer a mirror of volume %q", targetVolName, targetPoolName, volName)
+		}
+
+		curSrcVol.Config["volatile.mirror.last_refresh"] = refreshedAt.Format(time.RFC3339)
+		curTargetVol.Config["volatile.mirror.last_refresh"] = refreshedAt.Format(time.RFC3339)
+		curTargetVol.Config["volatile.mirror.source"] = srcUUID
+
+		err = tx.UpdateStorageVolumeConfig(ctx, curSrcVol.ID, curSrcVol.Config)
+		if err != nil {
+			return err
+		}
+
+		return tx.UpdateStorageVolumeConfig(ctx, curTargetVol.ID, curTargetVol.Config)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed recording refresh of mirror volume %q: %w", targetVolName, err)
+	}
+
+	return nil
+}
+
+// refreshVolumeMirrorOnMember creates or refreshes the mirror of a custom volume on a local pool of another
+// cluster member. The member pulls the volume from this member like for a copy between cluster members.
+func refreshVolumeMirrorOnMember(ctx context.Context, s *state.State, member string, projectName string, srcPoolName string, srcVol *db.StorageVolume, targetPoolName string, targetVolName string, targetConfig map[string]string, refresh bool) error {
+	var address string
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		memberInfo, err := tx.GetNodeByName(ctx, member)
+		if err != nil {
+			return fmt.Errorf("Failed loading cluster member %q: %w", member, err)
+		}
+
+		if memberInfo.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			return fmt.Errorf("Cluster member %q is offline", member)
+		}
+
+		address = memberInfo.Address
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	client, err := lxdCluster.Connect(ctx, address, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to cluster member %q: %w", member, err)
+	}
+
+	client = client.UseProject(projectName).UseTarget(member)
+
+	req := api.StorageVolumesPost{
+		Name:        targetVolName,
+		Type:        dbCluster.StoragePoolVolumeTypeNameCustom,
+		ContentType: srcVol.ContentType,
+		StorageVolumePut: api.StorageVolumePut{
+			Description: srcVol.Description,
+			Config:      targetConfig,
+		},
+		Source: api.StorageVolumeSource{
+			Type:     api.SourceTypeCopy,
+			Pool:     srcPoolName,
+			Name:     srcVol.Name,
+			Project:  projectName,
+			Location: s.ServerName,
+			Refresh:  refresh,
+		},
+	}
+
+	op, err := client.CreateStoragePoolVolume(targetPoolName, req)
+	if err != nil {
+		return err
+	}
+
+	return op.WaitContext(ctx)
+}
+
+// volumeMirrorTargetCheck checks that the project is allowed to use the storage pool and cluster member the
+// mirror of a custom volume with the given config is placed on. If create is true, it also checks that creating
+// the mirror doesn't exceed the project limits.
+func volumeMirrorTargetCheck(ctx context.Context, s *state.State, tx *db.ClusterTx, projectName string, targetVolName string, contentType string, config map[string]string, targetMember string, create bool) error {
+	targetPoolName := config["mirror.pool"]
+
+	_, err := tx.GetStoragePoolID(ctx, targetPoolName)
+	if err != nil {
+		return fmt.Errorf("Failed loading mirror storage pool %q: %w", targetPoolName, err)
+	}
+
+	hiddenPools, err := limits.HiddenStoragePools(ctx, tx, projectName)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(hiddenPools, targetPoolName) {
+		return api.StatusErrorf(http.StatusForbidden, "Project %q is not allowed to use storage pool %q", projectName, targetPoolName)
+	}
+
+	if targetMember != "" {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return fmt.Errorf("Failed loading project %q: %w", projectName, err)
+		}
+
+		p, err := dbProject.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		members, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		_, err = limits.CheckTargetMember(p, targetMember, members)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !create {
+		return nil
+	}
+
+	return limits.AllowVolumeCreation(ctx, s.GlobalConfig, tx, projectName, targetPoolName, api.StorageVolumesPost{
+		Name:        targetVolName,
+		Type:        dbCluster.StoragePoolVolumeTypeNameCustom,
+		ContentType: contentType,
+		StorageVolumePut: api.StorageVolumePut{
+			Config: volumeMirrorTargetConfig(config, config["volatile.uuid"]),
+		},
+		Source: api.StorageVolumeSource{
+			Type: api.SourceTypeCopy,
+		},
+	})
+}
+
+// storagePoolVolumeMirrorCheck checks that the project is allowed to mirror a custom volume as requested by
+// newConfig when its mirroring settings are changed.
+func storagePoolVolumeMirrorCheck(ctx context.Context, s *state.State, projectName string, dbVolume *db.StorageVolume, newConfig map[string]string) error {
+	if newConfig["mirror.pool"] == "" {
+		return nil
+	}
+
+	changed := false
+	for _, key := range []string{"mirror.pool", "mirror.volume", "mirror.target"} {
+		if dbVolume.Config[key] != newConfig[key] {
+			changed = true
+			break
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	targetMember := ""
+	if s.ServerClustered {
+		targetMember = newConfig["mirror.target"]
+	}
+
+	targetVolName := cmp.Or(newConfig["mirror.volume"], dbVolume.Name)
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return volumeMirrorTargetCheck(ctx, s, tx, projectName, targetVolName, dbVolume.ContentType, newConfig, targetMember, true)
+	})
+}
+
+// volumeMirrorTargetGet returns the custom volume with the given name in a pool, restricted to the given
+// cluster member if not empty.
+func volumeMirrorTargetGet(ctx context.Context, tx *db.ClusterTx, poolID int64, projectName string, volName string, member string) (*db.StorageVolume, error) {
+	volType := dbCluster.StoragePoolVolumeTypeCustom
+
+	vols, err := tx.GetStorageVolumes(ctx, false, db.StorageVolumeFilter{
+		Type:    &volType,
+		Project: &projectName,
+		Name:    &volName,
+		PoolID:  &poolID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vol := range vols {
+		if member == "" || vol.Location == member {
+			return vol, nil
+		}
+	}
+
+	return nil, api.StatusErrorf(http.StatusNotFound, "Storage volume not found")
+}
+
+// volumeMirrorTargetConfig returns the config of the mirror of a volume with the given config.
+// The mirror doesn't inherit the mirroring and volatile keys, nor the automatic snapshot and backup schedules
+// as its snapshots are kept in sync with the ones of the source volume.
+func volumeMirrorTargetConfig(config map[string]string, sourceUUID string) map[string]string {
+	targetConfig := make(map[string]string, len(config))
+	for k, v := range config {
+		if strings.HasPrefix(k, "mirror.") || strings.HasPrefix(k, "volatile.") || k == "snapshots.schedule" || k == "backups.schedule" {
+			continue
+		}
+
+		targetConfig[k] = v
+	}
+
+	targetConfig["volatile.mirror.source"] = sourceUUID
+
+	return targetConfig
+}
+
+// volumeMirrorState returns the mirroring state of a custom volume with the given config, or nil if the volume
+// is neither mirrored nor a mirror.
+func volumeMirrorState(config map[string]string, now time.Time) *api.StorageVolumeStateMirror {
+	mirror := &api.StorageVolumeStateMirror{Lag: -1}
+
+	switch {
+	case config["mirror.pool"] != "":
+		mirror.Role = api.StorageVolumeMirrorRoleSource
+	case config["volatile.mirror.source"] != "":
+		mirror.Role = api.StorageVolumeMirrorRoleTarget
+	default:
+		return nil
+	}
+
+	lastRefresh, err := time.Parse(time.RFC3339, config["volatile.mirror.last_refresh"])
+	if err == nil {
+		mirror.LastRefreshAt = lastRefresh
+		mirror.Lag = int64(now.Sub(lastRefresh) / time.Second)
+	}
+
+	return mirror
+}
+
+// promoteVolumeMirror turns the mirror of a custom volume into a regular volume and stops the mirroring of the
+// source volume if it still exists. As only the database is updated, this works even if the source is lost.
+func promoteVolumeMirror(ctx context.Context, s *state.State, pool storagePools.Pool, projectName string, volName string) error {
+	// Wait for any refresh of the mirror to complete so that it isn't recorded on the promoted volume.
+	unlock, err := volumeMirrorOperationLock(ctx, pool.Name(), projectName, volName)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		vol, err := tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, dbCluster.StoragePoolVolumeTypeCustom, volName, true)
+		if err != nil {
+			return err
+		}
+
+		srcUUID := vol.Config["volatile.mirror.source"]
+		if srcUUID == "" {
+			return api.StatusErrorf(http.StatusBadRequest, "Volume %q is not a mirror", volName)
+		}
+
+		srcVols, err := tx.GetStorageVolumes(ctx, false, db.StorageVolumeFilter{Project: &projectName, UUIDs: []string{srcUUID}})
+		if err != nil {
+			return fmt.Errorf("Failed loading source volume: %w", err)
+		}
+
+		for _, srcVol := range srcVols {
+			if shared.IsSnapshot(srcVol.Name) {
+				continue
+			}
+
+			srcConfig := maps.Clone(srcVol.Config)
+			maps.DeleteFunc(srcConfig, func(k string, _ string) bool {
+				return strings.HasPrefix(k, "mirror.") || strings.HasPrefix(k, "volatile.mirror.")
+			})
+
+			err = tx.UpdateStorageVolumeConfig(ctx, srcVol.ID, srcConfig)
+			if err != nil {
+				return fmt.Errorf("Failed stopping the mirroring of volume %q: %w", srcVol.Name, err)
+			}
+		}
+
+		config := maps.Clone(vol.Config)
+		maps.DeleteFunc(config, func(k string, _ string) bool {
+			return strings.HasPrefix(k, "volatile.mirror.")
+		})
+
+		return tx.UpdateStorageVolumeConfig(ctx, vol.ID, config)
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestVolumeMirrorTargetConfig(t *testing.T) {
+	config := map[string]string{
+		"size":                         "10GiB",
+		"security.shifted":             "true",
+		"snapshots.expiry":             "1w",
+		"snapshots.schedule":           "@daily",
+		"backups.schedule":             "@weekly",
+		"mirror.pool":                  "backup",
+		"mirror.schedule":              "@hourly",
+		"volatile.uuid":                "f6a1f46d-0a2b-4f1d-8a48-5b7b1a62ff3c",
+		"volatile.mirror.last_refresh": "2025-01-01T00:00:00Z",
+	}
+
+	targetConfig := volumeMirrorTargetConfig(config, config["volatile.uuid"])
+
+	assert.Equal(t, map[string]string{
+		"size":                   "10GiB",
+		"security.shifted":       "true",
+		"snapshots.expiry":       "1w",
+		"volatile.mirror.source": "f6a1f46d-0a2b-4f1d-8a48-5b7b1a62ff3c",
+	}, targetConfig)
+
+	// The source config must be left untouched.
+	assert.Equal(t, "backup", config["mirror.pool"])
+}
+
+func TestVolumeMirrorState(t *testing.T) {
+	now := time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)
+	lastRefresh := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   *api.StorageVolumeStateMirror
+	}{
+		{
+			name:   "Not mirrored",
+			config: map[string]string{"size": "10GiB"},
+			want:   nil,
+		},
+		{
+			name:   "Source never refreshed",
+			config: map[string]string{"mirror.pool": "backup"},
+			want:   &api.StorageVolumeStateMirror{Role: api.StorageVolumeMirrorRoleSource, Lag: -1},
+		},
+		{
+			name: "Source refreshed",
+			config: map[string]string{
+				"mirror.pool":                  "backup",
+				"volatile.mirror.last_refresh": lastRefresh.Format(time.RFC3339),
+			},
+			want: &api.StorageVolumeStateMirror{Role: api.StorageVolumeMirrorRoleSource, LastRefreshAt: lastRefresh, Lag: 3600},
+		},
+		{
+			name: "Target refreshed",
+			config: map[string]string{
+				"volatile.mirror.source":       "f6a1f46d-0a2b-4f1d-8a48-5b7b1a62ff3c",
+				"volatile.mirror.last_refresh": lastRefresh.Format(time.RFC3339),
+			},
+			want: &api.StorageVolumeStateMirror{Role: api.StorageVolumeMirrorRoleTarget, LastRefreshAt: lastRefresh, Lag: 3600},
+		},
+		{
+			name: "Invalid last refresh",
+			config: map[string]string{
+				"volatile.mirror.source":       "f6a1f46d-0a2b-4f1d-8a48-5b7b1a62ff3c",
+				"volatile.mirror.last_refresh": "invalid",
+			},
+			want: &api.StorageVolumeStateMirror{Role: api.StorageVolumeMirrorRoleTarget, Lag: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, volumeMirrorState(tt.config, now))
+		})
+	}
+}
@@ -52,6 +52,14 @@ var storagePoolVolumeSnapshotTypeCmd = APIEndpoint{
 	Put:    APIEndpointAction{Handler: storagePoolVolumeSnapshotTypePut, AccessHandler: storagePoolVolumeTypeAccessHandler(auth.EntitlementCanEdit)},
 }
 
+var storagePoolVolumeSnapshotTypeDiffCmd = APIEndpoint{
+	Path:            "storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName}/diff",
+	MetricsType:     entity.TypeStoragePool,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: storagePoolVolumeSnapshotTypeDiffGet, AccessHandler: storagePoolVolumeTypeAccessHandler(auth.EntitlementCanView)},
+}
+
 // swagger:operation POST /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots storage storage_pool_volumes_type_snapshots_post
 //
 //	Create a storage volume snapshot
@@ -1025,6 +1033,107 @@ func storagePoolVolumeSnapshotTypeDelete(d *Daemon, r *http.Request) response.Re
 	return response.OperationResponse(op)
 }
 
+// swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/snapshots/{snapshotName}/diff storage storage_pool_volumes_type_snapshot_diff_get
+//
+//	Compare the storage volume snapshot
+//
+//	Returns the paths which were added, removed or modified between the storage volume snapshot
+//	and either a later snapshot or the storage volume itself.
+//	The request fails if more than 10000 paths differ.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	  - in: query
+//	    name: against
+//	    description: Name of a later snapshot to compare with (defaults to the storage volume itself)
+//	    type: string
+//	    example: snap1
+//	responses:
+//	  "200":
+//	    description: Changed paths
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of changed paths
+//	          items:
+//	            $ref: "#/definitions/SnapshotDiffEntry"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeSnapshotTypeDiffGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	details, err := request.GetContextValue[storageVolumeDetails](r.Context(), ctxStorageVolumeDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Check that the storage volume type is valid.
+	if details.volumeType != dbCluster.StoragePoolVolumeTypeCustom {
+		return response.BadRequest(fmt.Errorf("Invalid storage volume type %q", details.volumeTypeName))
+	}
+
+	// Forward if needed.
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
+	entries, err := details.pool.DiffCustomVolumeSnapshot(effectiveProjectName, details.volumeName, details.snapshotName, request.QueryParam(r, "against"), nil)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if entries == nil {
+		entries = []api.SnapshotDiffEntry{}
+	}
+
+	return response.SyncResponse(true, entries)
+}
+
 func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
 	f := func(ctx context.Context) {
 		s := stateFunc()
@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"slices"
+	"time"
 
 	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/device"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
@@ -16,6 +24,7 @@ import (
 	storageDrivers "github.com/canonical/lxd/lxd/storage/drivers"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/version"
 )
 
 var storagePoolVolumeTypeStateCmd = APIEndpoint{
@@ -24,6 +33,7 @@ var storagePoolVolumeTypeStateCmd = APIEndpoint{
 	ProjectSpecific: true,
 
 	Get: APIEndpointAction{Handler: storagePoolVolumeTypeStateGet, AccessHandler: allowPermission(entity.TypeStorageVolume, auth.EntitlementCanView, "poolName", "type", "volumeName")},
+	Put: APIEndpointAction{Handler: storagePoolVolumeTypeStatePut, AccessHandler: storagePoolVolumeTypeAccessHandler(auth.EntitlementCanEdit)},
 }
 
 // swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/state storage storage_pool_volume_type_state_get
@@ -112,12 +122,66 @@ func storagePoolVolumeTypeStateGet(d *Daemon, r *http.Request) response.Response
 
 	// Fetch the current usage.
 	var usage *storagePools.VolumeUsage
+	var volLimits *api.StorageVolumeStateLimits
+	var volMirror *api.StorageVolumeStateMirror
 	if volumeType == cluster.StoragePoolVolumeTypeCustom {
 		// Custom volumes.
 		usage, err = pool.GetCustomVolumeUsage(projectName, volumeName)
 		if err != nil && err != storageDrivers.ErrNotSupported {
 			return response.SmartError(err)
 		}
+
+		var dbVolume *db.StorageVolume
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			dbVolume, err = tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, volumeType, volumeName, true)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		readBps, readIops, writeBps, writeIops, err := storagePools.VolumeIOLimits(dbVolume.Config)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if readBps > 0 || readIops > 0 || writeBps > 0 || writeIops > 0 {
+			volLimits = &api.StorageVolumeStateLimits{
+				ReadBytes:  readBps,
+				WriteBytes: writeBps,
+				ReadIOps:   readIops,
+				WriteIOps:  writeIops,
+				Instances:  []string{},
+			}
+
+			// List the local instances sharing the limits.
+			err = storagePools.VolumeUsedByInstanceDevices(s, pool.Name(), projectName, &dbVolume.StorageVolume, true, func(dbInst db.InstanceArgs, p api.Project, usedByDevices []string) error {
+				if dbInst.Node != s.ServerName {
+					return nil
+				}
+
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return err
+				}
+
+				isShared, err := device.DiskVolumeLimitsShared(s, inst, usedByDevices)
+				if err != nil {
+					return err
+				}
+
+				if isShared {
+					volLimits.Instances = append(volLimits.Instances, api.NewURL().Path(version.APIVersion, "instances", inst.Name()).Project(inst.Project().Name).String())
+				}
+
+				return nil
+			})
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+
+		volMirror = volumeMirrorState(dbVolume.Config, time.Now())
 	} else {
 		resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, volumeName, instancetype.Any)
 		if err != nil {
@@ -141,7 +205,10 @@ func storagePoolVolumeTypeStateGet(d *Daemon, r *http.Request) response.Response
 	}
 
 	// Prepare the state struct.
-	state := api.StorageVolumeState{}
+	state := api.StorageVolumeState{
+		Limits: volLimits,
+		Mirror: volMirror,
+	}
 
 	if usage != nil {
 		state.Usage = &api.StorageVolumeStateUsage{}
@@ -159,3 +226,121 @@ func storagePoolVolumeTypeStateGet(d *Daemon, r *http.Request) response.Response
 
 	return response.SyncResponse(true, state)
 }
+
+// swagger:operation PUT /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/state storage storage_pool_volume_type_state_put
+//
+//	Update the storage volume state
+//
+//	Refreshes the mirror of a custom storage volume ("refresh" action) or
+//	promotes the mirror of a custom storage volume to a regular volume ("promote" action).
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: lxd01
+//	  - in: body
+//	    name: state
+//	    description: Storage volume state
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StorageVolumeStatePut"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeStatePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	details, err := request.GetContextValue[storageVolumeDetails](r.Context(), ctxStorageVolumeDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	effectiveProjectName, err := request.GetContextValue[string](r.Context(), request.CtxEffectiveProjectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if details.volumeType != cluster.StoragePoolVolumeTypeCustom {
+		return response.BadRequest(errors.New("Only custom volumes can be mirrored"))
+	}
+
+	target := request.QueryParam(r, "target")
+	resp := forwardedResponseToNode(r.Context(), s, target)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(r.Context(), s)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.StorageVolumeStatePut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var dbVolume *db.StorageVolume
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVolume, err = tx.GetStoragePoolVolume(ctx, details.pool.ID(), effectiveProjectName, details.volumeType, details.volumeName, true)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var args operations.OperationArgs
+	switch req.Action {
+	case "refresh":
+		if dbVolume.Config["mirror.pool"] == "" {
+			return response.BadRequest(fmt.Errorf("Volume %q is not mirrored", details.volumeName))
+		}
+
+		args = volumeMirrorRefreshOperationArgs(s, details.pool, effectiveProjectName, details.volumeName, dbVolume.ID)
+	case "promote":
+		if dbVolume.Config["volatile.mirror.source"] == "" {
+			return response.BadRequest(fmt.Errorf("Volume %q is not a mirror", details.volumeName))
+		}
+
+		args = operations.OperationArgs{
+			ProjectName: effectiveProjectName,
+			EntityURL:   volumeMirrorVolumeURL(s, details.pool, effectiveProjectName, details.volumeName),
+			Type:        operationtype.VolumeMirrorPromote,
+			Class:       operationtype.OperationClassTask,
+			RunHook: func(ctx context.Context, op *operations.Operation) error {
+				return promoteVolumeMirror(ctx, s, details.pool, effectiveProjectName, details.volumeName)
+			},
+		}
+
+	default:
+		return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.OperationResponse(op)
+}
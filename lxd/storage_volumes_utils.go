@@ -8,6 +8,7 @@ import (
 	"github.com/canonical/lxd/lxd/backup"
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/device"
 	"github.com/canonical/lxd/lxd/device/config"
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/state"
@@ -20,6 +21,67 @@ import (
 
 var supportedVolumeTypes = []cluster.StoragePoolVolumeType{cluster.StoragePoolVolumeTypeContainer, cluster.StoragePoolVolumeTypeVM, cluster.StoragePoolVolumeTypeCustom, cluster.StoragePoolVolumeTypeImage}
 
+// storagePoolVolumeLimitsChanged returns whether the I/O limits differ between the old and new custom volume config.
+func storagePoolVolumeLimitsChanged(oldConfig map[string]string, newConfig map[string]string) bool {
+	for _, key := range []string{"limits.iops.read", "limits.iops.write", "limits.bandwidth.read", "limits.bandwidth.write"} {
+		if oldConfig[key] != newConfig[key] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// storagePoolVolumeApplyLimits applies the I/O limits of a custom volume to the running instances of the local
+// member which use it, along with the budget they share. Instances running on other members pick up the new limits when they are next started.
+func storagePoolVolumeApplyLimits(s *state.State, pool storagePools.Pool, projectName string, volumeName string) error {
+	var dbVolume *db.StorageVolume
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		dbVolume, err = tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, cluster.StoragePoolVolumeTypeCustom, volumeName, true)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var instances []instance.Instance
+	var instancesDevNames [][]string
+
+	err = storagePools.VolumeUsedByInstanceDevices(s, pool.Name(), projectName, &dbVolume.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
+		if dbInst.Node != s.ServerName {
+			return nil
+		}
+
+		inst, err := instance.Load(s, dbInst, project)
+		if err != nil {
+			return err
+		}
+
+		instances = append(instances, inst)
+		instancesDevNames = append(instancesDevNames, usedByDevices)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, inst := range instances {
+		if !inst.IsRunning() {
+			continue
+		}
+
+		err = device.DiskApplyVolumeLimits(s, inst, instancesDevNames[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update the budget shared by the instances using the volume.
+	return device.DiskSyncVolumeLimits(s)
+}
+
 func storagePoolVolumeUpdateUsers(ctx context.Context, s *state.State, projectName string, oldPoolName string, oldVol *api.StorageVolume, newPoolName string, newVol *api.StorageVolume) (revert.Hook, error) {
 	revert := revert.New()
 	defer revert.Fail()
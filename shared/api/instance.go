@@ -39,6 +39,9 @@ const SourceTypeImage = SourceType("image")
 // SourceTypeCopy represents instance creation from a copy operation.
 const SourceTypeCopy = SourceType("copy")
 
+// SourceTypeBackup represents instance creation from a backup stored in an object store.
+const SourceTypeBackup = SourceType("backup")
+
 // SourceTypeNone represents an unknown source type for instance creation.
 const SourceTypeNone = SourceType("none")
 
@@ -629,6 +632,12 @@ type InstanceSource struct {
 	//
 	// API extension: override_snapshot_profiles_on_copy
 	OverrideSnapshotProfiles bool `json:"override_snapshot_profiles" yaml:"override_snapshot_profiles"`
+
+	// URL of the backup object of the S3 endpoint for backups to import, its key must start with "<project>/" (for backup)
+	// Example: s3://backups/default/c1/backup0
+	//
+	// API extension: backups_s3
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
 }
 
 // InstanceUEFIVars represents the UEFI variables of a LXD virtual machine.
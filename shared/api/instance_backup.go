@@ -43,6 +43,18 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_metadata_version
 	Version uint32 `json:"version" yaml:"version"`
+
+	// Name of a previous optimized backup of the instance to create an incremental backup from
+	// Example: backup0
+	//
+	// API extension: backups_incremental
+	IncrementalFrom string `json:"incremental_from" yaml:"incremental_from"`
+
+	// URL of an object of the S3 endpoint for backups to push the backup to instead of storing it locally, its key must start with "<project>/"
+	// Example: s3://backups/default/c1/backup0
+	//
+	// API extension: backups_s3
+	TargetURL string `json:"target_url" yaml:"target_url"`
 }
 
 // InstanceBackup represents a LXD instance backup.
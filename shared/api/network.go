@@ -213,6 +213,11 @@ type NetworkState struct {
 	//
 	// API extension: network_state_ovn
 	OVN *NetworkStateOVN `json:"ovn" yaml:"ovn"`
+
+	// Routes learned from the network's BGP peers
+	//
+	// API extension: network_bgp_import
+	BGP *NetworkStateBGP `json:"bgp" yaml:"bgp"`
 }
 
 // NetworkStateAddress represents a network address
@@ -347,3 +352,36 @@ type NetworkStateOVN struct {
 	// OVN network chassis name
 	Chassis string `json:"chassis" yaml:"chassis"`
 }
+
+// NetworkStateBGP represents the routes learned from the network's BGP peers
+//
+// swagger:model
+//
+// API extension: network_bgp_import.
+type NetworkStateBGP struct {
+	// List of routes learned from the BGP peers
+	Routes []NetworkStateBGPRoute `json:"routes" yaml:"routes"`
+}
+
+// NetworkStateBGPRoute represents a route learned from a BGP peer
+//
+// swagger:model
+//
+// API extension: network_bgp_import.
+type NetworkStateBGPRoute struct {
+	// Route prefix
+	// Example: 10.100.0.0/16
+	Prefix string `json:"prefix" yaml:"prefix"`
+
+	// Next-hop address
+	// Example: 192.0.2.10
+	Nexthop string `json:"nexthop" yaml:"nexthop"`
+
+	// Address of the peer the route was learned from
+	// Example: 192.0.2.1
+	Peer string `json:"peer" yaml:"peer"`
+
+	// Whether the route is installed in the routing table
+	// Example: true
+	Imported bool `json:"imported" yaml:"imported"`
+}
@@ -23,7 +23,7 @@ type NetworkACLRule struct {
 	Action string `json:"action" yaml:"action"`
 
 	// lxdmeta:generate(entities=network-acl; group=rule-properties; key=source)
-	// Sources can be specified as CIDR or IP ranges, source subject name selectors (for ingress rules), or be left empty for any.
+	// Sources can be specified as CIDR or IP ranges, network address set names prefixed with `$`, source subject name selectors (for ingress rules), or be left empty for any.
 	// ---
 	//  type: string
 	//  required: no
@@ -34,7 +34,7 @@ type NetworkACLRule struct {
 	Source string `json:"source,omitempty" yaml:"source,omitempty"`
 
 	// lxdmeta:generate(entities=network-acl; group=rule-properties; key=destination)
-	// Destinations can be specified as CIDR or IP ranges, destination subject name selectors (for egress rules), or be left empty for any.
+	// Destinations can be specified as CIDR or IP ranges, network address set names prefixed with `$`, destination subject name selectors (for egress rules), DNS names prefixed with `fqdn:` (for egress rules), or be left empty for any.
 	// ---
 	//  type: string
 	//  required: no
@@ -302,3 +302,85 @@ type NetworkACLsPost struct {
 	NetworkACLPost `yaml:",inline"`
 	NetworkACLPut  `yaml:",inline"`
 }
+
+// NetworkACLState represents the state of an ACL.
+//
+// swagger:model
+//
+// API extension: network_acl_counters.
+type NetworkACLState struct {
+	// Rule counters for each network using the ACL, either directly or through instance NICs
+	Networks []NetworkACLStateNetwork `json:"networks" yaml:"networks"`
+
+	// Rule counters for each instance NIC on an OVN network that sets the ACL in its own security.acls option
+	NICs []NetworkACLStateNIC `json:"nics" yaml:"nics"`
+}
+
+// NetworkACLStateNetwork represents the rule counters of an ACL on a network.
+//
+// swagger:model
+//
+// API extension: network_acl_counters.
+type NetworkACLStateNetwork struct {
+	// Name of the network
+	// Example: lxdbr0
+	Name string `json:"name" yaml:"name"`
+
+	// Name of the cluster member the counters were gathered on
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// Counters of the ingress rules (in the same order as the rules)
+	Ingress []NetworkACLRuleCounters `json:"ingress" yaml:"ingress"`
+
+	// Counters of the egress rules (in the same order as the rules)
+	Egress []NetworkACLRuleCounters `json:"egress" yaml:"egress"`
+}
+
+// NetworkACLStateNIC represents the rule counters of an ACL on an instance NIC.
+//
+// swagger:model
+//
+// API extension: network_acl_counters.
+type NetworkACLStateNIC struct {
+	// Name of the project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Name of the NIC device
+	// Example: eth0
+	Name string `json:"name" yaml:"name"`
+
+	// Name of the network of the NIC
+	// Example: ovn0
+	Network string `json:"network" yaml:"network"`
+
+	// Name of the cluster member the counters were gathered on
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// Counters of the ingress rules (in the same order as the rules)
+	Ingress []NetworkACLRuleCounters `json:"ingress" yaml:"ingress"`
+
+	// Counters of the egress rules (in the same order as the rules)
+	Egress []NetworkACLRuleCounters `json:"egress" yaml:"egress"`
+}
+
+// NetworkACLRuleCounters represents the packet and byte counters of an ACL rule.
+//
+// swagger:model
+//
+// API extension: network_acl_counters.
+type NetworkACLRuleCounters struct {
+	// Number of packets that matched the rule
+	// Example: 1024
+	Packets uint64 `json:"packets" yaml:"packets"`
+
+	// Number of bytes that matched the rule
+	// Example: 65536
+	Bytes uint64 `json:"bytes" yaml:"bytes"`
+}
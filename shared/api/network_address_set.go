@@ -0,0 +1,119 @@
+package api
+
+// NetworkAddressSetPost used for renaming an address set.
+//
+// swagger:model
+//
+// API extension: network_address_set.
+type NetworkAddressSetPost struct {
+	// lxdmeta:generate(entities=network-address-set; group=address-set-properties; key=name)
+	//
+	// ---
+	//  type: string
+	//  required: yes
+	//  shortdesc: Unique name of the network address set in the project
+
+	// The new name for the address set
+	// Example: web-servers
+	Name string `json:"name" yaml:"name"` // Name of address set.
+}
+
+// NetworkAddressSetPut used for updating an address set.
+//
+// swagger:model
+//
+// API extension: network_address_set.
+type NetworkAddressSetPut struct {
+	// lxdmeta:generate(entities=network-address-set; group=address-set-properties; key=description)
+	//
+	// ---
+	//  type: string
+	//  required: no
+	//  shortdesc: Description of the network address set
+
+	// Description of the address set
+	// Example: Web servers
+	Description string `json:"description" yaml:"description"`
+
+	// lxdmeta:generate(entities=network-address-set; group=address-set-properties; key=addresses)
+	// Addresses can be specified as single IP addresses or CIDR subnets, of either IP family.
+	// ---
+	//  type: string list
+	//  required: no
+	//  shortdesc: IP addresses and subnets in the address set
+
+	// List of IP addresses and subnets in the address set
+	// Example: ["192.0.2.10", "198.51.100.0/24", "2001:db8::/64"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+
+	// lxdmeta:generate(entities=network-address-set; group=address-set-properties; key=config)
+	// The only supported keys are `user.*` custom keys.
+	// ---
+	//  type: string set
+	//  required: no
+	//  shortdesc: User-provided free-form key/value pairs
+
+	// Address set configuration map (refer to doc/howto/network_acls.md)
+	// Example: {"user.mykey": "foo"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// NetworkAddressSet used for displaying an address set.
+//
+// swagger:model
+//
+// API extension: network_address_set.
+type NetworkAddressSet struct {
+	WithEntitlements `yaml:",inline"`
+
+	// The name of the address set
+	// Example: web-servers
+	Name string `json:"name" yaml:"name"` // Name of address set.
+
+	// Description of the address set
+	// Example: Web servers
+	Description string `json:"description" yaml:"description"`
+
+	// List of IP addresses and subnets in the address set
+	// Example: ["192.0.2.10", "198.51.100.0/24", "2001:db8::/64"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+
+	// Address set configuration map (refer to doc/howto/network_acls.md)
+	// Example: {"user.mykey": "foo"}
+	Config map[string]string `json:"config" yaml:"config"`
+
+	// List of URLs of network ACLs using this address set
+	// Read only: true
+	// Example: ["/1.0/network-acls/web"]
+	UsedBy []string `json:"used_by" yaml:"used_by"` // Network ACLs that use the address set.
+
+	// Project name
+	// Example: project1
+	Project string `json:"project" yaml:"project"` // Project the address set belongs to.
+}
+
+// Writable converts a full NetworkAddressSet struct into a NetworkAddressSetPut struct (filters read-only fields).
+func (set *NetworkAddressSet) Writable() NetworkAddressSetPut {
+	return NetworkAddressSetPut{
+		Description: set.Description,
+		Addresses:   set.Addresses,
+		Config:      set.Config,
+	}
+}
+
+// SetWritable sets applicable values from NetworkAddressSetPut struct to NetworkAddressSet struct.
+func (set *NetworkAddressSet) SetWritable(put NetworkAddressSetPut) {
+	set.Description = put.Description
+	set.Addresses = put.Addresses
+	set.Config = put.Config
+}
+
+// NetworkAddressSetsPost used for creating an address set.
+//
+// swagger:model
+//
+// API extension: network_address_set.
+type NetworkAddressSetsPost struct {
+	NetworkAddressSetPost `yaml:",inline"`
+	NetworkAddressSetPut  `yaml:",inline"`
+}
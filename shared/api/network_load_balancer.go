@@ -55,6 +55,21 @@ type NetworkLoadBalancerBackend struct {
 	// TargetAddress to forward ListenPorts to
 	// Example: 198.51.100.2
 	TargetAddress string `json:"target_address" yaml:"target_address"`
+
+	// lxdmeta:generate(entities=network-load-balancer; group=load-balancer-backend-properties; key=weight)
+	// Traffic is spread across the backends of a port in proportion to their weight.
+	// Only supported on bridge networks.
+	// ---
+	//  type: integer
+	//  required: no
+	//  defaultdesc: `1`
+	//  shortdesc: Relative weight of the backend
+
+	// Weight of the backend relative to the other backends of the same port
+	// Example: 2
+	//
+	// API extension: network_load_balancer_bridge.
+	Weight uint64 `json:"weight,omitempty" yaml:"weight,omitempty"`
 }
 
 // Normalise normalises the fields in the load balancer backend so that they are comparable with ones stored.
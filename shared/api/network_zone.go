@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // NetworkZonesPost represents the fields of a new LXD network zone
 //
 // swagger:model
@@ -74,6 +78,65 @@ func (zone *NetworkZone) SetWritable(put NetworkZonePut) {
 	zone.Config = put.Config
 }
 
+// NetworkZoneState represents the state of a network zone.
+//
+// swagger:model
+//
+// API extension: network_zones_dnssec.
+type NetworkZoneState struct {
+	// DNSSEC state of the zone (nil when DNSSEC isn't enabled)
+	DNSSEC *NetworkZoneStateDNSSEC `json:"dnssec" yaml:"dnssec"`
+}
+
+// NetworkZoneStateDNSSEC represents the DNSSEC state of a network zone.
+//
+// swagger:model
+//
+// API extension: network_zones_dnssec.
+type NetworkZoneStateDNSSEC struct {
+	// DS records to add to the parent zone
+	// Example: ["example.net. 3600 IN DS 2371 13 2 1F987CC6583E92DF0890718C42..."]
+	DS []string `json:"ds" yaml:"ds"`
+
+	// Signing keys of the zone
+	Keys []NetworkZoneStateDNSSECKey `json:"keys" yaml:"keys"`
+}
+
+// NetworkZoneStateDNSSECKey represents a DNSSEC key of a network zone.
+//
+// swagger:model
+//
+// API extension: network_zones_dnssec.
+type NetworkZoneStateDNSSECKey struct {
+	// Key type (ksk or zsk)
+	// Example: zsk
+	Type string `json:"type" yaml:"type"`
+
+	// Key tag
+	// Example: 2371
+	KeyTag uint16 `json:"key_tag" yaml:"key_tag"`
+
+	// Signing algorithm
+	// Example: ECDSAP256SHA256
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+
+	// DNSKEY record of the key
+	// Example: example.net. 3600 IN DNSKEY 256 3 13 oJMRESz5E4gYzS/q6XDrvU1qMPYIjCWzJaOau8XNEZeqCYKD5ar0IRd8...
+	DNSKEY string `json:"dnskey" yaml:"dnskey"`
+
+	// Key status (published, active or retired)
+	// Example: active
+	Status string `json:"status" yaml:"status"`
+
+	// When the key was created
+	// Example: 2026-10-01T10:00:00Z
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// When the key is used to sign the zone
+	// Example: 2026-10-02T10:00:00Z
+	ActivateAt time.Time `json:"activate_at" yaml:"activate_at"`
+}
+
 // NetworkZoneRecordsPost represents the fields of a new LXD network zone record
 //
 // swagger:model
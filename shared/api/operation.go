@@ -29,6 +29,12 @@ const (
 	// MetadataOriginalEntityURL is set in operation metadata when renaming a resource.
 	// Callers are expected to set both MetadataOriginalEntityURL and MetadataEntityURL in operation metadata.
 	MetadataOriginalEntityURL = "original_entity_url"
+
+	// MetadataBytesSent is set in the metadata of migration source operations once the transfer finishes.
+	// It holds the number of bytes sent to the migration target.
+	//
+	// API extension: replicator_run_history.
+	MetadataBytesSent = "bytes_sent"
 )
 
 // Operation represents a LXD background operation
@@ -18,6 +18,18 @@ const (
 	PlacementRigorPermissive string = "permissive"
 )
 
+// API extension: placement_groups_domains.
+const (
+	// PlacementScopeMember applies the placement policy to individual cluster members.
+	PlacementScopeMember string = "member"
+
+	// PlacementScopeFailureDomain applies the placement policy to cluster member failure domains.
+	PlacementScopeFailureDomain string = "failure-domain"
+
+	// PlacementScopeClusterGroup applies the placement policy to cluster groups.
+	PlacementScopeClusterGroup string = "cluster-group"
+)
+
 // PlacementGroup represents a group of instances that should be scheduled.
 //
 // API extension: instance_placement_groups.
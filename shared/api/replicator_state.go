@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 const (
 	// ReplicatorStatusPending represents a replicator that has never been run.
 	ReplicatorStatusPending = "Pending"
@@ -23,6 +27,16 @@ type ReplicatorState struct {
 	// Status of the replicator job.
 	// Example: Pending
 	Status string `json:"status" yaml:"status"`
+
+	// Replication state of each instance in the project.
+	//
+	// API extension: replicator_run_history
+	Instances []ReplicatorInstanceState `json:"instances" yaml:"instances"`
+
+	// Recorded runs of the replicator, newest first.
+	//
+	// API extension: replicator_run_history
+	Runs []ReplicatorRun `json:"runs" yaml:"runs"`
 }
 
 // ReplicatorStatePut represents the fields available to change the state of a replicator.
@@ -35,3 +49,99 @@ type ReplicatorStatePut struct {
 	// Example: start
 	Action string `json:"action" yaml:"action"`
 }
+
+// ReplicatorInstanceState represents the replication state of a single instance.
+//
+// swagger:model
+//
+// API extension: replicator_run_history.
+type ReplicatorInstanceState struct {
+	// Name of the instance.
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Status of the last replication of the instance.
+	// Example: Completed
+	LastRunStatus string `json:"last_run_status" yaml:"last_run_status"`
+
+	// Timestamp when the instance was last replicated.
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LastRunAt time.Time `json:"last_run_at" yaml:"last_run_at"`
+
+	// Timestamp of the most recent point in time that the instance was successfully replicated from.
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	RecoveryPointAt time.Time `json:"recovery_point_at" yaml:"recovery_point_at"`
+
+	// Age in seconds of the most recent recovery point, or -1 if the instance was never successfully replicated.
+	// Example: 3600
+	RecoveryPointAge int64 `json:"recovery_point_age" yaml:"recovery_point_age"`
+
+	// Number of snapshots created by the replicator that are retained for the instance.
+	// Example: 3
+	ReplicationPoints int `json:"replication_points" yaml:"replication_points"`
+}
+
+// ReplicatorRun represents a single recorded run of a replicator.
+//
+// swagger:model
+//
+// API extension: replicator_run_history.
+type ReplicatorRun struct {
+	// Whether the run was a restore from the current leader cluster.
+	// Example: false
+	Restore bool `json:"restore" yaml:"restore"`
+
+	// Timestamp when the run started.
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// Timestamp when the run finished.
+	// Example: 2021-03-23T17:40:12.753398689-04:00
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+
+	// Status of the run (Completed or Failed).
+	// Example: Completed
+	Status string `json:"status" yaml:"status"`
+
+	// Total number of bytes sent to the target cluster during the run.
+	// Example: 1073741824
+	BytesTransferred int64 `json:"bytes_transferred" yaml:"bytes_transferred"`
+
+	// Result of each instance replicated during the run.
+	Instances []ReplicatorRunInstance `json:"instances" yaml:"instances"`
+}
+
+// ReplicatorRunInstance represents the result of replicating a single instance during a replicator run.
+//
+// swagger:model
+//
+// API extension: replicator_run_history.
+type ReplicatorRunInstance struct {
+	// Name of the instance.
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// Status of the instance replication (Completed or Failed).
+	// Example: Failed
+	Status string `json:"status" yaml:"status"`
+
+	// Error message if the instance replication failed.
+	// Example: Failed connecting to target cluster
+	Error string `json:"error" yaml:"error"`
+
+	// Name of the snapshot created by the replicator for this run, if any.
+	// Example: snap3
+	Snapshot string `json:"snapshot" yaml:"snapshot"`
+
+	// Timestamp when the instance replication started.
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// Timestamp when the instance replication finished.
+	// Example: 2021-03-23T17:39:01.753398689-04:00
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+
+	// Number of bytes sent to the target cluster for the instance.
+	// Example: 536870912
+	BytesTransferred int64 `json:"bytes_transferred" yaml:"bytes_transferred"`
+}
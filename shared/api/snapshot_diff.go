@@ -0,0 +1,46 @@
+package api
+
+// SnapshotDiffChangeAdded indicates a path which only exists in the newer content.
+const SnapshotDiffChangeAdded = "added"
+
+// SnapshotDiffChangeRemoved indicates a path which only exists in the snapshot.
+const SnapshotDiffChangeRemoved = "removed"
+
+// SnapshotDiffChangeModified indicates a path whose content or metadata changed.
+const SnapshotDiffChangeModified = "modified"
+
+// SnapshotDiffEntry represents a path whose content differs between a snapshot and a newer snapshot
+// or the current content of an instance or storage volume.
+//
+// swagger:model
+//
+// API extension: snapshot_diff.
+type SnapshotDiffEntry struct {
+	// Path relative to the root of the volume
+	// Example: /rootfs/etc/hostname
+	Path string `json:"path" yaml:"path"`
+
+	// Type of change (added, removed or modified)
+	// Example: modified
+	Change string `json:"change" yaml:"change"`
+
+	// Type of the file (file, directory, symlink, block-device, char-device, fifo or socket)
+	// Example: file
+	Type string `json:"type" yaml:"type"`
+
+	// Size in bytes in the snapshot (unset for added paths)
+	// Example: 4
+	OldSize int64 `json:"old_size" yaml:"old_size"`
+
+	// Size in bytes in the newer content (unset for removed paths)
+	// Example: 6
+	NewSize int64 `json:"new_size" yaml:"new_size"`
+
+	// Permissions in the snapshot in octal notation (unset for added paths)
+	// Example: 0644
+	OldMode string `json:"old_mode,omitempty" yaml:"old_mode,omitempty"`
+
+	// Permissions in the newer content in octal notation (unset for removed paths)
+	// Example: 0600
+	NewMode string `json:"new_mode,omitempty" yaml:"new_mode,omitempty"`
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"time"
+)
+
+// StoragePoolHealthStateHealthy indicates a storage pool without any known problem.
+const StoragePoolHealthStateHealthy = "healthy"
+
+// StoragePoolHealthStateDegraded indicates a storage pool which is usable but has lost redundancy or encountered errors.
+const StoragePoolHealthStateDegraded = "degraded"
+
+// StoragePoolHealthStateUnavailable indicates a storage pool which cannot be used.
+const StoragePoolHealthStateUnavailable = "unavailable"
+
+// StoragePoolScrubStatusRunning indicates a scrub which is in progress.
+const StoragePoolScrubStatusRunning = "running"
+
+// StoragePoolScrubStatusCompleted indicates a scrub which ran to completion.
+const StoragePoolScrubStatusCompleted = "completed"
+
+// StoragePoolScrubStatusCanceled indicates a scrub which was canceled or interrupted.
+const StoragePoolScrubStatusCanceled = "canceled"
+
+// StoragePoolHealth represents the health of a storage pool on a cluster member
+//
+// swagger:model
+//
+// API extension: storage_pool_health.
+type StoragePoolHealth struct {
+	// Overall state of the pool (healthy, degraded or unavailable)
+	// Example: degraded
+	State string `json:"state" yaml:"state"`
+
+	// Description of the problems affecting the pool, as reported by the storage
+	// Example: One or more devices could not be used because the label is missing or invalid.
+	Message string `json:"message" yaml:"message"`
+
+	// Devices backing the pool
+	Devices []StoragePoolHealthDevice `json:"devices" yaml:"devices"`
+
+	// Result of the last scrub (unset if the pool was never scrubbed or the driver doesn't support scrubbing)
+	Scrub *StoragePoolHealthScrub `json:"scrub" yaml:"scrub"`
+}
+
+// StoragePoolHealthDevice represents the health of a device backing a storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_health.
+type StoragePoolHealthDevice struct {
+	// Name of the device
+	// Example: /dev/sdb
+	Name string `json:"name" yaml:"name"`
+
+	// State of the device as reported by the storage
+	// Example: ONLINE
+	State string `json:"state" yaml:"state"`
+
+	// Number of read errors
+	// Example: 0
+	ReadErrors uint64 `json:"read_errors" yaml:"read_errors"`
+
+	// Number of write errors
+	// Example: 0
+	WriteErrors uint64 `json:"write_errors" yaml:"write_errors"`
+
+	// Number of checksum or corruption errors
+	// Example: 2
+	ChecksumErrors uint64 `json:"checksum_errors" yaml:"checksum_errors"`
+}
+
+// StoragePoolHealthScrub represents the result of the last scrub of a storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_health.
+type StoragePoolHealthScrub struct {
+	// Status of the scrub (running, completed or canceled)
+	// Example: completed
+	Status string `json:"status" yaml:"status"`
+
+	// When the scrub started
+	// Example: 2026-10-11T00:24:01Z
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// When the scrub ended (unset while it is running)
+	// Example: 2026-10-11T00:31:12Z
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+
+	// Number of errors found by the scrub
+	// Example: 0
+	Errors uint64 `json:"errors" yaml:"errors"`
+}
@@ -62,6 +62,12 @@ type StoragePoolVolumeBackupsPost struct {
 	//
 	// API extension: backup_metadata_version
 	Version uint32 `json:"version" yaml:"version"`
+
+	// Name of a previous optimized backup of the volume to create an incremental backup from
+	// Example: backup0
+	//
+	// API extension: backups_incremental
+	IncrementalFrom string `json:"incremental_from" yaml:"incremental_from"`
 }
 
 // StoragePoolVolumeBackupPost represents the fields available for the renaming of a volume backup
@@ -1,5 +1,17 @@
 package api
 
+import (
+	"time"
+)
+
+const (
+	// StorageVolumeMirrorRoleSource indicates the volume is mirrored to another storage pool.
+	StorageVolumeMirrorRoleSource = "source"
+
+	// StorageVolumeMirrorRoleTarget indicates the volume is the mirror of another volume.
+	StorageVolumeMirrorRoleTarget = "target"
+)
+
 // StorageVolumeState represents the live state of the volume
 //
 // swagger:model
@@ -8,6 +20,16 @@ package api
 type StorageVolumeState struct {
 	// Volume usage
 	Usage *StorageVolumeStateUsage `json:"usage" yaml:"usage"`
+
+	// Volume I/O limits (unset if the volume has no limits)
+	//
+	// API extension: storage_volume_limits
+	Limits *StorageVolumeStateLimits `json:"limits,omitempty" yaml:"limits,omitempty"`
+
+	// Volume mirroring state (unset if the volume isn't mirrored)
+	//
+	// API extension: storage_volume_mirror
+	Mirror *StorageVolumeStateMirror `json:"mirror,omitempty" yaml:"mirror,omitempty"`
 }
 
 // StorageVolumeStateUsage represents the disk usage of a volume
@@ -26,3 +48,60 @@ type StorageVolumeStateUsage struct {
 	// API extension: storage_volume_state_total
 	Total int64 `json:"total" yaml:"total"`
 }
+
+// StorageVolumeStateLimits represents the I/O limits of a custom volume, shared by all the instances using it on a cluster member
+//
+// swagger:model
+//
+// API extension: storage_volume_limits.
+type StorageVolumeStateLimits struct {
+	// Read limit in bytes per second. Uses 0 to indicate that there is no limit.
+	// Example: 104857600
+	ReadBytes int64 `json:"read_bytes" yaml:"read_bytes"`
+
+	// Write limit in bytes per second. Uses 0 to indicate that there is no limit.
+	// Example: 52428800
+	WriteBytes int64 `json:"write_bytes" yaml:"write_bytes"`
+
+	// Read limit in operations per second. Uses 0 to indicate that there is no limit.
+	// Example: 1000
+	ReadIOps int64 `json:"read_iops" yaml:"read_iops"`
+
+	// Write limit in operations per second. Uses 0 to indicate that there is no limit.
+	// Example: 500
+	WriteIOps int64 `json:"write_iops" yaml:"write_iops"`
+
+	// URLs of the running instances sharing the limits as a single budget on the cluster member
+	// Example: ["/1.0/instances/c1", "/1.0/instances/v1"]
+	Instances []string `json:"instances" yaml:"instances"`
+}
+
+// StorageVolumeStateMirror represents the mirroring state of a custom volume
+//
+// swagger:model
+//
+// API extension: storage_volume_mirror.
+type StorageVolumeStateMirror struct {
+	// Role of the volume in the mirror (source or target)
+	// Example: source
+	Role string `json:"role" yaml:"role"`
+
+	// Timestamp when the last successful refresh of the mirror started
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LastRefreshAt time.Time `json:"last_refresh_at" yaml:"last_refresh_at"`
+
+	// Age in seconds of the last successful refresh, or -1 if the mirror was never refreshed
+	// Example: 3600
+	Lag int64 `json:"lag" yaml:"lag"`
+}
+
+// StorageVolumeStatePut represents the fields available to change the state of a volume
+//
+// swagger:model
+//
+// API extension: storage_volume_mirror.
+type StorageVolumeStatePut struct {
+	// Action to perform on the volume (refresh or promote)
+	// Example: refresh
+	Action string `json:"action" yaml:"action"`
+}
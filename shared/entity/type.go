@@ -121,6 +121,9 @@ const (
 
 	// TypeReplicator represents replicator resources.
 	TypeReplicator Type = "replicator"
+
+	// TypeNetworkAddressSet represents network address set resources.
+	TypeNetworkAddressSet Type = "network_address_set"
 )
 
 const (
@@ -190,6 +193,7 @@ var entityTypes = map[Type]typeInfo{
 	TypePlacementGroup:        placementGroup{},
 	TypeClusterLink:           clusterLink{},
 	TypeReplicator:            replicator{},
+	TypeNetworkAddressSet:     networkAddressSet{},
 }
 
 // metricsEntityTypes is the source of truth for which entity types can be used to categorize endpoints
@@ -387,6 +391,22 @@ func (networkACL) pathArgNames() []string {
 	return []string{"name"}
 }
 
+type networkAddressSet struct {
+	typeInfoCommon
+}
+
+func (networkAddressSet) requiresProject() bool {
+	return true
+}
+
+func (networkAddressSet) path() []string {
+	return []string{"network-address-sets", pathPlaceholder}
+}
+
+func (networkAddressSet) pathArgNames() []string {
+	return []string{"name"}
+}
+
 type clusterMember struct {
 	typeInfoCommon
 }
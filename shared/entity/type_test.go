@@ -190,6 +190,15 @@ func TestURL(t *testing.T) {
 			expectedPathArgs:      []string{"my-network-acl"},
 			expectedErr:           nil,
 		},
+		{
+			name:                  "network address sets",
+			rawURL:                "/1.0/network-address-sets/my-address-set",
+			expectedNormalisedURL: "/1.0/network-address-sets/my-address-set?project=default",
+			expectedEntityType:    TypeNetworkAddressSet,
+			expectedProject:       api.ProjectDefaultName,
+			expectedPathArgs:      []string{"my-address-set"},
+			expectedErr:           nil,
+		},
 		{
 			name:                  "cluster members",
 			rawURL:                "/1.0/cluster/members/node01",
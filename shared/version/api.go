@@ -500,6 +500,31 @@ var APIExtensions = []string{
 	"operation_child_count",
 	"storage_driver_powerstore_nvme",
 	"access_management_expiry",
+	"replicator_running_instances",
+	"replicator_run_history",
+	"storage_buckets_local",
+	"acme_dns01",
+	"network_zones_dns_queries",
+	"network_zones_dnssec",
+	"network_bgp_import",
+	"network_bgp_attributes",
+	"cluster_scheduler_resources",
+	"placement_groups_domains",
+	"storage_volume_encryption",
+	"backups_schedule",
+	"backups_incremental",
+	"backups_s3",
+	"snapshot_diff",
+	"storage_pool_health",
+	"storage_volume_limits",
+	"storage_driver_nfs",
+	"storage_volume_mirror",
+	"storage_driver_dir_qcow2",
+	"network_load_balancer_bridge",
+	"network_acl_fqdn",
+	"network_address_set",
+	"network_acl_counters",
+	"network_bridge_wireguard",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
@@ -23,6 +23,7 @@ readonly test_group_cluster=(
     "clustering_address"
     "clustering_dns"
     "clustering_fan"
+    "clustering_wireguard"
     "clustering_recover"
     "clustering_ha"
     "clustering_handover"
@@ -146,6 +147,7 @@ readonly test_group_network=(
     "network"
     "network_acl"
     "network_forward"
+    "network_load_balancer"
     "network_zone"
     "network_ovn"
 )
@@ -239,6 +241,9 @@ readonly test_group_standalone_storage=(
     "backup_volume_rename_delete"
     "backup_instance_uuid"
     "backup_volume_expiry"
+    "backup_schedule"
+    "backup_incremental"
+    "backup_s3"
     "backup_export_import_recover"
     "backup_inconsistent_config"
     "container_copy_incremental"
@@ -280,9 +285,12 @@ readonly test_group_standalone_storage=(
     "snapshot_volume_db_recovery"
     "snapshot_fail"
     "snapshot_multi_volume"
+    "snapshot_diff"
     "storage_volume_recover"
     "storage_volume_recover_by_container"
     "storage"
+    "storage_volume_limits"
+    "storage_volume_mirror"
     "storage_volume_snapshots"
     "storage_local_volume_handling"
     "storage_profiles"
@@ -292,10 +300,14 @@ readonly test_group_standalone_storage=(
     "storage_driver_ceph"
     "storage_driver_cephfs"
     "storage_driver_dir"
+    "storage_driver_lvm"
+    "storage_driver_nfs"
     "storage_driver_zfs"
     "storage_driver_pure"
     "storage_pools"
+    "storage_pool_health"
     "storage_buckets"
+    "storage_buckets_local"
     "storage_volume_import"
     "storage_volume_initial_config"
 )
@@ -1096,6 +1096,58 @@ test_backup_volume_expiry() {
   lxc storage volume delete "${poolName}" vol1
 }
 
+test_backup_schedule() {
+  local poolName
+  poolName="lxdtest-$(basename "${LXD_DIR}")"
+
+  lxc init --empty c1 -d "${SMALL_ROOT_DISK}"
+  lxc storage volume create "${poolName}" vol1 size=1MiB
+
+  # Invalid values are rejected.
+  ! lxc config set c1 backups.schedule=foo || false
+  ! lxc config set c1 backups.retain=0 || false
+  ! lxc storage volume set "${poolName}" vol1 backups.expiry=foo || false
+
+  # Create manual backups. The one named like a scheduled backup is the oldest and exceeds the retain count.
+  lxc query -X POST --wait -d '{"name":"manual"}' /1.0/instances/c1/backups
+  lxc query -X POST --wait -d '{"name":"scheduled0"}' /1.0/instances/c1/backups
+  lxc query -X POST --wait -d '{"name":"scheduled0"}' /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups
+
+  # Schedule the instance backups through its profile.
+  lxc profile create backups
+  lxc profile set backups backups.schedule="* * * * *" backups.retain=1 backups.expiry=1d
+  lxc profile add c1 backups
+  lxc storage volume set "${poolName}" vol1 backups.schedule="* * * * *" backups.retain=1
+
+  # Wait for the scheduled backups to be created and the older scheduled backups to be pruned.
+  # Only the most recent scheduled backup is retained, manual backups are kept.
+  for _ in $(seq 150); do
+    if lxc query /1.0/instances/c1/backups | jq --exit-status '. == ["/1.0/instances/c1/backups/manual", "/1.0/instances/c1/backups/scheduled1"]' && \
+       lxc query /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups | jq --exit-status '. == ["/1.0/storage-pools/'"${poolName}"'/volumes/custom/vol1/backups/scheduled1"]'; then
+      break
+    fi
+
+    sleep 1
+  done
+
+  # Stop scheduling backups.
+  lxc profile remove c1 backups
+  lxc storage volume unset "${poolName}" vol1 backups.schedule
+
+  lxc query /1.0/instances/c1/backups | jq --exit-status 'any(. == "/1.0/instances/c1/backups/manual")'
+  lxc query /1.0/instances/c1/backups | jq --exit-status 'any(. == "/1.0/instances/c1/backups/scheduled1")'
+  lxc query /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups | jq --exit-status 'any(endswith("/backups/scheduled1"))'
+
+  # The scheduled backups expire according to backups.expiry.
+  [ "$(lxc query /1.0/instances/c1/backups/scheduled1 | jq --exit-status --raw-output '.expires_at')" != "0001-01-01T00:00:00Z" ]
+  [ "$(lxc query /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups/scheduled1 | jq --exit-status --raw-output '.expires_at')" = "0001-01-01T00:00:00Z" ]
+
+  # Cleanup.
+  lxc delete c1
+  lxc profile delete backups
+  lxc storage volume delete "${poolName}" vol1
+}
+
 test_backup_export_import_recover() {
   lxd_backend=$(storage_backend "$LXD_DIR")
 
@@ -1781,3 +1833,182 @@ _backup_nullable_fields_run() {
     return 1
   fi
 }
+
+test_backup_incremental() {
+  local lxd_backend poolName
+  lxd_backend="$(storage_backend "${LXD_DIR}")"
+  if [ "${lxd_backend}" != "zfs" ] && [ "${lxd_backend}" != "btrfs" ]; then
+    export TEST_UNMET_REQUIREMENT="zfs or btrfs specific test, not for ${lxd_backend}"
+    return
+  fi
+
+  poolName="lxdtest-$(basename "${LXD_DIR}")"
+
+  ensure_import_testimage
+  lxc launch testimage c1
+  lxc storage volume create "${poolName}" vol1 size=1MiB
+  lxc storage volume attach "${poolName}" vol1 c1 /mnt
+
+  echo "==> Full backup"
+  lxc exec c1 -- sh -c "echo full > /root/data && echo full > /mnt/data"
+  lxc snapshot c1 snap0
+  lxc storage volume snapshot "${poolName}" vol1 snap0
+  lxc query -X POST --wait -d '{"name":"full","optimized_storage":true}' /1.0/instances/c1/backups
+  lxc query -X POST --wait -d '{"name":"full","optimized_storage":true}' /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups
+
+  echo "==> Incremental backup with new snapshots"
+  lxc exec c1 -- sh -c "echo inc1 > /root/data && echo inc1 > /mnt/data"
+  lxc snapshot c1 snap1
+  lxc storage volume snapshot "${poolName}" vol1 snap1
+  lxc exec c1 -- sh -c "echo inc1-latest > /root/data && echo inc1-latest > /mnt/data"
+  lxc query -X POST --wait -d '{"name":"inc1","optimized_storage":true,"incremental_from":"full"}' /1.0/instances/c1/backups
+  lxc query -X POST --wait -d '{"name":"inc1","optimized_storage":true,"incremental_from":"full"}' /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups
+
+  echo "==> Incremental backup without new snapshots"
+  lxc exec c1 -- sh -c "echo inc2 > /root/data && echo inc2 > /mnt/data"
+  lxc query -X POST --wait -d '{"name":"inc2","optimized_storage":true,"incremental_from":"inc1"}' /1.0/instances/c1/backups
+  lxc query -X POST --wait -d '{"name":"inc2","optimized_storage":true,"incremental_from":"inc1"}' /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups
+
+  echo "==> Invalid incremental backups"
+  ! lxc query -X POST --wait -d '{"name":"foo","incremental_from":"full"}' /1.0/instances/c1/backups || false
+  ! lxc query -X POST --wait -d '{"name":"foo","optimized_storage":true,"incremental_from":"missing"}' /1.0/instances/c1/backups || false
+
+  for name in full inc1 inc2; do
+    lxc query /1.0/instances/c1/backups/"${name}"/export > "${LXD_DIR}/c1-${name}.tar.gz"
+    lxc query /1.0/storage-pools/"${poolName}"/volumes/custom/vol1/backups/"${name}"/export > "${LXD_DIR}/vol1-${name}.tar.gz"
+  done
+
+  # Only incremental backups record their base snapshot.
+  ! tar -xzOf "${LXD_DIR}/c1-full.tar.gz" backup/index.yaml | grep -F base_snapshot || false
+  tar -xzOf "${LXD_DIR}/c1-inc1.tar.gz" backup/index.yaml | grep -xF "base_snapshot: snap0"
+  tar -xzOf "${LXD_DIR}/c1-inc2.tar.gz" backup/index.yaml | grep -xF "base_snapshot: snap1"
+
+  lxc storage volume detach "${poolName}" vol1 c1
+  lxc delete -f c1
+
+  echo "==> Incremental backups cannot be imported without the full backup"
+  ! lxc import "${LXD_DIR}/c1-inc1.tar.gz" c2 || false
+  ! lxc storage volume import "${poolName}" "${LXD_DIR}/vol1-inc1.tar.gz" vol2 || false
+
+  echo "==> Restore the chain"
+  lxc import "${LXD_DIR}/c1-full.tar.gz" c2
+  lxc storage volume import "${poolName}" "${LXD_DIR}/vol1-full.tar.gz" vol2
+
+  # Skipping a backup of the chain fails.
+  ! lxc import "${LXD_DIR}/c1-inc2.tar.gz" c2 || false
+  ! lxc storage volume import "${poolName}" "${LXD_DIR}/vol1-inc2.tar.gz" vol2 || false
+
+  lxc import "${LXD_DIR}/c1-inc1.tar.gz" c2
+  lxc storage volume import "${poolName}" "${LXD_DIR}/vol1-inc1.tar.gz" vol2
+  [ "$(lxc query /1.0/instances/c2/snapshots | jq --exit-status --raw-output 'length')" = "2" ]
+  [ "$(lxc query /1.0/storage-pools/"${poolName}"/volumes/custom/vol2/snapshots | jq --exit-status --raw-output 'length')" = "2" ]
+
+  # Restored btrfs snapshots keep their received UUID so the next incremental stream can be applied on top.
+  if [ "${lxd_backend}" = "btrfs" ]; then
+    ! btrfs subvolume show "${LXD_DIR}/storage-pools/${poolName}/containers-snapshots/c2/snap1" | grep -E "Received UUID:\s+-$" || false
+    ! btrfs subvolume show "${LXD_DIR}/storage-pools/${poolName}/custom-snapshots/default_vol2/snap1" | grep -E "Received UUID:\s+-$" || false
+  fi
+
+  # Applying the same backup twice fails.
+  ! lxc import "${LXD_DIR}/c1-inc1.tar.gz" c2 || false
+
+  lxc import "${LXD_DIR}/c1-inc2.tar.gz" c2
+  lxc storage volume import "${poolName}" "${LXD_DIR}/vol1-inc2.tar.gz" vol2
+
+  lxc storage volume attach "${poolName}" vol2 c2 /mnt
+  lxc start c2
+  [ "$(lxc exec c2 -- cat /root/data)" = "inc2" ]
+  [ "$(lxc exec c2 -- cat /mnt/data)" = "inc2" ]
+
+  echo "==> Snapshots restored from incremental backups are usable"
+  lxc stop -f c2
+  lxc restore c2 snap1
+  lxc storage volume restore "${poolName}" vol2 snap1
+  lxc start c2
+  [ "$(lxc exec c2 -- cat /root/data)" = "inc1" ]
+  [ "$(lxc exec c2 -- cat /mnt/data)" = "inc1" ]
+
+  echo "==> Incremental backups cannot be applied to running instances"
+  ! lxc import "${LXD_DIR}/c1-inc2.tar.gz" c2 || false
+
+  # Cleanup.
+  lxc delete -f c2
+  lxc storage volume delete "${poolName}" vol1
+  lxc storage volume delete "${poolName}" vol2
+  rm "${LXD_DIR}"/c1-*.tar.gz "${LXD_DIR}"/vol1-*.tar.gz
+}
+
+test_backup_s3() {
+  local lxd_backend poolName bucketName s3Endpoint creds accessKey secretKey op
+  lxd_backend="$(storage_backend "${LXD_DIR}")"
+  if ! [[ "${lxd_backend}" =~ ^(dir|btrfs|lvm|zfs)$ ]]; then
+    export TEST_UNMET_REQUIREMENT="S3 backup tests require a local storage backend"
+    return
+  fi
+
+  poolName="$(lxc profile device get default root pool)"
+  bucketName="backups$$"
+
+  ensure_import_testimage
+  lxc init testimage c1 -c user.foo=bar
+  lxc snapshot c1 snap0
+
+  echo "==> Backups cannot be pushed before the S3 endpoint is configured"
+  ! lxc query -X POST --wait -d '{"target_url":"s3://'"${bucketName}"'/c1"}' /1.0/instances/c1/backups || false
+
+  # Use a bucket served by the storage buckets listener as the S3 endpoint.
+  s3Endpoint="127.0.0.1:$(local_tcp_port)"
+  lxc config set core.storage_buckets_address "${s3Endpoint}"
+  creds="$(lxc storage bucket create "${poolName}" "${bucketName}")"
+  accessKey="$(echo "${creds}" | awk '{ if ($2 == "access" && $3 == "key:") {print $4}}')"
+  secretKey="$(echo "${creds}" | awk '{ if ($2 == "secret" && $3 == "key:") {print $4}}')"
+
+  lxc config set backups.s3.endpoint="https://${s3Endpoint}" backups.s3.access_key="${accessKey}" backups.s3.ca_cert="$(cat "${LXD_DIR}/server.crt")"
+  ! lxc query -X POST --wait -d '{"target_url":"s3://'"${bucketName}"'/c1"}' /1.0/instances/c1/backups || false
+
+  echo "==> The secret key is stored as a server secret"
+  lxc config set backups.s3.secret_key="${secretKey}"
+  ! lxc config get backups.s3.secret_key | grep -F "${secretKey}" || false
+  ! lxc config show | grep -F "${secretKey}" || false
+
+  echo "==> Invalid pushes"
+  ! lxc query -X POST --wait -d '{"target_url":"https://'"${s3Endpoint}"'/'"${bucketName}"'/c1"}' /1.0/instances/c1/backups || false
+  ! lxc query -X POST --wait -d '{"target_url":"s3://'"${bucketName}"'/c1","expires_at":"2100-01-01T00:00:00Z"}' /1.0/instances/c1/backups || false
+  ! lxc query -X POST --wait -d '{"target_url":"s3://missing'"$$"'/default/c1"}' /1.0/instances/c1/backups || false
+
+  echo "==> Object keys are confined to the prefix of the project"
+  ! lxc query -X POST --wait -d '{"target_url":"s3://'"${bucketName}"'/other/c1"}' /1.0/instances/c1/backups || false
+  ! lxc query -X POST --wait -d '{"target_url":"s3://'"${bucketName}"'/default/../other/c1"}' /1.0/instances/c1/backups || false
+
+  echo "==> Push a backup"
+  op="$(lxc query -X POST --wait -d '{"target_url":"s3://'"${bucketName}"'/default/c1"}' /1.0/instances/c1/backups)"
+  echo "${op}" | jq --exit-status '.metadata.url == "s3://'"${bucketName}"'/default/c1"'
+  echo "${op}" | jq --exit-status '.metadata.size > 0'
+
+  # Backups pushed to an object store are not tracked locally.
+  [ "$(lxc query /1.0/instances/c1/backups | jq --exit-status --raw-output 'length')" = "0" ]
+
+  # The object matches the checksum reported by the operation.
+  s3cmdrun_local "${accessKey}" "${secretKey}" get "s3://${bucketName}/default/c1" "${LXD_DIR}/c1-s3.tar.gz"
+  [ "$(sha256sum < "${LXD_DIR}/c1-s3.tar.gz" | cut -d' ' -f1)" = "$(echo "${op}" | jq --exit-status --raw-output '.metadata.sha256')" ]
+  tar -xzOf "${LXD_DIR}/c1-s3.tar.gz" backup/index.yaml | grep -xF "name: c1"
+
+  echo "==> Import the backup from the object store"
+  ! lxc query -X POST -d '{"name":"c2","source":{"type":"backup","url":"s3://'"${bucketName}"'/default/missing"}}' /1.0/instances || false
+  lxc project create foo -c features.images=false -c features.profiles=false
+  ! lxc query -X POST -d '{"name":"c2","source":{"type":"backup","url":"s3://'"${bucketName}"'/default/c1"}}' "/1.0/instances?project=foo" || false
+  lxc project delete foo
+  lxc query -X POST --wait -d '{"name":"c2","source":{"type":"backup","url":"s3://'"${bucketName}"'/default/c1"}}' /1.0/instances
+  [ "$(lxc config get c2 user.foo)" = "bar" ]
+  [ "$(lxc query /1.0/instances/c2/snapshots | jq --exit-status --raw-output 'length')" = "1" ]
+
+  # Cleanup.
+  lxc delete c1 c2
+  rm "${LXD_DIR}/c1-s3.tar.gz"
+  lxc config unset backups.s3.endpoint
+  lxc config unset backups.s3.access_key
+  lxc config unset backups.s3.ca_cert
+  lxc config set backups.s3.secret_key=""
+  lxc storage bucket delete "${poolName}" "${bucketName}"
+  lxc config unset core.storage_buckets_address
+}
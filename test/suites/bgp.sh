@@ -49,6 +49,25 @@ test_bgp() {
     exit 1
   fi
 
+  sub_test "Configure route import from BGP peers on a bridge network"
+  lxc network create lxdt$$ ipv4.address=none ipv6.address=none bgp.peers.foo.address=192.0.2.2 bgp.peers.foo.asn=65001 bgp.peers.foo.max_prefixes=100 bgp.import=true bgp.import.prefixes=10.0.0.0/8
+  lxc query "/1.0/networks/lxdt$$/state" | jq --exit-status '.bgp.routes == []'
+
+  # The routing table and VRF are mutually exclusive.
+  ! lxc network set lxdt$$ bgp.import.table=100 bgp.import.vrf=vrf0 || false
+  lxc network set lxdt$$ bgp.import.table=100
+  ! lxc network set lxdt$$ bgp.import.table=foo || false
+  ! lxc network set lxdt$$ bgp.import.prefixes=foo || false
+
+  # Route import requires an explicit prefix filter.
+  ! lxc network unset lxdt$$ bgp.import.prefixes || false
+  ! lxc network set lxdt$$ bgp.peers.foo.max_prefixes=-1 || false
+
+  # The learned routes are only reported when route import is enabled.
+  lxc network set lxdt$$ bgp.import=false
+  lxc query "/1.0/networks/lxdt$$/state" | jq --exit-status '.bgp == null'
+  lxc network delete lxdt$$
+
   sub_test "Unconfigure BGP listener and verify it is no longer listening"
   lxc config set core.bgp_address="" core.bgp_routerid="" core.bgp_asn=""
 
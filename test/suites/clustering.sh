@@ -2667,6 +2667,103 @@ test_clustering_fan() {
   kill_lxd "${LXD_TWO_DIR}"
 }
 
+test_clustering_wireguard() {
+  if ! command -v wg >/dev/null; then
+    export TEST_UNMET_REQUIREMENT="Requires the 'wg' command"
+    return 0
+  fi
+
+  spawn_lxd_and_bootstrap_cluster
+
+  local cert
+  cert="$(cert_to_yaml "${LXD_ONE_DIR}/cluster.crt")"
+
+  # Spawn a second node
+  spawn_lxd_and_join_cluster "${cert}" 2 1 "${LXD_ONE_DIR}"
+
+  # Import the test image on node1
+  LXD_DIR="${LXD_ONE_DIR}" ensure_import_testimage
+
+  local wgbridge="${prefix}w"
+
+  echo "Create a WireGuard bridge"
+  LXD_DIR="${LXD_ONE_DIR}" lxc network create --target node1 "${wgbridge}"
+  LXD_DIR="${LXD_ONE_DIR}" lxc network create --target node2 "${wgbridge}"
+  LXD_DIR="${LXD_ONE_DIR}" lxc network create "${wgbridge}" bridge.mode=wireguard wireguard.overlay_subnet=10.201.0.0/16
+  LXD_DIR="${LXD_ONE_DIR}" lxc network show "${wgbridge}"
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc network get "${wgbridge}" bridge.mode)" = "wireguard" ]
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc network get "${wgbridge}" ipv4.nat)" = "true" ]
+
+  echo "Check the configuration restrictions"
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc network unset "${wgbridge}" wireguard.overlay_subnet || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc network set "${wgbridge}" wireguard.overlay_subnet=10.201.0.0/24 || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc network set "${wgbridge}" ipv6.address=fd42:4242:4242:1010::1/64 || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc network set "${wgbridge}" bridge.mtu=1500 || false
+
+  echo "Check that the public keys are distributed"
+  local key1 key2
+  key1="$(LXD_DIR="${LXD_ONE_DIR}" lxc network get "${wgbridge}" volatile.wireguard.public_key --target node1)"
+  key2="$(LXD_DIR="${LXD_ONE_DIR}" lxc network get "${wgbridge}" volatile.wireguard.public_key --target node2)"
+  [ -n "${key1}" ]
+  [ -n "${key2}" ]
+  [ "${key1}" != "${key2}" ]
+  [ "$(stat -c %a "${LXD_ONE_DIR}/networks/${wgbridge}/wireguard.key")" = "600" ]
+  grep -xF "PublicKey = ${key1}" "${LXD_TWO_DIR}/networks/${wgbridge}/wireguard.conf"
+
+  echo "Check that the member indexes are dense"
+  local idx1 idx2
+  idx1="$(LXD_DIR="${LXD_ONE_DIR}" lxc network get "${wgbridge}" volatile.wireguard.index --target node1)"
+  idx2="$(LXD_DIR="${LXD_ONE_DIR}" lxc network get "${wgbridge}" volatile.wireguard.index --target node2)"
+  [ "$((idx1 + idx2))" = "1" ]
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc network set "${wgbridge}" volatile.wireguard.index=foo --target node1 || false
+
+  # The first member learns about the second one on the next heartbeat.
+  for _ in $(seq 30); do
+    grep -qxF "PublicKey = ${key2}" "${LXD_ONE_DIR}/networks/${wgbridge}/wireguard.conf" && break
+    sleep 1
+  done
+
+  grep -xF "PublicKey = ${key2}" "${LXD_ONE_DIR}/networks/${wgbridge}/wireguard.conf"
+  grep -xF "AllowedIPs = 10.201.${idx2}.0/24" "${LXD_ONE_DIR}/networks/${wgbridge}/wireguard.conf"
+
+  echo "Create 2 containers"
+  LXD_DIR="${LXD_ONE_DIR}" lxc launch --target node1 testimage c1 -d "${SMALL_ROOT_DISK}" -n "${wgbridge}"
+  LXD_DIR="${LXD_ONE_DIR}" lxc launch --target node2 testimage c2 -d "${SMALL_ROOT_DISK}" -n "${wgbridge}"
+
+  echo "Get DHCP leases"
+  IP_C1="$(LXD_DIR="${LXD_ONE_DIR}" lxc exec c1 -- udhcpc -f -i eth0 -n -q -t5 2>&1 | awk '/obtained/ {print $4}')"
+  IP_C2="$(LXD_DIR="${LXD_ONE_DIR}" lxc exec c2 -- udhcpc -f -i eth0 -n -q -t5 2>&1 | awk '/obtained/ {print $4}')"
+
+  echo "Configure IP addresses"
+  LXD_DIR="${LXD_ONE_DIR}" lxc exec c1 -- ip addr add "${IP_C1}"/24 dev eth0
+  LXD_DIR="${LXD_ONE_DIR}" lxc exec c1 -- ip route add default via "10.201.${idx1}.1"
+  LXD_DIR="${LXD_ONE_DIR}" lxc exec c2 -- ip addr add "${IP_C2}"/24 dev eth0
+  LXD_DIR="${LXD_ONE_DIR}" lxc exec c2 -- ip route add default via "10.201.${idx2}.1"
+  LXD_DIR="${LXD_ONE_DIR}" lxc list
+
+  echo "Check that the containers are reachable from each other through the tunnel"
+  LXD_DIR="${LXD_ONE_DIR}" lxc exec c1 -- ping -nc2 -i0.1 -W1 "${IP_C2}"
+  LXD_DIR="${LXD_ONE_DIR}" lxc exec c2 -- ping -nc2 -i0.1 -W1 "${IP_C1}"
+
+  echo "Cleaning up"
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete -f c1 c2
+  LXD_DIR="${LXD_ONE_DIR}" lxc image delete testimage
+  LXD_DIR="${LXD_ONE_DIR}" lxc network delete "${wgbridge}"
+
+  echo "Tearing down cluster"
+  LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
+  LXD_DIR="${LXD_ONE_DIR}" lxd shutdown
+
+  rm -f "${LXD_TWO_DIR}/unix.socket"
+  rm -f "${LXD_ONE_DIR}/unix.socket"
+
+  teardown_clustering_netns
+  teardown_clustering_bridge
+
+  kill_lxd "${LXD_ONE_DIR}"
+  kill_lxd "${LXD_TWO_DIR}"
+}
+
 test_clustering_recover() {
   spawn_lxd_and_bootstrap_cluster
 
@@ -3208,6 +3305,26 @@ test_clustering_failure_domains() {
   # Test the get subcommand
   [ "$(LXD_DIR="${LXD_THREE_DIR}" lxc cluster failure-domain get node2)" = "az2" ]
 
+  # Spreading a placement group across failure domains places each instance in a different domain.
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-domains policy=spread rigor=strict scope=failure-domain
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc placement-group create pg-invalid policy=compact rigor=strict max_per_domain=2 || false
+  for i in 1 2 3; do
+    LXD_DIR="${LXD_ONE_DIR}" lxc init --empty "c${i}" -c placement.group=pg-domains
+  done
+
+  for i in 1 2 3; do
+    LXD_DIR="${LXD_ONE_DIR}" lxc cluster failure-domain get "$(LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c L "c${i}")"
+  done | sort -u | wc -l | grep -xF 3
+
+  # All failure domains are full.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc init --empty c4 -c placement.group=pg-domains || false
+
+  # Allow two instances per failure domain.
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group set pg-domains max_per_domain=2
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty c4 -c placement.group=pg-domains
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete c1 c2 c3 c4
+  LXD_DIR="${LXD_ONE_DIR}" lxc placement-group delete pg-domains
+
   # Shutdown a node in az2, its replacement is picked from az2.
   LXD_DIR="${LXD_TWO_DIR}" lxd shutdown
   sleep 3
@@ -4386,6 +4503,20 @@ EOF
   LXD_DIR="${LXD_ONE_DIR}" lxc init --empty cluster:c5 --target=node3
   [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c L cluster:c5)" = "node3" ]
 
+  sub_test "Resource-aware scheduling"
+  LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.scheduler=resources
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc config set cluster.scheduler=foo || false
+
+  # No member has enough CPU threads or memory for these instances.
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc init --empty cluster:c7 -c limits.cpu=100000 || false
+  ! LXD_DIR="${LXD_ONE_DIR}" lxc init --empty cluster:c7 -c limits.memory=1PiB || false
+
+  # c7 should go to node1 as it is the only member accepting untargeted instances.
+  LXD_DIR="${LXD_ONE_DIR}" lxc init --empty cluster:c7 -c limits.cpu=1 -c limits.memory=1MiB
+  [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc list -f csv -c L cluster:c7)" = "node1" ]
+  LXD_DIR="${LXD_ONE_DIR}" lxc delete cluster:c7
+  LXD_DIR="${LXD_ONE_DIR}" lxc config unset cluster.scheduler
+
   sub_test "volatile.cluster.group and placement.group behavior"
   # Check "volatile.cluster.group" is set correctly.
   [ "$(LXD_DIR="${LXD_ONE_DIR}" lxc config get cluster:c1 volatile.cluster.group || echo fail)" = "" ]
@@ -115,14 +115,102 @@ EOF
   [ "$(echo "$acl_show_output" | grep -cF "destination: ${daddr}")" = 1 ]
   [ "$(echo "$acl_show_output" | grep -cF 'state: enabled')" -ge 2 ] # Default state enabled for new rules.
 
+  echo "Check FQDN destinations"
+  ! lxc network acl rule add testacl ingress action=allow destination=fqdn:example.com || false # Not allowed in ingress rules
+  ! lxc network acl rule add testacl egress action=allow source=fqdn:example.com || false # Not allowed in sources
+  ! lxc network acl rule add testacl egress action=allow destination=fqdn:-invalid.example.com || false # Invalid DNS name
+  lxc network acl rule add testacl egress action=allow protocol=tcp destination=fqdn:lxd-acl-test.invalid destination_port=4443
+  lxc network acl rule add testacl egress action=drop protocol=tcp destination=fqdn:lxd-acl-test.invalid destination_port=4444
+  [ "$(lxc network acl show testacl | grep -cF 'destination: fqdn:lxd-acl-test.invalid')" = 2 ]
+
   echo "Apply ACL to network"
   lxc network set "${netName}" security.acls=testacl
 
+  echo "Verify rules with unresolvable FQDN destinations fail closed"
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -S | grep -F -- "--dports 4443" || false
+    iptables -w -S | grep -F -- "--dports 4444"
+  else
+    nft -nn list chain inet lxd "acl.${netName}" | grep -F "dport 4443" | grep -F "accept"
+    nft -nn list chain inet lxd "acl.${netName}" | grep -F "dport 4444" | grep -F "drop"
+    aclSets="$(nft -nn list sets inet)"
+    echo "${aclSets}" | grep -F "aclset4.${netName}.fqdn_" | grep -F "_deny"
+  fi
+
+  echo "Verify unresolvable FQDN destinations raise a warning"
+  for _ in $(seq 20); do
+    if lxc warning list --format csv | grep -F "Failed resolving network ACL FQDN"; then
+      break
+    fi
+
+    sleep 1
+  done
+
+  lxc warning list --format csv | grep -F "Failed resolving network ACL FQDN"
+  lxc warning delete --all
+
+  lxc network acl rule remove testacl egress destination=fqdn:lxd-acl-test.invalid destination_port=4443
+  lxc network acl rule remove testacl egress destination=fqdn:lxd-acl-test.invalid destination_port=4444
+
   echo "Verify corresponding firewall rules"
   if [ "$firewallDriver" = "xtables" ]; then
     iptables -w -S | grep -xF -- "-A lxd_acl_${netName} -s 192.168.1.2/32 -d ${daddr} -o ${netName} -p tcp -m multiport --dports 22,2222:2223 -j ACCEPT"
   else
-    nft -nn list chain inet lxd "acl.${netName}" | grep -F "oifname \"${netName}\" ip saddr 192.168.1.2 ip daddr ${daddr} tcp dport { 22, 2222-2223 } accept"
+    nft -nn list chain inet lxd "acl.${netName}" | grep -F "oifname \"${netName}\" ip saddr 192.168.1.2 ip daddr ${daddr} tcp dport { 22, 2222-2223 } counter packets 0 bytes 0 accept comment"
+  fi
+
+  echo "Check network address sets"
+  ! lxc network address-set create 192.168.1.1 || false # Don't allow non-hostname compatible names.
+  ! lxc network address-set create testset not-an-address || false # Invalid address.
+  ! lxc network address-set create testset 192.0.2.1 192.0.2.1 || false # Duplicate address.
+  ! lxc network address-set create testset non.userkey=foo || false # Only user keys are allowed.
+  lxc network address-set create testset 192.0.2.0/24 2001:db8::/64 user.somekey=foo
+  [ "$(lxc network address-set get testset user.somekey)" = "foo" ]
+  lxc network address-set list | grep -wF testset
+  ! lxc network acl rule add testacl ingress action=allow source="\$missing" || false # Unknown address set.
+  lxc network acl rule add testacl ingress action=allow source="\$testset" protocol=tcp destination_port=2224
+  lxc network address-set show testset | grep -xF -- "- /1.0/network-acls/testacl"
+
+  echo "Verify address set firewall rules"
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -S | grep -F -- "-s 192.0.2.0/24 -o ${netName} -p tcp" | grep -F 2224
+  else
+    nft -nn list set inet lxd "aclset4.${netName}.testset" | grep -F "192.0.2.0/24"
+    nft -nn list chain inet lxd "acl.${netName}" | grep -F "oifname \"${netName}\" ip saddr @aclset4.${netName}.testset tcp dport 2224 counter packets 0 bytes 0 accept comment"
+  fi
+
+  echo "Update address set addresses"
+  lxc network address-set add testset 198.51.100.1
+  ! lxc network address-set add testset 198.51.100.1 || false # Address already in set.
+  lxc network address-set remove testset 192.0.2.0/24
+  ! lxc network address-set remove testset 192.0.2.0/24 || false # Address not in set.
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -S | grep -F -- "-s 198.51.100.1/32 -o ${netName} -p tcp" | grep -F 2224
+    ! iptables -w -S | grep -F -- "-s 192.0.2.0/24 -o ${netName}" || false
+  else
+    nft -nn list set inet lxd "aclset4.${netName}.testset" | grep -F "198.51.100.1"
+    ! nft -nn list set inet lxd "aclset4.${netName}.testset" | grep -F "192.0.2.0/24" || false
+  fi
+
+  echo "Verify address sets in use cannot be renamed or deleted"
+  ! lxc network address-set rename testset testset2 || false
+  ! lxc network address-set delete testset || false
+  lxc network acl rule remove testacl ingress source="\$testset"
+  if [ "$firewallDriver" != "xtables" ]; then
+    ! nft -nn list set inet lxd "aclset4.${netName}.testset" || false
+  fi
+
+  lxc network address-set rename testset testset2
+  lxc network address-set delete testset2
+
+  echo "Check ACL rule counters"
+  ingressRules="$(lxc network acl show testacl | yq --exit-status '.ingress | length')"
+  if [ "$firewallDriver" = "xtables" ]; then
+    lxc query "/1.0/network-acls/testacl/state" | jq --exit-status '.networks == [] and .nics == []'
+  else
+    lxc query "/1.0/network-acls/testacl/state" | jq --exit-status --arg net "${netName}" --argjson rules "${ingressRules}" '.networks[] | select(.name == $net) | (.ingress | length) == $rules'
+    lxc network acl show-state testacl --format csv | grep -xF "${netName},,,none,ingress,0,0,0"
+    lxc query "/1.0/metrics" | grep -F "lxd_network_acl_rule_packets_total{acl=\"testacl\",direction=\"ingress\",network=\"${netName}\",project=\"default\",rule=\"0\"} 0"
   fi
 
   echo "Stop applying ACL to test network"
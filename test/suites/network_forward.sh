@@ -32,6 +32,18 @@ test_network_forward() {
   # Check forward is exported via BGP prefixes.
   lxc query /internal/testing/bgp | grep -F "198.51.100.1/32"
 
+  # Check the BGP path attributes of the forward take precedence over the network's.
+  lxc network set "${netName}" bgp.communities=65000:1 bgp.med=50
+  lxc network forward set "${netName}" 198.51.100.1 bgp.communities=65000:2 bgp.as_path_prepend=2
+  lxc query /internal/testing/bgp | jq --exit-status '.prefixes[] | select(.prefix == "198.51.100.1/32") | .communities == ["65000:2"] and .med == 50 and .as_path_prepend == 2'
+  ! lxc network forward set "${netName}" 198.51.100.1 bgp.communities=foo || false
+  ! lxc network set "${netName}" bgp.as_path_prepend=17 || false
+  lxc network forward unset "${netName}" 198.51.100.1 bgp.communities
+  lxc network forward unset "${netName}" 198.51.100.1 bgp.as_path_prepend
+  lxc query /internal/testing/bgp | jq --exit-status '.prefixes[] | select(.prefix == "198.51.100.1/32") | .communities == ["65000:1"] and .as_path_prepend == 0'
+  lxc network unset "${netName}" bgp.communities
+  lxc network unset "${netName}" bgp.med
+
   # Enable the BGP listener
   lxc config set core.bgp_address="${bgpIP}:8874"
   lxc config set core.bgp_asn=65536
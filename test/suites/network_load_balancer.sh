@@ -0,0 +1,149 @@
+test_network_load_balancer() {
+  ensure_import_testimage
+
+  firewallDriver=$(lxc info | awk -F ":" '/firewall:/{gsub(/ /, "", $0); print $2}')
+  netName=lxdt$$
+
+  lxc network create "${netName}" \
+        ipv4.address=192.0.2.1/24 \
+        ipv6.address=fd42:4242:4242:1010::1/64
+
+  # Check creating a load balancer with an unspecified address fails.
+  ! lxc network load-balancer create "${netName}" 0.0.0.0 || false
+  ! lxc network load-balancer create "${netName}" :: || false
+
+  # Check creating empty load balancer doesn't create any firewall rules.
+  lxc network load-balancer create "${netName}" 198.51.100.1
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -t nat -S | grep -F "generated for LXD network-load-balancer ${netName}" || false
+  else
+    ! nft -nn list chain inet lxd "lbprert.${netName}" || false
+    ! nft -nn list chain inet lxd "lbout.${netName}" || false
+    ! nft -nn list chain inet lxd "lbpstrt.${netName}" || false
+  fi
+
+  # Check load balancer is exported via BGP prefixes.
+  lxc query /internal/testing/bgp | grep -F "198.51.100.1/32"
+
+  # Check the listen address can't be reused by a forward.
+  ! lxc network forward create "${netName}" 198.51.100.1 || false
+
+  # Check a single backend creates a plain DNAT rule.
+  lxc network load-balancer backend add "${netName}" 198.51.100.1 b1 192.0.2.2 8080
+  lxc network load-balancer port add "${netName}" 198.51.100.1 tcp 80 target_backend=b1
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -t nat -S | grep -F -- "-A PREROUTING -d 198.51.100.1/32 -p tcp -m tcp --dport 80 -m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j DNAT --to-destination 192.0.2.2:8080"
+    iptables -w -t nat -S | grep -F -- "-A OUTPUT -d 198.51.100.1/32 -p tcp -m tcp --dport 80 -m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j DNAT --to-destination 192.0.2.2:8080"
+    iptables -w -t nat -S | grep -F -- "-A POSTROUTING -s 192.0.2.2/32 -d 192.0.2.2/32 -p tcp -m tcp --dport 8080 -m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j MASQUERADE"
+  else
+    nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "ip daddr 198.51.100.1 tcp dport 80 dnat ip to 192.0.2.2:8080"
+    nft -nn list chain inet lxd "lbout.${netName}" | grep -F "ip daddr 198.51.100.1 tcp dport 80 dnat ip to 192.0.2.2:8080"
+    nft -nn list chain inet lxd "lbpstrt.${netName}" | grep -F "ip saddr 192.0.2.2 ip daddr 192.0.2.2 tcp dport 8080 masquerade"
+  fi
+
+  # Check weights outside of the allowed range are rejected.
+  ! lxc network load-balancer edit "${netName}" 198.51.100.1 <<EOF2 || false
+backends:
+- name: b1
+  target_address: 192.0.2.2
+  target_port: "8080"
+  weight: 1001
+ports:
+- protocol: tcp
+  listen_port: "80"
+  target_backend:
+  - b1
+EOF2
+
+  # Check weighted backends spread new connections proportionally to their weight.
+  lxc network load-balancer edit "${netName}" 198.51.100.1 <<EOF2
+backends:
+- name: b1
+  target_address: 192.0.2.2
+  target_port: "8080"
+  weight: 3
+- name: b2
+  target_address: 192.0.2.3
+  target_port: "8080"
+ports:
+- protocol: tcp
+  listen_port: "80"
+  target_backend:
+  - b1
+  - b2
+EOF2
+  lxc network load-balancer show "${netName}" 198.51.100.1 | yq --exit-status '.backends[0].weight == 3'
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -t nat -S | grep -F "generated for LXD network-load-balancer ${netName}" | grep -F -- "-A PREROUTING" | grep -F -- "--probability 0.75" | grep -F -- "--to-destination 192.0.2.2:8080"
+    iptables -w -t nat -S | grep -F -- "-A PREROUTING -d 198.51.100.1/32 -p tcp -m tcp --dport 80 -m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j DNAT --to-destination 192.0.2.3:8080"
+    iptables -w -t nat -S | grep -F -- "-A POSTROUTING -s 192.0.2.3/32 -d 192.0.2.3/32 -p tcp -m tcp --dport 8080 -m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j MASQUERADE"
+  else
+    nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "numgen random mod 4" | grep -F "192.0.2.2 . 8080" | grep -F "192.0.2.3 . 8080"
+    nft -nn list chain inet lxd "lbout.${netName}" | grep -F "numgen random mod 4"
+    nft -nn list chain inet lxd "lbpstrt.${netName}" | grep -F "ip saddr 192.0.2.3 ip daddr 192.0.2.3 tcp dport 8080 masquerade"
+  fi
+
+  # Check removing the port clears the firewall rules.
+  lxc network load-balancer port remove "${netName}" 198.51.100.1 tcp 80
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -t nat -S | grep -F "generated for LXD network-load-balancer ${netName}" || false
+  else
+    ! nft -nn list chain inet lxd "lbprert.${netName}" || false
+  fi
+
+  # Check pool instances are used as targets until their health check fails.
+  lxc init testimage c1 --network "${netName}"
+  lxc config device set c1 eth0 ipv4.address=192.0.2.10
+  lxc start c1
+
+  lxc network load-balancer pool create "${netName}" p1 target_port=8080 healthcheck.interval=1 healthcheck.failure_count=2
+  lxc network load-balancer pool instance add "${netName}" p1 c1
+  lxc network load-balancer port add "${netName}" 198.51.100.1 tcp 443 target_pool=p1
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -t nat -S | grep -F -- "-A PREROUTING -d 198.51.100.1/32 -p tcp -m tcp --dport 443 -m comment --comment \"generated for LXD network-load-balancer ${netName}\" -j DNAT --to-destination 192.0.2.10:8080"
+  else
+    nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "ip daddr 198.51.100.1 tcp dport 443 dnat ip to 192.0.2.10:8080"
+  fi
+
+  # Nothing listens on the target port so the instance goes offline and stops being a target.
+  for _ in $(seq 20); do
+    lxc network load-balancer pool info "${netName}" p1 | grep -F "offline" && break
+    sleep 1
+  done
+
+  lxc network load-balancer pool info "${netName}" p1 | grep -F "offline"
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -t nat -S | grep -F "generated for LXD network-load-balancer ${netName}" || false
+  else
+    ! nft -nn list chain inet lxd "lbprert.${netName}" || false
+  fi
+
+  # Check disabling health checks makes the instance a target again with an unknown status.
+  lxc network load-balancer pool set "${netName}" p1 healthcheck=false
+  lxc network load-balancer pool info "${netName}" p1 | grep -F "unknown"
+  if [ "$firewallDriver" = "xtables" ]; then
+    iptables -w -t nat -S | grep -F "generated for LXD network-load-balancer ${netName}" | grep -F -- "--to-destination 192.0.2.10:8080"
+  else
+    nft -nn list chain inet lxd "lbprert.${netName}" | grep -F "ip daddr 198.51.100.1 tcp dport 443 dnat ip to 192.0.2.10:8080"
+  fi
+
+  lxc delete -f c1
+
+  # Check load balancer is exported via BGP prefixes before network delete.
+  lxc query /internal/testing/bgp | grep -F "198.51.100.1/32"
+
+  # Check deleting the network clears the load balancer firewall rules and BGP prefix.
+  lxc network load-balancer port remove "${netName}" 198.51.100.1 tcp 443
+  lxc network load-balancer pool delete "${netName}" p1
+  lxc network delete "${netName}"
+
+  ! lxc query /internal/testing/bgp | grep -F "198.51.100.1/32" || false
+
+  if [ "$firewallDriver" = "xtables" ]; then
+    ! iptables -w -t nat -S | grep -F "generated for LXD network-load-balancer ${netName}" || false
+  else
+    ! nft -nn list chain inet lxd "lbprert.${netName}" || false
+    ! nft -nn list chain inet lxd "lbout.${netName}" || false
+    ! nft -nn list chain inet lxd "lbpstrt.${netName}" || false
+  fi
+}
@@ -148,6 +148,23 @@ test_network_ovn() {
   [ "$(< "/sys/class/net/${c3Eth0Hostname}/mtu")" = "8942" ]
   [ "$(lxc exec c3 -- cat /sys/class/net/eth0/mtu)" = "8942" ]
 
+  echo "Check ACL rule counters of instance NICs that set their own ACLs."
+  lxc network acl create nicacl
+  lxc network acl rule add nicacl egress action=allow
+  lxc config device set c1 eth0 security.acls=nicacl
+  lxc query /1.0/network-acls/nicacl/state | jq --exit-status '[.nics[] | select(.instance == "c1" and .name == "eth0" and .network == "'"${ovn_network}"'" and (.egress | length) == 1)] | length == 1'
+  lxc query /1.0/network-acls/nicacl/state | jq --exit-status '[.nics[] | select(.instance != "c1")] == []'
+  lxc query /1.0/network-acls/nicacl/state | jq --exit-status '[.networks[] | select(.name == "'"${ovn_network}"'")] | length == 1'
+  lxc network acl show-state nicacl --format csv | grep -E "^${ovn_network},c1,eth0,[^,]+,egress,0,"
+
+  echo "Check that NICs of stopped instances have no ACL rule counters."
+  lxc stop --force c1
+  lxc query /1.0/network-acls/nicacl/state | jq --exit-status '.nics == []'
+  lxc start c1
+  lxc config device unset c1 eth0 security.acls
+  lxc query /1.0/network-acls/nicacl/state | jq --exit-status '.nics == [] and .networks == []'
+  lxc network acl delete nicacl
+
   if [ "${LXD_VM_TESTS}" != "0" ]; then
     ensure_import_ubuntu_vm_image
 
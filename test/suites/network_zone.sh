@@ -137,6 +137,47 @@ test_network_zone() {
   [ "$(dig "@${DNS_ADDR}" -p "${DNS_PORT}" axfr lxdfoo.example.net | grep -Fc demo.lxdfoo.example.net)" = "6" ]
   lxc network zone record entry remove lxdfoo.example.net demo A 1.1.1.1 --project foo
 
+  # Test answering DNS queries.
+  dig "@${DNS_ADDR}" -p "${DNS_PORT}" c1.lxd.example.net A | grep -F "status: NOTIMP"
+  ! lxc network zone set lxd.example.net dns.query.sources=foo || false
+  lxc network zone set lxd.example.net dns.query.enabled=true
+  [ "$(dig +short "@${DNS_ADDR}" -p "${DNS_PORT}" c1.lxd.example.net A)" = "192.0.2.42" ]
+  [ "$(dig +short +tcp "@${DNS_ADDR}" -p "${DNS_PORT}" c1.lxd.example.net AAAA)" = "fd42:4242:4242:1010::42" ]
+  [ "$(dig +short "@${DNS_ADDR}" -p "${DNS_PORT}" demo.lxd.example.net A)" = "2.2.2.2" ]
+  dig +short "@${DNS_ADDR}" -p "${DNS_PORT}" demo.lxd.example.net MX | grep -xF "10 mx2.example.net."
+  dig "@${DNS_ADDR}" -p "${DNS_PORT}" missing.lxd.example.net A | grep -F "status: NXDOMAIN"
+  dig "@${DNS_ADDR}" -p "${DNS_PORT}" c1.example.org A | grep -F "status: REFUSED"
+  [ "$(dig +short "@${DNS_ADDR}" -p "${DNS_PORT}" -x 192.0.2.42 || echo fail)" = "" ]
+  lxc network zone set lxd.example.net dns.query.sources=198.51.100.0/24
+  dig "@${DNS_ADDR}" -p "${DNS_PORT}" c1.lxd.example.net A | grep -F "status: REFUSED"
+  lxc network zone set lxd.example.net dns.query.sources=192.0.2.0/24
+  [ "$(dig +short "@${DNS_ADDR}" -p "${DNS_PORT}" c1.lxd.example.net A)" = "192.0.2.42" ]
+  lxc network zone set 2.0.192.in-addr.arpa dns.query.enabled=true
+  [ "$(dig +short "@${DNS_ADDR}" -p "${DNS_PORT}" -x 192.0.2.42)" = "c1.lxd.example.net." ]
+  lxc network zone unset 2.0.192.in-addr.arpa dns.query.enabled
+  lxc network zone unset lxd.example.net dns.query.sources
+
+  # Test DNSSEC signing.
+  lxc query /1.0/network-zones/lxd.example.net/state | jq --exit-status '.dnssec == null'
+  ! dig "@${DNS_ADDR}" -p "${DNS_PORT}" axfr lxd.example.net | grep -F "RRSIG" || false
+  ! lxc network zone set lxd.example.net dnssec.zsk.lifetime=1 || false
+  lxc network zone set lxd.example.net dnssec.enabled=true dnssec.zsk.lifetime=60
+  [ "$(lxc query /1.0/network-zones/lxd.example.net/state | jq --exit-status '.dnssec.keys | length')" = "2" ]
+  [ "$(lxc query /1.0/network-zones/lxd.example.net/state | jq --exit-status '.dnssec.ds | length')" = "1" ]
+  lxc query /1.0/network-zones/lxd.example.net/state | jq --exit-status 'all(.dnssec.keys[]; .status == "active")'
+  [ "$(dig "@${DNS_ADDR}" -p "${DNS_PORT}" axfr lxd.example.net | grep -c "\sIN\s\+DNSKEY\s")" = "2" ]
+  dig "@${DNS_ADDR}" -p "${DNS_PORT}" axfr lxd.example.net | grep "c1.lxd.example.net.\s\+300\s\+IN\s\+RRSIG\s\+A\s\+"
+  dig "@${DNS_ADDR}" -p "${DNS_PORT}" axfr lxd.example.net | grep "c1.lxd.example.net.\s\+[0-9]\+\s\+IN\s\+NSEC\s\+"
+  dig +dnssec "@${DNS_ADDR}" -p "${DNS_PORT}" c1.lxd.example.net A | grep -F "RRSIG"
+  ! dig "@${DNS_ADDR}" -p "${DNS_PORT}" c1.lxd.example.net A | grep -F "RRSIG" || false
+  dig +dnssec "@${DNS_ADDR}" -p "${DNS_PORT}" missing.lxd.example.net A | grep -F "NSEC"
+  lxc network zone unset lxd.example.net dnssec.enabled
+  lxc query /1.0/network-zones/lxd.example.net/state | jq --exit-status '.dnssec == null'
+  ! dig "@${DNS_ADDR}" -p "${DNS_PORT}" axfr lxd.example.net | grep -F "RRSIG" || false
+  lxc network zone unset lxd.example.net dnssec.zsk.lifetime
+
+  lxc network zone unset lxd.example.net dns.query.enabled
+
   # Test patching of network zone record.
   lxc network zone record create lxd.example.net patchtest user.key1=val1 user.key2=val2
   lxc network zone record entry add lxd.example.net patchtest A 3.3.3.3 --ttl 600
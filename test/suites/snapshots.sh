@@ -630,3 +630,58 @@ test_snapshot_multi_volume() {
   lxc storage volume delete "${poolName}" shared
   lxc storage volume delete "${poolName}" non-shared
 }
+
+test_snapshot_diff() {
+  ensure_import_testimage
+
+  local poolName
+  poolName="lxdtest-$(basename "${LXD_DIR}")"
+
+  lxc init testimage c1
+  echo "foo" | lxc file push - c1/root/modified
+  echo "foo" | lxc file push - c1/root/removed
+  lxc snapshot c1 snap0
+
+  echo "Check that a snapshot without changes has no differences."
+  [ "$(lxc snapshot diff c1/snap0 --format csv || echo fail)" = "" ]
+
+  echo "foobar" | lxc file push - c1/root/modified
+  echo "foo" | lxc file push - c1/root/added
+  lxc file delete c1/root/removed
+  lxc snapshot c1 snap1
+
+  echo "Check the differences with the current content and a later snapshot."
+  for against in "" "snap1"; do
+    # shellcheck disable=SC2086
+    lxc snapshot diff c1/snap0 ${against} --format csv > "${TEST_DIR}/diff.csv"
+    grep -q '^added,/rootfs/root/added,file,' "${TEST_DIR}/diff.csv"
+    grep -q '^modified,/rootfs/root/modified,file,' "${TEST_DIR}/diff.csv"
+    grep -q '^removed,/rootfs/root/removed,file,' "${TEST_DIR}/diff.csv"
+    ! grep -F '/rootfs/etc/' "${TEST_DIR}/diff.csv" || false
+  done
+
+  rm "${TEST_DIR}/diff.csv"
+
+  echo "Check that snapshots can only be compared with later snapshots."
+  ! lxc snapshot diff c1/snap1 snap0 || false
+  ! lxc snapshot diff c1/snap0 missing || false
+
+  lxc delete c1
+
+  echo "Check the differences of a custom volume snapshot."
+  lxc storage volume create "${poolName}" vol1
+  lxc launch testimage c1 -s "${poolName}"
+  lxc storage volume attach "${poolName}" vol1 c1 /mnt
+  lxc storage volume snapshot "${poolName}" vol1 snap0
+  lxc exec c1 -- touch /mnt/added
+  [ "$(lxc snapshot diff --storage "${poolName}" vol1/snap0 --format csv | cut -d, -f1,2)" = "added,/added" ]
+
+  echo "Check that block volumes cannot be compared."
+  lxc storage volume create "${poolName}" block1 --type=block
+  lxc storage volume snapshot "${poolName}" block1 snap0
+  ! lxc snapshot diff --storage "${poolName}" block1/snap0 || false
+
+  lxc delete -f c1
+  lxc storage volume delete "${poolName}" block1
+  lxc storage volume delete "${poolName}" vol1
+}
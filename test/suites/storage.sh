@@ -1047,3 +1047,152 @@ EOF
   LXD_DIR="${LXD_DIR}"
   kill_lxd "${LXD_STORAGE_DIR}"
 }
+
+test_storage_volume_limits() {
+  local pool
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+
+  ensure_import_testimage
+
+  # Pool defaults only apply to new custom volumes.
+  lxc storage set "${pool}" volume.limits.iops.write=1000
+  lxc storage volume create "${pool}" vol1
+  [ "$(lxc storage volume get "${pool}" vol1 limits.iops.write)" = "1000" ]
+  lxc init testimage c1 -s "${pool}"
+  [ "$(lxc storage volume get "${pool}" container/c1 limits.iops.write || echo fail)" = "" ]
+  lxc storage unset "${pool}" volume.limits.iops.write
+
+  # Invalid limits are rejected.
+  ! lxc storage volume set "${pool}" vol1 limits.iops.read=fast || false
+  ! lxc storage volume set "${pool}" vol1 limits.iops.read=0 || false
+  ! lxc storage volume set "${pool}" vol1 limits.bandwidth.read=fast || false
+  ! lxc storage volume set "${pool}" container/c1 limits.iops.read=100 || false
+
+  # The limits are reported in the volume state.
+  lxc storage volume set "${pool}" vol1 limits.bandwidth.read=10MiB limits.iops.read=200
+  [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq --exit-status '.limits.read_bytes')" = "10485760" ]
+  [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq --exit-status '.limits.read_iops')" = "200" ]
+  [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq --exit-status '.limits.write_iops')" = "1000" ]
+  lxc storage volume info "${pool}" vol1 | grep -xF "  Read bandwidth: 10.00MiB/s"
+  lxc storage volume info "${pool}" vol1 | grep -xF "  Write IOPS: 1000"
+
+  # The limits can be changed while the volume is attached to an instance.
+  lxc storage volume attach "${pool}" vol1 c1 /mnt
+  lxc storage volume set "${pool}" vol1 limits.iops.read=100
+  lxc storage volume unset "${pool}" vol1 limits.bandwidth.read
+  [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq --exit-status '.limits.read_bytes')" = "0" ]
+
+  # The limits are a budget shared by the running instances using the volume.
+  # This needs the volume to be backed by block devices of the host and a pure cgroup2 layout.
+  local lxd_backend
+  lxd_backend="$(storage_backend "$LXD_DIR")"
+  if [ "${lxd_backend}" = "lvm" ] || [ "${lxd_backend}" = "zfs" ] || [ "${lxd_backend}" = "btrfs" ]; then
+    if [ "$(stat -fc %T /sys/fs/cgroup)" = "cgroup2fs" ] && grep -qw io /sys/fs/cgroup/cgroup.controllers; then
+      lxc init testimage c2 -s "${pool}"
+      lxc storage volume attach "${pool}" vol1 c2 /mnt
+      lxc start c1 c2
+      [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq --exit-status '.limits.instances | length')" = "2" ]
+      lxc storage volume info "${pool}" vol1 | grep -xF "    - /1.0/instances/c1"
+
+      # Both containers are in the shared cgroup whose io.max holds the limits of the volume.
+      local pid cgroup_path
+      for c in c1 c2; do
+        pid="$(lxc query "/1.0/instances/${c}/state" | jq --exit-status '.pid')"
+        grep -F "/lxd.volume-limits/lxc.payload.${c}" "/proc/${pid}/cgroup"
+      done
+
+      cgroup_path="/sys/fs/cgroup$(awk -F: '$1 == "0" {print $3}' "/proc/${pid}/cgroup")"
+      grep -F "riops=100 " "${cgroup_path%/*}/io.max"
+
+      # Changes to the limits update the shared budget.
+      lxc storage volume set "${pool}" vol1 limits.iops.read=50
+      grep -F "riops=50 " "${cgroup_path%/*}/io.max"
+
+      # Stopped instances no longer share the limits.
+      lxc stop -f c2
+      [ "$(lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq --exit-status --raw-output '.limits.instances | join(",")')" = "/1.0/instances/c1" ]
+
+      lxc stop -f c1
+      ! grep -F "riops=" "${cgroup_path%/*}/io.max" || false
+      lxc delete c2
+      lxc storage volume set "${pool}" vol1 limits.iops.read=100
+    fi
+  fi
+
+  # Volumes without limits have none reported.
+  lxc storage volume unset "${pool}" vol1 limits.iops.read
+  lxc storage volume unset "${pool}" vol1 limits.iops.write
+  lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq --exit-status 'has("limits") | not'
+
+  lxc delete c1
+  lxc storage volume delete "${pool}" vol1
+}
+
+test_storage_volume_mirror() {
+  local pool mirror_pool
+  pool="lxdtest-$(basename "${LXD_DIR}")"
+  mirror_pool="${pool}-mirror"
+
+  ensure_import_testimage
+
+  lxc storage create "${mirror_pool}" dir
+  lxc storage volume create "${pool}" vol1
+  lxc launch testimage c1 -s "${pool}"
+  lxc storage volume attach "${pool}" vol1 c1 /mnt
+  lxc exec c1 -- sh -c "echo foo > /mnt/foo"
+
+  # Invalid mirror configurations are rejected.
+  ! lxc storage volume set "${pool}" vol1 mirror.pool="${pool}" || false
+  ! lxc storage volume set "${pool}" vol1 mirror.schedule=invalid || false
+  ! lxc storage volume set "${pool}" container/c1 mirror.pool="${mirror_pool}" || false
+  ! lxc storage volume mirror refresh "${pool}" vol1 || false
+  ! lxc storage volume mirror promote "${pool}" vol1 || false
+  ! lxc storage volume set "${pool}" vol1 mirror.pool=nonexistent || false
+
+  # The project must be allowed to use the mirror pool.
+  lxc project set default limits.disk.pool."${mirror_pool}"=0
+  ! lxc storage volume set "${pool}" vol1 mirror.pool="${mirror_pool}" || false
+  lxc project unset default limits.disk.pool."${mirror_pool}"
+
+  # The first refresh creates the mirror.
+  lxc storage volume set "${pool}" vol1 mirror.pool="${mirror_pool}" mirror.volume=vol1-mirror
+  lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq --exit-status '.mirror.role == "source" and .mirror.lag == -1'
+  lxc storage volume mirror refresh "${pool}" vol1
+  [ "$(lxc storage volume get "${mirror_pool}" vol1-mirror volatile.mirror.source)" = "$(lxc storage volume get "${pool}" vol1 volatile.uuid)" ]
+  [ "$(lxc storage volume get "${mirror_pool}" vol1-mirror mirror.pool || echo fail)" = "" ]
+  lxc query "/1.0/storage-pools/${pool}/volumes/custom/vol1/state" | jq --exit-status '.mirror.lag >= 0'
+  lxc query "/1.0/storage-pools/${mirror_pool}/volumes/custom/vol1-mirror/state" | jq --exit-status '.mirror.role == "target"'
+  lxc storage volume info "${mirror_pool}" vol1-mirror | grep -xF "  Role: target"
+
+  # The mirroring state cannot be changed by users.
+  ! lxc storage volume set "${mirror_pool}" vol1-mirror volatile.mirror.source=1c2c8bb4-8c93-4d3c-9a26-b3e2d3b5ba43 || false
+  ! lxc storage volume unset "${pool}" vol1 volatile.mirror.last_refresh || false
+
+  # Later refreshes bring over the changes and the snapshots.
+  lxc exec c1 -- sh -c "echo bar > /mnt/foo"
+  lxc storage volume snapshot "${pool}" vol1 snap0
+  lxc storage volume mirror refresh "${pool}" vol1
+  lxc storage volume show "${mirror_pool}" vol1-mirror/snap0
+  lxc storage volume attach "${mirror_pool}" vol1-mirror c1 /mnt2
+  [ "$(lxc exec c1 -- cat /mnt2/foo)" = "bar" ]
+  lxc storage volume detach "${mirror_pool}" vol1-mirror c1
+
+  # A volume which isn't the mirror of the source is never overwritten.
+  lxc storage volume create "${mirror_pool}" vol2
+  lxc storage volume set "${pool}" vol1 mirror.volume=vol2
+  ! lxc storage volume mirror refresh "${pool}" vol1 || false
+  lxc storage volume set "${pool}" vol1 mirror.volume=vol1-mirror
+  lxc storage volume delete "${mirror_pool}" vol2
+
+  # Promoting the mirror stops the mirroring on both sides.
+  lxc storage volume mirror promote "${mirror_pool}" vol1-mirror
+  [ "$(lxc storage volume get "${pool}" vol1 mirror.pool || echo fail)" = "" ]
+  [ "$(lxc storage volume get "${mirror_pool}" vol1-mirror volatile.mirror.source || echo fail)" = "" ]
+  lxc query "/1.0/storage-pools/${mirror_pool}/volumes/custom/vol1-mirror/state" | jq --exit-status 'has("mirror") | not'
+  ! lxc storage volume mirror promote "${mirror_pool}" vol1-mirror || false
+
+  lxc delete -f c1
+  lxc storage volume delete "${pool}" vol1
+  lxc storage volume delete "${mirror_pool}" vol1-mirror
+  lxc storage delete "${mirror_pool}"
+}
@@ -175,3 +175,115 @@ EOF
 
   delete_object_storage_pool "${poolName}"
 }
+
+test_storage_buckets_local() {
+  local lxd_backend
+
+  lxd_backend=$(storage_backend "$LXD_DIR")
+
+  if ! [[ "$lxd_backend" =~ ^(dir|btrfs|lvm|zfs)$ ]]; then
+    export TEST_UNMET_REQUIREMENT="Local storage bucket tests require a local storage backend"
+    return
+  fi
+
+  poolName=$(lxc profile device get default root pool)
+  bucketPrefix="lxd$$"
+
+  # Buckets have no URL until the storage buckets listener is configured.
+  lxc storage bucket create "${poolName}" "${bucketPrefix}.nourl"
+  lxc query "/1.0/storage-pools/${poolName}/buckets/${bucketPrefix}.nourl" | jq --exit-status '.s3_url == ""'
+  lxc storage bucket delete "${poolName}" "${bucketPrefix}.nourl"
+
+  s3Endpoint="127.0.0.1:$(local_tcp_port)"
+  lxc config set core.storage_buckets_address "${s3Endpoint}"
+
+  # Create bucket.
+  initCreds=$(lxc storage bucket create "${poolName}" "${bucketPrefix}.foo")
+  adAccessKey=$(echo "${initCreds}" | awk '{ if ($2 == "access" && $3 == "key:") {print $4}}')
+  adSecretKey=$(echo "${initCreds}" | awk '{ if ($2 == "secret" && $3 == "key:") {print $4}}')
+  [ "$(lxc storage bucket show "${poolName}" "${bucketPrefix}.foo" | awk '{if ($1 == "s3_url:") {print $2}}')" = "https://${s3Endpoint}/${bucketPrefix}.foo" ]
+
+  # Create read-only key with manually specified credentials.
+  lxc storage bucket key create "${poolName}" "${bucketPrefix}.foo" ro-key --role=read-only --access-key="${bucketPrefix}.foo.ro" --secret-key="password"
+  roAccessKey="${bucketPrefix}.foo.ro"
+  roSecretKey="password"
+
+  # Access keys must be unique, including across buckets, and the conflict doesn't reveal which key uses it.
+  ! lxc storage bucket key create "${poolName}" "${bucketPrefix}.foo" dup-key --access-key="${roAccessKey}" || false
+  lxc storage bucket create "${poolName}" "${bucketPrefix}.dup"
+  [[ "$(! lxc storage bucket key create "${poolName}" "${bucketPrefix}.dup" dup-key --access-key="${roAccessKey}" 2>&1 1>/dev/null)" = *"A bucket key with that name or access key already exists" ]]
+  lxc storage bucket delete "${poolName}" "${bucketPrefix}.dup"
+
+  # Test listing buckets via S3.
+  s3cmdrun_local "${adAccessKey}" "${adSecretKey}" ls | grep -F "${bucketPrefix}.foo"
+  s3cmdrun_local "${roAccessKey}" "${roSecretKey}" ls | grep -F "${bucketPrefix}.foo"
+  ! s3cmdrun_local "${roAccessKey}" "wrong" ls || false
+
+  # Test making and deleting buckets via S3 is blocked.
+  ! s3cmdrun_local "${adAccessKey}" "${adSecretKey}" mb "s3://${bucketPrefix}.foo2" || false
+  ! s3cmdrun_local "${adAccessKey}" "${adSecretKey}" rb "s3://${bucketPrefix}.foo" || false
+
+  # Test putting, listing and getting a file.
+  lxdTestFile="bucketfile_${bucketPrefix}.txt"
+  echo $$ > "${lxdTestFile}"
+  truncate -s 5M --no-create "${lxdTestFile}"
+  ORIG_MD5SUM="$(md5sum < "${lxdTestFile}")"
+  s3cmdrun_local "${adAccessKey}" "${adSecretKey}" put "${lxdTestFile}" "s3://${bucketPrefix}.foo"
+  ! s3cmdrun_local "${roAccessKey}" "${roSecretKey}" put "${lxdTestFile}" "s3://${bucketPrefix}.foo" || false
+  s3cmdrun_local "${roAccessKey}" "${roSecretKey}" ls "s3://${bucketPrefix}.foo" | grep -F "${lxdTestFile}"
+  s3cmdrun_local "${roAccessKey}" "${roSecretKey}" get "s3://${bucketPrefix}.foo/${lxdTestFile}" "${lxdTestFile}.get"
+  [ "${ORIG_MD5SUM}" = "$(md5sum < "${lxdTestFile}.get")" ]
+  rm "${lxdTestFile}.get"
+
+  # Test multipart upload.
+  truncate -s 20M "${lxdTestFile}.big"
+  s3cmdrun_local "${adAccessKey}" "${adSecretKey}" --multipart-chunk-size-mb=5 put "${lxdTestFile}.big" "s3://${bucketPrefix}.foo"
+  s3cmdrun_local "${adAccessKey}" "${adSecretKey}" get "s3://${bucketPrefix}.foo/${lxdTestFile}.big" "${lxdTestFile}.get"
+  cmp "${lxdTestFile}.big" "${lxdTestFile}.get"
+  rm "${lxdTestFile}.get"
+
+  # Test deleting a file.
+  ! s3cmdrun_local "${roAccessKey}" "${roSecretKey}" del "s3://${bucketPrefix}.foo/${lxdTestFile}" || false
+  s3cmdrun_local "${adAccessKey}" "${adSecretKey}" del "s3://${bucketPrefix}.foo/${lxdTestFile}"
+  s3cmdrun_local "${adAccessKey}" "${adSecretKey}" del "s3://${bucketPrefix}.foo/${lxdTestFile}.big"
+
+  # Test bucket quota.
+  initCreds=$(lxc storage bucket create "${poolName}" "${bucketPrefix}.foo2" size=1MiB)
+  initAccessKey=$(echo "${initCreds}" | awk '{ if ($2 == "access" && $3 == "key:") {print $4}}')
+  initSecretKey=$(echo "${initCreds}" | awk '{ if ($2 == "secret" && $3 == "key:") {print $4}}')
+  ! s3cmdrun_local "${initAccessKey}" "${initSecretKey}" put "${lxdTestFile}" "s3://${bucketPrefix}.foo2" || false
+
+  # Grow bucket quota.
+  lxc storage bucket set "${poolName}" "${bucketPrefix}.foo2" size=150MiB
+  s3cmdrun_local "${initAccessKey}" "${initSecretKey}" put "${lxdTestFile}" "s3://${bucketPrefix}.foo2"
+  lxc storage bucket delete "${poolName}" "${bucketPrefix}.foo2"
+  rm "${lxdTestFile}" "${lxdTestFile}.big"
+
+  # Test project disk limits.
+  lxc project create "${bucketPrefix}" -c features.storage.buckets=true -c limits.disk=100MiB
+  ! lxc storage bucket create "${poolName}" "${bucketPrefix}.limits" --project "${bucketPrefix}" || false
+  ! lxc storage bucket create "${poolName}" "${bucketPrefix}.limits" size=200MiB --project "${bucketPrefix}" || false
+  lxc storage bucket create "${poolName}" "${bucketPrefix}.limits" size=50MiB --project "${bucketPrefix}"
+  ! lxc storage bucket set "${poolName}" "${bucketPrefix}.limits" size=200MiB --project "${bucketPrefix}" || false
+  lxc storage bucket delete "${poolName}" "${bucketPrefix}.limits" --project "${bucketPrefix}"
+  lxc project delete "${bucketPrefix}"
+
+  # Delete bucket keys.
+  lxc storage bucket key delete "${poolName}" "${bucketPrefix}.foo" ro-key
+  ! s3cmdrun_local "${roAccessKey}" "${roSecretKey}" ls || false
+
+  # Delete bucket.
+  lxc storage bucket delete "${poolName}" "${bucketPrefix}.foo"
+  ! s3cmdrun_local "${adAccessKey}" "${adSecretKey}" ls || false
+
+  lxc config unset core.storage_buckets_address
+}
+
+s3cmdrun_local () {
+  local accessKey secreyKey
+  accessKey="${1}"
+  secreyKey="${2}"
+  shift 2
+
+  s3cmdrun "${accessKey}" "${secreyKey}" --ssl --no-check-certificate "$@"
+}
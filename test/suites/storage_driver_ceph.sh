@@ -149,6 +149,55 @@ test_storage_driver_ceph() {
     lxc storage delete "${pool1}"
     lxc storage delete "${pool2}"
     ceph --cluster "${LXD_CEPH_CLUSTER}" osd pool set ".mgr" size 1 --yes-i-really-mean-it
+
+    # Test volume encryption.
+    if command -v cryptsetup >/dev/null; then
+      lxc storage create "${pool1}" ceph volume.size="${DEFAULT_VOLUME_SIZE}" ceph.osd.pg_num=8 volume.security.encrypted=true
+      ensure_import_testimage
+
+      lxc launch testimage c1 -s "${pool1}"
+      lxc storage volume create "${pool1}" vol1
+      [ "$(lxc storage volume get "${pool1}" vol1 security.encrypted)" = "true" ]
+
+      # The keys are wrapped with the cluster key so that any cluster member can unlock the volumes.
+      key="$(lxc storage volume get "${pool1}" vol1 volatile.encryption.key)"
+      [ "${key#cluster:}" != "${key}" ]
+      [ "$(lxc storage volume get "${pool1}" container/c1 volatile.encryption.key)" != "${key}" ]
+
+      lxc storage volume attach "${pool1}" vol1 c1 /mnt
+      lxc exec c1 -- sh -c "echo foo > /mnt/bar"
+
+      # The RBD images only contain LUKS encrypted data.
+      dev="$(rbd --cluster "${LXD_CEPH_CLUSTER}" showmapped --format json | jq -r '.[] | select(.name == "custom_default_vol1") | .device')"
+      [ "$(blkid -s TYPE -o value -p "${dev}")" = "crypto_LUKS" ]
+
+      # Volumes are unlocked again after a restart.
+      lxc restart c1 --force
+      [ "$(lxc exec c1 -- cat /mnt/bar)" = "foo" ]
+
+      # Snapshots and copies.
+      lxc storage volume snapshot "${pool1}" vol1 snap0
+      [ "$(lxc storage volume get "${pool1}" vol1/snap0 volatile.encryption.key)" = "${key}" ]
+      lxc storage volume copy "${pool1}/vol1/snap0" "${pool1}/vol2"
+      lxc storage volume attach "${pool1}" vol2 c1 /mnt2
+      [ "$(lxc exec c1 -- cat /mnt2/bar)" = "foo" ]
+      lxc storage volume detach "${pool1}" vol2 c1
+
+      # Growing an encrypted volume.
+      lxc storage volume set "${pool1}" vol1 size=128MiB
+      [ "$(lxc exec c1 -- df -m /mnt | awk 'NR==2 {print $2}')" -gt 100 ]
+      [ "$(lxc exec c1 -- cat /mnt/bar)" = "foo" ]
+
+      lxc delete -f c1
+      lxc storage volume delete "${pool1}" vol1/snap0
+      lxc storage volume delete "${pool1}" vol1
+      lxc storage volume delete "${pool1}" vol2
+      lxc image delete testimage
+      lxc storage delete "${pool1}"
+
+      # All unlocked devices were locked again.
+      ! ls /dev/mapper/lxd-luks-* 2>/dev/null || false
+    fi
   )
 
   # shellcheck disable=SC2031
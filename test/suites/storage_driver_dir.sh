@@ -8,6 +8,7 @@ test_storage_driver_dir() {
   fi
 
   do_dir_on_empty_fs
+  do_dir_block_format
 
   if uname -r | grep -- -kvm$; then
     echo "==> SKIP: the -kvm kernel flavor is does not support XFS quotas (CONFIG_XFS_QUOTA is not set)"
@@ -50,6 +51,38 @@ do_dir_on_empty_fs() {
   deconfigure_loop_device "${tmp_file}" "${tmp_device}"
 }
 
+do_dir_block_format() {
+  echo "==> Check dir.block_format validation."
+  ! lxc storage create s1 dir dir.block_format=vmdk || false
+
+  if ! command -v qemu-img >/dev/null; then
+    echo "==> SKIP: qcow2 disk checks require qemu-img"
+    return
+  fi
+
+  lxc storage create s1 dir dir.block_format=qcow2
+  [ "$(lxc storage get s1 dir.block_format)" = "qcow2" ]
+
+  echo "==> Check that VM disks are created as qcow2 images."
+  lxc init --vm --empty v1 -s s1 -c limits.memory=128MiB -d "${SMALL_ROOT_DISK}"
+  pool_path="$(lxc storage get s1 source)"
+  [ -f "${pool_path}/virtual-machines/v1/root.qcow2" ]
+  [ ! -e "${pool_path}/virtual-machines/v1/root.img" ]
+
+  echo "==> Check that snapshots share the disk layers with the instance."
+  lxc snapshot v1 snap0
+  [ -f "${pool_path}/virtual-machines-snapshots/v1/snap0/root.qcow2" ]
+  [ "$(find "${pool_path}/virtual-machines/v1" -name 'layer-*.qcow2' | wc -l)" = "1" ]
+
+  echo "==> Check that restoring and deleting snapshots clean up unused layers."
+  lxc restore v1 snap0
+  lxc delete v1/snap0
+  [ "$(find "${pool_path}/virtual-machines/v1" -name 'layer-*.qcow2' | wc -l)" = "0" ]
+
+  lxc delete v1
+  lxc storage delete s1
+}
+
 do_dir_xfs_project_quotas() {
   echo "==> Create and mount a small XFS filesystem with project quotas."
 
@@ -0,0 +1,98 @@
+test_storage_driver_lvm() {
+  local lxd_backend
+
+  lxd_backend=$(storage_backend "${LXD_DIR}")
+  if [ "${lxd_backend}" != "lvm" ]; then
+    export TEST_UNMET_REQUIREMENT="lvm specific test, not for ${lxd_backend}"
+    return
+  fi
+
+  if ! command -v cryptsetup >/dev/null; then
+    export TEST_UNMET_REQUIREMENT="cryptsetup is required"
+    return
+  fi
+
+  do_storage_driver_lvm_encryption
+}
+
+do_storage_driver_lvm_encryption() {
+  local pool vg_name key
+  pool="lxdtest-$(basename "${LXD_DIR}")-encrypted"
+
+  ensure_import_testimage
+
+  lxc storage create "${pool}" lvm volume.size=24MiB volume.security.encrypted=true
+  vg_name="$(lxc storage get "${pool}" lvm.vg_name)"
+
+  echo "==> Encrypted instance and custom volumes"
+  lxc launch testimage c1 -s "${pool}"
+  lxc storage volume create "${pool}" vol1
+  [ "$(lxc storage volume get "${pool}" vol1 security.encrypted)" = "true" ]
+  key="$(lxc storage volume get "${pool}" vol1 volatile.encryption.key)"
+  [ -n "${key}" ]
+  [ "$(lxc storage volume get "${pool}" container/c1 volatile.encryption.key)" != "${key}" ]
+
+  # The server key is kept outside of the database and only readable by root.
+  [ "$(stat -c '%a' "${LXD_DIR}/storage-encryption.key")" = "600" ]
+
+  # The logical volumes only contain LUKS encrypted data.
+  [ "$(blkid -s TYPE -o value -p "/dev/${vg_name}/containers_c1")" = "crypto_LUKS" ]
+  [ "$(blkid -s TYPE -o value -p "/dev/${vg_name}/custom_default_vol1")" = "crypto_LUKS" ]
+
+  lxc storage volume attach "${pool}" vol1 c1 /mnt
+  lxc exec c1 -- sh -c "echo foo > /mnt/bar"
+
+  echo "==> Encryption cannot be changed after creation"
+  ! lxc storage volume set "${pool}" vol1 security.encrypted=false || false
+  ! lxc storage volume set "${pool}" vol1 volatile.encryption.key=foo || false
+
+  echo "==> Volumes are unlocked again after a restart"
+  lxc restart c1 --force
+  [ "$(lxc exec c1 -- cat /mnt/bar)" = "foo" ]
+
+  echo "==> Snapshots and copies"
+  lxc storage volume snapshot "${pool}" vol1 snap0
+  [ "$(lxc storage volume get "${pool}" vol1/snap0 volatile.encryption.key)" = "${key}" ]
+  lxc storage volume copy "${pool}/vol1" "${pool}/vol2"
+  [ "$(lxc storage volume get "${pool}" vol2 security.encrypted)" = "true" ]
+  lxc storage volume attach "${pool}" vol2 c1 /mnt2
+  [ "$(lxc exec c1 -- cat /mnt2/bar)" = "foo" ]
+  lxc storage volume detach "${pool}" vol2 c1
+
+  echo "==> Backup round trip"
+  lxc storage volume export "${pool}" vol1 "${LXD_DIR}/vol1.tar.gz"
+  lxc storage volume import "${pool}" "${LXD_DIR}/vol1.tar.gz" vol3
+  [ "$(lxc storage volume get "${pool}" vol3 security.encrypted)" = "true" ]
+  lxc storage volume attach "${pool}" vol3 c1 /mnt3
+  [ "$(lxc exec c1 -- cat /mnt3/bar)" = "foo" ]
+  lxc storage volume detach "${pool}" vol3 c1
+  rm "${LXD_DIR}/vol1.tar.gz"
+
+  echo "==> Unencrypted volumes can still be created"
+  lxc storage volume create "${pool}" vol4 security.encrypted=false
+  [ -z "$(lxc storage volume get "${pool}" vol4 volatile.encryption.key || echo fail)" ]
+  [ "$(blkid -s TYPE -o value -p "/dev/${vg_name}/custom_default_vol4")" != "crypto_LUKS" ]
+
+  echo "==> Recovered volumes keep their key"
+  lxc storage volume create "${pool}" vol5
+  key="$(lxc storage volume get "${pool}" vol5 volatile.encryption.key)"
+  lxd sql global "PRAGMA foreign_keys=ON; DELETE FROM storage_volumes WHERE name='vol5'"
+  ! lxc storage volume show "${pool}" vol5 || false
+  lxd recover <<EOF
+yes
+yes
+EOF
+  [ "$(lxc storage volume get "${pool}" vol5 volatile.encryption.key)" = "${key}" ]
+  lxc storage volume attach "${pool}" vol5 c1 /mnt5
+  lxc storage volume detach "${pool}" vol5 c1
+
+  echo "==> Cleanup"
+  lxc delete -f c1
+  lxc storage volume delete "${pool}" vol1/snap0
+  lxc storage volume delete "${pool}" vol1
+  lxc storage volume delete "${pool}" vol2
+  lxc storage volume delete "${pool}" vol3
+  lxc storage volume delete "${pool}" vol4
+  lxc storage volume delete "${pool}" vol5
+  lxc storage delete "${pool}"
+}
@@ -0,0 +1,90 @@
+test_storage_driver_nfs() {
+  local nfs_export="${LXD_NFS_EXPORT:-}"
+  local export_path=""
+  local vol_path
+
+  if ! command -v mount.nfs >/dev/null; then
+    export TEST_UNMET_REQUIREMENT="mount.nfs is missing"
+    return
+  fi
+
+  # Without an existing export, export a local directory through the kernel NFS server.
+  if [ -z "${nfs_export}" ]; then
+    if ! command -v exportfs >/dev/null || [ "$(cat /proc/fs/nfsd/threads 2>/dev/null || echo 0)" = "0" ]; then
+      export TEST_UNMET_REQUIREMENT="requires a running kernel NFS server or 'LXD_NFS_EXPORT' to be set"
+      return
+    fi
+
+    export_path="$(mktemp -d -p "${TEST_DIR}" XXX)"
+    exportfs -o rw,sync,no_subtree_check,no_root_squash,fsid="$(uuidgen)" "127.0.0.1:${export_path}"
+    nfs_export="127.0.0.1:${export_path}"
+  fi
+
+  # Invalid configurations.
+  ! lxc storage create nfs nfs || false
+  ! lxc storage create nfs nfs nfs.export=/srv/lxd || false
+  ! lxc storage create nfs nfs nfs.export="${nfs_export}" volume.security.shared=true || false
+  ! lxc storage create nfs nfs nfs.export="${nfs_export}" source=/srv/lxd || false
+
+  # Simple create/delete attempt.
+  lxc storage create nfs nfs nfs.export="${nfs_export}"
+  lxc storage show nfs | grep -xF "driver: nfs"
+  lxc query /1.0/storage-pools/nfs | jq --exit-status --arg export "${nfs_export}" '.config."nfs.export" == $export'
+  lxc storage info nfs
+  lxc storage delete nfs
+
+  lxc storage create nfs nfs nfs.export="${nfs_export}"
+
+  # The export can only be used by a single pool.
+  ! lxc storage create nfs2 nfs nfs.export="${nfs_export}" || false
+
+  # Only filesystem custom volumes are supported.
+  ! lxc storage volume create nfs vol1 --type=block || false
+
+  # Creation, rename, copy and deletion.
+  lxc storage volume create nfs vol1
+  lxc storage volume rename nfs vol1 vol2
+  lxc storage volume copy nfs/vol2 nfs/vol1
+  lxc storage volume delete nfs vol1
+  lxc storage volume delete nfs vol2
+
+  # Snapshots.
+  lxc storage volume create nfs vol1
+  vol_path="${LXD_DIR}/storage-pools/nfs/custom/default_vol1"
+  echo foo > "${vol_path}/data"
+  lxc storage volume snapshot nfs vol1 snap0
+  echo bar > "${vol_path}/data"
+  lxc storage volume snapshot nfs vol1
+  lxc storage volume rename nfs vol1/snap1 vol1/snap2
+  lxc storage volume restore nfs vol1 snap0
+  [ "$(< "${vol_path}/data")" = "foo" ]
+
+  # Copies include the snapshots.
+  lxc storage volume copy nfs/vol1 nfs/vol2
+  lxc query /1.0/storage-pools/nfs/volumes/custom/vol2/snapshots | jq --exit-status 'length == 2'
+  [ "$(< "${LXD_DIR}/storage-pools/nfs/custom-snapshots/default_vol2/snap2/data")" = "bar" ]
+  lxc storage volume copy nfs/vol1/snap2 nfs/vol3
+  [ "$(< "${LXD_DIR}/storage-pools/nfs/custom/default_vol3/data")" = "bar" ]
+
+  # The data lives on the export.
+  if [ -n "${export_path}" ]; then
+    [ "$(< "${export_path}/custom/default_vol3/data")" = "bar" ]
+  fi
+
+  lxc storage volume delete nfs vol1/snap0
+  lxc storage volume delete nfs vol1/snap2
+  lxc storage volume delete nfs vol1
+  lxc storage volume delete nfs vol2
+  lxc storage volume delete nfs vol3
+
+  # Cleanup.
+  lxc storage delete nfs
+
+  if [ -n "${export_path}" ]; then
+    # The export is left empty.
+    [ "$(find "${export_path}" -mindepth 1 -maxdepth 1 | wc -l)" = "0" ]
+
+    exportfs -u "127.0.0.1:${export_path}"
+    rmdir "${export_path}"
+  fi
+}
@@ -18,6 +18,7 @@ test_storage_driver_zfs() {
   do_zfs_bucket_dataset_cleanup
   do_zfs_image_variants
   do_zfs_image_variant_blocksize
+  do_zfs_encryption
 }
 
 do_zfs_delegate() {
@@ -678,3 +679,73 @@ zfs_image_variant_tombstone_count() {
   out=$(zfs list -H -o name -t volume -d 1 "${parent}")
   echo "${out}" | awk -v stem="${basename}" '$0 ~ "/" stem "-[0-9a-f-]{36}$" { c++ } END { print c+0 }'
 }
+
+do_zfs_encryption() {
+  local pool zpool key
+
+  if ! command -v cryptsetup >/dev/null; then
+    echo "==> SKIP: Skipping ZFS encryption tests as cryptsetup is not installed"
+    return
+  fi
+
+  pool="lxdtest-$(basename "${LXD_DIR}")-encrypted"
+
+  ensure_import_testimage
+
+  lxc storage create "${pool}" zfs volume.size=64MiB volume.zfs.block_mode=true volume.security.encrypted=true
+  zpool="$(lxc storage get "${pool}" zfs.pool_name)"
+
+  echo "==> Encrypted instance and custom volumes"
+  lxc launch testimage c1 -s "${pool}"
+  lxc storage volume create "${pool}" vol1
+  [ "$(lxc storage volume get "${pool}" vol1 security.encrypted)" = "true" ]
+  key="$(lxc storage volume get "${pool}" vol1 volatile.encryption.key)"
+  [ -n "${key}" ]
+  [ "$(lxc storage volume get "${pool}" container/c1 volatile.encryption.key)" != "${key}" ]
+
+  lxc storage volume attach "${pool}" vol1 c1 /mnt
+  lxc exec c1 -- sh -c "echo foo > /mnt/bar"
+
+  # The zvols only contain LUKS encrypted data.
+  [ "$(blkid -s TYPE -o value -p "/dev/zvol/${zpool}/containers/c1")" = "crypto_LUKS" ]
+  [ "$(blkid -s TYPE -o value -p "/dev/zvol/${zpool}/custom/default_vol1")" = "crypto_LUKS" ]
+
+  echo "==> Encryption requires a zvol"
+  ! lxc storage volume create "${pool}" vol2 zfs.block_mode=false || false
+
+  echo "==> Volumes are unlocked again after a restart"
+  lxc restart c1 --force
+  [ "$(lxc exec c1 -- cat /mnt/bar)" = "foo" ]
+
+  echo "==> Snapshots and copies"
+  lxc storage volume snapshot "${pool}" vol1 snap0
+  [ "$(lxc storage volume get "${pool}" vol1/snap0 volatile.encryption.key)" = "${key}" ]
+  lxc storage volume copy "${pool}/vol1/snap0" "${pool}/vol2"
+  lxc storage volume attach "${pool}" vol2 c1 /mnt2
+  [ "$(lxc exec c1 -- cat /mnt2/bar)" = "foo" ]
+  lxc storage volume detach "${pool}" vol2 c1
+
+  echo "==> Growing an encrypted volume"
+  lxc storage volume set "${pool}" vol1 size=128MiB
+  [ "$(lxc exec c1 -- df -m /mnt | awk 'NR==2 {print $2}')" -gt 100 ]
+  [ "$(lxc exec c1 -- cat /mnt/bar)" = "foo" ]
+
+  echo "==> Backup round trip"
+  lxc storage volume export "${pool}" vol1 "${LXD_DIR}/vol1.tar.gz"
+  lxc storage volume import "${pool}" "${LXD_DIR}/vol1.tar.gz" vol3
+  lxc storage volume attach "${pool}" vol3 c1 /mnt3
+  [ "$(lxc exec c1 -- cat /mnt3/bar)" = "foo" ]
+  lxc storage volume detach "${pool}" vol3 c1
+  rm "${LXD_DIR}/vol1.tar.gz"
+
+  echo "==> Cleanup"
+  lxc delete -f c1
+  lxc storage volume delete "${pool}" vol1/snap0
+  lxc storage volume delete "${pool}" vol1
+  lxc storage volume delete "${pool}" vol2
+  lxc storage volume delete "${pool}" vol3
+  lxc storage delete "${pool}"
+
+  # All unlocked devices were locked again.
+  ! ls /dev/mapper/lxd-luks-* 2>/dev/null || false
+}
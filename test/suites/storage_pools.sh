@@ -79,3 +79,42 @@ test_storage_pools() {
   ! lxc storage create "${poolName}" powerflex powerflex.gateway=https://127.0.0.1:1234 powerflex.user.password=secret powerflex.pool=fakepool powerflex.mode=nvme/tcp || false
   ! lxc storage show "${poolName}" || false
 }
+
+test_storage_pool_health() {
+  local lxd_backend
+  lxd_backend=$(storage_backend "${LXD_DIR}")
+
+  local poolName
+  poolName="lxdtest-$(basename "${LXD_DIR}")-health"
+
+  if [ "${lxd_backend}" = "dir" ]; then
+    # Drivers without health checks report them as not implemented and reject the scrub schedule.
+    lxc storage create "${poolName}" dir
+    [ "$(lxc query "/1.0/storage-pools/${poolName}/health" 2>&1 || true)" = "Error: Storage pool driver \"dir\" does not support health checks" ]
+    ! lxc storage set "${poolName}" scrub.schedule=@daily || false
+    lxc storage delete "${poolName}"
+    return
+  fi
+
+  if [ "${lxd_backend}" != "zfs" ] && [ "${lxd_backend}" != "btrfs" ]; then
+    export TEST_UNMET_REQUIREMENT="zfs or btrfs specific test, not for ${lxd_backend}"
+    return
+  fi
+
+  lxc storage create "${poolName}" "${lxd_backend}" size=1GiB
+
+  # A freshly created pool is healthy.
+  lxc storage show "${poolName}" --health
+  [ "$(lxc query "/1.0/storage-pools/${poolName}/health" | jq --exit-status --raw-output '.state')" = "healthy" ]
+  [ "$(lxc query "/1.0/storage-pools/${poolName}/health" | jq --exit-status '.devices | length')" -gt 0 ]
+  ! lxc storage show "${poolName}" --health --resources || false
+
+  # The scrub schedule only accepts cron expressions.
+  lxc storage set "${poolName}" scrub.schedule="0 3 * * 0"
+  lxc storage set "${poolName}" scrub.schedule=@weekly
+  [ "$(lxc storage get "${poolName}" scrub.schedule)" = "@weekly" ]
+  ! lxc storage set "${poolName}" scrub.schedule=weekly || false
+  lxc storage unset "${poolName}" scrub.schedule
+
+  lxc storage delete "${poolName}"
+}